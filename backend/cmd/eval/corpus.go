@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dishflow/backend/internal/service/ai"
+)
+
+// Case is a single labeled extraction input
+type Case struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`               // "webpage", "image", "video" or "transcript"
+	URL      string   `json:"url,omitempty"`      // webpage URL or remote video URL
+	Files    []string `json:"files,omitempty"`    // image/video/transcript files relative to the case directory
+	Language string   `json:"language,omitempty"` // passed to ExtractRecipe for video and transcript cases
+	Metadata string   `json:"metadata,omitempty"` // caption/description context for video and transcript cases
+
+	Dir      string               `json:"-"`
+	Expected *ai.ExtractionResult `json:"-"`
+}
+
+// loadCases reads every case directory under root, sorted by directory name
+func loadCases(root string) ([]*Case, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []*Case
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		c, err := loadCase(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		cases = append(cases, c)
+	}
+
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Dir < cases[j].Dir })
+	return cases, nil
+}
+
+func loadCase(dir string) (*Case, error) {
+	var c Case
+	if err := readJSON(filepath.Join(dir, "case.json"), &c); err != nil {
+		return nil, err
+	}
+	if c.Name == "" {
+		c.Name = filepath.Base(dir)
+	}
+	c.Dir = dir
+
+	var expected ai.ExtractionResult
+	if err := readJSON(filepath.Join(dir, "expected.json"), &expected); err != nil {
+		return nil, err
+	}
+	c.Expected = &expected
+
+	return &c, nil
+}
+
+// loadSnapshot returns the saved copy of a webpage case's page, or nil if the
+// case has none and the live site is fetched instead
+func (c *Case) loadSnapshot() ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(c.Dir, "snapshot.html"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// loadRecorded returns the recorded model response for offline scoring
+func (c *Case) loadRecorded() (*ai.ExtractionResult, error) {
+	var result ai.ExtractionResult
+	if err := readJSON(filepath.Join(c.Dir, "recorded.json"), &result); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no recorded response (run live with -record first)")
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// saveRecorded stores a live response so later runs can be scored offline
+func (c *Case) saveRecorded(result *ai.ExtractionResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.Dir, "recorded.json"), append(data, '\n'), 0644)
+}
+
+func filterCases(cases []*Case, substr string) []*Case {
+	substr = strings.ToLower(substr)
+	var out []*Case
+	for _, c := range cases {
+		if strings.Contains(strings.ToLower(c.Name), substr) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+// Command eval scores the recipe extraction prompts against a labeled golden set.
+//
+// Each case lives in its own directory under the corpus root:
+//
+//	case.json      – input description (type, url, files, language, metadata)
+//	expected.json  – hand-labeled ExtractionResult
+//	snapshot.html  – saved copy of the page for webpage cases, served instead of the live site
+//	recorded.json  – last model output for the case (written with -record)
+//	cassette.json  – raw HTTP traffic for the case (written with -cassette record)
+//
+// Live runs call Gemini (ExtractFromWebpage / ExtractFromImages / ExtractRecipe,
+// followed by RefineRecipe unless -refine=false). Transcript cases send a video URL
+// with the transcript file as its spoken context, the way captions reach the
+// video prompt. Offline runs score recorded.json instead, so prompt-independent
+// scoring changes can be checked without an API key. With -cassette replay the
+// full client pipeline (prompting, webpage parsing, JSON parsing) runs against
+// each case's cassette.json without network access; a request that no longer
+// matches the recording (an edited prompt, say) fails until the case is re-recorded.
+//
+// Usage:
+//
+//	GEMINI_API_KEY=... go run ./cmd/eval -record -cassette record
+//	go run ./cmd/eval -offline -baseline eval_output/baseline.json
+//	go run ./cmd/eval -cassette replay
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/cassette"
+	"github.com/dishflow/backend/internal/service/ai"
+)
+
+func main() {
+	corpusDir := flag.String("corpus", "cmd/eval/testdata/golden", "directory containing golden cases")
+	outDir := flag.String("out", "eval_output", "directory for report.json and report.md")
+	baselinePath := flag.String("baseline", "", "previous report.json to compare against")
+	offline := flag.Bool("offline", false, "score recorded.json responses instead of calling Gemini")
+	record := flag.Bool("record", false, "write live responses to recorded.json in each case directory")
+	refine := flag.Bool("refine", true, "run RefineRecipe after extraction (live mode only)")
+	only := flag.String("case", "", "only run cases whose name contains this substring")
+	cassetteMode := flag.String("cassette", "", "record or replay per-case HTTP cassettes (cassette.json)")
+	maxRegression := flag.Float64("max-regression", 0, "exit non-zero if the overall score drops more than this vs baseline (0 disables)")
+	flag.Parse()
+
+	if *offline && *record {
+		log.Fatal("❌ -offline and -record are mutually exclusive")
+	}
+	if *cassetteMode != "" && *cassetteMode != string(cassette.ModeRecord) && *cassetteMode != string(cassette.ModeReplay) {
+		log.Fatalf("❌ -cassette must be %q or %q", cassette.ModeRecord, cassette.ModeReplay)
+	}
+	if *offline && *cassetteMode != "" {
+		log.Fatal("❌ -offline and -cassette are mutually exclusive")
+	}
+
+	cases, err := loadCases(*corpusDir)
+	if err != nil {
+		log.Fatalf("❌ Failed to load corpus: %v", err)
+	}
+	if *only != "" {
+		cases = filterCases(cases, *only)
+	}
+	if len(cases) == 0 {
+		log.Fatalf("❌ No cases found in %s", *corpusDir)
+	}
+
+	ctx := context.Background()
+
+	// Clients are created per case so each one gets its own snapshot and cassette
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	switch {
+	case *offline:
+	case *cassetteMode == string(cassette.ModeReplay):
+		apiKey = "replay"
+	default:
+		if apiKey == "" || apiKey == "mock" {
+			log.Fatal("❌ GEMINI_API_KEY is required for live runs (use -offline or -cassette replay)")
+		}
+	}
+
+	mode := "live"
+	if *offline {
+		mode = "offline"
+	} else if *cassetteMode == string(cassette.ModeReplay) {
+		mode = "replay"
+	}
+
+	fmt.Printf("🧪 Extraction eval (%s) — %d cases from %s\n", mode, len(cases), *corpusDir)
+	fmt.Println(strings.Repeat("=", 60))
+
+	report := &Report{
+		GeneratedAt: time.Now().UTC(),
+		Mode:        mode,
+		Refine:      *refine && !*offline,
+		Corpus:      *corpusDir,
+	}
+
+	for i, c := range cases {
+		fmt.Printf("[%d/%d] %s (%s)\n", i+1, len(cases), c.Name, c.Type)
+
+		start := time.Now()
+		var actual *ai.ExtractionResult
+		if *offline {
+			actual, err = c.loadRecorded()
+		} else {
+			actual, err = runCaseWithTransport(ctx, apiKey, cassette.Mode(*cassetteMode), c, *refine)
+		}
+		if err == nil && *record {
+			if werr := c.saveRecorded(actual); werr != nil {
+				log.Printf("Warning: could not record response for %s: %v", c.Name, werr)
+			}
+		}
+
+		result := CaseResult{
+			Name:       c.Name,
+			Type:       c.Type,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+			fmt.Printf("   ❌ %s\n", result.Error)
+		} else {
+			result.Metrics = Score(c.Expected, actual)
+			result.Misses, result.Extras = ingredientDiff(c.Expected, actual)
+			fmt.Printf("   ✅ overall %.3f | ingr P %.2f R %.2f | qty %.2f unit %.2f | steps %.2f | title %.2f\n",
+				result.Metrics.Overall, result.Metrics.IngredientPrecision, result.Metrics.IngredientRecall,
+				result.Metrics.QuantityAccuracy, result.Metrics.UnitAccuracy,
+				result.Metrics.StepCountScore, result.Metrics.TitleSimilarity)
+		}
+		report.Cases = append(report.Cases, result)
+	}
+
+	report.Summary = summarize(report.Cases)
+
+	if *baselinePath != "" {
+		baseline, err := loadReport(*baselinePath)
+		if err != nil {
+			log.Fatalf("❌ Failed to load baseline: %v", err)
+		}
+		report.Baseline = compare(*baselinePath, baseline, report)
+	}
+
+	if err := writeReport(*outDir, report); err != nil {
+		log.Fatalf("❌ Failed to write report: %v", err)
+	}
+
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("📊 Overall %.3f over %d/%d scored cases\n", report.Summary.Overall, report.Summary.Scored, len(report.Cases))
+	if report.Baseline != nil {
+		fmt.Printf("📈 Delta vs baseline: %+.3f\n", report.Baseline.Summary.Overall)
+	}
+	fmt.Printf("💾 Report written to %s\n", filepath.Join(*outDir, "report.{json,md}"))
+
+	if *maxRegression > 0 && report.Baseline != nil && report.Baseline.Summary.Overall < -*maxRegression {
+		fmt.Printf("❌ Overall score regressed by %.3f (allowed %.3f)\n", -report.Baseline.Summary.Overall, *maxRegression)
+		os.Exit(1)
+	}
+}
+
+// runCaseWithTransport runs a case through a client whose traffic serves the
+// case's snapshot.html in place of the live page and, when mode is set, is
+// recorded to or replayed from the case's cassette.json
+func runCaseWithTransport(ctx context.Context, apiKey string, mode cassette.Mode, c *Case, refine bool) (*ai.ExtractionResult, error) {
+	var rt http.RoundTripper = http.DefaultTransport
+	snapshot, err := c.loadSnapshot()
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+	if snapshot != nil {
+		rt = &snapshotTransport{url: c.URL, html: snapshot, next: rt}
+	}
+
+	var tape *cassette.Transport
+	if mode != "" {
+		if tape, err = cassette.New(filepath.Join(c.Dir, "cassette.json"), mode, rt); err != nil {
+			return nil, err
+		}
+		rt = tape
+	}
+
+	client, err := ai.NewGeminiClientWithTransport(ctx, apiKey, rt)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := runCase(ctx, client, c, refine)
+	if err != nil {
+		return nil, err
+	}
+	if tape != nil {
+		return result, tape.Save()
+	}
+	return result, nil
+}
+
+// snapshotTransport answers GETs for a webpage case's URL with its saved page,
+// so scores don't move when the live site changes
+type snapshotTransport struct {
+	url  string
+	html []byte
+	next http.RoundTripper
+}
+
+func (t *snapshotTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.URL.String() != t.url {
+		return t.next.RoundTrip(req)
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:          io.NopCloser(bytes.NewReader(t.html)),
+		ContentLength: int64(len(t.html)),
+		Request:       req,
+	}, nil
+}
+
+// noProgress discards progress updates; ExtractRecipe requires a callback
+func noProgress(model.JobStatus, int, string) {}
+
+// runCase sends a single case through the extractor
+func runCase(ctx context.Context, extractor ai.RecipeExtractor, c *Case, refine bool) (*ai.ExtractionResult, error) {
+	var result *ai.ExtractionResult
+	var err error
+
+	switch c.Type {
+	case "webpage":
+		result, err = extractor.ExtractFromWebpage(ctx, c.URL, nil)
+	case "image":
+		images := make([][]byte, 0, len(c.Files))
+		mimeTypes := make([]string, 0, len(c.Files))
+		for _, f := range c.Files {
+			data, rerr := os.ReadFile(filepath.Join(c.Dir, f))
+			if rerr != nil {
+				return nil, fmt.Errorf("read %s: %w", f, rerr)
+			}
+			images = append(images, data)
+			mimeTypes = append(mimeTypes, mimeTypeFor(f))
+		}
+		result, err = extractor.ExtractFromImages(ctx, images, mimeTypes)
+	case "video":
+		// ExtractRecipe accepts https:// URLs natively (YouTube) or a local file path
+		videoURL := c.URL
+		if videoURL == "" && len(c.Files) > 0 {
+			videoURL = filepath.Join(c.Dir, c.Files[0])
+		}
+		result, err = extractor.ExtractRecipe(ctx, ai.ExtractionRequest{
+			VideoURL:    videoURL,
+			Language:    c.Language,
+			DetailLevel: "detailed",
+			Metadata:    c.Metadata,
+		}, noProgress)
+	case "transcript":
+		if c.URL == "" || len(c.Files) == 0 {
+			return nil, fmt.Errorf("transcript cases need a video url and a transcript file")
+		}
+		transcript, rerr := os.ReadFile(filepath.Join(c.Dir, c.Files[0]))
+		if rerr != nil {
+			return nil, fmt.Errorf("read %s: %w", c.Files[0], rerr)
+		}
+		metadata := "Transcript:\n" + strings.TrimSpace(string(transcript))
+		if c.Metadata != "" {
+			metadata = c.Metadata + "\n\n" + metadata
+		}
+		result, err = extractor.ExtractRecipe(ctx, ai.ExtractionRequest{
+			VideoURL:    c.URL,
+			Language:    c.Language,
+			DetailLevel: "detailed",
+			Metadata:    metadata,
+		}, noProgress)
+	default:
+		return nil, fmt.Errorf("unknown case type: %s", c.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if refine {
+		refined, rerr := extractor.RefineRecipe(ctx, result)
+		if rerr != nil {
+			// Mirror the production pipeline: refinement failures fall back to the raw result
+			log.Printf("Warning: refinement failed for %s, scoring raw result: %v", c.Name, rerr)
+		} else {
+			result = refined
+		}
+	}
+
+	return result, nil
+}
+
+func mimeTypeFor(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png":
+		return "image/png"
+	case ".webp":
+		return "image/webp"
+	case ".heic":
+		return "image/heic"
+	default:
+		return "image/jpeg"
+	}
+}
+
+func loadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dishflow/backend/internal/pkg/cassette"
+)
+
+// Every golden case must replay from its committed cassette, so a prompt edit
+// that changes a request fails here until the case is re-recorded
+func TestGoldenCasesReplay(t *testing.T) {
+	cases, err := loadCases("testdata/golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := map[string]bool{}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if _, err := os.Stat(filepath.Join(c.Dir, "cassette.json")); err != nil {
+				t.Fatalf("missing cassette: %v", err)
+			}
+			result, err := runCaseWithTransport(context.Background(), "replay", cassette.ModeReplay, c, true)
+			if err != nil {
+				t.Fatalf("replay failed: %v", err)
+			}
+			if m := Score(c.Expected, result); m.Overall <= 0 {
+				t.Errorf("expected a positive score, got %+v", m)
+			}
+		})
+		types[c.Type] = true
+	}
+
+	for _, typ := range []string{"webpage", "image", "video", "transcript"} {
+		if !types[typ] {
+			t.Errorf("no golden case of type %q", typ)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Report is the full output of an eval run (also the -baseline input format)
+type Report struct {
+	GeneratedAt time.Time    `json:"generatedAt"`
+	Mode        string       `json:"mode"` // "live" or "offline"
+	Refine      bool         `json:"refine"`
+	Corpus      string       `json:"corpus"`
+	Summary     Summary      `json:"summary"`
+	Cases       []CaseResult `json:"cases"`
+	Baseline    *Comparison  `json:"baseline,omitempty"`
+}
+
+// CaseResult holds the scores for a single case
+type CaseResult struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Error      string   `json:"error,omitempty"`
+	DurationMs int64    `json:"durationMs"`
+	Metrics    Metrics  `json:"metrics"`
+	Misses     []string `json:"missedIngredients,omitempty"`
+	Extras     []string `json:"extraIngredients,omitempty"`
+}
+
+// Summary averages metrics over successfully scored cases
+type Summary struct {
+	Metrics
+	Scored int `json:"scored"`
+	Failed int `json:"failed"`
+}
+
+// Comparison holds metric deltas (current - baseline)
+type Comparison struct {
+	Path        string             `json:"path"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+	Summary     Metrics            `json:"summary"`
+	Cases       map[string]float64 `json:"cases"` // overall delta per case present in both runs
+}
+
+func summarize(cases []CaseResult) Summary {
+	var s Summary
+	for _, c := range cases {
+		if c.Error != "" {
+			s.Failed++
+			continue
+		}
+		s.Scored++
+		s.Metrics = addMetrics(s.Metrics, c.Metrics, 1)
+	}
+	if s.Scored > 0 {
+		s.Metrics = scaleMetrics(s.Metrics, 1/float64(s.Scored))
+	}
+	return s
+}
+
+func compare(path string, baseline, current *Report) *Comparison {
+	cmp := &Comparison{
+		Path:        path,
+		GeneratedAt: baseline.GeneratedAt,
+		Summary:     addMetrics(current.Summary.Metrics, baseline.Summary.Metrics, -1),
+		Cases:       make(map[string]float64),
+	}
+
+	prev := make(map[string]CaseResult, len(baseline.Cases))
+	for _, c := range baseline.Cases {
+		prev[c.Name] = c
+	}
+	for _, c := range current.Cases {
+		b, ok := prev[c.Name]
+		if !ok || b.Error != "" || c.Error != "" {
+			continue
+		}
+		cmp.Cases[c.Name] = c.Metrics.Overall - b.Metrics.Overall
+	}
+	return cmp
+}
+
+func addMetrics(a, b Metrics, sign float64) Metrics {
+	return Metrics{
+		IngredientPrecision: a.IngredientPrecision + sign*b.IngredientPrecision,
+		IngredientRecall:    a.IngredientRecall + sign*b.IngredientRecall,
+		IngredientF1:        a.IngredientF1 + sign*b.IngredientF1,
+		QuantityAccuracy:    a.QuantityAccuracy + sign*b.QuantityAccuracy,
+		UnitAccuracy:        a.UnitAccuracy + sign*b.UnitAccuracy,
+		StepCountScore:      a.StepCountScore + sign*b.StepCountScore,
+		TitleSimilarity:     a.TitleSimilarity + sign*b.TitleSimilarity,
+		Overall:             a.Overall + sign*b.Overall,
+	}
+}
+
+func scaleMetrics(m Metrics, f float64) Metrics {
+	return addMetrics(Metrics{}, m, f)
+}
+
+// writeReport writes report.json and report.md into dir
+func writeReport(dir string, r *Report) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "report.json"), append(data, '\n'), 0644); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, "report.md"), []byte(renderMarkdown(r)), 0644)
+}
+
+func renderMarkdown(r *Report) string {
+	var md strings.Builder
+
+	md.WriteString("# Extraction Eval Report\n\n")
+	md.WriteString(fmt.Sprintf("- **Generated:** %s\n", r.GeneratedAt.Format("2006-01-02 15:04:05 MST")))
+	md.WriteString(fmt.Sprintf("- **Mode:** %s (refine: %t)\n", r.Mode, r.Refine))
+	md.WriteString(fmt.Sprintf("- **Corpus:** %s\n", r.Corpus))
+	md.WriteString(fmt.Sprintf("- **Cases:** %d scored, %d failed\n\n", r.Summary.Scored, r.Summary.Failed))
+
+	md.WriteString("## Summary\n\n")
+	if r.Baseline != nil {
+		md.WriteString("| Metric | Score | Δ vs baseline |\n")
+		md.WriteString("|--------|-------|---------------|\n")
+	} else {
+		md.WriteString("| Metric | Score |\n")
+		md.WriteString("|--------|-------|\n")
+	}
+	for _, row := range metricRows(r.Summary.Metrics) {
+		if r.Baseline != nil {
+			delta := metricRows(r.Baseline.Summary)
+			md.WriteString(fmt.Sprintf("| %s | %.3f | %+.3f |\n", row.name, row.value, lookupRow(delta, row.name)))
+		} else {
+			md.WriteString(fmt.Sprintf("| %s | %.3f |\n", row.name, row.value))
+		}
+	}
+	md.WriteString("\n")
+
+	md.WriteString("## Cases\n\n")
+	md.WriteString("| Case | Type | Overall | Ingr P | Ingr R | Qty | Unit | Steps | Title |")
+	if r.Baseline != nil {
+		md.WriteString(" Δ |")
+	}
+	md.WriteString("\n|------|------|---------|--------|--------|-----|------|-------|-------|")
+	if r.Baseline != nil {
+		md.WriteString("---|")
+	}
+	md.WriteString("\n")
+
+	for _, c := range r.Cases {
+		if c.Error != "" {
+			md.WriteString(fmt.Sprintf("| %s | %s | ❌ %s |||||||", c.Name, c.Type, strings.ReplaceAll(c.Error, "|", "/")))
+			if r.Baseline != nil {
+				md.WriteString(" |")
+			}
+			md.WriteString("\n")
+			continue
+		}
+		m := c.Metrics
+		md.WriteString(fmt.Sprintf("| %s | %s | %.3f | %.2f | %.2f | %.2f | %.2f | %.2f | %.2f |",
+			c.Name, c.Type, m.Overall, m.IngredientPrecision, m.IngredientRecall,
+			m.QuantityAccuracy, m.UnitAccuracy, m.StepCountScore, m.TitleSimilarity))
+		if r.Baseline != nil {
+			if d, ok := r.Baseline.Cases[c.Name]; ok {
+				md.WriteString(fmt.Sprintf(" %+.3f |", d))
+			} else {
+				md.WriteString(" n/a |")
+			}
+		}
+		md.WriteString("\n")
+	}
+	md.WriteString("\n")
+
+	// Ingredient-level misses are the most actionable signal when tuning prompts
+	var details strings.Builder
+	for _, c := range r.Cases {
+		if len(c.Misses) == 0 && len(c.Extras) == 0 {
+			continue
+		}
+		details.WriteString(fmt.Sprintf("### %s\n\n", c.Name))
+		if len(c.Misses) > 0 {
+			details.WriteString("- **Missed:** " + strings.Join(c.Misses, ", ") + "\n")
+		}
+		if len(c.Extras) > 0 {
+			details.WriteString("- **Unlabeled:** " + strings.Join(c.Extras, ", ") + "\n")
+		}
+		details.WriteString("\n")
+	}
+	if details.Len() > 0 {
+		md.WriteString("## Ingredient Differences\n\n")
+		md.WriteString(details.String())
+	}
+
+	return md.String()
+}
+
+type metricRow struct {
+	name  string
+	value float64
+}
+
+func metricRows(m Metrics) []metricRow {
+	return []metricRow{
+		{"Overall", m.Overall},
+		{"Ingredient precision", m.IngredientPrecision},
+		{"Ingredient recall", m.IngredientRecall},
+		{"Ingredient F1", m.IngredientF1},
+		{"Quantity accuracy", m.QuantityAccuracy},
+		{"Unit accuracy", m.UnitAccuracy},
+		{"Step count", m.StepCountScore},
+		{"Title similarity", m.TitleSimilarity},
+	}
+}
+
+func lookupRow(rows []metricRow, name string) float64 {
+	for _, r := range rows {
+		if r.name == name {
+			return r.value
+		}
+	}
+	return 0
+}
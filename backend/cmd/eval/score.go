@@ -0,0 +1,297 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dishflow/backend/internal/service/ai"
+)
+
+// Metrics holds the per-case (or averaged) extraction scores, all in [0,1]
+type Metrics struct {
+	IngredientPrecision float64 `json:"ingredientPrecision"`
+	IngredientRecall    float64 `json:"ingredientRecall"`
+	IngredientF1        float64 `json:"ingredientF1"`
+	QuantityAccuracy    float64 `json:"quantityAccuracy"` // over matched ingredients
+	UnitAccuracy        float64 `json:"unitAccuracy"`     // over matched ingredients
+	StepCountScore      float64 `json:"stepCountScore"`
+	TitleSimilarity     float64 `json:"titleSimilarity"`
+	Overall             float64 `json:"overall"`
+}
+
+// ingredientMatchThreshold is the minimum token overlap for two names to be
+// considered the same ingredient ("unsalted butter" vs "butter" = 0.5)
+const ingredientMatchThreshold = 0.5
+
+// Score compares an extraction against its golden label
+func Score(expected, actual *ai.ExtractionResult) Metrics {
+	var m Metrics
+
+	pairs := matchIngredientLists(expected.Ingredients, actual.Ingredients)
+
+	m.IngredientPrecision = ratio(len(pairs), len(actual.Ingredients))
+	m.IngredientRecall = ratio(len(pairs), len(expected.Ingredients))
+	if m.IngredientPrecision+m.IngredientRecall > 0 {
+		m.IngredientF1 = 2 * m.IngredientPrecision * m.IngredientRecall / (m.IngredientPrecision + m.IngredientRecall)
+	}
+
+	if len(pairs) > 0 {
+		qtyHits, unitHits := 0, 0
+		for _, p := range pairs {
+			exp, act := expected.Ingredients[p[0]], actual.Ingredients[p[1]]
+			if quantitiesEqual(exp.Quantity, act.Quantity) {
+				qtyHits++
+			}
+			if normalizeUnit(exp.Unit) == normalizeUnit(act.Unit) {
+				unitHits++
+			}
+		}
+		m.QuantityAccuracy = float64(qtyHits) / float64(len(pairs))
+		m.UnitAccuracy = float64(unitHits) / float64(len(pairs))
+	}
+
+	m.StepCountScore = stepCountScore(len(expected.Steps), len(actual.Steps))
+	m.TitleSimilarity = stringSimilarity(normalizeText(expected.Title), normalizeText(actual.Title))
+
+	// Ingredients carry most of the value for users; title and steps are easy to fix by hand
+	m.Overall = 0.35*m.IngredientF1 +
+		0.15*m.QuantityAccuracy +
+		0.15*m.UnitAccuracy +
+		0.2*m.StepCountScore +
+		0.15*m.TitleSimilarity
+
+	return m
+}
+
+// ingredientDiff lists expected ingredients that were missed and extracted ones with no label
+func ingredientDiff(expected, actual *ai.ExtractionResult) (misses, extras []string) {
+	pairs := matchIngredientLists(expected.Ingredients, actual.Ingredients)
+	usedExp := make(map[int]bool, len(pairs))
+	usedAct := make(map[int]bool, len(pairs))
+	for _, p := range pairs {
+		usedExp[p[0]] = true
+		usedAct[p[1]] = true
+	}
+	for i, ing := range expected.Ingredients {
+		if !usedExp[i] {
+			misses = append(misses, ing.Name)
+		}
+	}
+	for i, ing := range actual.Ingredients {
+		if !usedAct[i] {
+			extras = append(extras, ing.Name)
+		}
+	}
+	return misses, extras
+}
+
+// matchIngredientLists greedily pairs expected and actual ingredients by name
+// similarity, best pairs first. Returns [expectedIndex, actualIndex] pairs.
+func matchIngredientLists(expected, actual []ai.ExtractedIngredient) [][2]int {
+	type candidate struct {
+		e, a  int
+		score float64
+	}
+
+	var candidates []candidate
+	for i, exp := range expected {
+		for j, act := range actual {
+			s := nameSimilarity(exp.Name, act.Name)
+			if s >= ingredientMatchThreshold {
+				candidates = append(candidates, candidate{i, j, s})
+			}
+		}
+	}
+
+	// Simple insertion sort keeps ties in input order (lists are small)
+	for i := 1; i < len(candidates); i++ {
+		for j := i; j > 0 && candidates[j].score > candidates[j-1].score; j-- {
+			candidates[j], candidates[j-1] = candidates[j-1], candidates[j]
+		}
+	}
+
+	usedExp := make(map[int]bool)
+	usedAct := make(map[int]bool)
+	var pairs [][2]int
+	for _, c := range candidates {
+		if usedExp[c.e] || usedAct[c.a] {
+			continue
+		}
+		usedExp[c.e] = true
+		usedAct[c.a] = true
+		pairs = append(pairs, [2]int{c.e, c.a})
+	}
+	return pairs
+}
+
+// nameSimilarity is the Jaccard overlap of normalized name tokens
+func nameSimilarity(a, b string) float64 {
+	ta, tb := nameTokens(a), nameTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	inter := 0
+	for t := range ta {
+		if tb[t] {
+			inter++
+		}
+	}
+	union := len(ta) + len(tb) - inter
+	return float64(inter) / float64(union)
+}
+
+func nameTokens(s string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, f := range strings.Fields(normalizeText(s)) {
+		// Crude singularization is enough for label matching ("eggs" vs "egg")
+		if len(f) > 3 && strings.HasSuffix(f, "es") && !strings.HasSuffix(f, "oes") {
+			f = strings.TrimSuffix(f, "s")
+		} else if len(f) > 2 && strings.HasSuffix(f, "s") && !strings.HasSuffix(f, "ss") {
+			f = strings.TrimSuffix(f, "s")
+		}
+		tokens[f] = true
+	}
+	return tokens
+}
+
+// normalizeText lowercases and replaces punctuation with spaces
+func normalizeText(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+var unitAliases = map[string]string{
+	"t": "tsp", "tsp": "tsp", "tsps": "tsp", "teaspoon": "tsp", "teaspoons": "tsp", "c.à.c": "tsp", "cuillère à café": "tsp",
+	"tbsp": "tbsp", "tbsps": "tbsp", "tbs": "tbsp", "tablespoon": "tbsp", "tablespoons": "tbsp", "c.à.s": "tbsp", "cuillère à soupe": "tbsp",
+	"cup": "cup", "cups": "cup", "c": "cup", "tasse": "cup", "tasses": "cup", "taza": "cup", "tazas": "cup",
+	"g": "g", "gram": "g", "grams": "g", "gramme": "g", "grammes": "g", "gr": "g",
+	"kg": "kg", "kilogram": "kg", "kilograms": "kg",
+	"ml": "ml", "milliliter": "ml", "milliliters": "ml", "millilitre": "ml", "millilitres": "ml",
+	"l": "l", "liter": "l", "liters": "l", "litre": "l", "litres": "l",
+	"oz": "oz", "ounce": "oz", "ounces": "oz",
+	"lb": "lb", "lbs": "lb", "pound": "lb", "pounds": "lb",
+	"pinch": "pinch", "pinches": "pinch", "clove": "clove", "cloves": "clove",
+	"piece": "", "pieces": "", "pc": "", "pcs": "", "whole": "",
+}
+
+// normalizeUnit maps unit spellings to a canonical abbreviation; count-like units collapse to ""
+func normalizeUnit(u string) string {
+	u = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(u)), ".")
+	if canonical, ok := unitAliases[u]; ok {
+		return canonical
+	}
+	return u
+}
+
+// quantitiesEqual compares quantity strings numerically ("1/2" == "0.5"), falling
+// back to normalized text for values that don't parse ("to taste")
+func quantitiesEqual(a, b string) bool {
+	qa, okA := parseEvalQuantity(a)
+	qb, okB := parseEvalQuantity(b)
+	if okA && okB {
+		return math.Abs(qa-qb) <= 0.01*math.Max(math.Abs(qa), math.Abs(qb))+1e-9
+	}
+	return normalizeText(a) == normalizeText(b)
+}
+
+var unicodeFractions = map[rune]float64{
+	'½': 0.5, '⅓': 1.0 / 3, '⅔': 2.0 / 3, '¼': 0.25, '¾': 0.75, '⅛': 0.125,
+}
+
+// parseEvalQuantity parses "2", "1.5", "1/2", "1 1/2", "1½" and ranges ("1-2", lower bound)
+func parseEvalQuantity(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	if idx := strings.IndexAny(s, "-–"); idx > 0 {
+		s = strings.TrimSpace(s[:idx])
+	}
+
+	total := 0.0
+	parsed := false
+	for _, part := range strings.Fields(s) {
+		for r, v := range unicodeFractions {
+			if strings.ContainsRune(part, r) {
+				total += v
+				part = strings.ReplaceAll(part, string(r), "")
+				parsed = true
+			}
+		}
+		if part == "" {
+			continue
+		}
+		if num, den, ok := strings.Cut(part, "/"); ok {
+			n, err1 := strconv.ParseFloat(num, 64)
+			d, err2 := strconv.ParseFloat(den, 64)
+			if err1 != nil || err2 != nil || d == 0 {
+				return 0, false
+			}
+			total += n / d
+			parsed = true
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.ReplaceAll(part, ",", "."), 64)
+		if err != nil {
+			return 0, false
+		}
+		total += v
+		parsed = true
+	}
+	return total, parsed
+}
+
+func stepCountScore(expected, actual int) float64 {
+	if expected == 0 {
+		if actual == 0 {
+			return 1
+		}
+		return 0
+	}
+	diff := math.Abs(float64(expected - actual))
+	return math.Max(0, 1-diff/float64(expected))
+}
+
+// stringSimilarity is 1 - normalized Levenshtein distance
+func stringSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		if n == 0 {
+			return 1
+		}
+		return 0
+	}
+	return float64(n) / float64(d)
+}
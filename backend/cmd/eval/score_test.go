@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dishflow/backend/internal/service/ai"
+)
+
+func TestParseEvalQuantity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"2", 2, true},
+		{"1/2", 0.5, true},
+		{"1 1/2", 1.5, true},
+		{"1½", 1.5, true},
+		{"0,5", 0.5, true},
+		{"1-2", 1, true},
+		{"", 0, false},
+		{"to taste", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseEvalQuantity(tt.in)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("parseEvalQuantity(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	expected := &ai.ExtractionResult{
+		Title: "Garlic Butter Shrimp",
+		Ingredients: []ai.ExtractedIngredient{
+			{Name: "shrimp", Quantity: "1", Unit: "lb"},
+			{Name: "unsalted butter", Quantity: "2", Unit: "tbsp"},
+			{Name: "garlic cloves", Quantity: "4", Unit: ""},
+			{Name: "parsley", Quantity: "", Unit: ""},
+		},
+		Steps: []ai.ExtractedStep{{StepNumber: 1}, {StepNumber: 2}, {StepNumber: 3}, {StepNumber: 4}},
+	}
+
+	t.Run("perfect match", func(t *testing.T) {
+		m := Score(expected, expected)
+		if m.Overall < 0.999 {
+			t.Errorf("expected overall 1, got %v", m.Overall)
+		}
+	})
+
+	t.Run("partial match", func(t *testing.T) {
+		actual := &ai.ExtractionResult{
+			Title: "Garlic-Butter Shrimp",
+			Ingredients: []ai.ExtractedIngredient{
+				{Name: "Shrimp", Quantity: "16", Unit: "ounces"},
+				{Name: "Butter", Quantity: "2", Unit: "tablespoons"},
+				{Name: "Garlic clove", Quantity: "4"},
+				{Name: "Lemon", Quantity: "1"},
+			},
+			Steps: []ai.ExtractedStep{{StepNumber: 1}, {StepNumber: 2}},
+		}
+
+		m := Score(expected, actual)
+		if m.IngredientPrecision != 0.75 || m.IngredientRecall != 0.75 {
+			t.Errorf("precision/recall = %v/%v, want 0.75/0.75", m.IngredientPrecision, m.IngredientRecall)
+		}
+		// shrimp quantity and unit differ (16 oz vs 1 lb); butter and garlic agree
+		if math.Abs(m.QuantityAccuracy-2.0/3) > 1e-9 || math.Abs(m.UnitAccuracy-2.0/3) > 1e-9 {
+			t.Errorf("quantity/unit = %v/%v, want 2/3", m.QuantityAccuracy, m.UnitAccuracy)
+		}
+		if m.StepCountScore != 0.5 {
+			t.Errorf("step count score = %v, want 0.5", m.StepCountScore)
+		}
+		if m.TitleSimilarity != 1 {
+			t.Errorf("title similarity = %v, want 1 after normalization", m.TitleSimilarity)
+		}
+
+		misses, extras := ingredientDiff(expected, actual)
+		if len(misses) != 1 || misses[0] != "parsley" {
+			t.Errorf("misses = %v, want [parsley]", misses)
+		}
+		if len(extras) != 1 || extras[0] != "Lemon" {
+			t.Errorf("extras = %v, want [Lemon]", extras)
+		}
+	})
+}
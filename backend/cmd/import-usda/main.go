@@ -0,0 +1,315 @@
+// Command import-usda loads USDA FoodData Central CSV downloads into the
+// foods and food_portions tables that recipe nutrition is computed from.
+//
+// Download the Foundation Foods and SR Legacy CSV archives from
+// https://fdc.nal.usda.gov/download-datasets and extract each into a
+// directory; it should contain food.csv, food_nutrient.csv, food_portion.csv
+// and measure_unit.csv. Re-running an import replaces the foods it
+// contains, so a newer release can be loaded over an older one.
+//
+// Usage:
+//
+//	DATABASE_URL=postgres://... go run ./cmd/import-usda -dir ./FoodData_Central_sr_legacy_food_csv_2018-04
+//	DATABASE_URL=postgres://... go run ./cmd/import-usda -dir ./FoodData_Central_foundation_food_csv_2024-10
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/units"
+	"github.com/dishflow/backend/internal/repository/postgres"
+	"github.com/dishflow/backend/internal/service/nutrition"
+)
+
+// FoodData Central nutrient IDs for the tracked nutrients
+const (
+	nutrientEnergy        = 1008 // kcal
+	nutrientEnergyAtwater = 2047 // kcal, general Atwater factors (Foundation Foods)
+	nutrientEnergySpecial = 2048 // kcal, specific Atwater factors (Foundation Foods)
+	nutrientProtein       = 1003
+	nutrientFat           = 1004
+	nutrientCarbs         = 1005
+	nutrientFiber         = 1079
+	nutrientSugars        = 2000
+	nutrientSugarsNLEA    = 1063
+	nutrientSodium        = 1093
+	nutrientSaturatedFat  = 1258
+	nutrientCholesterol   = 1253
+	nutrientCalcium       = 1087
+	nutrientIron          = 1089
+	nutrientPotassium     = 1092
+	nutrientVitaminC      = 1162
+)
+
+func main() {
+	dir := flag.String("dir", "", "directory with the extracted FoodData Central CSV files")
+	types := flag.String("types", "foundation_food,sr_legacy_food", "comma-separated data types to import (branded_food is skipped by default)")
+	flag.Parse()
+
+	if *dir == "" {
+		log.Fatal("-dir is required")
+	}
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	wanted := make(map[string]bool)
+	for _, t := range strings.Split(*types, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			wanted[t] = true
+		}
+	}
+
+	start := time.Now()
+	foods, err := loadFoods(*dir, wanted)
+	if err != nil {
+		log.Fatalf("Failed to read foods: %v", err)
+	}
+	if len(foods) == 0 {
+		log.Fatalf("No foods of types %q in %s", *types, *dir)
+	}
+	log.Printf("Read %d foods in %s", len(foods), time.Since(start).Round(time.Millisecond))
+
+	if err := loadNutrients(*dir, foods); err != nil {
+		log.Fatalf("Failed to read nutrients: %v", err)
+	}
+	portions, err := loadPortions(*dir, foods)
+	if err != nil {
+		log.Fatalf("Failed to read portions: %v", err)
+	}
+	log.Printf("Read %d portions", portions)
+
+	db, err := sql.Open("pgx", dbURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	list := make([]*model.Food, 0, len(foods))
+	for _, f := range foods {
+		list = append(list, f)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	if err := postgres.NewFoodRepository(db).Import(ctx, list, nutrition.FoodMatchName); err != nil {
+		log.Fatalf("Failed to import foods: %v", err)
+	}
+	log.Printf("Imported %d foods in %s", len(list), time.Since(start).Round(time.Second))
+}
+
+// csvFile streams the rows of one CSV file, passing each to fn with a
+// lookup of the row's columns by header name
+func csvFile(dir, name string, fn func(col func(string) string) error) error {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.ReuseRecord = true
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.TrimPrefix(h, "\ufeff")] = i
+	}
+
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		col := func(name string) string {
+			if i, ok := index[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		if err := fn(col); err != nil {
+			return fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+	}
+}
+
+func loadFoods(dir string, wanted map[string]bool) (map[int]*model.Food, error) {
+	foods := make(map[int]*model.Food)
+	err := csvFile(dir, "food.csv", func(col func(string) string) error {
+		if !wanted[col("data_type")] {
+			return nil
+		}
+		id, err := strconv.Atoi(col("fdc_id"))
+		if err != nil {
+			return err
+		}
+		foods[id] = &model.Food{FDCID: id, Description: col("description"), DataType: col("data_type")}
+		return nil
+	})
+	return foods, err
+}
+
+func loadNutrients(dir string, foods map[int]*model.Food) error {
+	// Foundation Foods often only carry Atwater energy, SR Legacy only 1008
+	atwater := make(map[int]float64)
+	hasSugars := make(map[int]bool)
+
+	err := csvFile(dir, "food_nutrient.csv", func(col func(string) string) error {
+		id, err := strconv.Atoi(col("fdc_id"))
+		if err != nil {
+			return err
+		}
+		f, ok := foods[id]
+		if !ok {
+			return nil
+		}
+		nutrientID, err := strconv.Atoi(col("nutrient_id"))
+		if err != nil {
+			return err
+		}
+		amount, err := strconv.ParseFloat(col("amount"), 64)
+		if err != nil {
+			return nil // blank amounts are "not measured"
+		}
+
+		n := &f.Per100g
+		switch nutrientID {
+		case nutrientEnergy:
+			n.Calories = amount
+		case nutrientEnergyAtwater, nutrientEnergySpecial:
+			atwater[id] = amount
+		case nutrientProtein:
+			n.Protein = amount
+		case nutrientFat:
+			n.Fat = amount
+		case nutrientCarbs:
+			n.Carbs = amount
+		case nutrientFiber:
+			n.Fiber = amount
+		case nutrientSugars:
+			n.Sugar, hasSugars[id] = amount, true
+		case nutrientSugarsNLEA:
+			if !hasSugars[id] {
+				n.Sugar = amount
+			}
+		case nutrientSodium:
+			n.Sodium = amount
+		case nutrientSaturatedFat:
+			n.SaturatedFat = amount
+		case nutrientCholesterol:
+			n.Cholesterol = amount
+		case nutrientCalcium:
+			n.Calcium = amount
+		case nutrientIron:
+			n.Iron = amount
+		case nutrientPotassium:
+			n.Potassium = amount
+		case nutrientVitaminC:
+			n.VitaminC = amount
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for id, kcal := range atwater {
+		if foods[id].Per100g.Calories == 0 {
+			foods[id].Per100g.Calories = kcal
+		}
+	}
+	return nil
+}
+
+func loadPortions(dir string, foods map[int]*model.Food) (int, error) {
+	measureUnits := make(map[string]string)
+	err := csvFile(dir, "measure_unit.csv", func(col func(string) string) error {
+		measureUnits[col("id")] = col("name")
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = csvFile(dir, "food_portion.csv", func(col func(string) string) error {
+		id, err := strconv.Atoi(col("fdc_id"))
+		if err != nil {
+			return err
+		}
+		f, ok := foods[id]
+		if !ok {
+			return nil
+		}
+		grams, err := strconv.ParseFloat(col("gram_weight"), 64)
+		if err != nil || grams <= 0 {
+			return nil
+		}
+		amount, err := strconv.ParseFloat(col("amount"), 64)
+		if err != nil || amount <= 0 {
+			amount = 1
+		}
+
+		text := col("modifier")
+		if text == "" {
+			text = col("portion_description")
+		}
+		f.Portions = append(f.Portions, portion(amount, measureUnits[col("measure_unit_id")], text, grams))
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// portion normalizes a FoodData Central portion. Foundation Foods give the
+// measure unit separately; SR Legacy puts it at the start of the modifier
+// ("cup, chopped", "tbsp", "large"). Volume and weight measures are stored
+// by canonical unit name, anything else as a counted portion.
+func portion(amount float64, unitName, modifier string, grams float64) model.FoodPortion {
+	p := model.FoodPortion{Amount: amount, Modifier: strings.TrimSpace(modifier), GramWeight: grams}
+
+	if u, ok := units.Lookup(unitName); ok && (u.Dimension == units.Volume || u.Dimension == units.Mass) {
+		p.Unit = u.Name
+		return p
+	}
+	if unitName != "" && unitName != "undetermined" && unitName != "RACC" {
+		p.Modifier = strings.TrimSpace(unitName + " " + p.Modifier)
+		return p
+	}
+
+	head, rest, _ := strings.Cut(p.Modifier, ",")
+	if fields := strings.Fields(head); len(fields) > 0 {
+		if u, ok := units.Lookup(fields[0]); ok && (u.Dimension == units.Volume || u.Dimension == units.Mass) {
+			p.Unit = u.Name
+			p.Modifier = strings.TrimSpace(strings.Join(fields[1:], " ") + " " + rest)
+		}
+	}
+	return p
+}
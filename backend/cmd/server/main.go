@@ -52,9 +52,11 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/dishflow/backend/internal/config"
+	"github.com/dishflow/backend/internal/pkg/tag"
 	"github.com/dishflow/backend/internal/repository/postgres"
 	"github.com/dishflow/backend/internal/router"
 	"github.com/dishflow/backend/internal/service/cleanup"
+	"github.com/dishflow/backend/internal/service/dedup"
 )
 
 func main() {
@@ -155,6 +157,30 @@ func main() {
 			slog.Duration("max_job_age", maxJobAge))
 	}
 
+	// Start duplicate analysis worker if enabled
+	var dedupCancel context.CancelFunc
+	if cfg.DedupEnabled {
+		dedupCtx, cancel := context.WithCancel(context.Background())
+		dedupCancel = cancel
+
+		dedupInterval, err := time.ParseDuration(cfg.DedupInterval)
+		if err != nil {
+			logger.Warn("Invalid dedup interval, using default",
+				slog.String("value", cfg.DedupInterval),
+				slog.String("default", "15m"))
+			dedupInterval = 15 * time.Minute
+		}
+
+		dedupService := dedup.NewService(
+			postgres.NewDuplicateRepository(db, postgres.NewRecipeRepository(db, tag.NewNormalizer(cfg.TagSynonyms))),
+			logger,
+			dedup.Config{Interval: dedupInterval},
+		)
+
+		go dedupService.Start(dedupCtx)
+		logger.Info("Dedup worker started", slog.Duration("interval", dedupInterval))
+	}
+
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -185,6 +211,10 @@ func main() {
 		logger.Info("Stopping cleanup worker...")
 		cleanupCancel()
 	}
+	if dedupCancel != nil {
+		logger.Info("Stopping dedup worker...")
+		dedupCancel()
+	}
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -55,9 +55,20 @@ type Config struct {
 	CleanupMaxJobAge string // Max age for stuck jobs (e.g., "35m")
 	CleanupTempDir   string // Directory for temp files
 
+	// Duplicate recipe analysis worker
+	DedupEnabled  bool   // Enable background duplicate analysis
+	DedupInterval string // How often to analyze changed libraries (e.g., "15m")
+
+	// Tag synonyms applied on top of the built-in ones when tags are saved,
+	// e.g. "plant-based=vegan,veggie=vegetarian"
+	TagSynonyms map[string]string
+
 	// Concurrency limits
-	MaxConcurrentVideoJobs int // Max parallel video extraction jobs
-	MaxConcurrentLightJobs int // Max parallel URL/image extraction jobs
+	MaxConcurrentVideoJobs int           // Max parallel video extraction jobs
+	MaxConcurrentLightJobs int           // Max parallel URL/image extraction jobs
+	MaxJobsPerUser         int           // Max parallel extraction jobs per user per lane (0 = unlimited)
+	MaxBulkJobsPerUser     int           // Per-user cap for admin/inspirator bulk imports
+	JobQueueAging          time.Duration // Wait after which a queued job is promoted one priority class
 
 	// Swagger documentation
 	EnableSwagger bool // Enable Swagger UI at /swagger/
@@ -134,9 +145,19 @@ func Load() *Config {
 		CleanupMaxJobAge: getEnv("CLEANUP_MAX_JOB_AGE", "35m"),
 		CleanupTempDir:   getEnv("CLEANUP_TEMP_DIR", ""),
 
+		// Duplicate analysis worker
+		DedupEnabled:  getBoolEnv("DEDUP_ENABLED", true),
+		DedupInterval: getEnv("DEDUP_INTERVAL", "15m"),
+
+		// Tags
+		TagSynonyms: parseSynonyms(getEnv("TAG_SYNONYMS", "")),
+
 		// Concurrency
 		MaxConcurrentVideoJobs: getIntEnv("MAX_CONCURRENT_VIDEO_JOBS", 20),
 		MaxConcurrentLightJobs: getIntEnv("MAX_CONCURRENT_LIGHT_JOBS", 30),
+		MaxJobsPerUser:         getIntEnv("MAX_JOBS_PER_USER", 2),
+		MaxBulkJobsPerUser:     getIntEnv("MAX_BULK_JOBS_PER_USER", 5),
+		JobQueueAging:          getDurationEnv("JOB_QUEUE_AGING", 2*time.Minute),
 
 		// Swagger
 		EnableSwagger: getBoolEnv("ENABLE_SWAGGER", false),
@@ -213,6 +234,23 @@ func parseEmailList(raw string) []string {
 	return emails
 }
 
+// parseSynonyms parses comma-separated "from=to" pairs. Malformed entries
+// are skipped.
+func parseSynonyms(raw string) map[string]string {
+	if raw == "" {
+		return nil
+	}
+	synonyms := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		from, to, ok := strings.Cut(pair, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if ok && from != "" && to != "" {
+			synonyms[from] = to
+		}
+	}
+	return synonyms
+}
+
 // getIntEnv gets an integer environment variable with a default value
 func getIntEnv(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
+)
+
+// AnnotationHandler handles users' private notes on the ingredients and
+// steps of recipes they can see, their own or public ones
+type AnnotationHandler struct {
+	annotationRepo AnnotationRepository
+	recipeRepo     RecipeRepository
+}
+
+// NewAnnotationHandler creates a new annotation handler
+func NewAnnotationHandler(annotationRepo AnnotationRepository, recipeRepo RecipeRepository) *AnnotationHandler {
+	return &AnnotationHandler{
+		annotationRepo: annotationRepo,
+		recipeRepo:     recipeRepo,
+	}
+}
+
+// List handles GET /api/v1/recipes/{recipeID}/annotations
+// @Summary List your notes on a recipe
+// @Description Get your private notes on a recipe's ingredients and steps. Recipe details already show them inline.
+// @Tags Annotations
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Success 200 {object} SwaggerAnnotationListResponse "Notes"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid recipe ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Router /recipes/{recipeID}/annotations [get]
+func (h *AnnotationHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, recipe, ok := h.visibleRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	annotations, err := h.annotationRepo.ListByRecipe(r.Context(), recipe.ID, user.ID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"items": annotations,
+	})
+}
+
+// Set handles PUT /api/v1/recipes/{recipeID}/annotations
+// @Summary Set a note on an ingredient or step
+// @Description Write your private note on one of a recipe's ingredients or steps, replacing the note already there. Notes stay with you, not the recipe, and follow the ingredient or step through later edits.
+// @Tags Annotations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Param body body SwaggerAnnotationInput true "Note"
+// @Success 200 {object} SwaggerRecipeAnnotation "Saved note"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid input or unknown ingredient or step"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Router /recipes/{recipeID}/annotations [put]
+func (h *AnnotationHandler) Set(w http.ResponseWriter, r *http.Request) {
+	user, recipe, ok := h.visibleRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	var input model.RecipeAnnotationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	anchor, position, found := recipe.AnnotationTarget(input.Target, input.TargetID)
+	if !found {
+		response.ValidationFailed(w, "targetId", "no such "+input.Target+" in this recipe")
+		return
+	}
+
+	annotation, err := h.annotationRepo.Set(r.Context(), &model.RecipeAnnotation{
+		UserID:   user.ID,
+		RecipeID: recipe.ID,
+		Target:   input.Target,
+		TargetID: input.TargetID,
+		Anchor:   anchor,
+		Position: position,
+		Note:     input.Note,
+	})
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, annotation)
+}
+
+// Delete handles DELETE /api/v1/recipes/{recipeID}/annotations/{annotationID}
+// @Summary Delete a note
+// @Description Delete one of your notes on a recipe
+// @Tags Annotations
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Param annotationID path string true "Annotation UUID"
+// @Success 204 "Deleted"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Note not found"
+// @Router /recipes/{recipeID}/annotations/{annotationID} [delete]
+func (h *AnnotationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid recipe ID")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "annotationID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid annotation ID")
+		return
+	}
+
+	// Notes are the user's own, so deleting one doesn't need the recipe to
+	// still be visible to them
+	if err := h.annotationRepo.Delete(r.Context(), id, recipeID, user.ID); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			response.NotFound(w, "Note")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// visibleRecipe loads the recipe in the URL and checks the user can see it,
+// writing the error response when they can't
+func (h *AnnotationHandler) visibleRecipe(w http.ResponseWriter, r *http.Request) (*model.User, *model.Recipe, bool) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return nil, nil, false
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid recipe ID")
+		return nil, nil, false
+	}
+
+	recipe, err := h.recipeRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrRecipeNotFound) {
+			response.NotFound(w, "Recipe")
+			return nil, nil, false
+		}
+		response.InternalError(w)
+		return nil, nil, false
+	}
+
+	if recipe.UserID != user.ID && !recipe.IsPublic && !recipe.IsFeatured {
+		response.Forbidden(w, "Access denied")
+		return nil, nil, false
+	}
+	return user, recipe, true
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+func TestAnnotationHandler_Set(t *testing.T) {
+	mockAnnotationRepo := &mockAnnotationRepository{}
+	mockRecipeRepo := &mockRecipeRepository{}
+	handler := NewAnnotationHandler(mockAnnotationRepo, mockRecipeRepo)
+
+	user := &model.User{ID: uuid.New()}
+	recipeID, saltID := uuid.New(), uuid.New()
+	params := map[string]string{"recipeID": recipeID.String()}
+	isPublic := true
+	mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return &model.Recipe{
+			ID: id, UserID: uuid.New(), IsPublic: isPublic,
+			Ingredients: []model.RecipeIngredient{{ID: saltID, Name: "Sea Salt", SortOrder: 2}},
+		}, nil
+	}
+
+	t.Run("notes an ingredient of a public recipe", func(t *testing.T) {
+		var got *model.RecipeAnnotation
+		mockAnnotationRepo.SetFunc = func(ctx context.Context, a *model.RecipeAnnotation) (*model.RecipeAnnotation, error) {
+			got = a
+			return a, nil
+		}
+
+		body := `{"target":"ingredient","targetId":"` + saltID.String() + `","note":"  use less salt "}`
+		rr := httptest.NewRecorder()
+		handler.Set(rr, shareRequest("PUT", "/", body, user, params))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if got.UserID != user.ID || got.RecipeID != recipeID || got.Note != "use less salt" {
+			t.Errorf("unexpected note %+v", got)
+		}
+		if got.Anchor != "sea salt" || got.Position != 2 {
+			t.Errorf("anchor = %q at %d, want sea salt at 2", got.Anchor, got.Position)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"bad target":       `{"target":"title","targetId":"` + saltID.String() + `","note":"x"}`,
+			"empty note":       `{"target":"ingredient","targetId":"` + saltID.String() + `","note":"  "}`,
+			"unknown target":   `{"target":"ingredient","targetId":"` + uuid.NewString() + `","note":"x"}`,
+			"step id mismatch": `{"target":"step","targetId":"` + saltID.String() + `","note":"x"}`,
+		} {
+			rr := httptest.NewRecorder()
+			handler.Set(rr, shareRequest("PUT", "/", body, user, params))
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", name, rr.Code)
+			}
+		}
+	})
+
+	t.Run("private recipe", func(t *testing.T) {
+		isPublic = false
+		defer func() { isPublic = true }()
+
+		body := `{"target":"ingredient","targetId":"` + saltID.String() + `","note":"x"}`
+		rr := httptest.NewRecorder()
+		handler.Set(rr, shareRequest("PUT", "/", body, user, params))
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+}
+
+func TestAnnotationHandler_Delete(t *testing.T) {
+	mockAnnotationRepo := &mockAnnotationRepository{}
+	handler := NewAnnotationHandler(mockAnnotationRepo, &mockRecipeRepository{})
+	user := &model.User{ID: uuid.New()}
+	params := map[string]string{"recipeID": uuid.NewString(), "annotationID": uuid.NewString()}
+
+	t.Run("deletes", func(t *testing.T) {
+		mockAnnotationRepo.DeleteFunc = func(ctx context.Context, id, recipeID, userID uuid.UUID) error {
+			if userID != user.ID {
+				t.Errorf("deleted as %s", userID)
+			}
+			return nil
+		}
+		rr := httptest.NewRecorder()
+		handler.Delete(rr, shareRequest("DELETE", "/", "", user, params))
+		if rr.Code != http.StatusNoContent {
+			t.Errorf("expected 204, got %d", rr.Code)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockAnnotationRepo.DeleteFunc = func(ctx context.Context, id, recipeID, userID uuid.UUID) error {
+			return model.ErrNotFound
+		}
+		rr := httptest.NewRecorder()
+		handler.Delete(rr, shareRequest("DELETE", "/", "", user, params))
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+}
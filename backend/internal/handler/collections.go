@@ -0,0 +1,486 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
+)
+
+// CollectionHandler handles recipe collection HTTP requests
+type CollectionHandler struct {
+	collectionRepo CollectionRepository
+	recipeRepo     RecipeRepository
+}
+
+// NewCollectionHandler creates a new collection handler
+func NewCollectionHandler(collectionRepo CollectionRepository, recipeRepo RecipeRepository) *CollectionHandler {
+	return &CollectionHandler{
+		collectionRepo: collectionRepo,
+		recipeRepo:     recipeRepo,
+	}
+}
+
+// List handles GET /api/v1/collections
+// @Summary List collections
+// @Description Get the current user's recipe collections in their manual order
+// @Tags Collections
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SwaggerCollectionsResponse "List of collections"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 500 {object} SwaggerErrorResponse "Internal server error"
+// @Router /collections [get]
+func (h *CollectionHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	collections, err := h.collectionRepo.List(ctx, user.ID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"collections": collections,
+		"count":       len(collections),
+	})
+}
+
+// Get handles GET /api/v1/collections/{id}
+// @Summary Get collection by ID
+// @Description Get a collection with its ordered recipe IDs
+// @Tags Collections
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Collection UUID"
+// @Success 200 {object} SwaggerCollection "Collection"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid collection ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Collection not found"
+// @Router /collections/{id} [get]
+func (h *CollectionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid collection ID")
+		return
+	}
+
+	collection, err := h.collectionRepo.Get(ctx, id, user.ID)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	response.OK(w, collection)
+}
+
+// Create handles POST /api/v1/collections
+// @Summary Create a collection
+// @Description Create a new recipe collection at the end of the user's list
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SwaggerCollectionInput true "Collection data"
+// @Success 201 {object} SwaggerCollection "Collection created"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid request body"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 500 {object} SwaggerErrorResponse "Internal server error"
+// @Router /collections [post]
+func (h *CollectionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	var input model.CollectionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	collection, err := h.collectionRepo.Create(ctx, user.ID, &input)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	response.Created(w, collection)
+}
+
+// Update handles PUT /api/v1/collections/{id}
+// @Summary Update a collection
+// @Description Rename a collection or change its description and cover
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Collection UUID"
+// @Param request body SwaggerCollectionInput true "Updated collection data"
+// @Success 200 {object} SwaggerCollection "Collection updated"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid request body"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Collection not found"
+// @Router /collections/{id} [put]
+func (h *CollectionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid collection ID")
+		return
+	}
+
+	var input model.CollectionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	collection, err := h.collectionRepo.Update(ctx, id, user.ID, &input)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	response.OK(w, collection)
+}
+
+// Delete handles DELETE /api/v1/collections/{id}
+// @Summary Delete a collection
+// @Description Delete a collection. The recipes in it are kept.
+// @Tags Collections
+// @Security BearerAuth
+// @Param id path string true "Collection UUID"
+// @Success 204 "Collection deleted"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid collection ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Collection not found"
+// @Router /collections/{id} [delete]
+func (h *CollectionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid collection ID")
+		return
+	}
+
+	if err := h.collectionRepo.Delete(ctx, id, user.ID); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Reorder handles PUT /api/v1/collections/order
+// @Summary Reorder collections
+// @Description Set the order of the user's collections. Collections not listed keep their relative order after the listed ones.
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SwaggerCollectionOrderInput true "Collection IDs in the new order"
+// @Success 200 {object} SwaggerCollectionsResponse "Reordered collections"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid request body"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Collection not found"
+// @Router /collections/order [put]
+func (h *CollectionHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	var input model.CollectionOrderInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	if err := h.collectionRepo.Reorder(ctx, user.ID, input.IDs); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	collections, err := h.collectionRepo.List(ctx, user.ID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"collections": collections,
+		"count":       len(collections),
+	})
+}
+
+// ListRecipes handles GET /api/v1/collections/{id}/recipes
+// @Summary List recipes in a collection
+// @Description Get a paginated list of the recipes in a collection, in collection order unless sort is set. Takes the same filters as the recipe list.
+// @Tags Collections
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Collection UUID"
+// @Param cuisine query string false "Cuisines, e.g. italian,thai"
+// @Param difficulty query string false "Difficulties" Enums(easy, medium, hard)
+// @Param sourceType query string false "Source types, e.g. video,manual"
+// @Param tag query string false "Tags the recipe must all have"
+// @Param diet query string false "Diets the recipe must all fit" Enums(vegetarian, vegan, gluten-free, dairy-free, nut-free, keto, halal, kosher)
+// @Param excludeAllergens query string false "Allergens to avoid, e.g. nuts,dairy"
+// @Param maxTotalTime query int false "Max prep + cook time in minutes"
+// @Param maxCalories query int false "Max calories per serving"
+// @Param favorites query bool false "Only favorites"
+// @Param sort query string false "Sort order; collection order when omitted" Enums(recent, title, time, calories, most-cooked, last-cooked)
+// @Param limit query int false "Items per page (max 50)" default(20)
+// @Param cursor query string false "Cursor from a previous page's nextCursor"
+// @Param offset query int false "Rows to skip, for clients that page by offset; ignored with cursor"
+// @Success 200 {object} SwaggerRecipeListResponse "List of recipes"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid collection ID, filter or cursor"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Collection not found"
+// @Router /collections/{id}/recipes [get]
+func (h *CollectionHandler) ListRecipes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid collection ID")
+		return
+	}
+
+	filter, ok := recipeFilter(w, r)
+	if !ok {
+		return
+	}
+	page := pageParams(r, 20, 50)
+
+	// Ownership check
+	if _, err := h.collectionRepo.Get(ctx, id, user.ID); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	recipes, total, next, err := h.recipeRepo.ListByCollection(ctx, id, user.ID, filter, page)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	if recipes == nil {
+		recipes = []*model.Recipe{}
+	}
+
+	response.OK(w, withNextCursor(map[string]interface{}{
+		"items": recipes,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
+}
+
+// AddRecipes handles POST /api/v1/collections/{id}/recipes
+// @Summary Add recipes to a collection
+// @Description Append recipes to the end of a collection. Recipes already in it are left where they are.
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Collection UUID"
+// @Param request body SwaggerCollectionRecipesInput true "Recipes to add"
+// @Success 200 {object} SwaggerCollection "Updated collection"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid request body"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Collection or recipe not found"
+// @Router /collections/{id}/recipes [post]
+func (h *CollectionHandler) AddRecipes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid collection ID")
+		return
+	}
+
+	var input model.CollectionRecipesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	collection, err := h.collectionRepo.AddRecipes(ctx, id, user.ID, input.RecipeIDs)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	response.OK(w, collection)
+}
+
+// ReorderRecipes handles PUT /api/v1/collections/{id}/recipes/order
+// @Summary Reorder recipes in a collection
+// @Description Set the order of recipes in a collection. Recipes not listed keep their relative order after the listed ones.
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Collection UUID"
+// @Param request body SwaggerCollectionOrderInput true "Recipe IDs in the new order"
+// @Success 200 {object} SwaggerCollection "Updated collection"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid request body"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Collection or recipe not found"
+// @Router /collections/{id}/recipes/order [put]
+func (h *CollectionHandler) ReorderRecipes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid collection ID")
+		return
+	}
+
+	var input model.CollectionOrderInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	collection, err := h.collectionRepo.ReorderRecipes(ctx, id, user.ID, input.IDs)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	response.OK(w, collection)
+}
+
+// RemoveRecipe handles DELETE /api/v1/collections/{id}/recipes/{recipeID}
+// @Summary Remove a recipe from a collection
+// @Description Remove a recipe from a collection. The recipe itself is kept.
+// @Tags Collections
+// @Security BearerAuth
+// @Param id path string true "Collection UUID"
+// @Param recipeID path string true "Recipe UUID"
+// @Success 204 "Recipe removed"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Collection not found or recipe not in it"
+// @Router /collections/{id}/recipes/{recipeID} [delete]
+func (h *CollectionHandler) RemoveRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid collection ID")
+		return
+	}
+
+	recipeID, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid recipe ID")
+		return
+	}
+
+	if err := h.collectionRepo.RemoveRecipe(ctx, id, user.ID, recipeID); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// validateInput writes a validation error response; it returns false when
+// the input was rejected
+func validateInput(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return true
+	}
+	var valErr model.ErrValidation
+	if errors.As(err, &valErr) {
+		response.ValidationFailed(w, valErr.Field, valErr.Reason)
+		return false
+	}
+	response.BadRequest(w, err.Error())
+	return false
+}
+
+// writeCollectionError maps collection repository errors to responses
+func writeCollectionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		response.NotFound(w, "Collection")
+	case errors.Is(err, postgres.ErrRecipeNotFound):
+		response.NotFound(w, "Recipe")
+	default:
+		response.InternalError(w)
+	}
+}
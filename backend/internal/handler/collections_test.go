@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
+	"github.com/dishflow/backend/internal/repository/postgres"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func collectionRequest(method, target, body string, userID, collectionID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", collectionID.String())
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, middleware.UserContextKey, &model.User{ID: userID})
+	return req.WithContext(ctx)
+}
+
+func TestCollectionHandler_Create(t *testing.T) {
+	mockRepo := &mockCollectionRepository{}
+	handler := NewCollectionHandler(mockRepo, &mockRecipeRepository{})
+	userID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.CreateFunc = func(ctx context.Context, uid uuid.UUID, input *model.CollectionInput) (*model.Collection, error) {
+			return &model.Collection{ID: uuid.New(), UserID: uid, Name: input.Name, RecipeIDs: []uuid.UUID{}}, nil
+		}
+
+		req := collectionRequest("POST", "/collections", `{"name":"  Weeknight Dinners "}`, userID, uuid.Nil)
+		rr := httptest.NewRecorder()
+		handler.Create(rr, req)
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var c model.Collection
+		json.NewDecoder(rr.Body).Decode(&c)
+		if c.Name != "Weeknight Dinners" {
+			t.Errorf("expected trimmed name, got %q", c.Name)
+		}
+	})
+
+	t.Run("name required", func(t *testing.T) {
+		req := collectionRequest("POST", "/collections", `{"name":"  "}`, userID, uuid.Nil)
+		rr := httptest.NewRecorder()
+		handler.Create(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("cover recipe not owned", func(t *testing.T) {
+		mockRepo.CreateFunc = func(ctx context.Context, uid uuid.UUID, input *model.CollectionInput) (*model.Collection, error) {
+			return nil, postgres.ErrRecipeNotFound
+		}
+
+		body := `{"name":"Soups","coverRecipeId":"` + uuid.New().String() + `"}`
+		req := collectionRequest("POST", "/collections", body, userID, uuid.Nil)
+		rr := httptest.NewRecorder()
+		handler.Create(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+}
+
+func TestCollectionHandler_ListRecipes(t *testing.T) {
+	mockRepo := &mockCollectionRepository{}
+	mockRecipeRepo := &mockRecipeRepository{}
+	handler := NewCollectionHandler(mockRepo, mockRecipeRepo)
+	userID := uuid.New()
+	collectionID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.GetFunc = func(ctx context.Context, id, uid uuid.UUID) (*model.Collection, error) {
+			return &model.Collection{ID: id, UserID: uid}, nil
+		}
+		var gotPage pagination.Page
+		mockRecipeRepo.ListByCollectionFunc = func(ctx context.Context, cid, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+			gotPage = page
+			return []*model.Recipe{{ID: uuid.New(), Title: "Pho"}}, 7, "next-page", nil
+		}
+
+		req := collectionRequest("GET", "/collections/x/recipes?limit=5&cursor=this-page", "", userID, collectionID)
+		rr := httptest.NewRecorder()
+		handler.ListRecipes(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if gotPage != (pagination.Page{Limit: 5, Cursor: "this-page"}) {
+			t.Errorf("expected limit 5 and cursor, got %+v", gotPage)
+		}
+		var resp struct {
+			Items      []model.Recipe `json:"items"`
+			Total      int            `json:"total"`
+			NextCursor string         `json:"nextCursor"`
+		}
+		json.NewDecoder(rr.Body).Decode(&resp)
+		if len(resp.Items) != 1 || resp.Total != 7 || resp.NextCursor != "next-page" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("filters and sort", func(t *testing.T) {
+		mockRepo.GetFunc = func(ctx context.Context, id, uid uuid.UUID) (*model.Collection, error) {
+			return &model.Collection{ID: id, UserID: uid}, nil
+		}
+		var gotUser uuid.UUID
+		var gotFilter model.RecipeFilter
+		mockRecipeRepo.ListByCollectionFunc = func(ctx context.Context, cid, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+			gotUser, gotFilter = uid, filter
+			return []*model.Recipe{}, 0, "", nil
+		}
+
+		req := collectionRequest("GET", "/collections/x/recipes?cuisine=thai,italian&diet=vegan&favorites=true&sort=title", "", userID, collectionID)
+		rr := httptest.NewRecorder()
+		handler.ListRecipes(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if gotUser != userID {
+			t.Errorf("expected recipes scoped to %s, got %s", userID, gotUser)
+		}
+		if !reflect.DeepEqual(gotFilter.Cuisines, []string{"thai", "italian"}) || !reflect.DeepEqual(gotFilter.Diets, []string{"vegan"}) ||
+			!gotFilter.FavoritesOnly || gotFilter.Sort != "title" {
+			t.Errorf("unexpected filter: %+v", gotFilter)
+		}
+	})
+
+	t.Run("collection order by default", func(t *testing.T) {
+		var gotFilter model.RecipeFilter
+		mockRecipeRepo.ListByCollectionFunc = func(ctx context.Context, cid, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+			gotFilter = filter
+			return []*model.Recipe{}, 0, "", nil
+		}
+
+		req := collectionRequest("GET", "/collections/x/recipes", "", userID, collectionID)
+		handler.ListRecipes(httptest.NewRecorder(), req)
+
+		if gotFilter.Sort != "" {
+			t.Errorf("expected no sort so the collection order applies, got %q", gotFilter.Sort)
+		}
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		mockRecipeRepo.ListByCollectionFunc = func(ctx context.Context, cid, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+			t.Fatal("recipes should not be listed")
+			return nil, 0, "", nil
+		}
+
+		req := collectionRequest("GET", "/collections/x/recipes?difficulty=impossible", "", userID, collectionID)
+		rr := httptest.NewRecorder()
+		handler.ListRecipes(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("other user's collection", func(t *testing.T) {
+		mockRepo.GetFunc = nil // repository scopes by user and reports not found
+		mockRecipeRepo.ListByCollectionFunc = func(ctx context.Context, cid, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+			t.Fatal("recipes should not be listed")
+			return nil, 0, "", nil
+		}
+
+		req := collectionRequest("GET", "/collections/x/recipes", "", userID, collectionID)
+		rr := httptest.NewRecorder()
+		handler.ListRecipes(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+}
+
+func TestCollectionHandler_ReorderRecipes(t *testing.T) {
+	mockRepo := &mockCollectionRepository{}
+	handler := NewCollectionHandler(mockRepo, &mockRecipeRepository{})
+	userID := uuid.New()
+	collectionID := uuid.New()
+	a, b := uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.ReorderRecipesFunc = func(ctx context.Context, id, uid uuid.UUID, recipeIDs []uuid.UUID) (*model.Collection, error) {
+			return &model.Collection{ID: id, RecipeIDs: recipeIDs, RecipeCount: len(recipeIDs)}, nil
+		}
+
+		body := `{"ids":["` + b.String() + `","` + a.String() + `"]}`
+		req := collectionRequest("PUT", "/collections/x/recipes/order", body, userID, collectionID)
+		rr := httptest.NewRecorder()
+		handler.ReorderRecipes(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		var c model.Collection
+		json.NewDecoder(rr.Body).Decode(&c)
+		if len(c.RecipeIDs) != 2 || c.RecipeIDs[0] != b {
+			t.Errorf("unexpected order: %v", c.RecipeIDs)
+		}
+	})
+
+	t.Run("duplicates rejected", func(t *testing.T) {
+		body := `{"ids":["` + a.String() + `","` + a.String() + `"]}`
+		req := collectionRequest("PUT", "/collections/x/recipes/order", body, userID, collectionID)
+		rr := httptest.NewRecorder()
+		handler.ReorderRecipes(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
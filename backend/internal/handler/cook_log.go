@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+)
+
+// maxCookPhotoSize is the largest cook log photo accepted, matching what
+// the thumbnail store keeps
+const maxCookPhotoSize = 5 << 20
+
+// cookPhotoExts maps detected image types to the extension they're stored with
+var cookPhotoExts = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// CookLogHandler handles the cook log: when users cooked their recipes and
+// how it went
+type CookLogHandler struct {
+	cookLogRepo CookLogRepository
+	recipeRepo  RecipeRepository
+	photos      ThumbnailDownloader
+}
+
+// NewCookLogHandler creates a new cook log handler. Photos are stored with
+// the thumbnail store.
+func NewCookLogHandler(cookLogRepo CookLogRepository, recipeRepo RecipeRepository, photos ThumbnailDownloader) *CookLogHandler {
+	return &CookLogHandler{
+		cookLogRepo: cookLogRepo,
+		recipeRepo:  recipeRepo,
+		photos:      photos,
+	}
+}
+
+// ListByRecipe handles GET /api/v1/recipes/{recipeID}/cooks
+// @Summary List a recipe's cooks
+// @Description Get a page of the times you cooked one of your recipes, most recent first
+// @Tags Cook Log
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Param limit query int false "Page size (max 50)" default(20)
+// @Param cursor query string false "nextCursor from the previous page"
+// @Success 200 {object} SwaggerCookLogListResponse "Cook log page"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid recipe ID or cursor"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Router /recipes/{recipeID}/cooks [get]
+func (h *CookLogHandler) ListByRecipe(w http.ResponseWriter, r *http.Request) {
+	recipe, ok := ownedRecipe(w, r, h.recipeRepo)
+	if !ok {
+		return
+	}
+
+	page := pageParams(r, 20, 50)
+	logs, next, err := h.cookLogRepo.ListByRecipe(r.Context(), recipe.ID, recipe.UserID, page)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	response.OK(w, withNextCursor(map[string]interface{}{
+		"items": logs,
+		"limit": page.Limit,
+	}, next))
+}
+
+// Create handles POST /api/v1/recipes/{recipeID}/cooks
+// @Summary Log a cook
+// @Description Record that you cooked one of your recipes, with servings made, a 1-5 rating, private notes and any modifications. Photos are added afterwards with the photo upload endpoint.
+// @Tags Cook Log
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Param body body SwaggerCookLogInput true "Cook details"
+// @Success 201 {object} SwaggerCookLog "Logged cook"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid input"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Router /recipes/{recipeID}/cooks [post]
+func (h *CookLogHandler) Create(w http.ResponseWriter, r *http.Request) {
+	recipe, ok := ownedRecipe(w, r, h.recipeRepo)
+	if !ok {
+		return
+	}
+
+	var input model.CookLogInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	log, err := h.cookLogRepo.Create(r.Context(), recipe.ID, recipe.UserID, &input)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.Created(w, log)
+}
+
+// List handles GET /api/v1/cook-log
+// @Summary List your cook log
+// @Description Get a page of everything you've cooked, most recent first
+// @Tags Cook Log
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Page size (max 50)" default(20)
+// @Param cursor query string false "nextCursor from the previous page"
+// @Success 200 {object} SwaggerCookLogListResponse "Cook log page"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid cursor"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Router /cook-log [get]
+func (h *CookLogHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	page := pageParams(r, 20, 50)
+	logs, next, err := h.cookLogRepo.ListByUser(r.Context(), user.ID, page)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	response.OK(w, withNextCursor(map[string]interface{}{
+		"items": logs,
+		"limit": page.Limit,
+	}, next))
+}
+
+// Update handles PUT /api/v1/cook-log/{id}
+// @Summary Edit a logged cook
+// @Description Replace a logged cook's details. Set photos to the photos to keep, in order, to remove or reorder them; leave it out to keep them all.
+// @Tags Cook Log
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cook log entry UUID"
+// @Param body body SwaggerCookLogInput true "Cook details"
+// @Success 200 {object} SwaggerCookLog "Updated cook"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid input"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Cook not found"
+// @Router /cook-log/{id} [put]
+func (h *CookLogHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid cook ID")
+		return
+	}
+
+	var input model.CookLogInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	if input.Photos != nil {
+		existing, err := h.cookLogRepo.Get(r.Context(), id, user.ID)
+		if err != nil {
+			writeCookLogError(w, err)
+			return
+		}
+		for i, photo := range *input.Photos {
+			if !slices.Contains(existing.Photos, photo) || slices.Contains((*input.Photos)[:i], photo) {
+				response.ValidationFailed(w, "photos", "must only list the cook's uploaded photos, each once")
+				return
+			}
+		}
+	}
+
+	log, err := h.cookLogRepo.Update(r.Context(), id, user.ID, &input)
+	if err != nil {
+		writeCookLogError(w, err)
+		return
+	}
+
+	response.OK(w, log)
+}
+
+// Delete handles DELETE /api/v1/cook-log/{id}
+// @Summary Delete a logged cook
+// @Tags Cook Log
+// @Security BearerAuth
+// @Param id path string true "Cook log entry UUID"
+// @Success 204 "Cook deleted"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid cook ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Cook not found"
+// @Router /cook-log/{id} [delete]
+func (h *CookLogHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid cook ID")
+		return
+	}
+
+	if err := h.cookLogRepo.Delete(r.Context(), id, user.ID); err != nil {
+		writeCookLogError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// AddPhoto handles POST /api/v1/cook-log/{id}/photos
+// @Summary Add a photo to a logged cook
+// @Description Upload a photo of what you cooked (JPEG, PNG, WebP or GIF, max 5MB). A cook can have up to 6 photos.
+// @Tags Cook Log
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cook log entry UUID"
+// @Param photo formData file true "Photo"
+// @Success 200 {object} SwaggerCookLog "Cook with the photo added"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid photo or too many photos"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Cook not found"
+// @Router /cook-log/{id}/photos [post]
+func (h *CookLogHandler) AddPhoto(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid cook ID")
+		return
+	}
+
+	existing, err := h.cookLogRepo.Get(ctx, id, user.ID)
+	if err != nil {
+		writeCookLogError(w, err)
+		return
+	}
+	if len(existing.Photos) >= model.MaxCookLogPhotos {
+		response.ValidationFailed(w, "photo", "max 6 photos per cook")
+		return
+	}
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB max
+		response.LogAndBadRequest(w, "Failed to parse photo upload", err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll() // Cleanup temp files
+
+	file, _, err := r.FormFile("photo")
+	if err != nil {
+		response.ValidationFailed(w, "photo", "Photo file is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		response.BadRequest(w, "Failed to read photo file")
+		return
+	}
+	if len(data) > maxCookPhotoSize {
+		response.ValidationFailed(w, "photo", "max size 5MB")
+		return
+	}
+	ext, ok := cookPhotoExts[detectMimeType(data)]
+	if !ok {
+		response.ValidationFailed(w, "photo", "must be a JPEG, PNG, WebP or GIF image")
+		return
+	}
+
+	url, err := h.photos.Save(data, ext)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	log, err := h.cookLogRepo.AddPhoto(ctx, id, user.ID, url)
+	if err != nil {
+		writeCookLogError(w, err)
+		return
+	}
+
+	response.OK(w, log)
+}
+
+// writeCookLogError maps cook log repository errors to responses
+func writeCookLogError(w http.ResponseWriter, err error) {
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(w, "Cook")
+		return
+	}
+	response.InternalError(w)
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
+)
+
+func TestCookLogHandler_Create(t *testing.T) {
+	mockCookLogRepo := &mockCookLogRepository{}
+	mockRecipeRepo := &mockRecipeRepository{}
+	handler := NewCookLogHandler(mockCookLogRepo, mockRecipeRepo, nil)
+
+	user := &model.User{ID: uuid.New()}
+	recipeID := uuid.New()
+	params := map[string]string{"recipeID": recipeID.String()}
+	owner := user.ID
+	mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return &model.Recipe{ID: id, UserID: owner, Title: "Ramen"}, nil
+	}
+
+	t.Run("logs a cook", func(t *testing.T) {
+		var got *model.CookLogInput
+		mockCookLogRepo.CreateFunc = func(ctx context.Context, rID, uID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error) {
+			got = input
+			return &model.CookLog{ID: uuid.New(), RecipeID: rID, UserID: uID, CookedOn: input.Date, Rating: input.Rating}, nil
+		}
+
+		body := `{"cookedOn":"2026-02-10","servings":4,"rating":5,"notes":"  Kids loved it  ","modifications":["half the sugar"," "]}`
+		rr := httptest.NewRecorder()
+		handler.Create(rr, shareRequest("POST", "/", body, user, params))
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if !got.Date.Equal(time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("cookedOn = %v", got.Date)
+		}
+		if got.Notes == nil || *got.Notes != "Kids loved it" {
+			t.Errorf("notes = %v, want trimmed", got.Notes)
+		}
+		if len(got.Modifications) != 1 || got.Modifications[0] != "half the sugar" {
+			t.Errorf("modifications = %v, want blanks dropped", got.Modifications)
+		}
+	})
+
+	t.Run("defaults to today", func(t *testing.T) {
+		var got *model.CookLogInput
+		mockCookLogRepo.CreateFunc = func(ctx context.Context, rID, uID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error) {
+			got = input
+			return &model.CookLog{}, nil
+		}
+
+		rr := httptest.NewRecorder()
+		handler.Create(rr, shareRequest("POST", "/", `{}`, user, params))
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", rr.Code)
+		}
+		if today := time.Now().UTC().Truncate(24 * time.Hour); !got.Date.Equal(today) {
+			t.Errorf("cookedOn = %v, want %v", got.Date, today)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"rating too high": `{"rating":6}`,
+			"rating too low":  `{"rating":0}`,
+			"bad date":        `{"cookedOn":"10/02/2026"}`,
+			"future date":     `{"cookedOn":"2999-01-01"}`,
+			"no servings":     `{"servings":0}`,
+		} {
+			rr := httptest.NewRecorder()
+			handler.Create(rr, shareRequest("POST", "/", body, user, params))
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", name, rr.Code)
+			}
+		}
+	})
+
+	t.Run("someone else's recipe", func(t *testing.T) {
+		owner = uuid.New()
+		defer func() { owner = user.ID }()
+
+		rr := httptest.NewRecorder()
+		handler.Create(rr, shareRequest("POST", "/", `{"rating":4}`, user, params))
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+}
+
+func TestCookLogHandler_List(t *testing.T) {
+	mockCookLogRepo := &mockCookLogRepository{}
+	handler := NewCookLogHandler(mockCookLogRepo, &mockRecipeRepository{}, nil)
+	user := &model.User{ID: uuid.New()}
+
+	mockCookLogRepo.ListByUserFunc = func(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*model.CookLog, string, error) {
+		if page.Cursor == "bad" {
+			return nil, "", pagination.ErrInvalidCursor
+		}
+		return []*model.CookLog{{ID: uuid.New(), UserID: userID, RecipeTitle: "Ramen"}}, "next", nil
+	}
+
+	t.Run("returns a page with the next cursor", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.List(rr, shareRequest("GET", "/cook-log?limit=1", "", user, nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		var resp struct {
+			Items      []model.CookLog `json:"items"`
+			NextCursor string          `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Items) != 1 || resp.NextCursor != "next" {
+			t.Errorf("got %d items, cursor %q", len(resp.Items), resp.NextCursor)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.List(rr, shareRequest("GET", "/cook-log?cursor=bad", "", user, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
+
+func TestCookLogHandler_Update(t *testing.T) {
+	mockCookLogRepo := &mockCookLogRepository{}
+	handler := NewCookLogHandler(mockCookLogRepo, &mockRecipeRepository{}, nil)
+	user := &model.User{ID: uuid.New()}
+	id := uuid.New()
+	params := map[string]string{"id": id.String()}
+
+	mockCookLogRepo.GetFunc = func(ctx context.Context, cookID, userID uuid.UUID) (*model.CookLog, error) {
+		return &model.CookLog{ID: cookID, UserID: userID, Photos: []string{"a.jpg", "b.jpg"}}, nil
+	}
+	mockCookLogRepo.UpdateFunc = func(ctx context.Context, cookID, userID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error) {
+		return &model.CookLog{ID: cookID, Photos: *input.Photos}, nil
+	}
+
+	t.Run("removes and reorders photos", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Update(rr, shareRequest("PUT", "/", `{"photos":["b.jpg"]}`, user, params))
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("rejects photos that weren't uploaded", func(t *testing.T) {
+		for _, body := range []string{`{"photos":["https://evil.example/x.jpg"]}`, `{"photos":["a.jpg","a.jpg"]}`} {
+			rr := httptest.NewRecorder()
+			handler.Update(rr, shareRequest("PUT", "/", body, user, params))
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", body, rr.Code)
+			}
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockCookLogRepo.UpdateFunc = func(ctx context.Context, cookID, userID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error) {
+			return nil, model.ErrNotFound
+		}
+		rr := httptest.NewRecorder()
+		handler.Update(rr, shareRequest("PUT", "/", `{"rating":3}`, user, params))
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+}
+
+func TestCookLogHandler_AddPhoto(t *testing.T) {
+	mockCookLogRepo := &mockCookLogRepository{}
+	photos := &mockThumbnailDownloader{}
+	handler := NewCookLogHandler(mockCookLogRepo, &mockRecipeRepository{}, photos)
+	user := &model.User{ID: uuid.New()}
+	id := uuid.New()
+	params := map[string]string{"id": id.String()}
+
+	existing := []string{}
+	mockCookLogRepo.GetFunc = func(ctx context.Context, cookID, userID uuid.UUID) (*model.CookLog, error) {
+		return &model.CookLog{ID: cookID, Photos: existing}, nil
+	}
+	mockCookLogRepo.AddPhotoFunc = func(ctx context.Context, cookID, userID uuid.UUID, url string) (*model.CookLog, error) {
+		return &model.CookLog{ID: cookID, Photos: append(existing, url)}, nil
+	}
+	var savedExt string
+	photos.SaveFunc = func(data []byte, ext string) (string, error) {
+		savedExt = ext
+		return "https://example.com/api/v1/thumbnails/p" + ext, nil
+	}
+
+	upload := func(data []byte) *http.Request {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		fw, _ := mw.CreateFormFile("photo", "photo")
+		fw.Write(data)
+		mw.Close()
+		req := shareRequest("POST", "/", buf.String(), user, params)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return req
+	}
+	png := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+
+	t.Run("stores the photo", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.AddPhoto(rr, upload(png))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if savedExt != ".png" {
+			t.Errorf("saved as %q, want .png", savedExt)
+		}
+	})
+
+	t.Run("rejects non-images", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.AddPhoto(rr, upload([]byte("not an image")))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("too many photos", func(t *testing.T) {
+		existing = make([]string, model.MaxCookLogPhotos)
+		defer func() { existing = []string{} }()
+
+		rr := httptest.NewRecorder()
+		handler.AddPhoto(rr, upload(png))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,514 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
+	"github.com/dishflow/backend/internal/service/cooking"
+	"github.com/dishflow/backend/internal/service/scaling"
+)
+
+// CookSessionHandler handles cook sessions: cooking a recipe step by step
+// with timers, on any of the user's devices
+type CookSessionHandler struct {
+	sessionRepo CookSessionRepository
+	recipeRepo  RecipeRepository
+	cookLogRepo CookLogRepository
+	pantryRepo  PantryRepository
+}
+
+// NewCookSessionHandler creates a new cook session handler
+func NewCookSessionHandler(sessionRepo CookSessionRepository, recipeRepo RecipeRepository, cookLogRepo CookLogRepository, pantryRepo PantryRepository) *CookSessionHandler {
+	return &CookSessionHandler{
+		sessionRepo: sessionRepo,
+		recipeRepo:  recipeRepo,
+		cookLogRepo: cookLogRepo,
+		pantryRepo:  pantryRepo,
+	}
+}
+
+// Start handles POST /api/v1/recipes/{recipeID}/cook-sessions
+// @Summary Start cooking a recipe
+// @Description Start a cook session for one of your recipes, optionally scaled to a number of servings, on its first step. A recipe has one session in progress at a time; continue it from any device or abandon it to start over.
+// @Tags Cook Sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Param body body SwaggerCookSessionStartInput false "Servings to cook"
+// @Success 201 {object} SwaggerCookSession "Session with the scaled recipe"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid input"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Failure 409 {object} SwaggerErrorResponse "Recipe already being cooked"
+// @Router /recipes/{recipeID}/cook-sessions [post]
+func (h *CookSessionHandler) Start(w http.ResponseWriter, r *http.Request) {
+	recipe, ok := ownedRecipe(w, r, h.recipeRepo)
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var input model.CookSessionStartInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if input.Servings != nil && (*input.Servings < 1 || *input.Servings > scaling.MaxServings) {
+		response.ValidationFailed(w, "servings", fmt.Sprintf("must be between 1 and %d", scaling.MaxServings))
+		return
+	}
+
+	cooked, err := scaleRecipe(recipe, input.Servings)
+	if err != nil {
+		response.ValidationFailed(w, "servings", err.Error())
+		return
+	}
+
+	step := 1
+	if len(recipe.Steps) > 0 {
+		step = recipe.Steps[0].StepNumber
+	}
+
+	session, err := h.sessionRepo.Create(r.Context(), recipe.ID, recipe.UserID, input.Servings, step)
+	if err != nil {
+		if errors.Is(err, postgres.ErrCookSessionActive) {
+			response.Conflict(w, "Recipe is already being cooked; continue or abandon that session")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	session.Recipe = cooked
+	session.SetRemaining(time.Now())
+	response.Created(w, session)
+}
+
+// List handles GET /api/v1/cook-sessions
+// @Summary List cook sessions in progress
+// @Description Get the recipes you're cooking right now, most recently touched first, to continue one on this device
+// @Tags Cook Sessions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SwaggerCookSessionListResponse "Sessions in progress"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Router /cook-sessions [get]
+func (h *CookSessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	sessions, err := h.sessionRepo.ListActive(r.Context(), user.ID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	now := time.Now()
+	for _, s := range sessions {
+		s.SetRemaining(now)
+	}
+
+	response.OK(w, map[string]interface{}{
+		"items": sessions,
+	})
+}
+
+// Get handles GET /api/v1/cook-sessions/{id}
+// @Summary Get a cook session
+// @Description Get a cook session with its current step, timers and the recipe scaled to the session's servings. Poll it to follow a session from another device.
+// @Tags Cook Sessions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cook session UUID"
+// @Success 200 {object} SwaggerCookSession "Session with the scaled recipe"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid session ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Cook session or recipe not found"
+// @Router /cook-sessions/{id} [get]
+func (h *CookSessionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+	h.respond(w, r, session)
+}
+
+// Update handles PATCH /api/v1/cook-sessions/{id}
+// @Summary Move to another step
+// @Description Set the step a cook session is on
+// @Tags Cook Sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cook session UUID"
+// @Param body body SwaggerCookSessionUpdateInput true "Step number"
+// @Success 200 {object} SwaggerCookSession "Updated session"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid input"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Cook session not found"
+// @Failure 409 {object} SwaggerErrorResponse "Session already finished"
+// @Router /cook-sessions/{id} [patch]
+func (h *CookSessionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.activeSession(w, r)
+	if !ok {
+		return
+	}
+
+	var input model.CookSessionUpdateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	recipe, ok := h.recipe(w, r, session)
+	if !ok {
+		return
+	}
+	if !model.HasStep(recipe.Steps, input.CurrentStep) {
+		response.ValidationFailed(w, "currentStep", "no such step")
+		return
+	}
+
+	updated, err := h.sessionRepo.SetStep(r.Context(), session.ID, session.UserID, input.CurrentStep)
+	if err != nil {
+		writeCookSessionError(w, err)
+		return
+	}
+
+	updated.Recipe = recipe
+	updated.SetRemaining(time.Now())
+	response.OK(w, updated)
+}
+
+// StartTimer handles POST /api/v1/cook-sessions/{id}/timers
+// @Summary Start a timer
+// @Description Start a timer in a cook session. Give a step number to time that step: the timer is named after it and runs for the step's duration unless name or durationSeconds are given. A timer for no step needs both. Timers carry an absolute endsAt so every device counts down together; finished timers stay until stopped.
+// @Tags Cook Sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cook session UUID"
+// @Param body body SwaggerCookTimerInput true "Timer"
+// @Success 201 {object} SwaggerCookSession "Session with the new timer"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid input or too many timers"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Cook session not found"
+// @Failure 409 {object} SwaggerErrorResponse "Session already finished"
+// @Router /cook-sessions/{id}/timers [post]
+func (h *CookSessionHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.activeSession(w, r)
+	if !ok {
+		return
+	}
+
+	var input model.CookTimerInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if len(session.Timers) >= model.MaxCookTimers {
+		response.ValidationFailed(w, "timers", fmt.Sprintf("max %d timers", model.MaxCookTimers))
+		return
+	}
+
+	recipe, ok := h.recipe(w, r, session)
+	if !ok {
+		return
+	}
+	timer, err := input.Timer(recipe.Steps, time.Now())
+	if !validateInput(w, err) {
+		return
+	}
+
+	updated, err := h.sessionRepo.AddTimer(r.Context(), session.ID, session.UserID, timer)
+	if err != nil {
+		writeCookSessionError(w, err)
+		return
+	}
+
+	updated.Recipe = recipe
+	updated.SetRemaining(time.Now())
+	response.Created(w, updated)
+}
+
+// StopTimer handles DELETE /api/v1/cook-sessions/{id}/timers/{timerID}
+// @Summary Stop a timer
+// @Description Stop a running timer, or dismiss one that has finished
+// @Tags Cook Sessions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cook session UUID"
+// @Param timerID path string true "Timer UUID"
+// @Success 200 {object} SwaggerCookSession "Session without the timer"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Cook session or timer not found"
+// @Failure 409 {object} SwaggerErrorResponse "Session already finished"
+// @Router /cook-sessions/{id}/timers/{timerID} [delete]
+func (h *CookSessionHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.activeSession(w, r)
+	if !ok {
+		return
+	}
+
+	timerID, err := uuid.Parse(chi.URLParam(r, "timerID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid timer ID")
+		return
+	}
+
+	updated, err := h.sessionRepo.RemoveTimer(r.Context(), session.ID, session.UserID, timerID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			response.NotFound(w, "Timer")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	h.respond(w, r, updated)
+}
+
+// Finish handles POST /api/v1/cook-sessions/{id}/finish
+// @Summary Finish cooking
+// @Description Finish a cook session, logging the cook with the session's servings and an optional rating, notes and modifications. With deductPantry, the ingredients used (scaled, optional ones left out) are taken out of your pantry where they can be matched by name and converted to the pantry item's unit; items used up are removed. The session stays finished if the pantry can't be updated; pantryError then says so and pantry lists what was taken before it failed.
+// @Tags Cook Sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cook session UUID"
+// @Param body body SwaggerCookSessionFinishInput false "How it went"
+// @Success 200 {object} SwaggerCookSessionFinishResult "Finished session, logged cook and pantry changes"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid input"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Cook session not found"
+// @Failure 409 {object} SwaggerErrorResponse "Session already finished"
+// @Router /cook-sessions/{id}/finish [post]
+func (h *CookSessionHandler) Finish(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.activeSession(w, r)
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var input model.CookSessionFinishInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	recipe, ok := h.recipe(w, r, session)
+	if !ok {
+		return
+	}
+
+	logInput := model.CookLogInput{
+		Servings:      recipe.Servings,
+		Rating:        input.Rating,
+		Notes:         input.Notes,
+		Modifications: input.Modifications,
+	}
+	if !validateInput(w, logInput.Validate()) {
+		return
+	}
+
+	finished, err := h.sessionRepo.Finish(r.Context(), session.ID, session.UserID, &logInput)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			response.Conflict(w, "Cook session already finished")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+	finished.Recipe = recipe
+
+	// The session is finished and the cook logged from here on, so a retry
+	// would only get a conflict: report the rest as best it can be done
+	logger := middleware.GetLogger(r.Context())
+	result := &model.CookSessionFinishResult{
+		Session: finished,
+		Pantry:  []model.PantryDeduction{},
+	}
+	if result.CookLog, err = h.cookLogRepo.Get(r.Context(), *finished.CookLogID, finished.UserID); err != nil {
+		logger.Error("Failed to load logged cook", "error", err, "cook_session_id", finished.ID)
+	}
+
+	if input.DeductPantry {
+		if result.Pantry, err = h.deductPantry(r.Context(), finished.UserID, recipe.Ingredients); err != nil {
+			logger.Error("Failed to deduct pantry", "error", err, "cook_session_id", finished.ID)
+			result.PantryError = "Your pantry could not be fully updated"
+		}
+	}
+
+	response.OK(w, result)
+}
+
+// Abandon handles DELETE /api/v1/cook-sessions/{id}
+// @Summary Abandon a cook session
+// @Description Delete a cook session without logging a cook
+// @Tags Cook Sessions
+// @Security BearerAuth
+// @Param id path string true "Cook session UUID"
+// @Success 204 "Abandoned"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid session ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Cook session not found"
+// @Router /cook-sessions/{id} [delete]
+func (h *CookSessionHandler) Abandon(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid cook session ID")
+		return
+	}
+
+	if err := h.sessionRepo.Delete(r.Context(), id, user.ID); err != nil {
+		writeCookSessionError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// deductPantry takes ingredients out of the user's pantry, updating items
+// drawn on and removing those used up. On failure it returns the
+// deductions already made.
+func (h *CookSessionHandler) deductPantry(ctx context.Context, userID uuid.UUID, ingredients []model.RecipeIngredient) ([]model.PantryDeduction, error) {
+	items, err := h.pantryRepo.ListAll(ctx, userID)
+	if err != nil {
+		return []model.PantryDeduction{}, err
+	}
+	byID := make(map[uuid.UUID]model.PantryItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	deductions := cooking.Deduct(ingredients, items)
+	for i, d := range deductions {
+		if d.Removed {
+			err = h.pantryRepo.Delete(ctx, d.ItemID, userID)
+		} else {
+			item := byID[d.ItemID]
+			_, err = h.pantryRepo.Update(ctx, d.ItemID, userID, &model.PantryItemInput{
+				Name:     item.Name,
+				Category: item.Category,
+				Quantity: &d.Remaining,
+				Unit:     item.Unit,
+			})
+		}
+		// Removed or changed on another device in the meantime
+		if err != nil && !errors.Is(err, model.ErrNotFound) {
+			return deductions[:i], err
+		}
+	}
+	return deductions, nil
+}
+
+// respond writes a session with its recipe scaled to the session
+func (h *CookSessionHandler) respond(w http.ResponseWriter, r *http.Request, session *model.CookSession) {
+	recipe, ok := h.recipe(w, r, session)
+	if !ok {
+		return
+	}
+	session.Recipe = recipe
+	session.SetRemaining(time.Now())
+	response.OK(w, session)
+}
+
+// session loads the cook session in the URL for the user
+func (h *CookSessionHandler) session(w http.ResponseWriter, r *http.Request) (*model.CookSession, bool) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return nil, false
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid cook session ID")
+		return nil, false
+	}
+
+	session, err := h.sessionRepo.Get(r.Context(), id, user.ID)
+	if err != nil {
+		writeCookSessionError(w, err)
+		return nil, false
+	}
+	return session, true
+}
+
+// activeSession is session for changes, which need the session in progress
+func (h *CookSessionHandler) activeSession(w http.ResponseWriter, r *http.Request) (*model.CookSession, bool) {
+	session, ok := h.session(w, r)
+	if !ok {
+		return nil, false
+	}
+	if session.FinishedAt != nil {
+		response.Conflict(w, "Cook session already finished")
+		return nil, false
+	}
+	return session, true
+}
+
+// recipe loads a session's recipe scaled to the session's servings
+func (h *CookSessionHandler) recipe(w http.ResponseWriter, r *http.Request, session *model.CookSession) (*model.Recipe, bool) {
+	recipe, err := h.recipeRepo.GetByID(r.Context(), session.RecipeID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrRecipeNotFound) {
+			response.NotFound(w, "Recipe")
+			return nil, false
+		}
+		response.InternalError(w)
+		return nil, false
+	}
+
+	scaled, err := scaleRecipe(recipe, session.Servings)
+	if err != nil {
+		// The recipe lost its servings since the session started
+		return recipe, true
+	}
+	return scaled, true
+}
+
+// scaleRecipe scales recipe to servings, or returns it as written when
+// servings is nil
+func scaleRecipe(recipe *model.Recipe, servings *int) (*model.Recipe, error) {
+	if servings == nil {
+		return recipe, nil
+	}
+	return scaling.Recipe(recipe, *servings)
+}
+
+// writeCookSessionError maps cook session repository errors to responses
+func writeCookSessionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, model.ErrNotFound) {
+		response.NotFound(w, "Cook session")
+		return
+	}
+	response.InternalError(w)
+}
@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/repository/postgres"
+)
+
+func cookSessionTestRecipe(userID uuid.UUID) *model.Recipe {
+	servings, simmer := 2, 900
+	technique := "simmer"
+	return &model.Recipe{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Title:    "Tomato Soup",
+		Servings: &servings,
+		Ingredients: []model.RecipeIngredient{
+			{Name: "tomatoes", Quantity: ptr(400.0), Unit: ptr("g")},
+			{Name: "cream", Quantity: ptr(100.0), Unit: ptr("ml"), IsOptional: true},
+		},
+		Steps: []model.RecipeStep{
+			{StepNumber: 1, Instruction: "Chop the tomatoes"},
+			{StepNumber: 2, Instruction: "Simmer", DurationSeconds: &simmer, Technique: &technique},
+		},
+	}
+}
+
+func newCookSessionTestHandler(user *model.User) (*CookSessionHandler, *mockCookSessionRepository, *model.Recipe, *model.CookSession) {
+	recipe := cookSessionTestRecipe(user.ID)
+	servings := 4
+	session := &model.CookSession{
+		ID:          uuid.New(),
+		RecipeID:    recipe.ID,
+		UserID:      user.ID,
+		Servings:    &servings,
+		CurrentStep: 1,
+		Timers:      []model.CookTimer{},
+	}
+
+	sessionRepo := &mockCookSessionRepository{
+		GetFunc: func(ctx context.Context, id, userID uuid.UUID) (*model.CookSession, error) {
+			if id != session.ID || userID != user.ID {
+				return nil, model.ErrNotFound
+			}
+			s := *session
+			return &s, nil
+		},
+	}
+	recipeRepo := &mockRecipeRepository{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+			if id != recipe.ID {
+				return nil, postgres.ErrRecipeNotFound
+			}
+			return recipe, nil
+		},
+	}
+	return NewCookSessionHandler(sessionRepo, recipeRepo, &mockCookLogRepository{}, &mockPantryRepository{}), sessionRepo, recipe, session
+}
+
+func decodeCookSession(t *testing.T, rr *httptest.ResponseRecorder, status int) model.CookSession {
+	t.Helper()
+	if rr.Code != status {
+		t.Fatalf("expected %d, got %d: %s", status, rr.Code, rr.Body.String())
+	}
+	var session model.CookSession
+	if err := json.Unmarshal(rr.Body.Bytes(), &session); err != nil {
+		t.Fatal(err)
+	}
+	return session
+}
+
+func TestCookSessionHandler_Start(t *testing.T) {
+	user := &model.User{ID: uuid.New()}
+	handler, sessionRepo, recipe, _ := newCookSessionTestHandler(user)
+	params := map[string]string{"recipeID": recipe.ID.String()}
+
+	t.Run("starts scaled on the first step", func(t *testing.T) {
+		sessionRepo.CreateFunc = func(ctx context.Context, recipeID, userID uuid.UUID, servings *int, step int) (*model.CookSession, error) {
+			return &model.CookSession{ID: uuid.New(), RecipeID: recipeID, UserID: userID, Servings: servings, CurrentStep: step}, nil
+		}
+
+		rr := httptest.NewRecorder()
+		handler.Start(rr, shareRequest("POST", "/", `{"servings":4}`, user, params))
+
+		session := decodeCookSession(t, rr, http.StatusCreated)
+		if session.CurrentStep != 1 || session.Servings == nil || *session.Servings != 4 {
+			t.Errorf("unexpected session %+v", session)
+		}
+		if session.Recipe == nil || *session.Recipe.Ingredients[0].Quantity != 800 {
+			t.Errorf("recipe should be scaled to 4 servings, got %+v", session.Recipe)
+		}
+	})
+
+	t.Run("without a body", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Start(rr, shareRequest("POST", "/", "", user, params))
+
+		session := decodeCookSession(t, rr, http.StatusCreated)
+		if session.Servings != nil || *session.Recipe.Ingredients[0].Quantity != 400 {
+			t.Errorf("recipe should be cooked as written, got %+v", session)
+		}
+	})
+
+	t.Run("already cooking", func(t *testing.T) {
+		sessionRepo.CreateFunc = func(ctx context.Context, recipeID, userID uuid.UUID, servings *int, step int) (*model.CookSession, error) {
+			return nil, postgres.ErrCookSessionActive
+		}
+
+		rr := httptest.NewRecorder()
+		handler.Start(rr, shareRequest("POST", "/", `{}`, user, params))
+		if rr.Code != http.StatusConflict {
+			t.Errorf("expected 409, got %d", rr.Code)
+		}
+	})
+
+	t.Run("invalid servings", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Start(rr, shareRequest("POST", "/", `{"servings":0}`, user, params))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("someone else's recipe", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Start(rr, shareRequest("POST", "/", `{}`, &model.User{ID: uuid.New()}, params))
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+}
+
+func TestCookSessionHandler_Update(t *testing.T) {
+	user := &model.User{ID: uuid.New()}
+	handler, sessionRepo, _, session := newCookSessionTestHandler(user)
+	params := map[string]string{"id": session.ID.String()}
+	sessionRepo.SetStepFunc = func(ctx context.Context, id, userID uuid.UUID, step int) (*model.CookSession, error) {
+		s := *session
+		s.CurrentStep = step
+		return &s, nil
+	}
+
+	rr := httptest.NewRecorder()
+	handler.Update(rr, shareRequest("PATCH", "/", `{"currentStep":2}`, user, params))
+	if got := decodeCookSession(t, rr, http.StatusOK); got.CurrentStep != 2 {
+		t.Errorf("currentStep = %d, want 2", got.CurrentStep)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.Update(rr, shareRequest("PATCH", "/", `{"currentStep":3}`, user, params))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a missing step, got %d", rr.Code)
+	}
+
+	finished := time.Now()
+	session.FinishedAt = &finished
+	defer func() { session.FinishedAt = nil }()
+	rr = httptest.NewRecorder()
+	handler.Update(rr, shareRequest("PATCH", "/", `{"currentStep":2}`, user, params))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("expected 409 for a finished session, got %d", rr.Code)
+	}
+}
+
+func TestCookSessionHandler_StartTimer(t *testing.T) {
+	user := &model.User{ID: uuid.New()}
+	handler, sessionRepo, _, session := newCookSessionTestHandler(user)
+	params := map[string]string{"id": session.ID.String()}
+
+	var added model.CookTimer
+	sessionRepo.AddTimerFunc = func(ctx context.Context, id, userID uuid.UUID, timer model.CookTimer) (*model.CookSession, error) {
+		added = timer
+		s := *session
+		s.Timers = []model.CookTimer{timer}
+		return &s, nil
+	}
+
+	t.Run("timer from a step", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.StartTimer(rr, shareRequest("POST", "/", `{"stepNumber":2}`, user, params))
+
+		got := decodeCookSession(t, rr, http.StatusCreated)
+		if added.Name != "Step 2: simmer" || added.DurationSeconds != 900 {
+			t.Errorf("unexpected timer %+v", added)
+		}
+		if added.EndsAt.Sub(added.StartedAt) != 15*time.Minute {
+			t.Errorf("endsAt should be 15 minutes after start, got %v", added.EndsAt.Sub(added.StartedAt))
+		}
+		if r := got.Timers[0].RemainingSeconds; r < 899 || r > 900 {
+			t.Errorf("remainingSeconds = %d", r)
+		}
+	})
+
+	t.Run("named timer", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.StartTimer(rr, shareRequest("POST", "/", `{"name":" Bread ","durationSeconds":60}`, user, params))
+		decodeCookSession(t, rr, http.StatusCreated)
+		if added.Name != "Bread" || added.StepNumber != nil {
+			t.Errorf("unexpected timer %+v", added)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"step without duration": `{"stepNumber":1}`,
+			"missing step":          `{"stepNumber":9}`,
+			"no name":               `{"durationSeconds":60}`,
+			"no duration":           `{"name":"Bread"}`,
+			"too long":              `{"name":"Bread","durationSeconds":86401}`,
+		} {
+			rr := httptest.NewRecorder()
+			handler.StartTimer(rr, shareRequest("POST", "/", body, user, params))
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", name, rr.Code)
+			}
+		}
+	})
+}
+
+func TestCookSessionHandler_Finish(t *testing.T) {
+	user := &model.User{ID: uuid.New()}
+	handler, sessionRepo, _, session := newCookSessionTestHandler(user)
+	params := map[string]string{"id": session.ID.String()}
+	logID := uuid.New()
+
+	var logged *model.CookLogInput
+	sessionRepo.FinishFunc = func(ctx context.Context, id, userID uuid.UUID, log *model.CookLogInput) (*model.CookSession, error) {
+		logged = log
+		s := *session
+		now := time.Now()
+		s.FinishedAt, s.CookLogID = &now, &logID
+		return &s, nil
+	}
+	handler.cookLogRepo = &mockCookLogRepository{
+		GetFunc: func(ctx context.Context, id, userID uuid.UUID) (*model.CookLog, error) {
+			return &model.CookLog{ID: id, Servings: logged.Servings, Rating: logged.Rating}, nil
+		},
+	}
+
+	tomatoes, cream := uuid.New(), uuid.New()
+	updated := map[uuid.UUID]float64{}
+	var deleted []uuid.UUID
+	pantry := &mockPantryRepository{
+		ListAllFunc: func(ctx context.Context, userID uuid.UUID) ([]model.PantryItem, error) {
+			return []model.PantryItem{
+				{ID: tomatoes, Name: "Tomato", Category: "produce", Quantity: ptr(1.0), Unit: ptr("kg")},
+				{ID: cream, Name: "cream", Category: "dairy", Quantity: ptr(200.0), Unit: ptr("ml")},
+			}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id, userID uuid.UUID, input *model.PantryItemInput) (*model.PantryItem, error) {
+			updated[id] = *input.Quantity
+			return &model.PantryItem{ID: id}, nil
+		},
+		DeleteFunc: func(ctx context.Context, id, userID uuid.UUID) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+	}
+	handler.pantryRepo = pantry
+
+	t.Run("logs the cook and deducts the pantry", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Finish(rr, shareRequest("POST", "/", `{"rating":4,"deductPantry":true}`, user, params))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var result model.CookSessionFinishResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+
+		if logged.Servings == nil || *logged.Servings != 4 || *logged.Rating != 4 {
+			t.Errorf("cook should be logged with the session's servings, got %+v", logged)
+		}
+		if result.CookLog == nil || result.CookLog.ID != logID || result.Session.FinishedAt == nil {
+			t.Errorf("unexpected result %+v", result)
+		}
+		// 800 g of tomatoes for 4 servings; the optional cream is left alone
+		if len(updated) != 1 || updated[tomatoes] != 0.2 || len(deleted) != 0 {
+			t.Errorf("pantry updates %v, deletes %v", updated, deleted)
+		}
+		if len(result.Pantry) != 1 || result.Pantry[0].Used != 0.8 {
+			t.Errorf("pantry deductions %+v", result.Pantry)
+		}
+	})
+
+	t.Run("without deducting", func(t *testing.T) {
+		clear(updated)
+		rr := httptest.NewRecorder()
+		handler.Finish(rr, shareRequest("POST", "/", "", user, params))
+		if rr.Code != http.StatusOK || len(updated) != 0 {
+			t.Errorf("expected 200 and no pantry changes, got %d and %v", rr.Code, updated)
+		}
+	})
+
+	t.Run("pantry failure still finishes", func(t *testing.T) {
+		update := pantry.UpdateFunc
+		defer func() { pantry.UpdateFunc = update }()
+		pantry.UpdateFunc = func(ctx context.Context, id, userID uuid.UUID, input *model.PantryItemInput) (*model.PantryItem, error) {
+			return nil, errors.New("connection reset")
+		}
+
+		rr := httptest.NewRecorder()
+		handler.Finish(rr, shareRequest("POST", "/", `{"deductPantry":true}`, user, params))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var result model.CookSessionFinishResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		if result.PantryError == "" || len(result.Pantry) != 0 || result.CookLog == nil {
+			t.Errorf("expected the cook logged and the pantry failure reported, got %+v", result)
+		}
+	})
+
+	t.Run("finished on another device", func(t *testing.T) {
+		sessionRepo.FinishFunc = func(ctx context.Context, id, userID uuid.UUID, log *model.CookLogInput) (*model.CookSession, error) {
+			return nil, model.ErrNotFound
+		}
+		rr := httptest.NewRecorder()
+		handler.Finish(rr, shareRequest("POST", "/", `{}`, user, params))
+		if rr.Code != http.StatusConflict {
+			t.Errorf("expected 409, got %d", rr.Code)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
 	"github.com/dishflow/backend/internal/service/video"
 	"github.com/google/uuid"
 )
@@ -30,20 +31,111 @@ type RecipeRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Recipe, error)
 	GetBySourceRecipeID(ctx context.Context, userID, sourceRecipeID uuid.UUID) (*model.Recipe, error)
 	GetBySourceURL(ctx context.Context, userID uuid.UUID, sourceURL string) (*model.Recipe, error)
-	ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.Recipe, int, error)
-	ListPublic(ctx context.Context, limit, offset int) ([]*model.Recipe, int, error)
-	ListFeatured(ctx context.Context, limit, offset int) ([]*model.Recipe, int, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error)
+	Facets(ctx context.Context, userID uuid.UUID, filter model.RecipeFilter) (*model.RecipeFacets, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int, error)
+	ListByCollection(ctx context.Context, collectionID, userID uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error)
+	ListPublic(ctx context.Context, page pagination.Page) ([]*model.Recipe, int, string, error)
+	ListFeatured(ctx context.Context, page pagination.Page) ([]*model.Recipe, int, string, error)
 	ListForRecommendations(ctx context.Context, userID uuid.UUID) ([]*model.Recipe, error)
-	Search(ctx context.Context, userID uuid.UUID, query string, limit int) ([]*model.Recipe, error)
-	SearchPublic(ctx context.Context, query string, limit int) ([]*model.Recipe, error)
+	Search(ctx context.Context, userID uuid.UUID, query, lang string, limit int) ([]*model.Recipe, error)
+	SearchPublic(ctx context.Context, query, lang string, limit int) ([]*model.Recipe, error)
+	SearchByIngredients(ctx context.Context, userID uuid.UUID, search model.IngredientSearch) ([]*model.IngredientSearchResult, error)
 	Update(ctx context.Context, recipe *model.Recipe) error
 	SoftDelete(ctx context.Context, id uuid.UUID) error
 	SetFavorite(ctx context.Context, id uuid.UUID, isFavorite bool) error
+	ListRevisions(ctx context.Context, recipeID uuid.UUID) ([]model.RecipeRevision, error)
+	GetRevision(ctx context.Context, recipeID, revisionID uuid.UUID) (*model.RecipeRevision, error)
+	Restore(ctx context.Context, recipe *model.Recipe, fromRevisionID uuid.UUID) error
+}
+
+// CollectionRepository defines the interface for recipe collection persistence
+type CollectionRepository interface {
+	List(ctx context.Context, userID uuid.UUID) ([]*model.Collection, error)
+	Get(ctx context.Context, id, userID uuid.UUID) (*model.Collection, error)
+	Create(ctx context.Context, userID uuid.UUID, input *model.CollectionInput) (*model.Collection, error)
+	Update(ctx context.Context, id, userID uuid.UUID, input *model.CollectionInput) (*model.Collection, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	Reorder(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+	AddRecipes(ctx context.Context, id, userID uuid.UUID, recipeIDs []uuid.UUID) (*model.Collection, error)
+	RemoveRecipe(ctx context.Context, id, userID, recipeID uuid.UUID) error
+	ReorderRecipes(ctx context.Context, id, userID uuid.UUID, recipeIDs []uuid.UUID) (*model.Collection, error)
+}
+
+// CookLogRepository defines the interface for cook log persistence
+type CookLogRepository interface {
+	Get(ctx context.Context, id, userID uuid.UUID) (*model.CookLog, error)
+	Create(ctx context.Context, recipeID, userID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error)
+	Update(ctx context.Context, id, userID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error)
+	AddPhoto(ctx context.Context, id, userID uuid.UUID, url string) (*model.CookLog, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	ListByRecipe(ctx context.Context, recipeID, userID uuid.UUID, page pagination.Page) ([]*model.CookLog, string, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*model.CookLog, string, error)
+}
+
+// CookSessionRepository defines the interface for cook session persistence
+type CookSessionRepository interface {
+	Create(ctx context.Context, recipeID, userID uuid.UUID, servings *int, step int) (*model.CookSession, error)
+	Get(ctx context.Context, id, userID uuid.UUID) (*model.CookSession, error)
+	ListActive(ctx context.Context, userID uuid.UUID) ([]*model.CookSession, error)
+	SetStep(ctx context.Context, id, userID uuid.UUID, step int) (*model.CookSession, error)
+	AddTimer(ctx context.Context, id, userID uuid.UUID, timer model.CookTimer) (*model.CookSession, error)
+	RemoveTimer(ctx context.Context, id, userID, timerID uuid.UUID) (*model.CookSession, error)
+	Finish(ctx context.Context, id, userID uuid.UUID, log *model.CookLogInput) (*model.CookSession, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
+
+// AnnotationRepository defines the interface for users' notes on recipe
+// ingredients and steps
+type AnnotationRepository interface {
+	ListByRecipe(ctx context.Context, recipeID, userID uuid.UUID) ([]model.RecipeAnnotation, error)
+	Set(ctx context.Context, annotation *model.RecipeAnnotation) (*model.RecipeAnnotation, error)
+	Delete(ctx context.Context, id, recipeID, userID uuid.UUID) error
+}
+
+// DuplicateRepository defines the interface for duplicate recipe
+// suggestions and merging
+type DuplicateRepository interface {
+	ListGroups(ctx context.Context, userID uuid.UUID) ([]*model.DuplicateGroup, error)
+	Dismiss(ctx context.Context, id, userID uuid.UUID) error
+	Merge(ctx context.Context, userID, keepID uuid.UUID, mergeIDs []uuid.UUID) (*model.RecipeMergeResult, error)
+}
+
+// TagRepository defines the interface for tags across a recipe library
+type TagRepository interface {
+	List(ctx context.Context, userID uuid.UUID) ([]model.TagCount, error)
+	Replace(ctx context.Context, userID uuid.UUID, replacements map[string]string) (int, error)
+}
+
+// NutritionCalculator computes recipe nutrition from the food database
+type NutritionCalculator interface {
+	Calculate(ctx context.Context, recipe *model.Recipe) (*model.NutritionReport, error)
+}
+
+// ShareRepository defines the interface for recipe share persistence
+type ShareRepository interface {
+	Create(ctx context.Context, share *model.RecipeShare) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.RecipeShare, error)
+	ListInbox(ctx context.Context, userID uuid.UUID, email string, status model.ShareStatus) ([]*model.RecipeShare, error)
+	ListSent(ctx context.Context, ownerID uuid.UUID, recipeID *uuid.UUID) ([]*model.RecipeShare, error)
+	Accept(ctx context.Context, id, recipientID uuid.UUID) error
+	SetAcceptedRecipe(ctx context.Context, id, recipeID uuid.UUID) error
+	Reopen(ctx context.Context, id uuid.UUID) error
+	Decline(ctx context.Context, id, recipientID uuid.UUID) error
+	Delete(ctx context.Context, id, ownerID uuid.UUID) error
+}
+
+// ShareLinkRepository defines the interface for public share link persistence
+type ShareLinkRepository interface {
+	GetActive(ctx context.Context, recipeID uuid.UUID) (*model.RecipeShareLink, error)
+	Create(ctx context.Context, recipeID, userID uuid.UUID) (*model.RecipeShareLink, error)
+	Revoke(ctx context.Context, recipeID uuid.UUID) error
+	RecordView(ctx context.Context, token string) (*model.RecipeShareLink, error)
 }
 
 // PantryRepository defines the interface for pantry persistence
 type PantryRepository interface {
-	List(ctx context.Context, userID uuid.UUID, category *string, limit, offset int) ([]*model.PantryItem, int, error)
+	List(ctx context.Context, userID uuid.UUID, category *string, page pagination.Page) ([]*model.PantryItem, int, string, error)
 	ListAll(ctx context.Context, userID uuid.UUID) ([]model.PantryItem, error)
 	Get(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.PantryItem, error)
 	Create(ctx context.Context, userID uuid.UUID, input *model.PantryItemInput) (*model.PantryItem, error)
@@ -53,7 +145,7 @@ type PantryRepository interface {
 
 // ShoppingRepository defines the interface for shopping list persistence
 type ShoppingRepository interface {
-	ListLists(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]*model.ShoppingList, error)
+	ListLists(ctx context.Context, userID uuid.UUID, includeArchived bool, page pagination.Page) ([]*model.ShoppingList, string, error)
 	GetList(ctx context.Context, id, userID uuid.UUID) (*model.ShoppingList, error)
 	GetListWithItems(ctx context.Context, id, userID uuid.UUID) (*model.ShoppingListWithItems, error)
 	CreateList(ctx context.Context, userID uuid.UUID, input *model.ShoppingListInput) (*model.ShoppingList, error)
@@ -86,9 +178,13 @@ type ShoppingRepository interface {
 type JobRepository interface {
 	Create(ctx context.Context, job *model.VideoJob) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.VideoJob, error)
-	ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.VideoJob, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, filter model.JobListFilter, page pagination.Page) ([]*model.VideoJob, string, error)
+	CountByStatus(ctx context.Context, userID uuid.UUID, filter model.JobListFilter) (map[model.JobStatus]int, error)
 	UpdateProgress(ctx context.Context, id uuid.UUID, status model.JobStatus, progress int, message string) error
+	UpdatePartialRecipe(ctx context.Context, id uuid.UUID, partial *model.PartialRecipe) error
 	MarkCompleted(ctx context.Context, id uuid.UUID, resultRecipeID uuid.UUID) error
+	UpdateImportResult(ctx context.Context, id uuid.UUID, progress int, message string, result *model.ImportResult) error
+	MarkImportCompleted(ctx context.Context, id uuid.UUID, result *model.ImportResult) error
 	MarkFailed(ctx context.Context, id uuid.UUID, errorCode, errorMessage string) error
 	MarkCancelled(ctx context.Context, id uuid.UUID) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
@@ -97,9 +193,16 @@ type JobRepository interface {
 	CountUsedThisMonth(ctx context.Context, userID uuid.UUID) (int, error)
 }
 
+// ImageFetcher loads recipe thumbnails for exports
+type ImageFetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, string, error)
+}
+
 // ThumbnailDownloader downloads remote thumbnails to local disk.
 type ThumbnailDownloader interface {
 	Download(ctx context.Context, url string) (string, error)
+	// Save stores image data, e.g. a photo embedded in an import file
+	Save(data []byte, ext string) (string, error)
 }
 
 // VideoDownloader defines the interface for video downloading
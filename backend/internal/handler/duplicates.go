@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
+)
+
+// DuplicateHandler handles duplicate recipe suggestions and merging
+type DuplicateHandler struct {
+	duplicateRepo DuplicateRepository
+	recipeRepo    RecipeRepository
+}
+
+// NewDuplicateHandler creates a new duplicate handler
+func NewDuplicateHandler(duplicateRepo DuplicateRepository, recipeRepo RecipeRepository) *DuplicateHandler {
+	return &DuplicateHandler{
+		duplicateRepo: duplicateRepo,
+		recipeRepo:    recipeRepo,
+	}
+}
+
+// List handles GET /api/v1/recipes/duplicates
+// @Summary List suggested duplicates
+// @Description Get groups of your recipes that look like the same dish, judged by title similarity and ingredient overlap. Suggestions are refreshed in the background after your recipes change.
+// @Tags Recipes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SwaggerDuplicateGroupListResponse "Duplicate groups, most alike first"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Router /recipes/duplicates [get]
+func (h *DuplicateHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	groups, err := h.duplicateRepo.ListGroups(r.Context(), user.ID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"items": groups,
+	})
+}
+
+// Dismiss handles POST /api/v1/recipes/duplicates/{groupID}/dismiss
+// @Summary Dismiss a duplicate suggestion
+// @Description Mark a suggested group as not duplicates; the same recipes won't be suggested together again
+// @Tags Recipes
+// @Security BearerAuth
+// @Param groupID path string true "Duplicate group UUID"
+// @Success 204 "Dismissed"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid group ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Suggestion not found"
+// @Router /recipes/duplicates/{groupID}/dismiss [post]
+func (h *DuplicateHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "groupID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid group ID")
+		return
+	}
+
+	if err := h.duplicateRepo.Dismiss(r.Context(), id, user.ID); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			response.NotFound(w, "Suggestion")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Merge handles POST /api/v1/recipes/merge
+// @Summary Merge duplicate recipes
+// @Description Keep one recipe and fold duplicates into it: it becomes a favorite if any duplicate was, and the duplicates' meal plan entries, cook logs and collection memberships move to it. The duplicates are then deleted.
+// @Tags Recipes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body SwaggerRecipeMergeInput true "Recipe to keep and recipes to merge into it"
+// @Success 200 {object} SwaggerRecipeMergeResult "Kept recipe and what was moved to it"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid input"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Router /recipes/merge [post]
+func (h *DuplicateHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	var input model.RecipeMergeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	result, err := h.duplicateRepo.Merge(r.Context(), user.ID, input.KeepID, input.MergeIDs)
+	if err != nil {
+		if errors.Is(err, postgres.ErrRecipeNotFound) {
+			response.NotFound(w, "Recipe")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	result.Recipe, err = h.recipeRepo.GetByID(r.Context(), input.KeepID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, result)
+}
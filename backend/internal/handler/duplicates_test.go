@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/repository/postgres"
+)
+
+func TestDuplicateHandler_Merge(t *testing.T) {
+	mockDuplicateRepo := &mockDuplicateRepository{}
+	mockRecipeRepo := &mockRecipeRepository{}
+	handler := NewDuplicateHandler(mockDuplicateRepo, mockRecipeRepo)
+
+	user := &model.User{ID: uuid.New()}
+	keepID, dupID := uuid.New(), uuid.New()
+	mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return &model.Recipe{ID: id, UserID: user.ID, Title: "Carbonara", IsFavorite: true}, nil
+	}
+
+	t.Run("merges into the kept recipe", func(t *testing.T) {
+		mockDuplicateRepo.MergeFunc = func(ctx context.Context, userID, keep uuid.UUID, mergeIDs []uuid.UUID) (*model.RecipeMergeResult, error) {
+			if userID != user.ID || keep != keepID || len(mergeIDs) != 1 || mergeIDs[0] != dupID {
+				t.Errorf("unexpected merge %s %s %v", userID, keep, mergeIDs)
+			}
+			return &model.RecipeMergeResult{Merged: 1, MovedCookLogs: 2}, nil
+		}
+
+		body := fmt.Sprintf(`{"keepId":%q,"mergeIds":[%q]}`, keepID, dupID)
+		rr := httptest.NewRecorder()
+		handler.Merge(rr, shareRequest("POST", "/", body, user, nil))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var result model.RecipeMergeResult
+		json.Unmarshal(rr.Body.Bytes(), &result)
+		if result.Recipe == nil || result.Recipe.ID != keepID || result.MovedCookLogs != 2 {
+			t.Errorf("unexpected result %+v", result)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"no keepId":    fmt.Sprintf(`{"mergeIds":[%q]}`, dupID),
+			"no mergeIds":  fmt.Sprintf(`{"keepId":%q,"mergeIds":[]}`, keepID),
+			"keeps itself": fmt.Sprintf(`{"keepId":%q,"mergeIds":[%q]}`, keepID, keepID),
+			"repeated id":  fmt.Sprintf(`{"keepId":%q,"mergeIds":[%q,%q]}`, keepID, dupID, dupID),
+		} {
+			rr := httptest.NewRecorder()
+			handler.Merge(rr, shareRequest("POST", "/", body, user, nil))
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", name, rr.Code)
+			}
+		}
+	})
+
+	t.Run("recipe not owned", func(t *testing.T) {
+		mockDuplicateRepo.MergeFunc = func(ctx context.Context, userID, keep uuid.UUID, mergeIDs []uuid.UUID) (*model.RecipeMergeResult, error) {
+			return nil, postgres.ErrRecipeNotFound
+		}
+		body := fmt.Sprintf(`{"keepId":%q,"mergeIds":[%q]}`, keepID, dupID)
+		rr := httptest.NewRecorder()
+		handler.Merge(rr, shareRequest("POST", "/", body, user, nil))
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+}
+
+func TestDuplicateHandler_Dismiss(t *testing.T) {
+	mockDuplicateRepo := &mockDuplicateRepository{}
+	handler := NewDuplicateHandler(mockDuplicateRepo, &mockRecipeRepository{})
+	user := &model.User{ID: uuid.New()}
+	params := map[string]string{"groupID": uuid.NewString()}
+
+	mockDuplicateRepo.DismissFunc = func(ctx context.Context, id, userID uuid.UUID) error {
+		return model.ErrNotFound
+	}
+	rr := httptest.NewRecorder()
+	handler.Dismiss(rr, shareRequest("POST", "/", "", user, params))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rr.Code)
+	}
+
+	mockDuplicateRepo.DismissFunc = func(ctx context.Context, id, userID uuid.UUID) error { return nil }
+	rr = httptest.NewRecorder()
+	handler.Dismiss(rr, shareRequest("POST", "/", "", user, params))
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rr.Code)
+	}
+}
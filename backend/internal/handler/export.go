@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
+	"github.com/dishflow/backend/internal/service/export"
+)
+
+// exportPageSize is how many recipes a library export loads per query
+const exportPageSize = 100
+
+// ExportHandler exports recipes to standard file formats
+type ExportHandler struct {
+	recipeRepo RecipeRepository
+	images     ImageFetcher
+	logger     *slog.Logger
+}
+
+// NewExportHandler creates a new export handler. images may be nil, in
+// which case exports carry thumbnail URLs but no image files.
+func NewExportHandler(recipeRepo RecipeRepository, images ImageFetcher, logger *slog.Logger) *ExportHandler {
+	return &ExportHandler{
+		recipeRepo: recipeRepo,
+		images:     images,
+		logger:     logger,
+	}
+}
+
+// ExportRecipe handles GET /api/v1/recipes/{recipeID}/export
+// @Summary Export a recipe
+// @Description Download a recipe as schema.org JSON-LD, Cooklang, Markdown or a Paprika archive. Ingredient sections, nutrition and the thumbnail are included.
+// @Tags Export
+// @Produce application/ld+json,text/plain,text/markdown,application/zip
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Param format query string true "Export format" Enums(jsonld, cooklang, markdown, paprika)
+// @Success 200 {file} file "Exported recipe"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid format"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Router /recipes/{recipeID}/export [get]
+func (h *ExportHandler) ExportRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	format, ok := h.format(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid recipe ID")
+		return
+	}
+
+	recipe, err := h.recipeRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrRecipeNotFound) {
+			response.NotFound(w, "Recipe")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	if recipe.UserID != user.ID && !recipe.IsPublic && !recipe.IsFeatured {
+		response.Forbidden(w, "Access denied")
+		return
+	}
+
+	var image *export.Image
+	if format == export.FormatPaprika {
+		image = h.image(ctx, recipe)
+	}
+
+	file, err := export.Single(format, recipe, image)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", attachment(file.Name))
+	w.WriteHeader(http.StatusOK)
+	w.Write(file.Data)
+}
+
+// ExportLibrary handles GET /api/v1/recipes/export
+// @Summary Export all recipes
+// @Description Download every recipe you own as a zip streamed as it is built. Paprika exports are a .paprikarecipes archive; other formats hold one file per recipe with its thumbnail alongside.
+// @Tags Export
+// @Produce application/zip
+// @Security BearerAuth
+// @Param format query string true "Export format" Enums(jsonld, cooklang, markdown, paprika)
+// @Success 200 {file} file "Zip archive"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid format"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Router /recipes/export [get]
+func (h *ExportHandler) ExportLibrary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	format, ok := h.format(w, r)
+	if !ok {
+		return
+	}
+
+	// Fail before any bytes are sent if the library can't be read at all
+	page, _, next, err := h.recipeRepo.ListByUser(ctx, user.ID, model.RecipeFilter{}, pagination.Page{Limit: exportPageSize})
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", attachment(export.ArchiveName(format)))
+	w.WriteHeader(http.StatusOK)
+
+	archive := export.NewArchive(w, format)
+	for {
+		for _, summary := range page {
+			recipe, err := h.recipeRepo.GetByID(ctx, summary.ID)
+			if err != nil {
+				if errors.Is(err, postgres.ErrRecipeNotFound) {
+					continue // deleted mid-export
+				}
+				h.logger.Error("Recipe export aborted", "user_id", user.ID, "recipe_id", summary.ID, "error", err)
+				return
+			}
+			if err := archive.Add(recipe, h.image(ctx, recipe)); err != nil {
+				h.logger.Error("Recipe export aborted", "user_id", user.ID, "recipe_id", recipe.ID, "error", err)
+				return
+			}
+		}
+
+		if next == "" {
+			break
+		}
+		page, _, next, err = h.recipeRepo.ListByUser(ctx, user.ID, model.RecipeFilter{}, pagination.Page{Limit: exportPageSize, Cursor: next})
+		if err != nil {
+			h.logger.Error("Recipe export aborted", "user_id", user.ID, "error", err)
+			return
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		h.logger.Error("Recipe export aborted", "user_id", user.ID, "error", err)
+	}
+}
+
+// format reads the required format query parameter
+func (h *ExportHandler) format(w http.ResponseWriter, r *http.Request) (export.Format, bool) {
+	format, ok := export.ParseFormat(r.URL.Query().Get("format"))
+	if !ok {
+		response.ValidationFailed(w, "format", "must be one of jsonld, cooklang, markdown, paprika")
+		return "", false
+	}
+	return format, true
+}
+
+// image loads the recipe's thumbnail. Exports go ahead without it when it
+// can't be fetched.
+func (h *ExportHandler) image(ctx context.Context, recipe *model.Recipe) *export.Image {
+	if h.images == nil || recipe.ThumbnailURL == nil || *recipe.ThumbnailURL == "" {
+		return nil
+	}
+	data, ext, err := h.images.Fetch(ctx, *recipe.ThumbnailURL)
+	if err != nil {
+		h.logger.Warn("Skipping thumbnail in export", "recipe_id", recipe.ID, "error", err)
+		return nil
+	}
+	return &export.Image{Data: data, Ext: ext}
+}
+
+func attachment(filename string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
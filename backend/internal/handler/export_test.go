@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+func TestExportHandler_ExportRecipe(t *testing.T) {
+	mockRepo := &mockRecipeRepository{}
+	handler := NewExportHandler(mockRepo, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	user := &model.User{ID: uuid.New()}
+	recipeID := uuid.New()
+	params := map[string]string{"recipeID": recipeID.String()}
+
+	mockRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return &model.Recipe{ID: id, UserID: user.ID, Title: "Crème Brûlée"}, nil
+	}
+
+	t.Run("markdown download", func(t *testing.T) {
+		req := shareRequest("GET", "/recipes/x/export?format=md", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.ExportRecipe(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+			t.Errorf("unexpected content type %q", ct)
+		}
+		if cd := rr.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment;") || !strings.Contains(cd, ".md") {
+			t.Errorf("unexpected content disposition %q", cd)
+		}
+		if !strings.HasPrefix(rr.Body.String(), "# Crème Brûlée\n") {
+			t.Errorf("unexpected body %q", rr.Body.String())
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		req := shareRequest("GET", "/recipes/x/export?format=pdf", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.ExportRecipe(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("private recipe of another user", func(t *testing.T) {
+		mockRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+			return &model.Recipe{ID: id, UserID: uuid.New()}, nil
+		}
+
+		req := shareRequest("GET", "/recipes/x/export?format=jsonld", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.ExportRecipe(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+}
+
+func TestExportHandler_ExportLibrary(t *testing.T) {
+	mockRepo := &mockRecipeRepository{}
+	thumb := "https://example.com/a.jpg"
+	images := &mockImageFetcher{FetchFunc: func(ctx context.Context, url string) ([]byte, string, error) {
+		if url != thumb {
+			return nil, "", errors.New("unexpected url")
+		}
+		return []byte("jpeg"), ".jpg", nil
+	}}
+	handler := NewExportHandler(mockRepo, images, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	user := &model.User{ID: uuid.New()}
+
+	// More recipes than fit in one page
+	var library []*model.Recipe
+	for i := 0; i < exportPageSize+1; i++ {
+		library = append(library, &model.Recipe{ID: uuid.New(), UserID: user.ID, Title: "Soup"})
+	}
+	library[0].ThumbnailURL = &thumb
+	byID := map[uuid.UUID]*model.Recipe{}
+	for _, r := range library {
+		byID[r.ID] = r
+	}
+
+	// Cursors are the offset of the next page
+	mockRepo.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+		offset, _ := strconv.Atoi(page.Cursor)
+		end := min(offset+page.Limit, len(library))
+		next := ""
+		if end < len(library) {
+			next = strconv.Itoa(end)
+		}
+		return library[offset:end], len(library), next, nil
+	}
+	mockRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return byID[id], nil
+	}
+
+	req := shareRequest("GET", "/recipes/export?format=cooklang", "", user, nil)
+	rr := httptest.NewRecorder()
+	handler.ExportLibrary(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	body := rr.Body.Bytes()
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("expected a zip: %v", err)
+	}
+	if len(zr.File) != len(library)+1 {
+		t.Fatalf("expected %d files, got %d", len(library)+1, len(zr.File))
+	}
+	if zr.File[0].Name != "Soup.jpg" || zr.File[1].Name != "Soup.cook" || zr.File[2].Name != "Soup (2).cook" {
+		t.Errorf("unexpected files: %s, %s, %s", zr.File[0].Name, zr.File[1].Name, zr.File[2].Name)
+	}
+}
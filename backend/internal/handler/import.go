@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/pkg/units"
+	"github.com/dishflow/backend/internal/service/ai"
+	"github.com/dishflow/backend/internal/service/importer"
+	"github.com/dishflow/backend/internal/service/scheduler"
+)
+
+// maxImportSize caps import uploads, within the router's global body limit
+const maxImportSize = 50 << 20
+
+// Import handles POST /api/v1/recipes/import
+// @Summary Import recipes from another app
+// @Description Upload a library exported from Paprika (.paprikarecipes), Mealie (zip or JSON), Tandoor (zip), Whisk/Samsung Food (schema.org JSON) or a CSV with a header row. Returns an import job; poll it for per-recipe results. Imports don't use extraction quota but stop at the plan's recipe limit, and recipes whose source URL you already saved are skipped.
+// @Tags Recipes
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Export file"
+// @Param format formData string true "File format" Enums(paprika, mealie, tandoor, whisk, csv)
+// @Param enrich formData bool false "Estimate nutrition and dietary info with AI" default(false)
+// @Success 201 {object} SwaggerJobResponse "Import job created"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid or unreadable file"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 402 {object} SwaggerErrorResponse "Recipe limit reached"
+// @Failure 413 {object} SwaggerErrorResponse "File too large"
+// @Router /recipes/import [post]
+func (h *UnifiedExtractionHandler) Import(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.ErrorJSON(w, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE",
+				fmt.Sprintf("Import files are limited to %d MB", maxImportSize>>20), nil)
+			return
+		}
+		response.BadRequest(w, "Failed to parse form: "+err.Error())
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	format, ok := importer.ParseFormat(r.FormValue("format"))
+	if !ok {
+		response.ValidationFailed(w, "format", "must be one of paprika, mealie, tandoor, whisk, csv")
+		return
+	}
+	enrich := r.FormValue("enrich") == "true"
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.ValidationFailed(w, "file", "is required")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		response.BadRequest(w, "Failed to read file")
+		return
+	}
+
+	entries, err := importer.Parse(format, data)
+	if err != nil {
+		response.ValidationFailed(w, "file", err.Error())
+		return
+	}
+
+	maxRecipes := h.maxRecipes(r.Context(), user)
+	if maxRecipes >= 0 {
+		count, err := h.recipeRepo.CountByUser(r.Context(), user.ID)
+		if err != nil {
+			h.logger.Error("Failed to count recipes", "error", err, "user_id", user.ID)
+			response.InternalError(w)
+			return
+		}
+		if count >= maxRecipes {
+			response.PaymentRequired(w, fmt.Sprintf("Recipe limit reached (%d/%d). Upgrade to Pro for unlimited recipes.", count, maxRecipes))
+			return
+		}
+	}
+
+	job := model.NewExtractionJob(user.ID, model.JobTypeImport, header.Filename, "auto", "quick", true, false)
+	job.ImportResult = &model.ImportResult{
+		Format: string(format),
+		Total:  len(entries),
+		Items:  make([]model.ImportItemResult, len(entries)),
+	}
+	for i, e := range entries {
+		job.ImportResult.Items[i] = model.ImportItemResult{Index: i, Title: e.Name, Status: model.ImportStatusPending}
+	}
+
+	if err := h.jobRepo.Create(r.Context(), job); err != nil {
+		h.logger.Error("Failed to create import job", "error", err)
+		response.InternalError(w)
+		return
+	}
+
+	reqLogger := middleware.GetLogger(r.Context())
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	ctx = context.WithValue(ctx, middleware.LoggerKey, reqLogger)
+	h.activeJobs.Store(job.ID, cancel)
+
+	go func() {
+		defer cancel()
+		defer h.activeJobs.Delete(job.ID)
+
+		defer func() {
+			if r := recover(); r != nil {
+				reqLogger.Error("Panic in import job", "error", r, "jobID", job.ID)
+				h.jobRepo.MarkFailed(context.Background(), job.ID, "INTERNAL_ERROR", "An unexpected error occurred")
+			}
+		}()
+
+		// Imports are bulk work and yield to interactive extractions
+		release, err := h.scheduler.Acquire(ctx, scheduler.Request{
+			JobID:    job.ID,
+			UserID:   job.UserID,
+			Lane:     scheduler.LaneLight,
+			Priority: scheduler.PriorityBulk,
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			h.jobRepo.MarkFailed(context.Background(), job.ID, "TIMEOUT", "Job timed out waiting for processing slot")
+			return
+		}
+		defer release()
+
+		h.processImport(ctx, job, entries, enrich, maxRecipes)
+	}()
+
+	response.Created(w, map[string]string{
+		"jobId":  job.ID.String(),
+		"status": string(job.Status),
+	})
+}
+
+// maxRecipes returns the user's recipe limit, or -1 for unlimited
+func (h *UnifiedExtractionHandler) maxRecipes(ctx context.Context, user *model.User) int {
+	if model.IsAdminEmail(user.Email, h.adminEmails) || model.IsInspiratorEmail(user.Email, h.inspiratorEmails) {
+		return -1
+	}
+
+	entitlement := "free"
+	if h.userRepo != nil {
+		sub, err := h.userRepo.GetSubscription(ctx, user.ID)
+		if err != nil {
+			h.logger.Warn("Failed to get subscription for limit check", "error", err, "user_id", user.ID)
+		}
+		if sub != nil {
+			entitlement = sub.Entitlement
+		}
+	}
+	limits, ok := model.TierLimits[entitlement]
+	if !ok {
+		limits = model.TierLimits["free"]
+	}
+	return limits.MaxRecipes
+}
+
+// processImport saves each parsed recipe, recording its outcome on the job
+// as it goes. maxRecipes is the user's recipe limit (-1 for none); recipes
+// past it are skipped.
+func (h *UnifiedExtractionHandler) processImport(ctx context.Context, job *model.ExtractionJob, entries []importer.Entry, enrich bool, maxRecipes int) {
+	logger := middleware.GetLogger(ctx)
+	result := job.ImportResult
+	logger.Info("ImportStarted", "job_id", job.ID, "user_id", job.UserID, "format", result.Format, "recipes", len(entries))
+
+	// The job may have queued for a while, so count again
+	remaining := -1
+	if maxRecipes >= 0 {
+		count, err := h.recipeRepo.CountByUser(ctx, job.UserID)
+		if err != nil {
+			h.jobRepo.MarkFailed(ctx, job.ID, "INTERNAL_ERROR", "Failed to check recipe limit")
+			return
+		}
+		remaining = max(maxRecipes-count, 0)
+	}
+
+	for i, e := range entries {
+		if ctx.Err() != nil {
+			// CancelJob has already marked the job; a timeout has not
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				h.jobRepo.MarkFailed(context.Background(), job.ID, "TIMEOUT", "Import timed out")
+			}
+			return
+		}
+
+		switch {
+		case e.Err != nil:
+			result.Record(i, model.ImportStatusFailed, nil, e.Err.Error())
+		case remaining == 0:
+			result.Record(i, model.ImportStatusSkipped, nil, "Recipe limit reached")
+		default:
+			recipeID, existing, err := h.importRecipe(ctx, job.UserID, e.Draft, enrich)
+			switch {
+			case err != nil:
+				logger.Warn("Failed to import recipe", "job_id", job.ID, "index", i, "error", err)
+				result.Record(i, model.ImportStatusFailed, nil, "Could not save recipe")
+			case existing:
+				result.Record(i, model.ImportStatusSkipped, &recipeID, "Already in your recipes")
+			default:
+				result.Record(i, model.ImportStatusImported, &recipeID, "")
+				if remaining > 0 {
+					remaining--
+				}
+			}
+		}
+
+		progress := 5 + (i+1)*90/len(entries)
+		message := fmt.Sprintf("Imported %d of %d recipes", result.Imported, result.Total)
+		if err := h.jobRepo.UpdateImportResult(ctx, job.ID, progress, message, result); err != nil {
+			logger.Warn("Failed to update import progress", "error", err, "job_id", job.ID)
+		}
+	}
+
+	if err := h.jobRepo.MarkImportCompleted(ctx, job.ID, result); err != nil {
+		logger.Error("Failed to complete import job", "error", err, "job_id", job.ID)
+		return
+	}
+	logger.Info("ImportCompleted", "job_id", job.ID, "imported", result.Imported, "failed", result.Failed, "skipped", result.Skipped)
+}
+
+// importRecipe saves one imported recipe. existing is true when the user
+// already has a recipe from the same source URL, whose ID is returned.
+func (h *UnifiedExtractionHandler) importRecipe(ctx context.Context, userID uuid.UUID, draft *importer.Draft, enrich bool) (id uuid.UUID, existing bool, err error) {
+	if draft.SourceURL != "" {
+		if found, err := h.recipeRepo.GetBySourceURL(ctx, userID, model.NormalizeURL(draft.SourceURL)); err == nil && found != nil {
+			return found.ID, true, nil
+		}
+	}
+
+	recipe := importer.ToRecipe(draft, userID)
+
+	if h.thumbDownloader != nil {
+		if draft.Image != nil {
+			if url, err := h.thumbDownloader.Save(draft.Image.Data, draft.Image.Ext); err == nil {
+				recipe.ThumbnailURL = &url
+			} else {
+				h.logger.Warn("Failed to save imported photo", "error", err)
+			}
+		} else if draft.ImageURL != "" {
+			if url, err := h.thumbDownloader.Download(ctx, draft.ImageURL); err == nil {
+				recipe.ThumbnailURL = &url
+			} else {
+				h.logger.Warn("Failed to download thumbnail, keeping original URL", "url", draft.ImageURL, "error", err)
+			}
+		}
+	}
+
+	if enrich && h.enricher != nil {
+		enrichment, err := h.enricher.EnrichRecipe(ctx, recipeToEnrichmentInput(recipe))
+		if err != nil {
+			h.logger.Warn("Enrichment failed for imported recipe", "error", err, "title", recipe.Title)
+		}
+		applyEnrichment(recipe, enrichment)
+		h.applyNutrition(ctx, recipe)
+	}
+
+	if err := h.recipeRepo.Create(ctx, recipe); err != nil {
+		return uuid.Nil, false, err
+	}
+	return recipe.ID, false, nil
+}
+
+// recipeToEnrichmentInput converts a parsed recipe to enrichment input
+func recipeToEnrichmentInput(recipe *model.Recipe) *ai.EnrichmentInput {
+	input := &ai.EnrichmentInput{Title: recipe.Title}
+	if recipe.Servings != nil {
+		input.Servings = *recipe.Servings
+	}
+	if recipe.PrepTime != nil {
+		input.PrepTime = *recipe.PrepTime
+	}
+	if recipe.CookTime != nil {
+		input.CookTime = *recipe.CookTime
+	}
+	if recipe.Cuisine != nil {
+		input.Cuisine = *recipe.Cuisine
+	}
+
+	for _, ing := range recipe.Ingredients {
+		var parts []string
+		if ing.Quantity != nil {
+			parts = append(parts, units.FormatFraction(*ing.Quantity))
+		}
+		if ing.Unit != nil {
+			parts = append(parts, *ing.Unit)
+		}
+		line := strings.Join(append(parts, ing.Name), " ")
+		if ing.Notes != nil {
+			line += " (" + *ing.Notes + ")"
+		}
+		input.Ingredients = append(input.Ingredients, line)
+	}
+	for _, step := range recipe.Steps {
+		input.Steps = append(input.Steps, step.Instruction)
+	}
+	return input
+}
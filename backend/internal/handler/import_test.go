@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/service/ai"
+	"github.com/dishflow/backend/internal/service/importer"
+	"github.com/dishflow/backend/internal/service/scheduler"
+)
+
+type stubEnricher struct {
+	result *ai.EnrichmentResult
+}
+
+func (s *stubEnricher) EnrichRecipe(ctx context.Context, input *ai.EnrichmentInput) (*ai.EnrichmentResult, error) {
+	return s.result, nil
+}
+
+func importRequest(t *testing.T, user *model.User, fields map[string]string, file string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for k, v := range fields {
+		mw.WriteField(k, v)
+	}
+	if file != "" {
+		fw, err := mw.CreateFormFile("file", "recipes.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(fw, file)
+	}
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/recipes/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if user != nil {
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, user))
+	}
+	return req
+}
+
+const importCSV = "title,ingredients,directions,source\n" +
+	"Toast,1 slice bread,Toast it,\n" +
+	"Soup,1 l stock,Simmer,https://example.com/soup\n" +
+	"Empty,,,\n" +
+	"Salad,1 head lettuce,Toss,\n"
+
+func TestImport_Validation(t *testing.T) {
+	user := &model.User{ID: uuid.New()}
+	recipes := &mockRecipeRepository{}
+	h := &UnifiedExtractionHandler{
+		jobRepo:    &mockJobRepository{},
+		recipeRepo: recipes,
+		userRepo:   &mockUserRepository{},
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	tests := []struct {
+		name   string
+		user   *model.User
+		fields map[string]string
+		file   string
+		count  int
+		want   int
+	}{
+		{"unauthenticated", nil, map[string]string{"format": "csv"}, importCSV, 0, http.StatusUnauthorized},
+		{"unknown format", user, map[string]string{"format": "evernote"}, importCSV, 0, http.StatusBadRequest},
+		{"missing file", user, map[string]string{"format": "csv"}, "", 0, http.StatusBadRequest},
+		{"unreadable file", user, map[string]string{"format": "paprika"}, importCSV, 0, http.StatusBadRequest},
+		{"recipe limit reached", user, map[string]string{"format": "csv"}, importCSV, 25, http.StatusPaymentRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipes.CountByUserFunc = func(ctx context.Context, userID uuid.UUID) (int, error) {
+				return tt.count, nil
+			}
+			rr := httptest.NewRecorder()
+			h.Import(rr, importRequest(t, tt.user, tt.fields, tt.file))
+			if rr.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestImport_Run(t *testing.T) {
+	user := &model.User{ID: uuid.New()}
+	existingID := uuid.New()
+
+	var mu sync.Mutex
+	var created []*model.Recipe
+	recipes := &mockRecipeRepository{
+		CountByUserFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
+			return 23, nil // free plan allows 25, so two more
+		},
+		GetBySourceURLFunc: func(ctx context.Context, userID uuid.UUID, sourceURL string) (*model.Recipe, error) {
+			if sourceURL == model.NormalizeURL("https://example.com/soup") {
+				return &model.Recipe{ID: existingID}, nil
+			}
+			return nil, nil
+		},
+		CreateFunc: func(ctx context.Context, recipe *model.Recipe) error {
+			mu.Lock()
+			defer mu.Unlock()
+			created = append(created, recipe)
+			return nil
+		},
+	}
+
+	var job *model.ExtractionJob
+	done := make(chan *model.ImportResult, 1)
+	jobs := &mockJobRepository{
+		CreateFunc: func(ctx context.Context, j *model.VideoJob) error {
+			job = j
+			return nil
+		},
+		MarkImportCompletedFunc: func(ctx context.Context, id uuid.UUID, result *model.ImportResult) error {
+			done <- result
+			return nil
+		},
+	}
+
+	h := &UnifiedExtractionHandler{
+		jobRepo:    jobs,
+		recipeRepo: recipes,
+		userRepo:   &mockUserRepository{},
+		enricher: &stubEnricher{result: &ai.EnrichmentResult{
+			Nutrition: &ai.NutritionEstimate{PerServing: ai.NutritionValues{Calories: 300}, Confidence: 0.9},
+		}},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		scheduler: scheduler.New(scheduler.Config{
+			Lanes: []scheduler.LaneConfig{{Name: scheduler.LaneLight, Slots: 1}},
+		}),
+	}
+
+	rr := httptest.NewRecorder()
+	h.Import(rr, importRequest(t, user, map[string]string{"format": "csv", "enrich": "true"}, importCSV))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if job == nil || job.JobType != model.JobTypeImport || job.ImportResult == nil || job.ImportResult.Total != 4 {
+		t.Fatalf("unexpected job %+v", job)
+	}
+
+	var result *model.ImportResult
+	select {
+	case result = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("import did not complete")
+	}
+
+	want := []model.ImportStatus{
+		model.ImportStatusImported, // Toast
+		model.ImportStatusSkipped,  // Soup, already saved
+		model.ImportStatusFailed,   // Empty
+		model.ImportStatusImported, // Salad
+	}
+	for i, status := range want {
+		if result.Items[i].Status != status {
+			t.Errorf("item %d: expected %s, got %+v", i, status, result.Items[i])
+		}
+	}
+	if result.Items[1].RecipeID == nil || *result.Items[1].RecipeID != existingID {
+		t.Errorf("expected duplicate to point at the existing recipe, got %+v", result.Items[1])
+	}
+	if result.Imported != 2 || result.Skipped != 1 || result.Failed != 1 {
+		t.Errorf("unexpected totals %+v", result)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(created) != 2 {
+		t.Fatalf("expected 2 recipes created, got %d", len(created))
+	}
+	toast := created[0]
+	if toast.UserID != user.ID || toast.SourceType != "import" || len(toast.Ingredients) != 1 || toast.Ingredients[0].Name != "bread" {
+		t.Errorf("unexpected recipe %+v", toast)
+	}
+	if toast.Nutrition == nil || toast.Nutrition.Calories != 300 {
+		t.Errorf("expected enrichment to be applied, got %+v", toast.Nutrition)
+	}
+}
+
+func TestImport_RecipeLimitDuringRun(t *testing.T) {
+	user := &model.User{ID: uuid.New()}
+	created := 0
+	h := &UnifiedExtractionHandler{
+		jobRepo: &mockJobRepository{},
+		recipeRepo: &mockRecipeRepository{
+			CountByUserFunc: func(ctx context.Context, userID uuid.UUID) (int, error) { return 24, nil },
+			CreateFunc: func(ctx context.Context, recipe *model.Recipe) error {
+				created++
+				return nil
+			},
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	job := &model.ExtractionJob{ID: uuid.New(), UserID: user.ID, JobType: model.JobTypeImport}
+	entries, err := importer.Parse(importer.FormatCSV, []byte("title,steps\nA,x\nB,y\nC,z\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.ImportResult = &model.ImportResult{Total: len(entries), Items: make([]model.ImportItemResult, len(entries))}
+
+	h.processImport(context.Background(), job, entries, false, model.TierLimits["free"].MaxRecipes)
+
+	if created != 1 || job.ImportResult.Imported != 1 || job.ImportResult.Skipped != 2 {
+		t.Errorf("expected one import then skips, got %d created, %+v", created, job.ImportResult)
+	}
+	if job.ImportResult.Items[2].Error != "Recipe limit reached" {
+		t.Errorf("unexpected item %+v", job.ImportResult.Items[2])
+	}
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
+	"github.com/dishflow/backend/internal/service/ai"
+)
+
+const (
+	// partialWriteInterval throttles partial recipe writes while Gemini streams.
+	// The first snapshot and stage changes are always written immediately.
+	partialWriteInterval = 750 * time.Millisecond
+
+	// streamPollInterval is how often the job stream checks for changes
+	streamPollInterval = 1 * time.Second
+
+	// streamKeepAlive is how often a comment is sent to keep proxies from closing idle streams
+	streamKeepAlive = 15 * time.Second
+)
+
+// partialRecipeWriter converts streamed extraction snapshots into the job's
+// PartialRecipe and persists them so GetJob and the job stream can serve them
+// from any instance.
+type partialRecipeWriter struct {
+	jobRepo JobRepository
+	jobID   uuid.UUID
+
+	mu        sync.Mutex
+	thumbnail string
+	stage     string
+	lastWrite time.Time
+}
+
+type partialWriterKey struct{}
+
+// withPartialRecipeWriter attaches a writer to ctx and registers it with the
+// extractor so streamed snapshots are recorded under the extracting stage.
+func withPartialRecipeWriter(ctx context.Context, w *partialRecipeWriter) context.Context {
+	ctx = context.WithValue(ctx, partialWriterKey{}, w)
+	return ai.WithPartialResults(ctx, func(partial *ai.ExtractionResult) {
+		w.observe(ctx, model.PartialStageExtracting, partial, false)
+	})
+}
+
+// partialWriterFrom returns the writer attached to ctx, or nil
+func partialWriterFrom(ctx context.Context) *partialRecipeWriter {
+	w, _ := ctx.Value(partialWriterKey{}).(*partialRecipeWriter)
+	return w
+}
+
+// setThumbnail records a thumbnail known before Gemini returns one (video CDN, oEmbed)
+func (w *partialRecipeWriter) setThumbnail(url string) {
+	if w == nil || url == "" {
+		return
+	}
+	w.mu.Lock()
+	w.thumbnail = url
+	w.mu.Unlock()
+}
+
+// observe records a snapshot. force bypasses throttling (used between pipeline stages).
+func (w *partialRecipeWriter) observe(ctx context.Context, stage string, result *ai.ExtractionResult, force bool) {
+	if w == nil || result == nil || ctx.Err() != nil {
+		return
+	}
+
+	w.mu.Lock()
+	now := time.Now()
+	if !force && stage == w.stage && now.Sub(w.lastWrite) < partialWriteInterval {
+		w.mu.Unlock()
+		return
+	}
+	w.stage = stage
+	w.lastWrite = now
+	partial := toPartialRecipe(stage, result, w.thumbnail)
+	w.mu.Unlock()
+
+	if err := w.jobRepo.UpdatePartialRecipe(ctx, w.jobID, partial); err != nil {
+		middleware.GetLogger(ctx).Warn("Failed to update partial recipe", "error", err, "job_id", w.jobID)
+	}
+}
+
+// toPartialRecipe converts an extraction snapshot to the API preview shape
+func toPartialRecipe(stage string, result *ai.ExtractionResult, fallbackThumbnail string) *model.PartialRecipe {
+	partial := &model.PartialRecipe{
+		Stage:        stage,
+		Title:        result.Title,
+		Description:  result.Description,
+		ThumbnailURL: result.Thumbnail,
+		Servings:     result.Servings,
+		PrepTime:     result.PrepTime,
+		CookTime:     result.CookTime,
+		Ingredients:  make([]model.PartialIngredient, 0, len(result.Ingredients)),
+		Steps:        make([]model.PartialStep, 0, len(result.Steps)),
+		UpdatedAt:    time.Now().UTC(),
+	}
+	if partial.ThumbnailURL == "" {
+		partial.ThumbnailURL = fallbackThumbnail
+	}
+
+	for _, ing := range result.Ingredients {
+		partial.Ingredients = append(partial.Ingredients, model.PartialIngredient{
+			Name:     ing.Name,
+			Quantity: ing.Quantity,
+			Unit:     ing.Unit,
+			Section:  ing.Section,
+		})
+	}
+	for i, step := range result.Steps {
+		number := step.StepNumber
+		if number == 0 {
+			number = i + 1
+		}
+		partial.Steps = append(partial.Steps, model.PartialStep{
+			StepNumber:  number,
+			Instruction: step.Instruction,
+		})
+	}
+
+	return partial
+}
+
+// StreamJob streams job updates as Server-Sent Events
+// @Summary Stream job progress
+// @Description Server-Sent Events stream of job updates. Each "job" event carries a JobResponse
+// @Description (including partialRecipe while extraction is running). The stream ends after the
+// @Description job reaches a terminal status.
+// @Tags Jobs
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param jobID path string true "Job UUID"
+// @Success 200 {object} SwaggerJobResponse "Stream of job events"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid job ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Job not found"
+// @Router /jobs/{jobID}/stream [get]
+func (h *UnifiedExtractionHandler) StreamJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "jobID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid job ID")
+		return
+	}
+
+	job, err := h.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrJobNotFound) {
+			response.NotFound(w, "Job not found")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+	if job.UserID != user.ID {
+		response.Forbidden(w, "Access denied")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // disable nginx buffering
+	w.WriteHeader(http.StatusOK)
+
+	var lastPayload []byte
+	lastSent := time.Now()
+
+	send := func(event string, data []byte) bool {
+		// Long video jobs outlive the server's WriteTimeout, so extend it per event
+		_ = rc.SetWriteDeadline(time.Now().Add(streamKeepAlive * 2))
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return false
+		}
+		lastSent = time.Now()
+		return rc.Flush() == nil
+	}
+
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+
+	for {
+		resp := job.ToResponse("")
+		h.addQueueInfo(&resp, job)
+		if job.Status == model.JobStatusCompleted && job.ResultRecipeID != nil {
+			resp.Recipe, _ = h.recipeRepo.GetByID(ctx, *job.ResultRecipeID)
+		}
+
+		payload, err := json.Marshal(resp)
+		if err != nil {
+			return
+		}
+		if string(payload) != string(lastPayload) {
+			if !send("job", payload) {
+				return
+			}
+			lastPayload = payload
+		} else if time.Since(lastSent) >= streamKeepAlive {
+			_ = rc.SetWriteDeadline(time.Now().Add(streamKeepAlive * 2))
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil || rc.Flush() != nil {
+				return
+			}
+			lastSent = time.Now()
+		}
+
+		if isTerminalJobStatus(job.Status) {
+			send("done", []byte(`{}`))
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		job, err = h.jobRepo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, postgres.ErrJobNotFound) {
+				send("done", []byte(`{}`))
+			}
+			return
+		}
+	}
+}
+
+// isTerminalJobStatus reports whether a job will receive no further updates
+func isTerminalJobStatus(status model.JobStatus) bool {
+	return status == model.JobStatusCompleted ||
+		status == model.JobStatusFailed ||
+		status == model.JobStatusCancelled
+}
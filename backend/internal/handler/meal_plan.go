@@ -14,6 +14,7 @@ import (
 	"github.com/dishflow/backend/internal/middleware"
 	"github.com/dishflow/backend/internal/model"
 	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/service/scaling"
 )
 
 // MealPlanRepository defines the interface for meal plan persistence
@@ -23,7 +24,7 @@ type MealPlanRepository interface {
 	UpdateTitle(ctx context.Context, planID, userID uuid.UUID, title string) (*model.MealPlan, error)
 	AddEntry(ctx context.Context, planID uuid.UUID, input *model.MealPlanEntryInput) (*model.MealPlanEntry, error)
 	RemoveEntry(ctx context.Context, planID, entryID uuid.UUID) error
-	GetPlanRecipeIngredients(ctx context.Context, planID uuid.UUID) ([]model.RecipeIngredient, []string, error)
+	GetPlanRecipeIngredients(ctx context.Context, planID uuid.UUID) ([]model.MealPlanIngredient, error)
 }
 
 // MealPlanHandler handles meal plan HTTP requests
@@ -278,7 +279,7 @@ func (h *MealPlanHandler) GenerateShoppingList(w http.ResponseWriter, r *http.Re
 	}
 
 	// Get all recipe ingredients from the plan
-	ingredients, _, err := h.mealPlanRepo.GetPlanRecipeIngredients(ctx, planID)
+	ingredients, err := h.mealPlanRepo.GetPlanRecipeIngredients(ctx, planID)
 	if err != nil {
 		response.InternalError(w)
 		return
@@ -309,7 +310,12 @@ func (h *MealPlanHandler) GenerateShoppingList(w http.ResponseWriter, r *http.Re
 	}
 	aggregated := make(map[ingredientKey]*model.ShoppingItemInput)
 
-	for _, ing := range ingredients {
+	for _, planned := range ingredients {
+		ing := planned.RecipeIngredient
+		if planned.EntryServings != nil {
+			ing = scaling.Ingredient(ing, scaling.Factor(planned.RecipeServings, *planned.EntryServings))
+		}
+
 		category := model.NormalizeCategory(ing.Category)
 		qty, unit := model.ToShoppingUnit(ing.Quantity, ing.Unit)
 
@@ -3,9 +3,12 @@ package handler
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
+	"github.com/dishflow/backend/internal/repository/postgres"
 	"github.com/dishflow/backend/internal/service/ai"
 	"github.com/dishflow/backend/internal/service/video"
 	"github.com/google/uuid"
@@ -19,6 +22,10 @@ type mockUserRepository struct {
 	GetSubscriptionFunc         func(ctx context.Context, userID uuid.UUID) (*model.UserSubscription, error)
 	CountUserScansThisMonthFunc func(ctx context.Context, userID uuid.UUID) (int, error)
 	TrackScanUsageFunc          func(ctx context.Context, userID uuid.UUID) error
+	GetByClerkIDFunc            func(ctx context.Context, clerkID string) (*model.User, error)
+	UpsertSubscriptionFunc      func(ctx context.Context, sub *model.UserSubscription) error
+	IsEventProcessedFunc        func(ctx context.Context, eventID string) (bool, error)
+	LogEventFunc                func(ctx context.Context, eventID, eventType, appUserID string, payload json.RawMessage) error
 }
 
 func (m *mockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
@@ -51,20 +58,52 @@ func (m *mockUserRepository) TrackScanUsage(ctx context.Context, userID uuid.UUI
 	}
 	return m.TrackScanUsageFunc(ctx, userID)
 }
+func (m *mockUserRepository) GetByClerkID(ctx context.Context, clerkID string) (*model.User, error) {
+	if m.GetByClerkIDFunc == nil {
+		return nil, sql.ErrNoRows
+	}
+	return m.GetByClerkIDFunc(ctx, clerkID)
+}
+func (m *mockUserRepository) UpsertSubscription(ctx context.Context, sub *model.UserSubscription) error {
+	if m.UpsertSubscriptionFunc == nil {
+		return nil
+	}
+	return m.UpsertSubscriptionFunc(ctx, sub)
+}
+func (m *mockUserRepository) IsEventProcessed(ctx context.Context, eventID string) (bool, error) {
+	if m.IsEventProcessedFunc == nil {
+		return false, nil
+	}
+	return m.IsEventProcessedFunc(ctx, eventID)
+}
+func (m *mockUserRepository) LogEvent(ctx context.Context, eventID, eventType, appUserID string, payload json.RawMessage) error {
+	if m.LogEventFunc == nil {
+		return nil
+	}
+	return m.LogEventFunc(ctx, eventID, eventType, appUserID, payload)
+}
 
 type mockRecipeRepository struct {
 	CreateFunc                 func(ctx context.Context, recipe *model.Recipe) error
 	GetByIDFunc                func(ctx context.Context, id uuid.UUID) (*model.Recipe, error)
 	GetBySourceRecipeIDFunc    func(ctx context.Context, userID, sourceRecipeID uuid.UUID) (*model.Recipe, error)
+	CountByUserFunc            func(ctx context.Context, userID uuid.UUID) (int, error)
 	GetBySourceURLFunc         func(ctx context.Context, userID uuid.UUID, sourceURL string) (*model.Recipe, error)
-	ListByUserFunc             func(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.Recipe, int, error)
-	ListPublicFunc             func(ctx context.Context, limit, offset int) ([]*model.Recipe, int, error)
-	ListFeaturedFunc           func(ctx context.Context, limit, offset int) ([]*model.Recipe, int, error)
+	ListByUserFunc             func(ctx context.Context, userID uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error)
+	FacetsFunc                 func(ctx context.Context, userID uuid.UUID, filter model.RecipeFilter) (*model.RecipeFacets, error)
+	ListByCollectionFunc       func(ctx context.Context, collectionID, userID uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error)
+	ListPublicFunc             func(ctx context.Context, page pagination.Page) ([]*model.Recipe, int, string, error)
+	ListFeaturedFunc           func(ctx context.Context, page pagination.Page) ([]*model.Recipe, int, string, error)
 	ListForRecommendationsFunc func(ctx context.Context, userID uuid.UUID) ([]*model.Recipe, error)
-	SearchFunc                 func(ctx context.Context, userID uuid.UUID, query string, limit int) ([]*model.Recipe, error)
+	SearchFunc                 func(ctx context.Context, userID uuid.UUID, query, lang string, limit int) ([]*model.Recipe, error)
+	SearchPublicFunc           func(ctx context.Context, query, lang string, limit int) ([]*model.Recipe, error)
+	SearchByIngredientsFunc    func(ctx context.Context, userID uuid.UUID, search model.IngredientSearch) ([]*model.IngredientSearchResult, error)
 	UpdateFunc                 func(ctx context.Context, recipe *model.Recipe) error
 	SoftDeleteFunc             func(ctx context.Context, id uuid.UUID) error
 	SetFavoriteFunc            func(ctx context.Context, id uuid.UUID, isFavorite bool) error
+	ListRevisionsFunc          func(ctx context.Context, recipeID uuid.UUID) ([]model.RecipeRevision, error)
+	GetRevisionFunc            func(ctx context.Context, recipeID, revisionID uuid.UUID) (*model.RecipeRevision, error)
+	RestoreFunc                func(ctx context.Context, recipe *model.Recipe, fromRevisionID uuid.UUID) error
 }
 
 func (m *mockRecipeRepository) Create(ctx context.Context, recipe *model.Recipe) error {
@@ -91,23 +130,41 @@ func (m *mockRecipeRepository) GetBySourceURL(ctx context.Context, userID uuid.U
 	}
 	return m.GetBySourceURLFunc(ctx, userID, sourceURL)
 }
-func (m *mockRecipeRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.Recipe, int, error) {
+func (m *mockRecipeRepository) ListByUser(ctx context.Context, userID uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
 	if m.ListByUserFunc == nil {
-		return nil, 0, nil
+		return nil, 0, "", nil
+	}
+	return m.ListByUserFunc(ctx, userID, filter, page)
+}
+func (m *mockRecipeRepository) Facets(ctx context.Context, userID uuid.UUID, filter model.RecipeFilter) (*model.RecipeFacets, error) {
+	if m.FacetsFunc == nil {
+		return &model.RecipeFacets{}, nil
 	}
-	return m.ListByUserFunc(ctx, userID, limit, offset)
+	return m.FacetsFunc(ctx, userID, filter)
 }
-func (m *mockRecipeRepository) ListPublic(ctx context.Context, limit, offset int) ([]*model.Recipe, int, error) {
+func (m *mockRecipeRepository) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	if m.CountByUserFunc == nil {
+		return 0, nil
+	}
+	return m.CountByUserFunc(ctx, userID)
+}
+func (m *mockRecipeRepository) ListByCollection(ctx context.Context, collectionID, userID uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+	if m.ListByCollectionFunc == nil {
+		return nil, 0, "", nil
+	}
+	return m.ListByCollectionFunc(ctx, collectionID, userID, filter, page)
+}
+func (m *mockRecipeRepository) ListPublic(ctx context.Context, page pagination.Page) ([]*model.Recipe, int, string, error) {
 	if m.ListPublicFunc == nil {
-		return nil, 0, nil
+		return nil, 0, "", nil
 	}
-	return m.ListPublicFunc(ctx, limit, offset)
+	return m.ListPublicFunc(ctx, page)
 }
-func (m *mockRecipeRepository) ListFeatured(ctx context.Context, limit, offset int) ([]*model.Recipe, int, error) {
+func (m *mockRecipeRepository) ListFeatured(ctx context.Context, page pagination.Page) ([]*model.Recipe, int, string, error) {
 	if m.ListFeaturedFunc == nil {
-		return nil, 0, nil
+		return nil, 0, "", nil
 	}
-	return m.ListFeaturedFunc(ctx, limit, offset)
+	return m.ListFeaturedFunc(ctx, page)
 }
 func (m *mockRecipeRepository) ListForRecommendations(ctx context.Context, userID uuid.UUID) ([]*model.Recipe, error) {
 	if m.ListForRecommendationsFunc == nil {
@@ -115,14 +172,23 @@ func (m *mockRecipeRepository) ListForRecommendations(ctx context.Context, userI
 	}
 	return m.ListForRecommendationsFunc(ctx, userID)
 }
-func (m *mockRecipeRepository) Search(ctx context.Context, userID uuid.UUID, query string, limit int) ([]*model.Recipe, error) {
+func (m *mockRecipeRepository) Search(ctx context.Context, userID uuid.UUID, query, lang string, limit int) ([]*model.Recipe, error) {
 	if m.SearchFunc == nil {
 		return []*model.Recipe{}, nil
 	}
-	return m.SearchFunc(ctx, userID, query, limit)
+	return m.SearchFunc(ctx, userID, query, lang, limit)
 }
-func (m *mockRecipeRepository) SearchPublic(ctx context.Context, query string, limit int) ([]*model.Recipe, error) {
-	return []*model.Recipe{}, nil
+func (m *mockRecipeRepository) SearchPublic(ctx context.Context, query, lang string, limit int) ([]*model.Recipe, error) {
+	if m.SearchPublicFunc == nil {
+		return []*model.Recipe{}, nil
+	}
+	return m.SearchPublicFunc(ctx, query, lang, limit)
+}
+func (m *mockRecipeRepository) SearchByIngredients(ctx context.Context, userID uuid.UUID, search model.IngredientSearch) ([]*model.IngredientSearchResult, error) {
+	if m.SearchByIngredientsFunc == nil {
+		return []*model.IngredientSearchResult{}, nil
+	}
+	return m.SearchByIngredientsFunc(ctx, userID, search)
 }
 func (m *mockRecipeRepository) Update(ctx context.Context, recipe *model.Recipe) error {
 	if m.UpdateFunc == nil {
@@ -142,9 +208,294 @@ func (m *mockRecipeRepository) SetFavorite(ctx context.Context, id uuid.UUID, is
 	}
 	return m.SetFavoriteFunc(ctx, id, isFavorite)
 }
+func (m *mockRecipeRepository) ListRevisions(ctx context.Context, recipeID uuid.UUID) ([]model.RecipeRevision, error) {
+	if m.ListRevisionsFunc == nil {
+		return []model.RecipeRevision{}, nil
+	}
+	return m.ListRevisionsFunc(ctx, recipeID)
+}
+func (m *mockRecipeRepository) GetRevision(ctx context.Context, recipeID, revisionID uuid.UUID) (*model.RecipeRevision, error) {
+	if m.GetRevisionFunc == nil {
+		return nil, postgres.ErrRevisionNotFound
+	}
+	return m.GetRevisionFunc(ctx, recipeID, revisionID)
+}
+func (m *mockRecipeRepository) Restore(ctx context.Context, recipe *model.Recipe, fromRevisionID uuid.UUID) error {
+	if m.RestoreFunc == nil {
+		return nil
+	}
+	return m.RestoreFunc(ctx, recipe, fromRevisionID)
+}
+
+type mockCollectionRepository struct {
+	ListFunc           func(ctx context.Context, userID uuid.UUID) ([]*model.Collection, error)
+	GetFunc            func(ctx context.Context, id, userID uuid.UUID) (*model.Collection, error)
+	CreateFunc         func(ctx context.Context, userID uuid.UUID, input *model.CollectionInput) (*model.Collection, error)
+	UpdateFunc         func(ctx context.Context, id, userID uuid.UUID, input *model.CollectionInput) (*model.Collection, error)
+	DeleteFunc         func(ctx context.Context, id, userID uuid.UUID) error
+	ReorderFunc        func(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+	AddRecipesFunc     func(ctx context.Context, id, userID uuid.UUID, recipeIDs []uuid.UUID) (*model.Collection, error)
+	RemoveRecipeFunc   func(ctx context.Context, id, userID, recipeID uuid.UUID) error
+	ReorderRecipesFunc func(ctx context.Context, id, userID uuid.UUID, recipeIDs []uuid.UUID) (*model.Collection, error)
+}
+
+func (m *mockCollectionRepository) List(ctx context.Context, userID uuid.UUID) ([]*model.Collection, error) {
+	if m.ListFunc == nil {
+		return []*model.Collection{}, nil
+	}
+	return m.ListFunc(ctx, userID)
+}
+func (m *mockCollectionRepository) Get(ctx context.Context, id, userID uuid.UUID) (*model.Collection, error) {
+	if m.GetFunc == nil {
+		return nil, model.ErrNotFound
+	}
+	return m.GetFunc(ctx, id, userID)
+}
+func (m *mockCollectionRepository) Create(ctx context.Context, userID uuid.UUID, input *model.CollectionInput) (*model.Collection, error) {
+	return m.CreateFunc(ctx, userID, input)
+}
+func (m *mockCollectionRepository) Update(ctx context.Context, id, userID uuid.UUID, input *model.CollectionInput) (*model.Collection, error) {
+	return m.UpdateFunc(ctx, id, userID, input)
+}
+func (m *mockCollectionRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	return m.DeleteFunc(ctx, id, userID)
+}
+func (m *mockCollectionRepository) Reorder(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	return m.ReorderFunc(ctx, userID, ids)
+}
+func (m *mockCollectionRepository) AddRecipes(ctx context.Context, id, userID uuid.UUID, recipeIDs []uuid.UUID) (*model.Collection, error) {
+	return m.AddRecipesFunc(ctx, id, userID, recipeIDs)
+}
+func (m *mockCollectionRepository) RemoveRecipe(ctx context.Context, id, userID, recipeID uuid.UUID) error {
+	return m.RemoveRecipeFunc(ctx, id, userID, recipeID)
+}
+func (m *mockCollectionRepository) ReorderRecipes(ctx context.Context, id, userID uuid.UUID, recipeIDs []uuid.UUID) (*model.Collection, error) {
+	return m.ReorderRecipesFunc(ctx, id, userID, recipeIDs)
+}
+
+type mockShareRepository struct {
+	CreateFunc            func(ctx context.Context, share *model.RecipeShare) error
+	GetByIDFunc           func(ctx context.Context, id uuid.UUID) (*model.RecipeShare, error)
+	ListInboxFunc         func(ctx context.Context, userID uuid.UUID, email string, status model.ShareStatus) ([]*model.RecipeShare, error)
+	ListSentFunc          func(ctx context.Context, ownerID uuid.UUID, recipeID *uuid.UUID) ([]*model.RecipeShare, error)
+	AcceptFunc            func(ctx context.Context, id, recipientID uuid.UUID) error
+	SetAcceptedRecipeFunc func(ctx context.Context, id, recipeID uuid.UUID) error
+	ReopenFunc            func(ctx context.Context, id uuid.UUID) error
+	DeclineFunc           func(ctx context.Context, id, recipientID uuid.UUID) error
+	DeleteFunc            func(ctx context.Context, id, ownerID uuid.UUID) error
+}
+
+func (m *mockShareRepository) Create(ctx context.Context, share *model.RecipeShare) error {
+	return m.CreateFunc(ctx, share)
+}
+func (m *mockShareRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.RecipeShare, error) {
+	if m.GetByIDFunc == nil {
+		return nil, postgres.ErrShareNotFound
+	}
+	return m.GetByIDFunc(ctx, id)
+}
+func (m *mockShareRepository) ListInbox(ctx context.Context, userID uuid.UUID, email string, status model.ShareStatus) ([]*model.RecipeShare, error) {
+	if m.ListInboxFunc == nil {
+		return []*model.RecipeShare{}, nil
+	}
+	return m.ListInboxFunc(ctx, userID, email, status)
+}
+func (m *mockShareRepository) ListSent(ctx context.Context, ownerID uuid.UUID, recipeID *uuid.UUID) ([]*model.RecipeShare, error) {
+	if m.ListSentFunc == nil {
+		return []*model.RecipeShare{}, nil
+	}
+	return m.ListSentFunc(ctx, ownerID, recipeID)
+}
+func (m *mockShareRepository) Accept(ctx context.Context, id, recipientID uuid.UUID) error {
+	return m.AcceptFunc(ctx, id, recipientID)
+}
+func (m *mockShareRepository) SetAcceptedRecipe(ctx context.Context, id, recipeID uuid.UUID) error {
+	return m.SetAcceptedRecipeFunc(ctx, id, recipeID)
+}
+func (m *mockShareRepository) Reopen(ctx context.Context, id uuid.UUID) error {
+	return m.ReopenFunc(ctx, id)
+}
+func (m *mockShareRepository) Decline(ctx context.Context, id, recipientID uuid.UUID) error {
+	return m.DeclineFunc(ctx, id, recipientID)
+}
+func (m *mockShareRepository) Delete(ctx context.Context, id, ownerID uuid.UUID) error {
+	return m.DeleteFunc(ctx, id, ownerID)
+}
+
+type mockShareLinkRepository struct {
+	GetActiveFunc  func(ctx context.Context, recipeID uuid.UUID) (*model.RecipeShareLink, error)
+	CreateFunc     func(ctx context.Context, recipeID, userID uuid.UUID) (*model.RecipeShareLink, error)
+	RevokeFunc     func(ctx context.Context, recipeID uuid.UUID) error
+	RecordViewFunc func(ctx context.Context, token string) (*model.RecipeShareLink, error)
+}
+
+func (m *mockShareLinkRepository) GetActive(ctx context.Context, recipeID uuid.UUID) (*model.RecipeShareLink, error) {
+	if m.GetActiveFunc == nil {
+		return nil, postgres.ErrShareLinkNotFound
+	}
+	return m.GetActiveFunc(ctx, recipeID)
+}
+func (m *mockShareLinkRepository) Create(ctx context.Context, recipeID, userID uuid.UUID) (*model.RecipeShareLink, error) {
+	return m.CreateFunc(ctx, recipeID, userID)
+}
+func (m *mockShareLinkRepository) Revoke(ctx context.Context, recipeID uuid.UUID) error {
+	return m.RevokeFunc(ctx, recipeID)
+}
+func (m *mockShareLinkRepository) RecordView(ctx context.Context, token string) (*model.RecipeShareLink, error) {
+	if m.RecordViewFunc == nil {
+		return nil, postgres.ErrShareLinkNotFound
+	}
+	return m.RecordViewFunc(ctx, token)
+}
+
+type mockCookLogRepository struct {
+	GetFunc          func(ctx context.Context, id, userID uuid.UUID) (*model.CookLog, error)
+	CreateFunc       func(ctx context.Context, recipeID, userID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error)
+	UpdateFunc       func(ctx context.Context, id, userID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error)
+	AddPhotoFunc     func(ctx context.Context, id, userID uuid.UUID, url string) (*model.CookLog, error)
+	DeleteFunc       func(ctx context.Context, id, userID uuid.UUID) error
+	ListByRecipeFunc func(ctx context.Context, recipeID, userID uuid.UUID, page pagination.Page) ([]*model.CookLog, string, error)
+	ListByUserFunc   func(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*model.CookLog, string, error)
+}
+
+func (m *mockCookLogRepository) Get(ctx context.Context, id, userID uuid.UUID) (*model.CookLog, error) {
+	if m.GetFunc == nil {
+		return nil, model.ErrNotFound
+	}
+	return m.GetFunc(ctx, id, userID)
+}
+func (m *mockCookLogRepository) Create(ctx context.Context, recipeID, userID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error) {
+	return m.CreateFunc(ctx, recipeID, userID, input)
+}
+func (m *mockCookLogRepository) Update(ctx context.Context, id, userID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error) {
+	return m.UpdateFunc(ctx, id, userID, input)
+}
+func (m *mockCookLogRepository) AddPhoto(ctx context.Context, id, userID uuid.UUID, url string) (*model.CookLog, error) {
+	return m.AddPhotoFunc(ctx, id, userID, url)
+}
+func (m *mockCookLogRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	return m.DeleteFunc(ctx, id, userID)
+}
+func (m *mockCookLogRepository) ListByRecipe(ctx context.Context, recipeID, userID uuid.UUID, page pagination.Page) ([]*model.CookLog, string, error) {
+	return m.ListByRecipeFunc(ctx, recipeID, userID, page)
+}
+func (m *mockCookLogRepository) ListByUser(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*model.CookLog, string, error) {
+	return m.ListByUserFunc(ctx, userID, page)
+}
+
+type mockAnnotationRepository struct {
+	ListByRecipeFunc func(ctx context.Context, recipeID, userID uuid.UUID) ([]model.RecipeAnnotation, error)
+	SetFunc          func(ctx context.Context, annotation *model.RecipeAnnotation) (*model.RecipeAnnotation, error)
+	DeleteFunc       func(ctx context.Context, id, recipeID, userID uuid.UUID) error
+}
+
+func (m *mockAnnotationRepository) ListByRecipe(ctx context.Context, recipeID, userID uuid.UUID) ([]model.RecipeAnnotation, error) {
+	if m.ListByRecipeFunc == nil {
+		return nil, nil
+	}
+	return m.ListByRecipeFunc(ctx, recipeID, userID)
+}
+func (m *mockAnnotationRepository) Set(ctx context.Context, annotation *model.RecipeAnnotation) (*model.RecipeAnnotation, error) {
+	return m.SetFunc(ctx, annotation)
+}
+func (m *mockAnnotationRepository) Delete(ctx context.Context, id, recipeID, userID uuid.UUID) error {
+	return m.DeleteFunc(ctx, id, recipeID, userID)
+}
+
+type mockDuplicateRepository struct {
+	ListGroupsFunc func(ctx context.Context, userID uuid.UUID) ([]*model.DuplicateGroup, error)
+	DismissFunc    func(ctx context.Context, id, userID uuid.UUID) error
+	MergeFunc      func(ctx context.Context, userID, keepID uuid.UUID, mergeIDs []uuid.UUID) (*model.RecipeMergeResult, error)
+}
+
+func (m *mockDuplicateRepository) ListGroups(ctx context.Context, userID uuid.UUID) ([]*model.DuplicateGroup, error) {
+	return m.ListGroupsFunc(ctx, userID)
+}
+func (m *mockDuplicateRepository) Dismiss(ctx context.Context, id, userID uuid.UUID) error {
+	return m.DismissFunc(ctx, id, userID)
+}
+func (m *mockDuplicateRepository) Merge(ctx context.Context, userID, keepID uuid.UUID, mergeIDs []uuid.UUID) (*model.RecipeMergeResult, error) {
+	return m.MergeFunc(ctx, userID, keepID, mergeIDs)
+}
+
+type mockCookSessionRepository struct {
+	CreateFunc      func(ctx context.Context, recipeID, userID uuid.UUID, servings *int, step int) (*model.CookSession, error)
+	GetFunc         func(ctx context.Context, id, userID uuid.UUID) (*model.CookSession, error)
+	ListActiveFunc  func(ctx context.Context, userID uuid.UUID) ([]*model.CookSession, error)
+	SetStepFunc     func(ctx context.Context, id, userID uuid.UUID, step int) (*model.CookSession, error)
+	AddTimerFunc    func(ctx context.Context, id, userID uuid.UUID, timer model.CookTimer) (*model.CookSession, error)
+	RemoveTimerFunc func(ctx context.Context, id, userID, timerID uuid.UUID) (*model.CookSession, error)
+	FinishFunc      func(ctx context.Context, id, userID uuid.UUID, log *model.CookLogInput) (*model.CookSession, error)
+	DeleteFunc      func(ctx context.Context, id, userID uuid.UUID) error
+}
+
+func (m *mockCookSessionRepository) Create(ctx context.Context, recipeID, userID uuid.UUID, servings *int, step int) (*model.CookSession, error) {
+	return m.CreateFunc(ctx, recipeID, userID, servings, step)
+}
+func (m *mockCookSessionRepository) Get(ctx context.Context, id, userID uuid.UUID) (*model.CookSession, error) {
+	return m.GetFunc(ctx, id, userID)
+}
+func (m *mockCookSessionRepository) ListActive(ctx context.Context, userID uuid.UUID) ([]*model.CookSession, error) {
+	return m.ListActiveFunc(ctx, userID)
+}
+func (m *mockCookSessionRepository) SetStep(ctx context.Context, id, userID uuid.UUID, step int) (*model.CookSession, error) {
+	return m.SetStepFunc(ctx, id, userID, step)
+}
+func (m *mockCookSessionRepository) AddTimer(ctx context.Context, id, userID uuid.UUID, timer model.CookTimer) (*model.CookSession, error) {
+	return m.AddTimerFunc(ctx, id, userID, timer)
+}
+func (m *mockCookSessionRepository) RemoveTimer(ctx context.Context, id, userID, timerID uuid.UUID) (*model.CookSession, error) {
+	return m.RemoveTimerFunc(ctx, id, userID, timerID)
+}
+func (m *mockCookSessionRepository) Finish(ctx context.Context, id, userID uuid.UUID, log *model.CookLogInput) (*model.CookSession, error) {
+	return m.FinishFunc(ctx, id, userID, log)
+}
+func (m *mockCookSessionRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	return m.DeleteFunc(ctx, id, userID)
+}
+
+type mockTagRepository struct {
+	ListFunc    func(ctx context.Context, userID uuid.UUID) ([]model.TagCount, error)
+	ReplaceFunc func(ctx context.Context, userID uuid.UUID, replacements map[string]string) (int, error)
+}
+
+func (m *mockTagRepository) List(ctx context.Context, userID uuid.UUID) ([]model.TagCount, error) {
+	return m.ListFunc(ctx, userID)
+}
+func (m *mockTagRepository) Replace(ctx context.Context, userID uuid.UUID, replacements map[string]string) (int, error) {
+	return m.ReplaceFunc(ctx, userID, replacements)
+}
+
+type mockNutritionCalculator struct {
+	CalculateFunc func(ctx context.Context, recipe *model.Recipe) (*model.NutritionReport, error)
+}
+
+func (m *mockNutritionCalculator) Calculate(ctx context.Context, recipe *model.Recipe) (*model.NutritionReport, error) {
+	return m.CalculateFunc(ctx, recipe)
+}
+
+type mockThumbnailDownloader struct {
+	DownloadFunc func(ctx context.Context, url string) (string, error)
+	SaveFunc     func(data []byte, ext string) (string, error)
+}
+
+func (m *mockThumbnailDownloader) Download(ctx context.Context, url string) (string, error) {
+	return m.DownloadFunc(ctx, url)
+}
+func (m *mockThumbnailDownloader) Save(data []byte, ext string) (string, error) {
+	return m.SaveFunc(data, ext)
+}
+
+type mockImageFetcher struct {
+	FetchFunc func(ctx context.Context, url string) ([]byte, string, error)
+}
+
+func (m *mockImageFetcher) Fetch(ctx context.Context, url string) ([]byte, string, error) {
+	return m.FetchFunc(ctx, url)
+}
 
 type mockPantryRepository struct {
-	ListFunc    func(ctx context.Context, userID uuid.UUID, category *string, limit, offset int) ([]*model.PantryItem, int, error)
+	ListFunc    func(ctx context.Context, userID uuid.UUID, category *string, page pagination.Page) ([]*model.PantryItem, int, string, error)
 	ListAllFunc func(ctx context.Context, userID uuid.UUID) ([]model.PantryItem, error)
 	GetFunc     func(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.PantryItem, error)
 	CreateFunc  func(ctx context.Context, userID uuid.UUID, input *model.PantryItemInput) (*model.PantryItem, error)
@@ -152,8 +503,8 @@ type mockPantryRepository struct {
 	DeleteFunc  func(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 }
 
-func (m *mockPantryRepository) List(ctx context.Context, userID uuid.UUID, category *string, limit, offset int) ([]*model.PantryItem, int, error) {
-	return m.ListFunc(ctx, userID, category, limit, offset)
+func (m *mockPantryRepository) List(ctx context.Context, userID uuid.UUID, category *string, page pagination.Page) ([]*model.PantryItem, int, string, error) {
+	return m.ListFunc(ctx, userID, category, page)
 }
 func (m *mockPantryRepository) ListAll(ctx context.Context, userID uuid.UUID) ([]model.PantryItem, error) {
 	if m.ListAllFunc == nil {
@@ -175,7 +526,7 @@ func (m *mockPantryRepository) Delete(ctx context.Context, id uuid.UUID, userID
 }
 
 type mockShoppingRepository struct {
-	ListListsFunc            func(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]*model.ShoppingList, error)
+	ListListsFunc            func(ctx context.Context, userID uuid.UUID, includeArchived bool, page pagination.Page) ([]*model.ShoppingList, string, error)
 	GetListFunc              func(ctx context.Context, id, userID uuid.UUID) (*model.ShoppingList, error)
 	GetListWithItemsFunc     func(ctx context.Context, id, userID uuid.UUID) (*model.ShoppingListWithItems, error)
 	CreateListFunc           func(ctx context.Context, userID uuid.UUID, input *model.ShoppingListInput) (*model.ShoppingList, error)
@@ -197,8 +548,8 @@ type mockShoppingRepository struct {
 	VerifyListsOwnershipFunc func(ctx context.Context, userID uuid.UUID, listIDs []uuid.UUID) (bool, error)
 }
 
-func (m *mockShoppingRepository) ListLists(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]*model.ShoppingList, error) {
-	return m.ListListsFunc(ctx, userID, includeArchived)
+func (m *mockShoppingRepository) ListLists(ctx context.Context, userID uuid.UUID, includeArchived bool, page pagination.Page) ([]*model.ShoppingList, string, error) {
+	return m.ListListsFunc(ctx, userID, includeArchived, page)
 }
 func (m *mockShoppingRepository) GetList(ctx context.Context, id, userID uuid.UUID) (*model.ShoppingList, error) {
 	return m.GetListFunc(ctx, id, userID)
@@ -279,9 +630,13 @@ func (m *mockShoppingRepository) VerifyListsOwnership(ctx context.Context, userI
 type mockJobRepository struct {
 	CreateFunc              func(ctx context.Context, job *model.VideoJob) error
 	GetByIDFunc             func(ctx context.Context, id uuid.UUID) (*model.VideoJob, error)
-	ListByUserFunc          func(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.VideoJob, error)
+	ListByUserFunc          func(ctx context.Context, userID uuid.UUID, filter model.JobListFilter, page pagination.Page) ([]*model.VideoJob, string, error)
+	CountByStatusFunc       func(ctx context.Context, userID uuid.UUID, filter model.JobListFilter) (map[model.JobStatus]int, error)
 	UpdateProgressFunc      func(ctx context.Context, id uuid.UUID, status model.JobStatus, progress int, message string) error
+	UpdatePartialRecipeFunc func(ctx context.Context, id uuid.UUID, partial *model.PartialRecipe) error
 	MarkCompletedFunc       func(ctx context.Context, id uuid.UUID, resultRecipeID uuid.UUID) error
+	UpdateImportResultFunc  func(ctx context.Context, id uuid.UUID, progress int, message string, result *model.ImportResult) error
+	MarkImportCompletedFunc func(ctx context.Context, id uuid.UUID, result *model.ImportResult) error
 	MarkFailedFunc          func(ctx context.Context, id uuid.UUID, errorCode, errorMessage string) error
 	MarkCancelledFunc       func(ctx context.Context, id uuid.UUID) error
 	DeleteFunc              func(ctx context.Context, id, userID uuid.UUID) error
@@ -302,11 +657,17 @@ func (m *mockJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.V
 	}
 	return m.GetByIDFunc(ctx, id)
 }
-func (m *mockJobRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.VideoJob, error) {
+func (m *mockJobRepository) ListByUser(ctx context.Context, userID uuid.UUID, filter model.JobListFilter, page pagination.Page) ([]*model.VideoJob, string, error) {
 	if m.ListByUserFunc == nil {
-		return nil, nil
+		return nil, "", nil
+	}
+	return m.ListByUserFunc(ctx, userID, filter, page)
+}
+func (m *mockJobRepository) CountByStatus(ctx context.Context, userID uuid.UUID, filter model.JobListFilter) (map[model.JobStatus]int, error) {
+	if m.CountByStatusFunc == nil {
+		return map[model.JobStatus]int{}, nil
 	}
-	return m.ListByUserFunc(ctx, userID, limit, offset)
+	return m.CountByStatusFunc(ctx, userID, filter)
 }
 func (m *mockJobRepository) UpdateProgress(ctx context.Context, id uuid.UUID, status model.JobStatus, progress int, message string) error {
 	if m.UpdateProgressFunc == nil {
@@ -314,12 +675,30 @@ func (m *mockJobRepository) UpdateProgress(ctx context.Context, id uuid.UUID, st
 	}
 	return m.UpdateProgressFunc(ctx, id, status, progress, message)
 }
+func (m *mockJobRepository) UpdatePartialRecipe(ctx context.Context, id uuid.UUID, partial *model.PartialRecipe) error {
+	if m.UpdatePartialRecipeFunc == nil {
+		return nil
+	}
+	return m.UpdatePartialRecipeFunc(ctx, id, partial)
+}
 func (m *mockJobRepository) MarkCompleted(ctx context.Context, id uuid.UUID, resultRecipeID uuid.UUID) error {
 	if m.MarkCompletedFunc == nil {
 		return nil
 	}
 	return m.MarkCompletedFunc(ctx, id, resultRecipeID)
 }
+func (m *mockJobRepository) UpdateImportResult(ctx context.Context, id uuid.UUID, progress int, message string, result *model.ImportResult) error {
+	if m.UpdateImportResultFunc == nil {
+		return nil
+	}
+	return m.UpdateImportResultFunc(ctx, id, progress, message, result)
+}
+func (m *mockJobRepository) MarkImportCompleted(ctx context.Context, id uuid.UUID, result *model.ImportResult) error {
+	if m.MarkImportCompletedFunc == nil {
+		return nil
+	}
+	return m.MarkImportCompletedFunc(ctx, id, result)
+}
 func (m *mockJobRepository) MarkFailed(ctx context.Context, id uuid.UUID, errorCode, errorMessage string) error {
 	if m.MarkFailedFunc == nil {
 		return nil
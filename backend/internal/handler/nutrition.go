@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
+)
+
+// NutritionHandler handles recipe nutrition computed from the food database
+type NutritionHandler struct {
+	recipeRepo RecipeRepository
+	calculator NutritionCalculator
+}
+
+// NewNutritionHandler creates a new nutrition handler
+func NewNutritionHandler(recipeRepo RecipeRepository, calculator NutritionCalculator) *NutritionHandler {
+	return &NutritionHandler{
+		recipeRepo: recipeRepo,
+		calculator: calculator,
+	}
+}
+
+// Get handles GET /api/v1/recipes/{recipeID}/nutrition
+// @Summary Get a recipe's nutrition breakdown
+// @Description Compute a recipe's nutrition from the USDA food database: each ingredient is matched to a food and weighed, and its macros and micros are added up. Ingredients no food matches are estimated by AI where available. Each ingredient's share is listed with where it came from.
+// @Tags Recipes
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Success 200 {object} SwaggerNutritionReport "Per-serving and total nutrition with a per-ingredient breakdown"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid recipe ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Router /recipes/{recipeID}/nutrition [get]
+func (h *NutritionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid recipe ID")
+		return
+	}
+
+	recipe, err := h.recipeRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrRecipeNotFound) {
+			response.NotFound(w, "Recipe")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	if recipe.UserID != user.ID && !recipe.IsPublic && !recipe.IsFeatured {
+		response.Forbidden(w, "Access denied")
+		return
+	}
+
+	report, err := h.calculator.Calculate(r.Context(), recipe)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, report)
+}
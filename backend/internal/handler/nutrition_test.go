@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+func TestNutritionHandler_Get(t *testing.T) {
+	mockRecipeRepo := &mockRecipeRepository{}
+	mockCalculator := &mockNutritionCalculator{}
+	handler := NewNutritionHandler(mockRecipeRepo, mockCalculator)
+
+	user := &model.User{ID: uuid.New()}
+	recipeID := uuid.New()
+	recipe := &model.Recipe{ID: recipeID, UserID: uuid.New()}
+	mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return recipe, nil
+	}
+	mockCalculator.CalculateFunc = func(ctx context.Context, r *model.Recipe) (*model.NutritionReport, error) {
+		return &model.NutritionReport{
+			RecipeID:   r.ID,
+			Servings:   2,
+			PerServing: model.Nutrients{Calories: 210},
+			Coverage:   1,
+			Ingredients: []model.IngredientNutrition{
+				{Name: "onion", Source: model.NutritionSourceUSDA, Food: "Onions, raw"},
+			},
+		}, nil
+	}
+	params := map[string]string{"recipeID": recipeID.String()}
+
+	t.Run("public recipe", func(t *testing.T) {
+		recipe.IsPublic = true
+		defer func() { recipe.IsPublic = false }()
+
+		rr := httptest.NewRecorder()
+		handler.Get(rr, shareRequest("GET", "/", "", user, params))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var report model.NutritionReport
+		json.Unmarshal(rr.Body.Bytes(), &report)
+		if report.RecipeID != recipeID || report.PerServing.Calories != 210 || len(report.Ingredients) != 1 ||
+			report.Ingredients[0].Food != "Onions, raw" {
+			t.Errorf("unexpected report %+v", report)
+		}
+	})
+
+	t.Run("someone else's private recipe", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Get(rr, shareRequest("GET", "/", "", user, params))
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+
+	t.Run("invalid recipe ID", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Get(rr, shareRequest("GET", "/", "", user, map[string]string{"recipeID": "nope"}))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
@@ -7,7 +7,6 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -48,8 +47,11 @@ func NewPantryHandler(repo PantryRepository, scanner ai.PantryScanner, userRepo
 // @Security BearerAuth
 // @Param category query string false "Filter by category" Enums(dairy, produce, proteins, bakery, pantry, spices, condiments, beverages, snacks, frozen, household, other)
 // @Param limit query int false "Items per page (max 200)" default(100)
-// @Param offset query int false "Pagination offset" default(0)
+// @Param cursor query string false "Cursor from a previous page's nextCursor"
+// @Param offset query int false "Rows to skip, for clients that page by offset; ignored with cursor"
+// @Param units query string false "Convert quantities; preferred uses the user's setting" Enums(metric, imperial, preferred)
 // @Success 200 {object} SwaggerPantryListResponse "List of pantry items"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid units or cursor"
 // @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
 // @Failure 500 {object} SwaggerErrorResponse "Internal server error"
 // @Router /pantry [get]
@@ -69,26 +71,18 @@ func (h *PantryHandler) List(w http.ResponseWriter, r *http.Request) {
 		categoryPtr = &normalized
 	}
 
-	// Pagination with SQL (no more in-memory slicing)
-	limit := 100
-	offset := 0
+	page := pageParams(r, 100, 200)
 
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= 200 {
-			limit = val
-		}
-	}
-
-	if o := r.URL.Query().Get("offset"); o != "" {
-		if val, err := strconv.Atoi(o); err == nil && val >= 0 {
-			offset = val
-		}
+	system, convert, err := unitSystemParam(r, user)
+	if err != nil {
+		response.ValidationFailed(w, "units", err.Error())
+		return
 	}
 
-	// SQL-based pagination - efficient for large datasets
-	items, total, err := h.repo.List(ctx, user.ID, categoryPtr, limit, offset)
+	// Keyset pagination - a category's items can continue on the next page
+	items, total, next, err := h.repo.List(ctx, user.ID, categoryPtr, page)
 	if err != nil {
-		response.InternalError(w)
+		writeListError(w, err)
 		return
 	}
 
@@ -103,6 +97,9 @@ func (h *PantryHandler) List(w http.ResponseWriter, r *http.Request) {
 	var groupOrder []string
 
 	for _, item := range items {
+		if convert {
+			convertPantryItemUnits(item, system)
+		}
 		cat := item.Category
 		if g, ok := groupMap[cat]; ok {
 			g.Items = append(g.Items, item)
@@ -122,13 +119,12 @@ func (h *PantryHandler) List(w http.ResponseWriter, r *http.Request) {
 		groups = append(groups, *groupMap[cat])
 	}
 
-	response.OK(w, map[string]interface{}{
+	response.OK(w, withNextCursor(map[string]interface{}{
 		"groups": groups,
 		"total":  total,
 		"count":  len(items),
-		"limit":  limit,
-		"offset": offset,
-	})
+		"limit":  page.Limit,
+	}, next))
 }
 
 // Get handles GET /api/v1/pantry/{id}
@@ -138,8 +134,9 @@ func (h *PantryHandler) List(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Pantry item UUID"
+// @Param units query string false "Convert the quantity; preferred uses the user's setting" Enums(metric, imperial, preferred)
 // @Success 200 {object} SwaggerPantryItem "Pantry item details"
-// @Failure 400 {object} SwaggerErrorResponse "Invalid item ID"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid item ID or units"
 // @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
 // @Failure 404 {object} SwaggerErrorResponse "Pantry item not found"
 // @Router /pantry/{id} [get]
@@ -158,6 +155,12 @@ func (h *PantryHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	system, convert, err := unitSystemParam(r, user)
+	if err != nil {
+		response.ValidationFailed(w, "units", err.Error())
+		return
+	}
+
 	item, err := h.repo.Get(ctx, id, user.ID)
 	if err == model.ErrNotFound {
 		response.NotFound(w, "Pantry item")
@@ -168,6 +171,10 @@ func (h *PantryHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if convert {
+		convertPantryItemUnits(item, system)
+	}
+
 	response.OK(w, item)
 }
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/dishflow/backend/internal/middleware"
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
 	"github.com/google/uuid"
 )
 
@@ -19,11 +20,11 @@ func TestPantryHandler_List(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		qty := 1.0
 		unit := "kg"
-		mockRepo.ListFunc = func(ctx context.Context, uid uuid.UUID, category *string, limit, offset int) ([]*model.PantryItem, int, error) {
+		mockRepo.ListFunc = func(ctx context.Context, uid uuid.UUID, category *string, page pagination.Page) ([]*model.PantryItem, int, string, error) {
 			items := []*model.PantryItem{
 				{ID: uuid.New(), Name: "Salt", Quantity: &qty, Unit: &unit},
 			}
-			return items, len(items), nil
+			return items, len(items), "", nil
 		}
 
 		req := httptest.NewRequest("GET", "/pantry", nil)
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+)
+
+// IngredientSearchResponse is the API response for ingredient search
+type IngredientSearchResponse struct {
+	Include []string               `json:"include"`
+	Exclude []string               `json:"exclude,omitempty"`
+	Results []IngredientSearchItem `json:"results"`
+	Count   int                    `json:"count"`
+}
+
+// IngredientSearchItem is a recipe summary with its ingredient coverage
+type IngredientSearchItem struct {
+	ID              string                        `json:"id"`
+	Title           string                        `json:"title"`
+	Cuisine         string                        `json:"cuisine,omitempty"`
+	ThumbnailURL    string                        `json:"thumbnailUrl,omitempty"`
+	Difficulty      string                        `json:"difficulty,omitempty"`
+	PrepTime        *int                          `json:"prepTime,omitempty"`
+	CookTime        *int                          `json:"cookTime,omitempty"`
+	IsFavorite      bool                          `json:"isFavorite"`
+	InLibrary       bool                          `json:"inLibrary"`
+	IngredientCount int                           `json:"ingredientCount"`
+	Coverage        int                           `json:"coverage"`
+	Matched         []model.IngredientSearchMatch `json:"matched"`
+	Missing         []string                      `json:"missing"`
+}
+
+// SearchByIngredients handles GET /api/v1/recipes/search/ingredients
+// @Summary What can I make with...
+// @Description Find recipes in your library and public recipes that use the given ingredients, ranked by coverage: the share of a recipe's required ingredients you listed. Plurals and whole-word variations match ("chicken" covers "chicken thighs") and common substitutes count. Recipes using an excluded ingredient are left out.
+// @Tags Recipes
+// @Produce json
+// @Security BearerAuth
+// @Param include query string true "Comma-separated ingredients you have (max 10)"
+// @Param exclude query string false "Comma-separated ingredients to avoid (max 10)"
+// @Param scope query string false "Recipes to search" Enums(all, library, public) default(all)
+// @Param limit query int false "Max results (1-50)" default(20)
+// @Success 200 {object} SwaggerIngredientSearchResponse "Matching recipes"
+// @Failure 400 {object} SwaggerErrorResponse "Missing or invalid parameters"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 500 {object} SwaggerErrorResponse "Internal server error"
+// @Router /recipes/search/ingredients [get]
+func (h *RecipeHandler) SearchByIngredients(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	q := r.URL.Query()
+	include := queryList(q["include"])
+	exclude := queryList(q["exclude"])
+	if len(include) == 0 {
+		response.ValidationFailed(w, "include", "at least one ingredient is required")
+		return
+	}
+	if len(include) > model.MaxIngredientSearchTerms {
+		response.ValidationFailed(w, "include", fmt.Sprintf("at most %d ingredients", model.MaxIngredientSearchTerms))
+		return
+	}
+	if len(exclude) > model.MaxIngredientSearchTerms {
+		response.ValidationFailed(w, "exclude", fmt.Sprintf("at most %d ingredients", model.MaxIngredientSearchTerms))
+		return
+	}
+
+	scope := q.Get("scope")
+	switch scope {
+	case "":
+		scope = model.IngredientSearchAll
+	case model.IngredientSearchAll, model.IngredientSearchLibrary, model.IngredientSearchPublic:
+	default:
+		response.ValidationFailed(w, "scope", "must be one of all, library, public")
+		return
+	}
+
+	limit := 20
+	if l := q.Get("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= model.MaxIngredientSearchLimit {
+			limit = val
+		}
+	}
+
+	matches, err := h.repo.SearchByIngredients(r.Context(), user.ID, model.IngredientSearch{
+		Include: include,
+		Exclude: exclude,
+		Scope:   scope,
+		Limit:   limit,
+	})
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	results := make([]IngredientSearchItem, 0, len(matches))
+	for _, m := range matches {
+		item := IngredientSearchItem{
+			ID:              m.Recipe.ID.String(),
+			Title:           m.Recipe.Title,
+			PrepTime:        m.Recipe.PrepTime,
+			CookTime:        m.Recipe.CookTime,
+			IsFavorite:      m.Recipe.IsFavorite,
+			InLibrary:       m.Recipe.UserID == user.ID,
+			IngredientCount: m.Recipe.IngredientCount,
+			Coverage:        m.Coverage,
+			Matched:         m.Matched,
+			Missing:         m.Missing,
+		}
+		if m.Recipe.Cuisine != nil {
+			item.Cuisine = *m.Recipe.Cuisine
+		}
+		if m.Recipe.ThumbnailURL != nil {
+			item.ThumbnailURL = *m.Recipe.ThumbnailURL
+		}
+		if m.Recipe.Difficulty != nil {
+			item.Difficulty = *m.Recipe.Difficulty
+		}
+		if item.Matched == nil {
+			item.Matched = []model.IngredientSearchMatch{}
+		}
+		if item.Missing == nil {
+			item.Missing = []string{}
+		}
+		results = append(results, item)
+	}
+
+	response.OK(w, IngredientSearchResponse{
+		Include: include,
+		Exclude: exclude,
+		Results: results,
+		Count:   len(results),
+	})
+}
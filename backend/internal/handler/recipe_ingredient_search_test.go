@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+)
+
+func TestRecipeHandler_SearchByIngredients(t *testing.T) {
+	userID := uuid.New()
+	withUser := func(req *http.Request) *http.Request {
+		return req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID}))
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		handler := NewRecipeHandler(&mockRecipeRepository{}, &mockAnnotationRepository{}, nil, nil)
+		tests := []struct {
+			name  string
+			query string
+			auth  bool
+			want  int
+		}{
+			{"auth required", "include=rice", false, http.StatusUnauthorized},
+			{"missing include", "exclude=nuts", true, http.StatusBadRequest},
+			{"blank include", "include=,%20,", true, http.StatusBadRequest},
+			{"too many", "include=a,b,c,d,e,f,g,h,i,j,k", true, http.StatusBadRequest},
+			{"bad scope", "include=rice&scope=friends", true, http.StatusBadRequest},
+			{"ok", "include=rice&scope=library", true, http.StatusOK},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest("GET", "/recipes/search/ingredients?"+tt.query, nil)
+				if tt.auth {
+					req = withUser(req)
+				}
+				rr := httptest.NewRecorder()
+				handler.SearchByIngredients(rr, req)
+				if rr.Code != tt.want {
+					t.Errorf("expected %d, got %d: %s", tt.want, rr.Code, rr.Body.String())
+				}
+			})
+		}
+	})
+
+	t.Run("returns coverage", func(t *testing.T) {
+		var got model.IngredientSearch
+		otherUser := uuid.New()
+		repo := &mockRecipeRepository{
+			SearchByIngredientsFunc: func(ctx context.Context, uid uuid.UUID, search model.IngredientSearch) ([]*model.IngredientSearchResult, error) {
+				got = search
+				return []*model.IngredientSearchResult{
+					{
+						Recipe:    &model.Recipe{ID: uuid.New(), UserID: userID, Title: "Chicken Rice", IngredientCount: 2},
+						Coverage:  100,
+						TermsUsed: 2,
+						Matched: []model.IngredientSearchMatch{
+							{Ingredient: "chicken thighs", Term: "chicken"},
+							{Ingredient: "rice", Term: "rice"},
+						},
+					},
+					{
+						Recipe:    &model.Recipe{ID: uuid.New(), UserID: otherUser, Title: "Spinach Pie", IngredientCount: 3},
+						Coverage:  33,
+						TermsUsed: 1,
+						Matched:   []model.IngredientSearchMatch{{Ingredient: "spinach", Term: "Spinach"}},
+						Missing:   []string{"feta", "filo"},
+					},
+				}, nil
+			},
+		}
+		handler := NewRecipeHandler(repo, &mockAnnotationRepository{}, nil, nil)
+
+		req := httptest.NewRequest("GET", "/recipes/search/ingredients?include=chicken,%20rice&include=Spinach,rice&exclude=peanuts&limit=5", nil)
+		rr := httptest.NewRecorder()
+		handler.SearchByIngredients(rr, withUser(req))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		want := model.IngredientSearch{
+			Include: []string{"chicken", "rice", "Spinach"},
+			Exclude: []string{"peanuts"},
+			Scope:   model.IngredientSearchAll,
+			Limit:   5,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected search %+v, got %+v", want, got)
+		}
+
+		var resp IngredientSearchResponse
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Count != 2 || !resp.Results[0].InLibrary || resp.Results[1].InLibrary {
+			t.Errorf("unexpected results %+v", resp.Results)
+		}
+		if resp.Results[0].Missing == nil || len(resp.Results[0].Missing) != 0 {
+			t.Errorf("expected empty missing list, got %v", resp.Results[0].Missing)
+		}
+		if resp.Results[1].Coverage != 33 || len(resp.Results[1].Missing) != 2 {
+			t.Errorf("unexpected coverage %+v", resp.Results[1])
+		}
+	})
+
+	t.Run("repo error returns 500", func(t *testing.T) {
+		repo := &mockRecipeRepository{
+			SearchByIngredientsFunc: func(ctx context.Context, uid uuid.UUID, search model.IngredientSearch) ([]*model.IngredientSearchResult, error) {
+				return nil, errors.New("db error")
+			},
+		}
+		handler := NewRecipeHandler(repo, &mockAnnotationRepository{}, nil, nil)
+		rr := httptest.NewRecorder()
+		handler.SearchByIngredients(rr, withUser(httptest.NewRequest("GET", "/recipes/search/ingredients?include=rice", nil)))
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("expected 500, got %d", rr.Code)
+		}
+	})
+}
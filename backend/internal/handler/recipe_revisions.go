@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
+	"github.com/dishflow/backend/internal/service/revision"
+)
+
+// ListRevisions handles GET /api/v1/recipes/{recipeID}/revisions
+// @Summary List recipe revisions
+// @Description List a recipe's saved revisions, newest first. Revisions from sync conflicts hold the client edit that lost.
+// @Tags Recipes
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Success 200 {object} SwaggerRecipeRevisionList "Revisions"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid recipe ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Router /recipes/{recipeID}/revisions [get]
+func (h *RecipeHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	recipe, ok := ownedRecipe(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.repo.ListRevisions(r.Context(), recipe.ID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"revisions": revisions,
+	})
+}
+
+// GetRevision handles GET /api/v1/recipes/{recipeID}/revisions/{revisionID}
+// @Summary Get a recipe revision
+// @Description Get one revision including the full recipe snapshot
+// @Tags Recipes
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Param revisionID path string true "Revision UUID"
+// @Success 200 {object} SwaggerRecipeRevision "Revision"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe or revision not found"
+// @Router /recipes/{recipeID}/revisions/{revisionID} [get]
+func (h *RecipeHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	recipe, ok := ownedRecipe(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	rev, ok := h.revision(w, r, recipe.ID, chi.URLParam(r, "revisionID"))
+	if !ok {
+		return
+	}
+
+	response.OK(w, rev)
+}
+
+// DiffRevisions handles GET /api/v1/recipes/{recipeID}/revisions/diff
+// @Summary Diff two recipe revisions
+// @Description Compare two revisions of a recipe. When "to" is omitted the revision is compared with the current recipe.
+// @Tags Recipes
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Param from query string true "Revision UUID to compare from"
+// @Param to query string false "Revision UUID to compare to (defaults to the current recipe)"
+// @Success 200 {object} SwaggerRecipeDiff "Differences"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe or revision not found"
+// @Router /recipes/{recipeID}/revisions/diff [get]
+func (h *RecipeHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	recipe, ok := ownedRecipe(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	fromParam := r.URL.Query().Get("from")
+	if fromParam == "" {
+		response.ValidationFailed(w, "from", "is required")
+		return
+	}
+	from, ok := h.revision(w, r, recipe.ID, fromParam)
+	if !ok {
+		return
+	}
+
+	to := recipe
+	var toID *uuid.UUID
+	if toParam := r.URL.Query().Get("to"); toParam != "" {
+		rev, ok := h.revision(w, r, recipe.ID, toParam)
+		if !ok {
+			return
+		}
+		to = rev.Snapshot
+		toID = &rev.ID
+	}
+
+	diff := revision.Diff(from.Snapshot, to)
+	diff.From = from.ID
+	diff.To = toID
+	response.OK(w, diff)
+}
+
+// RestoreRevision handles POST /api/v1/recipes/{recipeID}/revisions/{revisionID}/restore
+// @Summary Restore a recipe revision
+// @Description Replace the recipe's title, details, ingredients and steps with those of an old revision. The restore is saved as a new revision.
+// @Tags Recipes
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Param revisionID path string true "Revision UUID"
+// @Success 200 {object} SwaggerRecipe "Restored recipe"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe or revision not found"
+// @Router /recipes/{recipeID}/revisions/{revisionID}/restore [post]
+func (h *RecipeHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	recipe, ok := ownedRecipe(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	rev, ok := h.revision(w, r, recipe.ID, chi.URLParam(r, "revisionID"))
+	if !ok {
+		return
+	}
+
+	restored := revision.Restore(recipe, rev.Snapshot)
+	if err := h.repo.Restore(r.Context(), restored, rev.ID); err != nil {
+		if errors.Is(err, postgres.ErrRecipeNotFound) {
+			response.NotFound(w, "Recipe")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, restored)
+}
+
+// revision loads one revision of a recipe, writing the error response on failure
+func (h *RecipeHandler) revision(w http.ResponseWriter, r *http.Request, recipeID uuid.UUID, idStr string) (*model.RecipeRevision, bool) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(w, "Invalid revision ID")
+		return nil, false
+	}
+
+	rev, err := h.repo.GetRevision(r.Context(), recipeID, id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrRevisionNotFound) {
+			response.NotFound(w, "Revision")
+			return nil, false
+		}
+		response.InternalError(w)
+		return nil, false
+	}
+	return rev, true
+}
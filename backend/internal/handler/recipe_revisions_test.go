@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/repository/postgres"
+)
+
+func revisionRequest(method, target string, userID, recipeID uuid.UUID, revisionID string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("recipeID", recipeID.String())
+	if revisionID != "" {
+		rctx.URLParams.Add("revisionID", revisionID)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, middleware.UserContextKey, &model.User{ID: userID})
+	return req.WithContext(ctx)
+}
+
+func TestRecipeHandler_Revisions(t *testing.T) {
+	mockRepo := &mockRecipeRepository{}
+	handler := NewRecipeHandler(mockRepo, &mockAnnotationRepository{}, nil, nil)
+	userID := uuid.New()
+	recipeID := uuid.New()
+	revisionID := uuid.New()
+
+	current := func() *model.Recipe {
+		return &model.Recipe{
+			ID: recipeID, UserID: userID, Title: "Crepes", SyncVersion: 4, IsFavorite: true,
+			Ingredients: []model.RecipeIngredient{{Name: "Flour"}},
+		}
+	}
+	old := &model.RecipeRevision{
+		ID: revisionID, RecipeID: recipeID, SyncVersion: 2, Source: model.RevisionSourceUpdate,
+		Snapshot: &model.Recipe{
+			ID: recipeID, UserID: userID, Title: "Pancakes", SyncVersion: 2,
+			Ingredients: []model.RecipeIngredient{{Name: "Flour"}, {Name: "Milk"}},
+		},
+	}
+
+	mockRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return current(), nil
+	}
+	mockRepo.GetRevisionFunc = func(ctx context.Context, rid, id uuid.UUID) (*model.RecipeRevision, error) {
+		if id != revisionID {
+			return nil, postgres.ErrRevisionNotFound
+		}
+		return old, nil
+	}
+
+	t.Run("diff against current", func(t *testing.T) {
+		req := revisionRequest("GET", "/recipes/x/revisions/diff?from="+revisionID.String(), userID, recipeID, "")
+		rr := httptest.NewRecorder()
+		handler.DiffRevisions(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var diff model.RecipeDiff
+		json.NewDecoder(rr.Body).Decode(&diff)
+		if diff.From != revisionID || diff.To != nil {
+			t.Errorf("unexpected diff endpoints: %+v", diff)
+		}
+		if len(diff.Fields) != 1 || diff.Fields[0].Field != "title" {
+			t.Errorf("expected title change, got %+v", diff.Fields)
+		}
+		if len(diff.Ingredients) != 1 || diff.Ingredients[0].Change != model.DiffRemoved || diff.Ingredients[0].Name != "Milk" {
+			t.Errorf("expected Milk removed, got %+v", diff.Ingredients)
+		}
+	})
+
+	t.Run("diff requires from", func(t *testing.T) {
+		req := revisionRequest("GET", "/recipes/x/revisions/diff", userID, recipeID, "")
+		rr := httptest.NewRecorder()
+		handler.DiffRevisions(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		req := revisionRequest("GET", "/recipes/x/revisions/y", userID, recipeID, uuid.New().String())
+		rr := httptest.NewRecorder()
+		handler.GetRevision(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		var saved *model.Recipe
+		var from uuid.UUID
+		mockRepo.RestoreFunc = func(ctx context.Context, recipe *model.Recipe, fromRevisionID uuid.UUID) error {
+			saved, from = recipe, fromRevisionID
+			return nil
+		}
+
+		req := revisionRequest("POST", "/recipes/x/revisions/y/restore", userID, recipeID, revisionID.String())
+		rr := httptest.NewRecorder()
+		handler.RestoreRevision(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if saved == nil || from != revisionID {
+			t.Fatalf("expected restore from %s, got %v", revisionID, from)
+		}
+		if saved.Title != "Pancakes" || len(saved.Ingredients) != 2 {
+			t.Errorf("content not restored: %+v", saved)
+		}
+		if saved.SyncVersion != 4 || !saved.IsFavorite {
+			t.Errorf("sync state should come from the current recipe: %+v", saved)
+		}
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		req := revisionRequest("GET", "/recipes/x/revisions", uuid.New(), recipeID, "")
+		rr := httptest.NewRecorder()
+		handler.ListRevisions(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -15,16 +16,18 @@ import (
 	"github.com/dishflow/backend/internal/model"
 	"github.com/dishflow/backend/internal/pkg/response"
 	"github.com/dishflow/backend/internal/repository/postgres"
+	"github.com/dishflow/backend/internal/service/scaling"
 )
 
 type RecipeHandler struct {
 	repo             RecipeRepository
+	annotationRepo   AnnotationRepository
 	adminEmails      []string
 	inspiratorEmails []string
 }
 
-func NewRecipeHandler(repo RecipeRepository, adminEmails []string, inspiratorEmails []string) *RecipeHandler {
-	return &RecipeHandler{repo: repo, adminEmails: adminEmails, inspiratorEmails: inspiratorEmails}
+func NewRecipeHandler(repo RecipeRepository, annotationRepo AnnotationRepository, adminEmails []string, inspiratorEmails []string) *RecipeHandler {
+	return &RecipeHandler{repo: repo, annotationRepo: annotationRepo, adminEmails: adminEmails, inspiratorEmails: inspiratorEmails}
 }
 
 // Create handles POST /api/v1/recipes
@@ -74,7 +77,9 @@ func (h *RecipeHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate ingredients
-	for i, ing := range req.Ingredients {
+	for i := range req.Ingredients {
+		applyIngredientText(&req.Ingredients[i])
+		ing := req.Ingredients[i]
 		if strings.TrimSpace(ing.Name) == "" {
 			response.BadRequest(w, fmt.Sprintf("Ingredient %d: name is required", i+1))
 			return
@@ -83,6 +88,10 @@ func (h *RecipeHandler) Create(w http.ResponseWriter, r *http.Request) {
 			response.BadRequest(w, fmt.Sprintf("Ingredient %d: quantity cannot be negative", i+1))
 			return
 		}
+		if ing.QuantityMax != nil && (ing.Quantity == nil || *ing.QuantityMax < *ing.Quantity) {
+			response.BadRequest(w, fmt.Sprintf("Ingredient %d: quantityMax must not be less than quantity", i+1))
+			return
+		}
 	}
 
 	// Set user ID from context
@@ -115,11 +124,12 @@ func (h *RecipeHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 // Search handles GET /api/v1/recipes/search
 // @Summary Search recipes
-// @Description Search user's recipes by title, cuisine, description, or tags
+// @Description Search user's recipes by title, tags, cuisine, ingredients and description. Words are stemmed, the last word matches as a prefix, and close misspellings still match. Results are ranked by relevance and include a snippet with the matched words in <mark> tags.
 // @Tags Recipes
 // @Produce json
 // @Security BearerAuth
 // @Param q query string true "Search query (min 1 character)"
+// @Param lang query string false "Language to stem the query for; all when omitted" Enums(en, fr, es)
 // @Param limit query int false "Max results (1-50)" default(10)
 // @Success 200 {object} SwaggerSearchResponse "Search results"
 // @Failure 400 {object} SwaggerErrorResponse "Missing or invalid query"
@@ -140,6 +150,12 @@ func (h *RecipeHandler) Search(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	lang, ok := searchLang(r)
+	if !ok {
+		response.ValidationFailed(w, "lang", "must be one of en, fr, es")
+		return
+	}
+
 	// Parse limit with sensible defaults and bounds
 	limit := 10
 	if l := r.URL.Query().Get("limit"); l != "" {
@@ -149,7 +165,7 @@ func (h *RecipeHandler) Search(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute search
-	recipes, err := h.repo.Search(r.Context(), user.ID, query, limit)
+	recipes, err := h.repo.Search(r.Context(), user.ID, query, lang, limit)
 	if err != nil {
 		// Log error, don't expose details
 		response.InternalError(w)
@@ -168,6 +184,7 @@ func (h *RecipeHandler) Search(w http.ResponseWriter, r *http.Request) {
 			ID:         r.ID.String(),
 			Title:      r.Title,
 			IsFavorite: r.IsFavorite,
+			Snippet:    r.Snippet,
 		}
 		if r.Cuisine != nil {
 			result.Cuisine = *r.Cuisine
@@ -203,6 +220,13 @@ type SearchResult struct {
 	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
 	Difficulty   string `json:"difficulty,omitempty"`
 	IsFavorite   bool   `json:"isFavorite"`
+	Snippet      string `json:"snippet,omitempty"`
+}
+
+// searchLang reads the optional lang query parameter
+func searchLang(r *http.Request) (string, bool) {
+	lang := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("lang")))
+	return lang, lang == "" || slices.Contains(model.SearchLanguages, lang)
 }
 
 // SearchPublic handles GET /api/v1/recipes/search/public — no auth required
@@ -213,6 +237,12 @@ func (h *RecipeHandler) SearchPublic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	lang, ok := searchLang(r)
+	if !ok {
+		response.ValidationFailed(w, "lang", "must be one of en, fr, es")
+		return
+	}
+
 	limit := 15
 	if l := r.URL.Query().Get("limit"); l != "" {
 		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= 50 {
@@ -220,7 +250,7 @@ func (h *RecipeHandler) SearchPublic(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	recipes, err := h.repo.SearchPublic(r.Context(), query, limit)
+	recipes, err := h.repo.SearchPublic(r.Context(), query, lang, limit)
 	if err != nil {
 		response.InternalError(w)
 		return
@@ -233,8 +263,9 @@ func (h *RecipeHandler) SearchPublic(w http.ResponseWriter, r *http.Request) {
 	results := make([]SearchResult, 0, len(recipes))
 	for _, r := range recipes {
 		result := SearchResult{
-			ID:    r.ID.String(),
-			Title: r.Title,
+			ID:      r.ID.String(),
+			Title:   r.Title,
+			Snippet: r.Snippet,
 		}
 		if r.Cuisine != nil {
 			result.Cuisine = *r.Cuisine
@@ -257,36 +288,24 @@ func (h *RecipeHandler) SearchPublic(w http.ResponseWriter, r *http.Request) {
 
 // ListSuggested handles GET /api/v1/recipes/suggested
 // @Summary List suggested/public recipes
-// @Description Get paginated list of curated public recipes available to all users
+// @Description Get a page of curated public recipes available to all users, shuffled; the order stays fixed while paging with nextCursor
 // @Tags Recipes
 // @Produce json
 // @Param limit query int false "Items per page (max 50)" default(20)
-// @Param offset query int false "Pagination offset" default(0)
+// @Param cursor query string false "Cursor from a previous page's nextCursor"
+// @Param offset query int false "Rows to skip, for clients that page by offset; ignored with cursor"
 // @Success 200 {object} SwaggerRecipeListResponse "List of suggested recipes"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid cursor"
 // @Failure 500 {object} SwaggerErrorResponse "Internal server error"
 // @Router /recipes/suggested [get]
 func (h *RecipeHandler) ListSuggested(w http.ResponseWriter, r *http.Request) {
 	// No auth required - public endpoint
 
-	// Pagination
-	limit := 20
-	offset := 0
-
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= 50 {
-			limit = val
-		}
-	}
-
-	if o := r.URL.Query().Get("offset"); o != "" {
-		if val, err := strconv.Atoi(o); err == nil && val >= 0 {
-			offset = val
-		}
-	}
+	page := pageParams(r, 20, 50)
 
-	recipes, total, err := h.repo.ListPublic(r.Context(), limit, offset)
+	recipes, total, next, err := h.repo.ListPublic(r.Context(), page)
 	if err != nil {
-		response.InternalError(w)
+		writeListError(w, err)
 		return
 	}
 
@@ -294,46 +313,33 @@ func (h *RecipeHandler) ListSuggested(w http.ResponseWriter, r *http.Request) {
 		recipes = []*model.Recipe{}
 	}
 
-	response.OK(w, map[string]interface{}{
-		"items":  recipes,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
-	})
+	response.OK(w, withNextCursor(map[string]interface{}{
+		"items": recipes,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
 }
 
 // ListFeatured handles GET /api/v1/recipes/featured
 // @Summary List featured/curated recipes
-// @Description Get paginated list of featured recipes from inspirator creators
+// @Description Get a page of featured recipes from inspirator creators, shuffled; the order stays fixed while paging with nextCursor
 // @Tags Recipes
 // @Produce json
 // @Param limit query int false "Items per page (max 50)" default(30)
-// @Param offset query int false "Pagination offset" default(0)
+// @Param cursor query string false "Cursor from a previous page's nextCursor"
+// @Param offset query int false "Rows to skip, for clients that page by offset; ignored with cursor"
 // @Success 200 {object} SwaggerRecipeListResponse "List of featured recipes"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid cursor"
 // @Failure 500 {object} SwaggerErrorResponse "Internal server error"
 // @Router /recipes/featured [get]
 func (h *RecipeHandler) ListFeatured(w http.ResponseWriter, r *http.Request) {
 	// No auth required - public endpoint
 
-	// Pagination
-	limit := 30
-	offset := 0
+	page := pageParams(r, 30, 50)
 
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= 50 {
-			limit = val
-		}
-	}
-
-	if o := r.URL.Query().Get("offset"); o != "" {
-		if val, err := strconv.Atoi(o); err == nil && val >= 0 {
-			offset = val
-		}
-	}
-
-	recipes, total, err := h.repo.ListFeatured(r.Context(), limit, offset)
+	recipes, total, next, err := h.repo.ListFeatured(r.Context(), page)
 	if err != nil {
-		response.InternalError(w)
+		writeListError(w, err)
 		return
 	}
 
@@ -341,23 +347,35 @@ func (h *RecipeHandler) ListFeatured(w http.ResponseWriter, r *http.Request) {
 		recipes = []*model.Recipe{}
 	}
 
-	response.OK(w, map[string]interface{}{
-		"items":  recipes,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
-	})
+	response.OK(w, withNextCursor(map[string]interface{}{
+		"items": recipes,
+		"total": total,
+		"limit": page.Limit,
+	}, next))
 }
 
 // List handles GET /api/v1/recipes
 // @Summary List user's recipes
-// @Description Get paginated list of user's recipes
+// @Description Get a filtered, sorted page of the user's recipes. List filters accept comma-separated values; cuisine, difficulty and sourceType match any value, tag and diet must all match. Facet counts for filter chips are included unless facets=false; each facet is counted with the other filters applied.
 // @Tags Recipes
 // @Produce json
 // @Security BearerAuth
 // @Param limit query int false "Items per page (max 50)" default(20)
-// @Param offset query int false "Pagination offset" default(0)
+// @Param cursor query string false "Cursor from a previous page's nextCursor"
+// @Param offset query int false "Rows to skip, for clients that page by offset; ignored with cursor"
+// @Param cuisine query string false "Cuisines, e.g. italian,thai"
+// @Param difficulty query string false "Difficulties" Enums(easy, medium, hard)
+// @Param sourceType query string false "Source types, e.g. video,manual"
+// @Param tag query string false "Tags the recipe must all have"
+// @Param diet query string false "Diets the recipe must all fit" Enums(vegetarian, vegan, gluten-free, dairy-free, nut-free, keto, halal, kosher)
+// @Param excludeAllergens query string false "Allergens to avoid, e.g. nuts,dairy"
+// @Param maxTotalTime query int false "Max prep + cook time in minutes"
+// @Param maxCalories query int false "Max calories per serving"
+// @Param favorites query bool false "Only favorites"
+// @Param sort query string false "Sort order" Enums(recent, title, time, calories, most-cooked, last-cooked) default(recent)
+// @Param facets query bool false "Include facet counts" default(true)
 // @Success 200 {object} SwaggerRecipeListResponse "List of recipes"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid filter or cursor"
 // @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
 // @Failure 500 {object} SwaggerErrorResponse "Internal server error"
 // @Router /recipes [get]
@@ -368,25 +386,16 @@ func (h *RecipeHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Pagination
-	limit := 20
-	offset := 0
+	page := pageParams(r, 20, 50)
 
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= 50 {
-			limit = val
-		}
-	}
-
-	if o := r.URL.Query().Get("offset"); o != "" {
-		if val, err := strconv.Atoi(o); err == nil && val >= 0 {
-			offset = val
-		}
+	filter, ok := recipeFilter(w, r)
+	if !ok {
+		return
 	}
 
-	recipes, total, err := h.repo.ListByUser(r.Context(), user.ID, limit, offset)
+	recipes, total, next, err := h.repo.ListByUser(r.Context(), user.ID, filter, page)
 	if err != nil {
-		response.InternalError(w)
+		writeListError(w, err)
 		return
 	}
 
@@ -395,12 +404,75 @@ func (h *RecipeHandler) List(w http.ResponseWriter, r *http.Request) {
 		recipes = []*model.Recipe{}
 	}
 
-	response.OK(w, map[string]interface{}{
-		"items":  recipes,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
-	})
+	result := withNextCursor(map[string]interface{}{
+		"items": recipes,
+		"total": total,
+		"limit": page.Limit,
+	}, next)
+
+	if r.URL.Query().Get("facets") != "false" {
+		facets, err := h.repo.Facets(r.Context(), user.ID, filter)
+		if err != nil {
+			response.InternalError(w)
+			return
+		}
+		result["facets"] = facets
+	}
+
+	response.OK(w, result)
+}
+
+// recipeFilter reads list filters and sort from the query, writing a
+// validation error and returning false when one is invalid
+func recipeFilter(w http.ResponseWriter, r *http.Request) (model.RecipeFilter, bool) {
+	q := r.URL.Query()
+	filter := model.RecipeFilter{
+		Cuisines:         queryList(q["cuisine"]),
+		Difficulties:     queryList(q["difficulty"]),
+		SourceTypes:      queryList(q["sourceType"]),
+		Tags:             queryList(q["tag"]),
+		Diets:            queryList(q["diet"]),
+		ExcludeAllergens: queryList(q["excludeAllergens"]),
+		FavoritesOnly:    q.Get("favorites") == "true",
+		Sort:             q.Get("sort"),
+	}
+
+	for _, d := range filter.Difficulties {
+		if !slices.Contains([]string{"easy", "medium", "hard"}, strings.ToLower(d)) {
+			response.ValidationFailed(w, "difficulty", "must be easy, medium or hard")
+			return filter, false
+		}
+	}
+	for _, diet := range filter.Diets {
+		if _, ok := model.DietFlags[strings.ToLower(diet)]; !ok {
+			response.ValidationFailed(w, "diet", "must be one of vegetarian, vegan, gluten-free, dairy-free, nut-free, keto, halal, kosher")
+			return filter, false
+		}
+	}
+	if filter.Sort != "" && !slices.Contains(model.RecipeSorts, filter.Sort) {
+		response.ValidationFailed(w, "sort", "must be one of "+strings.Join(model.RecipeSorts, ", "))
+		return filter, false
+	}
+
+	limits := []struct {
+		field  string
+		target *int
+	}{
+		{"maxTotalTime", &filter.MaxTotalTime},
+		{"maxCalories", &filter.MaxCalories},
+	}
+	for _, l := range limits {
+		if v := q.Get(l.field); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				response.ValidationFailed(w, l.field, "must be a positive number")
+				return filter, false
+			}
+			*l.target = n
+		}
+	}
+
+	return filter, true
 }
 
 // Get handles GET /api/v1/recipes/{recipeID}
@@ -410,8 +482,10 @@ func (h *RecipeHandler) List(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param recipeID path string true "Recipe UUID"
-// @Success 200 {object} SwaggerRecipe "Recipe details"
-// @Failure 400 {object} SwaggerErrorResponse "Invalid recipe ID"
+// @Param servings query int false "Scale ingredient quantities to this many servings (1-100)"
+// @Param units query string false "Convert quantities and temperatures; preferred uses the user's setting" Enums(metric, imperial, preferred)
+// @Success 200 {object} SwaggerRecipe "Recipe details, with the user's notes on its ingredients and steps"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid recipe ID, servings or units"
 // @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
 // @Failure 403 {object} SwaggerErrorResponse "Access denied"
 // @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
@@ -445,6 +519,41 @@ func (h *RecipeHandler) Get(w http.ResponseWriter, r *http.Request) {
 		response.Forbidden(w, "Access denied")
 		return
 	}
+	if recipe.UserID != user.ID {
+		recipe.HideCookStats()
+	}
+
+	// The user's own notes, inline on the ingredients and steps
+	annotations, err := h.annotationRepo.ListByRecipe(r.Context(), recipe.ID, user.ID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+	recipe.AttachAnnotations(annotations)
+
+	// Optional server-side scaling
+	if v := r.URL.Query().Get("servings"); v != "" {
+		servings, err := strconv.Atoi(v)
+		if err != nil || servings < 1 || servings > scaling.MaxServings {
+			response.ValidationFailed(w, "servings", fmt.Sprintf("must be between 1 and %d", scaling.MaxServings))
+			return
+		}
+		scaled, err := scaling.Recipe(recipe, servings)
+		if err != nil {
+			response.ValidationFailed(w, "servings", err.Error())
+			return
+		}
+		recipe = scaled
+	}
+
+	system, convert, err := unitSystemParam(r, user)
+	if err != nil {
+		response.ValidationFailed(w, "units", err.Error())
+		return
+	}
+	if convert {
+		recipe = convertRecipeUnits(recipe, system)
+	}
 
 	response.OK(w, recipe)
 }
@@ -515,7 +624,9 @@ func (h *RecipeHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate ingredients
-	for i, ing := range req.Ingredients {
+	for i := range req.Ingredients {
+		applyIngredientText(&req.Ingredients[i])
+		ing := req.Ingredients[i]
 		if strings.TrimSpace(ing.Name) == "" {
 			response.BadRequest(w, fmt.Sprintf("Ingredient %d: name is required", i+1))
 			return
@@ -524,6 +635,10 @@ func (h *RecipeHandler) Update(w http.ResponseWriter, r *http.Request) {
 			response.BadRequest(w, fmt.Sprintf("Ingredient %d: quantity cannot be negative", i+1))
 			return
 		}
+		if ing.QuantityMax != nil && (ing.Quantity == nil || *ing.QuantityMax < *ing.Quantity) {
+			response.BadRequest(w, fmt.Sprintf("Ingredient %d: quantityMax must not be less than quantity", i+1))
+			return
+		}
 	}
 
 	// Ensure IDs match
@@ -716,11 +831,23 @@ func (h *RecipeHandler) Clone(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create clone
+	clone := cloneRecipe(source, user.ID)
+	if err := h.repo.Create(r.Context(), clone); err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.Created(w, clone)
+}
+
+// cloneRecipe copies a recipe, with its ingredients and steps, into userID's
+// library. The copy remembers where it came from via SourceRecipeID.
+func cloneRecipe(source *model.Recipe, userID uuid.UUID) *model.Recipe {
+	sourceID := source.ID
 	now := time.Now().UTC()
 	clone := &model.Recipe{
 		ID:             uuid.New(),
-		UserID:         user.ID,
+		UserID:         userID,
 		Title:          source.Title,
 		Description:    source.Description,
 		Servings:       source.Servings,
@@ -746,6 +873,7 @@ func (h *RecipeHandler) Clone(w http.ResponseWriter, r *http.Request) {
 			RecipeID:       clone.ID,
 			Name:           ing.Name,
 			Quantity:       ing.Quantity,
+			QuantityMax:    ing.QuantityMax,
 			Unit:           ing.Unit,
 			Category:       ing.Category,
 			Section:        ing.Section,
@@ -773,10 +901,5 @@ func (h *RecipeHandler) Clone(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	if err := h.repo.Create(r.Context(), clone); err != nil {
-		response.InternalError(w)
-		return
-	}
-
-	response.Created(w, clone)
+	return clone
 }
@@ -6,17 +6,20 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/dishflow/backend/internal/middleware"
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 func TestRecipeHandler_List(t *testing.T) {
 	mockRepo := &mockRecipeRepository{}
-	handler := NewRecipeHandler(mockRepo, nil, nil)
+	handler := NewRecipeHandler(mockRepo, &mockAnnotationRepository{}, nil, nil)
 	userID := uuid.New()
 
 	t.Run("auth required", func(t *testing.T) {
@@ -29,11 +32,11 @@ func TestRecipeHandler_List(t *testing.T) {
 	})
 
 	t.Run("success", func(t *testing.T) {
-		mockRepo.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, limit, offset int) ([]*model.Recipe, int, error) {
+		mockRepo.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
 			if uid != userID {
 				t.Errorf("expected userID %s, got %s", userID, uid)
 			}
-			return []*model.Recipe{{ID: uuid.New(), Title: "Test Recipe"}}, 1, nil
+			return []*model.Recipe{{ID: uuid.New(), Title: "Test Recipe"}}, 1, "", nil
 		}
 
 		req := httptest.NewRequest("GET", "/recipes", nil)
@@ -57,8 +60,8 @@ func TestRecipeHandler_List(t *testing.T) {
 	})
 
 	t.Run("repo error", func(t *testing.T) {
-		mockRepo.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, limit, offset int) ([]*model.Recipe, int, error) {
-			return nil, 0, errors.New("db error")
+		mockRepo.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+			return nil, 0, "", errors.New("db error")
 		}
 
 		req := httptest.NewRequest("GET", "/recipes", nil)
@@ -71,11 +74,200 @@ func TestRecipeHandler_List(t *testing.T) {
 			t.Errorf("expected 500, got %d", rr.Code)
 		}
 	})
+
+	t.Run("cursor paging", func(t *testing.T) {
+		var got pagination.Page
+		mockRepo.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+			got = page
+			return []*model.Recipe{{ID: uuid.New(), Title: "Test Recipe"}}, 3, "page-3", nil
+		}
+
+		req := httptest.NewRequest("GET", "/recipes?limit=1&cursor=page-2&facets=false", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		rr := httptest.NewRecorder()
+
+		handler.List(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if got != (pagination.Page{Limit: 1, Cursor: "page-2"}) {
+			t.Errorf("expected limit 1 and cursor page-2, got %+v", got)
+		}
+		var resp map[string]interface{}
+		json.NewDecoder(rr.Body).Decode(&resp)
+		if resp["nextCursor"] != "page-3" {
+			t.Errorf("expected nextCursor page-3, got %v", resp["nextCursor"])
+		}
+		if _, ok := resp["offset"]; ok {
+			t.Error("offset should no longer be returned")
+		}
+	})
+
+	t.Run("offset paging for older clients", func(t *testing.T) {
+		var got pagination.Page
+		mockRepo.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+			got = page
+			return []*model.Recipe{}, 0, "", nil
+		}
+
+		for target, want := range map[string]int{
+			"/recipes?limit=20&offset=40&facets=false": 40,
+			"/recipes?limit=20&offset=0&facets=false":  0,
+		} {
+			req := httptest.NewRequest("GET", target, nil)
+			ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+			handler.List(httptest.NewRecorder(), req.WithContext(ctx))
+
+			if !got.ByOffset() || *got.Offset != want {
+				t.Errorf("%s: expected offset %d, got %+v", target, want, got)
+			}
+		}
+
+		req := httptest.NewRequest("GET", "/recipes?offset=40&cursor=page-2&facets=false", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		handler.List(httptest.NewRecorder(), req.WithContext(ctx))
+		if got.ByOffset() {
+			t.Errorf("cursor should win over offset, got %+v", got)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		mockRepo.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+			return nil, 0, "", pagination.ErrInvalidCursor
+		}
+
+		req := httptest.NewRequest("GET", "/recipes?cursor=garbage", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		rr := httptest.NewRecorder()
+
+		handler.List(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("filters, sort and facets", func(t *testing.T) {
+		var got model.RecipeFilter
+		mockRepo.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+			got = filter
+			return []*model.Recipe{}, 0, "", nil
+		}
+		mockRepo.FacetsFunc = func(ctx context.Context, uid uuid.UUID, filter model.RecipeFilter) (*model.RecipeFacets, error) {
+			return &model.RecipeFacets{Cuisines: []model.FacetCount{{Value: "Thai", Count: 3}}}, nil
+		}
+
+		req := httptest.NewRequest("GET", "/recipes?cuisine=thai,italian&diet=vegan&diet=gluten-free&excludeAllergens=nuts&maxTotalTime=30&maxCalories=600&sourceType=video&favorites=true&sort=time", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		rr := httptest.NewRecorder()
+
+		handler.List(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		want := model.RecipeFilter{
+			Cuisines:         []string{"thai", "italian"},
+			SourceTypes:      []string{"video"},
+			Diets:            []string{"vegan", "gluten-free"},
+			ExcludeAllergens: []string{"nuts"},
+			MaxTotalTime:     30,
+			MaxCalories:      600,
+			FavoritesOnly:    true,
+			Sort:             model.RecipeSortTime,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected filter %+v, got %+v", want, got)
+		}
+
+		var resp struct {
+			Facets *model.RecipeFacets `json:"facets"`
+		}
+		json.NewDecoder(rr.Body).Decode(&resp)
+		if resp.Facets == nil || len(resp.Facets.Cuisines) != 1 || resp.Facets.Cuisines[0].Count != 3 {
+			t.Errorf("expected facets, got %+v", resp.Facets)
+		}
+	})
+
+	t.Run("facets can be skipped", func(t *testing.T) {
+		mockRepo.FacetsFunc = func(ctx context.Context, uid uuid.UUID, filter model.RecipeFilter) (*model.RecipeFacets, error) {
+			t.Error("facets should not be loaded")
+			return nil, nil
+		}
+		req := httptest.NewRequest("GET", "/recipes?facets=false", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		rr := httptest.NewRecorder()
+
+		handler.List(rr, req.WithContext(ctx))
+
+		var resp map[string]interface{}
+		json.NewDecoder(rr.Body).Decode(&resp)
+		if _, ok := resp["facets"]; ok {
+			t.Errorf("expected no facets, got %v", resp["facets"])
+		}
+		mockRepo.FacetsFunc = nil
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		for _, query := range []string{"diet=paleo", "difficulty=extreme", "sort=rating", "maxTotalTime=-5", "maxCalories=lots"} {
+			req := httptest.NewRequest("GET", "/recipes?"+query, nil)
+			ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+			rr := httptest.NewRecorder()
+
+			handler.List(rr, req.WithContext(ctx))
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", query, rr.Code)
+			}
+		}
+	})
+}
+
+func TestRecipeHandler_Create(t *testing.T) {
+	mockRepo := &mockRecipeRepository{}
+	handler := NewRecipeHandler(mockRepo, &mockAnnotationRepository{}, nil, nil)
+	userID := uuid.New()
+
+	t.Run("parses ingredient text", func(t *testing.T) {
+		var created *model.Recipe
+		mockRepo.CreateFunc = func(ctx context.Context, recipe *model.Recipe) error {
+			created = recipe
+			return nil
+		}
+
+		body := `{"title":"Aglio e olio","ingredients":[
+			{"text":"2-3 cloves garlic, thinly sliced"},
+			{"text":"½ tsp chili flakes (optional)"},
+			{"name":"Spaghetti","text":"400 g pasta","unit":"grams"}
+		]}`
+		req := httptest.NewRequest("POST", "/recipes", strings.NewReader(body))
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		rr := httptest.NewRecorder()
+		handler.Create(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		garlic := created.Ingredients[0]
+		if garlic.Name != "garlic" || *garlic.Quantity != 2 || *garlic.QuantityMax != 3 ||
+			*garlic.Unit != "cloves" || *garlic.Notes != "thinly sliced" || garlic.Text != "" {
+			t.Errorf("unexpected garlic: %+v", garlic)
+		}
+		if chili := created.Ingredients[1]; !chili.IsOptional || *chili.Quantity != 0.5 {
+			t.Errorf("unexpected chili: %+v", chili)
+		}
+		// Explicit fields win over the parsed text
+		if pasta := created.Ingredients[2]; pasta.Name != "Spaghetti" || *pasta.Unit != "grams" || *pasta.Quantity != 400 {
+			t.Errorf("unexpected pasta: %+v", pasta)
+		}
+	})
 }
 
 func TestRecipeHandler_Get(t *testing.T) {
 	mockRepo := &mockRecipeRepository{}
-	handler := NewRecipeHandler(mockRepo, nil, nil)
+	mockAnnotationRepo := &mockAnnotationRepository{}
+	handler := NewRecipeHandler(mockRepo, mockAnnotationRepo, nil, nil)
 	userID := uuid.New()
 	recipeID := uuid.New()
 
@@ -120,11 +312,148 @@ func TestRecipeHandler_Get(t *testing.T) {
 			t.Errorf("expected 403, got %d", rr.Code)
 		}
 	})
+
+	t.Run("scaled to servings", func(t *testing.T) {
+		servings, eggs := 6, 1.0
+		mockRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+			return &model.Recipe{
+				ID: recipeID, UserID: userID, Servings: &servings,
+				Ingredients: []model.RecipeIngredient{{Name: "eggs", Quantity: &eggs}},
+			}, nil
+		}
+
+		req := httptest.NewRequest("GET", "/recipes/"+recipeID.String()+"?servings=2", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("recipeID", recipeID.String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+
+		rr := httptest.NewRecorder()
+		handler.Get(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		var recipe model.Recipe
+		json.NewDecoder(rr.Body).Decode(&recipe)
+		if recipe.Scaling == nil || *recipe.Servings != 2 || recipe.Ingredients[0].DisplayQuantity != "1/2" {
+			t.Errorf("unexpected scaled recipe: servings %v, scaling %+v, ingredients %+v",
+				recipe.Servings, recipe.Scaling, recipe.Ingredients)
+		}
+	})
+
+	t.Run("preferred units", func(t *testing.T) {
+		flour, temp := 2.0, "180°C"
+		mockRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+			return &model.Recipe{
+				ID: recipeID, UserID: userID,
+				Ingredients: []model.RecipeIngredient{{Name: "flour", Quantity: &flour, Unit: stringPtr("cups")}},
+				Steps:       []model.RecipeStep{{Instruction: "Bake", Temperature: &temp}},
+			}, nil
+		}
+
+		req := httptest.NewRequest("GET", "/recipes/"+recipeID.String()+"?units=preferred", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("recipeID", recipeID.String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID, PreferredUnitSystem: "metric"})
+
+		rr := httptest.NewRecorder()
+		handler.Get(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		var recipe model.Recipe
+		json.NewDecoder(rr.Body).Decode(&recipe)
+		ing := recipe.Ingredients[0]
+		if *ing.Quantity != 250 || *ing.Unit != "g" || *recipe.Steps[0].Temperature != "180°C" {
+			t.Errorf("unexpected converted recipe: %v %s, %s", *ing.Quantity, *ing.Unit, *recipe.Steps[0].Temperature)
+		}
+		if temp != "180°C" || flour != 2 {
+			t.Error("Get modified the repository's recipe")
+		}
+	})
+
+	t.Run("notes inline", func(t *testing.T) {
+		saltID, stepID := uuid.New(), uuid.New()
+		mockRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+			return &model.Recipe{
+				ID: recipeID, UserID: uuid.New(), IsPublic: true,
+				Ingredients: []model.RecipeIngredient{{ID: saltID, Name: "Salt"}},
+				Steps:       []model.RecipeStep{{ID: stepID, StepNumber: 1, Instruction: "Season"}},
+			}, nil
+		}
+		mockAnnotationRepo.ListByRecipeFunc = func(ctx context.Context, rID, uID uuid.UUID) ([]model.RecipeAnnotation, error) {
+			if uID != userID {
+				t.Errorf("expected notes of user %s, got %s", userID, uID)
+			}
+			return []model.RecipeAnnotation{
+				{Target: model.AnnotationTargetIngredient, TargetID: saltID, Note: "Use less salt"},
+				{Target: model.AnnotationTargetStep, TargetID: uuid.New(), Anchor: "sear", Note: "Hotter pan"},
+			}, nil
+		}
+		defer func() { mockAnnotationRepo.ListByRecipeFunc = nil }()
+
+		req := httptest.NewRequest("GET", "/recipes/"+recipeID.String()+"", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("recipeID", recipeID.String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+
+		rr := httptest.NewRecorder()
+		handler.Get(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		var recipe model.Recipe
+		json.NewDecoder(rr.Body).Decode(&recipe)
+		if a := recipe.Ingredients[0].Annotation; a == nil || a.Note != "Use less salt" {
+			t.Errorf("ingredient annotation = %+v", a)
+		}
+		if recipe.Steps[0].Annotation != nil {
+			t.Errorf("step annotation = %+v, want none", recipe.Steps[0].Annotation)
+		}
+		if len(recipe.DetachedAnnotations) != 1 || recipe.DetachedAnnotations[0].Note != "Hotter pan" {
+			t.Errorf("detached = %+v", recipe.DetachedAnnotations)
+		}
+	})
+
+	t.Run("invalid units", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/recipes/"+recipeID.String()+"?units=cubits", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("recipeID", recipeID.String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+
+		rr := httptest.NewRecorder()
+		handler.Get(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("invalid servings", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/recipes/"+recipeID.String()+"?servings=0", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("recipeID", recipeID.String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+
+		rr := httptest.NewRecorder()
+		handler.Get(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
 }
 
 func TestRecipeHandler_Search(t *testing.T) {
 	mockRepo := &mockRecipeRepository{}
-	handler := NewRecipeHandler(mockRepo, nil, nil)
+	handler := NewRecipeHandler(mockRepo, &mockAnnotationRepository{}, nil, nil)
 	userID := uuid.New()
 
 	t.Run("auth required", func(t *testing.T) {
@@ -162,7 +491,7 @@ func TestRecipeHandler_Search(t *testing.T) {
 
 	t.Run("successful search", func(t *testing.T) {
 		cuisine := "Italian"
-		mockRepo.SearchFunc = func(ctx context.Context, uid uuid.UUID, query string, limit int) ([]*model.Recipe, error) {
+		mockRepo.SearchFunc = func(ctx context.Context, uid uuid.UUID, query, lang string, limit int) ([]*model.Recipe, error) {
 			if uid != userID {
 				t.Errorf("expected userID %s, got %s", userID, uid)
 			}
@@ -205,7 +534,7 @@ func TestRecipeHandler_Search(t *testing.T) {
 	})
 
 	t.Run("empty results", func(t *testing.T) {
-		mockRepo.SearchFunc = func(ctx context.Context, uid uuid.UUID, query string, limit int) ([]*model.Recipe, error) {
+		mockRepo.SearchFunc = func(ctx context.Context, uid uuid.UUID, query, lang string, limit int) ([]*model.Recipe, error) {
 			return []*model.Recipe{}, nil
 		}
 
@@ -228,7 +557,7 @@ func TestRecipeHandler_Search(t *testing.T) {
 	})
 
 	t.Run("respects limit parameter", func(t *testing.T) {
-		mockRepo.SearchFunc = func(ctx context.Context, uid uuid.UUID, query string, limit int) ([]*model.Recipe, error) {
+		mockRepo.SearchFunc = func(ctx context.Context, uid uuid.UUID, query, lang string, limit int) ([]*model.Recipe, error) {
 			if limit != 5 {
 				t.Errorf("expected limit 5, got %d", limit)
 			}
@@ -247,7 +576,7 @@ func TestRecipeHandler_Search(t *testing.T) {
 	})
 
 	t.Run("repo error returns 500", func(t *testing.T) {
-		mockRepo.SearchFunc = func(ctx context.Context, uid uuid.UUID, query string, limit int) ([]*model.Recipe, error) {
+		mockRepo.SearchFunc = func(ctx context.Context, uid uuid.UUID, query, lang string, limit int) ([]*model.Recipe, error) {
 			return nil, errors.New("db error")
 		}
 
@@ -261,4 +590,42 @@ func TestRecipeHandler_Search(t *testing.T) {
 			t.Errorf("expected 500, got %d", rr.Code)
 		}
 	})
+
+	t.Run("passes language and returns snippet", func(t *testing.T) {
+		mockRepo.SearchFunc = func(ctx context.Context, uid uuid.UUID, query, lang string, limit int) ([]*model.Recipe, error) {
+			if lang != "fr" {
+				t.Errorf("expected lang 'fr', got '%s'", lang)
+			}
+			return []*model.Recipe{
+				{ID: uuid.New(), Title: "Poulet rôti", Snippet: "<mark>poulet</mark>, thym, ail"},
+			}, nil
+		}
+
+		req := httptest.NewRequest("GET", "/recipes/search?q=poulet&lang=FR", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		rr := httptest.NewRecorder()
+
+		handler.Search(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp SearchResponse
+		json.NewDecoder(rr.Body).Decode(&resp)
+		if len(resp.Results) != 1 || resp.Results[0].Snippet != "<mark>poulet</mark>, thym, ail" {
+			t.Errorf("expected snippet in results, got %+v", resp.Results)
+		}
+	})
+
+	t.Run("unsupported language", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/recipes/search?q=test&lang=de", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		rr := httptest.NewRecorder()
+
+		handler.Search(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
 }
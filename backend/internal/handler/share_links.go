@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
+	"github.com/dishflow/backend/internal/service/export"
+)
+
+// ShareLinkHandler handles public recipe share links
+type ShareLinkHandler struct {
+	linkRepo   ShareLinkRepository
+	recipeRepo RecipeRepository
+	baseURL    string
+}
+
+// NewShareLinkHandler creates a new share link handler. baseURL is the public
+// origin the /r/{token} pages are served from.
+func NewShareLinkHandler(linkRepo ShareLinkRepository, recipeRepo RecipeRepository, baseURL string) *ShareLinkHandler {
+	return &ShareLinkHandler{
+		linkRepo:   linkRepo,
+		recipeRepo: recipeRepo,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// GetLink handles GET /api/v1/recipes/{recipeID}/link
+// @Summary Get a recipe's public link
+// @Description Get the active public link for one of your recipes, with its view count
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Success 200 {object} SwaggerRecipeShareLink "Share link"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe or link not found"
+// @Router /recipes/{recipeID}/link [get]
+func (h *ShareLinkHandler) GetLink(w http.ResponseWriter, r *http.Request) {
+	recipe, ok := ownedRecipe(w, r, h.recipeRepo)
+	if !ok {
+		return
+	}
+
+	link, err := h.linkRepo.GetActive(r.Context(), recipe.ID)
+	if err != nil {
+		writeShareLinkError(w, err)
+		return
+	}
+
+	link.URL = h.pageURL(link.Token)
+	response.OK(w, link)
+}
+
+// CreateLink handles POST /api/v1/recipes/{recipeID}/link
+// @Summary Create a public link
+// @Description Create an unguessable public link to one of your recipes that anyone can open without the app. Returns the existing link if the recipe already has one.
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Success 200 {object} SwaggerRecipeShareLink "Share link"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Router /recipes/{recipeID}/link [post]
+func (h *ShareLinkHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	recipe, ok := ownedRecipe(w, r, h.recipeRepo)
+	if !ok {
+		return
+	}
+
+	link, err := h.linkRepo.Create(r.Context(), recipe.ID, recipe.UserID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	link.URL = h.pageURL(link.Token)
+	response.OK(w, link)
+}
+
+// RevokeLink handles DELETE /api/v1/recipes/{recipeID}/link
+// @Summary Disable a public link
+// @Description Disable the recipe's public link. The link stops working immediately; creating a new one issues a different URL.
+// @Tags Sharing
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Success 204 "Link disabled"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe or link not found"
+// @Router /recipes/{recipeID}/link [delete]
+func (h *ShareLinkHandler) RevokeLink(w http.ResponseWriter, r *http.Request) {
+	recipe, ok := ownedRecipe(w, r, h.recipeRepo)
+	if !ok {
+		return
+	}
+
+	if err := h.linkRepo.Revoke(r.Context(), recipe.ID); err != nil {
+		writeShareLinkError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Page handles GET /r/{token}, rendering the shared recipe as a web page
+// with schema.org JSON-LD and Open Graph tags for link previews
+func (h *ShareLinkHandler) Page(w http.ResponseWriter, r *http.Request) {
+	link, recipe, err := h.resolve(r)
+	// Never cache, so disabling a link takes effect immediately
+	w.Header().Set("Cache-Control", "no-store")
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, postgres.ErrShareLinkNotFound) || errors.Is(err, postgres.ErrRecipeNotFound) {
+			status = http.StatusNotFound
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		_ = recipePageTemplate.Execute(w, nil)
+		return
+	}
+
+	url := h.pageURL(link.Token)
+	jsonLD, err := json.Marshal(export.JSONLD(recipe, url))
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	page := recipePage{
+		Recipe:  recipe,
+		URL:     url,
+		JSONURL: h.baseURL + "/api/v1/r/" + link.Token,
+		JSONLD:  template.JS(jsonLD), // json.Marshal escapes <, > and &
+	}
+	if recipe.Description != nil {
+		page.Description = *recipe.Description
+	}
+	for _, ing := range recipe.Ingredients {
+		page.Ingredients = append(page.Ingredients, export.IngredientLine(ing))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := recipePageTemplate.Execute(w, page); err != nil {
+		response.InternalError(w)
+	}
+}
+
+// PageJSON handles GET /api/v1/r/{token}
+// @Summary Get a recipe by public link
+// @Description Resolve a public share link to its recipe, for opening shared links in the app. Counts as a view.
+// @Tags Sharing
+// @Produce json
+// @Param token path string true "Share link token"
+// @Success 200 {object} SwaggerSharedRecipeResponse "Shared recipe"
+// @Failure 404 {object} SwaggerErrorResponse "Link not found or disabled"
+// @Router /r/{token} [get]
+func (h *ShareLinkHandler) PageJSON(w http.ResponseWriter, r *http.Request) {
+	link, recipe, err := h.resolve(r)
+	w.Header().Set("Cache-Control", "no-store")
+	if err != nil {
+		if errors.Is(err, postgres.ErrShareLinkNotFound) || errors.Is(err, postgres.ErrRecipeNotFound) {
+			response.NotFound(w, "Share link")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"recipe": recipe,
+		"url":    h.pageURL(link.Token),
+	})
+}
+
+// resolve looks up an active link by token, counting the view, and loads
+// its recipe without the owner's personal fields
+func (h *ShareLinkHandler) resolve(r *http.Request) (*model.RecipeShareLink, *model.Recipe, error) {
+	token := chi.URLParam(r, "token")
+	if token == "" || len(token) > 64 {
+		return nil, nil, postgres.ErrShareLinkNotFound
+	}
+
+	link, err := h.linkRepo.RecordView(r.Context(), token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recipe, err := h.recipeRepo.GetByID(r.Context(), link.RecipeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	recipe.IsFavorite = false
+	recipe.SourceMetadata = nil
+	recipe.HideCookStats()
+	return link, recipe, nil
+}
+
+func (h *ShareLinkHandler) pageURL(token string) string {
+	return h.baseURL + "/r/" + token
+}
+
+// writeShareLinkError maps share link repository errors to responses
+func writeShareLinkError(w http.ResponseWriter, err error) {
+	if errors.Is(err, postgres.ErrShareLinkNotFound) {
+		response.NotFound(w, "Share link")
+		return
+	}
+	response.InternalError(w)
+}
+
+// recipePage is the data for recipePageTemplate; a nil page renders the
+// not found message
+type recipePage struct {
+	Recipe      *model.Recipe
+	URL         string
+	JSONURL     string
+	Description string
+	Ingredients []string
+	JSONLD      template.JS
+}
+
+var recipePageTemplate = template.Must(template.New("recipe").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+{{- if .}}
+<title>{{.Recipe.Title}} · DLISHE</title>
+<meta name="description" content="{{.Description}}">
+<meta property="og:type" content="article">
+<meta property="og:site_name" content="DLISHE">
+<meta property="og:title" content="{{.Recipe.Title}}">
+<meta property="og:description" content="{{.Description}}">
+<meta property="og:url" content="{{.URL}}">
+{{- with .Recipe.ThumbnailURL}}
+<meta property="og:image" content="{{.}}">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:image" content="{{.}}">
+{{- else}}
+<meta name="twitter:card" content="summary">
+{{- end}}
+<meta name="twitter:title" content="{{.Recipe.Title}}">
+<link rel="canonical" href="{{.URL}}">
+<link rel="alternate" type="application/json" href="{{.JSONURL}}">
+<script type="application/ld+json">{{.JSONLD}}</script>
+{{- else}}
+<title>Recipe not found · DLISHE</title>
+{{- end}}
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,sans-serif;max-width:40rem;margin:0 auto;padding:1.5rem;line-height:1.5;color:#222}
+img{width:100%;border-radius:12px}
+.meta{color:#666}
+li{margin-bottom:.4rem}
+</style>
+</head>
+<body>
+{{- if .}}
+{{- with .Recipe}}
+{{- with .ThumbnailURL}}
+<img src="{{.}}" alt="">
+{{- end}}
+<h1>{{.Title}}</h1>
+{{- end}}
+{{- with .Description}}
+<p>{{.}}</p>
+{{- end}}
+{{- with .Recipe}}
+<p class="meta">
+{{- with .Servings}}Serves {{.}}{{end}}
+{{- with .PrepTime}} · Prep {{.}} min{{end}}
+{{- with .CookTime}} · Cook {{.}} min{{end}}
+</p>
+{{- end}}
+{{- if .Ingredients}}
+<h2>Ingredients</h2>
+<ul>
+{{- range .Ingredients}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Recipe.Steps}}
+<h2>Steps</h2>
+<ol>
+{{- range .Recipe.Steps}}
+<li>{{.Instruction}}</li>
+{{- end}}
+</ol>
+{{- end}}
+{{- else}}
+<h1>Recipe not found</h1>
+<p>This link has been disabled or the recipe no longer exists.</p>
+{{- end}}
+</body>
+</html>
+`))
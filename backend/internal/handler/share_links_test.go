@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestShareLinkHandler_Page(t *testing.T) {
+	mockLinkRepo := &mockShareLinkRepository{}
+	mockRecipeRepo := &mockRecipeRepository{}
+	handler := NewShareLinkHandler(mockLinkRepo, mockRecipeRepo, "https://example.com/")
+
+	recipeID := uuid.New()
+	thumb := "https://example.com/t.jpg"
+	servings := 4
+	mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return &model.Recipe{
+			ID:           id,
+			Title:        `Fish & "Chips" </script>`,
+			ThumbnailURL: &thumb,
+			Servings:     &servings,
+			IsFavorite:   true,
+			Ingredients:  []model.RecipeIngredient{{Name: "cod"}},
+			Steps:        []model.RecipeStep{{StepNumber: 1, Instruction: "Fry the fish"}},
+		}, nil
+	}
+
+	t.Run("renders recipe with JSON-LD and Open Graph", func(t *testing.T) {
+		mockLinkRepo.RecordViewFunc = func(ctx context.Context, token string) (*model.RecipeShareLink, error) {
+			return &model.RecipeShareLink{RecipeID: recipeID, Token: token, ViewCount: 1}, nil
+		}
+
+		req := shareRequest("GET", "/r/abc", "", nil, map[string]string{"token": "abc"})
+		rr := httptest.NewRecorder()
+		handler.Page(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if cc := rr.Header().Get("Cache-Control"); cc != "no-store" {
+			t.Errorf("expected no-store, got %q", cc)
+		}
+		body := rr.Body.String()
+		for _, want := range []string{
+			`<script type="application/ld+json">`,
+			`"@type":"Recipe"`,
+			`<meta property="og:url" content="https://example.com/r/abc">`,
+			`<meta property="og:image" content="https://example.com/t.jpg">`,
+			`<li>Fry the fish</li>`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected page to contain %q", want)
+			}
+		}
+		if strings.Count(body, "</script>") != 1 {
+			t.Error("title was not escaped inside the JSON-LD script")
+		}
+	})
+
+	t.Run("disabled link", func(t *testing.T) {
+		mockLinkRepo.RecordViewFunc = nil
+
+		req := shareRequest("GET", "/r/abc", "", nil, map[string]string{"token": "abc"})
+		rr := httptest.NewRecorder()
+		handler.Page(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+		if !strings.Contains(rr.Body.String(), "Recipe not found") {
+			t.Error("expected not found page")
+		}
+	})
+}
+
+func TestShareLinkHandler_PageJSON(t *testing.T) {
+	mockLinkRepo := &mockShareLinkRepository{}
+	mockRecipeRepo := &mockRecipeRepository{}
+	handler := NewShareLinkHandler(mockLinkRepo, mockRecipeRepo, "https://example.com")
+
+	recipeID := uuid.New()
+	mockLinkRepo.RecordViewFunc = func(ctx context.Context, token string) (*model.RecipeShareLink, error) {
+		return &model.RecipeShareLink{RecipeID: recipeID, Token: token}, nil
+	}
+	mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return &model.Recipe{ID: id, Title: "Pho", IsFavorite: true}, nil
+	}
+
+	req := shareRequest("GET", "/api/v1/r/abc", "", nil, map[string]string{"token": "abc"})
+	rr := httptest.NewRecorder()
+	handler.PageJSON(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp struct {
+		Recipe model.Recipe `json:"recipe"`
+		URL    string       `json:"url"`
+	}
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if resp.Recipe.ID != recipeID || resp.URL != "https://example.com/r/abc" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Recipe.IsFavorite {
+		t.Error("owner's favorite flag should not be shared")
+	}
+}
+
+func TestShareLinkHandler_CreateLink(t *testing.T) {
+	mockLinkRepo := &mockShareLinkRepository{}
+	mockRecipeRepo := &mockRecipeRepository{}
+	handler := NewShareLinkHandler(mockLinkRepo, mockRecipeRepo, "https://example.com")
+
+	user := &model.User{ID: uuid.New()}
+	recipeID := uuid.New()
+	params := map[string]string{"recipeID": recipeID.String()}
+
+	t.Run("success", func(t *testing.T) {
+		mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+			return &model.Recipe{ID: id, UserID: user.ID}, nil
+		}
+		mockLinkRepo.CreateFunc = func(ctx context.Context, rid, uid uuid.UUID) (*model.RecipeShareLink, error) {
+			return &model.RecipeShareLink{ID: uuid.New(), RecipeID: rid, UserID: uid, Token: "tok"}, nil
+		}
+
+		req := shareRequest("POST", "/recipes/x/link", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.CreateLink(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		var link model.RecipeShareLink
+		json.NewDecoder(rr.Body).Decode(&link)
+		if link.URL != "https://example.com/r/tok" {
+			t.Errorf("unexpected url %q", link.URL)
+		}
+	})
+
+	t.Run("not the owner", func(t *testing.T) {
+		mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+			return &model.Recipe{ID: id, UserID: uuid.New()}, nil
+		}
+
+		req := shareRequest("POST", "/recipes/x/link", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.CreateLink(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+}
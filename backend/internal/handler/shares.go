@@ -0,0 +1,418 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
+)
+
+// ShareHandler handles sharing recipes between users
+type ShareHandler struct {
+	shareRepo   ShareRepository
+	recipeRepo  RecipeRepository
+	userRepo    UserRepository
+	adminEmails []string
+}
+
+// NewShareHandler creates a new share handler
+func NewShareHandler(shareRepo ShareRepository, recipeRepo RecipeRepository, userRepo UserRepository, adminEmails []string) *ShareHandler {
+	return &ShareHandler{
+		shareRepo:   shareRepo,
+		recipeRepo:  recipeRepo,
+		userRepo:    userRepo,
+		adminEmails: adminEmails,
+	}
+}
+
+// Share handles POST /api/v1/recipes/{recipeID}/shares
+// @Summary Share a recipe
+// @Description Share one of your recipes with another user by user ID or email. Emails that don't belong to a user yet are delivered when someone signs up with them. Requires a plan with recipe sharing.
+// @Tags Sharing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Param request body SwaggerRecipeShareInput true "Recipient"
+// @Success 201 {object} SwaggerRecipeShare "Share created"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid request body"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 402 {object} SwaggerErrorResponse "Recipe sharing not included in plan"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe or user not found"
+// @Failure 409 {object} SwaggerErrorResponse "Already shared with this user"
+// @Router /recipes/{recipeID}/shares [post]
+func (h *ShareHandler) Share(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	recipe, ok := ownedRecipe(w, r, h.recipeRepo)
+	if !ok {
+		return
+	}
+
+	if !h.sharingAllowed(ctx, user) {
+		response.PaymentRequired(w, "Recipe sharing is a Pro feature. Upgrade to share recipes.")
+		return
+	}
+
+	var input model.RecipeShareInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	share := &model.RecipeShare{
+		RecipeID:       recipe.ID,
+		OwnerID:        user.ID,
+		RecipientID:    input.RecipientID,
+		RecipientEmail: input.RecipientEmail,
+	}
+	if err := h.shareRepo.Create(ctx, share); err != nil {
+		switch {
+		case errors.Is(err, postgres.ErrUserNotFound):
+			response.NotFound(w, "User")
+		case errors.Is(err, postgres.ErrShareExists):
+			response.Conflict(w, "Recipe already shared with this user")
+		case errors.Is(err, postgres.ErrSelfShare):
+			response.ValidationFailed(w, "recipient", "cannot share a recipe with yourself")
+		default:
+			response.InternalError(w)
+		}
+		return
+	}
+
+	share.RecipeTitle = recipe.Title
+	share.RecipeThumbnailURL = recipe.ThumbnailURL
+	share.OwnerName = user.Name
+	response.Created(w, share)
+}
+
+// ListRecipeShares handles GET /api/v1/recipes/{recipeID}/shares
+// @Summary List a recipe's shares
+// @Description List who one of your recipes has been shared with and whether they accepted
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Param recipeID path string true "Recipe UUID"
+// @Success 200 {object} SwaggerRecipeSharesResponse "Shares"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 403 {object} SwaggerErrorResponse "Access denied"
+// @Failure 404 {object} SwaggerErrorResponse "Recipe not found"
+// @Router /recipes/{recipeID}/shares [get]
+func (h *ShareHandler) ListRecipeShares(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	recipe, ok := ownedRecipe(w, r, h.recipeRepo)
+	if !ok {
+		return
+	}
+
+	shares, err := h.shareRepo.ListSent(ctx, user.ID, &recipe.ID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"shares": shares,
+		"count":  len(shares),
+	})
+}
+
+// Inbox handles GET /api/v1/shares/inbox
+// @Summary List recipes shared with you
+// @Description List shares sent to the current user, including ones sent to their email before they signed up
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Share status" Enums(pending, accepted, declined) default(pending)
+// @Success 200 {object} SwaggerRecipeSharesResponse "Shares"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid status"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Router /shares/inbox [get]
+func (h *ShareHandler) Inbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	status := model.ShareStatusPending
+	if s := r.URL.Query().Get("status"); s != "" {
+		status = model.ShareStatus(s)
+		if status != model.ShareStatusPending && status != model.ShareStatusAccepted && status != model.ShareStatusDeclined {
+			response.ValidationFailed(w, "status", "must be pending, accepted or declined")
+			return
+		}
+	}
+
+	shares, err := h.shareRepo.ListInbox(ctx, user.ID, userEmail(user), status)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"shares": shares,
+		"count":  len(shares),
+	})
+}
+
+// Sent handles GET /api/v1/shares/sent
+// @Summary List recipes you shared
+// @Description List all shares sent by the current user
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SwaggerRecipeSharesResponse "Shares"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Router /shares/sent [get]
+func (h *ShareHandler) Sent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	shares, err := h.shareRepo.ListSent(ctx, user.ID, nil)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"shares": shares,
+		"count":  len(shares),
+	})
+}
+
+// Accept handles POST /api/v1/shares/{shareID}/accept
+// @Summary Accept a shared recipe
+// @Description Accept a share and receive your own copy of the recipe. The copy keeps a link to the original via sourceRecipeId.
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Param shareID path string true "Share UUID"
+// @Success 201 {object} SwaggerCloneResponse "Copy of the shared recipe"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid share ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Share not found"
+// @Failure 409 {object} SwaggerErrorResponse "Share already answered"
+// @Router /shares/{shareID}/accept [post]
+func (h *ShareHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	share, ok := h.pendingShareFor(w, r, user)
+	if !ok {
+		return
+	}
+
+	source, err := h.recipeRepo.GetByID(ctx, share.RecipeID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrRecipeNotFound) {
+			response.NotFound(w, "Recipe")
+			return
+		}
+		response.InternalError(w)
+		return
+	}
+
+	// Accepting a recipe the user already copied links the share to that copy
+	recipe, err := h.recipeRepo.GetBySourceRecipeID(ctx, user.ID, source.ID)
+	if err != nil && !errors.Is(err, postgres.ErrRecipeNotFound) {
+		response.LogAndInternalError(w, err)
+		return
+	}
+
+	// Claim the share before copying, so losing a race to a second accept
+	// or a decline never leaves an orphaned copy behind
+	if err := h.shareRepo.Accept(ctx, share.ID, user.ID); err != nil {
+		writeShareError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if recipe == nil {
+		status = http.StatusCreated
+		recipe = cloneRecipe(source, user.ID)
+		if err := h.recipeRepo.Create(ctx, recipe); err != nil {
+			// Hand the share back so the recipient can try again
+			if rerr := h.shareRepo.Reopen(ctx, share.ID); rerr != nil {
+				err = errors.Join(err, rerr)
+			}
+			response.LogAndInternalError(w, err)
+			return
+		}
+	}
+
+	if err := h.shareRepo.SetAcceptedRecipe(ctx, share.ID, recipe.ID); err != nil {
+		response.LogAndInternalError(w, err)
+		return
+	}
+
+	response.JSON(w, status, recipe)
+}
+
+// Decline handles POST /api/v1/shares/{shareID}/decline
+// @Summary Decline a shared recipe
+// @Description Decline a share. The sender can share the recipe again later.
+// @Tags Sharing
+// @Security BearerAuth
+// @Param shareID path string true "Share UUID"
+// @Success 204 "Share declined"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid share ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Share not found"
+// @Failure 409 {object} SwaggerErrorResponse "Share already answered"
+// @Router /shares/{shareID}/decline [post]
+func (h *ShareHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	share, ok := h.pendingShareFor(w, r, user)
+	if !ok {
+		return
+	}
+
+	if err := h.shareRepo.Decline(ctx, share.ID, user.ID); err != nil {
+		writeShareError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Revoke handles DELETE /api/v1/shares/{shareID}
+// @Summary Revoke a share
+// @Description Withdraw a share you sent. Recipients keep copies they already accepted.
+// @Tags Sharing
+// @Security BearerAuth
+// @Param shareID path string true "Share UUID"
+// @Success 204 "Share revoked"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid share ID"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Share not found"
+// @Router /shares/{shareID} [delete]
+func (h *ShareHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := middleware.GetUserFromContext(ctx)
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "shareID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid share ID")
+		return
+	}
+
+	if err := h.shareRepo.Delete(ctx, id, user.ID); err != nil {
+		writeShareError(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// pendingShareFor loads the share in the URL and checks it is a pending
+// share addressed to the user, writing the error response when it isn't.
+// Shares addressed to someone else are reported as not found.
+func (h *ShareHandler) pendingShareFor(w http.ResponseWriter, r *http.Request, user *model.User) (*model.RecipeShare, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "shareID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid share ID")
+		return nil, false
+	}
+
+	share, err := h.shareRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeShareError(w, err)
+		return nil, false
+	}
+
+	addressed := share.RecipientID != nil && *share.RecipientID == user.ID
+	if share.RecipientID == nil && share.RecipientEmail != nil {
+		addressed = strings.EqualFold(*share.RecipientEmail, userEmail(user))
+	}
+	if !addressed {
+		response.NotFound(w, "Share")
+		return nil, false
+	}
+
+	if share.Status != model.ShareStatusPending {
+		response.Conflict(w, "Share already "+string(share.Status))
+		return nil, false
+	}
+	return share, true
+}
+
+// sharingAllowed reports whether the user's plan includes recipe sharing.
+// Admins always can; a failed subscription lookup falls back to free.
+func (h *ShareHandler) sharingAllowed(ctx context.Context, user *model.User) bool {
+	if model.IsAdminEmail(user.Email, h.adminEmails) {
+		return true
+	}
+
+	entitlement := "free"
+	if sub, err := h.userRepo.GetSubscription(ctx, user.ID); err == nil && sub != nil {
+		entitlement = sub.Entitlement
+	}
+	limits, ok := model.TierLimits[entitlement]
+	if !ok {
+		limits = model.TierLimits["free"]
+	}
+	return limits.RecipeSharing
+}
+
+func userEmail(user *model.User) string {
+	if user.Email == nil {
+		return ""
+	}
+	return *user.Email
+}
+
+// writeShareError maps share repository errors to responses
+func writeShareError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, postgres.ErrShareNotFound):
+		response.NotFound(w, "Share")
+	case errors.Is(err, postgres.ErrShareExists):
+		response.Conflict(w, "Recipe already shared with this user")
+	default:
+		response.InternalError(w)
+	}
+}
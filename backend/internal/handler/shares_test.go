@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/repository/postgres"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func shareRequest(method, target, body string, user *model.User, params map[string]string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, middleware.UserContextKey, user)
+	return req.WithContext(ctx)
+}
+
+func TestShareHandler_Share(t *testing.T) {
+	mockShareRepo := &mockShareRepository{}
+	mockRecipeRepo := &mockRecipeRepository{}
+	mockUserRepo := &mockUserRepository{}
+	handler := NewShareHandler(mockShareRepo, mockRecipeRepo, mockUserRepo, nil)
+
+	email := "owner@example.com"
+	user := &model.User{ID: uuid.New(), Email: &email}
+	recipeID := uuid.New()
+	params := map[string]string{"recipeID": recipeID.String()}
+
+	mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return &model.Recipe{ID: id, UserID: user.ID, Title: "Ramen"}, nil
+	}
+
+	t.Run("free tier requires upgrade", func(t *testing.T) {
+		mockShareRepo.CreateFunc = func(ctx context.Context, share *model.RecipeShare) error {
+			t.Fatal("share should not be created")
+			return nil
+		}
+
+		req := shareRequest("POST", "/recipes/x/shares", `{"recipientEmail":"friend@example.com"}`, user, params)
+		rr := httptest.NewRecorder()
+		handler.Share(rr, req)
+
+		if rr.Code != http.StatusPaymentRequired {
+			t.Errorf("expected 402, got %d", rr.Code)
+		}
+	})
+
+	mockUserRepo.GetSubscriptionFunc = func(ctx context.Context, userID uuid.UUID) (*model.UserSubscription, error) {
+		return &model.UserSubscription{Entitlement: "pro", IsActive: true}, nil
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var got *model.RecipeShare
+		mockShareRepo.CreateFunc = func(ctx context.Context, share *model.RecipeShare) error {
+			got = share
+			share.ID = uuid.New()
+			share.Status = model.ShareStatusPending
+			return nil
+		}
+
+		req := shareRequest("POST", "/recipes/x/shares", `{"recipientEmail":" Friend@Example.com "}`, user, params)
+		rr := httptest.NewRecorder()
+		handler.Share(rr, req)
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if got.RecipientEmail == nil || *got.RecipientEmail != "friend@example.com" {
+			t.Errorf("expected normalized email, got %v", got.RecipientEmail)
+		}
+		if got.RecipeID != recipeID || got.OwnerID != user.ID {
+			t.Errorf("unexpected share: %+v", got)
+		}
+	})
+
+	t.Run("cannot share with yourself", func(t *testing.T) {
+		// The repository rejects recipients that resolve to the owner,
+		// including emails of the owner's own account
+		mockShareRepo.CreateFunc = func(ctx context.Context, share *model.RecipeShare) error {
+			return postgres.ErrSelfShare
+		}
+
+		for _, body := range []string{`{"recipientId":"` + user.ID.String() + `"}`, `{"recipientEmail":"OWNER@example.com"}`} {
+			req := shareRequest("POST", "/recipes/x/shares", body, user, params)
+			rr := httptest.NewRecorder()
+			handler.Share(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", body, rr.Code)
+			}
+		}
+	})
+
+	t.Run("already shared", func(t *testing.T) {
+		mockShareRepo.CreateFunc = func(ctx context.Context, share *model.RecipeShare) error {
+			return postgres.ErrShareExists
+		}
+
+		req := shareRequest("POST", "/recipes/x/shares", `{"recipientId":"`+uuid.New().String()+`"}`, user, params)
+		rr := httptest.NewRecorder()
+		handler.Share(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Errorf("expected 409, got %d", rr.Code)
+		}
+	})
+
+	t.Run("not the owner", func(t *testing.T) {
+		mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+			return &model.Recipe{ID: id, UserID: uuid.New()}, nil
+		}
+
+		req := shareRequest("POST", "/recipes/x/shares", `{"recipientEmail":"friend@example.com"}`, user, params)
+		rr := httptest.NewRecorder()
+		handler.Share(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+}
+
+func TestShareHandler_Accept(t *testing.T) {
+	mockShareRepo := &mockShareRepository{}
+	mockRecipeRepo := &mockRecipeRepository{}
+	handler := NewShareHandler(mockShareRepo, mockRecipeRepo, &mockUserRepository{}, nil)
+
+	email := "friend@example.com"
+	user := &model.User{ID: uuid.New(), Email: &email}
+	shareID := uuid.New()
+	sourceID := uuid.New()
+	params := map[string]string{"shareID": shareID.String()}
+
+	mockRecipeRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+		return &model.Recipe{ID: id, UserID: uuid.New(), Title: "Ramen"}, nil
+	}
+
+	t.Run("clones the recipe", func(t *testing.T) {
+		otherEmail := "Friend@Example.com"
+		mockShareRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.RecipeShare, error) {
+			return &model.RecipeShare{ID: id, RecipeID: sourceID, RecipientEmail: &otherEmail, Status: model.ShareStatusPending}, nil
+		}
+		var created *model.Recipe
+		mockRecipeRepo.CreateFunc = func(ctx context.Context, recipe *model.Recipe) error {
+			created = recipe
+			return nil
+		}
+		mockShareRepo.AcceptFunc = func(ctx context.Context, id, recipientID uuid.UUID) error {
+			if recipientID != user.ID {
+				t.Errorf("expected share claimed by %s, got %s", user.ID, recipientID)
+			}
+			if created != nil {
+				t.Error("expected the share claimed before the copy was made")
+			}
+			return nil
+		}
+		var acceptedRecipe uuid.UUID
+		mockShareRepo.SetAcceptedRecipeFunc = func(ctx context.Context, id, recipeID uuid.UUID) error {
+			acceptedRecipe = recipeID
+			return nil
+		}
+
+		req := shareRequest("POST", "/shares/x/accept", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.Accept(rr, req)
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if created == nil || created.UserID != user.ID {
+			t.Fatalf("expected a copy owned by the recipient, got %+v", created)
+		}
+		if created.SourceRecipeID == nil || *created.SourceRecipeID != sourceID {
+			t.Errorf("expected sourceRecipeId %s, got %v", sourceID, created.SourceRecipeID)
+		}
+		if acceptedRecipe != created.ID {
+			t.Errorf("expected share linked to copy %s, got %s", created.ID, acceptedRecipe)
+		}
+		var resp model.Recipe
+		json.NewDecoder(rr.Body).Decode(&resp)
+		if resp.ID != created.ID {
+			t.Errorf("expected copy in response, got %s", resp.ID)
+		}
+	})
+
+	pending := func(ctx context.Context, id uuid.UUID) (*model.RecipeShare, error) {
+		return &model.RecipeShare{ID: id, RecipeID: sourceID, RecipientID: &user.ID, Status: model.ShareStatusPending}, nil
+	}
+
+	t.Run("links an existing copy", func(t *testing.T) {
+		mockShareRepo.GetByIDFunc = pending
+		existing := &model.Recipe{ID: uuid.New(), UserID: user.ID, SourceRecipeID: &sourceID}
+		mockRecipeRepo.GetBySourceRecipeIDFunc = func(ctx context.Context, userID, id uuid.UUID) (*model.Recipe, error) {
+			return existing, nil
+		}
+		defer func() { mockRecipeRepo.GetBySourceRecipeIDFunc = nil }()
+		mockRecipeRepo.CreateFunc = func(ctx context.Context, recipe *model.Recipe) error {
+			t.Error("expected no new copy")
+			return nil
+		}
+		mockShareRepo.AcceptFunc = func(ctx context.Context, id, recipientID uuid.UUID) error { return nil }
+		var acceptedRecipe uuid.UUID
+		mockShareRepo.SetAcceptedRecipeFunc = func(ctx context.Context, id, recipeID uuid.UUID) error {
+			acceptedRecipe = recipeID
+			return nil
+		}
+
+		req := shareRequest("POST", "/shares/x/accept", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.Accept(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if acceptedRecipe != existing.ID {
+			t.Errorf("expected share linked to existing copy %s, got %s", existing.ID, acceptedRecipe)
+		}
+	})
+
+	t.Run("copy lookup fails", func(t *testing.T) {
+		mockShareRepo.GetByIDFunc = pending
+		mockRecipeRepo.GetBySourceRecipeIDFunc = func(ctx context.Context, userID, id uuid.UUID) (*model.Recipe, error) {
+			return nil, errors.New("connection reset")
+		}
+		defer func() { mockRecipeRepo.GetBySourceRecipeIDFunc = nil }()
+		mockShareRepo.AcceptFunc = func(ctx context.Context, id, recipientID uuid.UUID) error {
+			t.Error("expected the share left pending")
+			return nil
+		}
+
+		req := shareRequest("POST", "/shares/x/accept", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.Accept(rr, req)
+
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("expected 500, got %d", rr.Code)
+		}
+	})
+
+	t.Run("answered concurrently", func(t *testing.T) {
+		mockShareRepo.GetByIDFunc = pending
+		mockShareRepo.AcceptFunc = func(ctx context.Context, id, recipientID uuid.UUID) error {
+			return postgres.ErrShareNotFound
+		}
+		mockRecipeRepo.CreateFunc = func(ctx context.Context, recipe *model.Recipe) error {
+			t.Error("expected no copy for a share that was already answered")
+			return nil
+		}
+
+		req := shareRequest("POST", "/shares/x/accept", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.Accept(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("copy fails", func(t *testing.T) {
+		mockShareRepo.GetByIDFunc = pending
+		mockShareRepo.AcceptFunc = func(ctx context.Context, id, recipientID uuid.UUID) error { return nil }
+		mockRecipeRepo.CreateFunc = func(ctx context.Context, recipe *model.Recipe) error {
+			return errors.New("disk full")
+		}
+		reopened := false
+		mockShareRepo.ReopenFunc = func(ctx context.Context, id uuid.UUID) error {
+			reopened = id == shareID
+			return nil
+		}
+
+		req := shareRequest("POST", "/shares/x/accept", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.Accept(rr, req)
+
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("expected 500, got %d", rr.Code)
+		}
+		if !reopened {
+			t.Error("expected the share reopened")
+		}
+	})
+
+	t.Run("addressed to someone else", func(t *testing.T) {
+		other := uuid.New()
+		mockShareRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.RecipeShare, error) {
+			return &model.RecipeShare{ID: id, RecipeID: sourceID, RecipientID: &other, Status: model.ShareStatusPending}, nil
+		}
+
+		req := shareRequest("POST", "/shares/x/accept", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.Accept(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("already declined", func(t *testing.T) {
+		mockShareRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.RecipeShare, error) {
+			return &model.RecipeShare{ID: id, RecipeID: sourceID, RecipientID: &user.ID, Status: model.ShareStatusDeclined}, nil
+		}
+
+		req := shareRequest("POST", "/shares/x/accept", "", user, params)
+		rr := httptest.NewRecorder()
+		handler.Accept(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Errorf("expected 409, got %d", rr.Code)
+		}
+	})
+}
+
+func TestShareHandler_Decline(t *testing.T) {
+	mockShareRepo := &mockShareRepository{}
+	handler := NewShareHandler(mockShareRepo, &mockRecipeRepository{}, &mockUserRepository{}, nil)
+
+	user := &model.User{ID: uuid.New()}
+	shareID := uuid.New()
+
+	mockShareRepo.GetByIDFunc = func(ctx context.Context, id uuid.UUID) (*model.RecipeShare, error) {
+		return &model.RecipeShare{ID: id, RecipientID: &user.ID, Status: model.ShareStatusPending}, nil
+	}
+	declined := false
+	mockShareRepo.DeclineFunc = func(ctx context.Context, id, recipientID uuid.UUID) error {
+		declined = id == shareID && recipientID == user.ID
+		return nil
+	}
+
+	req := shareRequest("POST", "/shares/x/decline", "", user, map[string]string{"shareID": shareID.String()})
+	rr := httptest.NewRecorder()
+	handler.Decline(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rr.Code)
+	}
+	if !declined {
+		t.Error("expected share to be declined")
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/dishflow/backend/internal/pkg/response"
 	"github.com/dishflow/backend/internal/repository/postgres"
 	"github.com/dishflow/backend/internal/service/ai"
+	"github.com/dishflow/backend/internal/service/scaling"
 )
 
 // ShoppingHandler handles shopping list-related HTTP requests
@@ -38,12 +40,15 @@ func NewShoppingHandler(shoppingRepo ShoppingRepository, recipeRepo RecipeReposi
 
 // ListLists handles GET /api/v1/shopping-lists
 // @Summary List shopping lists
-// @Description Get all shopping lists for the current user
+// @Description Get the current user's shopping lists, newest first. Without limit or cursor every list is returned; either one pages them.
 // @Tags Shopping
 // @Produce json
 // @Security BearerAuth
 // @Param includeArchived query bool false "Include archived lists" default(false)
+// @Param limit query int false "Lists per page (max 100); 50 when only cursor is given"
+// @Param cursor query string false "Cursor from a previous page's nextCursor"
 // @Success 200 {object} SwaggerShoppingListsResponse "List of shopping lists"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid cursor"
 // @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
 // @Failure 500 {object} SwaggerErrorResponse "Internal server error"
 // @Router /shopping-lists [get]
@@ -56,17 +61,22 @@ func (h *ShoppingHandler) ListLists(w http.ResponseWriter, r *http.Request) {
 	}
 
 	includeArchived := r.URL.Query().Get("includeArchived") == "true"
+	page := pageParams(r, 50, 100)
+	if r.URL.Query().Get("limit") == "" && page.Cursor == "" {
+		// Clients from before paging expect every list in one response
+		page.Limit = 0
+	}
 
-	lists, err := h.shoppingRepo.ListLists(ctx, user.ID, includeArchived)
+	lists, next, err := h.shoppingRepo.ListLists(ctx, user.ID, includeArchived, page)
 	if err != nil {
-		response.InternalError(w)
+		writeListError(w, err)
 		return
 	}
 
-	response.OK(w, map[string]interface{}{
+	response.OK(w, withNextCursor(map[string]interface{}{
 		"lists": lists,
 		"count": len(lists),
-	})
+	}, next))
 }
 
 // GetList handles GET /api/v1/shopping-lists/{id}
@@ -77,8 +87,9 @@ func (h *ShoppingHandler) ListLists(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param id path string true "Shopping list UUID"
 // @Param includeItems query bool false "Include list items" default(false)
+// @Param units query string false "Convert item quantities; preferred uses the user's setting" Enums(metric, imperial, preferred)
 // @Success 200 {object} SwaggerShoppingListWithItems "Shopping list details"
-// @Failure 400 {object} SwaggerErrorResponse "Invalid list ID"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid list ID or units"
 // @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
 // @Failure 404 {object} SwaggerErrorResponse "Shopping list not found"
 // @Router /shopping-lists/{id} [get]
@@ -100,6 +111,12 @@ func (h *ShoppingHandler) GetList(w http.ResponseWriter, r *http.Request) {
 	// Check if we should include items
 	includeItems := r.URL.Query().Get("includeItems") == "true"
 
+	system, convert, err := unitSystemParam(r, user)
+	if err != nil {
+		response.ValidationFailed(w, "units", err.Error())
+		return
+	}
+
 	if includeItems {
 		listWithItems, err := h.shoppingRepo.GetListWithItems(ctx, id, user.ID)
 		if err == model.ErrNotFound {
@@ -110,6 +127,9 @@ func (h *ShoppingHandler) GetList(w http.ResponseWriter, r *http.Request) {
 			response.InternalError(w)
 			return
 		}
+		if convert {
+			convertShoppingItemUnits(listWithItems.Items, system)
+		}
 		response.OK(w, listWithItems)
 	} else {
 		list, err := h.shoppingRepo.GetList(ctx, id, user.ID)
@@ -314,8 +334,9 @@ func (h *ShoppingHandler) ArchiveList(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Shopping list UUID"
+// @Param units query string false "Convert item quantities; preferred uses the user's setting" Enums(metric, imperial, preferred)
 // @Success 200 {object} SwaggerShoppingItemsResponse "List of items"
-// @Failure 400 {object} SwaggerErrorResponse "Invalid list ID"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid list ID or units"
 // @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
 // @Failure 404 {object} SwaggerErrorResponse "Shopping list not found"
 // @Router /shopping-lists/{id}/items [get]
@@ -334,6 +355,12 @@ func (h *ShoppingHandler) ListItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	system, convert, err := unitSystemParam(r, user)
+	if err != nil {
+		response.ValidationFailed(w, "units", err.Error())
+		return
+	}
+
 	// Verify user owns the list
 	_, err = h.shoppingRepo.GetList(ctx, listID, user.ID)
 	if err == model.ErrNotFound {
@@ -351,6 +378,10 @@ func (h *ShoppingHandler) ListItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if convert {
+		convertShoppingItemUnits(items, system)
+	}
+
 	response.OK(w, map[string]interface{}{
 		"items": items,
 		"count": len(items),
@@ -658,11 +689,16 @@ func (h *ShoppingHandler) AddFromRecipe(w http.ResponseWriter, r *http.Request)
 	var req struct {
 		RecipeID    uuid.UUID `json:"recipeId"`
 		Ingredients []string  `json:"ingredients"` // Optional: if present, only add these
+		Servings    *int      `json:"servings"`    // Optional: scale quantities to this many servings
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.BadRequest(w, "Invalid request body")
 		return
 	}
+	if req.Servings != nil && (*req.Servings < 1 || *req.Servings > scaling.MaxServings) {
+		response.ValidationFailed(w, "servings", fmt.Sprintf("must be between 1 and %d", scaling.MaxServings))
+		return
+	}
 
 	// Get the recipe
 	recipe, err := h.recipeRepo.GetByID(ctx, req.RecipeID)
@@ -699,6 +735,11 @@ func (h *ShoppingHandler) AddFromRecipe(w http.ResponseWriter, r *http.Request)
 	aggregated := make(map[ingredientKey]*model.ShoppingItemInput)
 	recipeName := recipe.Title
 
+	factor := 1.0
+	if req.Servings != nil {
+		factor = scaling.Factor(recipe.Servings, *req.Servings)
+	}
+
 	for _, ingredient := range recipe.Ingredients {
 		// If filtered list is provided, skip if not in list
 		if len(req.Ingredients) > 0 {
@@ -717,7 +758,9 @@ func (h *ShoppingHandler) AddFromRecipe(w http.ResponseWriter, r *http.Request)
 		// Normalize category using centralized validation
 		category := model.NormalizeCategory(ingredient.Category)
 
-		// Convert cooking units to shopping units (strips tbsp, pinch, etc.)
+		// Scale to the requested servings, then convert cooking units to
+		// shopping units (strips tbsp, pinch, etc.)
+		ingredient = scaling.Ingredient(ingredient, factor)
 		qty, unit := model.ToShoppingUnit(ingredient.Quantity, ingredient.Unit)
 
 		// Aggregate by (name, category) — unit-agnostic
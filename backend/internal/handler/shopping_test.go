@@ -3,12 +3,14 @@ package handler
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/dishflow/backend/internal/middleware"
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
@@ -22,10 +24,10 @@ func TestShoppingHandler_ListLists(t *testing.T) {
 	userID := uuid.New()
 
 	t.Run("success", func(t *testing.T) {
-		mockRepo.ListListsFunc = func(ctx context.Context, uid uuid.UUID, archive bool) ([]*model.ShoppingList, error) {
+		mockRepo.ListListsFunc = func(ctx context.Context, uid uuid.UUID, archive bool, page pagination.Page) ([]*model.ShoppingList, string, error) {
 			return []*model.ShoppingList{
 				{ID: uuid.New(), Name: "Weekly Groceries"},
-			}, nil
+			}, "", nil
 		}
 
 		req := httptest.NewRequest("GET", "/shopping-lists", nil)
@@ -38,6 +40,72 @@ func TestShoppingHandler_ListLists(t *testing.T) {
 			t.Errorf("expected 200, got %d", rr.Code)
 		}
 	})
+
+	t.Run("no limit or cursor lists everything", func(t *testing.T) {
+		var got pagination.Page
+		mockRepo.ListListsFunc = func(ctx context.Context, uid uuid.UUID, archive bool, page pagination.Page) ([]*model.ShoppingList, string, error) {
+			got = page
+			return []*model.ShoppingList{}, "", nil
+		}
+
+		for target, want := range map[string]pagination.Page{
+			"/shopping-lists":            {},
+			"/shopping-lists?limit=10":   {Limit: 10},
+			"/shopping-lists?cursor=abc": {Limit: 50, Cursor: "abc"},
+		} {
+			req := httptest.NewRequest("GET", target, nil)
+			ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+			handler.ListLists(httptest.NewRecorder(), req.WithContext(ctx))
+
+			if got != want {
+				t.Errorf("%s: expected page %+v, got %+v", target, want, got)
+			}
+		}
+	})
+
+	t.Run("next page", func(t *testing.T) {
+		var got pagination.Page
+		mockRepo.ListListsFunc = func(ctx context.Context, uid uuid.UUID, archive bool, page pagination.Page) ([]*model.ShoppingList, string, error) {
+			got = page
+			return []*model.ShoppingList{{ID: uuid.New(), Name: "Party"}}, "after-party", nil
+		}
+
+		req := httptest.NewRequest("GET", "/shopping-lists?limit=1&cursor=abc", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		rr := httptest.NewRecorder()
+
+		handler.ListLists(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if got != (pagination.Page{Limit: 1, Cursor: "abc"}) {
+			t.Errorf("expected limit 1 and cursor abc, got %+v", got)
+		}
+		var resp struct {
+			NextCursor string `json:"nextCursor"`
+		}
+		json.NewDecoder(rr.Body).Decode(&resp)
+		if resp.NextCursor != "after-party" {
+			t.Errorf("expected nextCursor after-party, got %q", resp.NextCursor)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		mockRepo.ListListsFunc = func(ctx context.Context, uid uuid.UUID, archive bool, page pagination.Page) ([]*model.ShoppingList, string, error) {
+			return nil, "", pagination.ErrInvalidCursor
+		}
+
+		req := httptest.NewRequest("GET", "/shopping-lists?cursor=garbage", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		rr := httptest.NewRecorder()
+
+		handler.ListLists(rr, req.WithContext(ctx))
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
 }
 
 func TestShoppingHandler_SmartMergeList(t *testing.T) {
@@ -28,11 +28,11 @@ type SwaggerError struct {
 // SwaggerPaginatedResponse represents a paginated list response wrapper
 // @Description Paginated list response wrapper
 type SwaggerPaginatedResponse struct {
-	Items  interface{} `json:"items"`
-	Total  int         `json:"total" example:"100"`
-	Count  int         `json:"count" example:"20"`
-	Limit  int         `json:"limit" example:"20"`
-	Offset int         `json:"offset" example:"0"`
+	Items      interface{} `json:"items"`
+	Total      int         `json:"total" example:"100"`
+	Count      int         `json:"count" example:"20"`
+	Limit      int         `json:"limit" example:"20"`
+	NextCursor string      `json:"nextCursor,omitempty" example:"WyJyZWNlbnQiLCIyMDI0LTAyLTAxIl0"`
 }
 
 // ============================================================================
@@ -138,6 +138,7 @@ type SwaggerRecipeIngredient struct {
 	RecipeID       string   `json:"recipeId" example:"550e8400-e29b-41d4-a716-446655440000"`
 	Name           string   `json:"name" example:"Olive oil"`
 	Quantity       *float64 `json:"quantity,omitempty" example:"2"`
+	QuantityMax    *float64 `json:"quantityMax,omitempty" example:"3"`
 	Unit           *string  `json:"unit,omitempty" example:"tablespoons"`
 	Category       string   `json:"category" example:"condiments" enums:"dairy,produce,proteins,bakery,pantry,spices,condiments,beverages,snacks,frozen,household,other"`
 	IsOptional     bool     `json:"isOptional" example:"false"`
@@ -145,6 +146,23 @@ type SwaggerRecipeIngredient struct {
 	VideoTimestamp *int     `json:"videoTimestamp,omitempty" example:"120"`
 	SortOrder      int      `json:"sortOrder" example:"0"`
 	CreatedAt      string   `json:"createdAt" example:"2024-02-01T10:30:00Z"`
+
+	// Input only: a raw line parsed into the fields above
+	Text string `json:"text,omitempty" example:"2-3 tbsp olive oil, extra virgin preferred"`
+
+	DisplayQuantity string `json:"displayQuantity,omitempty" example:"1 1/2"`
+	ScalingNote     string `json:"scalingNote,omitempty" example:"Seasonings don't scale linearly — start with less and adjust to taste"`
+
+	Annotation *SwaggerRecipeAnnotation `json:"annotation,omitempty"`
+}
+
+// SwaggerRecipeScaling describes how a recipe was scaled
+// @Description Present when the recipe was requested with ?servings=N
+type SwaggerRecipeScaling struct {
+	OriginalServings int      `json:"originalServings" example:"4"`
+	Servings         int      `json:"servings" example:"6"`
+	Factor           float64  `json:"factor" example:"1.5"`
+	Warnings         []string `json:"warnings,omitempty"`
 }
 
 // SwaggerRecipeStep represents a recipe step
@@ -160,10 +178,12 @@ type SwaggerRecipeStep struct {
 	VideoTimestampStart *int    `json:"videoTimestampStart,omitempty" example:"60"`
 	VideoTimestampEnd   *int    `json:"videoTimestampEnd,omitempty" example:"180"`
 	CreatedAt           string  `json:"createdAt" example:"2024-02-01T10:30:00Z"`
+
+	Annotation *SwaggerRecipeAnnotation `json:"annotation,omitempty"`
 }
 
 // SwaggerRecipeNutritionInfo represents nutritional information per serving
-// @Description Nutritional information per serving (AI-estimated)
+// @Description Nutritional information per serving, from the USDA food database or AI-estimated
 type SwaggerRecipeNutritionInfo struct {
 	Calories   int      `json:"calories" example:"450"`
 	Protein    int      `json:"protein" example:"25"`
@@ -174,6 +194,7 @@ type SwaggerRecipeNutritionInfo struct {
 	Sodium     int      `json:"sodium,omitempty" example:"600"`
 	Tags       []string `json:"tags,omitempty" example:"high-protein,moderate-carb"`
 	Confidence float64  `json:"confidence,omitempty" example:"0.75"`
+	Source     string   `json:"source,omitempty" example:"usda"`
 }
 
 // SwaggerRecipeDietaryInfo represents dietary information for filtering
@@ -189,6 +210,19 @@ type SwaggerRecipeDietaryInfo struct {
 	IsKosher     bool     `json:"isKosher,omitempty" example:"false"`
 	Allergens    []string `json:"allergens,omitempty" example:"dairy,eggs,gluten"`
 	MealTypes    []string `json:"mealTypes,omitempty" example:"lunch,dinner,snack"`
+
+	// Set by the ingredient rules on every save
+	Triggers   []SwaggerDietaryTrigger `json:"triggers,omitempty"`
+	Unverified []string                `json:"unverified,omitempty" example:"house spice blend"`
+	Classified bool                    `json:"classified,omitempty" example:"true"`
+}
+
+// SwaggerDietaryTrigger names what put an allergen or ruled out a diet
+// @Description The ingredient behind an allergen or ruled-out diet. Rules triggers name the ingredient; AI triggers can't.
+type SwaggerDietaryTrigger struct {
+	Flag       string `json:"flag" example:"dairy" enums:"gluten,dairy,lactose,nuts,peanuts,shellfish,eggs,soy,fish,pork,sesame,vegetarian,vegan,halal,kosher"`
+	Ingredient string `json:"ingredient,omitempty" example:"parmesan"`
+	Source     string `json:"source" example:"rules" enums:"rules,ai"`
 }
 
 // SwaggerRecipe represents a full recipe
@@ -217,17 +251,96 @@ type SwaggerRecipe struct {
 	UpdatedAt       string                      `json:"updatedAt" example:"2026-02-02T19:30:10.803252Z"`
 	IngredientCount int                         `json:"ingredientCount,omitempty" example:"14"`
 	StepCount       int                         `json:"stepCount,omitempty" example:"9"`
+	TimesCooked     int                         `json:"timesCooked,omitempty" example:"5"`
+	LastCookedOn    *string                     `json:"lastCookedOn,omitempty" example:"2026-02-10T00:00:00Z"`
+	AverageRating   *float64                    `json:"averageRating,omitempty" example:"4.5"`
 	Ingredients     []SwaggerRecipeIngredient   `json:"ingredients,omitempty"`
 	Steps           []SwaggerRecipeStep         `json:"steps,omitempty"`
+	Scaling         *SwaggerRecipeScaling       `json:"scaling,omitempty"`
+
+	// The user's notes whose ingredient or step no longer exists
+	DetachedAnnotations []SwaggerRecipeAnnotation `json:"detachedAnnotations,omitempty"`
 }
 
 // SwaggerRecipeListResponse represents paginated recipe list
 // @Description Paginated list of recipes
 type SwaggerRecipeListResponse struct {
-	Items  []SwaggerRecipe `json:"items"`
-	Total  int             `json:"total" example:"25"`
-	Limit  int             `json:"limit" example:"20"`
-	Offset int             `json:"offset" example:"0"`
+	Items      []SwaggerRecipe      `json:"items"`
+	Total      int                  `json:"total" example:"25"`
+	Limit      int                  `json:"limit" example:"20"`
+	NextCursor string               `json:"nextCursor,omitempty" example:"WyJyZWNpcGVzOnJlY2VudCIsIjIwMjQtMDItMDEiXQ"`
+	Facets     *SwaggerRecipeFacets `json:"facets,omitempty"`
+}
+
+// SwaggerRecipeFacets represents filter chip counts
+// @Description Recipe counts per filter value; each facet is counted with the other filters applied
+type SwaggerRecipeFacets struct {
+	Cuisines     []SwaggerFacetCount `json:"cuisines"`
+	Difficulties []SwaggerFacetCount `json:"difficulties"`
+	SourceTypes  []SwaggerFacetCount `json:"sourceTypes"`
+	Tags         []SwaggerFacetCount `json:"tags"`
+	Diets        []SwaggerFacetCount `json:"diets"`
+	TotalTime    []SwaggerFacetCount `json:"totalTime"`
+}
+
+// SwaggerFacetCount represents how many recipes a filter value matches
+type SwaggerFacetCount struct {
+	Value string `json:"value" example:"italian"`
+	Count int    `json:"count" example:"4"`
+}
+
+// SwaggerSearchResponse represents recipe search results
+// @Description Ranked recipe search results
+type SwaggerSearchResponse struct {
+	Query   string                `json:"query" example:"chiken curry"`
+	Results []SwaggerSearchResult `json:"results"`
+	Count   int                   `json:"count" example:"1"`
+}
+
+// SwaggerSearchResult represents one search result
+// @Description Lightweight recipe summary with a highlighted snippet
+type SwaggerSearchResult struct {
+	ID           string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Title        string `json:"title" example:"Thai Green Curry"`
+	Cuisine      string `json:"cuisine,omitempty" example:"Thai"`
+	ThumbnailURL string `json:"thumbnailUrl,omitempty" example:"https://example.com/thumb.jpg"`
+	Difficulty   string `json:"difficulty,omitempty" example:"easy"`
+	IsFavorite   bool   `json:"isFavorite" example:"false"`
+	Snippet      string `json:"snippet,omitempty" example:"coconut milk, <mark>chicken</mark> thighs, green <mark>curry</mark> paste"`
+}
+
+// SwaggerIngredientSearchResponse represents ingredient search results
+// @Description Recipes ranked by how much of them the given ingredients cover
+type SwaggerIngredientSearchResponse struct {
+	Include []string                      `json:"include" example:"chicken,rice,spinach"`
+	Exclude []string                      `json:"exclude,omitempty" example:"peanut"`
+	Results []SwaggerIngredientSearchItem `json:"results"`
+	Count   int                           `json:"count" example:"1"`
+}
+
+// SwaggerIngredientSearchItem represents one ingredient search result
+// @Description Recipe summary with matched and missing ingredients
+type SwaggerIngredientSearchItem struct {
+	ID              string                         `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Title           string                         `json:"title" example:"Chicken and Spinach Rice"`
+	Cuisine         string                         `json:"cuisine,omitempty" example:"Indian"`
+	ThumbnailURL    string                         `json:"thumbnailUrl,omitempty" example:"https://example.com/thumb.jpg"`
+	Difficulty      string                         `json:"difficulty,omitempty" example:"easy"`
+	PrepTime        int                            `json:"prepTime,omitempty" example:"10"`
+	CookTime        int                            `json:"cookTime,omitempty" example:"25"`
+	IsFavorite      bool                           `json:"isFavorite" example:"false"`
+	InLibrary       bool                           `json:"inLibrary" example:"true"`
+	IngredientCount int                            `json:"ingredientCount" example:"5"`
+	Coverage        int                            `json:"coverage" example:"60"`
+	Matched         []SwaggerIngredientSearchMatch `json:"matched"`
+	Missing         []string                       `json:"missing" example:"onion,garam masala"`
+}
+
+// SwaggerIngredientSearchMatch represents a covered recipe ingredient
+type SwaggerIngredientSearchMatch struct {
+	Ingredient   string `json:"ingredient" example:"chicken thighs"`
+	Term         string `json:"term" example:"chicken"`
+	IsSubstitute bool   `json:"isSubstitute,omitempty" example:"false"`
 }
 
 // SwaggerFavoriteRequest represents toggle favorite request
@@ -250,6 +363,63 @@ type SwaggerCloneResponse struct {
 	SourceRecipeID string `json:"sourceRecipeId" example:"550e8400-e29b-41d4-a716-446655440001"`
 }
 
+// SwaggerRecipeRevision represents a saved revision of a recipe
+// @Description Recipe revision; snapshot is only included when fetching a single revision
+type SwaggerRecipeRevision struct {
+	ID              string         `json:"id" example:"550e8400-e29b-41d4-a716-446655440010"`
+	RecipeID        string         `json:"recipeId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SyncVersion     int            `json:"syncVersion" example:"3"`
+	Source          string         `json:"source" example:"update" enums:"create,update,sync,conflict,restore"`
+	RestoredFrom    *string        `json:"restoredFrom,omitempty" example:"550e8400-e29b-41d4-a716-446655440011"`
+	CreatedAt       string         `json:"createdAt" example:"2024-02-01T10:30:00Z"`
+	Title           string         `json:"title" example:"Spaghetti Carbonara"`
+	IngredientCount int            `json:"ingredientCount" example:"6"`
+	StepCount       int            `json:"stepCount" example:"5"`
+	Snapshot        *SwaggerRecipe `json:"snapshot,omitempty"`
+}
+
+// SwaggerRecipeRevisionList represents a list of recipe revisions
+// @Description Recipe revisions, newest first
+type SwaggerRecipeRevisionList struct {
+	Revisions []SwaggerRecipeRevision `json:"revisions"`
+}
+
+// SwaggerRecipeFieldChange represents a changed recipe field
+// @Description Changed top-level recipe field
+type SwaggerRecipeFieldChange struct {
+	Field string      `json:"field" example:"servings"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// SwaggerRecipeIngredientChange represents an ingredient difference
+// @Description Added, removed or changed ingredient
+type SwaggerRecipeIngredientChange struct {
+	Change string                   `json:"change" example:"changed" enums:"added,removed,changed"`
+	Name   string                   `json:"name" example:"Spaghetti"`
+	From   *SwaggerRecipeIngredient `json:"from,omitempty"`
+	To     *SwaggerRecipeIngredient `json:"to,omitempty"`
+}
+
+// SwaggerRecipeStepChange represents a step difference
+// @Description Added, removed or changed step, matched by position
+type SwaggerRecipeStepChange struct {
+	Change     string             `json:"change" example:"added" enums:"added,removed,changed"`
+	StepNumber int                `json:"stepNumber" example:"3"`
+	From       *SwaggerRecipeStep `json:"from,omitempty"`
+	To         *SwaggerRecipeStep `json:"to,omitempty"`
+}
+
+// SwaggerRecipeDiff represents the differences between two recipe revisions
+// @Description Differences between two revisions; "to" is omitted when comparing with the current recipe
+type SwaggerRecipeDiff struct {
+	From        string                          `json:"from" example:"550e8400-e29b-41d4-a716-446655440010"`
+	To          *string                         `json:"to,omitempty" example:"550e8400-e29b-41d4-a716-446655440012"`
+	Fields      []SwaggerRecipeFieldChange      `json:"fields"`
+	Ingredients []SwaggerRecipeIngredientChange `json:"ingredients"`
+	Steps       []SwaggerRecipeStepChange       `json:"steps"`
+}
+
 // ============================================================================
 // Extraction Types
 // ============================================================================
@@ -358,11 +528,40 @@ type SwaggerJobError struct {
 	Retryable bool   `json:"retryable" example:"true"`
 }
 
+// SwaggerPartial represents a recipe preview while extraction is running
+// @Description Best-effort recipe preview, replaced by recipe once the job completes
+type SwaggerPartial struct {
+	Stage        string                     `json:"stage" example:"extracting" enums:"extracting,refining,enriching"`
+	Title        string                     `json:"title,omitempty" example:"Lemon Garlic Pasta"`
+	Description  string                     `json:"description,omitempty"`
+	ThumbnailURL string                     `json:"thumbnailUrl,omitempty" example:"https://example.com/image.jpg"`
+	Servings     int                        `json:"servings,omitempty" example:"2"`
+	PrepTime     int                        `json:"prepTime,omitempty" example:"5"`
+	CookTime     int                        `json:"cookTime,omitempty" example:"12"`
+	Ingredients  []SwaggerPartialIngredient `json:"ingredients"`
+	Steps        []SwaggerPartialStep       `json:"steps"`
+	UpdatedAt    string                     `json:"updatedAt" example:"2024-02-01T10:30:05Z"`
+}
+
+// SwaggerPartialIngredient represents an ingredient in a recipe preview
+type SwaggerPartialIngredient struct {
+	Name     string `json:"name" example:"spaghetti"`
+	Quantity string `json:"quantity,omitempty" example:"200"`
+	Unit     string `json:"unit,omitempty" example:"g"`
+	Section  string `json:"section,omitempty" example:"Main"`
+}
+
+// SwaggerPartialStep represents a step in a recipe preview
+type SwaggerPartialStep struct {
+	StepNumber  int    `json:"stepNumber" example:"1"`
+	Instruction string `json:"instruction" example:"Cook the spaghetti until al dente."`
+}
+
 // SwaggerJobResponse represents job status response
 // @Description Recipe extraction job status
 type SwaggerJobResponse struct {
 	JobID            string           `json:"jobId" example:"550e8400-e29b-41d4-a716-446655440000"`
-	JobType          string           `json:"jobType" example:"url" enums:"url,image,video"`
+	JobType          string           `json:"jobType" example:"url" enums:"url,image,video,import"`
 	Status           string           `json:"status" example:"processing" enums:"pending,downloading,processing,extracting,completed,failed,cancelled"`
 	Progress         int              `json:"progress" example:"45"`
 	Message          string           `json:"message,omitempty" example:"Extracting recipe..."`
@@ -370,15 +569,45 @@ type SwaggerJobResponse struct {
 	StatusURL        string           `json:"statusUrl,omitempty" example:"/api/v1/jobs/550e8400-e29b-41d4-a716-446655440000"`
 	StreamURL        string           `json:"streamUrl,omitempty" example:"/api/v1/jobs/550e8400-e29b-41d4-a716-446655440000/stream"`
 	EstimatedSeconds int              `json:"estimatedSeconds,omitempty" example:"15"`
+	QueuePosition    int              `json:"queuePosition,omitempty" example:"3"`
+	EstimatedWait    int              `json:"estimatedWaitSeconds,omitempty" example:"40"`
 	Recipe           *SwaggerRecipe   `json:"recipe,omitempty"`
+	PartialRecipe    *SwaggerPartial  `json:"partialRecipe,omitempty"`
+	Import           *SwaggerImport   `json:"import,omitempty"`
 	Error            *SwaggerJobError `json:"error,omitempty"`
 	CreatedAt        string           `json:"createdAt" example:"2024-02-01T10:30:00Z"`
 	CompletedAt      *string          `json:"completedAt,omitempty" example:"2024-02-01T10:31:30Z"`
 }
 
-// SwaggerJobListResponse represents list of jobs
-// @Description List of video extraction jobs
-type SwaggerJobListResponse []SwaggerJobResponse
+// SwaggerImport represents the per-recipe outcome of an import job
+// @Description Import progress and results, recipe by recipe
+type SwaggerImport struct {
+	Format   string              `json:"format" example:"paprika" enums:"paprika,mealie,tandoor,whisk,csv"`
+	Total    int                 `json:"total" example:"120"`
+	Imported int                 `json:"imported" example:"112"`
+	Failed   int                 `json:"failed" example:"3"`
+	Skipped  int                 `json:"skipped" example:"5"`
+	Items    []SwaggerImportItem `json:"items"`
+}
+
+// SwaggerImportItem represents the outcome for one recipe in an import
+// @Description Outcome for one recipe in an import file
+type SwaggerImportItem struct {
+	Index    int    `json:"index" example:"0"`
+	Title    string `json:"title" example:"Banana Bread"`
+	Status   string `json:"status" example:"imported" enums:"pending,imported,failed,skipped"`
+	RecipeID string `json:"recipeId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Error    string `json:"error,omitempty" example:"recipe has no title"`
+}
+
+// SwaggerJobListResponse represents a page of job history
+// @Description Page of extraction jobs with per-status counts
+type SwaggerJobListResponse struct {
+	Items        []SwaggerJobResponse `json:"items"`
+	NextCursor   string               `json:"nextCursor,omitempty" example:"MjAyNC0wMi0wMVQxMDozMDowMFp8NTUwZTg0MDA"`
+	StatusCounts map[string]int       `json:"statusCounts"`
+	Total        int                  `json:"total" example:"42"`
+}
 
 // ============================================================================
 // Pantry Types
@@ -412,11 +641,11 @@ type SwaggerPantryItemInput struct {
 // SwaggerPantryListResponse represents paginated pantry list
 // @Description Paginated list of pantry items
 type SwaggerPantryListResponse struct {
-	Items  []SwaggerPantryItem `json:"items"`
-	Total  int                 `json:"total" example:"50"`
-	Count  int                 `json:"count" example:"20"`
-	Limit  int                 `json:"limit" example:"100"`
-	Offset int                 `json:"offset" example:"0"`
+	Items      []SwaggerPantryItem `json:"items"`
+	Total      int                 `json:"total" example:"50"`
+	Count      int                 `json:"count" example:"20"`
+	Limit      int                 `json:"limit" example:"100"`
+	NextCursor string              `json:"nextCursor,omitempty" example:"WyJwYW50cnkiLCJkYWlyeSIsIm1pbGsiXQ"`
 }
 
 // SwaggerPantryExpiringResponse represents expiring items response
@@ -522,8 +751,9 @@ type SwaggerShoppingItemInput struct {
 // SwaggerShoppingListsResponse represents list of shopping lists
 // @Description List of shopping lists
 type SwaggerShoppingListsResponse struct {
-	Lists []SwaggerShoppingList `json:"lists"`
-	Count int                   `json:"count" example:"3"`
+	Lists      []SwaggerShoppingList `json:"lists"`
+	Count      int                   `json:"count" example:"3"`
+	NextCursor string                `json:"nextCursor,omitempty" example:"WyJzaG9wcGluZy1saXN0cyIsIjIwMjQtMDItMDEiXQ"`
 }
 
 // SwaggerShoppingItemsResponse represents list of items
@@ -538,6 +768,7 @@ type SwaggerShoppingItemsResponse struct {
 type SwaggerAddFromRecipeRequest struct {
 	RecipeID    string   `json:"recipeId" example:"550e8400-e29b-41d4-a716-446655440000" binding:"required"`
 	Ingredients []string `json:"ingredients,omitempty" example:"flour,sugar,eggs"`
+	Servings    *int     `json:"servings,omitempty" example:"4"`
 }
 
 // SwaggerAddFromRecipeResponse represents add from recipe response
@@ -563,6 +794,402 @@ type SwaggerAnalyzeResponse struct {
 	MissingStaples  []string                `json:"missingStaples,omitempty" example:"salt,pepper"`
 }
 
+// ============================================================================
+// Collection Types
+// ============================================================================
+
+// SwaggerCollection represents a recipe collection
+// @Description Recipe collection (cookbook)
+type SwaggerCollection struct {
+	ID            string   `json:"id" example:"550e8400-e29b-41d4-a716-446655440020"`
+	UserID        string   `json:"userId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Name          string   `json:"name" example:"Weeknight Dinners"`
+	Description   *string  `json:"description,omitempty" example:"Quick meals under 30 minutes"`
+	CoverImageURL *string  `json:"coverImageUrl,omitempty" example:"https://example.com/cover.jpg"`
+	CoverRecipeID *string  `json:"coverRecipeId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CoverURL      *string  `json:"coverUrl,omitempty" example:"https://example.com/cover.jpg"`
+	SortOrder     int      `json:"sortOrder" example:"0"`
+	RecipeCount   int      `json:"recipeCount" example:"12"`
+	RecipeIDs     []string `json:"recipeIds" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SyncVersion   int      `json:"syncVersion" example:"1"`
+	CreatedAt     string   `json:"createdAt" example:"2024-02-01T10:30:00Z"`
+	UpdatedAt     string   `json:"updatedAt" example:"2024-02-01T10:30:00Z"`
+	DeletedAt     *string  `json:"deletedAt,omitempty"`
+}
+
+// SwaggerCollectionInput represents collection create/update input
+// @Description Collection input for create/update
+type SwaggerCollectionInput struct {
+	Name          string  `json:"name" example:"Weeknight Dinners" binding:"required"`
+	Description   *string `json:"description,omitempty" example:"Quick meals under 30 minutes"`
+	CoverImageURL *string `json:"coverImageUrl,omitempty" example:"https://example.com/cover.jpg"`
+	CoverRecipeID *string `json:"coverRecipeId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// SwaggerCollectionsResponse represents list of collections
+// @Description List of collections
+type SwaggerCollectionsResponse struct {
+	Collections []SwaggerCollection `json:"collections"`
+	Count       int                 `json:"count" example:"4"`
+}
+
+// SwaggerCollectionOrderInput represents a new ordering
+// @Description IDs in the new order
+type SwaggerCollectionOrderInput struct {
+	IDs []string `json:"ids" example:"550e8400-e29b-41d4-a716-446655440020" binding:"required"`
+}
+
+// SwaggerCollectionRecipesInput represents recipes to add to a collection
+// @Description Recipes to add
+type SwaggerCollectionRecipesInput struct {
+	RecipeIDs []string `json:"recipeIds" example:"550e8400-e29b-41d4-a716-446655440000" binding:"required"`
+}
+
+// ============================================================================
+// Sharing Types
+// ============================================================================
+
+// SwaggerRecipeShare represents a recipe shared between users
+// @Description Recipe share with the recipe and sender for display
+type SwaggerRecipeShare struct {
+	ID                 string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440030"`
+	RecipeID           string  `json:"recipeId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OwnerID            string  `json:"ownerId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	RecipientID        *string `json:"recipientId,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	RecipientEmail     *string `json:"recipientEmail,omitempty" example:"friend@example.com"`
+	Status             string  `json:"status" example:"pending" enums:"pending,accepted,declined"`
+	CreatedAt          string  `json:"createdAt" example:"2024-02-01T10:30:00Z"`
+	AcceptedAt         *string `json:"acceptedAt,omitempty" example:"2024-02-02T08:00:00Z"`
+	DeclinedAt         *string `json:"declinedAt,omitempty" example:"2024-02-02T08:00:00Z"`
+	AcceptedRecipeID   *string `json:"acceptedRecipeId,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
+	RecipeTitle        string  `json:"recipeTitle,omitempty" example:"Chocolate Chip Cookies"`
+	RecipeThumbnailURL *string `json:"recipeThumbnailUrl,omitempty" example:"https://example.com/cookies.jpg"`
+	OwnerName          *string `json:"ownerName,omitempty" example:"Alex"`
+}
+
+// SwaggerRecipeShareInput represents a share request
+// @Description Recipient by user ID or email; provide exactly one
+type SwaggerRecipeShareInput struct {
+	RecipientID    *string `json:"recipientId,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	RecipientEmail *string `json:"recipientEmail,omitempty" example:"friend@example.com"`
+}
+
+// SwaggerRecipeSharesResponse represents list of shares
+// @Description List of recipe shares
+type SwaggerRecipeSharesResponse struct {
+	Shares []SwaggerRecipeShare `json:"shares"`
+	Count  int                  `json:"count" example:"2"`
+}
+
+// SwaggerRecipeShareLink represents a public share link
+// @Description Public link to a recipe with its view count
+type SwaggerRecipeShareLink struct {
+	ID           string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440040"`
+	RecipeID     string  `json:"recipeId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID       string  `json:"userId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Token        string  `json:"token" example:"q8V2c0kq3v5fC7m1xY0b2w"`
+	URL          string  `json:"url" example:"https://api.dlishe.com/r/q8V2c0kq3v5fC7m1xY0b2w"`
+	ViewCount    int     `json:"viewCount" example:"12"`
+	LastViewedAt *string `json:"lastViewedAt,omitempty" example:"2024-02-02T08:00:00Z"`
+	CreatedAt    string  `json:"createdAt" example:"2024-02-01T10:30:00Z"`
+}
+
+// SwaggerSharedRecipeResponse represents a recipe opened from a public link
+// @Description Recipe behind a public share link
+type SwaggerSharedRecipeResponse struct {
+	Recipe SwaggerRecipe `json:"recipe"`
+	URL    string        `json:"url" example:"https://api.dlishe.com/r/q8V2c0kq3v5fC7m1xY0b2w"`
+}
+
+// ============================================================================
+// Duplicate Types
+// ============================================================================
+
+// SwaggerDuplicateGroup represents a group of suggested duplicate recipes
+// @Description Recipes that look like the same dish
+type SwaggerDuplicateGroup struct {
+	ID        string          `json:"id" example:"550e8400-e29b-41d4-a716-446655440080"`
+	UserID    string          `json:"userId" example:"550e8400-e29b-41d4-a716-446655440099"`
+	RecipeIDs []string        `json:"recipeIds" example:"550e8400-e29b-41d4-a716-446655440000,550e8400-e29b-41d4-a716-446655440001"`
+	Score     float64         `json:"score" example:"0.82"`
+	CreatedAt string          `json:"createdAt" example:"2026-02-10T19:30:00Z"`
+	Recipes   []SwaggerRecipe `json:"recipes"`
+}
+
+// SwaggerDuplicateGroupListResponse represents the duplicate suggestions
+// @Description Suggested duplicate groups, most alike first
+type SwaggerDuplicateGroupListResponse struct {
+	Items []SwaggerDuplicateGroup `json:"items"`
+}
+
+// SwaggerRecipeMergeInput represents a merge request
+// @Description Recipe to keep and the duplicates to fold into it
+type SwaggerRecipeMergeInput struct {
+	KeepID   string   `json:"keepId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	MergeIDs []string `json:"mergeIds" example:"550e8400-e29b-41d4-a716-446655440001"`
+}
+
+// SwaggerRecipeMergeResult represents the outcome of a merge
+// @Description Kept recipe and what moved to it from the deleted duplicates
+type SwaggerRecipeMergeResult struct {
+	Recipe               SwaggerRecipe `json:"recipe"`
+	Merged               int           `json:"merged" example:"1"`
+	MovedMealPlanEntries int           `json:"movedMealPlanEntries" example:"2"`
+	MovedCookLogs        int           `json:"movedCookLogs" example:"3"`
+	MovedCollections     int           `json:"movedCollections" example:"1"`
+}
+
+// ============================================================================
+// Tag Types
+// ============================================================================
+
+// SwaggerTagCount represents a tag with its recipe count
+// @Description A tag as stored on recipes and how many recipes carry it
+type SwaggerTagCount struct {
+	Tag   string `json:"tag" example:"vegan"`
+	Count int    `json:"count" example:"12"`
+}
+
+// SwaggerTagListResponse represents the user's tags
+// @Description Every tag in the library, most used first
+type SwaggerTagListResponse struct {
+	Items []SwaggerTagCount `json:"items"`
+}
+
+// SwaggerTagRenameInput represents a tag rename request
+// @Description New name for a tag; it is normalized like any saved tag
+type SwaggerTagRenameInput struct {
+	Name string `json:"name" example:"weeknight"`
+}
+
+// SwaggerTagMergeInput represents a tag merge request
+// @Description Tags to replace and the tag to replace them with
+type SwaggerTagMergeInput struct {
+	Tags []string `json:"tags" example:"Vegan,plant-based"`
+	Into string   `json:"into" example:"vegan"`
+}
+
+// SwaggerTagChangeResult represents the outcome of a tag change
+// @Description The resulting tag, the spellings it replaced and how many recipes changed
+type SwaggerTagChangeResult struct {
+	Tag      string   `json:"tag,omitempty" example:"vegan"`
+	Replaced []string `json:"replaced" example:"Vegan,plant-based"`
+	Updated  int      `json:"updated" example:"7"`
+}
+
+// ============================================================================
+// Nutrition Types
+// ============================================================================
+
+// SwaggerNutrients represents amounts of the tracked nutrients
+// @Description Nutrient amounts in grams, except calories (kcal) and the minerals and vitamin C (mg)
+type SwaggerNutrients struct {
+	Calories     float64 `json:"calories" example:"452"`
+	Protein      float64 `json:"protein" example:"24.5"`
+	Carbs        float64 `json:"carbs" example:"38.2"`
+	Fat          float64 `json:"fat" example:"21.3"`
+	SaturatedFat float64 `json:"saturatedFat" example:"8.1"`
+	Fiber        float64 `json:"fiber" example:"4.6"`
+	Sugar        float64 `json:"sugar" example:"6.2"`
+	Sodium       float64 `json:"sodium" example:"610"`
+	Cholesterol  float64 `json:"cholesterol" example:"95"`
+	Calcium      float64 `json:"calcium" example:"180"`
+	Iron         float64 `json:"iron" example:"3.2"`
+	Potassium    float64 `json:"potassium" example:"640"`
+	VitaminC     float64 `json:"vitaminC" example:"12.4"`
+}
+
+// SwaggerIngredientNutrition represents one ingredient's share of a recipe's nutrition
+// @Description An ingredient's matched food, weight and nutrients. Source is usda, ai (estimated, see fallbackReason) or none (left out, see reason).
+type SwaggerIngredientNutrition struct {
+	IngredientID   string            `json:"ingredientId" example:"550e8400-e29b-41d4-a716-446655440010"`
+	Name           string            `json:"name" example:"onion"`
+	Grams          *float64          `json:"grams,omitempty" example:"110"`
+	Source         string            `json:"source" example:"usda" enums:"usda,ai,none"`
+	FoodID         *int              `json:"fdcId,omitempty" example:"170000"`
+	Food           string            `json:"food,omitempty" example:"Onions, raw"`
+	Nutrients      *SwaggerNutrients `json:"nutrients,omitempty"`
+	Reason         string            `json:"reason,omitempty" example:"no quantity"`
+	FallbackReason string            `json:"fallbackReason,omitempty" example:"no matching food"`
+}
+
+// SwaggerNutritionReport represents a recipe's computed nutrition
+// @Description Per-serving and total nutrition with a per-ingredient breakdown. Coverage is the share of ingredients computed from the food database.
+type SwaggerNutritionReport struct {
+	RecipeID    string                       `json:"recipeId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Servings    int                          `json:"servings" example:"4"`
+	PerServing  SwaggerNutrients             `json:"perServing"`
+	Total       SwaggerNutrients             `json:"total"`
+	Tags        []string                     `json:"tags,omitempty" example:"high-protein"`
+	Ingredients []SwaggerIngredientNutrition `json:"ingredients"`
+	Coverage    float64                      `json:"coverage" example:"0.9"`
+}
+
+// ============================================================================
+// Cook Log Types
+// ============================================================================
+
+// SwaggerCookLog represents one time a user cooked a recipe
+// @Description Logged cook with rating, notes, photos and modifications
+type SwaggerCookLog struct {
+	ID                 string   `json:"id" example:"550e8400-e29b-41d4-a716-446655440050"`
+	RecipeID           string   `json:"recipeId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID             string   `json:"userId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	CookedOn           string   `json:"cookedOn" example:"2026-02-10T00:00:00Z"`
+	Servings           *int     `json:"servings,omitempty" example:"4"`
+	Rating             *int     `json:"rating,omitempty" example:"5" minimum:"1" maximum:"5"`
+	Notes              *string  `json:"notes,omitempty" example:"Kids loved it, make double next time"`
+	Photos             []string `json:"photos" example:"https://api.dlishe.com/api/v1/thumbnails/550e8400-e29b-41d4-a716-446655440060.jpg"`
+	Modifications      []string `json:"modifications" example:"Used half the sugar,Swapped cream for yogurt"`
+	CreatedAt          string   `json:"createdAt" example:"2026-02-10T19:30:00Z"`
+	UpdatedAt          string   `json:"updatedAt" example:"2026-02-10T19:30:00Z"`
+	RecipeTitle        string   `json:"recipeTitle,omitempty" example:"Lemon Pasta"`
+	RecipeThumbnailURL *string  `json:"recipeThumbnailUrl,omitempty" example:"https://example.com/pasta.jpg"`
+}
+
+// SwaggerCookLogInput represents cook log create/update input
+// @Description Cook details. Photos are uploaded separately; on update, photos lists the ones to keep.
+type SwaggerCookLogInput struct {
+	CookedOn      string    `json:"cookedOn,omitempty" example:"2026-02-10"`
+	Servings      *int      `json:"servings,omitempty" example:"4"`
+	Rating        *int      `json:"rating,omitempty" example:"5" minimum:"1" maximum:"5"`
+	Notes         *string   `json:"notes,omitempty" example:"Kids loved it, make double next time"`
+	Modifications []string  `json:"modifications,omitempty" example:"Used half the sugar"`
+	Photos        *[]string `json:"photos,omitempty"`
+}
+
+// SwaggerCookLogListResponse represents a page of the cook log
+// @Description Page of logged cooks, most recent first
+type SwaggerCookLogListResponse struct {
+	Items      []SwaggerCookLog `json:"items"`
+	Limit      int              `json:"limit" example:"20"`
+	NextCursor string           `json:"nextCursor,omitempty" example:"WyJjb29rcyIsIjIwMjYtMDItMTAiXQ"`
+}
+
+// ============================================================================
+// Cook Session Types
+// ============================================================================
+
+// SwaggerCookSession represents a recipe being cooked
+// @Description Cook session with the step the cook is on and their timers. recipe (the recipe scaled to the session's servings) is included on single-session responses; recipeTitle and recipeThumbnailUrl on the list.
+type SwaggerCookSession struct {
+	ID                 string             `json:"id" example:"550e8400-e29b-41d4-a716-446655440070"`
+	RecipeID           string             `json:"recipeId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID             string             `json:"userId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Servings           *int               `json:"servings,omitempty" example:"6"`
+	CurrentStep        int                `json:"currentStep" example:"3"`
+	Timers             []SwaggerCookTimer `json:"timers"`
+	CookLogID          *string            `json:"cookLogId,omitempty" example:"550e8400-e29b-41d4-a716-446655440050"`
+	StartedAt          string             `json:"startedAt" example:"2026-02-10T18:45:00Z"`
+	UpdatedAt          string             `json:"updatedAt" example:"2026-02-10T19:02:00Z"`
+	FinishedAt         *string            `json:"finishedAt,omitempty" example:"2026-02-10T19:30:00Z"`
+	RecipeTitle        string             `json:"recipeTitle,omitempty" example:"Lemon Pasta"`
+	RecipeThumbnailURL *string            `json:"recipeThumbnailUrl,omitempty" example:"https://example.com/pasta.jpg"`
+	Recipe             *SwaggerRecipe     `json:"recipe,omitempty"`
+}
+
+// SwaggerCookTimer represents a timer in a cook session
+// @Description Countdown ending at endsAt; remainingSeconds is 0 once it has gone off
+type SwaggerCookTimer struct {
+	ID               string `json:"id" example:"550e8400-e29b-41d4-a716-446655440071"`
+	Name             string `json:"name" example:"Step 3: simmer"`
+	StepNumber       *int   `json:"stepNumber,omitempty" example:"3"`
+	DurationSeconds  int    `json:"durationSeconds" example:"900"`
+	StartedAt        string `json:"startedAt" example:"2026-02-10T19:00:00Z"`
+	EndsAt           string `json:"endsAt" example:"2026-02-10T19:15:00Z"`
+	RemainingSeconds int    `json:"remainingSeconds" example:"780"`
+}
+
+// SwaggerCookSessionStartInput represents cook session start input
+// @Description Servings to cook; leave out to cook the recipe as written
+type SwaggerCookSessionStartInput struct {
+	Servings *int `json:"servings,omitempty" example:"6" minimum:"1" maximum:"100"`
+}
+
+// SwaggerCookSessionUpdateInput represents cook session update input
+// @Description Step number to move to
+type SwaggerCookSessionUpdateInput struct {
+	CurrentStep int `json:"currentStep" example:"4"`
+}
+
+// SwaggerCookTimerInput represents timer start input
+// @Description A step to time, or a name and duration for any other timer
+type SwaggerCookTimerInput struct {
+	StepNumber      *int   `json:"stepNumber,omitempty" example:"3"`
+	Name            string `json:"name,omitempty" example:"Pasta water"`
+	DurationSeconds *int   `json:"durationSeconds,omitempty" example:"600" minimum:"1" maximum:"86400"`
+}
+
+// SwaggerCookSessionFinishInput represents cook session finish input
+// @Description How the cook went, and whether to take the ingredients used out of the pantry
+type SwaggerCookSessionFinishInput struct {
+	Rating        *int     `json:"rating,omitempty" example:"5" minimum:"1" maximum:"5"`
+	Notes         *string  `json:"notes,omitempty" example:"Needed more lemon"`
+	Modifications []string `json:"modifications,omitempty" example:"Added chilli flakes"`
+	DeductPantry  bool     `json:"deductPantry" example:"true"`
+}
+
+// SwaggerPantryDeduction represents a pantry item drawn down by a cook
+// @Description Amount used and left, in the pantry item's unit
+type SwaggerPantryDeduction struct {
+	ItemID    string  `json:"itemId" example:"550e8400-e29b-41d4-a716-446655440080"`
+	Name      string  `json:"name" example:"Spaghetti"`
+	Used      float64 `json:"used" example:"375"`
+	Unit      *string `json:"unit,omitempty" example:"g"`
+	Remaining float64 `json:"remaining" example:"125"`
+	Removed   bool    `json:"removed" example:"false"`
+}
+
+// SwaggerCookSessionFinishResult represents a finished cook session
+// @Description Finished session, the cook it logged and what it took from the pantry
+type SwaggerCookSessionFinishResult struct {
+	Session     SwaggerCookSession       `json:"session"`
+	CookLog     SwaggerCookLog           `json:"cookLog"`
+	Pantry      []SwaggerPantryDeduction `json:"pantry"`
+	PantryError string                   `json:"pantryError,omitempty" example:"Your pantry could not be fully updated"`
+}
+
+// SwaggerCookSessionListResponse represents the cook sessions in progress
+// @Description Sessions in progress, most recently touched first
+type SwaggerCookSessionListResponse struct {
+	Items []SwaggerCookSession `json:"items"`
+}
+
+// ============================================================================
+// Annotation Types
+// ============================================================================
+
+// SwaggerRecipeAnnotation represents a user's note on an ingredient or step
+// @Description Private note on a recipe ingredient or step
+type SwaggerRecipeAnnotation struct {
+	ID          string  `json:"id" example:"550e8400-e29b-41d4-a716-446655440070"`
+	UserID      string  `json:"userId" example:"550e8400-e29b-41d4-a716-446655440099"`
+	RecipeID    string  `json:"recipeId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Target      string  `json:"target" example:"ingredient" enums:"ingredient,step"`
+	TargetID    string  `json:"targetId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Anchor      string  `json:"anchor" example:"salt"`
+	Position    int     `json:"position" example:"3"`
+	Note        string  `json:"note" example:"Use less salt"`
+	SyncVersion int     `json:"syncVersion" example:"1"`
+	CreatedAt   string  `json:"createdAt" example:"2026-02-10T19:30:00Z"`
+	UpdatedAt   string  `json:"updatedAt" example:"2026-02-10T19:30:00Z"`
+	DeletedAt   *string `json:"deletedAt,omitempty"`
+}
+
+// SwaggerAnnotationInput represents a note to set
+// @Description Note on one of the recipe's ingredients or steps
+type SwaggerAnnotationInput struct {
+	Target   string `json:"target" example:"ingredient" enums:"ingredient,step"`
+	TargetID string `json:"targetId" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Note     string `json:"note" example:"Use less salt"`
+}
+
+// SwaggerAnnotationListResponse represents a user's notes on a recipe
+// @Description Notes on a recipe's ingredients and steps
+type SwaggerAnnotationListResponse struct {
+	Items []SwaggerRecipeAnnotation `json:"items"`
+}
+
 // ============================================================================
 // Sync Types
 // ============================================================================
@@ -570,31 +1197,36 @@ type SwaggerAnalyzeResponse struct {
 // SwaggerSyncRequest represents sync request
 // @Description Multi-device sync request
 type SwaggerSyncRequest struct {
-	LastSyncTimestamp string                `json:"lastSyncTimestamp" example:"2024-02-01T10:30:00Z" binding:"required"`
-	Recipes           []SwaggerRecipe       `json:"recipes,omitempty"`
-	PantryItems       []SwaggerPantryItem   `json:"pantryItems,omitempty"`
-	ShoppingLists     []SwaggerShoppingList `json:"shoppingLists,omitempty"`
-	ShoppingItems     []SwaggerShoppingItem `json:"shoppingItems,omitempty"`
+	LastSyncTimestamp string                    `json:"lastSyncTimestamp" example:"2024-02-01T10:30:00Z" binding:"required"`
+	Recipes           []SwaggerRecipe           `json:"recipes,omitempty"`
+	PantryItems       []SwaggerPantryItem       `json:"pantryItems,omitempty"`
+	ShoppingLists     []SwaggerShoppingList     `json:"shoppingLists,omitempty"`
+	ShoppingItems     []SwaggerShoppingItem     `json:"shoppingItems,omitempty"`
+	Collections       []SwaggerCollection       `json:"collections,omitempty"`
+	Annotations       []SwaggerRecipeAnnotation `json:"annotations,omitempty"`
 }
 
 // SwaggerConflict represents a sync conflict
 // @Description Sync conflict details
 type SwaggerConflict struct {
-	ResourceType string `json:"resourceType" example:"recipe" enums:"recipe,pantry_item,shopping_list,shopping_item"`
-	ResourceID   string `json:"resourceId" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Resolution   string `json:"resolution" example:"server_wins" enums:"server_wins,client_wins,merged"`
-	Reason       string `json:"reason" example:"Server version is newer"`
+	ResourceType string  `json:"resourceType" example:"recipe" enums:"recipe,pantry_item,shopping_list,shopping_item,collection"`
+	ResourceID   string  `json:"resourceId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Resolution   string  `json:"resolution" example:"server_wins" enums:"server_wins,client_wins,merged"`
+	Reason       string  `json:"reason" example:"Server version is newer"`
+	RevisionID   *string `json:"revisionId,omitempty" example:"550e8400-e29b-41d4-a716-446655440010"`
 }
 
 // SwaggerSyncResponse represents sync response
 // @Description Multi-device sync response
 type SwaggerSyncResponse struct {
-	ServerTimestamp string                `json:"serverTimestamp" example:"2024-02-01T10:35:00Z"`
-	Recipes         []SwaggerRecipe       `json:"recipes,omitempty"`
-	PantryItems     []SwaggerPantryItem   `json:"pantryItems,omitempty"`
-	ShoppingLists   []SwaggerShoppingList `json:"shoppingLists,omitempty"`
-	ShoppingItems   []SwaggerShoppingItem `json:"shoppingItems,omitempty"`
-	Conflicts       []SwaggerConflict     `json:"conflicts,omitempty"`
+	ServerTimestamp string                    `json:"serverTimestamp" example:"2024-02-01T10:35:00Z"`
+	Recipes         []SwaggerRecipe           `json:"recipes,omitempty"`
+	PantryItems     []SwaggerPantryItem       `json:"pantryItems,omitempty"`
+	ShoppingLists   []SwaggerShoppingList     `json:"shoppingLists,omitempty"`
+	ShoppingItems   []SwaggerShoppingItem     `json:"shoppingItems,omitempty"`
+	Collections     []SwaggerCollection       `json:"collections,omitempty"`
+	Annotations     []SwaggerRecipeAnnotation `json:"annotations,omitempty"`
+	Conflicts       []SwaggerConflict         `json:"conflicts,omitempty"`
 }
 
 // ============================================================================
@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/pkg/tag"
+)
+
+// TagHandler handles renaming, merging and cleaning up tags across a
+// user's recipe library
+type TagHandler struct {
+	tagRepo    TagRepository
+	normalizer *tag.Normalizer
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagRepo TagRepository, normalizer *tag.Normalizer) *TagHandler {
+	return &TagHandler{
+		tagRepo:    tagRepo,
+		normalizer: normalizer,
+	}
+}
+
+// List handles GET /api/v1/tags
+// @Summary List tags
+// @Description Get every tag on your recipes, spelled as stored, with how many recipes carry it, most used first
+// @Tags Tags
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SwaggerTagListResponse "Tags with recipe counts"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Router /tags [get]
+func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	tags, err := h.tagRepo.List(r.Context(), user.ID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, map[string]interface{}{
+		"items": tags,
+	})
+}
+
+// Rename handles PUT /api/v1/tags/{tag}
+// @Summary Rename a tag
+// @Description Rename a tag on every recipe carrying it. Spellings that normalize the same ("Vegan", "vegan") are renamed together, and the new name is normalized too. Renaming to a tag that already exists merges the two.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tag path string true "Tag to rename (URL-encoded)"
+// @Param body body SwaggerTagRenameInput true "New name"
+// @Success 200 {object} SwaggerTagChangeResult "Renamed"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid input"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Tag not found"
+// @Router /tags/{tag} [put]
+func (h *TagHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	var input model.TagRenameInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	h.replace(w, r, user.ID, []string{tagParam(r)}, h.normalizer.Tag(input.Name))
+}
+
+// Merge handles POST /api/v1/tags/merge
+// @Summary Merge tags
+// @Description Replace several tags with one on every recipe carrying any of them; a recipe with more than one keeps the merged tag once. Each tag also matches spellings that normalize the same.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body SwaggerTagMergeInput true "Tags to merge and the tag to merge them into"
+// @Success 200 {object} SwaggerTagChangeResult "Merged"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid input"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Tag not found"
+// @Router /tags/merge [post]
+func (h *TagHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	var input model.TagMergeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !validateInput(w, input.Validate()) {
+		return
+	}
+
+	h.replace(w, r, user.ID, input.Tags, h.normalizer.Tag(input.Into))
+}
+
+// Delete handles DELETE /api/v1/tags/{tag}
+// @Summary Delete a tag
+// @Description Remove a tag, and spellings that normalize the same, from every recipe carrying it
+// @Tags Tags
+// @Produce json
+// @Security BearerAuth
+// @Param tag path string true "Tag to delete (URL-encoded)"
+// @Success 200 {object} SwaggerTagChangeResult "Deleted"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Failure 404 {object} SwaggerErrorResponse "Tag not found"
+// @Router /tags/{tag} [delete]
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	h.replace(w, r, user.ID, []string{tagParam(r)}, "")
+}
+
+// Normalize handles POST /api/v1/tags/normalize
+// @Summary Normalize all tags
+// @Description Apply tag normalization to the whole library: lowercase, trim and replace synonyms (e.g. "plant-based" becomes "vegan"). New and edited recipes are normalized as they're saved; this cleans up tags saved before.
+// @Tags Tags
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SwaggerTagChangeResult "Tags changed and recipes updated"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Router /tags/normalize [post]
+func (h *TagHandler) Normalize(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	tags, err := h.tagRepo.List(r.Context(), user.ID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	result := &model.TagChangeResult{Replaced: []string{}}
+	replacements := make(map[string]string)
+	for _, t := range tags {
+		if normalized := h.normalizer.Tag(t.Tag); normalized != t.Tag {
+			replacements[t.Tag] = normalized
+			result.Replaced = append(result.Replaced, t.Tag)
+		}
+	}
+
+	if result.Updated, err = h.tagRepo.Replace(r.Context(), user.ID, replacements); err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, result)
+}
+
+// replace swaps every stored spelling of names for to, or removes them when
+// to is empty
+func (h *TagHandler) replace(w http.ResponseWriter, r *http.Request, userID uuid.UUID, names []string, to string) {
+	tags, err := h.tagRepo.List(r.Context(), userID)
+	if err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[h.normalizer.Tag(name)] = true
+	}
+
+	result := &model.TagChangeResult{Tag: to, Replaced: []string{}}
+	replacements := make(map[string]string)
+	found := false
+	for _, t := range tags {
+		if !wanted[h.normalizer.Tag(t.Tag)] {
+			continue
+		}
+		found = true
+		if t.Tag != to {
+			replacements[t.Tag] = to
+			result.Replaced = append(result.Replaced, t.Tag)
+		}
+	}
+	if !found {
+		response.NotFound(w, "Tag")
+		return
+	}
+
+	if result.Updated, err = h.tagRepo.Replace(r.Context(), userID, replacements); err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.OK(w, result)
+}
+
+// tagParam reads the {tag} path parameter, which may arrive still escaped
+// when it holds a slash
+func tagParam(r *http.Request) string {
+	raw := chi.URLParam(r, "tag")
+	if name, err := url.PathUnescape(raw); err == nil {
+		return name
+	}
+	return raw
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/tag"
+)
+
+func newTagTestHandler() (*TagHandler, *map[string]string) {
+	replaced := new(map[string]string)
+	repo := &mockTagRepository{
+		ListFunc: func(ctx context.Context, userID uuid.UUID) ([]model.TagCount, error) {
+			return []model.TagCount{
+				{Tag: "vegan", Count: 5},
+				{Tag: "Vegan", Count: 2},
+				{Tag: "plant-based", Count: 1},
+				{Tag: "Weeknight", Count: 3},
+				{Tag: "dinner", Count: 4},
+			}, nil
+		},
+		ReplaceFunc: func(ctx context.Context, userID uuid.UUID, replacements map[string]string) (int, error) {
+			*replaced = replacements
+			return len(replacements), nil
+		},
+	}
+	return NewTagHandler(repo, tag.NewNormalizer(map[string]string{"weeknight": "quick"})), replaced
+}
+
+func decodeTagResult(t *testing.T, rr *httptest.ResponseRecorder) model.TagChangeResult {
+	t.Helper()
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var result model.TagChangeResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestTagHandler_Rename(t *testing.T) {
+	handler, replaced := newTagTestHandler()
+	user := &model.User{ID: uuid.New()}
+
+	t.Run("renames every spelling", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Rename(rr, shareRequest("PUT", "/", `{"name":" Meatless "}`, user, map[string]string{"tag": "VEGAN"}))
+
+		result := decodeTagResult(t, rr)
+		want := map[string]string{"vegan": "meatless", "Vegan": "meatless", "plant-based": "meatless"}
+		if !maps.Equal(*replaced, want) {
+			t.Errorf("replacements %v, want %v", *replaced, want)
+		}
+		if result.Tag != "meatless" || len(result.Replaced) != 3 || result.Updated != 3 {
+			t.Errorf("unexpected result %+v", result)
+		}
+	})
+
+	t.Run("escaped path", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Rename(rr, shareRequest("PUT", "/", `{"name":"quick"}`, user, map[string]string{"tag": "weeknight%20"}))
+		decodeTagResult(t, rr)
+		if !maps.Equal(*replaced, map[string]string{"Weeknight": "quick"}) {
+			t.Errorf("replacements %v", *replaced)
+		}
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Rename(rr, shareRequest("PUT", "/", `{"name":"x"}`, user, map[string]string{"tag": "brunch"}))
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("blank name", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Rename(rr, shareRequest("PUT", "/", `{"name":"  "}`, user, map[string]string{"tag": "vegan"}))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
+
+func TestTagHandler_Merge(t *testing.T) {
+	handler, replaced := newTagTestHandler()
+	user := &model.User{ID: uuid.New()}
+
+	rr := httptest.NewRecorder()
+	handler.Merge(rr, shareRequest("POST", "/", `{"tags":["Weeknight","dinner"],"into":"Supper"}`, user, nil))
+
+	result := decodeTagResult(t, rr)
+	if !maps.Equal(*replaced, map[string]string{"Weeknight": "supper", "dinner": "supper"}) {
+		t.Errorf("replacements %v", *replaced)
+	}
+	if result.Tag != "supper" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.Merge(rr, shareRequest("POST", "/", `{"tags":[],"into":"supper"}`, user, nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without tags, got %d", rr.Code)
+	}
+}
+
+func TestTagHandler_Delete(t *testing.T) {
+	handler, replaced := newTagTestHandler()
+	user := &model.User{ID: uuid.New()}
+
+	rr := httptest.NewRecorder()
+	handler.Delete(rr, shareRequest("DELETE", "/", "", user, map[string]string{"tag": "dinner"}))
+
+	result := decodeTagResult(t, rr)
+	if !maps.Equal(*replaced, map[string]string{"dinner": ""}) || result.Tag != "" {
+		t.Errorf("replacements %v, result %+v", *replaced, result)
+	}
+}
+
+func TestTagHandler_Normalize(t *testing.T) {
+	handler, replaced := newTagTestHandler()
+	user := &model.User{ID: uuid.New()}
+
+	rr := httptest.NewRecorder()
+	handler.Normalize(rr, shareRequest("POST", "/", "", user, nil))
+
+	decodeTagResult(t, rr)
+	want := map[string]string{"Vegan": "vegan", "plant-based": "vegan", "Weeknight": "quick"}
+	if !maps.Equal(*replaced, want) {
+		t.Errorf("replacements %v, want %v", *replaced, want)
+	}
+}
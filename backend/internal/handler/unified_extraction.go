@@ -13,7 +13,6 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -27,6 +26,8 @@ import (
 	"github.com/dishflow/backend/internal/pkg/response"
 	"github.com/dishflow/backend/internal/repository/postgres"
 	"github.com/dishflow/backend/internal/service/ai"
+	"github.com/dishflow/backend/internal/service/nutrition"
+	"github.com/dishflow/backend/internal/service/scheduler"
 )
 
 // UnifiedExtractionHandler handles all recipe extraction types (url, image, video)
@@ -45,11 +46,10 @@ type UnifiedExtractionHandler struct {
 	// activeJobs stores cancel functions for running jobs
 	activeJobs sync.Map // map[uuid.UUID]context.CancelFunc
 
-	// semaphores limit concurrent processing jobs
-	// Video jobs are heavy (download+processing), so we limit them strictly
-	// URL/Image jobs are lighter, so we allow more concurrency
-	videoSemaphore chan struct{}
-	lightSemaphore chan struct{}
+	// scheduler hands out processing slots by priority class with per-user caps.
+	// Video jobs are heavy (download+processing) and run in their own lane;
+	// URL/Image jobs are lighter and share a larger lane.
+	scheduler *scheduler.Scheduler
 
 	// tempDir for storing temporary files
 	tempDir string
@@ -61,6 +61,9 @@ type UnifiedExtractionHandler struct {
 
 	// thumbDownloader downloads remote thumbnails to local disk
 	thumbDownloader ThumbnailDownloader
+
+	// nutrition computes saved recipes' nutrition from the food database
+	nutrition NutritionCalculator
 }
 
 // NewUnifiedExtractionHandler creates a new unified extraction handler
@@ -78,8 +81,8 @@ func NewUnifiedExtractionHandler(
 	logger *slog.Logger,
 	adminEmails []string,
 	inspiratorEmails []string,
-	maxVideoJobs int,
-	maxLightJobs int,
+	jobScheduler *scheduler.Scheduler,
+	nutrition NutritionCalculator,
 ) *UnifiedExtractionHandler {
 	h := &UnifiedExtractionHandler{
 		jobRepo:             jobRepo,
@@ -93,11 +96,11 @@ func NewUnifiedExtractionHandler(
 		thumbDownloader:     thumbDownloader,
 		redis:               redisClient,
 		logger:              logger,
-		videoSemaphore:      make(chan struct{}, maxVideoJobs),
-		lightSemaphore:      make(chan struct{}, maxLightJobs),
+		scheduler:           jobScheduler,
 		tempDir:             os.TempDir(),
 		adminEmails:         adminEmails,
 		inspiratorEmails:    inspiratorEmails,
+		nutrition:           nutrition,
 	}
 
 	// Cleanup orphaned temp files from previous crashes
@@ -190,6 +193,13 @@ func (h *UnifiedExtractionHandler) Extract(w http.ResponseWriter, r *http.Reques
 	isAdmin := model.IsAdminEmail(user.Email, h.adminEmails)
 	isInspirator := model.IsInspiratorEmail(user.Email, h.inspiratorEmails)
 
+	// Admin/inspirator extractions are bulk imports and yield to interactive users;
+	// everyone else is scheduled by subscription tier below
+	priority := scheduler.PriorityFree
+	if isAdmin || isInspirator {
+		priority = scheduler.PriorityBulk
+	}
+
 	// Enforce subscription tier limits on extractions (admins and inspirators bypass)
 	if !isAdmin && !isInspirator && h.userRepo != nil {
 		sub, err := h.userRepo.GetSubscription(r.Context(), user.ID)
@@ -205,6 +215,9 @@ func (h *UnifiedExtractionHandler) Extract(w http.ResponseWriter, r *http.Reques
 		if !ok {
 			limits = model.TierLimits["free"]
 		}
+		if entitlement != "free" && ok {
+			priority = scheduler.PriorityPro
+		}
 		if limits.Extractions < 0 {
 			// Unlimited — skip check
 		} else {
@@ -564,21 +577,26 @@ func (h *UnifiedExtractionHandler) Extract(w http.ResponseWriter, r *http.Reques
 			}
 		}()
 
-		// Acquire semaphore based on job type
-		var semaphore chan struct{}
+		// Wait for a processing slot in the job's lane
+		lane := scheduler.LaneLight
 		if job.JobType == model.JobTypeVideo {
-			semaphore = h.videoSemaphore
-		} else {
-			semaphore = h.lightSemaphore
+			lane = scheduler.LaneVideo
 		}
-
-		select {
-		case semaphore <- struct{}{}:
-			defer func() { <-semaphore }()
-		case <-ctx.Done():
+		release, err := h.scheduler.Acquire(ctx, scheduler.Request{
+			JobID:    job.ID,
+			UserID:   job.UserID,
+			Lane:     lane,
+			Priority: priority,
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				// Cancelled by the user while queued; CancelJob already updated the status
+				return
+			}
 			h.jobRepo.MarkFailed(context.Background(), job.ID, "TIMEOUT", "Job timed out waiting for processing slot")
 			return
 		}
+		defer release()
 
 		h.processJob(ctx, job, isAdmin, isInspirator)
 	}()
@@ -646,6 +664,11 @@ func (h *UnifiedExtractionHandler) processJob(ctx context.Context, job *model.Ex
 		return
 	}
 
+	// Stream Gemini output so title, thumbnail and ingredients can be previewed
+	// (JobResponse.PartialRecipe) before refinement and enrichment finish
+	partials := &partialRecipeWriter{jobRepo: h.jobRepo, jobID: job.ID}
+	ctx = withPartialRecipeWriter(ctx, partials)
+
 	var result *ai.ExtractionResult
 
 	switch job.JobType {
@@ -688,6 +711,7 @@ func (h *UnifiedExtractionHandler) processJob(ctx context.Context, job *model.Ex
 	}
 
 	// Refine the recipe
+	partials.observe(ctx, model.PartialStageRefining, result, true)
 	updateProgress(model.JobStatusExtracting, 85, "Refining recipe...")
 	refined, refineErr := h.extractor.RefineRecipe(ctx, result)
 	if refineErr == nil && refined != nil {
@@ -702,6 +726,7 @@ func (h *UnifiedExtractionHandler) processJob(ctx context.Context, job *model.Ex
 	// Enrich with nutrition and dietary info
 	var enrichment *ai.EnrichmentResult
 	if h.enricher != nil {
+		partials.observe(ctx, model.PartialStageEnriching, result, true)
 		updateProgress(model.JobStatusExtracting, 90, "Analyzing nutrition and dietary info...")
 		enrichInput := h.extractionResultToEnrichmentInput(result)
 		enrichment, err = h.enricher.EnrichRecipe(ctx, enrichInput)
@@ -927,6 +952,7 @@ func (h *UnifiedExtractionHandler) processYouTubeExtraction(ctx context.Context,
 	// Fetch metadata via oEmbed (best-effort, non-blocking on failure)
 	oembed := fetchYouTubeOEmbed(ctx, job.SourceURL)
 	thumbnailURL := oembed.ThumbnailURL
+	partialWriterFrom(ctx).setThumbnail(thumbnailURL)
 
 	var metadataStr string
 	if oembed.Title != "" {
@@ -987,6 +1013,7 @@ func (h *UnifiedExtractionHandler) processInstagramExtraction(ctx context.Contex
 
 	if thumbnailURL != "" {
 		h.logger.Info("Instagram thumbnail URL extracted", "url", thumbnailURL)
+		partialWriterFrom(ctx).setThumbnail(thumbnailURL)
 	}
 
 	// Fetch metadata for context
@@ -1040,6 +1067,7 @@ func (h *UnifiedExtractionHandler) processYtDlpExtraction(ctx context.Context, j
 	// Log thumbnail URL if found
 	if thumbnailURL != "" {
 		h.logger.Info("Thumbnail CDN URL extracted", "url", thumbnailURL)
+		partialWriterFrom(ctx).setThumbnail(thumbnailURL)
 	}
 
 	// Fetch metadata (title, description)
@@ -1312,60 +1340,7 @@ func (h *UnifiedExtractionHandler) saveExtractedRecipe(ctx context.Context, job
 		recipe.FeaturedAt = &now
 	}
 
-	// Apply enrichment data
-	if enrichment != nil {
-		// Use servings estimate if extraction didn't provide
-		if recipe.Servings == nil && enrichment.ServingsEstimate != nil && enrichment.ServingsEstimate.Confidence >= ai.MinConfidenceThreshold {
-			recipe.Servings = &enrichment.ServingsEstimate.Value
-		}
-
-		// Apply nutrition
-		if enrichment.Nutrition != nil && enrichment.Nutrition.Confidence >= ai.MinConfidenceThreshold {
-			recipe.Nutrition = &model.RecipeNutrition{
-				Calories:   enrichment.Nutrition.PerServing.Calories,
-				Protein:    enrichment.Nutrition.PerServing.Protein,
-				Carbs:      enrichment.Nutrition.PerServing.Carbs,
-				Fat:        enrichment.Nutrition.PerServing.Fat,
-				Fiber:      enrichment.Nutrition.PerServing.Fiber,
-				Sugar:      enrichment.Nutrition.PerServing.Sugar,
-				Sodium:     enrichment.Nutrition.PerServing.Sodium,
-				Tags:       enrichment.Nutrition.Tags,
-				Confidence: enrichment.Nutrition.Confidence,
-			}
-		}
-
-		// Apply dietary info
-		if enrichment.DietaryInfo != nil && enrichment.DietaryInfo.Confidence >= ai.MinConfidenceThreshold {
-			recipe.DietaryInfo = &model.DietaryInfo{
-				Allergens: enrichment.DietaryInfo.Allergens,
-				MealTypes: enrichment.DietaryInfo.MealTypes,
-			}
-			if enrichment.DietaryInfo.IsVegetarian != nil {
-				recipe.DietaryInfo.IsVegetarian = *enrichment.DietaryInfo.IsVegetarian
-			}
-			if enrichment.DietaryInfo.IsVegan != nil {
-				recipe.DietaryInfo.IsVegan = *enrichment.DietaryInfo.IsVegan
-			}
-			if enrichment.DietaryInfo.IsGlutenFree != nil {
-				recipe.DietaryInfo.IsGlutenFree = *enrichment.DietaryInfo.IsGlutenFree
-			}
-			if enrichment.DietaryInfo.IsDairyFree != nil {
-				recipe.DietaryInfo.IsDairyFree = *enrichment.DietaryInfo.IsDairyFree
-			}
-			if enrichment.DietaryInfo.IsNutFree != nil {
-				recipe.DietaryInfo.IsNutFree = *enrichment.DietaryInfo.IsNutFree
-			}
-			if enrichment.DietaryInfo.IsKeto != nil {
-				recipe.DietaryInfo.IsKeto = *enrichment.DietaryInfo.IsKeto
-			}
-			if enrichment.DietaryInfo.IsHalal != nil {
-				recipe.DietaryInfo.IsHalal = *enrichment.DietaryInfo.IsHalal
-			}
-			if enrichment.DietaryInfo.IsKosher != nil {
-				recipe.DietaryInfo.IsKosher = *enrichment.DietaryInfo.IsKosher
-			}
-		}
-	}
+	applyEnrichment(recipe, enrichment)
 
 	// Convert ingredients
 	for i, ing := range result.Ingredients {
@@ -1373,7 +1348,7 @@ func (h *UnifiedExtractionHandler) saveExtractedRecipe(ctx context.Context, job
 			continue
 		}
 
-		qty := parseQuantity(ing.Quantity)
+		amount := parseIngredientAmount(ing.Quantity, ing.Unit)
 		category := model.NormalizeCategory(ing.Category)
 
 		section := ing.Section
@@ -1382,16 +1357,17 @@ func (h *UnifiedExtractionHandler) saveExtractedRecipe(ctx context.Context, job
 		}
 
 		recipe.Ingredients = append(recipe.Ingredients, model.RecipeIngredient{
-			ID:         uuid.New(),
-			RecipeID:   recipe.ID,
-			Name:       ing.Name,
-			Quantity:   qty,
-			Unit:       stringPtr(ing.Unit),
-			Category:   category,
-			Section:    section,
-			IsOptional: ing.IsOptional,
-			Notes:      stringPtr(ing.Notes),
-			SortOrder:  i,
+			ID:          uuid.New(),
+			RecipeID:    recipe.ID,
+			Name:        ing.Name,
+			Quantity:    amount.Quantity,
+			QuantityMax: amount.QuantityMax,
+			Unit:        stringPtr(amount.Unit),
+			Category:    category,
+			Section:     section,
+			IsOptional:  ing.IsOptional || amount.IsOptional,
+			Notes:       stringPtr(joinNotes(amount.AllNotes(), ing.Notes)),
+			SortOrder:   i,
 		})
 	}
 
@@ -1408,6 +1384,8 @@ func (h *UnifiedExtractionHandler) saveExtractedRecipe(ctx context.Context, job
 		})
 	}
 
+	h.applyNutrition(ctx, recipe)
+
 	if err := h.recipeRepo.Create(ctx, recipe); err != nil {
 		return uuid.Nil, err
 	}
@@ -1415,6 +1393,86 @@ func (h *UnifiedExtractionHandler) saveExtractedRecipe(ctx context.Context, job
 	return recipe.ID, nil
 }
 
+// applyNutrition replaces the recipe's AI nutrition estimate with one
+// computed from the food database, when enough of its ingredients matched a
+// food to be trusted over the estimate
+func (h *UnifiedExtractionHandler) applyNutrition(ctx context.Context, recipe *model.Recipe) {
+	if h.nutrition == nil || recipe.Servings == nil || len(recipe.Ingredients) == 0 {
+		return
+	}
+
+	report, err := h.nutrition.Calculate(ctx, recipe)
+	if err != nil {
+		h.logger.Warn("Nutrition calculation failed, keeping AI estimate", "error", err, "title", recipe.Title)
+		return
+	}
+	if report.Coverage < nutrition.MinCoverage {
+		return
+	}
+	recipe.Nutrition = report.Summary()
+}
+
+// applyEnrichment fills in servings, nutrition and dietary info from an
+// enrichment result where the AI was confident enough. Servings and
+// nutrition the recipe already has are kept.
+func applyEnrichment(recipe *model.Recipe, enrichment *ai.EnrichmentResult) {
+	if enrichment == nil {
+		return
+	}
+
+	// Use servings estimate if extraction didn't provide
+	if recipe.Servings == nil && enrichment.ServingsEstimate != nil && enrichment.ServingsEstimate.Confidence >= ai.MinConfidenceThreshold {
+		recipe.Servings = &enrichment.ServingsEstimate.Value
+	}
+
+	// Apply nutrition
+	if recipe.Nutrition == nil && enrichment.Nutrition != nil && enrichment.Nutrition.Confidence >= ai.MinConfidenceThreshold {
+		recipe.Nutrition = &model.RecipeNutrition{
+			Calories:   enrichment.Nutrition.PerServing.Calories,
+			Protein:    enrichment.Nutrition.PerServing.Protein,
+			Carbs:      enrichment.Nutrition.PerServing.Carbs,
+			Fat:        enrichment.Nutrition.PerServing.Fat,
+			Fiber:      enrichment.Nutrition.PerServing.Fiber,
+			Sugar:      enrichment.Nutrition.PerServing.Sugar,
+			Sodium:     enrichment.Nutrition.PerServing.Sodium,
+			Tags:       enrichment.Nutrition.Tags,
+			Confidence: enrichment.Nutrition.Confidence,
+		}
+	}
+
+	// Apply dietary info
+	if enrichment.DietaryInfo != nil && enrichment.DietaryInfo.Confidence >= ai.MinConfidenceThreshold {
+		recipe.DietaryInfo = &model.DietaryInfo{
+			Allergens: enrichment.DietaryInfo.Allergens,
+			MealTypes: enrichment.DietaryInfo.MealTypes,
+		}
+		if enrichment.DietaryInfo.IsVegetarian != nil {
+			recipe.DietaryInfo.IsVegetarian = *enrichment.DietaryInfo.IsVegetarian
+		}
+		if enrichment.DietaryInfo.IsVegan != nil {
+			recipe.DietaryInfo.IsVegan = *enrichment.DietaryInfo.IsVegan
+		}
+		if enrichment.DietaryInfo.IsGlutenFree != nil {
+			recipe.DietaryInfo.IsGlutenFree = *enrichment.DietaryInfo.IsGlutenFree
+		}
+		if enrichment.DietaryInfo.IsDairyFree != nil {
+			recipe.DietaryInfo.IsDairyFree = *enrichment.DietaryInfo.IsDairyFree
+		}
+		if enrichment.DietaryInfo.IsNutFree != nil {
+			recipe.DietaryInfo.IsNutFree = *enrichment.DietaryInfo.IsNutFree
+		}
+		if enrichment.DietaryInfo.IsKeto != nil {
+			recipe.DietaryInfo.IsKeto = *enrichment.DietaryInfo.IsKeto
+		}
+		if enrichment.DietaryInfo.IsHalal != nil {
+			recipe.DietaryInfo.IsHalal = *enrichment.DietaryInfo.IsHalal
+		}
+		if enrichment.DietaryInfo.IsKosher != nil {
+			recipe.DietaryInfo.IsKosher = *enrichment.DietaryInfo.IsKosher
+		}
+	}
+}
+
 // CancelJobInternal cancels a running extraction job (internal use)
 func (h *UnifiedExtractionHandler) CancelJobInternal(jobID uuid.UUID) {
 	if cancelFn, ok := h.activeJobs.Load(jobID); ok {
@@ -1544,7 +1602,27 @@ func (h *UnifiedExtractionHandler) CancelJob(w http.ResponseWriter, r *http.Requ
 	response.NoContent(w)
 }
 
-// ListJobs returns a list of jobs for the user
+// ListJobs returns the user's job history
+// @Summary List extraction jobs
+// @Description Job history, newest first, with filters, text search and cursor pagination.
+// @Description statusCounts ignores the status filter so every status tab can show a count.
+// @Description Every request gets this envelope, including ones that page with offset instead of cursor.
+// @Tags Jobs
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Comma-separated statuses" example(failed,cancelled)
+// @Param type query string false "Comma-separated job types" example(url,video)
+// @Param from query string false "Created on or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339), or through the given day (YYYY-MM-DD)"
+// @Param domain query string false "Source domain, subdomains included" example(allrecipes.com)
+// @Param q query string false "Search source URL and recipe title"
+// @Param cursor query string false "Cursor from a previous page's nextCursor"
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param offset query int false "Rows to skip, for clients that page by offset; ignored with cursor"
+// @Success 200 {object} SwaggerJobListResponse "Page of jobs"
+// @Failure 400 {object} SwaggerErrorResponse "Invalid filter or cursor"
+// @Failure 401 {object} SwaggerErrorResponse "Unauthorized"
+// @Router /jobs [get]
 func (h *UnifiedExtractionHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetUserFromContext(r.Context())
 	if user == nil {
@@ -1552,46 +1630,166 @@ func (h *UnifiedExtractionHandler) ListJobs(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	limit := 20
-	offset := 0
-
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= 100 {
-			limit = val
+	filter, err := parseJobListFilter(r)
+	if err != nil {
+		if valErr, ok := err.(model.ErrValidation); ok {
+			response.ValidationFailed(w, valErr.Field, valErr.Reason)
+			return
 		}
+		response.BadRequest(w, err.Error())
+		return
 	}
-	if o := r.URL.Query().Get("offset"); o != "" {
-		if val, err := strconv.Atoi(o); err == nil && val >= 0 {
-			offset = val
-		}
+
+	page := pageParams(r, 20, 100)
+	jobs, nextCursor, err := h.jobRepo.ListByUser(r.Context(), user.ID, filter, page)
+	if err != nil {
+		writeListError(w, err)
+		return
 	}
 
-	jobs, err := h.jobRepo.ListByUser(r.Context(), user.ID, limit, offset)
+	counts, err := h.jobRepo.CountByStatus(r.Context(), user.ID, filter)
 	if err != nil {
 		response.InternalError(w)
 		return
 	}
 
-	if jobs == nil {
-		jobs = []*model.VideoJob{}
+	resp := model.JobListResponse{
+		Items:        h.jobResponses(r.Context(), jobs),
+		NextCursor:   nextCursor,
+		StatusCounts: counts,
+	}
+	for status, count := range counts {
+		if len(filter.Statuses) == 0 || containsJobStatus(filter.Statuses, status) {
+			resp.Total += count
+		}
 	}
 
-	// Convert to response
-	resp := make([]model.JobResponse, 0)
+	response.OK(w, resp)
+}
+
+// jobResponses converts listed jobs to responses with queue info and, for
+// completed jobs, their recipe
+func (h *UnifiedExtractionHandler) jobResponses(ctx context.Context, jobs []*model.VideoJob) []model.JobResponse {
+	resp := make([]model.JobResponse, 0, len(jobs))
 	for _, job := range jobs {
 		jobResp := job.ToResponse("")
+		h.addQueueInfo(&jobResp, job)
 
 		// Populate basic recipe info if completed
 		if job.Status == model.JobStatusCompleted && job.ResultRecipeID != nil {
-			if recipe, err := h.recipeRepo.GetByID(r.Context(), *job.ResultRecipeID); err == nil {
+			if recipe, err := h.recipeRepo.GetByID(ctx, *job.ResultRecipeID); err == nil {
 				jobResp.Recipe = recipe
 			}
 		}
 
 		resp = append(resp, jobResp)
 	}
+	return resp
+}
 
-	response.OK(w, resp)
+// parseJobListFilter reads ListJobs query parameters
+func parseJobListFilter(r *http.Request) (model.JobListFilter, error) {
+	q := r.URL.Query()
+	filter := model.JobListFilter{Query: strings.TrimSpace(q.Get("q"))}
+
+	for _, v := range splitCSV(q.Get("status")) {
+		status := model.JobStatus(v)
+		switch status {
+		case model.JobStatusPending, model.JobStatusDownloading, model.JobStatusProcessing,
+			model.JobStatusExtracting, model.JobStatusCompleted, model.JobStatusFailed, model.JobStatusCancelled:
+			filter.Statuses = append(filter.Statuses, status)
+		default:
+			return filter, model.ErrValidation{Field: "status", Reason: "unknown status " + v}
+		}
+	}
+
+	for _, v := range splitCSV(q.Get("type")) {
+		jobType := model.JobType(v)
+		switch jobType {
+		case model.JobTypeURL, model.JobTypeImage, model.JobTypeVideo, model.JobTypeImport:
+			filter.JobTypes = append(filter.JobTypes, jobType)
+		default:
+			return filter, model.ErrValidation{Field: "type", Reason: "must be url, image, video, or import"}
+		}
+	}
+
+	if v := q.Get("from"); v != "" {
+		from, _, err := parseDateParam(v)
+		if err != nil {
+			return filter, model.ErrValidation{Field: "from", Reason: "must be RFC3339 or YYYY-MM-DD"}
+		}
+		filter.From = &from
+	}
+	if v := q.Get("to"); v != "" {
+		to, dateOnly, err := parseDateParam(v)
+		if err != nil {
+			return filter, model.ErrValidation{Field: "to", Reason: "must be RFC3339 or YYYY-MM-DD"}
+		}
+		if dateOnly {
+			// A bare date includes the whole day
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+
+	if v := q.Get("domain"); v != "" {
+		domain, ok := normalizeDomain(v)
+		if !ok {
+			return filter, model.ErrValidation{Field: "domain", Reason: "must be a host name"}
+		}
+		filter.Domain = domain
+	}
+
+	return filter, nil
+}
+
+// parseDateParam accepts RFC3339 timestamps or YYYY-MM-DD dates (UTC)
+func parseDateParam(v string) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, false, nil
+	}
+	t, err = time.Parse("2006-01-02", v)
+	return t, true, err
+}
+
+// normalizeDomain reduces "https://www.Example.com/path" to "example.com"
+func normalizeDomain(v string) (string, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if i := strings.Index(v, "://"); i >= 0 {
+		v = v[i+3:]
+	}
+	if i := strings.IndexAny(v, "/:?#"); i >= 0 {
+		v = v[:i]
+	}
+	v = strings.TrimPrefix(v, "www.")
+	if v == "" {
+		return "", false
+	}
+	for _, c := range v {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '.' || c == '-') {
+			return "", false
+		}
+	}
+	return v, true
+}
+
+func splitCSV(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func containsJobStatus(statuses []model.JobStatus, status model.JobStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
 }
 
 // GetJob returns a single job
@@ -1632,9 +1830,27 @@ func (h *UnifiedExtractionHandler) GetJob(w http.ResponseWriter, r *http.Request
 
 	resp := job.ToResponse("")
 	resp.Recipe = resultRecipe
+	h.addQueueInfo(&resp, job)
 	response.OK(w, resp)
 }
 
+// addQueueInfo reports where a pending job is in the scheduler queue. Queue
+// state lives on the instance that accepted the job, so other instances leave
+// these fields empty.
+func (h *UnifiedExtractionHandler) addQueueInfo(resp *model.JobResponse, job *model.ExtractionJob) {
+	if h.scheduler == nil || job.Status != model.JobStatusPending {
+		return
+	}
+	info, ok := h.scheduler.Position(job.ID)
+	if !ok {
+		return
+	}
+	waitSeconds := int(info.EstimatedWait.Round(time.Second) / time.Second)
+	resp.QueuePosition = info.Position
+	resp.EstimatedWaitSeconds = waitSeconds
+	resp.EstimatedSeconds += waitSeconds
+}
+
 // DeleteJob deletes a single job
 func (h *UnifiedExtractionHandler) DeleteJob(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetUserFromContext(r.Context())
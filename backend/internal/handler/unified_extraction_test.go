@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/cassette"
+	"github.com/dishflow/backend/internal/pkg/pagination"
+	"github.com/dishflow/backend/internal/service/ai"
+	"github.com/dishflow/backend/internal/service/scheduler"
+)
+
+// TestUnifiedExtractionHandler_Extract_URLReplay runs a URL extraction from
+// request to saved recipe against a recorded webpage and Gemini session.
+// Record with CASSETTE_MODE=record and GEMINI_API_KEY set.
+func TestUnifiedExtractionHandler_Extract_URLReplay(t *testing.T) {
+	mode := cassette.ModeFromEnv()
+	apiKey := "replay"
+	if mode == cassette.ModeRecord {
+		apiKey = os.Getenv("GEMINI_API_KEY")
+		if apiKey == "" {
+			t.Skip("GEMINI_API_KEY required to record cassettes")
+		}
+	}
+
+	rt, err := cassette.New("testdata/cassettes/extract_url.json", mode, nil)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	defer func() {
+		if err := rt.Save(); err != nil {
+			t.Errorf("Failed to save cassette: %v", err)
+		}
+	}()
+
+	client, err := ai.NewGeminiClientWithTransport(context.Background(), apiKey, rt)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var mu sync.Mutex
+	var saved *model.Recipe
+	var partials []*model.PartialRecipe
+	done := make(chan uuid.UUID, 1)
+	failed := make(chan string, 1)
+	jobs := &mockJobRepository{
+		UpdatePartialRecipeFunc: func(ctx context.Context, id uuid.UUID, partial *model.PartialRecipe) error {
+			mu.Lock()
+			defer mu.Unlock()
+			partials = append(partials, partial)
+			return nil
+		},
+		MarkCompletedFunc: func(ctx context.Context, id uuid.UUID, recipeID uuid.UUID) error {
+			done <- recipeID
+			return nil
+		},
+		MarkFailedFunc: func(ctx context.Context, id uuid.UUID, code, message string) error {
+			failed <- code + ": " + message
+			return nil
+		},
+	}
+	recipes := &mockRecipeRepository{
+		CreateFunc: func(ctx context.Context, recipe *model.Recipe) error {
+			mu.Lock()
+			defer mu.Unlock()
+			saved = recipe
+			return nil
+		},
+	}
+
+	h := &UnifiedExtractionHandler{
+		jobRepo:    jobs,
+		recipeRepo: recipes,
+		extractor:  client,
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		scheduler: scheduler.New(scheduler.Config{
+			Lanes: []scheduler.LaneConfig{{Name: scheduler.LaneLight, Slots: 1}},
+		}),
+	}
+
+	body := `{"type": "url", "url": "https://example.com/recipes/lemon-garlic-pasta"}`
+	req := httptest.NewRequest("POST", "/recipes/extract", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	h.Extract(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var recipeID uuid.UUID
+	select {
+	case recipeID = <-done:
+	case msg := <-failed:
+		t.Fatalf("extraction failed: %s", msg)
+	case <-time.After(10 * time.Second):
+		t.Fatal("extraction did not complete")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if saved == nil || saved.ID != recipeID {
+		t.Fatalf("expected the completed job to point at the saved recipe, got %v", recipeID)
+	}
+	if saved.Title != "Lemon Garlic Pasta" || saved.SourceType != "webpage" {
+		t.Errorf("unexpected recipe %q from %s", saved.Title, saved.SourceType)
+	}
+	if len(saved.Ingredients) != 5 || len(saved.Steps) != 3 {
+		t.Errorf("expected 5 ingredients and 3 steps, got %d and %d", len(saved.Ingredients), len(saved.Steps))
+	}
+	if saved.ThumbnailURL == nil || *saved.ThumbnailURL != "https://example.com/img/lemon-pasta.jpg" {
+		t.Errorf("unexpected thumbnail %v", saved.ThumbnailURL)
+	}
+	if len(partials) == 0 || partials[0].Stage != model.PartialStageExtracting {
+		t.Errorf("expected streamed partial recipes while extracting, got %d", len(partials))
+	}
+}
+
+func TestUnifiedExtractionHandler_ListJobs(t *testing.T) {
+	mockJobs := &mockJobRepository{}
+	handler := &UnifiedExtractionHandler{jobRepo: mockJobs, recipeRepo: &mockRecipeRepository{}}
+	userID := uuid.New()
+
+	withUser := func(req *http.Request) *http.Request {
+		ctx := context.WithValue(req.Context(), middleware.UserContextKey, &model.User{ID: userID})
+		return req.WithContext(ctx)
+	}
+
+	t.Run("auth required", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ListJobs(rr, httptest.NewRequest("GET", "/jobs", nil))
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", rr.Code)
+		}
+	})
+
+	t.Run("filters and counts", func(t *testing.T) {
+		var got model.JobListFilter
+		var gotPage pagination.Page
+		mockJobs.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, filter model.JobListFilter, page pagination.Page) ([]*model.VideoJob, string, error) {
+			got, gotPage = filter, page
+			return []*model.VideoJob{{ID: uuid.New(), UserID: userID, Status: model.JobStatusFailed}}, "next-page", nil
+		}
+		mockJobs.CountByStatusFunc = func(ctx context.Context, uid uuid.UUID, filter model.JobListFilter) (map[model.JobStatus]int, error) {
+			return map[model.JobStatus]int{
+				model.JobStatusFailed:    3,
+				model.JobStatusCancelled: 1,
+				model.JobStatusCompleted: 40,
+			}, nil
+		}
+
+		req := httptest.NewRequest("GET", "/jobs?status=failed,cancelled&type=url&domain=https://www.AllRecipes.com/recipe/1&to=2024-02-01&q=soup&limit=10", nil)
+		rr := httptest.NewRecorder()
+		handler.ListJobs(rr, withUser(req))
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if len(got.Statuses) != 2 || got.JobTypes[0] != model.JobTypeURL || got.Domain != "allrecipes.com" ||
+			got.Query != "soup" || gotPage.Limit != 10 {
+			t.Errorf("unexpected filter: %+v, page %+v", got, gotPage)
+		}
+		if got.To == nil || !got.To.Equal(time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected to-date to include the whole day, got %v", got.To)
+		}
+
+		var resp model.JobListResponse
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Items) != 1 || resp.NextCursor != "next-page" {
+			t.Errorf("unexpected page: %+v", resp)
+		}
+		if resp.Total != 4 || resp.StatusCounts[model.JobStatusCompleted] != 40 {
+			t.Errorf("total = %d, counts = %v; want total 4 and all status counts", resp.Total, resp.StatusCounts)
+		}
+	})
+
+	// Cursor and offset requests get the same envelope
+	for name, target := range map[string]string{
+		"cursor paging": "/jobs?limit=10&cursor=this-page",
+		"offset paging": "/jobs?limit=10&offset=20",
+	} {
+		t.Run(name, func(t *testing.T) {
+			var got pagination.Page
+			mockJobs.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, filter model.JobListFilter, page pagination.Page) ([]*model.VideoJob, string, error) {
+				got = page
+				return []*model.VideoJob{{ID: uuid.New(), UserID: userID, Status: model.JobStatusFailed}}, "next-page", nil
+			}
+			mockJobs.CountByStatusFunc = func(ctx context.Context, uid uuid.UUID, filter model.JobListFilter) (map[model.JobStatus]int, error) {
+				return map[model.JobStatus]int{model.JobStatusFailed: 21}, nil
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ListJobs(rr, withUser(httptest.NewRequest("GET", target, nil)))
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			if got.ByOffset() {
+				if *got.Offset != 20 || got.Limit != 10 {
+					t.Errorf("expected limit 10 offset 20, got %+v", got)
+				}
+			} else if got != (pagination.Page{Limit: 10, Cursor: "this-page"}) {
+				t.Errorf("expected limit 10 and cursor, got %+v", got)
+			}
+
+			var resp model.JobListResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("expected the envelope: %v", err)
+			}
+			if len(resp.Items) != 1 || resp.Total != 21 || resp.NextCursor != "next-page" {
+				t.Errorf("unexpected page: %+v", resp)
+			}
+		})
+	}
+
+	t.Run("invalid status", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ListJobs(rr, withUser(httptest.NewRequest("GET", "/jobs?status=exploded", nil)))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		mockJobs.ListByUserFunc = func(ctx context.Context, uid uuid.UUID, filter model.JobListFilter, page pagination.Page) ([]*model.VideoJob, string, error) {
+			return nil, "", pagination.ErrInvalidCursor
+		}
+		rr := httptest.NewRecorder()
+		handler.ListJobs(rr, withUser(httptest.NewRequest("GET", "/jobs?cursor=garbage", nil)))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
+
+func TestUnifiedExtractionHandler_ApplyNutrition(t *testing.T) {
+	coverage := 0.0
+	handler := &UnifiedExtractionHandler{
+		nutrition: &mockNutritionCalculator{
+			CalculateFunc: func(ctx context.Context, recipe *model.Recipe) (*model.NutritionReport, error) {
+				return &model.NutritionReport{PerServing: model.Nutrients{Calories: 412.6}, Coverage: coverage}, nil
+			},
+		},
+	}
+	servings := 4
+	newRecipe := func() *model.Recipe {
+		return &model.Recipe{
+			Servings:    &servings,
+			Ingredients: []model.RecipeIngredient{{Name: "onion"}},
+			Nutrition:   &model.RecipeNutrition{Calories: 300, Confidence: 0.8},
+		}
+	}
+
+	t.Run("mostly matched replaces the AI estimate", func(t *testing.T) {
+		coverage = 0.75
+		recipe := newRecipe()
+		handler.applyNutrition(context.Background(), recipe)
+		if recipe.Nutrition.Source != model.NutritionSourceUSDA || recipe.Nutrition.Calories != 413 {
+			t.Errorf("unexpected nutrition %+v", recipe.Nutrition)
+		}
+	})
+
+	t.Run("poorly matched keeps the AI estimate", func(t *testing.T) {
+		coverage = 0.3
+		recipe := newRecipe()
+		handler.applyNutrition(context.Background(), recipe)
+		if recipe.Nutrition.Source != "" || recipe.Nutrition.Calories != 300 {
+			t.Errorf("unexpected nutrition %+v", recipe.Nutrition)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/units"
+)
+
+var errInvalidUnitSystem = errors.New("must be metric, imperial or preferred")
+
+// unitSystemParam reads the optional ?units= query parameter: "metric" or
+// "imperial" pick a system, "preferred" uses the user's PreferredUnitSystem.
+// ok is false when the caller didn't ask for a conversion.
+func unitSystemParam(r *http.Request, user *model.User) (system units.System, ok bool, err error) {
+	v := r.URL.Query().Get("units")
+	if v == "" {
+		return "", false, nil
+	}
+	if v == "preferred" {
+		// Accounts created before the preference existed default to metric
+		if system, ok = units.ParseSystem(user.PreferredUnitSystem); !ok {
+			system = units.Metric
+		}
+		return system, true, nil
+	}
+	system, ok = units.ParseSystem(v)
+	if !ok {
+		return "", false, errInvalidUnitSystem
+	}
+	return system, true, nil
+}
+
+// convertQuantity rewrites quantity and unit in place when they convert to system
+func convertQuantity(quantity **float64, unit **string, name string, system units.System) (units.Quantity, bool) {
+	if *quantity == nil || *unit == nil {
+		return units.Quantity{}, false
+	}
+	q, ok := units.ToSystem(**quantity, **unit, name, system)
+	if !ok {
+		return units.Quantity{}, false
+	}
+	*quantity = &q.Value
+	*unit = &q.Unit
+	return q, true
+}
+
+// convertRecipeUnits returns a copy of recipe with ingredient quantities and
+// step temperatures expressed in system. The original is not modified.
+func convertRecipeUnits(recipe *model.Recipe, system units.System) *model.Recipe {
+	converted := *recipe
+
+	converted.Ingredients = make([]model.RecipeIngredient, len(recipe.Ingredients))
+	for i, ing := range recipe.Ingredients {
+		fromUnit := ing.Unit
+		if q, ok := convertQuantity(&ing.Quantity, &ing.Unit, ing.Name, system); ok {
+			ing.DisplayQuantity = q.Display
+			if ing.QuantityMax != nil {
+				max := convertRangeMax(*ing.QuantityMax, *fromUnit, q.Unit, ing.Name)
+				ing.QuantityMax = &max
+				ing.DisplayQuantity += "-" + units.Format(max, lookupUnit(q.Unit))
+			}
+		}
+		converted.Ingredients[i] = ing
+	}
+
+	converted.Steps = make([]model.RecipeStep, len(recipe.Steps))
+	for i, step := range recipe.Steps {
+		if step.Temperature != nil {
+			temp := units.ConvertTemperature(*step.Temperature, system)
+			step.Temperature = &temp
+		}
+		converted.Steps[i] = step
+	}
+
+	return &converted
+}
+
+// convertRangeMax converts the upper end of a range into the unit the lower
+// end was converted to
+func convertRangeMax(max float64, fromUnit, toUnit, name string) float64 {
+	from, to := lookupUnit(fromUnit), lookupUnit(toUnit)
+	converted, err := units.ConvertIngredient(max, from, to, name)
+	if err != nil {
+		return max
+	}
+	return units.Round(converted, to)
+}
+
+// lookupUnit returns the zero Unit for unknown names
+func lookupUnit(name string) units.Unit {
+	u, _ := units.Lookup(name)
+	return u
+}
+
+// convertShoppingItemUnits converts item quantities in place
+func convertShoppingItemUnits(items []model.ShoppingItem, system units.System) {
+	for i := range items {
+		convertQuantity(&items[i].Quantity, &items[i].Unit, items[i].Name, system)
+	}
+}
+
+// convertPantryItemUnits converts a pantry item's quantity in place
+func convertPantryItemUnits(item *model.PantryItem, system units.System) {
+	convertQuantity(&item.Quantity, &item.Unit, item.Name, system)
+}
@@ -4,11 +4,23 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/ingredient"
+	"github.com/dishflow/backend/internal/pkg/pagination"
+	"github.com/dishflow/backend/internal/pkg/response"
+	"github.com/dishflow/backend/internal/repository/postgres"
 )
 
 // detectMimeType detects image mime type from file magic bytes
@@ -50,47 +62,60 @@ func stringPtr(s string) *string {
 	return &s
 }
 
-// parseQuantity converts a string quantity to float64
-// Handles: "1.5", "1/2", "1 1/2" (mixed fractions), "2-3" (takes first value)
-func parseQuantity(s string) *float64 {
-	if s == "" {
-		return nil
+// parseIngredientAmount parses the free-text quantity and unit the AI returns
+// ("1 1/2", "½", "2-3" + "cloves", "a pinch", "1 (14 oz)" + "can"). A unit the
+// parser doesn't recognise is kept as written, and leftover words ("large",
+// "to taste") become notes.
+func parseIngredientAmount(quantity, unit string) ingredient.Line {
+	unit = strings.TrimSpace(unit)
+	line := ingredient.Parse(strings.TrimSpace(quantity + " " + unit))
+	if line.Unit == "" {
+		line.Unit = unit
+		line.Name = strings.TrimSpace(strings.TrimSuffix(line.Name, unit))
 	}
+	if line.Name != "" {
+		line.Notes = joinNotes(line.Name, line.Notes)
+	}
+	line.Name = ""
+	return line
+}
 
-	s = strings.TrimSpace(s)
-
-	// Try to parse as simple float first
-	var f float64
-	if _, err := fmt.Sscanf(s, "%f", &f); err == nil {
-		// Check if there's a fraction part after (e.g., "1 1/2")
-		parts := strings.Fields(s)
-		if len(parts) == 2 {
-			var num, denom float64
-			if _, err := fmt.Sscanf(parts[1], "%f/%f", &num, &denom); err == nil && denom != 0 {
-				f += num / denom
-			}
+// joinNotes joins non-empty notes with ", "
+func joinNotes(notes ...string) string {
+	var parts []string
+	for _, n := range notes {
+		if n = strings.TrimSpace(n); n != "" {
+			parts = append(parts, n)
 		}
-		return &f
 	}
+	return strings.Join(parts, ", ")
+}
 
-	// Try handling simple fractions like "1/2"
-	var num, denom float64
-	if _, err := fmt.Sscanf(s, "%f/%f", &num, &denom); err == nil && denom != 0 {
-		f = num / denom
-		return &f
+// applyIngredientText fills an ingredient from its raw Text line, so clients
+// can send "2 cloves garlic, minced" instead of structured fields. Fields the
+// client set explicitly win over parsed ones.
+func applyIngredientText(ing *model.RecipeIngredient) {
+	if strings.TrimSpace(ing.Text) == "" {
+		return
 	}
-
-	// Try handling ranges like "2-3" (take the first value)
-	if strings.Contains(s, "-") {
-		parts := strings.Split(s, "-")
-		if len(parts) >= 1 {
-			if _, err := fmt.Sscanf(strings.TrimSpace(parts[0]), "%f", &f); err == nil {
-				return &f
-			}
-		}
+	line := ingredient.Parse(ing.Text)
+	ing.Text = ""
+	if strings.TrimSpace(ing.Name) == "" {
+		ing.Name = line.Name
+	}
+	if ing.Quantity == nil {
+		ing.Quantity = line.Quantity
+		ing.QuantityMax = line.QuantityMax
+	}
+	if ing.Unit == nil {
+		ing.Unit = stringPtr(line.Unit)
+	}
+	if ing.Notes == nil {
+		ing.Notes = stringPtr(line.AllNotes())
+	}
+	if line.IsOptional {
+		ing.IsOptional = true
 	}
-
-	return nil
 }
 
 // decodeBase64Flexible decodes base64 data, trying standard, URL-safe, and raw variants.
@@ -177,3 +202,91 @@ func fetchYouTubeOEmbed(ctx context.Context, videoURL string) youtubeOEmbedResul
 	}
 	return result
 }
+
+// queryList splits comma-separated or repeated query values into
+// distinct, trimmed values
+func queryList(values []string) []string {
+	var list []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			key := strings.ToLower(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+// pageParams reads the limit and cursor query parameters of a list. limit
+// falls back to def when missing or outside 1..max. Without a cursor, an
+// offset parameter is honored for clients that still page by offset.
+func pageParams(r *http.Request, def, max int) pagination.Page {
+	page := pagination.Page{Limit: def, Cursor: r.URL.Query().Get("cursor")}
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= max {
+			page.Limit = val
+		}
+	}
+	if o := r.URL.Query().Get("offset"); o != "" && page.Cursor == "" {
+		if val, err := strconv.Atoi(o); err == nil && val >= 0 {
+			page.Offset = &val
+		}
+	}
+	return page
+}
+
+// writeListError writes the response for a failed list query: a bad request
+// for a cursor that can't be used, an internal error otherwise
+func writeListError(w http.ResponseWriter, err error) {
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		response.BadRequest(w, "Invalid cursor")
+		return
+	}
+	response.InternalError(w)
+}
+
+// withNextCursor adds the cursor for the next page to a list response when
+// there is one
+func withNextCursor(resp map[string]interface{}, next string) map[string]interface{} {
+	if next != "" {
+		resp["nextCursor"] = next
+	}
+	return resp
+}
+
+// ownedRecipe loads the recipe named in the URL and checks the caller owns it.
+// It writes the error response and returns false when it doesn't.
+func ownedRecipe(w http.ResponseWriter, r *http.Request, repo RecipeRepository) (*model.Recipe, bool) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return nil, false
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "recipeID"))
+	if err != nil {
+		response.BadRequest(w, "Invalid recipe ID")
+		return nil, false
+	}
+
+	recipe, err := repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrRecipeNotFound) {
+			response.NotFound(w, "Recipe")
+			return nil, false
+		}
+		response.InternalError(w)
+		return nil, false
+	}
+
+	if recipe.UserID != user.ID {
+		response.Forbidden(w, "Access denied")
+		return nil, false
+	}
+	return recipe, true
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Annotation targets
+const (
+	AnnotationTargetIngredient = "ingredient"
+	AnnotationTargetStep       = "step"
+)
+
+// RecipeAnnotation is a user's private note on one ingredient or step of a
+// recipe, such as "use less salt". It is kept apart from the recipe so it
+// works on recipes the user doesn't own and outlives edits to the recipe.
+type RecipeAnnotation struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   uuid.UUID `json:"userId"`
+	RecipeID uuid.UUID `json:"recipeId"`
+	Target   string    `json:"target"`   // ingredient or step
+	TargetID uuid.UUID `json:"targetId"` // RecipeIngredient or RecipeStep ID
+	// Anchor is the ingredient name or step instruction the note was written
+	// against, and Position its sort order or step number. They re-attach
+	// the note when an update replaces the ingredient or step with a new ID.
+	Anchor      string     `json:"anchor"`
+	Position    int        `json:"position"`
+	Note        string     `json:"note"`
+	SyncVersion int        `json:"syncVersion"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+}
+
+// RecipeAnnotationInput sets the note on an ingredient or step
+type RecipeAnnotationInput struct {
+	Target   string    `json:"target"`
+	TargetID uuid.UUID `json:"targetId"`
+	Note     string    `json:"note"`
+}
+
+// Validate validates the annotation input and trims the note
+func (a *RecipeAnnotationInput) Validate() error {
+	if a.Target != AnnotationTargetIngredient && a.Target != AnnotationTargetStep {
+		return ErrValidation{Field: "target", Reason: "must be ingredient or step"}
+	}
+	if a.TargetID == uuid.Nil {
+		return ErrValidation{Field: "targetId", Reason: "required"}
+	}
+	a.Note = strings.TrimSpace(a.Note)
+	if a.Note == "" {
+		return ErrValidation{Field: "note", Reason: "required"}
+	}
+	if len(a.Note) > 2000 {
+		return ErrValidation{Field: "note", Reason: "max length 2000 characters"}
+	}
+	return nil
+}
+
+// AnnotationAnchor normalizes an ingredient name or step instruction for
+// matching a note to it
+func AnnotationAnchor(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// AnnotationTarget finds the ingredient or step a note is for and returns
+// the anchor and position to store with the note
+func (r *Recipe) AnnotationTarget(target string, id uuid.UUID) (anchor string, position int, ok bool) {
+	switch target {
+	case AnnotationTargetIngredient:
+		for _, ing := range r.Ingredients {
+			if ing.ID == id {
+				return AnnotationAnchor(ing.Name), ing.SortOrder, true
+			}
+		}
+	case AnnotationTargetStep:
+		for _, step := range r.Steps {
+			if step.ID == id {
+				return AnnotationAnchor(step.Instruction), step.StepNumber, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// AttachAnnotations shows the user's notes inline on the recipe's
+// ingredients and steps. A note goes on the ingredient or step with its
+// target ID or, when that is gone, on one with the same anchor, nearest its
+// old position. Notes that match nothing are kept in DetachedAnnotations so
+// they aren't lost.
+func (r *Recipe) AttachAnnotations(notes []RecipeAnnotation) {
+	var ingredientNotes, stepNotes []RecipeAnnotation
+	for _, n := range notes {
+		switch n.Target {
+		case AnnotationTargetIngredient:
+			ingredientNotes = append(ingredientNotes, n)
+		case AnnotationTargetStep:
+			stepNotes = append(stepNotes, n)
+		}
+	}
+
+	ingredients := make([]annotatable, len(r.Ingredients))
+	for i := range r.Ingredients {
+		ing := &r.Ingredients[i]
+		ingredients[i] = annotatable{id: ing.ID, anchor: AnnotationAnchor(ing.Name), position: ing.SortOrder, note: &ing.Annotation}
+	}
+	steps := make([]annotatable, len(r.Steps))
+	for i := range r.Steps {
+		step := &r.Steps[i]
+		steps[i] = annotatable{id: step.ID, anchor: AnnotationAnchor(step.Instruction), position: step.StepNumber, note: &step.Annotation}
+	}
+
+	r.DetachedAnnotations = append(attach(ingredients, ingredientNotes), attach(steps, stepNotes)...)
+}
+
+// annotatable is an ingredient or step a note can be attached to
+type annotatable struct {
+	id       uuid.UUID
+	anchor   string
+	position int
+	note     **RecipeAnnotation
+}
+
+// attach places notes on targets, by ID first so a note whose target still
+// exists is never displaced by one matched on its anchor, and returns the
+// notes that matched nothing
+func attach(targets []annotatable, notes []RecipeAnnotation) []RecipeAnnotation {
+	placed := make([]bool, len(notes))
+	for i := range notes {
+		for _, t := range targets {
+			if t.id == notes[i].TargetID && *t.note == nil {
+				*t.note = &notes[i]
+				placed[i] = true
+				break
+			}
+		}
+	}
+
+	var detached []RecipeAnnotation
+	for i := range notes {
+		if placed[i] {
+			continue
+		}
+		best := -1
+		for j, t := range targets {
+			if *t.note != nil || t.anchor != notes[i].Anchor {
+				continue
+			}
+			if best < 0 || distance(t.position, notes[i].Position) < distance(targets[best].position, notes[i].Position) {
+				best = j
+			}
+		}
+		if best < 0 {
+			detached = append(detached, notes[i])
+			continue
+		}
+		*targets[best].note = &notes[i]
+	}
+	return detached
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxCollectionRecipes caps how many recipes can be added in one request
+const MaxCollectionRecipes = 200
+
+// Collection is a user-defined, ordered group of recipes (a cookbook)
+type Collection struct {
+	ID            uuid.UUID  `json:"id"`
+	UserID        uuid.UUID  `json:"userId"`
+	Name          string     `json:"name"`
+	Description   *string    `json:"description,omitempty"`
+	CoverImageURL *string    `json:"coverImageUrl,omitempty"` // explicitly chosen image
+	CoverRecipeID *uuid.UUID `json:"coverRecipeId,omitempty"` // use this recipe's thumbnail as cover
+	SortOrder     int        `json:"sortOrder"`
+	SyncVersion   int        `json:"syncVersion"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
+
+	// CoverURL is the image to show: the explicit cover, else the cover
+	// recipe's thumbnail, else the first recipe's thumbnail
+	CoverURL    *string `json:"coverUrl,omitempty"`
+	RecipeCount int     `json:"recipeCount"`
+	// RecipeIDs lists member recipes in collection order
+	RecipeIDs []uuid.UUID `json:"recipeIds"`
+}
+
+// CollectionInput represents input for creating/updating collections
+type CollectionInput struct {
+	Name          string     `json:"name"`
+	Description   *string    `json:"description,omitempty"`
+	CoverImageURL *string    `json:"coverImageUrl,omitempty"`
+	CoverRecipeID *uuid.UUID `json:"coverRecipeId,omitempty"`
+}
+
+// CollectionOrderInput is a full ordering of collections or of the recipes in one
+type CollectionOrderInput struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// CollectionRecipesInput adds recipes to a collection
+type CollectionRecipesInput struct {
+	RecipeIDs []uuid.UUID `json:"recipeIds"`
+}
+
+// Validate validates the collection input
+func (c *CollectionInput) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return ErrValidation{Field: "name", Reason: "required"}
+	}
+	if len(c.Name) > 255 {
+		return ErrValidation{Field: "name", Reason: "max length 255 characters"}
+	}
+	if c.Description != nil && len(*c.Description) > 5000 {
+		return ErrValidation{Field: "description", Reason: "max length 5000 characters"}
+	}
+	if c.CoverImageURL != nil && len(*c.CoverImageURL) > 2048 {
+		return ErrValidation{Field: "coverImageUrl", Reason: "max length 2048 characters"}
+	}
+	return nil
+}
+
+// Validate validates an ordering
+func (o *CollectionOrderInput) Validate() error {
+	if len(o.IDs) == 0 {
+		return ErrValidation{Field: "ids", Reason: "required"}
+	}
+	seen := make(map[uuid.UUID]bool, len(o.IDs))
+	for _, id := range o.IDs {
+		if seen[id] {
+			return ErrValidation{Field: "ids", Reason: "must not contain duplicates"}
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// Validate validates the recipes to add
+func (c *CollectionRecipesInput) Validate() error {
+	if len(c.RecipeIDs) == 0 {
+		return ErrValidation{Field: "recipeIds", Reason: "required"}
+	}
+	if len(c.RecipeIDs) > MaxCollectionRecipes {
+		return ErrValidation{Field: "recipeIds", Reason: "max 200 recipes per request"}
+	}
+	return nil
+}
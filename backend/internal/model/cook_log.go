@@ -0,0 +1,104 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cook log limits
+const (
+	MaxCookLogPhotos        = 6
+	MaxCookLogModifications = 20
+)
+
+// CookLog records one time a user cooked a recipe
+type CookLog struct {
+	ID            uuid.UUID `json:"id"`
+	RecipeID      uuid.UUID `json:"recipeId"`
+	UserID        uuid.UUID `json:"userId"`
+	CookedOn      time.Time `json:"cookedOn"`
+	Servings      *int      `json:"servings,omitempty"` // servings made
+	Rating        *int      `json:"rating,omitempty"`   // 1-5
+	Notes         *string   `json:"notes,omitempty"`    // private to the user
+	Photos        []string  `json:"photos"`
+	Modifications []string  `json:"modifications"` // e.g. "used half the sugar"
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+
+	// Joined for the user's cook history
+	RecipeTitle        string  `json:"recipeTitle,omitempty"`
+	RecipeThumbnailURL *string `json:"recipeThumbnailUrl,omitempty"`
+}
+
+// CookLogInput records or edits a cook. Photos are uploaded separately; on
+// update, Photos (when set) is the photos to keep, in order.
+type CookLogInput struct {
+	CookedOn      string    `json:"cookedOn,omitempty"` // YYYY-MM-DD, defaults to today
+	Servings      *int      `json:"servings,omitempty"`
+	Rating        *int      `json:"rating,omitempty"`
+	Notes         *string   `json:"notes,omitempty"`
+	Modifications []string  `json:"modifications,omitempty"`
+	Photos        *[]string `json:"photos,omitempty"`
+
+	// Date is CookedOn parsed by Validate
+	Date time.Time `json:"-"`
+}
+
+// Validate validates the cook log input, parsing CookedOn and trimming text
+func (c *CookLogInput) Validate() error {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if c.CookedOn == "" {
+		c.Date = today
+	} else {
+		date, err := time.Parse("2006-01-02", c.CookedOn)
+		if err != nil {
+			return ErrValidation{Field: "cookedOn", Reason: "must be a date in YYYY-MM-DD format"}
+		}
+		// A day's grace for users ahead of UTC
+		if date.After(today.AddDate(0, 0, 1)) {
+			return ErrValidation{Field: "cookedOn", Reason: "must not be in the future"}
+		}
+		c.Date = date
+	}
+
+	if c.Servings != nil && (*c.Servings < 1 || *c.Servings > 100) {
+		return ErrValidation{Field: "servings", Reason: "must be between 1 and 100"}
+	}
+	if c.Rating != nil && (*c.Rating < 1 || *c.Rating > 5) {
+		return ErrValidation{Field: "rating", Reason: "must be between 1 and 5"}
+	}
+	if c.Notes != nil {
+		notes := strings.TrimSpace(*c.Notes)
+		if len(notes) > 5000 {
+			return ErrValidation{Field: "notes", Reason: "max length 5000 characters"}
+		}
+		if notes == "" {
+			c.Notes = nil
+		} else {
+			c.Notes = &notes
+		}
+	}
+
+	modifications := make([]string, 0, len(c.Modifications))
+	for _, m := range c.Modifications {
+		m = strings.TrimSpace(m)
+		if m == "" {
+			continue
+		}
+		if len(m) > 500 {
+			return ErrValidation{Field: "modifications", Reason: "max length 500 characters each"}
+		}
+		modifications = append(modifications, m)
+	}
+	if len(modifications) > MaxCookLogModifications {
+		return ErrValidation{Field: "modifications", Reason: "max 20 modifications"}
+	}
+	c.Modifications = modifications
+
+	if c.Photos != nil && len(*c.Photos) > MaxCookLogPhotos {
+		return ErrValidation{Field: "photos", Reason: "max 6 photos"}
+	}
+	return nil
+}
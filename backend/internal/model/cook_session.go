@@ -0,0 +1,179 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cook session limits
+const (
+	MaxCookTimers        = 20
+	MaxCookTimerSeconds  = 24 * 60 * 60
+	MaxCookTimerNameSize = 100
+)
+
+// CookSession is a recipe being cooked: the scale it's cooked at, the step
+// the cook is on and their running timers. It is kept on the server so
+// cooking can continue on another device.
+type CookSession struct {
+	ID          uuid.UUID   `json:"id"`
+	RecipeID    uuid.UUID   `json:"recipeId"`
+	UserID      uuid.UUID   `json:"userId"`
+	Servings    *int        `json:"servings,omitempty"` // servings being made; nil cooks the recipe as written
+	CurrentStep int         `json:"currentStep"`        // step number the cook is on
+	Timers      []CookTimer `json:"timers"`
+	CookLogID   *uuid.UUID  `json:"cookLogId,omitempty"` // the cook logged when the session finished
+	StartedAt   time.Time   `json:"startedAt"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
+	FinishedAt  *time.Time  `json:"finishedAt,omitempty"`
+
+	// Joined for listing sessions in progress
+	RecipeTitle        string  `json:"recipeTitle,omitempty"`
+	RecipeThumbnailURL *string `json:"recipeThumbnailUrl,omitempty"`
+
+	// Recipe is the recipe scaled to Servings, set on responses for a
+	// single session
+	Recipe *Recipe `json:"recipe,omitempty"`
+}
+
+// CookTimer is a countdown in a cook session. EndsAt is absolute so every
+// device shows the same time left.
+type CookTimer struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	StepNumber      *int      `json:"stepNumber,omitempty"` // the step it times, if any
+	DurationSeconds int       `json:"durationSeconds"`
+	StartedAt       time.Time `json:"startedAt"`
+	EndsAt          time.Time `json:"endsAt"`
+
+	// RemainingSeconds is computed when the session is read; 0 once done
+	RemainingSeconds int `json:"remainingSeconds"`
+}
+
+// SetRemaining computes each timer's remaining seconds as of now
+func (s *CookSession) SetRemaining(now time.Time) {
+	for i := range s.Timers {
+		t := &s.Timers[i]
+		t.RemainingSeconds = max(0, int(t.EndsAt.Sub(now).Round(time.Second)/time.Second))
+	}
+}
+
+// CookSessionStartInput starts cooking a recipe
+type CookSessionStartInput struct {
+	Servings *int `json:"servings,omitempty"` // scales the recipe; defaults to its own servings
+}
+
+// CookSessionUpdateInput moves a cook session to another step
+type CookSessionUpdateInput struct {
+	CurrentStep int `json:"currentStep"`
+}
+
+// CookTimerInput starts a timer. A timer for a step is named after it and
+// runs for the step's duration unless Name or DurationSeconds say
+// otherwise; a timer for no step needs both.
+type CookTimerInput struct {
+	StepNumber      *int   `json:"stepNumber,omitempty"`
+	Name            string `json:"name,omitempty"`
+	DurationSeconds *int   `json:"durationSeconds,omitempty"`
+}
+
+// Timer validates the input and builds the timer it starts at now, taking
+// defaults from the recipe's steps
+func (t *CookTimerInput) Timer(steps []RecipeStep, now time.Time) (CookTimer, error) {
+	timer := CookTimer{
+		ID:         uuid.New(),
+		Name:       strings.TrimSpace(t.Name),
+		StepNumber: t.StepNumber,
+		StartedAt:  now,
+	}
+	if t.DurationSeconds != nil {
+		timer.DurationSeconds = *t.DurationSeconds
+	}
+
+	if t.StepNumber != nil {
+		step := findStep(steps, *t.StepNumber)
+		if step == nil {
+			return CookTimer{}, ErrValidation{Field: "stepNumber", Reason: "no such step"}
+		}
+		if timer.Name == "" {
+			timer.Name = stepTimerName(step)
+		}
+		if t.DurationSeconds == nil {
+			if step.DurationSeconds == nil {
+				return CookTimer{}, ErrValidation{Field: "durationSeconds", Reason: "required, the step has no duration"}
+			}
+			timer.DurationSeconds = *step.DurationSeconds
+		}
+	}
+
+	if timer.Name == "" {
+		return CookTimer{}, ErrValidation{Field: "name", Reason: "required without stepNumber"}
+	}
+	if len([]rune(timer.Name)) > MaxCookTimerNameSize {
+		return CookTimer{}, ErrValidation{Field: "name", Reason: "max 100 characters"}
+	}
+	if timer.DurationSeconds < 1 || timer.DurationSeconds > MaxCookTimerSeconds {
+		return CookTimer{}, ErrValidation{Field: "durationSeconds", Reason: "must be between 1 and 86400"}
+	}
+
+	timer.EndsAt = now.Add(time.Duration(timer.DurationSeconds) * time.Second)
+	return timer, nil
+}
+
+// stepTimerName names a step's timer after the step and its technique,
+// like "Step 3: simmer"
+func stepTimerName(step *RecipeStep) string {
+	name := fmt.Sprintf("Step %d", step.StepNumber)
+	if step.Technique != nil && strings.TrimSpace(*step.Technique) != "" {
+		name += ": " + strings.TrimSpace(*step.Technique)
+	}
+	return name
+}
+
+// HasStep reports whether steps has the given step number
+func HasStep(steps []RecipeStep, number int) bool {
+	return findStep(steps, number) != nil
+}
+
+func findStep(steps []RecipeStep, number int) *RecipeStep {
+	for i := range steps {
+		if steps[i].StepNumber == number {
+			return &steps[i]
+		}
+	}
+	return nil
+}
+
+// CookSessionFinishInput finishes a cook session, logging the cook with
+// the session's servings. DeductPantry takes the ingredients used out of
+// the pantry.
+type CookSessionFinishInput struct {
+	Rating        *int     `json:"rating,omitempty"`
+	Notes         *string  `json:"notes,omitempty"`
+	Modifications []string `json:"modifications,omitempty"`
+	DeductPantry  bool     `json:"deductPantry"`
+}
+
+// PantryDeduction is one pantry item drawn down by a finished cook
+type PantryDeduction struct {
+	ItemID    uuid.UUID `json:"itemId"`
+	Name      string    `json:"name"`
+	Used      float64   `json:"used"`           // in the pantry item's unit
+	Unit      *string   `json:"unit,omitempty"` // the pantry item's unit
+	Remaining float64   `json:"remaining"`      // left in the pantry
+	Removed   bool      `json:"removed"`        // used up and removed from the pantry
+}
+
+// CookSessionFinishResult is a finished cook session with the cook it
+// logged and what it took from the pantry. The session is finished even
+// when the pantry couldn't be updated: PantryError says so, and Pantry
+// holds what was taken before it failed.
+type CookSessionFinishResult struct {
+	Session     *CookSession      `json:"session"`
+	CookLog     *CookLog          `json:"cookLog,omitempty"`
+	Pantry      []PantryDeduction `json:"pantry"`
+	PantryError string            `json:"pantryError,omitempty"`
+}
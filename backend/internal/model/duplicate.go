@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxMergeRecipes caps how many recipes one merge folds into the kept one
+const MaxMergeRecipes = 20
+
+// DuplicateCandidate is a recipe as the duplicate analysis compares it
+type DuplicateCandidate struct {
+	RecipeID    uuid.UUID
+	Title       string
+	Ingredients []string // ingredient match keys
+}
+
+// DuplicateGroup is a set of a user's recipes that look like the same dish,
+// suggested by the background duplicate analysis
+type DuplicateGroup struct {
+	ID        uuid.UUID   `json:"id"`
+	UserID    uuid.UUID   `json:"userId"`
+	RecipeIDs []uuid.UUID `json:"recipeIds"`
+	Score     float64     `json:"score"` // 0-1, how alike the recipes are
+	CreatedAt time.Time   `json:"createdAt"`
+
+	// Recipes are the group's recipes that still exist, oldest first
+	Recipes []*Recipe `json:"recipes,omitempty"`
+}
+
+// RecipeMergeInput merges duplicate recipes into the one kept
+type RecipeMergeInput struct {
+	KeepID   uuid.UUID   `json:"keepId"`
+	MergeIDs []uuid.UUID `json:"mergeIds"`
+}
+
+// Validate validates the merge input
+func (m *RecipeMergeInput) Validate() error {
+	if m.KeepID == uuid.Nil {
+		return ErrValidation{Field: "keepId", Reason: "required"}
+	}
+	if len(m.MergeIDs) == 0 {
+		return ErrValidation{Field: "mergeIds", Reason: "required"}
+	}
+	if len(m.MergeIDs) > MaxMergeRecipes {
+		return ErrValidation{Field: "mergeIds", Reason: "max 20 recipes"}
+	}
+	seen := make(map[uuid.UUID]bool, len(m.MergeIDs))
+	for _, id := range m.MergeIDs {
+		if id == m.KeepID {
+			return ErrValidation{Field: "mergeIds", Reason: "must not include keepId"}
+		}
+		if seen[id] {
+			return ErrValidation{Field: "mergeIds", Reason: "duplicate recipe ID"}
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// RecipeMergeResult reports what a merge moved to the kept recipe
+type RecipeMergeResult struct {
+	Recipe               *Recipe `json:"recipe"`
+	Merged               int     `json:"merged"` // recipes deleted
+	MovedMealPlanEntries int     `json:"movedMealPlanEntries"`
+	MovedCookLogs        int     `json:"movedCookLogs"`
+	MovedCollections     int     `json:"movedCollections"`
+}
@@ -0,0 +1,45 @@
+package model
+
+// Ingredient search scopes
+const (
+	IngredientSearchAll     = "all"     // the user's recipes and public ones
+	IngredientSearchLibrary = "library" // only the user's recipes
+	IngredientSearchPublic  = "public"  // only public and featured recipes
+)
+
+// Ingredient search limits
+const (
+	MaxIngredientSearchTerms = 10
+	MaxIngredientSearchLimit = 50
+)
+
+// IngredientSearch asks which recipes can be made with some ingredients
+type IngredientSearch struct {
+	Include []string // ingredients the user has; a recipe must use at least one
+	Exclude []string // recipes using any of these are left out
+	Scope   string
+	Limit   int
+}
+
+// IngredientSearchResult is a recipe with how well the searched
+// ingredients cover it
+type IngredientSearchResult struct {
+	// Recipe is a summary without ingredients or steps
+	Recipe *Recipe
+	// Coverage is the percentage of the recipe's required ingredients
+	// (all ingredients when none are required) that were searched for
+	Coverage int
+	// TermsUsed counts the searched ingredients the recipe uses
+	TermsUsed int
+	Matched   []IngredientSearchMatch
+	// Missing lists required ingredients that weren't searched for
+	Missing []string
+}
+
+// IngredientSearchMatch pairs a recipe ingredient with the searched
+// ingredient that covers it
+type IngredientSearchMatch struct {
+	Ingredient   string `json:"ingredient"`
+	Term         string `json:"term"`
+	IsSubstitute bool   `json:"isSubstitute,omitempty"`
+}
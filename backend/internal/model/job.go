@@ -28,6 +28,9 @@ const (
 	JobTypeURL   JobType = "url"
 	JobTypeImage JobType = "image"
 	JobTypeVideo JobType = "video"
+	// JobTypeImport imports a library exported from another recipe manager.
+	// Imports don't count towards the monthly extraction quota.
+	JobTypeImport JobType = "import"
 )
 
 // ExtractionJob represents a recipe extraction job (url, image, or video)
@@ -53,6 +56,91 @@ type ExtractionJob struct {
 	StartedAt      *time.Time `json:"startedAt,omitempty" db:"started_at"`
 	CompletedAt    *time.Time `json:"completedAt,omitempty" db:"completed_at"`
 	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+
+	PartialRecipe *PartialRecipe `json:"-" db:"partial_recipe"` // Preview while the job is running
+	ImportResult  *ImportResult  `json:"-" db:"import_result"`  // Per-recipe outcome of import jobs
+}
+
+// PartialRecipe is a best-effort preview of a recipe while its extraction job is
+// still running. Fields appear as soon as Gemini has produced them; the saved
+// recipe (JobResponse.Recipe) replaces it once the job completes.
+type PartialRecipe struct {
+	Stage        string              `json:"stage"` // "extracting", "refining", "enriching"
+	Title        string              `json:"title,omitempty"`
+	Description  string              `json:"description,omitempty"`
+	ThumbnailURL string              `json:"thumbnailUrl,omitempty"`
+	Servings     int                 `json:"servings,omitempty"`
+	PrepTime     int                 `json:"prepTime,omitempty"`
+	CookTime     int                 `json:"cookTime,omitempty"`
+	Ingredients  []PartialIngredient `json:"ingredients"`
+	Steps        []PartialStep       `json:"steps"`
+	UpdatedAt    time.Time           `json:"updatedAt"`
+}
+
+// PartialIngredient is an ingredient line in a PartialRecipe
+type PartialIngredient struct {
+	Name     string `json:"name"`
+	Quantity string `json:"quantity,omitempty"`
+	Unit     string `json:"unit,omitempty"`
+	Section  string `json:"section,omitempty"`
+}
+
+// PartialStep is an instruction in a PartialRecipe
+type PartialStep struct {
+	StepNumber  int    `json:"stepNumber"`
+	Instruction string `json:"instruction"`
+}
+
+// Partial recipe stages
+const (
+	PartialStageExtracting = "extracting"
+	PartialStageRefining   = "refining"
+	PartialStageEnriching  = "enriching"
+)
+
+// ImportResult reports the outcome of an import job, recipe by recipe
+type ImportResult struct {
+	Format   string             `json:"format"`
+	Total    int                `json:"total"`
+	Imported int                `json:"imported"`
+	Failed   int                `json:"failed"`
+	Skipped  int                `json:"skipped"`
+	Items    []ImportItemResult `json:"items"`
+}
+
+// ImportItemResult is the outcome for one recipe in an import file
+type ImportItemResult struct {
+	Index    int          `json:"index"` // position in the file, from 0
+	Title    string       `json:"title"`
+	Status   ImportStatus `json:"status"`
+	RecipeID *uuid.UUID   `json:"recipeId,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// ImportStatus is the outcome of importing one recipe
+type ImportStatus string
+
+const (
+	ImportStatusPending  ImportStatus = "pending"
+	ImportStatusImported ImportStatus = "imported"
+	ImportStatusFailed   ImportStatus = "failed"
+	ImportStatusSkipped  ImportStatus = "skipped" // duplicate, or over the recipe limit
+)
+
+// Record sets the outcome of item i and updates the totals
+func (r *ImportResult) Record(i int, status ImportStatus, recipeID *uuid.UUID, errMsg string) {
+	item := &r.Items[i]
+	item.Status = status
+	item.RecipeID = recipeID
+	item.Error = errMsg
+	switch status {
+	case ImportStatusImported:
+		r.Imported++
+	case ImportStatusFailed:
+		r.Failed++
+	case ImportStatusSkipped:
+		r.Skipped++
+	}
 }
 
 // VideoJob is an alias for ExtractionJob for backwards compatibility
@@ -60,19 +148,44 @@ type VideoJob = ExtractionJob
 
 // JobResponse is the API response for a job
 type JobResponse struct {
-	JobID            string     `json:"jobId"`
-	JobType          JobType    `json:"jobType"`
-	Status           JobStatus  `json:"status"`
-	Progress         int        `json:"progress"`
-	Message          string     `json:"message,omitempty"`
-	SourceURL        string     `json:"sourceUrl,omitempty"`
-	StatusURL        string     `json:"statusUrl,omitempty"`
-	StreamURL        string     `json:"streamUrl,omitempty"`
-	EstimatedSeconds int        `json:"estimatedSeconds,omitempty"`
-	Recipe           *Recipe    `json:"recipe,omitempty"`
-	Error            *JobError  `json:"error,omitempty"`
-	CreatedAt        time.Time  `json:"createdAt"`
-	CompletedAt      *time.Time `json:"completedAt,omitempty"`
+	JobID            string    `json:"jobId"`
+	JobType          JobType   `json:"jobType"`
+	Status           JobStatus `json:"status"`
+	Progress         int       `json:"progress"`
+	Message          string    `json:"message,omitempty"`
+	SourceURL        string    `json:"sourceUrl,omitempty"`
+	StatusURL        string    `json:"statusUrl,omitempty"`
+	StreamURL        string    `json:"streamUrl,omitempty"`
+	EstimatedSeconds int       `json:"estimatedSeconds,omitempty"`
+	// QueuePosition and EstimatedWaitSeconds are set while a pending job waits
+	// for a processing slot (1 = next to start)
+	QueuePosition        int            `json:"queuePosition,omitempty"`
+	EstimatedWaitSeconds int            `json:"estimatedWaitSeconds,omitempty"`
+	Recipe               *Recipe        `json:"recipe,omitempty"`
+	PartialRecipe        *PartialRecipe `json:"partialRecipe,omitempty"`
+	Import               *ImportResult  `json:"import,omitempty"` // import jobs only
+	Error                *JobError      `json:"error,omitempty"`
+	CreatedAt            time.Time      `json:"createdAt"`
+	CompletedAt          *time.Time     `json:"completedAt,omitempty"`
+}
+
+// JobListFilter narrows a user's job history. Zero values mean "no filter".
+type JobListFilter struct {
+	Statuses []JobStatus
+	JobTypes []JobType
+	From     *time.Time // created at or after
+	To       *time.Time // created before
+	Domain   string     // source URL host, subdomains included ("allrecipes.com")
+	Query    string     // matched against the source URL and result recipe title
+}
+
+// JobListResponse is the API response for a page of job history, whether
+// it was asked for by cursor or by offset
+type JobListResponse struct {
+	Items        []JobResponse     `json:"items"`
+	NextCursor   string            `json:"nextCursor,omitempty"`
+	StatusCounts map[JobStatus]int `json:"statusCounts"`
+	Total        int               `json:"total"`
 }
 
 // JobError represents an error in a job
@@ -145,11 +258,16 @@ func (j *ExtractionJob) ToResponse(baseURL string) JobResponse {
 			resp.EstimatedSeconds = 15
 		case JobTypeVideo:
 			resp.EstimatedSeconds = 45
+		case JobTypeImport:
+			resp.EstimatedSeconds = 60
 		default:
 			resp.EstimatedSeconds = 30
 		}
+		resp.PartialRecipe = j.PartialRecipe
 	}
 
+	resp.Import = j.ImportResult
+
 	if j.CompletedAt != nil {
 		resp.CompletedAt = j.CompletedAt
 	}
@@ -168,10 +286,10 @@ func (j *ExtractionJob) ToResponse(baseURL string) JobResponse {
 // isRetryableError determines if an error code is retryable
 func isRetryableError(code string) bool {
 	retryableCodes := map[string]bool{
-		"DOWNLOAD_FAILED":     true,
-		"GEMINI_UNAVAILABLE":  true,
-		"TIMEOUT":             true,
-		"RATE_LIMITED":        true,
+		"DOWNLOAD_FAILED":    true,
+		"GEMINI_UNAVAILABLE": true,
+		"TIMEOUT":            true,
+		"RATE_LIMITED":       true,
 	}
 	return retryableCodes[code]
 }
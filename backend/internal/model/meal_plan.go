@@ -25,6 +25,7 @@ type MealPlanEntry struct {
 	DayIndex     int       `json:"dayIndex"`
 	MealType     string    `json:"mealType"`
 	SortOrder    int       `json:"sortOrder"`
+	Servings     *int      `json:"servings,omitempty"` // nil = recipe's own servings
 	RecipeTitle  *string   `json:"recipeTitle,omitempty"`
 	ThumbnailURL *string   `json:"thumbnailUrl,omitempty"`
 	PrepTime     *int      `json:"prepTime,omitempty"`
@@ -37,6 +38,16 @@ type MealPlanEntryInput struct {
 	RecipeID uuid.UUID `json:"recipeId"`
 	DayIndex int       `json:"dayIndex"`
 	MealType string    `json:"mealType"`
+	Servings *int      `json:"servings,omitempty"`
+}
+
+// MealPlanIngredient is a recipe ingredient as it appears in a meal plan entry,
+// with the servings needed to scale it
+type MealPlanIngredient struct {
+	RecipeIngredient
+	RecipeTitle    string
+	RecipeServings *int // servings the ingredient quantities are written for
+	EntryServings  *int // servings planned for the entry (nil = recipe default)
 }
 
 // Validate validates the entry input
@@ -52,5 +63,8 @@ func (e *MealPlanEntryInput) Validate() error {
 	default:
 		return ErrValidation{Field: "mealType", Reason: "must be breakfast, lunch, dinner, or snack"}
 	}
+	if e.Servings != nil && (*e.Servings < 1 || *e.Servings > 100) {
+		return ErrValidation{Field: "servings", Reason: "must be between 1 and 100"}
+	}
 	return nil
 }
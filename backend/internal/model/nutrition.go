@@ -0,0 +1,180 @@
+package model
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// Where an ingredient's nutrition came from
+const (
+	NutritionSourceUSDA = "usda" // computed from the food database
+	NutritionSourceAI   = "ai"   // estimated by the AI, no food matched
+	NutritionSourceNone = "none" // left out of the totals
+)
+
+// Nutrients are amounts of the nutrients tracked for recipes, in grams unless noted
+type Nutrients struct {
+	Calories     float64 `json:"calories"` // kcal
+	Protein      float64 `json:"protein"`
+	Carbs        float64 `json:"carbs"`
+	Fat          float64 `json:"fat"`
+	SaturatedFat float64 `json:"saturatedFat"`
+	Fiber        float64 `json:"fiber"`
+	Sugar        float64 `json:"sugar"`
+	Sodium       float64 `json:"sodium"`      // mg
+	Cholesterol  float64 `json:"cholesterol"` // mg
+	Calcium      float64 `json:"calcium"`     // mg
+	Iron         float64 `json:"iron"`        // mg
+	Potassium    float64 `json:"potassium"`   // mg
+	VitaminC     float64 `json:"vitaminC"`    // mg
+}
+
+// Add returns the sum of n and o
+func (n Nutrients) Add(o Nutrients) Nutrients {
+	return Nutrients{
+		Calories:     n.Calories + o.Calories,
+		Protein:      n.Protein + o.Protein,
+		Carbs:        n.Carbs + o.Carbs,
+		Fat:          n.Fat + o.Fat,
+		SaturatedFat: n.SaturatedFat + o.SaturatedFat,
+		Fiber:        n.Fiber + o.Fiber,
+		Sugar:        n.Sugar + o.Sugar,
+		Sodium:       n.Sodium + o.Sodium,
+		Cholesterol:  n.Cholesterol + o.Cholesterol,
+		Calcium:      n.Calcium + o.Calcium,
+		Iron:         n.Iron + o.Iron,
+		Potassium:    n.Potassium + o.Potassium,
+		VitaminC:     n.VitaminC + o.VitaminC,
+	}
+}
+
+// Scale returns n multiplied by factor
+func (n Nutrients) Scale(factor float64) Nutrients {
+	return Nutrients{
+		Calories:     n.Calories * factor,
+		Protein:      n.Protein * factor,
+		Carbs:        n.Carbs * factor,
+		Fat:          n.Fat * factor,
+		SaturatedFat: n.SaturatedFat * factor,
+		Fiber:        n.Fiber * factor,
+		Sugar:        n.Sugar * factor,
+		Sodium:       n.Sodium * factor,
+		Cholesterol:  n.Cholesterol * factor,
+		Calcium:      n.Calcium * factor,
+		Iron:         n.Iron * factor,
+		Potassium:    n.Potassium * factor,
+		VitaminC:     n.VitaminC * factor,
+	}
+}
+
+// Round returns n rounded to one decimal place for display
+func (n Nutrients) Round() Nutrients {
+	r := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return Nutrients{
+		Calories:     math.Round(n.Calories),
+		Protein:      r(n.Protein),
+		Carbs:        r(n.Carbs),
+		Fat:          r(n.Fat),
+		SaturatedFat: r(n.SaturatedFat),
+		Fiber:        r(n.Fiber),
+		Sugar:        r(n.Sugar),
+		Sodium:       math.Round(n.Sodium),
+		Cholesterol:  math.Round(n.Cholesterol),
+		Calcium:      math.Round(n.Calcium),
+		Iron:         r(n.Iron),
+		Potassium:    math.Round(n.Potassium),
+		VitaminC:     r(n.VitaminC),
+	}
+}
+
+// Tags returns the NutritionTags a serving with these nutrients earns,
+// using the same thresholds the AI estimate is asked to follow
+func (n Nutrients) Tags() []string {
+	var tags []string
+	if n.Calories < 300 {
+		tags = append(tags, "low-calorie")
+	}
+	if n.Protein > 25 {
+		tags = append(tags, "high-protein")
+	}
+	if n.Carbs < 20 {
+		tags = append(tags, "low-carb")
+	}
+	if n.Carbs-n.Fiber < 10 {
+		tags = append(tags, "keto-friendly")
+	}
+	if n.Fat < 10 {
+		tags = append(tags, "low-fat")
+	}
+	if n.Fiber > 8 {
+		tags = append(tags, "high-fiber")
+	}
+	if n.Sodium < 400 {
+		tags = append(tags, "low-sodium")
+	}
+	return tags
+}
+
+// Food is a food from the USDA FoodData Central composition data
+type Food struct {
+	FDCID       int           `json:"fdcId"`
+	Description string        `json:"description"`
+	DataType    string        `json:"dataType"`
+	Per100g     Nutrients     `json:"per100g"`
+	Portions    []FoodPortion `json:"portions,omitempty"`
+}
+
+// FoodPortion is a household measure of a food and what it weighs
+type FoodPortion struct {
+	Amount     float64 `json:"amount"`
+	Unit       string  `json:"unit,omitempty"` // canonical unit name ("cup"), empty for counted portions
+	Modifier   string  `json:"modifier,omitempty"`
+	GramWeight float64 `json:"gramWeight"`
+}
+
+// IngredientNutrition is one ingredient's share of a recipe's nutrition
+type IngredientNutrition struct {
+	IngredientID uuid.UUID  `json:"ingredientId"`
+	Name         string     `json:"name"`
+	Grams        *float64   `json:"grams,omitempty"`
+	Source       string     `json:"source"` // usda, ai or none
+	FoodID       *int       `json:"fdcId,omitempty"`
+	Food         string     `json:"food,omitempty"` // matched food's description
+	Nutrients    *Nutrients `json:"nutrients,omitempty"`
+	// Reason explains why an ingredient is left out of the totals
+	Reason string `json:"reason,omitempty"`
+	// FallbackReason explains why an AI estimate stands in for the food
+	// database
+	FallbackReason string `json:"fallbackReason,omitempty"`
+}
+
+// NutritionReport is a recipe's nutrition computed ingredient by ingredient
+type NutritionReport struct {
+	RecipeID    uuid.UUID             `json:"recipeId"`
+	Servings    int                   `json:"servings"`
+	PerServing  Nutrients             `json:"perServing"`
+	Total       Nutrients             `json:"total"`
+	Tags        []string              `json:"tags,omitempty"`
+	Ingredients []IngredientNutrition `json:"ingredients"`
+
+	// Coverage is the share of counted ingredients (0-1) computed from the
+	// food database rather than estimated
+	Coverage float64 `json:"coverage"`
+}
+
+// Summary returns the report as the recipe's stored per-serving nutrition
+func (r *NutritionReport) Summary() *RecipeNutrition {
+	return &RecipeNutrition{
+		Calories:   int(math.Round(r.PerServing.Calories)),
+		Protein:    int(math.Round(r.PerServing.Protein)),
+		Carbs:      int(math.Round(r.PerServing.Carbs)),
+		Fat:        int(math.Round(r.PerServing.Fat)),
+		Fiber:      int(math.Round(r.PerServing.Fiber)),
+		Sugar:      int(math.Round(r.PerServing.Sugar)),
+		Sodium:     int(math.Round(r.PerServing.Sodium)),
+		Tags:       r.Tags,
+		Confidence: math.Round(r.Coverage*100) / 100,
+		Source:     NutritionSourceUSDA,
+	}
+}
@@ -16,7 +16,8 @@ type RecipeNutrition struct {
 	Sugar      int      `json:"sugar,omitempty"`      // grams
 	Sodium     int      `json:"sodium,omitempty"`     // mg
 	Tags       []string `json:"tags,omitempty"`       // e.g., "high-protein", "low-carb", "keto-friendly"
-	Confidence float64  `json:"confidence,omitempty"` // AI confidence score 0-1
+	Confidence float64  `json:"confidence,omitempty"` // AI confidence score 0-1, or food database coverage
+	Source     string   `json:"source,omitempty"`     // "usda" when computed from the food database, else AI
 }
 
 // DietaryInfo contains dietary flags for filtering
@@ -31,8 +32,35 @@ type DietaryInfo struct {
 	IsKosher     bool     `json:"isKosher,omitempty"`
 	Allergens    []string `json:"allergens,omitempty"` // e.g., ["gluten", "dairy", "nuts"]
 	MealTypes    []string `json:"mealTypes,omitempty"` // e.g., ["breakfast", "lunch", "dinner"]
+
+	// Triggers name the ingredient behind each allergen and ruled-out diet
+	Triggers []DietaryTrigger `json:"triggers,omitempty"`
+	// Unverified lists ingredients the rules don't know, so "free" flags
+	// rest on the AI's judgement of them
+	Unverified []string `json:"unverified,omitempty"`
+	// Classified is set once the rule-based check has run
+	Classified bool `json:"classified,omitempty"`
+}
+
+// Dietary trigger sources
+const (
+	DietarySourceRules = "rules"
+	DietarySourceAI    = "ai"
+)
+
+// DietaryTrigger records why a recipe carries an allergen or isn't fit for
+// a diet. Flag is an allergen ("dairy") or a diet it rules out
+// ("vegetarian"); Ingredient is empty for AI allergens, which can't be
+// pinned to one.
+type DietaryTrigger struct {
+	Flag       string `json:"flag"`
+	Ingredient string `json:"ingredient,omitempty"`
+	Source     string `json:"source"`
 }
 
+// SearchLanguages are the languages recipe search can stem for
+var SearchLanguages = []string{"en", "fr", "es"}
+
 // Recipe represents a recipe in DLISHE
 type Recipe struct {
 	ID             uuid.UUID        `json:"id" db:"id"`
@@ -65,9 +93,33 @@ type Recipe struct {
 	IngredientCount int `json:"ingredientCount,omitempty"`
 	StepCount       int `json:"stepCount,omitempty"`
 
+	// Cook log stats, shown to the recipe's owner only
+	TimesCooked   int        `json:"timesCooked,omitempty"`
+	LastCookedOn  *time.Time `json:"lastCookedOn,omitempty"`
+	AverageRating *float64   `json:"averageRating,omitempty"` // of rated cooks
+
+	// Snippet is set by search: matching description or ingredient text,
+	// HTML-escaped, with the matched words wrapped in <mark> tags
+	Snippet string `json:"snippet,omitempty"`
+
 	// Related data (not stored in recipes table, loaded on GetByID)
 	Ingredients []RecipeIngredient `json:"ingredients,omitempty"`
 	Steps       []RecipeStep       `json:"steps,omitempty"`
+
+	// DetachedAnnotations are the user's notes whose ingredient or step no
+	// longer exists, set with the notes shown inline (see AttachAnnotations)
+	DetachedAnnotations []RecipeAnnotation `json:"detachedAnnotations,omitempty"`
+
+	// Scaling is set when the recipe was scaled to a requested serving count
+	Scaling *RecipeScaling `json:"scaling,omitempty"`
+}
+
+// RecipeScaling describes how a recipe response was scaled
+type RecipeScaling struct {
+	OriginalServings int      `json:"originalServings"`
+	Servings         int      `json:"servings"`
+	Factor           float64  `json:"factor"`
+	Warnings         []string `json:"warnings,omitempty"` // e.g. pan size may need adjusting
 }
 
 // RecipeIngredient represents an ingredient in a recipe
@@ -76,6 +128,7 @@ type RecipeIngredient struct {
 	RecipeID       uuid.UUID `json:"recipeId" db:"recipe_id"`
 	Name           string    `json:"name" db:"name"`
 	Quantity       *float64  `json:"quantity,omitempty" db:"quantity"`
+	QuantityMax    *float64  `json:"quantityMax,omitempty" db:"quantity_max"` // upper bound of a range ("2-3")
 	Unit           *string   `json:"unit,omitempty" db:"unit"`
 	Category       string    `json:"category" db:"category"`         // dairy, produce, proteins, etc.
 	Section        string    `json:"section,omitempty" db:"section"` // e.g. "Dough", "Sauce", "Main"
@@ -84,6 +137,17 @@ type RecipeIngredient struct {
 	VideoTimestamp *int      `json:"videoTimestamp,omitempty" db:"video_timestamp"` // seconds
 	SortOrder      int       `json:"sortOrder" db:"sort_order"`
 	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+
+	// Text is a raw ingredient line ("2 cloves garlic, minced") accepted on
+	// create/update; it is parsed into the fields above and never stored
+	Text string `json:"text,omitempty" db:"-"`
+
+	// Set on scaled responses only
+	DisplayQuantity string `json:"displayQuantity,omitempty" db:"-"` // e.g. "1 1/2"
+	ScalingNote     string `json:"scalingNote,omitempty" db:"-"`     // why the amount may need adjusting
+
+	// Annotation is the requesting user's note, set on Get only
+	Annotation *RecipeAnnotation `json:"annotation,omitempty" db:"-"`
 }
 
 // RecipeStep represents a step in a recipe
@@ -98,17 +162,9 @@ type RecipeStep struct {
 	VideoTimestampStart *int      `json:"videoTimestampStart,omitempty" db:"video_timestamp_start"`
 	VideoTimestampEnd   *int      `json:"videoTimestampEnd,omitempty" db:"video_timestamp_end"`
 	CreatedAt           time.Time `json:"createdAt" db:"created_at"`
-}
 
-// RecipeShare represents a shared recipe between users
-type RecipeShare struct {
-	ID             uuid.UUID  `json:"id" db:"id"`
-	RecipeID       uuid.UUID  `json:"recipeId" db:"recipe_id"`
-	OwnerID        uuid.UUID  `json:"ownerId" db:"owner_id"`
-	RecipientID    *uuid.UUID `json:"recipientId,omitempty" db:"recipient_id"`
-	RecipientEmail *string    `json:"recipientEmail,omitempty" db:"recipient_email"`
-	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
-	AcceptedAt     *time.Time `json:"acceptedAt,omitempty" db:"accepted_at"`
+	// Annotation is the requesting user's note, set on Get only
+	Annotation *RecipeAnnotation `json:"annotation,omitempty" db:"-"`
 }
 
 // Ingredient categories (matching DLISHE mobile app)
@@ -195,6 +251,14 @@ func (r *Recipe) TotalTime() int {
 	return total
 }
 
+// HideCookStats clears the owner's cook log stats before the recipe is
+// shown to someone else
+func (r *Recipe) HideCookStats() {
+	r.TimesCooked = 0
+	r.LastCookedOn = nil
+	r.AverageRating = nil
+}
+
 // NewRecipe creates a new recipe with default values
 func NewRecipe(userID uuid.UUID, title string) *Recipe {
 	return &Recipe{
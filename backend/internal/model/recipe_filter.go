@@ -0,0 +1,74 @@
+package model
+
+// Recipe list sort orders
+const (
+	RecipeSortRecent     = "recent"      // newest first
+	RecipeSortTitle      = "title"       // A-Z
+	RecipeSortTime       = "time"        // quickest first (prep + cook)
+	RecipeSortCalories   = "calories"    // lightest first
+	RecipeSortMostCooked = "most-cooked" // cooked most often first
+	RecipeSortLastCooked = "last-cooked" // cooked most recently first
+)
+
+// RecipeSorts lists the accepted sort orders
+var RecipeSorts = []string{RecipeSortRecent, RecipeSortTitle, RecipeSortTime, RecipeSortCalories, RecipeSortMostCooked, RecipeSortLastCooked}
+
+// DietFlags maps diet filter values to the DietaryInfo flag they require
+var DietFlags = map[string]string{
+	"vegetarian":  "isVegetarian",
+	"vegan":       "isVegan",
+	"gluten-free": "isGlutenFree",
+	"dairy-free":  "isDairyFree",
+	"nut-free":    "isNutFree",
+	"keto":        "isKeto",
+	"halal":       "isHalal",
+	"kosher":      "isKosher",
+}
+
+// AllergenFreeFlags maps allergens to the DietaryInfo flag marking a recipe
+// free of them. Excluding one of these requires the flag; any allergen
+// also rules out recipes listing it in DietaryInfo.Allergens.
+var AllergenFreeFlags = map[string]string{
+	"gluten":  "isGlutenFree",
+	"dairy":   "isDairyFree",
+	"lactose": "isDairyFree",
+	"nuts":    "isNutFree",
+}
+
+// TotalTimeBuckets are the "ready in" facet limits, in minutes
+var TotalTimeBuckets = []int{15, 30, 60, 120}
+
+// RecipeFilter narrows and orders a recipe list. Values within Cuisines,
+// Difficulties and SourceTypes are alternatives; Tags and Diets must all
+// hold. Zero values don't filter.
+type RecipeFilter struct {
+	Cuisines         []string
+	Difficulties     []string
+	SourceTypes      []string
+	Tags             []string
+	Diets            []string
+	ExcludeAllergens []string
+	MaxTotalTime     int // minutes of prep + cook
+	MaxCalories      int // per serving
+	FavoritesOnly    bool
+	Sort             string
+}
+
+// FacetCount is how many recipes a filter value would match
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// RecipeFacets holds filter chip counts for a recipe list. Each facet is
+// counted with every other active filter applied, so picking another
+// cuisine shows how many recipes it adds.
+type RecipeFacets struct {
+	Cuisines     []FacetCount `json:"cuisines"`
+	Difficulties []FacetCount `json:"difficulties"`
+	SourceTypes  []FacetCount `json:"sourceTypes"`
+	Tags         []FacetCount `json:"tags"`
+	Diets        []FacetCount `json:"diets"`
+	// TotalTime counts recipes ready within each of TotalTimeBuckets
+	TotalTime []FacetCount `json:"totalTime"`
+}
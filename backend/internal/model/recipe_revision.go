@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevisionSource records what produced a recipe revision
+type RevisionSource string
+
+const (
+	RevisionSourceCreate   RevisionSource = "create"
+	RevisionSourceUpdate   RevisionSource = "update"
+	RevisionSourceSync     RevisionSource = "sync"
+	RevisionSourceConflict RevisionSource = "conflict" // client edit that lost a sync conflict
+	RevisionSourceRestore  RevisionSource = "restore"
+)
+
+// RecipeRevision is an immutable snapshot of a recipe at one sync version
+type RecipeRevision struct {
+	ID           uuid.UUID      `json:"id" db:"id"`
+	RecipeID     uuid.UUID      `json:"recipeId" db:"recipe_id"`
+	SyncVersion  int            `json:"syncVersion" db:"sync_version"`
+	Source       RevisionSource `json:"source" db:"source"`
+	RestoredFrom *uuid.UUID     `json:"restoredFrom,omitempty" db:"restored_from"`
+	CreatedAt    time.Time      `json:"createdAt" db:"created_at"`
+
+	// Summary fields for listings
+	Title           string `json:"title" db:"-"`
+	IngredientCount int    `json:"ingredientCount" db:"-"`
+	StepCount       int    `json:"stepCount" db:"-"`
+
+	// Snapshot is only loaded for a single revision
+	Snapshot *Recipe `json:"snapshot,omitempty" db:"snapshot"`
+}
+
+// DiffChange describes how an ingredient or step differs between two revisions
+type DiffChange string
+
+const (
+	DiffAdded   DiffChange = "added"
+	DiffRemoved DiffChange = "removed"
+	DiffChanged DiffChange = "changed"
+)
+
+// RecipeDiff lists the differences between two versions of a recipe
+type RecipeDiff struct {
+	From uuid.UUID `json:"from"`
+	// To is nil when comparing against the current recipe
+	To          *uuid.UUID         `json:"to,omitempty"`
+	Fields      []FieldChange      `json:"fields"`
+	Ingredients []IngredientChange `json:"ingredients"`
+	Steps       []StepChange       `json:"steps"`
+}
+
+// FieldChange is a changed top-level recipe field
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// IngredientChange is an added, removed or edited ingredient
+type IngredientChange struct {
+	Change DiffChange        `json:"change"`
+	Name   string            `json:"name"`
+	From   *RecipeIngredient `json:"from,omitempty"`
+	To     *RecipeIngredient `json:"to,omitempty"`
+}
+
+// StepChange is an added, removed or edited step, matched by position
+type StepChange struct {
+	Change     DiffChange  `json:"change"`
+	StepNumber int         `json:"stepNumber"`
+	From       *RecipeStep `json:"from,omitempty"`
+	To         *RecipeStep `json:"to,omitempty"`
+}
@@ -157,3 +157,59 @@ func TestNewRecipe(t *testing.T) {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestRecipeAttachAnnotations(t *testing.T) {
+	saltID, pepperID, oldSaltID := uuid.New(), uuid.New(), uuid.New()
+	stepID := uuid.New()
+	recipe := &Recipe{
+		Ingredients: []RecipeIngredient{
+			{ID: saltID, Name: "Salt", SortOrder: 0},
+			{ID: pepperID, Name: "Black  Pepper", SortOrder: 1},
+			{ID: uuid.New(), Name: "salt", SortOrder: 5},
+		},
+		Steps: []RecipeStep{{ID: stepID, StepNumber: 1, Instruction: "Season well"}},
+	}
+
+	recipe.AttachAnnotations([]RecipeAnnotation{
+		// Matched by ID
+		{Target: AnnotationTargetIngredient, TargetID: saltID, Anchor: "salt", Position: 0, Note: "less"},
+		// ID replaced by an update: re-attached to the nearest "salt"
+		{Target: AnnotationTargetIngredient, TargetID: oldSaltID, Anchor: "salt", Position: 4, Note: "flaky"},
+		// Anchor normalized across case and spacing
+		{Target: AnnotationTargetIngredient, TargetID: uuid.New(), Anchor: "black pepper", Position: 1, Note: "fresh"},
+		{Target: AnnotationTargetStep, TargetID: stepID, Note: "taste first"},
+		// Step removed from the recipe
+		{Target: AnnotationTargetStep, TargetID: uuid.New(), Anchor: "rest the dough", Note: "overnight"},
+	})
+
+	want := map[int]string{0: "less", 1: "fresh", 2: "flaky"}
+	for i, ing := range recipe.Ingredients {
+		if ing.Annotation == nil || ing.Annotation.Note != want[i] {
+			t.Errorf("ingredient %d annotation = %+v, want %q", i, ing.Annotation, want[i])
+		}
+	}
+	if recipe.Steps[0].Annotation == nil || recipe.Steps[0].Annotation.Note != "taste first" {
+		t.Errorf("step annotation = %+v", recipe.Steps[0].Annotation)
+	}
+	if len(recipe.DetachedAnnotations) != 1 || recipe.DetachedAnnotations[0].Note != "overnight" {
+		t.Errorf("detached = %+v", recipe.DetachedAnnotations)
+	}
+}
+
+func TestRecipeAnnotationTarget(t *testing.T) {
+	ingID, stepID := uuid.New(), uuid.New()
+	recipe := &Recipe{
+		Ingredients: []RecipeIngredient{{ID: ingID, Name: " Olive  Oil", SortOrder: 3}},
+		Steps:       []RecipeStep{{ID: stepID, StepNumber: 2, Instruction: "Heat the oil"}},
+	}
+
+	if anchor, pos, ok := recipe.AnnotationTarget(AnnotationTargetIngredient, ingID); !ok || anchor != "olive oil" || pos != 3 {
+		t.Errorf("ingredient target = %q, %d, %v", anchor, pos, ok)
+	}
+	if anchor, pos, ok := recipe.AnnotationTarget(AnnotationTargetStep, stepID); !ok || anchor != "heat the oil" || pos != 2 {
+		t.Errorf("step target = %q, %d, %v", anchor, pos, ok)
+	}
+	if _, _, ok := recipe.AnnotationTarget(AnnotationTargetStep, ingID); ok {
+		t.Error("found an ingredient ID as a step")
+	}
+}
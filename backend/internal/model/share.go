@@ -0,0 +1,83 @@
+package model
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareStatus is the lifecycle state of a recipe share
+type ShareStatus string
+
+const (
+	ShareStatusPending  ShareStatus = "pending"
+	ShareStatusAccepted ShareStatus = "accepted"
+	ShareStatusDeclined ShareStatus = "declined"
+)
+
+// RecipeShare represents a shared recipe between users
+type RecipeShare struct {
+	ID               uuid.UUID   `json:"id" db:"id"`
+	RecipeID         uuid.UUID   `json:"recipeId" db:"recipe_id"`
+	OwnerID          uuid.UUID   `json:"ownerId" db:"owner_id"`
+	RecipientID      *uuid.UUID  `json:"recipientId,omitempty" db:"recipient_id"`
+	RecipientEmail   *string     `json:"recipientEmail,omitempty" db:"recipient_email"`
+	Status           ShareStatus `json:"status" db:"status"`
+	CreatedAt        time.Time   `json:"createdAt" db:"created_at"`
+	AcceptedAt       *time.Time  `json:"acceptedAt,omitempty" db:"accepted_at"`
+	DeclinedAt       *time.Time  `json:"declinedAt,omitempty" db:"declined_at"`
+	AcceptedRecipeID *uuid.UUID  `json:"acceptedRecipeId,omitempty" db:"accepted_recipe_id"` // recipient's copy
+
+	// Joined for display in the inbox
+	RecipeTitle        string  `json:"recipeTitle,omitempty" db:"-"`
+	RecipeThumbnailURL *string `json:"recipeThumbnailUrl,omitempty" db:"-"`
+	OwnerName          *string `json:"ownerName,omitempty" db:"-"`
+}
+
+// RecipeShareInput shares a recipe with a user by ID or email
+type RecipeShareInput struct {
+	RecipientID    *uuid.UUID `json:"recipientId,omitempty"`
+	RecipientEmail *string    `json:"recipientEmail,omitempty"`
+}
+
+// Validate validates the share input and normalizes the email
+func (s *RecipeShareInput) Validate() error {
+	if s.RecipientEmail != nil {
+		email := strings.ToLower(strings.TrimSpace(*s.RecipientEmail))
+		if email == "" {
+			s.RecipientEmail = nil
+		} else {
+			if len(email) > 255 {
+				return ErrValidation{Field: "recipientEmail", Reason: "max length 255 characters"}
+			}
+			if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+				return ErrValidation{Field: "recipientEmail", Reason: "invalid email address"}
+			}
+			s.RecipientEmail = &email
+		}
+	}
+	if s.RecipientID != nil && *s.RecipientID == uuid.Nil {
+		s.RecipientID = nil
+	}
+	if (s.RecipientID == nil) == (s.RecipientEmail == nil) {
+		return ErrValidation{Field: "recipient", Reason: "provide exactly one of recipientId or recipientEmail"}
+	}
+	return nil
+}
+
+// RecipeShareLink is a public link to a recipe for people without the app
+type RecipeShareLink struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	RecipeID     uuid.UUID  `json:"recipeId" db:"recipe_id"`
+	UserID       uuid.UUID  `json:"userId" db:"user_id"`
+	Token        string     `json:"token" db:"token"`
+	ViewCount    int        `json:"viewCount" db:"view_count"`
+	LastViewedAt *time.Time `json:"lastViewedAt,omitempty" db:"last_viewed_at"`
+	CreatedAt    time.Time  `json:"createdAt" db:"created_at"`
+	RevokedAt    *time.Time `json:"-" db:"revoked_at"`
+
+	// URL is the public page for the link
+	URL string `json:"url" db:"-"`
+}
@@ -8,29 +8,35 @@ import (
 
 // SyncRequest represents a client's sync request
 type SyncRequest struct {
-	LastSyncTimestamp time.Time      `json:"lastSyncTimestamp"`
-	Recipes           []Recipe       `json:"recipes,omitempty"`
-	PantryItems       []PantryItem   `json:"pantryItems,omitempty"`
-	ShoppingLists     []ShoppingList `json:"shoppingLists,omitempty"`
-	ShoppingItems     []ShoppingItem `json:"shoppingItems,omitempty"`
+	LastSyncTimestamp time.Time          `json:"lastSyncTimestamp"`
+	Recipes           []Recipe           `json:"recipes,omitempty"`
+	PantryItems       []PantryItem       `json:"pantryItems,omitempty"`
+	ShoppingLists     []ShoppingList     `json:"shoppingLists,omitempty"`
+	ShoppingItems     []ShoppingItem     `json:"shoppingItems,omitempty"`
+	Collections       []Collection       `json:"collections,omitempty"`
+	Annotations       []RecipeAnnotation `json:"annotations,omitempty"`
 }
 
 // SyncResponse represents the server's sync response
 type SyncResponse struct {
-	ServerTimestamp time.Time      `json:"serverTimestamp"`
-	Recipes         []Recipe       `json:"recipes,omitempty"`
-	PantryItems     []PantryItem   `json:"pantryItems,omitempty"`
-	ShoppingLists   []ShoppingList `json:"shoppingLists,omitempty"`
-	ShoppingItems   []ShoppingItem `json:"shoppingItems,omitempty"`
-	Conflicts       []Conflict     `json:"conflicts,omitempty"`
+	ServerTimestamp time.Time          `json:"serverTimestamp"`
+	Recipes         []Recipe           `json:"recipes,omitempty"`
+	PantryItems     []PantryItem       `json:"pantryItems,omitempty"`
+	ShoppingLists   []ShoppingList     `json:"shoppingLists,omitempty"`
+	ShoppingItems   []ShoppingItem     `json:"shoppingItems,omitempty"`
+	Collections     []Collection       `json:"collections,omitempty"`
+	Annotations     []RecipeAnnotation `json:"annotations,omitempty"`
+	Conflicts       []Conflict         `json:"conflicts,omitempty"`
 }
 
 // Conflict represents a sync conflict
 type Conflict struct {
-	ResourceType string    `json:"resourceType"` // "recipe", "pantry_item", "shopping_list", "shopping_item"
+	ResourceType string    `json:"resourceType"` // "recipe", "pantry_item", "shopping_list", "shopping_item", "collection", "annotation"
 	ResourceID   uuid.UUID `json:"resourceId"`
 	Resolution   string    `json:"resolution"` // "server_wins", "client_wins", "merged"
 	Reason       string    `json:"reason"`
+	// RevisionID points at the saved copy of a client edit that lost
+	RevisionID *uuid.UUID `json:"revisionId,omitempty"`
 }
 
 // ConflictResolution defines how conflicts are resolved
@@ -50,4 +56,6 @@ const (
 	ResourceTypePantryItem   ResourceType = "pantry_item"
 	ResourceTypeShoppingList ResourceType = "shopping_list"
 	ResourceTypeShoppingItem ResourceType = "shopping_item"
+	ResourceTypeCollection   ResourceType = "collection"
+	ResourceTypeAnnotation   ResourceType = "annotation"
 )
@@ -0,0 +1,60 @@
+package model
+
+import "strings"
+
+// MaxMergeTags caps how many tags one merge folds into another
+const MaxMergeTags = 50
+
+// MaxTagLength caps a tag's length in characters
+const MaxTagLength = 50
+
+// TagCount is one of a user's tags with how many recipes carry it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TagRenameInput renames a tag across a user's recipes
+type TagRenameInput struct {
+	Name string `json:"name"`
+}
+
+// Validate validates the rename input
+func (t *TagRenameInput) Validate() error {
+	return validateTag("name", t.Name)
+}
+
+// TagMergeInput folds several tags into one across a user's recipes
+type TagMergeInput struct {
+	Tags []string `json:"tags"`
+	Into string   `json:"into"`
+}
+
+// Validate validates the merge input
+func (t *TagMergeInput) Validate() error {
+	if len(t.Tags) == 0 {
+		return ErrValidation{Field: "tags", Reason: "required"}
+	}
+	if len(t.Tags) > MaxMergeTags {
+		return ErrValidation{Field: "tags", Reason: "max 50 tags"}
+	}
+	return validateTag("into", t.Into)
+}
+
+func validateTag(field, tag string) error {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return ErrValidation{Field: field, Reason: "required"}
+	}
+	if len([]rune(tag)) > MaxTagLength {
+		return ErrValidation{Field: field, Reason: "max 50 characters"}
+	}
+	return nil
+}
+
+// TagChangeResult reports what a tag rename, merge or delete did
+type TagChangeResult struct {
+	Tag      string   `json:"tag,omitempty"` // the tag recipes now carry; empty after a delete
+	Replaced []string `json:"replaced"`      // the tags as they were spelled before
+	Updated  int      `json:"updated"`       // recipes changed
+}
@@ -0,0 +1,293 @@
+// Package cassette provides a record/replay http.RoundTripper so tests and
+// offline tools can exercise real HTTP clients (Gemini, webpage fetching)
+// without network access or credentials.
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a Transport records live traffic or replays a cassette
+type Mode string
+
+const (
+	// ModeReplay serves responses from the cassette and never touches the network
+	ModeReplay Mode = "replay"
+	// ModeRecord forwards requests to the real transport and appends them to the cassette
+	ModeRecord Mode = "record"
+)
+
+// ModeFromEnv reads CASSETTE_MODE ("record" or "replay"), defaulting to replay
+// so CI never needs credentials.
+func ModeFromEnv() Mode {
+	if strings.EqualFold(os.Getenv("CASSETTE_MODE"), string(ModeRecord)) {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// ErrNoInteraction is returned in replay mode when no recorded interaction matches
+var ErrNoInteraction = fmt.Errorf("cassette: no matching interaction")
+
+// Query parameters and headers that must never be written to disk
+var (
+	redactedParams  = []string{"key", "api_key", "access_token"}
+	redactedHeaders = []string{"Authorization", "X-Goog-Api-Key", "Cookie", "Set-Cookie"}
+)
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded (redacted) form of an outgoing request
+type Request struct {
+	Method   string `json:"method"`
+	URL      string `json:"url"`
+	BodyHash string `json:"bodyHash,omitempty"` // sha256 of the canonicalized body
+	Body     string `json:"body,omitempty"`     // kept for readability when the body is small text
+}
+
+// Response is the recorded form of a response
+type Response struct {
+	StatusCode   int                 `json:"statusCode"`
+	Header       map[string][]string `json:"header,omitempty"`
+	Body         string              `json:"body"`
+	BodyEncoding string              `json:"bodyEncoding,omitempty"` // "base64" for binary bodies
+}
+
+// maxInlineRequestBody caps how much request body text is stored verbatim
+const maxInlineRequestBody = 4096
+
+// Transport is an http.RoundTripper backed by a cassette file.
+//
+// Replay matching is by method, redacted URL and body hash, all exact: a
+// request that changed since it was recorded (an edited prompt, say) fails
+// rather than getting the old answer, and the cassette must be re-recorded.
+type Transport struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New opens the cassette at path. In replay mode the file must exist; in record
+// mode any existing recording is discarded and next (http.DefaultTransport if
+// nil) is used for live requests. Call Save to persist a recording.
+func New(path string, mode Mode, next http.RoundTripper) (*Transport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &Transport{path: path, mode: mode, next: next}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cassette: load %s: %w", path, err)
+		}
+		if err := json.Unmarshal(data, &t.interactions); err != nil {
+			return nil, fmt.Errorf("cassette: parse %s: %w", path, err)
+		}
+		t.used = make([]bool, len(t.interactions))
+	}
+
+	return t, nil
+}
+
+// Mode returns the mode the transport was opened with
+func (t *Transport) Mode() Mode {
+	return t.mode
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := Request{
+		Method:   req.Method,
+		URL:      redactURL(req.URL),
+		BodyHash: hashBody(body),
+	}
+
+	if t.mode == ModeReplay {
+		interaction, ok := t.match(recorded)
+		if !ok {
+			return nil, fmt.Errorf("%w for %s %s (body %s); re-record with CASSETTE_MODE=record",
+				ErrNoInteraction, recorded.Method, recorded.URL, shortHash(recorded.BodyHash))
+		}
+		return interaction.Response.toHTTP(req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if len(body) <= maxInlineRequestBody && utf8.Valid(body) {
+		recorded.Body = string(body)
+	}
+
+	t.mu.Lock()
+	t.interactions = append(t.interactions, Interaction{
+		Request:  recorded,
+		Response: newResponse(resp, respBody),
+	})
+	t.mu.Unlock()
+
+	return resp, nil
+}
+
+// Save writes recorded interactions to disk. It is a no-op in replay mode.
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+
+	t.mu.Lock()
+	data, err := json.MarshalIndent(t.interactions, "", "  ")
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(t.path, append(data, '\n'), 0644)
+}
+
+func (t *Transport) match(r Request) (Interaction, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, in := range t.interactions {
+		if !t.used[i] && in.Request.Method == r.Method && in.Request.URL == r.URL && in.Request.BodyHash == r.BodyHash {
+			t.used[i] = true
+			return in, true
+		}
+	}
+	return Interaction{}, false
+}
+
+// shortHash abbreviates a body hash for error messages
+func shortHash(h string) string {
+	if h == "" {
+		return "empty"
+	}
+	return h[:min(len(h), 12)]
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// hashBody hashes the body, canonicalizing JSON first because protojson
+// deliberately randomizes whitespace between runs.
+func hashBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		if canonical, err := json.Marshal(v); err == nil {
+			body = canonical
+		}
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func redactURL(u *url.URL) string {
+	clean := *u
+	q := clean.Query()
+	for _, p := range redactedParams {
+		q.Del(p)
+	}
+	clean.RawQuery = q.Encode()
+	clean.User = nil
+	return clean.String()
+}
+
+func newResponse(resp *http.Response, body []byte) Response {
+	header := make(map[string][]string, len(resp.Header))
+	for k, v := range resp.Header {
+		header[k] = v
+	}
+	for _, h := range redactedHeaders {
+		delete(header, http.CanonicalHeaderKey(h))
+	}
+
+	r := Response{StatusCode: resp.StatusCode, Header: header}
+	if utf8.Valid(body) {
+		r.Body = string(body)
+	} else {
+		r.Body = base64.StdEncoding.EncodeToString(body)
+		r.BodyEncoding = "base64"
+	}
+	return r
+}
+
+func (r Response) toHTTP(req *http.Request) (*http.Response, error) {
+	body := []byte(r.Body)
+	if r.BodyEncoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("cassette: decode body: %w", err)
+		}
+		body = decoded
+	}
+
+	header := make(http.Header, len(r.Header))
+	for k, v := range r.Header {
+		header[k] = v
+	}
+	// Body is already decompressed and may differ in size from the original
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package cassette
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+
+	path := filepath.Join(t.TempDir(), "echo.json")
+
+	rec, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("New(record) failed: %v", err)
+	}
+	client := &http.Client{Transport: rec}
+	resp, err := client.Post(server.URL+"/v1/echo?key=SECRET&alt=json", "application/json", strings.NewReader(`{"a": 1, "b": 2}`))
+	if err != nil {
+		t.Fatalf("record request failed: %v", err)
+	}
+	resp.Body.Close()
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	server.Close()
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "SECRET") || strings.Contains(string(data), "session=secret") {
+		t.Fatalf("cassette leaked credentials: %s", data)
+	}
+
+	rep, err := New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("New(replay) failed: %v", err)
+	}
+	client = &http.Client{Transport: rep}
+
+	// Different key and JSON whitespace must still match; the server is already closed
+	resp, err = client.Post(server.URL+"/v1/echo?alt=json&key=OTHER", "application/json", strings.NewReader(`{"b":2,"a":1}`))
+	if err != nil {
+		t.Fatalf("replay request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != `{"echo":{"a": 1, "b": 2}}` {
+		t.Errorf("unexpected replay body: %s", body)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 live call, got %d", calls)
+	}
+
+	// Each interaction is served once
+	_, err = client.Post(server.URL+"/v1/echo?alt=json", "application/json", strings.NewReader(`{"a": 1, "b": 2}`))
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("expected ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestReplayChangedBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.json")
+	rec, err := New(path, ModeRecord, roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`"old answer"`))}, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&http.Client{Transport: rec}).Post("https://api.example.com/generate", "application/json", strings.NewReader(`{"prompt":"v1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = (&http.Client{Transport: rep}).Post("https://api.example.com/generate", "application/json", strings.NewReader(`{"prompt":"v2"}`))
+	if !errors.Is(err, ErrNoInteraction) || !strings.Contains(err.Error(), "re-record") {
+		t.Errorf("a changed prompt must not replay the old answer, got %v", err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestReplayMissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil); err == nil {
+		t.Fatal("expected error for missing cassette in replay mode")
+	}
+}
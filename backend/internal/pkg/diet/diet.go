@@ -0,0 +1,290 @@
+// Package diet classifies recipes for allergens and diets from their
+// ingredients with fixed rules, so a "nut-free" label never rests on the AI
+// alone.
+package diet
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/ingredient"
+	"github.com/dishflow/backend/internal/pkg/units"
+)
+
+// Diets the rules can rule out, in the order triggers are listed
+var diets = []string{"vegetarian", "vegan", "halal", "kosher"}
+
+// excludes maps each diet to the traits that rule it out
+var excludes = map[string][]string{
+	"vegetarian": {Meat, Pork, Fish, Shellfish, Gelatin, Rennet},
+	"vegan":      {Meat, Pork, Fish, Shellfish, Gelatin, Rennet, Dairy, Eggs, Honey},
+	"halal":      {Pork, Alcohol},
+	"kosher":     {Pork, Shellfish},
+}
+
+// descriptors are words that say how an ingredient is prepared or bought
+// rather than what it is, so they don't leave a name half-recognized
+var descriptors = map[string]bool{
+	"a": true, "and": true, "or": true, "of": true, "to": true, "for": true, "the": true, "taste": true,
+	"fresh": true, "freshly": true, "dried": true, "dry": true, "raw": true, "cooked": true, "frozen": true,
+	"canned": true, "tinned": true, "organic": true, "chopped": true, "minced": true, "diced": true,
+	"sliced": true, "ground": true, "grated": true, "shredded": true, "crushed": true, "whole": true,
+	"large": true, "small": true, "medium": true, "finely": true, "roughly": true, "thinly": true,
+	"peeled": true, "seeded": true, "pitted": true, "halved": true, "quartered": true, "cubed": true,
+	"rinsed": true, "drained": true, "divided": true, "packed": true, "softened": true, "melted": true,
+	"toasted": true, "roasted": true, "smoked": true, "warm": true, "cold": true, "hot": true,
+	"boiling": true, "room": true, "temperature": true, "extra": true, "virgin": true, "fine": true,
+	"coarse": true, "flaky": true, "unsalted": true, "salted": true, "sweetened": true, "unsweetened": true,
+	"plain": true, "light": true, "dark": true, "red": true, "green": true, "yellow": true, "white": true,
+	"black": true, "brown": true, "sweet": true, "baby": true, "flat": true, "leaf": true, "leaves": true,
+	"sprig": true, "stalk": true, "clove": true, "piece": true, "juice": true, "zest": true, "powder": true,
+	"flake": true, "paste": true, "puree": true, "pure": true, "granulated": true, "instant": true,
+	"optional": true, "more": true, "plus": true, "about": true, "good": true, "quality": true,
+	"mild": true, "spicy": true, "juiced": true, "heaping": true, "level": true, "italian": true,
+}
+
+// keyword is one ontology keyword split into normalized words
+type keyword struct {
+	words []string
+	rule  *rule
+}
+
+var keywords []keyword
+
+func init() {
+	for i := range ontology {
+		for _, k := range ontology[i].keywords {
+			keywords = append(keywords, keyword{words: words(k), rule: &ontology[i]})
+		}
+	}
+	// Longest first, so a longer keyword claims its words before the
+	// shorter ones inside it
+	slices.SortStableFunc(keywords, func(a, b keyword) int {
+		return len(b.words) - len(a.words)
+	})
+}
+
+// words splits a name into lowercase words with plurals stripped
+func words(name string) []string {
+	fields := strings.Fields(ingredient.MatchKey(name))
+	for i, w := range fields {
+		fields[i] = ingredient.NormalizeName(w)
+	}
+	return fields
+}
+
+// sameWord reports whether w is kw, allowing the plurals NormalizeName
+// leaves alone ("cloves", "peaches")
+func sameWord(w, kw string) bool {
+	return w == kw || w == kw+"s" || w == kw+"es"
+}
+
+// Classification is what the rules make of one ingredient
+type Classification struct {
+	Traits []string
+	// Known is set when every word of the name is recognized and none of
+	// the matching rules is uncertain, so the traits are the full story
+	Known bool
+}
+
+// Has reports whether the ingredient carries trait
+func (c Classification) Has(trait string) bool {
+	return slices.Contains(c.Traits, trait)
+}
+
+// Classify looks an ingredient name up in the ontology
+func Classify(name string) Classification {
+	w := words(name)
+	covered := make([]bool, len(w))
+	var c Classification
+	matched, uncertain := false, false
+
+	for _, k := range keywords {
+		for start := 0; start+len(k.words) <= len(w); start++ {
+			if !matchAt(w, covered, start, k.words) {
+				continue
+			}
+			for i := range k.words {
+				covered[start+i] = true
+			}
+			matched = true
+			uncertain = uncertain || k.rule.uncertain
+			for _, t := range k.rule.traits {
+				if !c.Has(t) {
+					c.Traits = append(c.Traits, t)
+				}
+			}
+		}
+	}
+
+	c.Known = matched && !uncertain
+	for i, word := range w {
+		if !covered[i] && !descriptors[word] && !isNumber(word) && !isUnit(word) {
+			c.Known = false
+		}
+	}
+	return c
+}
+
+// matchAt reports whether kw appears in w at start over words no longer
+// keyword has claimed
+func matchAt(w []string, covered []bool, start int, kw []string) bool {
+	for i, k := range kw {
+		if covered[start+i] || !sameWord(w[start+i], k) {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumber(s string) bool {
+	return strings.Trim(s, "0123456789") == ""
+}
+
+// isUnit reports whether a word is a measure left in the name ("2 cups milk")
+func isUnit(s string) bool {
+	_, ok := units.Lookup(s)
+	return ok
+}
+
+// rulesOut reports whether an ingredient alone rules out diet. Meat and
+// dairy together aren't kosher either, which Reconcile checks per recipe.
+func rulesOut(c Classification, diet string) bool {
+	for _, t := range excludes[diet] {
+		if c.Has(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reconcile classifies a recipe's ingredients and merges the result with
+// its previous dietary info, which may come from the AI or an earlier
+// classification. The rules win wherever they're sure: an allergen they
+// find is always listed and clears its "free" flag, and they can set a
+// flag only when every ingredient is known. Allergens the AI found are
+// kept even when the rules don't see them. For ingredients the rules
+// don't know, flags keep the AI's verdict as long as the AI has judged
+// those same ingredients.
+func Reconcile(prev *model.DietaryInfo, ingredients []model.RecipeIngredient) *model.DietaryInfo {
+	if prev == nil {
+		prev = &model.DietaryInfo{}
+	}
+	info := &model.DietaryInfo{
+		IsKeto:     prev.IsKeto,
+		MealTypes:  prev.MealTypes,
+		Classified: true,
+	}
+
+	// Allergens only the rules found before are recomputed below; the rest
+	// of the list came from the AI
+	sources := make(map[string]map[string]bool)
+	for _, t := range prev.Triggers {
+		if sources[t.Flag] == nil {
+			sources[t.Flag] = make(map[string]bool)
+		}
+		sources[t.Flag][t.Source] = true
+	}
+	var aiAllergens []string
+	for _, a := range prev.Allergens {
+		a = strings.ToLower(strings.TrimSpace(a))
+		fromAI := !sources[a][model.DietarySourceRules] || sources[a][model.DietarySourceAI]
+		if a != "" && fromAI && !slices.Contains(aiAllergens, a) {
+			aiAllergens = append(aiAllergens, a)
+		}
+	}
+
+	traits := make(map[string][]string) // trait → ingredients carrying it
+	ruledOut := make(map[string][]string)
+	allKnown := true
+	for _, ing := range ingredients {
+		name := strings.TrimSpace(ing.Name)
+		if name == "" {
+			continue
+		}
+		c := Classify(name)
+		if !c.Known {
+			allKnown = false
+			info.Unverified = append(info.Unverified, name)
+		}
+		for _, t := range c.Traits {
+			traits[t] = append(traits[t], name)
+		}
+		for _, d := range diets {
+			if rulesOut(c, d) {
+				ruledOut[d] = append(ruledOut[d], name)
+			}
+		}
+	}
+	if len(traits[Meat]) > 0 && len(traits[Dairy]) > 0 && len(ruledOut["kosher"]) == 0 {
+		ruledOut["kosher"] = append(slices.Clone(traits[Meat]), traits[Dairy]...)
+	}
+
+	// The AI's verdict on unknown ingredients only counts if it saw them:
+	// a recipe it never classified, or the same unknowns as last time
+	aiVouched := !prev.Classified
+	if !aiVouched {
+		aiVouched = true
+		for _, name := range info.Unverified {
+			if !slices.Contains(prev.Unverified, name) {
+				aiVouched = false
+				break
+			}
+		}
+	}
+
+	for _, a := range model.Allergens {
+		for _, name := range traits[a] {
+			info.Triggers = append(info.Triggers, model.DietaryTrigger{Flag: a, Ingredient: name, Source: model.DietarySourceRules})
+		}
+		if len(traits[a]) > 0 || slices.Contains(aiAllergens, a) {
+			info.Allergens = append(info.Allergens, a)
+		}
+	}
+	for _, a := range aiAllergens {
+		if !slices.Contains(model.Allergens, a) {
+			info.Allergens = append(info.Allergens, a)
+		}
+		info.Triggers = append(info.Triggers, model.DietaryTrigger{Flag: a, Source: model.DietarySourceAI})
+	}
+	for _, d := range diets {
+		for _, name := range ruledOut[d] {
+			info.Triggers = append(info.Triggers, model.DietaryTrigger{Flag: d, Ingredient: name, Source: model.DietarySourceRules})
+		}
+	}
+
+	// free decides a "free of" flag: false if anything rules it out, true
+	// if the rules know every ingredient, else the AI's verdict
+	free := func(aiFlag, blocked bool) bool {
+		switch {
+		case blocked:
+			return false
+		case allKnown:
+			return true
+		default:
+			return aiFlag && aiVouched
+		}
+	}
+	has := func(allergens ...string) bool {
+		for _, a := range allergens {
+			if len(traits[a]) > 0 || slices.Contains(aiAllergens, a) {
+				return true
+			}
+		}
+		return false
+	}
+
+	info.IsGlutenFree = free(prev.IsGlutenFree, has(Gluten))
+	info.IsDairyFree = free(prev.IsDairyFree, has(Dairy, "lactose"))
+	info.IsNutFree = free(prev.IsNutFree, has(Nuts, Peanuts))
+	info.IsVegetarian = free(prev.IsVegetarian, len(ruledOut["vegetarian"]) > 0 || has(Fish, Shellfish, Pork))
+	info.IsVegan = free(prev.IsVegan, len(ruledOut["vegan"]) > 0 || has(Fish, Shellfish, Pork, Dairy, "lactose", Eggs))
+
+	// The rules can't certify halal or kosher, which depend on how the
+	// food was produced, only rule them out
+	info.IsHalal = prev.IsHalal && aiVouched && len(ruledOut["halal"]) == 0 && !has(Pork)
+	info.IsKosher = prev.IsKosher && aiVouched && len(ruledOut["kosher"]) == 0 && !has(Pork, Shellfish)
+
+	return info
+}
@@ -0,0 +1,151 @@
+package diet
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		traits []string
+		known  bool
+	}{
+		{"Parmesan cheese, grated", []string{Dairy, Rennet}, true},
+		{"soy sauce", []string{Soy, Gluten}, true},
+		{"low-sodium tamari", []string{Soy}, false},
+		{"coconut milk", nil, true},
+		{"2 cups whole milk", []string{Dairy}, true},
+		{"smooth peanut butter", []string{Peanuts}, false},
+		{"peanut butter", []string{Peanuts}, true},
+		{"cream of tartar", nil, true},
+		{"Fresh basil leaves", nil, true},
+		{"Garlic cloves, minced", nil, true},
+		{"nutmeg", nil, true},
+		{"Worcestershire sauce", []string{Fish}, true},
+		{"rice flour", nil, true},
+		{"all-purpose flour", []string{Gluten}, true},
+		{"sourdough bread", []string{Gluten}, false},
+		{"chicken stock", []string{Meat}, true},
+		{"stock", nil, false},
+		{"bacon", []string{Meat, Pork}, true},
+		{"red wine vinegar", nil, true},
+		{"dry white wine", []string{Alcohol}, true},
+		{"onion rings", nil, false},
+		{"dragonfruit", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(tt.name)
+			if !slices.Equal(got.Traits, tt.traits) || got.Known != tt.known {
+				t.Errorf("Classify(%q) = %v known %v; want %v known %v", tt.name, got.Traits, got.Known, tt.traits, tt.known)
+			}
+		})
+	}
+}
+
+func ingredients(names ...string) []model.RecipeIngredient {
+	list := make([]model.RecipeIngredient, len(names))
+	for i, n := range names {
+		list[i] = model.RecipeIngredient{Name: n}
+	}
+	return list
+}
+
+func hasTrigger(info *model.DietaryInfo, flag, ingredient, source string) bool {
+	return slices.Contains(info.Triggers, model.DietaryTrigger{Flag: flag, Ingredient: ingredient, Source: source})
+}
+
+func TestReconcile(t *testing.T) {
+	t.Run("rules override a wrong AI label", func(t *testing.T) {
+		ai := &model.DietaryInfo{IsVegetarian: true, IsVegan: true, IsDairyFree: true, IsGlutenFree: true, IsNutFree: true, IsKeto: true}
+		info := Reconcile(ai, ingredients("spaghetti", "parmesan", "pine nuts", "basil", "olive oil"))
+
+		if info.IsVegetarian || info.IsVegan || info.IsDairyFree || info.IsGlutenFree || info.IsNutFree {
+			t.Errorf("flags should be cleared: %+v", info)
+		}
+		if !info.IsKeto {
+			t.Error("keto is left to the AI")
+		}
+		if !slices.Equal(info.Allergens, []string{"gluten", "dairy", "nuts"}) {
+			t.Errorf("allergens %v", info.Allergens)
+		}
+		for _, want := range []model.DietaryTrigger{
+			{Flag: "dairy", Ingredient: "parmesan", Source: model.DietarySourceRules},
+			{Flag: "vegetarian", Ingredient: "parmesan", Source: model.DietarySourceRules},
+			{Flag: "nuts", Ingredient: "pine nuts", Source: model.DietarySourceRules},
+		} {
+			if !hasTrigger(info, want.Flag, want.Ingredient, want.Source) {
+				t.Errorf("missing trigger %+v in %+v", want, info.Triggers)
+			}
+		}
+		if !info.Classified || len(info.Unverified) != 0 {
+			t.Errorf("all ingredients should be known: %v", info.Unverified)
+		}
+	})
+
+	t.Run("known ingredients set flags", func(t *testing.T) {
+		info := Reconcile(nil, ingredients("chickpeas", "tahini", "lemon juice", "garlic", "salt"))
+		if !info.IsVegan || !info.IsVegetarian || !info.IsGlutenFree || !info.IsDairyFree || !info.IsNutFree {
+			t.Errorf("expected free flags: %+v", info)
+		}
+		if !slices.Equal(info.Allergens, []string{"sesame"}) {
+			t.Errorf("allergens %v", info.Allergens)
+		}
+		if info.IsHalal || info.IsKosher {
+			t.Error("rules alone can't certify halal or kosher")
+		}
+	})
+
+	t.Run("unknown ingredients keep the AI's verdict", func(t *testing.T) {
+		ai := &model.DietaryInfo{IsNutFree: true, IsHalal: true, Allergens: []string{"Soy"}}
+		info := Reconcile(ai, ingredients("rice", "mystery spice blend"))
+		if !info.IsNutFree || !info.IsHalal {
+			t.Errorf("AI flags should stand: %+v", info)
+		}
+		if info.IsGlutenFree {
+			t.Error("a flag the AI didn't give shouldn't be set")
+		}
+		if !slices.Equal(info.Allergens, []string{"soy"}) || !hasTrigger(info, "soy", "", model.DietarySourceAI) {
+			t.Errorf("AI allergen should be kept: %v %+v", info.Allergens, info.Triggers)
+		}
+		if !slices.Equal(info.Unverified, []string{"mystery spice blend"}) {
+			t.Errorf("unverified %v", info.Unverified)
+		}
+
+		// Saving again keeps the verdict and doesn't turn the AI's soy
+		// into a rules finding
+		again := Reconcile(info, ingredients("rice", "mystery spice blend"))
+		if !again.IsNutFree || !slices.Equal(again.Allergens, []string{"soy"}) {
+			t.Errorf("second pass changed the result: %+v", again)
+		}
+
+		// A new unknown ingredient wasn't judged by the AI
+		edited := Reconcile(info, ingredients("rice", "mystery spice blend", "secret sauce"))
+		if edited.IsNutFree || edited.IsHalal {
+			t.Errorf("new unknown ingredient should clear flags: %+v", edited)
+		}
+	})
+
+	t.Run("removing an ingredient drops its allergen", func(t *testing.T) {
+		first := Reconcile(nil, ingredients("rice", "soy sauce"))
+		info := Reconcile(first, ingredients("rice", "salt"))
+		if len(info.Allergens) != 0 || !info.IsGlutenFree {
+			t.Errorf("stale rules allergens: %+v", info)
+		}
+	})
+
+	t.Run("meat with dairy isn't kosher", func(t *testing.T) {
+		ai := &model.DietaryInfo{IsKosher: true, IsHalal: true}
+		info := Reconcile(ai, ingredients("ground beef", "butter", "onion"))
+		if info.IsKosher || !hasTrigger(info, "kosher", "butter", model.DietarySourceRules) {
+			t.Errorf("expected kosher ruled out: %+v", info)
+		}
+		if !info.IsHalal {
+			t.Error("halal should keep the AI's verdict")
+		}
+	})
+}
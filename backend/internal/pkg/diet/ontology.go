@@ -0,0 +1,132 @@
+package diet
+
+// Traits an ingredient can carry. The allergens match model.Allergens; the
+// rest only rule out diets.
+const (
+	Gluten    = "gluten"
+	Dairy     = "dairy"
+	Eggs      = "eggs"
+	Soy       = "soy"
+	Nuts      = "nuts"
+	Peanuts   = "peanuts"
+	Sesame    = "sesame"
+	Fish      = "fish"
+	Shellfish = "shellfish"
+	Pork      = "pork"
+
+	Meat    = "meat"    // land animals and poultry
+	Gelatin = "gelatin" // from animal bones and skin
+	Rennet  = "rennet"  // cheeses made with animal rennet
+	Honey   = "honey"
+	Alcohol = "alcohol"
+)
+
+// rule gives the traits of ingredients named by its keywords. A rule with no
+// traits marks a known ingredient free of all of them. Uncertain rules name
+// ingredients whose make-up varies by brand or recipe ("bread", "stock"), so
+// their traits are flagged but they can't vouch for the rest.
+type rule struct {
+	keywords  []string
+	traits    []string
+	uncertain bool
+}
+
+// ontology maps ingredients to traits. Keywords are matched as whole words
+// with the longest match winning, so "coconut milk" beats "milk", "peanut
+// butter" beats "butter" and "rice flour" beats "flour".
+var ontology = []rule{
+	// Dairy
+	{keywords: []string{"milk", "whole milk", "skim milk", "buttermilk", "milk powder", "condensed milk", "evaporated milk"}, traits: []string{Dairy}},
+	{keywords: []string{"cream", "heavy cream", "double cream", "single cream", "whipping cream", "sour cream", "creme fraiche", "crème fraîche", "half and half", "ice cream"}, traits: []string{Dairy}},
+	{keywords: []string{"butter", "ghee", "unsalted butter", "salted butter"}, traits: []string{Dairy}},
+	{keywords: []string{"cheese", "cheddar", "mozzarella", "feta", "ricotta", "mascarpone", "cream cheese", "cottage cheese", "goat cheese", "brie", "camembert", "gouda", "halloumi", "paneer", "burrata", "monterey jack", "swiss cheese", "blue cheese"}, traits: []string{Dairy}},
+	{keywords: []string{"parmesan", "parmigiano", "parmigiano reggiano", "pecorino", "pecorino romano", "grana padano", "gruyere", "gruyère", "manchego", "gorgonzola", "emmental", "comte", "comté"}, traits: []string{Dairy, Rennet}},
+	{keywords: []string{"yogurt", "yoghurt", "greek yogurt", "kefir", "whey", "casein", "labneh"}, traits: []string{Dairy}},
+	{keywords: []string{"milk chocolate", "white chocolate"}, traits: []string{Dairy, Soy}},
+	{keywords: []string{"custard"}, traits: []string{Dairy, Eggs}},
+
+	// Look like dairy but aren't
+	{keywords: []string{"coconut milk", "coconut cream", "rice milk", "cocoa butter", "apple butter", "cream of tartar", "butternut", "butternut squash", "butter bean", "butterhead lettuce", "butter lettuce", "oyster mushroom"}},
+	{keywords: []string{"almond milk", "cashew milk", "almond butter", "cashew butter", "nut butter"}, traits: []string{Nuts}},
+	{keywords: []string{"oat milk"}, uncertain: true},
+	{keywords: []string{"soy milk", "soya milk"}, traits: []string{Soy}},
+	{keywords: []string{"vegan butter", "vegan cheese", "plant-based butter", "margarine"}, uncertain: true},
+
+	// Eggs
+	{keywords: []string{"egg", "egg yolk", "egg white", "mayonnaise", "mayo", "aioli", "meringue"}, traits: []string{Eggs}},
+	{keywords: []string{"egg noodle", "egg pasta", "wonton wrapper", "dumpling wrapper"}, traits: []string{Eggs, Gluten}},
+
+	// Gluten
+	{keywords: []string{"flour", "all-purpose flour", "plain flour", "bread flour", "self-raising flour", "self-rising flour", "whole wheat flour", "wholemeal flour", "cake flour", "pastry flour"}, traits: []string{Gluten}},
+	{keywords: []string{"wheat", "semolina", "durum", "spelt", "barley", "rye", "bulgur", "couscous", "farro", "seitan", "malt", "wheat germ"}, traits: []string{Gluten}},
+	{keywords: []string{"breadcrumb", "panko", "pasta", "spaghetti", "penne", "fusilli", "linguine", "fettuccine", "tagliatelle", "lasagna", "lasagne", "macaroni", "orzo", "rigatoni", "udon", "ramen", "flour tortilla", "pita", "baguette", "croissant", "cracker", "graham cracker", "phyllo", "filo", "pie crust", "pizza dough"}, traits: []string{Gluten}},
+	{keywords: []string{"bread", "bun", "roll", "noodle", "soba", "tortilla", "biscuit", "cookie", "cake", "pastry", "puff pastry", "brioche"}, traits: []string{Gluten}, uncertain: true},
+	{keywords: []string{"soy sauce", "teriyaki sauce", "teriyaki"}, traits: []string{Soy, Gluten}},
+	{keywords: []string{"hoisin sauce", "hoisin"}, traits: []string{Soy, Gluten}, uncertain: true},
+	{keywords: []string{"beer", "ale", "stout", "lager"}, traits: []string{Gluten, Alcohol}},
+
+	// Look like gluten but aren't
+	{keywords: []string{"rice flour", "coconut flour", "chickpea flour", "gram flour", "corn flour", "cornflour", "cornstarch", "corn starch", "cornmeal", "polenta", "buckwheat", "tapioca", "tapioca flour", "potato starch", "arrowroot", "gluten-free flour", "corn tortilla", "rice noodle", "rice paper", "cassava flour"}},
+	{keywords: []string{"almond flour", "ground almond"}, traits: []string{Nuts}},
+	{keywords: []string{"oat", "rolled oat", "oatmeal", "oat flour"}, uncertain: true},
+
+	// Soy
+	{keywords: []string{"soy", "soya", "soybean", "tofu", "tempeh", "edamame", "miso", "tamari", "soybean oil"}, traits: []string{Soy}},
+
+	// Tree nuts
+	{keywords: []string{"nut", "mixed nut", "almond", "walnut", "pecan", "cashew", "pistachio", "hazelnut", "macadamia", "brazil nut", "pine nut", "chestnut", "marzipan", "almond extract"}, traits: []string{Nuts}},
+	{keywords: []string{"praline", "nutella", "frangipane"}, traits: []string{Nuts, Dairy}, uncertain: true},
+	{keywords: []string{"pesto"}, traits: []string{Nuts, Dairy, Rennet}},
+
+	// Look like nuts but aren't
+	{keywords: []string{"nutmeg", "coconut", "water chestnut", "nutritional yeast"}},
+
+	// Peanuts
+	{keywords: []string{"peanut", "peanut butter", "peanut oil", "groundnut", "satay", "satay sauce"}, traits: []string{Peanuts}},
+
+	// Sesame
+	{keywords: []string{"sesame", "sesame seed", "sesame oil", "tahini", "hummus", "halva", "halvah", "za'atar", "zaatar"}, traits: []string{Sesame}},
+
+	// Fish
+	{keywords: []string{"fish", "salmon", "tuna", "cod", "haddock", "halibut", "tilapia", "trout", "sardine", "anchovy", "anchovies", "mackerel", "sea bass", "snapper", "swordfish", "sole", "catfish", "herring", "bonito", "caviar", "roe"}, traits: []string{Fish}},
+	{keywords: []string{"fish sauce", "fish stock", "worcestershire", "worcestershire sauce", "dashi"}, traits: []string{Fish}},
+	{keywords: []string{"caesar dressing"}, traits: []string{Fish, Eggs, Dairy, Rennet}},
+
+	// Shellfish
+	{keywords: []string{"shrimp", "prawn", "crab", "lobster", "crawfish", "crayfish", "langoustine", "scallop", "clam", "mussel", "oyster", "squid", "calamari", "octopus", "oyster sauce", "shrimp paste"}, traits: []string{Shellfish}},
+
+	// Meat
+	{keywords: []string{"meat", "chicken", "beef", "veal", "lamb", "mutton", "goat", "turkey", "duck", "goose", "venison", "rabbit", "quail", "steak", "mince", "ground beef", "ground turkey", "ground chicken", "meatball", "brisket", "oxtail", "liver", "suet", "tallow", "bone marrow"}, traits: []string{Meat}},
+	{keywords: []string{"chicken stock", "chicken broth", "beef stock", "beef broth", "bone broth", "chicken bouillon", "beef bouillon"}, traits: []string{Meat}},
+	{keywords: []string{"pork", "pork belly", "pork shoulder", "pork chop", "ham", "bacon", "pancetta", "prosciutto", "guanciale", "chorizo", "salami", "pepperoni", "lard", "pork rind", "ground pork"}, traits: []string{Meat, Pork}},
+	{keywords: []string{"sausage", "hot dog"}, traits: []string{Meat}, uncertain: true},
+	{keywords: []string{"stock", "broth", "bouillon"}, uncertain: true},
+	{keywords: []string{"vegetable stock", "vegetable broth", "vegetable bouillon", "mushroom broth"}},
+	{keywords: []string{"gelatin", "gelatine"}, traits: []string{Gelatin}},
+	{keywords: []string{"honey"}, traits: []string{Honey}},
+
+	// Alcohol
+	{keywords: []string{"wine", "red wine", "white wine", "rum", "vodka", "whisky", "whiskey", "brandy", "cognac", "bourbon", "sake", "mirin", "sherry", "liqueur", "amaretto", "marsala", "vermouth", "tequila", "gin", "champagne", "prosecco", "cider", "kahlua", "grand marnier"}, traits: []string{Alcohol}},
+	{keywords: []string{"wine vinegar", "red wine vinegar", "white wine vinegar", "sherry vinegar", "cider vinegar", "apple cider vinegar", "rice wine vinegar", "ginger ale", "ginger beer"}},
+	{keywords: []string{"apple cider"}, uncertain: true},
+
+	// Vegetables
+	{keywords: []string{"onion", "red onion", "garlic", "shallot", "leek", "carrot", "celery", "vegetable", "potato", "sweet potato", "tomato", "cherry tomato", "tomato paste", "tomato sauce", "passata", "bell pepper", "pepper", "chili", "chile", "chilli", "jalapeno", "jalapeño", "cucumber", "zucchini", "courgette", "eggplant", "aubergine", "mushroom", "spinach", "kale", "lettuce", "arugula", "rocket", "cabbage", "broccoli", "cauliflower", "pea", "green bean", "corn", "sweetcorn", "avocado", "pumpkin", "squash", "beet", "beetroot", "radish", "asparagus", "artichoke", "fennel", "scallion", "green onion", "spring onion", "okra", "bok choy", "brussels sprout", "turnip", "parsnip", "yam", "watercress", "bean sprout"}},
+
+	// Fruit
+	{keywords: []string{"lemon", "lime", "orange", "apple", "banana", "berry", "strawberry", "blueberry", "raspberry", "blackberry", "cranberry", "mango", "pineapple", "grape", "peach", "pear", "plum", "apricot", "cherry", "date", "fig", "raisin", "kiwi", "pomegranate", "watermelon", "melon", "papaya", "passion fruit", "lemon juice", "lime juice", "orange juice", "apple juice", "zest", "lemon zest", "lime zest", "orange zest", "olive", "caper"}},
+
+	// Herbs and spices
+	{keywords: []string{"salt", "sea salt", "kosher salt", "black pepper", "white pepper", "peppercorn", "paprika", "smoked paprika", "cumin", "coriander", "cilantro", "parsley", "basil", "oregano", "thyme", "rosemary", "sage", "mint", "dill", "chive", "tarragon", "bay leaf", "bay leaves", "cinnamon", "clove", "cardamom", "turmeric", "chili powder", "chili flake", "red pepper flake", "cayenne", "curry powder", "garam masala", "five spice", "vanilla", "vanilla extract", "vanilla bean", "saffron", "star anise", "allspice", "fenugreek", "mustard", "mustard seed", "dijon mustard", "ginger", "lemongrass", "kaffir lime leaf", "sumac", "italian seasoning", "herbes de provence"}},
+
+	// Grains and legumes
+	{keywords: []string{"rice", "white rice", "brown rice", "basmati rice", "jasmine rice", "arborio rice", "wild rice", "quinoa", "millet", "amaranth", "bean", "black bean", "kidney bean", "pinto bean", "cannellini bean", "navy bean", "chickpea", "garbanzo", "lentil", "red lentil", "split pea", "potato flake"}},
+
+	// Pantry
+	{keywords: []string{"water", "ice", "sugar", "brown sugar", "caster sugar", "powdered sugar", "icing sugar", "confectioners sugar", "maple syrup", "agave", "molasses", "corn syrup", "golden syrup", "stevia"}},
+	{keywords: []string{"oil", "olive oil", "extra virgin olive oil", "vegetable oil", "canola oil", "rapeseed oil", "sunflower oil", "coconut oil", "avocado oil", "grapeseed oil", "cooking spray"}},
+	{keywords: []string{"vinegar", "balsamic vinegar", "rice vinegar", "white vinegar", "coffee", "espresso", "tea", "sparkling water", "club soda"}},
+	{keywords: []string{"baking powder", "baking soda", "bicarbonate of soda", "yeast", "instant yeast", "dry yeast", "cocoa", "cocoa powder", "agar", "agar agar"}},
+	{keywords: []string{"chocolate", "dark chocolate", "chocolate chip", "semisweet chocolate", "bittersweet chocolate"}, uncertain: true},
+	{keywords: []string{"ketchup", "salsa", "sriracha", "hot sauce", "tabasco", "harissa", "pickle", "jam", "sambal", "gochujang", "curry paste"}, uncertain: true},
+}
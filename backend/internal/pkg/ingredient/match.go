@@ -0,0 +1,93 @@
+package ingredient
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// NormalizeName lowercases an ingredient name and strips a clear English
+// plural, so "Tomatoes" and "tomato" compare equal
+func NormalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	// Safe plural stripping: only handle clear cases to avoid mangling
+	// words like "cheese", "rice", "sauce", "grapes", "olives"
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		// berries→berry, cherries→cherry
+		name = name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "oes"):
+		// tomatoes→tomato, potatoes→potato
+		name = name[:len(name)-2]
+	case strings.HasSuffix(name, "ves"):
+		// halves→half (but not "olives" — too risky, skip)
+	case strings.HasSuffix(name, "ses") || strings.HasSuffix(name, "zes") ||
+		strings.HasSuffix(name, "xes") || strings.HasSuffix(name, "ches") ||
+		strings.HasSuffix(name, "shes"):
+		// sauces→sauce, peaches→peach — don't strip, these are fine as-is
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") &&
+		!strings.HasSuffix(name, "us") && !strings.HasSuffix(name, "is"):
+		// carrots→carrot, onions→onion, but not "hummus", "couscous", "lentils" edge case is ok
+		name = name[:len(name)-1]
+	}
+
+	return name
+}
+
+// MatchKey is the form ingredient names are stored and compared in for
+// ingredient search: NormalizeName applied to the name's words, with
+// punctuation collapsed to single spaces. Keys only hold letters, digits
+// and spaces, so they are safe inside a regular expression.
+func MatchKey(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	return NormalizeName(strings.Join(words, " "))
+}
+
+// CommonSubstitutes maps an ingredient to common stand-ins for it
+var CommonSubstitutes = map[string][]string{
+	"butter":        {"margarine", "oil", "coconut oil"},
+	"milk":          {"almond milk", "oat milk", "soy milk", "coconut milk"},
+	"egg":           {"flax egg", "chia egg", "applesauce"},
+	"flour":         {"almond flour", "coconut flour", "whole wheat flour"},
+	"sugar":         {"honey", "maple syrup", "stevia"},
+	"sour cream":    {"greek yogurt", "yogurt"},
+	"heavy cream":   {"coconut cream", "evaporated milk"},
+	"lemon juice":   {"lime juice", "vinegar"},
+	"onion":         {"shallot", "leek", "green onion"},
+	"garlic":        {"garlic powder", "garlic paste"},
+	"chicken broth": {"vegetable broth", "beef broth", "water"},
+	"parsley":       {"cilantro", "basil"},
+	"basil":         {"oregano", "parsley"},
+}
+
+// substitutedFor is the reverse index: substitute → original ingredient
+var substitutedFor map[string]string
+
+func init() {
+	substitutedFor = make(map[string]string)
+	for original, subs := range CommonSubstitutes {
+		for _, sub := range subs {
+			substitutedFor[sub] = original
+		}
+	}
+}
+
+// SubstitutedFor returns the ingredient that sub commonly stands in for
+func SubstitutedFor(sub string) (string, bool) {
+	original, ok := substitutedFor[sub]
+	return original, ok
+}
+
+// Substitutes returns the ingredients name can stand in for or be replaced
+// by, in either direction of CommonSubstitutes
+func Substitutes(name string) []string {
+	name = NormalizeName(name)
+	subs := append([]string(nil), CommonSubstitutes[name]...)
+	if original, ok := substitutedFor[name]; ok && !slices.Contains(subs, original) {
+		subs = append(subs, original)
+	}
+	return subs
+}
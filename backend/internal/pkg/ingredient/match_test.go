@@ -0,0 +1,42 @@
+package ingredient
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchKey(t *testing.T) {
+	tests := map[string]string{
+		"Tomatoes":             "tomato",
+		"  Cherry Tomatoes, ":  "cherry tomato",
+		"chicken-thighs":       "chicken thigh",
+		"Berries":              "berry",
+		"rice":                 "rice",
+		"hummus":               "hummus",
+		"peaches":              "peaches",
+		"crème fraîche":        "crème fraîche",
+		"salt & pepper (opt.)": "salt pepper opt",
+		"!!!":                  "",
+	}
+	for name, want := range tests {
+		if got := MatchKey(name); got != want {
+			t.Errorf("MatchKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSubstitutes(t *testing.T) {
+	if got, want := Substitutes("Eggs"), []string{"flax egg", "chia egg", "applesauce"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Substitutes(Eggs) = %v, want %v", got, want)
+	}
+	if got, want := Substitutes("margarine"), []string{"butter"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Substitutes(margarine) = %v, want %v", got, want)
+	}
+	// basil and parsley stand in for each other
+	if got, want := Substitutes("basil"), []string{"oregano", "parsley"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Substitutes(basil) = %v, want %v", got, want)
+	}
+	if got := Substitutes("saffron"); len(got) != 0 {
+		t.Errorf("Substitutes(saffron) = %v, want none", got)
+	}
+}
@@ -0,0 +1,92 @@
+package ingredient
+
+import "strings"
+
+// prepWords describe how an ingredient is prepared rather than what it is
+var prepWords = map[string]bool{
+	// English
+	"chopped": true, "finely": true, "roughly": true, "coarsely": true, "thinly": true, "freshly": true,
+	"diced": true, "minced": true, "sliced": true, "grated": true, "shredded": true, "crushed": true,
+	"peeled": true, "cubed": true, "julienned": true, "halved": true, "quartered": true, "melted": true,
+	"softened": true, "beaten": true, "sifted": true, "drained": true, "rinsed": true, "trimmed": true,
+	"seeded": true, "deseeded": true, "cored": true, "zested": true, "toasted": true, "juiced": true,
+	"mashed": true,
+	// French
+	"haché": true, "hachée": true, "hachés": true, "hachées": true,
+	"émincé": true, "émincée": true, "émincés": true, "émincées": true,
+	"râpé": true, "râpée": true, "râpés": true, "râpées": true,
+	"pelé": true, "pelée": true, "pelés": true, "pelées": true,
+	"fondu": true, "fondue": true, "ciselé": true, "ciselée": true, "ciselés": true,
+	"écrasé": true, "écrasée": true, "écrasés": true, "écrasées": true,
+	"finement": true, "grossièrement": true,
+	// Spanish
+	"picado": true, "picada": true, "picados": true, "picadas": true,
+	"rallado": true, "rallada": true, "rallados": true, "ralladas": true,
+	"pelado": true, "pelada": true, "pelados": true, "peladas": true,
+	"derretido": true, "derretida": true, "finamente": true,
+	// Arabic
+	"مفروم": true, "مفرومة": true, "مبشور": true, "مبشورة": true, "مقطع": true, "مقطعة": true,
+}
+
+// prepJoiners may sit between two preparation words ("peeled and diced")
+var prepJoiners = map[string]bool{"and": true, "et": true, "y": true, "و": true}
+
+// servingPhrases are trailing notes that aren't part of the name
+var servingPhrases = []string{
+	"or to taste", "to taste", "as needed", "for serving", "for garnish", "to serve",
+	"selon le goût", "au goût", "al gusto", "حسب الرغبة", "حسب الذوق",
+}
+
+// splitNotes separates the ingredient name from preparation notes: anything
+// after the first comma, serving phrases, and prep words before or after the
+// name ("2 finely chopped onions", "oignons émincés")
+func splitNotes(s string) (name, notes string) {
+	s = strings.TrimSpace(s)
+	var parts []string
+	if before, after, ok := strings.Cut(s, ","); ok {
+		s = strings.TrimSpace(before)
+		if after = strings.Trim(after, " ,;"); after != "" {
+			parts = append(parts, after)
+		}
+	}
+
+	lower := strings.ToLower(s)
+	for _, p := range servingPhrases {
+		if strings.HasSuffix(lower, " "+p) {
+			s = strings.TrimSpace(s[:len(s)-len(p)])
+			parts = append(parts, p)
+			break
+		}
+	}
+
+	words := strings.Fields(s)
+	lead := 0
+	for lead < len(words)-1 && isPrep(words, lead) {
+		lead++
+	}
+	trail := len(words)
+	for trail > lead+1 && isPrep(words, trail-1) {
+		trail--
+	}
+
+	var prep []string
+	if lead > 0 {
+		prep = append(prep, strings.Join(words[:lead], " "))
+	}
+	if trail < len(words) {
+		prep = append(prep, strings.Join(words[trail:], " "))
+	}
+	parts = append(prep, parts...)
+
+	return strings.Join(words[lead:trail], " "), strings.Join(parts, ", ")
+}
+
+// isPrep reports whether words[i] is a prep word, or a joiner between two
+func isPrep(words []string, i int) bool {
+	w := strings.ToLower(strings.Trim(words[i], ",;"))
+	if prepWords[w] {
+		return true
+	}
+	return prepJoiners[w] && i > 0 && i < len(words)-1 &&
+		prepWords[strings.ToLower(words[i-1])] && prepWords[strings.ToLower(words[i+1])]
+}
@@ -0,0 +1,422 @@
+// Package ingredient parses free-text ingredient lines such as
+// "1 ½ cups flour, sifted", "2-3 cloves garlic", "1 (14 oz) can tomatoes" or
+// "200 g de farine" into a quantity, unit, name and notes.
+//
+// The parser is deterministic and works the same for AI extraction output,
+// manually typed recipes and imported files. It understands unicode and
+// mixed fractions, ranges, parenthetical package sizes, preparation notes and
+// units written in English, French, Spanish and Arabic.
+package ingredient
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dishflow/backend/internal/pkg/units"
+)
+
+// Amount is a quantity with a unit, e.g. the "14 oz" in "1 (14 oz) can"
+type Amount struct {
+	Quantity float64
+	Unit     string
+}
+
+// String renders the amount as "14 oz"
+func (a Amount) String() string {
+	return strings.TrimSpace(strconv.FormatFloat(a.Quantity, 'f', -1, 64) + " " + a.Unit)
+}
+
+// Line is a parsed ingredient line
+type Line struct {
+	// Quantity is nil for unquantified ingredients ("salt to taste").
+	// For ranges it holds the lower bound and QuantityMax the upper.
+	Quantity    *float64
+	QuantityMax *float64
+	// Unit is canonical ("tbsp", "cup", "clove") or empty
+	Unit string
+	Name string
+	// Notes holds preparation and serving notes ("finely chopped", "to taste")
+	Notes       string
+	PackageSize *Amount
+	IsOptional  bool
+}
+
+// AllNotes returns the package size and notes as a single string, which is
+// how they are stored on a recipe ingredient
+func (l Line) AllNotes() string {
+	var parts []string
+	if l.PackageSize != nil {
+		parts = append(parts, l.PackageSize.String())
+	}
+	if l.Notes != "" {
+		parts = append(parts, l.Notes)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Parse parses a single ingredient line. It never fails: text it can't make
+// sense of ends up in Name.
+func Parse(line string) Line {
+	var l Line
+	text := normalize(line)
+	text, l.IsOptional = stripOptional(text)
+
+	text, parens := extractParens(text)
+	var notes []string
+	for _, p := range parens {
+		if amount, ok := parseAmount(p); ok && l.PackageSize == nil {
+			l.PackageSize = &amount
+		} else {
+			notes = append(notes, p)
+		}
+	}
+
+	tokens := splitAttachedUnits(strings.Fields(text))
+	l.Quantity, l.QuantityMax, tokens = parseQuantity(tokens)
+
+	var plural bool
+	if l.Quantity != nil {
+		plural = *l.Quantity > 1 || l.QuantityMax != nil
+	}
+	l.Unit, tokens = parseUnit(tokens, l.Quantity, plural)
+	tokens = stripConnector(tokens)
+
+	name, prep := splitNotes(strings.Join(tokens, " "))
+	l.Name = name
+	if prep != "" {
+		notes = append([]string{prep}, notes...)
+	}
+	l.Notes = strings.Join(notes, ", ")
+	return l
+}
+
+// ParseQuantity parses a quantity on its own ("1 1/2", "½", "2-3"). ok is
+// false when s doesn't start with a number.
+func ParseQuantity(s string) (min, max *float64, ok bool) {
+	tokens := splitAttachedUnits(strings.Fields(normalize(s)))
+	min, max, _ = parseQuantity(tokens)
+	return min, max, min != nil
+}
+
+// vulgarFractions maps unicode fraction characters to ASCII fractions
+var vulgarFractions = map[rune]string{
+	'½': "1/2", '⅓': "1/3", '⅔': "2/3", '¼': "1/4", '¾': "3/4",
+	'⅕': "1/5", '⅖': "2/5", '⅗': "3/5", '⅘': "4/5", '⅙': "1/6", '⅚': "5/6",
+	'⅛': "1/8", '⅜': "3/8", '⅝': "5/8", '⅞': "7/8",
+}
+
+// normalize rewrites unicode fractions, dashes and Arabic-Indic digits to
+// ASCII so the rest of the parser only deals with one spelling
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case vulgarFractions[r] != "":
+			// "1½" becomes "1 1/2"
+			b.WriteString(" " + vulgarFractions[r])
+		case r >= '٠' && r <= '٩':
+			b.WriteRune('0' + r - '٠')
+		case r >= '۰' && r <= '۹':
+			b.WriteRune('0' + r - '۰')
+		case r == '٫':
+			b.WriteRune('.')
+		case r == '،':
+			b.WriteRune(',')
+		case r == '⁄':
+			b.WriteRune('/')
+		case r == '–' || r == '—':
+			b.WriteRune('-')
+		case r == ' ':
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// optionalMarker matches an "optional" marker as a whole word, in any case.
+// \b only knows ASCII words, so the boundaries are spelled out to cover
+// accented and Arabic text.
+var optionalMarker = regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(\(optional\)|optional|\(facultatif\)|facultatif|\(opcional\)|opcional|\(اختياري\)|اختياري)(?:[^\p{L}\p{N}]|$)`)
+
+// stripOptional removes an "optional" marker wherever it appears
+func stripOptional(s string) (string, bool) {
+	m := optionalMarker.FindStringSubmatchIndex(s)
+	if m == nil {
+		return s, false
+	}
+	rest := strings.Trim(s[:m[2]]+" "+s[m[3]:], " ,;")
+	return strings.Join(strings.Fields(rest), " "), true
+}
+
+// extractParens removes top-level parenthesised text and returns it separately
+func extractParens(s string) (string, []string) {
+	var out strings.Builder
+	var parens []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			if depth == 0 {
+				start = i + 1
+			}
+			depth++
+		case ')':
+			if depth == 0 {
+				out.WriteRune(r)
+				continue
+			}
+			depth--
+			if depth == 0 {
+				if p := strings.TrimSpace(s[start:i]); p != "" {
+					parens = append(parens, p)
+				}
+			}
+		default:
+			if depth == 0 {
+				out.WriteRune(r)
+			}
+		}
+	}
+	return strings.Join(strings.Fields(out.String()), " "), parens
+}
+
+var approximateWords = map[string]bool{
+	"about": true, "approx": true, "approx.": true, "approximately": true, "around": true, "roughly": true,
+	"environ": true, "unos": true, "unas": true, "aproximadamente": true, "حوالي": true,
+}
+
+// parseAmount parses a package size such as "14 oz" or "about 400 g". Only
+// measurable units count; "(2 cloves)" stays a note.
+func parseAmount(s string) (Amount, bool) {
+	tokens := splitAttachedUnits(strings.Fields(normalize(s)))
+	if len(tokens) > 0 && approximateWords[strings.ToLower(tokens[0])] {
+		tokens = tokens[1:]
+	}
+	min, _, tokens := parseQuantity(tokens)
+	if min == nil || len(tokens) == 0 {
+		return Amount{}, false
+	}
+	u, ok := units.Lookup(strings.Join(tokens, " "))
+	if !ok || u.Dimension == units.Count {
+		return Amount{}, false
+	}
+	return Amount{Quantity: *min, Unit: u.Label(*min)}, true
+}
+
+// splitAttachedUnits splits "200g" and "1.5kg" into a number and a unit token
+func splitAttachedUnits(tokens []string) []string {
+	out := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		i := 0
+		for i < len(t) && (t[i] >= '0' && t[i] <= '9' || t[i] == '.' || t[i] == ',' || t[i] == '/') {
+			i++
+		}
+		if i > 0 && i < len(t) {
+			if _, ok := lookupUnit(t[i:]); ok {
+				out = append(out, t[:i], t[i:])
+				continue
+			}
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// numberWords are spelled-out quantities at the start of a line
+var numberWords = map[string]float64{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "half": 0.5,
+	"un": 1, "une": 1, "deux": 2, "trois": 3, "demi": 0.5,
+	"una": 1, "uno": 1, "dos": 2, "tres": 3, "medio": 0.5, "media": 0.5,
+}
+
+// vagueWords after an article mean there is no real quantity ("a few", "a little")
+var vagueWords = map[string]bool{"few": true, "little": true, "bit": true, "peu": true, "poco": true, "poca": true}
+
+// rangeConnectors join the two ends of a range ("2 to 3", "2 à 3", "2 o 3")
+var rangeConnectors = map[string]bool{"-": true, "to": true, "or": true, "à": true, "a": true, "o": true, "ou": true, "إلى": true, "الى": true, "أو": true}
+
+// parseQuantity consumes a leading quantity or range from tokens
+func parseQuantity(tokens []string) (min, max *float64, rest []string) {
+	if len(tokens) == 0 {
+		return nil, nil, tokens
+	}
+
+	lower := strings.ToLower(tokens[0])
+	if v, ok := numberWords[lower]; ok {
+		if len(tokens) > 1 && vagueWords[strings.ToLower(tokens[1])] {
+			return nil, nil, tokens
+		}
+		return &v, nil, tokens[1:]
+	}
+
+	// "2-3" and "1-1/2" in a single token
+	if lo, hi, ok := strings.Cut(tokens[0], "-"); ok && lo != "" && hi != "" {
+		a, okA := parseNumber(lo)
+		b, okB := parseNumber(hi)
+		if okA && okB {
+			if b < 1 && a == float64(int(a)) && strings.Contains(hi, "/") {
+				v := a + b
+				return &v, nil, tokens[1:]
+			}
+			if b > a {
+				return &a, &b, tokens[1:]
+			}
+			return &a, nil, tokens[1:]
+		}
+	}
+
+	v, n := parseMixed(tokens)
+	if n == 0 {
+		return nil, nil, tokens
+	}
+	tokens = tokens[n:]
+
+	// Range: "2 - 3", "2 to 3", or a dangling "2- 3"
+	if len(tokens) >= 2 && rangeConnectors[strings.ToLower(tokens[0])] {
+		if hi, m := parseMixed(tokens[1:]); m > 0 && hi > v {
+			return &v, &hi, tokens[1+m:]
+		}
+	}
+	return &v, nil, tokens
+}
+
+// parseMixed parses "1", "1.5", "1/2" or a mixed number "1 1/2"
+func parseMixed(tokens []string) (float64, int) {
+	if len(tokens) == 0 {
+		return 0, 0
+	}
+	v, ok := parseNumber(tokens[0])
+	if !ok {
+		return 0, 0
+	}
+	if len(tokens) > 1 && v == float64(int(v)) && strings.Contains(tokens[1], "/") {
+		if frac, ok := parseNumber(tokens[1]); ok && frac < 1 {
+			return v + frac, 2
+		}
+	}
+	return v, 1
+}
+
+// parseNumber parses "2", "1.5", "1,5" (decimal comma) or "3/4"
+func parseNumber(s string) (float64, bool) {
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, err1 := strconv.ParseFloat(num, 64)
+		d, err2 := strconv.ParseFloat(den, 64)
+		if err1 != nil || err2 != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	if whole, frac, ok := strings.Cut(s, ","); ok {
+		if len(frac) == 3 {
+			s = whole + frac // thousands separator: "1,000"
+		} else {
+			s = whole + "." + frac
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+// countUnit is a non-measurable unit with its canonical English spelling
+type countUnit struct {
+	singular, plural string
+}
+
+var countUnits = map[string]countUnit{}
+
+func init() {
+	for _, u := range []struct {
+		unit     countUnit
+		spelling []string
+	}{
+		{countUnit{"pinch", "pinches"}, []string{"pinch", "pinches", "pincée", "pincées", "pizca", "pizcas", "رشة"}},
+		{countUnit{"dash", "dashes"}, []string{"dash", "dashes", "trait", "chorrito"}},
+		{countUnit{"clove", "cloves"}, []string{"clove", "cloves", "gousse", "gousses", "diente", "dientes", "فص", "فصوص"}},
+		{countUnit{"can", "cans"}, []string{"can", "cans", "tin", "tins", "boîte", "boîtes", "boite", "boites", "lata", "latas", "علبة", "علب"}},
+		{countUnit{"slice", "slices"}, []string{"slice", "slices", "tranche", "tranches", "rebanada", "rebanadas", "loncha", "lonchas", "شريحة", "شرائح"}},
+		{countUnit{"bunch", "bunches"}, []string{"bunch", "bunches", "botte", "bottes", "manojo", "manojos", "حزمة"}},
+		{countUnit{"sprig", "sprigs"}, []string{"sprig", "sprigs", "brin", "brins", "ramita", "ramitas"}},
+		{countUnit{"handful", "handfuls"}, []string{"handful", "handfuls", "poignée", "poignées", "puñado", "puñados", "حفنة"}},
+		{countUnit{"piece", "pieces"}, []string{"piece", "pieces", "pc", "pcs", "morceau", "morceaux", "pieza", "piezas", "حبة", "حبات"}},
+		{countUnit{"package", "packages"}, []string{"package", "packages", "pack", "packs", "packet", "packets", "paquet", "paquets", "paquete", "paquetes", "sachet", "sachets", "كيس"}},
+		{countUnit{"stick", "sticks"}, []string{"stick", "sticks"}},
+		{countUnit{"head", "heads"}, []string{"head", "heads", "tête", "têtes"}},
+		{countUnit{"stalk", "stalks"}, []string{"stalk", "stalks", "branche", "branches", "tallo", "tallos"}},
+		{countUnit{"leaf", "leaves"}, []string{"leaf", "leaves", "feuille", "feuilles", "hoja", "hojas", "ورقة", "أوراق"}},
+		{countUnit{"drop", "drops"}, []string{"drop", "drops", "goutte", "gouttes", "gota", "gotas"}},
+	} {
+		for _, s := range u.spelling {
+			countUnits[s] = u.unit
+		}
+	}
+}
+
+// lookupUnit resolves measurable and countable unit spellings
+func lookupUnit(s string) (func(plural bool) string, bool) {
+	if u, ok := units.Lookup(s); ok && u.Dimension != units.Temperature {
+		return func(plural bool) string {
+			if plural {
+				return u.Label(2)
+			}
+			return u.Name
+		}, true
+	}
+	if u, ok := countUnits[strings.ToLower(strings.TrimSuffix(s, "."))]; ok {
+		return func(plural bool) string {
+			if plural {
+				return u.plural
+			}
+			return u.singular
+		}, true
+	}
+	return nil, false
+}
+
+// parseUnit consumes a unit of up to three words ("cuillère à soupe", "fl oz").
+// Without a quantity only multi-letter units count, so "l'huile" or a stray
+// "c" isn't mistaken for one.
+func parseUnit(tokens []string, qty *float64, plural bool) (string, []string) {
+	for n := 3; n >= 1; n-- {
+		if len(tokens) < n {
+			continue
+		}
+		candidate := strings.Join(tokens[:n], " ")
+		if qty == nil && len([]rune(candidate)) < 3 {
+			continue
+		}
+		// Don't swallow the whole line: "2 cans" has a unit but "can" alone is a name
+		if label, ok := lookupUnit(candidate); ok && (qty != nil || len(tokens) > n) {
+			return label(plural), tokens[n:]
+		}
+	}
+	return "", tokens
+}
+
+// stripConnector removes "of"/"de"/"d'" between a unit and the name
+func stripConnector(tokens []string) []string {
+	if len(tokens) < 2 {
+		return tokens
+	}
+	switch strings.ToLower(tokens[0]) {
+	case "of", "de", "del", "du", "des", "من":
+		tokens = tokens[1:]
+		if len(tokens) > 1 && strings.ToLower(tokens[0]) == "la" {
+			tokens = tokens[1:]
+		}
+		return tokens
+	}
+	for _, prefix := range []string{"d'", "d’"} {
+		if len(tokens[0]) > len(prefix) && strings.HasPrefix(strings.ToLower(tokens[0]), prefix) {
+			out := append([]string{tokens[0][len(prefix):]}, tokens[1:]...)
+			return out
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,128 @@
+package ingredient
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func f(v float64) *float64 { return &v }
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want Line
+	}{
+		{"2 cups flour", Line{Quantity: f(2), Unit: "cups", Name: "flour"}},
+		{"1 1/2 cups milk", Line{Quantity: f(1.5), Unit: "cups", Name: "milk"}},
+		{"1½ tsp salt", Line{Quantity: f(1.5), Unit: "tsp", Name: "salt"}},
+		{"½ cup sugar", Line{Quantity: f(0.5), Unit: "cup", Name: "sugar"}},
+		{"2-3 cloves garlic, minced", Line{Quantity: f(2), QuantityMax: f(3), Unit: "cloves", Name: "garlic", Notes: "minced"}},
+		{"2 to 3 tbsp olive oil", Line{Quantity: f(2), QuantityMax: f(3), Unit: "tbsp", Name: "olive oil"}},
+		{"1 (14 oz) can diced tomatoes", Line{Quantity: f(1), Unit: "can", Name: "tomatoes", Notes: "diced", PackageSize: &Amount{14, "oz"}}},
+		{"a pinch of salt", Line{Quantity: f(1), Unit: "pinch", Name: "salt"}},
+		{"salt and pepper to taste", Line{Name: "salt and pepper", Notes: "to taste"}},
+		{"2 large onions, finely chopped", Line{Quantity: f(2), Name: "large onions", Notes: "finely chopped"}},
+		{"3 finely chopped shallots", Line{Quantity: f(3), Name: "shallots", Notes: "finely chopped"}},
+		{"200g dark chocolate (70%)", Line{Quantity: f(200), Unit: "g", Name: "dark chocolate", Notes: "70%"}},
+		{"1 tbsp chopped parsley (optional)", Line{Quantity: f(1), Unit: "tbsp", Name: "parsley", Notes: "chopped", IsOptional: true}},
+		{"1 cup nonoptional thing", Line{Quantity: f(1), Unit: "cup", Name: "nonoptional thing"}},
+		{"1-1/2 lb chicken thighs", Line{Quantity: f(1.5), Unit: "lb", Name: "chicken thighs"}},
+		{"a few sprigs thyme", Line{Name: "a few sprigs thyme"}},
+		{"4 eggs, beaten", Line{Quantity: f(4), Name: "eggs", Notes: "beaten"}},
+		// French
+		{"2 cuillères à soupe d'huile d'olive", Line{Quantity: f(2), Unit: "tbsp", Name: "huile d'olive"}},
+		{"1,5 kg de farine", Line{Quantity: f(1.5), Unit: "kg", Name: "farine"}},
+		{"3 gousses d'ail hachées", Line{Quantity: f(3), Unit: "cloves", Name: "ail", Notes: "hachées"}},
+		{"2 oignons émincés", Line{Quantity: f(2), Name: "oignons", Notes: "émincés"}},
+		// Spanish
+		{"2 cucharaditas de sal", Line{Quantity: f(2), Unit: "tsp", Name: "sal"}},
+		{"1 taza de arroz", Line{Quantity: f(1), Unit: "cup", Name: "arroz"}},
+		{"una pizca de pimienta", Line{Quantity: f(1), Unit: "pinch", Name: "pimienta"}},
+		// Arabic
+		{"٢ كوب دقيق", Line{Quantity: f(2), Unit: "cups", Name: "دقيق"}},
+		{"1 ملعقة كبيرة زيت زيتون", Line{Quantity: f(1), Unit: "tbsp", Name: "زيت زيتون"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := Parse(tt.line)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) =\n  %s\nwant\n  %s", tt.line, describe(got), describe(tt.want))
+			}
+		})
+	}
+}
+
+func TestStripOptional(t *testing.T) {
+	tests := []struct {
+		in, want string
+		optional bool
+	}{
+		{"ȺȺȺȺ optional", "ȺȺȺȺ", true},
+		{"İİİİ optional", "İİİİ", true},
+		{"1 cup nonoptional thing", "1 cup nonoptional thing", false},
+		{"1 tbsp parsley (Optional)", "1 tbsp parsley", true},
+		{"sel facultatif", "sel", true},
+		{"ملح اختياري", "ملح", true},
+	}
+	for _, tt := range tests {
+		got, optional := stripOptional(tt.in)
+		if got != tt.want || optional != tt.optional {
+			t.Errorf("stripOptional(%q) = %q, %v; want %q, %v", tt.in, got, optional, tt.want, tt.optional)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("stripOptional(%q) returned invalid UTF-8", tt.in)
+		}
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in       string
+		min, max *float64
+	}{
+		{"1.5", f(1.5), nil},
+		{"1/2", f(0.5), nil},
+		{"1 1/2", f(1.5), nil},
+		{"¾", f(0.75), nil},
+		{"2–3", f(2), f(3)},
+		{"1,000", f(1000), nil},
+		{"a pinch", f(1), nil},
+		{"some", nil, nil},
+		{"", nil, nil},
+	}
+	for _, tt := range tests {
+		min, max, ok := ParseQuantity(tt.in)
+		if !reflect.DeepEqual(min, tt.min) || !reflect.DeepEqual(max, tt.max) || ok != (tt.min != nil) {
+			t.Errorf("ParseQuantity(%q) = %v, %v, %v", tt.in, deref(min), deref(max), ok)
+		}
+	}
+}
+
+func TestAllNotes(t *testing.T) {
+	l := Parse("1 (400 g) tin chickpeas, drained and rinsed")
+	if got := l.AllNotes(); got != "400 g, drained and rinsed" {
+		t.Errorf("AllNotes() = %q", got)
+	}
+}
+
+func deref(p *float64) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func describe(l Line) string {
+	pkg := interface{}(nil)
+	if l.PackageSize != nil {
+		pkg = *l.PackageSize
+	}
+	return fmtLine(deref(l.Quantity), deref(l.QuantityMax), l.Unit, l.Name, l.Notes, pkg, l.IsOptional)
+}
+
+func fmtLine(args ...interface{}) string {
+	return fmt.Sprintf("qty=%v max=%v unit=%q name=%q notes=%q package=%v optional=%v", args...)
+}
@@ -0,0 +1,183 @@
+// Package pagination pages through lists with opaque keyset cursors.
+//
+// A list is ordered by a Keyset whose last key is unique. A page is fetched
+// with limit+1 rows; when the extra row is there, the keys of the last row
+// kept become the next page's cursor. Unlike offsets, rows added or removed
+// while a client pages through don't shift later pages, and the database
+// seeks straight to the position instead of skipping rows.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a cursor can't be decoded, or was made
+// for a different ordering
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page asks for one page of a list
+type Page struct {
+	Limit  int
+	Cursor string // from the previous page's next cursor; empty for the first page
+
+	// Offset skips rows from the start of the list, for clients that still
+	// page by offset. It is ignored when Cursor is set.
+	Offset *int
+}
+
+// ByOffset reports whether the page is asked for by offset rather than
+// by cursor
+func (p Page) ByOffset() bool {
+	return p.Cursor == "" && p.Offset != nil
+}
+
+// Skip returns the OFFSET clause for a page asked for by offset, or ""
+// otherwise. arg adds a query argument and returns its placeholder.
+func (p Page) Skip(arg func(any) string) string {
+	if !p.ByOffset() || *p.Offset <= 0 {
+		return ""
+	}
+	return " OFFSET " + arg(*p.Offset)
+}
+
+// Key is one column of a keyset ordering. SQL must never be NULL; wrap
+// nullable columns in COALESCE with a sentinel that sorts where NULLs should.
+type Key struct {
+	SQL  string
+	Type string // SQL type the key's text form is cast back to
+	Desc bool
+}
+
+// Keyset is an ordering whose keys, taken together, are unique per row
+type Keyset struct {
+	// Name tags cursors so one made for another ordering is rejected
+	Name string
+	Keys []Key
+}
+
+// OrderBy returns the ORDER BY list for the keyset
+func (k Keyset) OrderBy() string {
+	parts := make([]string, len(k.Keys))
+	for i, key := range k.Keys {
+		parts[i] = key.SQL + direction(key.Desc)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Columns returns the keys as text, to select after a row's own columns
+// and scan into the values passed to Encode
+func (k Keyset) Columns() string {
+	parts := make([]string, len(k.Keys))
+	for i, key := range k.Keys {
+		parts[i] = "(" + key.SQL + ")::text"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// After returns a condition selecting the rows after the position values
+// (from Decode) in the ordering. arg adds a query argument and returns its
+// placeholder.
+func (k Keyset) After(values []string, arg func(any) string) string {
+	placeholders := make([]string, len(k.Keys))
+	for i, key := range k.Keys {
+		placeholders[i] = arg(values[i]) + "::text::" + key.Type
+	}
+
+	// Keys sorted the same way compare as one row, which an index on them
+	// can serve directly
+	if k.uniform() {
+		cols := make([]string, len(k.Keys))
+		for i, key := range k.Keys {
+			cols[i] = key.SQL
+		}
+		return "(" + strings.Join(cols, ", ") + ") " + comparison(k.Keys[0].Desc) +
+			" (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	// Otherwise: past the first key, or equal to it and past the rest
+	cond := ""
+	for i := len(k.Keys) - 1; i >= 0; i-- {
+		key := k.Keys[i]
+		past := key.SQL + " " + comparison(key.Desc) + " " + placeholders[i]
+		if cond == "" {
+			cond = past
+			continue
+		}
+		cond = "(" + past + " OR (" + key.SQL + " = " + placeholders[i] + " AND " + cond + "))"
+	}
+	return cond
+}
+
+// Encode returns an opaque cursor for a row position, given the row's keys
+// as selected by Columns
+func (k Keyset) Encode(values []string) string {
+	raw, _ := json.Marshal(append([]string{k.Name}, values...))
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode returns the row position in a cursor made by Encode
+func (k Keyset) Decode(cursor string) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var values []string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if len(values) != len(k.Keys)+1 || values[0] != k.Name {
+		return nil, ErrInvalidCursor
+	}
+	return values[1:], nil
+}
+
+// Ordering returns the Name of the keyset a cursor was made for, so an
+// ordering that depends on a value kept in its name (such as a shuffle
+// seed) can be rebuilt from the cursor
+func Ordering(cursor string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", ErrInvalidCursor
+	}
+	var values []string
+	if err := json.Unmarshal(raw, &values); err != nil || len(values) == 0 {
+		return "", ErrInvalidCursor
+	}
+	return values[0], nil
+}
+
+// Next trims rows fetched with a limit of limit+1 back to limit and, when
+// there were more, returns the cursor for the next page. keys holds each
+// row's keys as selected by Columns.
+func Next[T any](k Keyset, rows []T, keys [][]string, limit int) ([]T, string) {
+	if len(rows) <= limit {
+		return rows, ""
+	}
+	return rows[:limit], k.Encode(keys[limit-1])
+}
+
+func (k Keyset) uniform() bool {
+	for _, key := range k.Keys[1:] {
+		if key.Desc != k.Keys[0].Desc {
+			return false
+		}
+	}
+	return true
+}
+
+func direction(desc bool) string {
+	if desc {
+		return " DESC"
+	}
+	return " ASC"
+}
+
+func comparison(desc bool) string {
+	if desc {
+		return "<"
+	}
+	return ">"
+}
@@ -0,0 +1,116 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+var recent = Keyset{Name: "recent", Keys: []Key{
+	{SQL: "r.created_at", Type: "timestamptz", Desc: true},
+	{SQL: "r.id", Type: "uuid", Desc: true},
+}}
+
+var quickest = Keyset{Name: "time", Keys: []Key{
+	{SQL: "r.total", Type: "int"},
+	{SQL: "r.created_at", Type: "timestamptz", Desc: true},
+	{SQL: "r.id", Type: "uuid"},
+}}
+
+func placeholders() (func(any) string, *[]any) {
+	var args []any
+	return func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}, &args
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	values := []string{"2024-02-01 10:30:00.123456+00", "550e8400-e29b-41d4-a716-446655440000"}
+	got, err := recent.Decode(recent.Encode(values))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("Decode = %v, want %v", got, values)
+	}
+
+	// Keys may hold any text, separators included
+	title := Keyset{Name: "title", Keys: []Key{{SQL: "t", Type: "text"}}}
+	if got, err := title.Decode(title.Encode([]string{`mac | "cheese"`})); err != nil || got[0] != `mac | "cheese"` {
+		t.Errorf("Decode = %v, %v", got, err)
+	}
+}
+
+func TestDecodeRejectsBadCursors(t *testing.T) {
+	cursors := map[string]string{
+		"not base64":      "%%%",
+		"not json":        "bm90IGpzb24",
+		"other ordering":  quickest.Encode([]string{"30", "2024-02-01", "550e8400-e29b-41d4-a716-446655440000"}),
+		"wrong key count": Keyset{Name: "recent", Keys: recent.Keys[:1]}.Encode([]string{"2024-02-01"}),
+	}
+	for name, cursor := range cursors {
+		if _, err := recent.Decode(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: err = %v, want ErrInvalidCursor", name, err)
+		}
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	if got, want := quickest.OrderBy(), "r.total ASC, r.created_at DESC, r.id ASC"; got != want {
+		t.Errorf("OrderBy = %q, want %q", got, want)
+	}
+}
+
+func TestAfter(t *testing.T) {
+	arg, args := placeholders()
+	got := recent.After([]string{"2024-02-01", "id"}, arg)
+	if want := "(r.created_at, r.id) < ($1::text::timestamptz, $2::text::uuid)"; got != want {
+		t.Errorf("After = %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(*args, []any{"2024-02-01", "id"}) {
+		t.Errorf("args = %v", *args)
+	}
+
+	// Mixed directions expand key by key
+	arg, _ = placeholders()
+	got = quickest.After([]string{"30", "2024-02-01", "id"}, arg)
+	want := "(r.total > $1::text::int OR (r.total = $1::text::int AND " +
+		"(r.created_at < $2::text::timestamptz OR (r.created_at = $2::text::timestamptz AND r.id > $3::text::uuid))))"
+	if got != want {
+		t.Errorf("After = %q, want %q", got, want)
+	}
+}
+
+func TestNext(t *testing.T) {
+	keys := [][]string{{"c", "3"}, {"b", "2"}, {"a", "1"}}
+
+	rows, cursor := Next(recent, []int{3, 2, 1}, keys, 2)
+	if !reflect.DeepEqual(rows, []int{3, 2}) {
+		t.Errorf("rows = %v, want [3 2]", rows)
+	}
+	if values, err := recent.Decode(cursor); err != nil || !reflect.DeepEqual(values, keys[1]) {
+		t.Errorf("cursor holds %v, %v; want %v", values, err, keys[1])
+	}
+
+	if rows, cursor := Next(recent, []int{3, 2, 1}, keys, 3); len(rows) != 3 || cursor != "" {
+		t.Errorf("last page = %v, %q; want all rows and no cursor", rows, cursor)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	offset := 40
+	arg, args := placeholders()
+	if got := (Page{Offset: &offset}).Skip(arg); got != " OFFSET $1" || (*args)[0] != 40 {
+		t.Errorf("Skip = %q, args %v", got, *args)
+	}
+
+	arg, _ = placeholders()
+	if got := (Page{Offset: &offset, Cursor: "abc"}).Skip(arg); got != "" {
+		t.Errorf("cursor should win over offset, got %q", got)
+	}
+	if got := (Page{}).Skip(arg); got != "" {
+		t.Errorf("no offset should skip nothing, got %q", got)
+	}
+}
@@ -0,0 +1,73 @@
+// Package tag normalizes free-form recipe tags, so "Vegan", "vegan " and
+// "plant-based" end up as one tag.
+package tag
+
+import "strings"
+
+// DefaultSynonyms map common variants to the tag they mean. Configured
+// synonyms are added on top and can override these.
+var DefaultSynonyms = map[string]string{
+	"plant-based":    "vegan",
+	"plant based":    "vegan",
+	"veggie":         "vegetarian",
+	"gf":             "gluten-free",
+	"gluten free":    "gluten-free",
+	"dairy free":     "dairy-free",
+	"nut free":       "nut-free",
+	"low carb":       "low-carb",
+	"high protein":   "high-protein",
+	"low calorie":    "low-calorie",
+	"desserts":       "dessert",
+	"quick and easy": "quick",
+	"quick & easy":   "quick",
+}
+
+// Normalizer folds tags to a canonical form: trimmed, lowercase, single
+// spaced, with synonyms replaced
+type Normalizer struct {
+	synonyms map[string]string
+}
+
+// NewNormalizer creates a normalizer with the default synonyms plus extra
+func NewNormalizer(extra map[string]string) *Normalizer {
+	n := &Normalizer{synonyms: make(map[string]string, len(DefaultSynonyms)+len(extra))}
+	for _, m := range []map[string]string{DefaultSynonyms, extra} {
+		for from, to := range m {
+			if from, to = fold(from), fold(to); from != "" && to != "" {
+				n.synonyms[from] = to
+			}
+		}
+	}
+	return n
+}
+
+// fold trims, lowercases and collapses whitespace
+func fold(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// Tag normalizes one tag; it returns "" for a blank one
+func (n *Normalizer) Tag(s string) string {
+	s = fold(s)
+	if to, ok := n.synonyms[s]; ok {
+		return to
+	}
+	return s
+}
+
+// Tags normalizes a tag list, dropping blanks and keeping the first of
+// tags that normalize the same
+func (n *Normalizer) Tags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		if t = n.Tag(t); t != "" && !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package tag
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizer(t *testing.T) {
+	n := NewNormalizer(map[string]string{"Weeknight": "quick", "veggie": "Meatless"})
+
+	tests := map[string]string{
+		"Vegan":            "vegan",
+		"  Plant-Based ":   "vegan",
+		"Gluten   Free":    "gluten-free",
+		"weeknight":        "quick",
+		"veggie":           "meatless",
+		"Italian":          "italian",
+		"   ":              "",
+		"Quick and   easy": "quick",
+		"one-pot":          "one-pot",
+	}
+	for in, want := range tests {
+		if got := n.Tag(in); got != want {
+			t.Errorf("Tag(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	got := n.Tags([]string{"Vegan", "dinner", "plant-based", "", "Dinner", "vegan"})
+	if !slices.Equal(got, []string{"vegan", "dinner"}) {
+		t.Errorf("Tags = %v", got)
+	}
+	if n.Tags(nil) != nil {
+		t.Error("nil tags should stay nil")
+	}
+}
@@ -0,0 +1,97 @@
+package units
+
+import "strings"
+
+// density is how much one millilitre of an ingredient weighs, as measured
+// with a spoon or cup (so flour is spooned and levelled, brown sugar packed)
+type density struct {
+	keywords []string
+	gPerMl   float64
+	// liquid ingredients are measured by volume in both systems
+	liquid bool
+}
+
+var densities = []density{
+	// Liquids
+	{keywords: []string{"water", "stock", "broth", "coffee", "tea", "wine", "vinegar", "juice"}, gPerMl: 1, liquid: true},
+	{keywords: []string{"milk", "buttermilk", "cream", "half and half"}, gPerMl: 1.03, liquid: true},
+	{keywords: []string{"coconut milk"}, gPerMl: 0.98, liquid: true},
+	{keywords: []string{"oil", "olive oil", "vegetable oil"}, gPerMl: 0.92, liquid: true},
+	{keywords: []string{"soy sauce"}, gPerMl: 1.15, liquid: true},
+	{keywords: []string{"honey", "molasses", "golden syrup"}, gPerMl: 1.42, liquid: true},
+	{keywords: []string{"maple syrup"}, gPerMl: 1.32, liquid: true},
+
+	// Dry and solid ingredients
+	{keywords: []string{"flour", "all-purpose flour", "plain flour", "bread flour", "self-raising flour", "self-rising flour"}, gPerMl: 0.53},
+	{keywords: []string{"whole wheat flour", "wholemeal flour"}, gPerMl: 0.51},
+	{keywords: []string{"almond flour", "ground almonds"}, gPerMl: 0.41},
+	{keywords: []string{"cornstarch", "cornflour", "corn starch"}, gPerMl: 0.54},
+	{keywords: []string{"cocoa", "cocoa powder"}, gPerMl: 0.36},
+	{keywords: []string{"sugar", "granulated sugar", "caster sugar", "white sugar"}, gPerMl: 0.85},
+	{keywords: []string{"brown sugar"}, gPerMl: 0.93},
+	{keywords: []string{"powdered sugar", "icing sugar", "confectioners sugar", "confectioners' sugar"}, gPerMl: 0.51},
+	{keywords: []string{"butter"}, gPerMl: 0.96},
+	{keywords: []string{"peanut butter"}, gPerMl: 1.08},
+	{keywords: []string{"salt", "table salt", "sea salt"}, gPerMl: 1.22},
+	{keywords: []string{"kosher salt"}, gPerMl: 0.61},
+	{keywords: []string{"baking powder"}, gPerMl: 0.81},
+	{keywords: []string{"baking soda", "bicarbonate of soda"}, gPerMl: 1.22},
+	{keywords: []string{"yeast"}, gPerMl: 0.64},
+	{keywords: []string{"rice"}, gPerMl: 0.79},
+	{keywords: []string{"oats", "rolled oats"}, gPerMl: 0.38},
+	{keywords: []string{"couscous"}, gPerMl: 0.74},
+	{keywords: []string{"quinoa"}, gPerMl: 0.72},
+	{keywords: []string{"lentils"}, gPerMl: 0.81},
+	{keywords: []string{"breadcrumbs", "panko"}, gPerMl: 0.25},
+	{keywords: []string{"yogurt", "yoghurt", "sour cream", "cream cheese"}, gPerMl: 1.03},
+	{keywords: []string{"parmesan", "grated cheese", "shredded cheese"}, gPerMl: 0.42},
+	{keywords: []string{"chocolate chips"}, gPerMl: 0.72},
+	{keywords: []string{"raisins"}, gPerMl: 0.63},
+	{keywords: []string{"nuts", "walnuts", "pecans", "almonds", "hazelnuts"}, gPerMl: 0.5},
+}
+
+// lookupDensity finds the density entry whose keyword matches the most
+// specific (longest) part of the ingredient name, so "brown sugar" beats
+// "sugar" and "peanut butter" beats "butter"
+func lookupDensity(ingredient string) (density, bool) {
+	name := strings.ToLower(ingredient)
+	var best density
+	bestLen := 0
+	for _, d := range densities {
+		for _, k := range d.keywords {
+			if len(k) > bestLen && containsWord(name, k) {
+				best, bestLen = d, len(k)
+			}
+		}
+	}
+	return best, bestLen > 0
+}
+
+// Density returns the weight in grams of one millilitre of ingredient
+func Density(ingredient string) (float64, bool) {
+	d, ok := lookupDensity(ingredient)
+	return d.gPerMl, ok
+}
+
+// containsWord reports whether s contains word as a whole word (a trailing
+// plural "s" is allowed), so "oil" doesn't match "boiling"
+func containsWord(s, word string) bool {
+	for from := 0; ; {
+		i := strings.Index(s[from:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := from+i, from+i+len(word)
+		if end < len(s) && s[end] == 's' {
+			end++
+		}
+		if (start == 0 || !isLetter(s[start-1])) && (end == len(s) || !isLetter(s[end])) {
+			return true
+		}
+		from = start + 1
+	}
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z'
+}
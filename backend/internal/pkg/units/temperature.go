@@ -0,0 +1,61 @@
+package units
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// temperaturePattern matches "180°C", "350 F", "180-200 degrees C", "375 fahrenheit"
+var temperaturePattern = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)(?:\s*(?:-|–|to)\s*(\d+(?:\.\d+)?))?\s*(?:°|º|˚|degrees?)?\s*(celsius|fahrenheit|c|f)\b`)
+
+// ConvertTemperature rewrites the temperatures in s (e.g. a RecipeStep.Temperature
+// such as "180°C (fan 160°C)") into the target system. Strings that already
+// mention a temperature in the target system, or none at all ("medium heat"),
+// are returned unchanged.
+func ConvertTemperature(s string, system System) string {
+	matches := temperaturePattern.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+	for _, m := range matches {
+		if temperatureUnit(m[3]).System == system {
+			return s
+		}
+	}
+
+	to := Celsius
+	if system == Imperial {
+		to = Fahrenheit
+	}
+	return temperaturePattern.ReplaceAllStringFunc(s, func(match string) string {
+		m := temperaturePattern.FindStringSubmatch(match)
+		from := temperatureUnit(m[3])
+		out := formatTemperature(m[1], from, to)
+		if m[2] != "" {
+			out += "-" + formatTemperature(m[2], from, to)
+		}
+		return out + to.Name
+	})
+}
+
+func temperatureUnit(s string) Unit {
+	if strings.HasPrefix(strings.ToLower(s), "f") {
+		return Fahrenheit
+	}
+	return Celsius
+}
+
+// formatTemperature converts a number and rounds it the way ovens are set:
+// to the nearest 5 degrees, or the nearest degree below 100 (sous vide, proofing)
+func formatTemperature(s string, from, to Unit) string {
+	v, _ := strconv.ParseFloat(s, 64)
+	c, _ := Convert(v, from, to)
+	if math.Abs(c) >= 100 {
+		c = math.Round(c/5) * 5
+	} else {
+		c = math.Round(c)
+	}
+	return strconv.FormatFloat(c, 'f', -1, 64)
+}
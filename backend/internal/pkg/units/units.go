@@ -0,0 +1,415 @@
+// Package units converts cooking quantities between units and between the
+// metric and imperial systems.
+//
+// Everything here is deterministic and table driven: a unit table covering
+// mass, volume, count and temperature, readable unit ladders (1500 g → 1.5 kg,
+// 9 tsp → 3 tbsp), kitchen-friendly rounding, and an ingredient density table
+// so "1 cup flour" can become "125 g flour" for a metric cook.
+package units
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Dimension is the physical quantity a unit measures
+type Dimension int
+
+const (
+	Mass Dimension = iota + 1
+	Volume
+	Count
+	Temperature
+)
+
+// System is a measurement system, matching User.PreferredUnitSystem
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+// ParseSystem parses "metric" or "imperial" (case-insensitive)
+func ParseSystem(s string) (System, bool) {
+	switch System(strings.ToLower(strings.TrimSpace(s))) {
+	case Metric:
+		return Metric, true
+	case Imperial:
+		return Imperial, true
+	}
+	return "", false
+}
+
+// ErrIncompatible is returned when converting between dimensions without a density
+var ErrIncompatible = errors.New("units measure different dimensions")
+
+// Unit is a canonical unit of measure
+type Unit struct {
+	Name      string
+	Dimension Dimension
+	// System is empty for counts, which read the same everywhere
+	System System
+	// Base is the unit's size in grams (mass), millilitres (volume) or items (count).
+	// Unused for temperature.
+	Base float64
+}
+
+// Label returns the name to print after qty ("cups" for more than one cup)
+func (u Unit) Label(qty float64) string {
+	if u.Name == "cup" && qty > 1 {
+		return "cups"
+	}
+	return u.Name
+}
+
+// Canonical units
+var (
+	Milligram  = Unit{"mg", Mass, Metric, 0.001}
+	Gram       = Unit{"g", Mass, Metric, 1}
+	Kilogram   = Unit{"kg", Mass, Metric, 1000}
+	Ounce      = Unit{"oz", Mass, Imperial, 28.349523125}
+	Pound      = Unit{"lb", Mass, Imperial, 453.59237}
+	Millilitre = Unit{"ml", Volume, Metric, 1}
+	Centilitre = Unit{"cl", Volume, Metric, 10}
+	Decilitre  = Unit{"dl", Volume, Metric, 100}
+	Litre      = Unit{"l", Volume, Metric, 1000}
+	Teaspoon   = Unit{"tsp", Volume, Imperial, 4.92892159375}
+	Tablespoon = Unit{"tbsp", Volume, Imperial, 14.78676478125}
+	FluidOunce = Unit{"fl oz", Volume, Imperial, 29.5735295625}
+	Cup        = Unit{"cup", Volume, Imperial, 236.5882365}
+	Pint       = Unit{"pint", Volume, Imperial, 473.176473}
+	Quart      = Unit{"quart", Volume, Imperial, 946.352946}
+	Gallon     = Unit{"gallon", Volume, Imperial, 3785.411784}
+	Piece      = Unit{"piece", Count, "", 1}
+	Dozen      = Unit{"dozen", Count, "", 12}
+	Celsius    = Unit{"°C", Temperature, Metric, 0}
+	Fahrenheit = Unit{"°F", Temperature, Imperial, 0}
+)
+
+// aliases maps lowercase spellings to canonical units
+var aliases = map[string]Unit{
+	"mg": Milligram, "milligram": Milligram, "milligrams": Milligram,
+	"g": Gram, "gr": Gram, "gram": Gram, "grams": Gram, "gramme": Gram, "grammes": Gram,
+	"kg": Kilogram, "kilo": Kilogram, "kilos": Kilogram, "kilogram": Kilogram, "kilograms": Kilogram,
+	"oz": Ounce, "ounce": Ounce, "ounces": Ounce,
+	"lb": Pound, "lbs": Pound, "pound": Pound, "pounds": Pound,
+	"ml": Millilitre, "milliliter": Millilitre, "milliliters": Millilitre, "millilitre": Millilitre, "millilitres": Millilitre,
+	"cl": Centilitre, "centiliter": Centilitre, "centiliters": Centilitre, "centilitre": Centilitre, "centilitres": Centilitre,
+	"dl": Decilitre, "deciliter": Decilitre, "deciliters": Decilitre, "decilitre": Decilitre, "decilitres": Decilitre,
+	"l": Litre, "liter": Litre, "liters": Litre, "litre": Litre, "litres": Litre,
+	"tsp": Teaspoon, "tsps": Teaspoon, "teaspoon": Teaspoon, "teaspoons": Teaspoon,
+	"tbsp": Tablespoon, "tbsps": Tablespoon, "tbs": Tablespoon, "tablespoon": Tablespoon, "tablespoons": Tablespoon,
+	"fl oz": FluidOunce, "fl. oz": FluidOunce, "fluid ounce": FluidOunce, "fluid ounces": FluidOunce,
+	"c": Cup, "cup": Cup, "cups": Cup,
+	"pt": Pint, "pint": Pint, "pints": Pint,
+	"qt": Quart, "quart": Quart, "quarts": Quart,
+	"gal": Gallon, "gallon": Gallon, "gallons": Gallon,
+	"piece": Piece, "pieces": Piece, "pc": Piece, "pcs": Piece, "dozen": Dozen,
+
+	// French
+	"cuillère à soupe": Tablespoon, "cuillères à soupe": Tablespoon, "cuillere a soupe": Tablespoon,
+	"c. à soupe": Tablespoon, "c.à.s": Tablespoon, "càs": Tablespoon,
+	"cuillère à café": Teaspoon, "cuillères à café": Teaspoon, "cuillere a cafe": Teaspoon,
+	"c. à café": Teaspoon, "c.à.c": Teaspoon, "càc": Teaspoon,
+	"tasse": Cup, "tasses": Cup,
+
+	// Spanish
+	"cucharada": Tablespoon, "cucharadas": Tablespoon, "cda": Tablespoon, "cdas": Tablespoon,
+	"cucharadita": Teaspoon, "cucharaditas": Teaspoon, "cdta": Teaspoon, "cdtas": Teaspoon, "cdita": Teaspoon,
+	"taza": Cup, "tazas": Cup, "gramo": Gram, "gramos": Gram, "kilogramo": Kilogram, "kilogramos": Kilogram,
+	"litro": Litre, "litros": Litre, "mililitro": Millilitre, "mililitros": Millilitre,
+	"onza": Ounce, "onzas": Ounce, "libra": Pound, "libras": Pound,
+
+	// Arabic
+	"ملعقة كبيرة": Tablespoon, "ملاعق كبيرة": Tablespoon, "ملعقة صغيرة": Teaspoon, "ملاعق صغيرة": Teaspoon,
+	"كوب": Cup, "أكواب": Cup, "غرام": Gram, "غ": Gram, "جرام": Gram, "جم": Gram, "غم": Gram,
+	"كيلو": Kilogram, "كيلوغرام": Kilogram, "كغ": Kilogram, "كجم": Kilogram,
+	"مل": Millilitre, "ملل": Millilitre, "مليلتر": Millilitre, "لتر": Litre,
+	"celsius": Celsius, "°c": Celsius, "c°": Celsius,
+	"fahrenheit": Fahrenheit, "°f": Fahrenheit, "f°": Fahrenheit,
+}
+
+// Lookup resolves a unit spelling ("Tablespoons", "kg.", "fl oz") to its canonical unit
+func Lookup(name string) (Unit, bool) {
+	key := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+	u, ok := aliases[key]
+	return u, ok
+}
+
+// Convert converts value between two units of the same dimension
+func Convert(value float64, from, to Unit) (float64, error) {
+	if from.Dimension != to.Dimension {
+		return 0, ErrIncompatible
+	}
+	if from.Dimension == Temperature {
+		return convertTemperature(value, from, to), nil
+	}
+	return value * from.Base / to.Base, nil
+}
+
+// ConvertIngredient is Convert that also crosses between mass and volume
+// using the ingredient's density, if the density table knows it.
+func ConvertIngredient(value float64, from, to Unit, ingredient string) (float64, error) {
+	if from.Dimension == to.Dimension {
+		return Convert(value, from, to)
+	}
+	d, ok := lookupDensity(ingredient)
+	if !ok {
+		return 0, ErrIncompatible
+	}
+	base := value * from.Base
+	switch {
+	case from.Dimension == Volume && to.Dimension == Mass:
+		return base * d.gPerMl / to.Base, nil
+	case from.Dimension == Mass && to.Dimension == Volume:
+		return base / d.gPerMl / to.Base, nil
+	}
+	return 0, ErrIncompatible
+}
+
+func convertTemperature(value float64, from, to Unit) float64 {
+	switch {
+	case from == Celsius && to == Fahrenheit:
+		return value*9/5 + 32
+	case from == Fahrenheit && to == Celsius:
+		return (value - 32) * 5 / 9
+	}
+	return value
+}
+
+// Quantity is an amount ready for display
+type Quantity struct {
+	Value   float64
+	Unit    string
+	Display string
+}
+
+// ToSystem renders value in the target system, picking a readable unit and
+// rounding to something measurable. Dry ingredients with a known density are
+// weighed in metric and measured by volume in imperial; liquids stay volumes.
+// ok is false when nothing changes: unknown or count units, or a quantity
+// already in the target system.
+func ToSystem(value float64, unit, ingredient string, system System) (Quantity, bool) {
+	from, ok := Lookup(unit)
+	if !ok || from.System == "" || from.System == system || from.Dimension == Temperature {
+		return Quantity{}, false
+	}
+
+	target := from.Dimension
+	if d, ok := lookupDensity(ingredient); ok {
+		switch {
+		case d.liquid:
+			target = Volume
+		case system == Metric:
+			target = Mass
+		default:
+			target = Volume
+		}
+	}
+
+	l, ok := ladders[ladderKey{target, system}]
+	if !ok {
+		return Quantity{}, false
+	}
+	to := l.steps[len(l.steps)-1].unit
+	converted, err := ConvertIngredient(value, from, to, ingredient)
+	if err != nil {
+		return Quantity{}, false
+	}
+
+	qty, u := l.normalize(converted * to.Base)
+	return Quantity{Value: qty, Unit: u.Label(qty), Display: Format(qty, u)}, true
+}
+
+// ladder lists the units worth printing for one dimension in one system
+type ladder struct {
+	// steps from largest to smallest
+	steps []ladderStep
+	// fractional rounds to kitchen fractions; otherwise metric-style rounding
+	fractional bool
+}
+
+type ladderStep struct {
+	unit Unit
+	// min is the smallest amount (in this unit) worth expressing in it
+	min float64
+}
+
+type ladderKey struct {
+	dimension Dimension
+	system    System
+}
+
+var ladders = map[ladderKey]ladder{
+	{Volume, Imperial}: {
+		steps:      []ladderStep{{Cup, 0.25}, {Tablespoon, 1}, {Teaspoon, 0}},
+		fractional: true,
+	},
+	{Mass, Imperial}: {
+		steps:      []ladderStep{{Pound, 1}, {Ounce, 0}},
+		fractional: true,
+	},
+	{Mass, Metric}: {
+		steps: []ladderStep{{Kilogram, 1}, {Gram, 0}},
+	},
+	{Volume, Metric}: {
+		steps: []ladderStep{{Litre, 1}, {Millilitre, 0}},
+	},
+}
+
+// maxUnitError is how far a rounded amount in a larger unit may drift from the
+// exact amount before a smaller unit is used instead
+const maxUnitError = 0.05
+
+// Normalize rounds value to a measurable amount and re-expresses it in the
+// unit of the same system that reads best (9 tsp → 3 tbsp, 1500 g → 1.5 kg).
+// Units without a ladder (counts, temperatures) are returned as is.
+func Normalize(value float64, u Unit) (float64, Unit) {
+	l, ok := ladders[ladderKey{u.Dimension, u.System}]
+	if !ok {
+		return value, u
+	}
+	return l.normalize(value * u.Base)
+}
+
+// Round rounds value to a measurable amount without changing its unit. Counts
+// and unknown units (u is the zero Unit) round like countable items.
+func Round(value float64, u Unit) float64 {
+	switch {
+	case u.Dimension == Count || u.Dimension == 0:
+		return RoundCount(value)
+	case u.System == Metric:
+		return roundMetric(value, u.Base > 1)
+	default:
+		return RoundFraction(value)
+	}
+}
+
+// normalize picks the largest step that expresses base (in g or ml) within
+// maxUnitError after rounding
+func (l ladder) normalize(base float64) (float64, Unit) {
+	for i, s := range l.steps {
+		inUnit := base / s.unit.Base
+		last := i == len(l.steps)-1
+		if inUnit < s.min && !last {
+			continue
+		}
+		var q float64
+		if l.fractional {
+			q = RoundFraction(inUnit)
+		} else {
+			q = roundMetric(inUnit, s.unit.Base > 1)
+		}
+		if !last && base > 0 && math.Abs(q*s.unit.Base-base)/base > maxUnitError {
+			continue
+		}
+		return q, s.unit
+	}
+	// Unreachable: the smallest step always accepts
+	return base, l.steps[len(l.steps)-1].unit
+}
+
+// Format renders an amount the way its system writes it: kitchen fractions
+// for imperial and counts ("1 1/2"), decimals for metric ("1.5")
+func Format(value float64, u Unit) string {
+	if u.System == Metric {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return FormatFraction(value)
+}
+
+// kitchenFractions are the fractional parts measuring cups and spoons cover
+var kitchenFractions = []float64{0, 1.0 / 8, 1.0 / 4, 1.0 / 3, 1.0 / 2, 2.0 / 3, 3.0 / 4, 1}
+
+// RoundFraction rounds to the nearest kitchen fraction, coarser for large amounts
+func RoundFraction(v float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 10 {
+		return math.Round(v*2) / 2
+	}
+	whole := math.Floor(v)
+	frac := v - whole
+	best := kitchenFractions[0]
+	for _, f := range kitchenFractions {
+		if math.Abs(f-frac) < math.Abs(best-frac) {
+			best = f
+		}
+	}
+	if whole == 0 && best == 0 {
+		best = 1.0 / 8 // never round a real amount away
+	}
+	return whole + best
+}
+
+// roundMetric rounds grams/millilitres to sensible steps, or kg/l to 0.05
+func roundMetric(v float64, large bool) float64 {
+	var step float64
+	switch {
+	case large:
+		step = 0.05
+	case v < 10:
+		step = 0.5
+	case v < 100:
+		step = 5
+	case v < 1000:
+		step = 10
+	default:
+		step = 50
+	}
+	q := math.Round(v/step) * step
+	if q == 0 && v > 0 {
+		q = step
+	}
+	return math.Round(q*100) / 100
+}
+
+// RoundCount rounds countable items (eggs, cloves, cans): halves below 5,
+// whole numbers above, and never below half an item
+func RoundCount(v float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 5 {
+		return math.Round(v)
+	}
+	q := math.Round(v*2) / 2
+	if q < 0.5 {
+		q = 0.5
+	}
+	return q
+}
+
+// FormatFraction renders 1.5 as "1 1/2" and 0.333 as "1/3"
+func FormatFraction(v float64) string {
+	whole := math.Floor(v)
+	frac := v - whole
+	var fracStr string
+	for _, f := range []struct {
+		value float64
+		text  string
+	}{
+		{1.0 / 8, "1/8"}, {1.0 / 4, "1/4"}, {1.0 / 3, "1/3"}, {1.0 / 2, "1/2"},
+		{2.0 / 3, "2/3"}, {3.0 / 4, "3/4"},
+	} {
+		if math.Abs(frac-f.value) < 0.01 {
+			fracStr = f.text
+			break
+		}
+	}
+
+	switch {
+	case frac < 0.01:
+		return strconv.FormatFloat(whole, 'f', -1, 64)
+	case fracStr == "":
+		return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+	case whole == 0:
+		return fracStr
+	default:
+		return strconv.FormatFloat(whole, 'f', -1, 64) + " " + fracStr
+	}
+}
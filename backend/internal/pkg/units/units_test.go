@@ -0,0 +1,153 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		want Unit
+	}{
+		{"Tablespoons", Tablespoon},
+		{"kg.", Kilogram},
+		{" fl oz ", FluidOunce},
+		{"c", Cup},
+		{"°F", Fahrenheit},
+		{"dozen", Dozen},
+	}
+	for _, tt := range tests {
+		if got, ok := Lookup(tt.name); !ok || got != tt.want {
+			t.Errorf("Lookup(%q) = %v, %v; want %v", tt.name, got, ok, tt.want)
+		}
+	}
+	if _, ok := Lookup("handful"); ok {
+		t.Error("Lookup(handful) should not resolve")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from, to Unit
+		want     float64
+	}{
+		{"cup to tbsp", 1, Cup, Tablespoon, 16},
+		{"lb to g", 1, Pound, Gram, 453.59237},
+		{"l to cups", 1, Litre, Cup, 4.2268},
+		{"dozen to pieces", 2, Dozen, Piece, 24},
+		{"boiling point", 100, Celsius, Fahrenheit, 212},
+		{"oven", 350, Fahrenheit, Celsius, 176.6667},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to)
+		if err != nil || math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("%s: got %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+
+	if _, err := Convert(1, Cup, Gram); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("cup to g without density: err = %v", err)
+	}
+	if got, err := ConvertIngredient(1, Cup, Gram, "all-purpose flour"); err != nil || math.Round(got) != 125 {
+		t.Errorf("1 cup flour = %v g, %v; want 125", got, err)
+	}
+	if _, err := ConvertIngredient(1, Cup, Gram, "spinach"); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("unknown density: err = %v", err)
+	}
+}
+
+func TestDensity(t *testing.T) {
+	tests := []struct {
+		ingredient string
+		want       float64
+	}{
+		{"light brown sugar", 0.93},
+		{"Sugar", 0.85},
+		{"unsalted butter", 0.96},
+		{"smooth peanut butter", 1.08},
+		{"buttermilk", 1.03},
+	}
+	for _, tt := range tests {
+		if got, ok := Density(tt.ingredient); !ok || got != tt.want {
+			t.Errorf("Density(%q) = %v, %v; want %v", tt.ingredient, got, ok, tt.want)
+		}
+	}
+	if _, ok := Density("boiling water"); !ok {
+		t.Error("boiling water should match water")
+	}
+	if _, ok := Density("chicken thighs"); ok {
+		t.Error("chicken should have no density")
+	}
+}
+
+func TestToSystem(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      float64
+		unit       string
+		ingredient string
+		system     System
+		want       Quantity
+	}{
+		{"flour is weighed in metric", 2, "cups", "flour", Metric, Quantity{250, "g", "250"}},
+		{"flour is measured in cups", 250, "g", "plain flour", Imperial, Quantity{2, "cups", "2"}},
+		{"milk stays a volume", 2, "cups", "whole milk", Metric, Quantity{470, "ml", "470"}},
+		{"milk by weight becomes a volume", 500, "g", "milk", Imperial, Quantity{2, "cups", "2"}},
+		{"meat stays a weight", 1, "kg", "chicken thighs", Imperial, Quantity{2.25, "lb", "2 1/4"}},
+		{"small volumes use spoons", 15, "ml", "vinegar", Imperial, Quantity{1, "tbsp", "1"}},
+		{"pounds to kilos", 3, "lb", "beef chuck", Metric, Quantity{1.35, "kg", "1.35"}},
+		{"butter in tbsp to grams", 2, "tbsp", "butter", Metric, Quantity{30, "g", "30"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ToSystem(tt.value, tt.unit, tt.ingredient, tt.system)
+			if !ok || got != tt.want {
+				t.Errorf("ToSystem(%v %s %s, %s) = %+v, %v; want %+v", tt.value, tt.unit, tt.ingredient, tt.system, got, ok, tt.want)
+			}
+		})
+	}
+
+	for _, unit := range []string{"", "cloves", "pinch", "g"} {
+		if got, ok := ToSystem(3, unit, "garlic", Metric); ok {
+			t.Errorf("ToSystem(3 %q, metric) converted to %+v", unit, got)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if q, u := Normalize(9, Teaspoon); q != 3 || u != Tablespoon {
+		t.Errorf("9 tsp = %v %s, want 3 tbsp", q, u.Name)
+	}
+	if q, u := Normalize(1500, Gram); q != 1.5 || u != Kilogram {
+		t.Errorf("1500 g = %v %s, want 1.5 kg", q, u.Name)
+	}
+	if q, u := Normalize(2, Pint); q != 4 || u != Cup {
+		t.Errorf("2 pints = %v %s, want 4 cups", q, u.Name)
+	}
+}
+
+func TestConvertTemperature(t *testing.T) {
+	tests := []struct {
+		in     string
+		system System
+		want   string
+	}{
+		{"180°C", Imperial, "355°F"},
+		{"350 F", Metric, "175°C"},
+		{"375 degrees Fahrenheit", Metric, "190°C"},
+		{"180-200°C (fan)", Imperial, "355-390°F (fan)"},
+		{"63°C", Imperial, "145°F"},
+		{"200°C / 400°F", Imperial, "200°C / 400°F"},
+		{"350°F", Imperial, "350°F"},
+		{"medium-high heat", Metric, "medium-high heat"},
+	}
+	for _, tt := range tests {
+		if got := ConvertTemperature(tt.in, tt.system); got != tt.want {
+			t.Errorf("ConvertTemperature(%q, %s) = %q, want %q", tt.in, tt.system, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// AnnotationRepository handles users' private notes on recipe ingredients
+// and steps
+type AnnotationRepository struct {
+	db *sql.DB
+}
+
+// NewAnnotationRepository creates a new annotation repository
+func NewAnnotationRepository(db *sql.DB) *AnnotationRepository {
+	return &AnnotationRepository{db: db}
+}
+
+const annotationColumns = `
+	id, user_id, recipe_id, target, target_id, anchor, position, note,
+	sync_version, created_at, updated_at, deleted_at
+`
+
+func scanAnnotation(row rowScanner) (*model.RecipeAnnotation, error) {
+	a := &model.RecipeAnnotation{}
+	err := row.Scan(
+		&a.ID, &a.UserID, &a.RecipeID, &a.Target, &a.TargetID, &a.Anchor, &a.Position, &a.Note,
+		&a.SyncVersion, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *AnnotationRepository) query(ctx context.Context, query string, args ...any) ([]model.RecipeAnnotation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	annotations := []model.RecipeAnnotation{}
+	for rows.Next() {
+		a, err := scanAnnotation(rows)
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, *a)
+	}
+	return annotations, rows.Err()
+}
+
+// ListByRecipe retrieves a user's notes on a recipe
+func (r *AnnotationRepository) ListByRecipe(ctx context.Context, recipeID, userID uuid.UUID) ([]model.RecipeAnnotation, error) {
+	return r.query(ctx, `
+		SELECT `+annotationColumns+`
+		FROM recipe_annotations
+		WHERE recipe_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		ORDER BY target, position, created_at
+	`, recipeID, userID)
+}
+
+// Get retrieves one of a user's notes, including deleted ones
+func (r *AnnotationRepository) Get(ctx context.Context, id, userID uuid.UUID) (*model.RecipeAnnotation, error) {
+	return scanAnnotation(r.db.QueryRowContext(ctx, `
+		SELECT `+annotationColumns+`
+		FROM recipe_annotations
+		WHERE id = $1 AND user_id = $2
+	`, id, userID))
+}
+
+// Set writes the user's note on an ingredient or step, replacing the note
+// already there
+func (r *AnnotationRepository) Set(ctx context.Context, a *model.RecipeAnnotation) (*model.RecipeAnnotation, error) {
+	return scanAnnotation(r.db.QueryRowContext(ctx, `
+		INSERT INTO recipe_annotations (user_id, recipe_id, target, target_id, anchor, position, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, recipe_id, target, target_id) WHERE deleted_at IS NULL
+		DO UPDATE SET
+			anchor = EXCLUDED.anchor, position = EXCLUDED.position, note = EXCLUDED.note,
+			sync_version = recipe_annotations.sync_version + 1
+		RETURNING `+annotationColumns,
+		a.UserID, a.RecipeID, a.Target, a.TargetID, a.Anchor, a.Position, a.Note))
+}
+
+// Delete soft deletes one of a user's notes on a recipe, so the deletion
+// syncs to their other devices
+func (r *AnnotationRepository) Delete(ctx context.Context, id, recipeID, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE recipe_annotations
+		SET deleted_at = NOW(), sync_version = sync_version + 1
+		WHERE id = $1 AND recipe_id = $2 AND user_id = $3 AND deleted_at IS NULL
+	`, id, recipeID, userID)
+	return expectRow(result, err)
+}
+
+// GetChangesSince retrieves a user's notes changed since a time, deleted
+// ones included
+func (r *AnnotationRepository) GetChangesSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]model.RecipeAnnotation, error) {
+	return r.query(ctx, `
+		SELECT `+annotationColumns+`
+		FROM recipe_annotations
+		WHERE user_id = $1 AND updated_at > $2
+		ORDER BY updated_at ASC
+	`, userID, since)
+}
+
+// Upsert writes a note as sent by a sync client, keeping the client's ID. A
+// live note replaces any other note on the same ingredient or step, the
+// latest write winning. It returns model.ErrNotFound when the ID belongs to
+// another user or the user can't see the recipe.
+func (r *AnnotationRepository) Upsert(ctx context.Context, userID uuid.UUID, a *model.RecipeAnnotation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if a.DeletedAt == nil {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE recipe_annotations
+			SET deleted_at = NOW(), sync_version = sync_version + 1
+			WHERE user_id = $1 AND recipe_id = $2 AND target = $3 AND target_id = $4
+			  AND id <> $5 AND deleted_at IS NULL
+		`, userID, a.RecipeID, a.Target, a.TargetID, a.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO recipe_annotations (id, user_id, recipe_id, target, target_id, anchor, position, note, deleted_at)
+		SELECT $1, $2, r.id, $4, $5, $6, $7, $8, $9
+		FROM recipes r
+		WHERE r.id = $3 AND r.deleted_at IS NULL
+		  AND (r.user_id = $2 OR r.is_public = TRUE OR r.is_featured = TRUE)
+		ON CONFLICT (id) DO UPDATE SET
+			target = EXCLUDED.target, target_id = EXCLUDED.target_id,
+			anchor = EXCLUDED.anchor, position = EXCLUDED.position,
+			note = EXCLUDED.note, deleted_at = EXCLUDED.deleted_at,
+			sync_version = recipe_annotations.sync_version + 1
+		WHERE recipe_annotations.user_id = EXCLUDED.user_id
+		  AND recipe_annotations.recipe_id = EXCLUDED.recipe_id
+	`, a.ID, userID, a.RecipeID, a.Target, a.TargetID, a.Anchor, a.Position, a.Note, a.DeletedAt)
+	if err := expectRow(result, err); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,443 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// CollectionRepository handles recipe collection data access
+type CollectionRepository struct {
+	db *sql.DB
+}
+
+// NewCollectionRepository creates a new collection repository
+func NewCollectionRepository(db *sql.DB) *CollectionRepository {
+	return &CollectionRepository{db: db}
+}
+
+// collectionColumns selects a collection with its resolved cover, recipe
+// count and ordered member IDs. The query must alias collections as c.
+const collectionColumns = `
+	c.id, c.user_id, c.name, c.description, c.cover_image_url, c.cover_recipe_id,
+	c.sort_order, c.sync_version, c.created_at, c.updated_at, c.deleted_at,
+	COALESCE(
+		c.cover_image_url,
+		(SELECT thumbnail_url FROM recipes WHERE id = c.cover_recipe_id AND deleted_at IS NULL),
+		(SELECT r.thumbnail_url FROM collection_recipes cr
+		 JOIN recipes r ON r.id = cr.recipe_id AND r.deleted_at IS NULL
+		 WHERE cr.collection_id = c.id AND r.thumbnail_url IS NOT NULL
+		 ORDER BY cr.sort_order, cr.added_at LIMIT 1)
+	) AS cover_url,
+	COALESCE((
+		SELECT array_agg(cr.recipe_id::text ORDER BY cr.sort_order, cr.added_at)
+		FROM collection_recipes cr
+		JOIN recipes r ON r.id = cr.recipe_id AND r.deleted_at IS NULL
+		WHERE cr.collection_id = c.id
+	), '{}') AS recipe_ids
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCollection(row rowScanner) (*model.Collection, error) {
+	c := &model.Collection{}
+	var recipeIDs TextArray
+	err := row.Scan(
+		&c.ID, &c.UserID, &c.Name, &c.Description, &c.CoverImageURL, &c.CoverRecipeID,
+		&c.SortOrder, &c.SyncVersion, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt,
+		&c.CoverURL, &recipeIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.RecipeIDs = make([]uuid.UUID, 0, len(recipeIDs))
+	for _, s := range recipeIDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		c.RecipeIDs = append(c.RecipeIDs, id)
+	}
+	c.RecipeCount = len(c.RecipeIDs)
+	return c, nil
+}
+
+func scanCollections(rows *sql.Rows) ([]*model.Collection, error) {
+	defer rows.Close()
+
+	collections := []*model.Collection{}
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, c)
+	}
+	return collections, rows.Err()
+}
+
+// List returns all collections for a user in their manual order
+func (r *CollectionRepository) List(ctx context.Context, userID uuid.UUID) ([]*model.Collection, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+collectionColumns+`
+		FROM collections c
+		WHERE c.user_id = $1 AND c.deleted_at IS NULL
+		ORDER BY c.sort_order, c.created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanCollections(rows)
+}
+
+// Get returns a single collection by ID
+func (r *CollectionRepository) Get(ctx context.Context, id, userID uuid.UUID) (*model.Collection, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+collectionColumns+`
+		FROM collections c
+		WHERE c.id = $1 AND c.user_id = $2 AND c.deleted_at IS NULL
+	`, id, userID)
+
+	c, err := scanCollection(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	return c, err
+}
+
+// Create creates a collection at the end of the user's list
+func (r *CollectionRepository) Create(ctx context.Context, userID uuid.UUID, input *model.CollectionInput) (*model.Collection, error) {
+	id := uuid.New()
+	coverRecipeID, err := r.ownedRecipeOrNil(ctx, userID, input.CoverRecipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO collections (id, user_id, name, description, cover_image_url, cover_recipe_id, sort_order)
+		VALUES ($1, $2, $3, $4, $5, $6,
+		        (SELECT COALESCE(MAX(sort_order) + 1, 0) FROM collections WHERE user_id = $2 AND deleted_at IS NULL))
+	`, id, userID, input.Name, input.Description, input.CoverImageURL, coverRecipeID)
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, id, userID)
+}
+
+// Update updates a collection's name, description and cover
+func (r *CollectionRepository) Update(ctx context.Context, id, userID uuid.UUID, input *model.CollectionInput) (*model.Collection, error) {
+	coverRecipeID, err := r.ownedRecipeOrNil(ctx, userID, input.CoverRecipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE collections
+		SET name = $3, description = $4, cover_image_url = $5, cover_recipe_id = $6,
+		    sync_version = sync_version + 1
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`, id, userID, input.Name, input.Description, input.CoverImageURL, coverRecipeID)
+	if err := expectRow(result, err); err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, id, userID)
+}
+
+// Delete soft deletes a collection. Its recipes are not affected.
+func (r *CollectionRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE collections
+		SET deleted_at = NOW(), sync_version = sync_version + 1
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`, id, userID)
+	return expectRow(result, err)
+}
+
+// Reorder sets the order of a user's collections. Collections not listed
+// keep their relative order after the listed ones.
+func (r *CollectionRepository) Reorder(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	var owned bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT NOT EXISTS (
+			SELECT unnest($1::uuid[])
+			EXCEPT
+			SELECT id FROM collections WHERE user_id = $2 AND deleted_at IS NULL
+		)
+	`, ids, userID).Scan(&owned)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return model.ErrNotFound
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		WITH given AS (
+			SELECT id, ord FROM unnest($2::uuid[]) WITH ORDINALITY AS t(id, ord)
+		), ranked AS (
+			SELECT c.id, ROW_NUMBER() OVER (ORDER BY g.ord NULLS LAST, c.sort_order, c.created_at) - 1 AS pos
+			FROM collections c
+			LEFT JOIN given g ON g.id = c.id
+			WHERE c.user_id = $1 AND c.deleted_at IS NULL
+		)
+		UPDATE collections c
+		SET sort_order = ranked.pos, sync_version = c.sync_version + 1
+		FROM ranked
+		WHERE c.id = ranked.id AND c.sort_order <> ranked.pos
+	`, userID, ids)
+	return err
+}
+
+// AddRecipes appends the user's recipes to a collection, skipping ones
+// already in it. Recipes the user doesn't own are rejected.
+func (r *CollectionRepository) AddRecipes(ctx context.Context, id, userID uuid.UUID, recipeIDs []uuid.UUID) (*model.Collection, error) {
+	if _, err := r.Get(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	var owned bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT NOT EXISTS (
+			SELECT unnest($1::uuid[])
+			EXCEPT
+			SELECT id FROM recipes WHERE user_id = $2 AND deleted_at IS NULL
+		)
+	`, recipeIDs, userID).Scan(&owned)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, ErrRecipeNotFound
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO collection_recipes (collection_id, recipe_id, sort_order)
+		SELECT $1, t.id,
+		       (SELECT COALESCE(MAX(sort_order) + 1, 0) FROM collection_recipes WHERE collection_id = $1) + t.ord - 1
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS t(id, ord)
+		ON CONFLICT (collection_id, recipe_id) DO NOTHING
+	`, id, recipeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := touchCollection(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, id, userID)
+}
+
+// RemoveRecipe removes a recipe from a collection
+func (r *CollectionRepository) RemoveRecipe(ctx context.Context, id, userID, recipeID uuid.UUID) error {
+	if _, err := r.Get(ctx, id, userID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM collection_recipes WHERE collection_id = $1 AND recipe_id = $2`, id, recipeID)
+	if err := expectRow(result, err); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return ErrRecipeNotFound
+		}
+		return err
+	}
+
+	if err := touchCollection(ctx, tx, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReorderRecipes sets the order of recipes within a collection. Recipes not
+// listed keep their relative order after the listed ones.
+func (r *CollectionRepository) ReorderRecipes(ctx context.Context, id, userID uuid.UUID, recipeIDs []uuid.UUID) (*model.Collection, error) {
+	if _, err := r.Get(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	var members bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT NOT EXISTS (
+			SELECT unnest($1::uuid[])
+			EXCEPT
+			SELECT recipe_id FROM collection_recipes WHERE collection_id = $2
+		)
+	`, recipeIDs, id).Scan(&members)
+	if err != nil {
+		return nil, err
+	}
+	if !members {
+		return nil, ErrRecipeNotFound
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := setRecipeOrder(ctx, tx, id, recipeIDs); err != nil {
+		return nil, err
+	}
+	if err := touchCollection(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, id, userID)
+}
+
+// GetChangesSince returns collections changed since the given time,
+// including deleted ones so clients can remove them
+func (r *CollectionRepository) GetChangesSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]model.Collection, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+collectionColumns+`
+		FROM collections c
+		WHERE c.user_id = $1 AND c.updated_at > $2
+		ORDER BY c.updated_at ASC
+	`, userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := scanCollections(rows)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]model.Collection, len(collections))
+	for i, c := range collections {
+		changes[i] = *c
+	}
+	return changes, nil
+}
+
+// Upsert writes a collection as sent by a sync client, keeping the client's
+// ID and replacing its membership. Recipes the user doesn't own are dropped.
+func (r *CollectionRepository) Upsert(ctx context.Context, userID uuid.UUID, c *model.Collection) error {
+	coverRecipeID, err := r.ownedRecipeOrNil(ctx, userID, c.CoverRecipeID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO collections (id, user_id, name, description, cover_image_url, cover_recipe_id, sort_order, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name, description = EXCLUDED.description,
+			cover_image_url = EXCLUDED.cover_image_url, cover_recipe_id = EXCLUDED.cover_recipe_id,
+			sort_order = EXCLUDED.sort_order, deleted_at = EXCLUDED.deleted_at,
+			sync_version = collections.sync_version + 1
+		WHERE collections.user_id = EXCLUDED.user_id
+	`, c.ID, userID, c.Name, c.Description, c.CoverImageURL, coverRecipeID, c.SortOrder, c.DeletedAt)
+	if err := expectRow(result, err); err != nil {
+		return err
+	}
+
+	if c.DeletedAt == nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM collection_recipes WHERE collection_id = $1`, c.ID); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO collection_recipes (collection_id, recipe_id, sort_order)
+			SELECT $1, t.id, t.ord - 1
+			FROM unnest($2::uuid[]) WITH ORDINALITY AS t(id, ord)
+			JOIN recipes r ON r.id = t.id AND r.user_id = $3 AND r.deleted_at IS NULL
+			ON CONFLICT (collection_id, recipe_id) DO NOTHING
+		`, c.ID, c.RecipeIDs, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ownedRecipeOrNil checks that a cover recipe belongs to the user
+func (r *CollectionRepository) ownedRecipeOrNil(ctx context.Context, userID uuid.UUID, recipeID *uuid.UUID) (*uuid.UUID, error) {
+	if recipeID == nil {
+		return nil, nil
+	}
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM recipes WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)`,
+		*recipeID, userID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrRecipeNotFound
+	}
+	return recipeID, nil
+}
+
+// setRecipeOrder renumbers a collection's recipes, listed ones first
+func setRecipeOrder(ctx context.Context, tx *sql.Tx, collectionID uuid.UUID, recipeIDs []uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+		WITH given AS (
+			SELECT id, ord FROM unnest($2::uuid[]) WITH ORDINALITY AS t(id, ord)
+		), ranked AS (
+			SELECT cr.recipe_id, ROW_NUMBER() OVER (ORDER BY g.ord NULLS LAST, cr.sort_order, cr.added_at) - 1 AS pos
+			FROM collection_recipes cr
+			LEFT JOIN given g ON g.id = cr.recipe_id
+			WHERE cr.collection_id = $1
+		)
+		UPDATE collection_recipes cr
+		SET sort_order = ranked.pos
+		FROM ranked
+		WHERE cr.collection_id = $1 AND cr.recipe_id = ranked.recipe_id
+	`, collectionID, recipeIDs)
+	return err
+}
+
+// touchCollection bumps a collection's version so membership changes sync
+func touchCollection(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE collections SET sync_version = sync_version + 1 WHERE id = $1`, id)
+	return err
+}
+
+// expectRow turns an update or delete that matched nothing into ErrNotFound
+func expectRow(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return model.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
+)
+
+// CookLogRepository handles the cook log, a record of each time a user
+// cooked one of their recipes
+type CookLogRepository struct {
+	db *sql.DB
+}
+
+// NewCookLogRepository creates a new cook log repository
+func NewCookLogRepository(db *sql.DB) *CookLogRepository {
+	return &CookLogRepository{db: db}
+}
+
+// cookLogColumns selects a cook log entry with its recipe's title and
+// thumbnail. The query must alias cook_logs as l.
+const cookLogColumns = `
+	l.id, l.recipe_id, l.user_id, l.cooked_on, l.servings, l.rating, l.notes,
+	l.photos, l.modifications, l.created_at, l.updated_at,
+	COALESCE((SELECT title FROM recipes WHERE id = l.recipe_id), ''),
+	(SELECT thumbnail_url FROM recipes WHERE id = l.recipe_id)
+`
+
+// cookLogKeyset orders cook logs most recently cooked first
+var cookLogKeyset = pagination.Keyset{Name: "cooks", Keys: []pagination.Key{
+	{SQL: "l.cooked_on", Type: "date", Desc: true},
+	{SQL: "l.created_at", Type: "timestamptz", Desc: true},
+	{SQL: "l.id", Type: "uuid", Desc: true},
+}}
+
+func scanCookLog(row rowScanner, extra ...any) (*model.CookLog, error) {
+	l := &model.CookLog{}
+	var photos, modifications TextArray
+	dest := append([]any{
+		&l.ID, &l.RecipeID, &l.UserID, &l.CookedOn, &l.Servings, &l.Rating, &l.Notes,
+		&photos, &modifications, &l.CreatedAt, &l.UpdatedAt,
+		&l.RecipeTitle, &l.RecipeThumbnailURL,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	l.Photos = []string(photos)
+	l.Modifications = []string(modifications)
+	return l, nil
+}
+
+// Get retrieves one of a user's cook log entries
+func (r *CookLogRepository) Get(ctx context.Context, id, userID uuid.UUID) (*model.CookLog, error) {
+	return scanCookLog(r.db.QueryRowContext(ctx, `
+		SELECT `+cookLogColumns+`
+		FROM cook_logs l
+		WHERE l.id = $1 AND l.user_id = $2
+	`, id, userID))
+}
+
+// Create logs a cook of a recipe. Photos in the input are ignored; they are
+// added with AddPhoto.
+func (r *CookLogRepository) Create(ctx context.Context, recipeID, userID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error) {
+	return scanCookLog(r.db.QueryRowContext(ctx, `
+		WITH l AS (
+			INSERT INTO cook_logs (recipe_id, user_id, cooked_on, servings, rating, notes, modifications)
+			VALUES ($1, $2, $3, $4, $5, $6, $7::text[])
+			RETURNING *
+		)
+		SELECT `+cookLogColumns+` FROM l
+	`, recipeID, userID, input.Date, input.Servings, input.Rating, input.Notes, input.Modifications))
+}
+
+// Update replaces a cook log entry's details. Photos are replaced only when
+// the input sets them.
+func (r *CookLogRepository) Update(ctx context.Context, id, userID uuid.UUID, input *model.CookLogInput) (*model.CookLog, error) {
+	var photos any
+	if input.Photos != nil {
+		photos = *input.Photos
+	}
+	return scanCookLog(r.db.QueryRowContext(ctx, `
+		WITH l AS (
+			UPDATE cook_logs
+			SET cooked_on = $3, servings = $4, rating = $5, notes = $6,
+				modifications = $7::text[], photos = COALESCE($8::text[], photos)
+			WHERE id = $1 AND user_id = $2
+			RETURNING *
+		)
+		SELECT `+cookLogColumns+` FROM l
+	`, id, userID, input.Date, input.Servings, input.Rating, input.Notes, input.Modifications, photos))
+}
+
+// AddPhoto appends a photo URL to a cook log entry. It returns
+// model.ErrNotFound if the entry is gone or already has the maximum number
+// of photos.
+func (r *CookLogRepository) AddPhoto(ctx context.Context, id, userID uuid.UUID, url string) (*model.CookLog, error) {
+	return scanCookLog(r.db.QueryRowContext(ctx, `
+		WITH l AS (
+			UPDATE cook_logs SET photos = array_append(photos, $3)
+			WHERE id = $1 AND user_id = $2 AND cardinality(photos) < $4
+			RETURNING *
+		)
+		SELECT `+cookLogColumns+` FROM l
+	`, id, userID, url, model.MaxCookLogPhotos))
+}
+
+// Delete removes a cook log entry
+func (r *CookLogRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM cook_logs WHERE id = $1 AND user_id = $2`, id, userID)
+	return expectRow(result, err)
+}
+
+// ListByRecipe retrieves a page of a recipe's cook log, most recently
+// cooked first, with the cursor for the next page
+func (r *CookLogRepository) ListByRecipe(ctx context.Context, recipeID, userID uuid.UUID, page pagination.Page) ([]*model.CookLog, string, error) {
+	return r.list(ctx, `l.recipe_id = $1 AND l.user_id = $2`, page, recipeID, userID)
+}
+
+// ListByUser retrieves a page of a user's cook log across their recipes,
+// most recently cooked first, with the cursor for the next page
+func (r *CookLogRepository) ListByUser(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*model.CookLog, string, error) {
+	return r.list(ctx, `l.user_id = $1`, page, userID)
+}
+
+func (r *CookLogRepository) list(ctx context.Context, where string, page pagination.Page, args ...any) ([]*model.CookLog, string, error) {
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if page.Cursor != "" {
+		position, err := cookLogKeyset.Decode(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		where += ` AND ` + cookLogKeyset.After(position, arg)
+	}
+
+	limit := page.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	// Fetch one extra row to know whether another page exists
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+cookLogColumns+`, `+cookLogKeyset.Columns()+`
+		FROM cook_logs l
+		WHERE `+where+`
+		ORDER BY `+cookLogKeyset.OrderBy()+`
+		LIMIT `+arg(limit+1)+page.Skip(arg), args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	logs := []*model.CookLog{}
+	var keys [][]string
+	for rows.Next() {
+		key := make([]string, len(cookLogKeyset.Keys))
+		extra := make([]any, len(key))
+		for i := range key {
+			extra[i] = &key[i]
+		}
+		l, err := scanCookLog(rows, extra...)
+		if err != nil {
+			return nil, "", err
+		}
+		logs = append(logs, l)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	logs, next := pagination.Next(cookLogKeyset, logs, keys, limit)
+	return logs, next, nil
+}
@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// ErrCookSessionActive is returned when starting a recipe that already has
+// a cook session in progress
+var ErrCookSessionActive = errors.New("recipe already has a cook session in progress")
+
+// CookSessionRepository handles cook sessions, the recipes users are
+// cooking right now. Updates are single statements on the session row, so
+// devices changing the same session don't overwrite each other.
+type CookSessionRepository struct {
+	db *sql.DB
+}
+
+// NewCookSessionRepository creates a new cook session repository
+func NewCookSessionRepository(db *sql.DB) *CookSessionRepository {
+	return &CookSessionRepository{db: db}
+}
+
+// cookSessionColumns selects a cook session with its recipe's title and
+// thumbnail. The query must alias cook_sessions as s.
+const cookSessionColumns = `
+	s.id, s.recipe_id, s.user_id, s.servings, s.current_step, s.timers, s.cook_log_id,
+	s.started_at, s.updated_at, s.finished_at,
+	COALESCE((SELECT title FROM recipes WHERE id = s.recipe_id), ''),
+	(SELECT thumbnail_url FROM recipes WHERE id = s.recipe_id)
+`
+
+func scanCookSession(row rowScanner) (*model.CookSession, error) {
+	s := &model.CookSession{}
+	var timers []byte
+	err := row.Scan(
+		&s.ID, &s.RecipeID, &s.UserID, &s.Servings, &s.CurrentStep, &timers, &s.CookLogID,
+		&s.StartedAt, &s.UpdatedAt, &s.FinishedAt, &s.RecipeTitle, &s.RecipeThumbnailURL,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(timers, &s.Timers); err != nil {
+		return nil, err
+	}
+	if s.Timers == nil {
+		s.Timers = []model.CookTimer{}
+	}
+	return s, nil
+}
+
+// Create starts a cook session. It returns ErrCookSessionActive if the
+// recipe is already being cooked.
+func (r *CookSessionRepository) Create(ctx context.Context, recipeID, userID uuid.UUID, servings *int, step int) (*model.CookSession, error) {
+	s, err := scanCookSession(r.db.QueryRowContext(ctx, `
+		WITH s AS (
+			INSERT INTO cook_sessions (recipe_id, user_id, servings, current_step)
+			VALUES ($1, $2, $3, $4)
+			RETURNING *
+		)
+		SELECT `+cookSessionColumns+` FROM s
+	`, recipeID, userID, servings, step))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return nil, ErrCookSessionActive
+	}
+	return s, err
+}
+
+// Get retrieves one of a user's cook sessions, finished or not
+func (r *CookSessionRepository) Get(ctx context.Context, id, userID uuid.UUID) (*model.CookSession, error) {
+	return scanCookSession(r.db.QueryRowContext(ctx, `
+		SELECT `+cookSessionColumns+`
+		FROM cook_sessions s
+		WHERE s.id = $1 AND s.user_id = $2
+	`, id, userID))
+}
+
+// ListActive retrieves the user's cook sessions in progress on recipes
+// still in their library, most recently touched first
+func (r *CookSessionRepository) ListActive(ctx context.Context, userID uuid.UUID) ([]*model.CookSession, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+cookSessionColumns+`
+		FROM cook_sessions s
+		WHERE s.user_id = $1 AND s.finished_at IS NULL
+			AND EXISTS (SELECT 1 FROM recipes WHERE id = s.recipe_id AND deleted_at IS NULL)
+		ORDER BY s.updated_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*model.CookSession{}
+	for rows.Next() {
+		s, err := scanCookSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// SetStep moves a session in progress to another step. It returns
+// model.ErrNotFound if the session is gone or finished.
+func (r *CookSessionRepository) SetStep(ctx context.Context, id, userID uuid.UUID, step int) (*model.CookSession, error) {
+	return scanCookSession(r.db.QueryRowContext(ctx, `
+		WITH s AS (
+			UPDATE cook_sessions SET current_step = $3
+			WHERE id = $1 AND user_id = $2 AND finished_at IS NULL
+			RETURNING *
+		)
+		SELECT `+cookSessionColumns+` FROM s
+	`, id, userID, step))
+}
+
+// AddTimer appends a timer to a session in progress. It returns
+// model.ErrNotFound if the session is gone, finished or already has the
+// maximum number of timers.
+func (r *CookSessionRepository) AddTimer(ctx context.Context, id, userID uuid.UUID, timer model.CookTimer) (*model.CookSession, error) {
+	timer.RemainingSeconds = 0
+	data, err := json.Marshal(timer)
+	if err != nil {
+		return nil, err
+	}
+	return scanCookSession(r.db.QueryRowContext(ctx, `
+		WITH s AS (
+			UPDATE cook_sessions SET timers = timers || jsonb_build_array($3::jsonb)
+			WHERE id = $1 AND user_id = $2 AND finished_at IS NULL
+				AND jsonb_array_length(timers) < $4
+			RETURNING *
+		)
+		SELECT `+cookSessionColumns+` FROM s
+	`, id, userID, data, model.MaxCookTimers))
+}
+
+// RemoveTimer stops a timer in a session in progress. It returns
+// model.ErrNotFound if the session is gone or finished or has no such
+// timer.
+func (r *CookSessionRepository) RemoveTimer(ctx context.Context, id, userID, timerID uuid.UUID) (*model.CookSession, error) {
+	return scanCookSession(r.db.QueryRowContext(ctx, `
+		WITH s AS (
+			UPDATE cook_sessions
+			SET timers = COALESCE(
+				(SELECT jsonb_agg(t ORDER BY i) FROM jsonb_array_elements(timers) WITH ORDINALITY AS x(t, i)
+				 WHERE t->>'id' <> $3::text),
+				'[]'::jsonb)
+			WHERE id = $1 AND user_id = $2 AND finished_at IS NULL
+				AND timers @> jsonb_build_array(jsonb_build_object('id', $3::text))
+			RETURNING *
+		)
+		SELECT `+cookSessionColumns+` FROM s
+	`, id, userID, timerID))
+}
+
+// Finish ends a session in progress and logs the cook, together, so a
+// session finished from two devices at once is logged once. Running timers
+// are cleared. It returns model.ErrNotFound if the session is gone or
+// already finished.
+func (r *CookSessionRepository) Finish(ctx context.Context, id, userID uuid.UUID, log *model.CookLogInput) (*model.CookSession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var recipeID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		UPDATE cook_sessions SET finished_at = NOW(), timers = '[]'
+		WHERE id = $1 AND user_id = $2 AND finished_at IS NULL
+		RETURNING recipe_id
+	`, id, userID).Scan(&recipeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var logID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO cook_logs (recipe_id, user_id, cooked_on, servings, rating, notes, modifications)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::text[])
+		RETURNING id
+	`, recipeID, userID, log.Date, log.Servings, log.Rating, log.Notes, log.Modifications).Scan(&logID)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := scanCookSession(tx.QueryRowContext(ctx, `
+		WITH s AS (
+			UPDATE cook_sessions SET cook_log_id = $2
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT `+cookSessionColumns+` FROM s
+	`, id, logID))
+	if err != nil {
+		return nil, err
+	}
+	return s, tx.Commit()
+}
+
+// Delete abandons a cook session without logging a cook
+func (r *CookSessionRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM cook_sessions WHERE id = $1 AND user_id = $2`, id, userID)
+	return expectRow(result, err)
+}
@@ -0,0 +1,361 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// DuplicateRepository handles suggested duplicate recipes and merging them
+type DuplicateRepository struct {
+	db      *sql.DB
+	recipes *RecipeRepository
+}
+
+// NewDuplicateRepository creates a new duplicate repository. A merge that
+// changes the kept recipe records a revision of it through recipes.
+func NewDuplicateRepository(db *sql.DB, recipes *RecipeRepository) *DuplicateRepository {
+	return &DuplicateRepository{db: db, recipes: recipes}
+}
+
+// ListUsersToScan retrieves users whose recipes changed since their library
+// was last analyzed for duplicates, or that were never analyzed
+func (r *DuplicateRepository) ListUsersToScan(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.user_id
+		FROM recipes r
+		LEFT JOIN recipe_duplicate_scans s ON s.user_id = r.user_id
+		GROUP BY r.user_id, s.scanned_at
+		HAVING s.scanned_at IS NULL OR MAX(r.updated_at) > s.scanned_at
+		ORDER BY s.scanned_at NULLS FIRST
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, rows.Err()
+}
+
+// ListCandidates retrieves a user's recipes with their ingredient match keys
+// for the duplicate analysis
+func (r *DuplicateRepository) ListCandidates(ctx context.Context, userID uuid.UUID) ([]model.DuplicateCandidate, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.id, r.title,
+		       COALESCE(array_agg(DISTINCT i.match_name) FILTER (WHERE i.match_name <> ''), '{}')
+		FROM recipes r
+		LEFT JOIN recipe_ingredients i ON i.recipe_id = r.id
+		WHERE r.user_id = $1 AND r.deleted_at IS NULL
+		GROUP BY r.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []model.DuplicateCandidate
+	for rows.Next() {
+		var c model.DuplicateCandidate
+		var ingredients TextArray
+		if err := rows.Scan(&c.RecipeID, &c.Title, &ingredients); err != nil {
+			return nil, err
+		}
+		c.Ingredients = []string(ingredients)
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// SaveGroups replaces a user's open duplicate suggestions with the groups
+// from a new analysis. Groups the user already dismissed are not added
+// back.
+func (r *DuplicateRepository) SaveGroups(ctx context.Context, userID uuid.UUID, groups []model.DuplicateGroup, scannedAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM recipe_duplicate_groups WHERE user_id = $1 AND dismissed_at IS NULL
+	`, userID); err != nil {
+		return err
+	}
+
+	for _, g := range groups {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO recipe_duplicate_groups (user_id, recipe_ids, score)
+			VALUES ($1, $2::uuid[], $3)
+			ON CONFLICT (user_id, recipe_ids) DO NOTHING
+		`, userID, g.RecipeIDs, g.Score); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO recipe_duplicate_scans (user_id, scanned_at)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET scanned_at = EXCLUDED.scanned_at
+	`, userID, scannedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListGroups retrieves a user's open duplicate suggestions, best first, with
+// their recipes. Recipes deleted since the analysis are left out, and so
+// are groups with fewer than two recipes left.
+func (r *DuplicateRepository) ListGroups(ctx context.Context, userID uuid.UUID) ([]*model.DuplicateGroup, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, recipe_ids, score, created_at
+		FROM recipe_duplicate_groups
+		WHERE user_id = $1 AND dismissed_at IS NULL
+		ORDER BY score DESC, created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []*model.DuplicateGroup
+	var recipeIDs []uuid.UUID
+	for rows.Next() {
+		g := &model.DuplicateGroup{}
+		var ids TextArray
+		if err := rows.Scan(&g.ID, &g.UserID, &ids, &g.Score, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		for _, s := range ids {
+			id, err := uuid.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			g.RecipeIDs = append(g.RecipeIDs, id)
+		}
+		recipeIDs = append(recipeIDs, g.RecipeIDs...)
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return []*model.DuplicateGroup{}, nil
+	}
+
+	recipeRows, err := r.db.QueryContext(ctx, `
+		SELECT `+recipeSummaryColumns+`
+		FROM recipes r
+		WHERE r.user_id = $1 AND r.id = ANY($2::uuid[]) AND r.deleted_at IS NULL
+		ORDER BY r.created_at, r.id
+	`, userID, recipeIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer recipeRows.Close()
+
+	recipes := make(map[uuid.UUID]*model.Recipe)
+	var order []uuid.UUID
+	for recipeRows.Next() {
+		recipe, err := scanRecipeSummary(recipeRows)
+		if err != nil {
+			return nil, err
+		}
+		recipes[recipe.ID] = recipe
+		order = append(order, recipe.ID)
+	}
+	if err := recipeRows.Err(); err != nil {
+		return nil, err
+	}
+
+	live := make([]*model.DuplicateGroup, 0, len(groups))
+	for _, g := range groups {
+		members := make(map[uuid.UUID]bool, len(g.RecipeIDs))
+		for _, id := range g.RecipeIDs {
+			members[id] = true
+		}
+		for _, id := range order {
+			if members[id] {
+				g.Recipes = append(g.Recipes, recipes[id])
+			}
+		}
+		if len(g.Recipes) >= 2 {
+			live = append(live, g)
+		}
+	}
+	return live, nil
+}
+
+// Dismiss hides one of a user's duplicate suggestions for good
+func (r *DuplicateRepository) Dismiss(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE recipe_duplicate_groups
+		SET dismissed_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND dismissed_at IS NULL
+	`, id, userID)
+	return expectRow(result, err)
+}
+
+// Merge folds a user's duplicate recipes into the one they keep: the kept
+// recipe becomes a favorite if any duplicate was, and the duplicates' meal
+// plan entries, cook logs and collection memberships move to it. The
+// duplicates are then soft deleted. It returns ErrRecipeNotFound unless the
+// user owns all the recipes.
+func (r *DuplicateRepository) Merge(ctx context.Context, userID, keepID uuid.UUID, mergeIDs []uuid.UUID) (*model.RecipeMergeResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the recipes so they can't be edited or deleted mid-merge
+	var owned int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT id FROM recipes
+			WHERE user_id = $1 AND id = ANY($2::uuid[]) AND deleted_at IS NULL
+			FOR UPDATE
+		) locked
+	`, userID, append([]uuid.UUID{keepID}, mergeIDs...)).Scan(&owned)
+	if err != nil {
+		return nil, err
+	}
+	if owned != len(mergeIDs)+1 {
+		return nil, ErrRecipeNotFound
+	}
+
+	result := &model.RecipeMergeResult{Merged: len(mergeIDs)}
+
+	// The kept recipe becomes a favorite if any duplicate was one, which is
+	// an edit like any other and goes into its history
+	if err := r.recipes.ensureBaselineRevisionsTx(ctx, tx, []uuid.UUID{keepID}); err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE recipes
+		SET is_favorite = TRUE, sync_version = sync_version + 1
+		WHERE id = $1 AND NOT is_favorite
+		  AND EXISTS (SELECT 1 FROM recipes WHERE id = ANY($2::uuid[]) AND is_favorite)
+		RETURNING id
+	`, keepID, mergeIDs)
+	if err != nil {
+		return nil, err
+	}
+	favorited, err := scanIDs(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.recipes.recordRevisionsTx(ctx, tx, favorited); err != nil {
+		return nil, err
+	}
+
+	// A meal slot holds a recipe once, so entries whose slot already has
+	// the kept recipe (or another duplicate) are dropped instead of moved
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM meal_plan_entries e
+		WHERE e.recipe_id = ANY($2::uuid[])
+		  AND EXISTS (
+			SELECT 1 FROM meal_plan_entries o
+			WHERE o.plan_id = e.plan_id AND o.day_index = e.day_index AND o.meal_type = e.meal_type
+			  AND (o.recipe_id = $1 OR (o.recipe_id = ANY($2::uuid[]) AND o.id < e.id))
+		  )
+	`, keepID, mergeIDs); err != nil {
+		return nil, err
+	}
+	moved, err := tx.ExecContext(ctx, `
+		UPDATE meal_plan_entries
+		SET recipe_id = $1
+		WHERE recipe_id = ANY($2::uuid[])
+		  AND plan_id IN (SELECT id FROM meal_plans WHERE user_id = $3)
+	`, keepID, mergeIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	if result.MovedMealPlanEntries, err = rowsAffected(moved); err != nil {
+		return nil, err
+	}
+
+	moved, err = tx.ExecContext(ctx, `
+		UPDATE cook_logs SET recipe_id = $1 WHERE recipe_id = ANY($2::uuid[]) AND user_id = $3
+	`, keepID, mergeIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	if result.MovedCookLogs, err = rowsAffected(moved); err != nil {
+		return nil, err
+	}
+
+	// Bump the affected collections so the membership change syncs
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE collections
+		SET sync_version = sync_version + 1,
+		    cover_recipe_id = CASE WHEN cover_recipe_id = ANY($2::uuid[]) THEN $1 ELSE cover_recipe_id END
+		WHERE id IN (SELECT collection_id FROM collection_recipes WHERE recipe_id = ANY($2::uuid[]))
+	`, keepID, mergeIDs); err != nil {
+		return nil, err
+	}
+	moved, err = tx.ExecContext(ctx, `
+		INSERT INTO collection_recipes (collection_id, recipe_id, sort_order, added_at)
+		SELECT DISTINCT ON (collection_id) collection_id, $1, sort_order, added_at
+		FROM collection_recipes
+		WHERE recipe_id = ANY($2::uuid[])
+		ORDER BY collection_id, sort_order
+		ON CONFLICT (collection_id, recipe_id) DO NOTHING
+	`, keepID, mergeIDs)
+	if err != nil {
+		return nil, err
+	}
+	if result.MovedCollections, err = rowsAffected(moved); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM collection_recipes WHERE recipe_id = ANY($1::uuid[])
+	`, mergeIDs); err != nil {
+		return nil, err
+	}
+
+	// Soft delete the duplicates as SoftDelete does, dropping any meal plan
+	// entries left in other users' plans
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE recipes SET deleted_at = $2, updated_at = $2 WHERE id = ANY($1::uuid[])
+	`, mergeIDs, now); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM meal_plan_entries WHERE recipe_id = ANY($1::uuid[])
+	`, mergeIDs); err != nil {
+		return nil, err
+	}
+
+	// The merge settles any open suggestion involving the duplicates
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM recipe_duplicate_groups
+		WHERE user_id = $1 AND dismissed_at IS NULL AND recipe_ids && $2::uuid[]
+	`, userID, mergeIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func rowsAffected(result sql.Result) (int, error) {
+	n, err := result.RowsAffected()
+	return int(n), err
+}
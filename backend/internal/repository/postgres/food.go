@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// minFoodSimilarity is how closely a food's name must match an ingredient
+// name, as pg_trgm strict word similarity, to be used for its nutrition
+const minFoodSimilarity = 0.6
+
+// FoodRepository handles the USDA food composition data
+type FoodRepository struct {
+	db *sql.DB
+}
+
+// NewFoodRepository creates a new food repository
+func NewFoodRepository(db *sql.DB) *FoodRepository {
+	return &FoodRepository{db: db}
+}
+
+// Match finds the food best matching an ingredient's nutrition match name.
+// Foods whose whole name appears in the ingredient's ("egg" in "large egg")
+// rank first, then the closest names overall, so "red bell pepper" gets the
+// bell pepper rather than black pepper. Raw foods and the lab-analyzed
+// datasets win ties.
+func (r *FoodRepository) Match(ctx context.Context, name string) (*model.Food, error) {
+	if name == "" {
+		return nil, model.ErrNotFound
+	}
+
+	food := &model.Food{}
+	n := &food.Per100g
+	err := r.db.QueryRowContext(ctx, `
+		SELECT fdc_id, description, data_type,
+		       calories, protein, carbs, fat, saturated_fat, fiber, sugar,
+		       sodium, cholesterol, calcium, iron, potassium, vitamin_c
+		FROM foods
+		WHERE match_name = $1 OR strict_word_similarity(match_name, $1) >= $2
+		ORDER BY match_name = $1 DESC,
+		         strict_word_similarity(match_name, $1) DESC,
+		         similarity(match_name, $1) DESC,
+		         description ILIKE '%, raw%' DESC,
+		         CASE data_type WHEN 'foundation_food' THEN 0 WHEN 'sr_legacy_food' THEN 1 ELSE 2 END,
+		         length(description)
+		LIMIT 1
+	`, name, minFoodSimilarity).Scan(
+		&food.FDCID, &food.Description, &food.DataType,
+		&n.Calories, &n.Protein, &n.Carbs, &n.Fat, &n.SaturatedFat, &n.Fiber, &n.Sugar,
+		&n.Sodium, &n.Cholesterol, &n.Calcium, &n.Iron, &n.Potassium, &n.VitaminC,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT amount, unit, modifier, gram_weight
+		FROM food_portions
+		WHERE fdc_id = $1
+		ORDER BY id
+	`, food.FDCID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p model.FoodPortion
+		if err := rows.Scan(&p.Amount, &p.Unit, &p.Modifier, &p.GramWeight); err != nil {
+			return nil, err
+		}
+		food.Portions = append(food.Portions, p)
+	}
+	return food, rows.Err()
+}
+
+// Import inserts or replaces foods and their portions in one transaction.
+// matchName gives each food's match name.
+func (r *FoodRepository) Import(ctx context.Context, foods []*model.Food, matchName func(description string) string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	upsertFood, err := tx.PrepareContext(ctx, `
+		INSERT INTO foods (fdc_id, description, data_type, match_name,
+		                   calories, protein, carbs, fat, saturated_fat, fiber, sugar,
+		                   sodium, cholesterol, calcium, iron, potassium, vitamin_c, imported_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, NOW())
+		ON CONFLICT (fdc_id) DO UPDATE SET
+			description = EXCLUDED.description, data_type = EXCLUDED.data_type,
+			match_name = EXCLUDED.match_name, calories = EXCLUDED.calories,
+			protein = EXCLUDED.protein, carbs = EXCLUDED.carbs, fat = EXCLUDED.fat,
+			saturated_fat = EXCLUDED.saturated_fat, fiber = EXCLUDED.fiber,
+			sugar = EXCLUDED.sugar, sodium = EXCLUDED.sodium,
+			cholesterol = EXCLUDED.cholesterol, calcium = EXCLUDED.calcium,
+			iron = EXCLUDED.iron, potassium = EXCLUDED.potassium,
+			vitamin_c = EXCLUDED.vitamin_c, imported_at = NOW()
+	`)
+	if err != nil {
+		return err
+	}
+	defer upsertFood.Close()
+
+	deletePortions, err := tx.PrepareContext(ctx, `DELETE FROM food_portions WHERE fdc_id = $1`)
+	if err != nil {
+		return err
+	}
+	defer deletePortions.Close()
+
+	insertPortion, err := tx.PrepareContext(ctx, `
+		INSERT INTO food_portions (fdc_id, amount, unit, modifier, gram_weight)
+		VALUES ($1, $2, $3, $4, $5)
+	`)
+	if err != nil {
+		return err
+	}
+	defer insertPortion.Close()
+
+	for _, f := range foods {
+		n := f.Per100g
+		if _, err := upsertFood.ExecContext(ctx,
+			f.FDCID, f.Description, f.DataType, matchName(f.Description),
+			n.Calories, n.Protein, n.Carbs, n.Fat, n.SaturatedFat, n.Fiber, n.Sugar,
+			n.Sodium, n.Cholesterol, n.Calcium, n.Iron, n.Potassium, n.VitaminC,
+		); err != nil {
+			return err
+		}
+		if _, err := deletePortions.ExecContext(ctx, f.FDCID); err != nil {
+			return err
+		}
+		for _, p := range f.Portions {
+			if _, err := insertPortion.ExecContext(ctx, f.FDCID, p.Amount, p.Unit, p.Modifier, p.GramWeight); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
@@ -3,17 +3,25 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
 )
 
-var (
-	ErrJobNotFound = errors.New("job not found")
-)
+var ErrJobNotFound = errors.New("job not found")
+
+// jobKeyset orders job history newest first
+var jobKeyset = pagination.Keyset{Name: "jobs", Keys: []pagination.Key{
+	{SQL: "j.created_at", Type: "timestamptz", Desc: true},
+	{SQL: "j.id", Type: "uuid", Desc: true},
+}}
 
 // JobRepository handles video job database operations
 type JobRepository struct {
@@ -31,10 +39,18 @@ func (r *JobRepository) Create(ctx context.Context, job *model.ExtractionJob) er
 		INSERT INTO video_jobs (
 			id, user_id, job_type, source_url, source_path, mime_type,
 			language, detail_level, save_auto, status,
-			progress, status_message, idempotency_key, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			progress, status_message, idempotency_key, created_at, import_result
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
+	var importJSON []byte
+	if job.ImportResult != nil {
+		var err error
+		if importJSON, err = json.Marshal(job.ImportResult); err != nil {
+			return err
+		}
+	}
+
 	_, err := r.db.ExecContext(ctx, query,
 		job.ID,
 		job.UserID,
@@ -50,6 +66,7 @@ func (r *JobRepository) Create(ctx context.Context, job *model.ExtractionJob) er
 		job.StatusMessage,
 		job.IdempotencyKey,
 		job.CreatedAt,
+		importJSON,
 	)
 
 	return err
@@ -61,12 +78,14 @@ func (r *JobRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Extra
 		SELECT id, user_id, COALESCE(job_type, 'video'), source_url, source_path, mime_type,
 			   language, detail_level, COALESCE(save_auto, true), status,
 			   progress, status_message, result_recipe_id, error_code,
-			   error_message, idempotency_key, started_at, completed_at, created_at
+			   error_message, idempotency_key, started_at, completed_at, created_at,
+			   partial_recipe, import_result
 		FROM video_jobs
 		WHERE id = $1
 	`
 
 	job := &model.ExtractionJob{}
+	var partialJSON, importJSON []byte
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&job.ID,
 		&job.UserID,
@@ -87,6 +106,8 @@ func (r *JobRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Extra
 		&job.StartedAt,
 		&job.CompletedAt,
 		&job.CreatedAt,
+		&partialJSON,
+		&importJSON,
 	)
 
 	if err != nil {
@@ -96,6 +117,15 @@ func (r *JobRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Extra
 		return nil, err
 	}
 
+	if partialJSON != nil {
+		job.PartialRecipe = &model.PartialRecipe{}
+		unmarshalJSONB(partialJSON, job.PartialRecipe, "partial_recipe")
+	}
+	if importJSON != nil {
+		job.ImportResult = &model.ImportResult{}
+		unmarshalJSONB(importJSON, job.ImportResult, "import_result")
+	}
+
 	return job, nil
 }
 
@@ -143,28 +173,55 @@ func (r *JobRepository) GetByIdempotencyKey(ctx context.Context, userID uuid.UUI
 	return job, nil
 }
 
-// ListByUser retrieves all jobs for a user
-func (r *JobRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.ExtractionJob, error) {
-	query := `
-		SELECT id, user_id, COALESCE(job_type, 'video'), source_url, source_path, mime_type,
-			   language, detail_level, COALESCE(save_auto, true), status,
-			   progress, status_message, result_recipe_id, error_code,
-			   error_message, idempotency_key, started_at, completed_at, created_at
-		FROM video_jobs
-		WHERE user_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
+// ListByUser retrieves a page of a user's jobs, newest first.
+// Pages are keyed on (created_at, id) so new jobs don't shift later pages;
+// pass the returned cursor as page.Cursor to fetch the next page.
+// The cursor is empty when there are no more results.
+func (r *JobRepository) ListByUser(ctx context.Context, userID uuid.UUID, filter model.JobListFilter, page pagination.Page) ([]*model.ExtractionJob, string, error) {
+	whereClause, args := jobFilterClause(userID, filter, true)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if page.Cursor != "" {
+		position, err := jobKeyset.Decode(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		whereClause += ` AND ` + jobKeyset.After(position, arg)
+	}
+
+	limit := page.Limit
+	if limit <= 0 {
+		limit = 20
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	// Fetch one extra row to know whether another page exists
+	query := fmt.Sprintf(`
+		SELECT j.id, j.user_id, COALESCE(j.job_type, 'video'), j.source_url, j.source_path, j.mime_type,
+			   j.language, j.detail_level, COALESCE(j.save_auto, true), j.status,
+			   j.progress, j.status_message, j.result_recipe_id, j.error_code,
+			   j.error_message, j.idempotency_key, j.started_at, j.completed_at, j.created_at,
+			   %s
+		FROM video_jobs j
+		LEFT JOIN recipes r ON r.id = j.result_recipe_id
+		%s
+		ORDER BY %s
+		LIMIT %s%s
+	`, jobKeyset.Columns(), whereClause, jobKeyset.OrderBy(), arg(limit+1), page.Skip(arg))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
 	var jobs []*model.ExtractionJob
+	var keys [][]string
 	for rows.Next() {
 		job := &model.ExtractionJob{}
+		key := make([]string, 2)
 		err := rows.Scan(
 			&job.ID,
 			&job.UserID,
@@ -185,14 +242,114 @@ func (r *JobRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit,
 			&job.StartedAt,
 			&job.CompletedAt,
 			&job.CreatedAt,
+			&key[0],
+			&key[1],
 		)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		jobs = append(jobs, job)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	jobs, nextCursor := pagination.Next(jobKeyset, jobs, keys, limit)
+	return jobs, nextCursor, nil
+}
+
+// CountByStatus counts a user's jobs per status. filter.Statuses, Cursor and
+// Limit are ignored so clients can show a count for every status tab.
+func (r *JobRepository) CountByStatus(ctx context.Context, userID uuid.UUID, filter model.JobListFilter) (map[model.JobStatus]int, error) {
+	whereClause, args := jobFilterClause(userID, filter, false)
+
+	query := `
+		SELECT j.status, COUNT(*)
+		FROM video_jobs j
+		LEFT JOIN recipes r ON r.id = j.result_recipe_id
+		` + whereClause + `
+		GROUP BY j.status
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[model.JobStatus]int)
+	for rows.Next() {
+		var status model.JobStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// jobFilterClause builds the WHERE clause shared by ListByUser and CountByStatus.
+// Queries must alias video_jobs as j and LEFT JOIN recipes as r.
+func jobFilterClause(userID uuid.UUID, filter model.JobListFilter, withStatus bool) (string, []interface{}) {
+	whereClause := `WHERE j.user_id = $1 AND j.deleted_at IS NULL`
+	args := []interface{}{userID}
+	argIndex := 2
+
+	if withStatus && len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, s := range filter.Statuses {
+			statuses[i] = string(s)
+		}
+		whereClause += fmt.Sprintf(` AND j.status = ANY($%d)`, argIndex)
+		args = append(args, TextArray(statuses))
+		argIndex++
+	}
+
+	if len(filter.JobTypes) > 0 {
+		types := make([]string, len(filter.JobTypes))
+		for i, t := range filter.JobTypes {
+			types[i] = string(t)
+		}
+		whereClause += fmt.Sprintf(` AND COALESCE(j.job_type, 'video') = ANY($%d)`, argIndex)
+		args = append(args, TextArray(types))
+		argIndex++
+	}
+
+	if filter.From != nil {
+		whereClause += fmt.Sprintf(` AND j.created_at >= $%d`, argIndex)
+		args = append(args, *filter.From)
+		argIndex++
+	}
+	if filter.To != nil {
+		whereClause += fmt.Sprintf(` AND j.created_at < $%d`, argIndex)
+		args = append(args, *filter.To)
+		argIndex++
+	}
+
+	if filter.Domain != "" {
+		// Host without "www.", matched exactly or as a parent domain
+		whereClause += fmt.Sprintf(` AND (
+			substring(lower(j.source_url) from '^[a-z][a-z0-9+.-]*://(?:www\.)?([^/:?#]+)') = $%d
+			OR substring(lower(j.source_url) from '^[a-z][a-z0-9+.-]*://([^/:?#]+)') LIKE '%%.' || $%d
+		)`, argIndex, argIndex)
+		args = append(args, filter.Domain)
+		argIndex++
+	}
+
+	if filter.Query != "" {
+		whereClause += fmt.Sprintf(` AND (j.source_url ILIKE $%d ESCAPE '\' OR r.title ILIKE $%d ESCAPE '\')`, argIndex, argIndex)
+		args = append(args, "%"+escapeLike(filter.Query)+"%")
 	}
 
-	return jobs, rows.Err()
+	return whereClause, args
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
 }
 
 // Update updates a job
@@ -257,6 +414,77 @@ func (r *JobRepository) UpdateProgress(ctx context.Context, id uuid.UUID, status
 	return nil
 }
 
+// UpdatePartialRecipe stores the latest partial recipe preview for a running job
+func (r *JobRepository) UpdatePartialRecipe(ctx context.Context, id uuid.UUID, partial *model.PartialRecipe) error {
+	data, err := json.Marshal(partial)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE video_jobs SET partial_recipe = $2 WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id, data)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
+
+// UpdateImportResult stores an import job's progress and per-recipe results
+func (r *JobRepository) UpdateImportResult(ctx context.Context, id uuid.UUID, progress int, message string, result *model.ImportResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE video_jobs
+		SET status = $2, progress = $3, status_message = $4, import_result = $5
+		WHERE id = $1
+	`
+	res, err := r.db.ExecContext(ctx, query, id, model.JobStatusProcessing, progress, message, data)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
+
+// MarkImportCompleted marks an import job as completed with its final results
+func (r *JobRepository) MarkImportCompleted(ctx context.Context, id uuid.UUID, result *model.ImportResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE video_jobs
+		SET status = $2, progress = 100, status_message = $3, import_result = $4, completed_at = $5
+		WHERE id = $1
+	`
+
+	now := time.Now().UTC()
+	message := fmt.Sprintf("Imported %d of %d recipes", result.Imported, result.Total)
+	_, err = r.db.ExecContext(ctx, query, id, model.JobStatusCompleted, message, data, now)
+	return err
+}
+
 // MarkStarted atomically marks a job as started
 // Returns (true, nil) if successfully started
 // Returns (false, nil) if job was already started by another goroutine
@@ -290,7 +518,7 @@ func (r *JobRepository) MarkCompleted(ctx context.Context, id, recipeID uuid.UUI
 	query := `
 		UPDATE video_jobs
 		SET status = $2, progress = 100, result_recipe_id = $3,
-			status_message = $4, completed_at = $5
+			status_message = $4, completed_at = $5, partial_recipe = NULL
 		WHERE id = $1
 	`
 
@@ -304,7 +532,8 @@ func (r *JobRepository) MarkCompleted(ctx context.Context, id, recipeID uuid.UUI
 func (r *JobRepository) MarkFailed(ctx context.Context, id uuid.UUID, errorCode, errorMessage string) error {
 	query := `
 		UPDATE video_jobs
-		SET status = $2, error_code = $3, error_message = $4, completed_at = $5
+		SET status = $2, error_code = $3, error_message = $4, completed_at = $5,
+			partial_recipe = NULL
 		WHERE id = $1
 	`
 
@@ -317,7 +546,7 @@ func (r *JobRepository) MarkFailed(ctx context.Context, id uuid.UUID, errorCode,
 func (r *JobRepository) MarkCancelled(ctx context.Context, id uuid.UUID) error {
 	query := `
 		UPDATE video_jobs
-		SET status = $2, status_message = $3, completed_at = $4
+		SET status = $2, status_message = $3, completed_at = $4, partial_recipe = NULL
 		WHERE id = $1 AND status NOT IN ($5, $6, $7)
 	`
 
@@ -359,12 +588,13 @@ func (r *JobRepository) CountPendingByUser(ctx context.Context, userID uuid.UUID
 // CountUsedThisMonth counts all non-failed extractions this month for a user.
 // Includes completed AND in-progress jobs to prevent parallel request race conditions.
 // Excludes TRANSIENT_FAILURE jobs (rate limits, server errors) so users aren't penalized
-// for infrastructure issues they can't control.
+// for infrastructure issues they can't control. Imports are not extractions.
 func (r *JobRepository) CountUsedThisMonth(ctx context.Context, userID uuid.UUID) (int, error) {
 	query := `
 		SELECT COUNT(*) FROM video_jobs
 		WHERE user_id = $1
 		AND status NOT IN ($2, $3)
+		AND COALESCE(job_type, 'video') <> 'import'
 		AND (error_code IS NULL OR error_code != 'TRANSIENT_FAILURE')
 		AND created_at >= date_trunc('month', CURRENT_DATE)
 	`
@@ -380,7 +610,8 @@ func (r *JobRepository) MarkStuckJobsAsFailed(ctx context.Context, maxDuration t
 		SET status = $1, 
 		    error_code = $2, 
 		    error_message = $3,
-		    completed_at = $4
+		    completed_at = $4,
+		    partial_recipe = NULL
 		WHERE status IN ($5, $6, $7, $8)
 		AND started_at IS NOT NULL
 		AND started_at < $9
@@ -119,17 +119,17 @@ func (r *MealPlanRepository) AddEntry(ctx context.Context, planID uuid.UUID, inp
 	}
 
 	query := `
-		INSERT INTO meal_plan_entries (plan_id, recipe_id, day_index, meal_type, sort_order)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, plan_id, recipe_id, day_index, meal_type, sort_order, created_at
+		INSERT INTO meal_plan_entries (plan_id, recipe_id, day_index, meal_type, sort_order, servings)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, plan_id, recipe_id, day_index, meal_type, sort_order, servings, created_at
 	`
 
 	entry := &model.MealPlanEntry{}
 	err = r.db.QueryRowContext(ctx, query,
-		planID, input.RecipeID, input.DayIndex, input.MealType, maxSort+1,
+		planID, input.RecipeID, input.DayIndex, input.MealType, maxSort+1, input.Servings,
 	).Scan(
 		&entry.ID, &entry.PlanID, &entry.RecipeID, &entry.DayIndex,
-		&entry.MealType, &entry.SortOrder, &entry.CreatedAt,
+		&entry.MealType, &entry.SortOrder, &entry.Servings, &entry.CreatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -172,7 +172,7 @@ func (r *MealPlanRepository) RemoveEntry(ctx context.Context, planID, entryID uu
 // GetEntriesWithRecipes returns all entries for a plan with recipe details joined
 func (r *MealPlanRepository) GetEntriesWithRecipes(ctx context.Context, planID uuid.UUID) ([]model.MealPlanEntry, error) {
 	query := `
-		SELECT e.id, e.plan_id, e.recipe_id, e.day_index, e.meal_type, e.sort_order, e.created_at,
+		SELECT e.id, e.plan_id, e.recipe_id, e.day_index, e.meal_type, e.sort_order, e.servings, e.created_at,
 		       r.title, r.thumbnail_url, r.prep_time, r.cook_time
 		FROM meal_plan_entries e
 		JOIN recipes r ON r.id = e.recipe_id AND r.deleted_at IS NULL
@@ -191,7 +191,7 @@ func (r *MealPlanRepository) GetEntriesWithRecipes(ctx context.Context, planID u
 		entry := model.MealPlanEntry{}
 		err := rows.Scan(
 			&entry.ID, &entry.PlanID, &entry.RecipeID, &entry.DayIndex,
-			&entry.MealType, &entry.SortOrder, &entry.CreatedAt,
+			&entry.MealType, &entry.SortOrder, &entry.Servings, &entry.CreatedAt,
 			&entry.RecipeTitle, &entry.ThumbnailURL, &entry.PrepTime, &entry.CookTime,
 		)
 		if err != nil {
@@ -207,10 +207,11 @@ func (r *MealPlanRepository) GetEntriesWithRecipes(ctx context.Context, planID u
 	return entries, rows.Err()
 }
 
-// GetPlanRecipeIngredients returns all ingredients from all recipes in a plan
-func (r *MealPlanRepository) GetPlanRecipeIngredients(ctx context.Context, planID uuid.UUID) ([]model.RecipeIngredient, []string, error) {
+// GetPlanRecipeIngredients returns the ingredients of every entry in a plan.
+// A recipe planned twice appears twice, each with its entry's servings.
+func (r *MealPlanRepository) GetPlanRecipeIngredients(ctx context.Context, planID uuid.UUID) ([]model.MealPlanIngredient, error) {
 	query := `
-		SELECT DISTINCT ri.name, ri.quantity, ri.unit, ri.category, r.title
+		SELECT ri.name, ri.quantity, ri.unit, ri.category, r.title, r.servings, e.servings
 		FROM meal_plan_entries e
 		JOIN recipes r ON r.id = e.recipe_id AND r.deleted_at IS NULL
 		JOIN recipe_ingredients ri ON ri.recipe_id = r.id
@@ -220,22 +221,20 @@ func (r *MealPlanRepository) GetPlanRecipeIngredients(ctx context.Context, planI
 
 	rows, err := r.db.QueryContext(ctx, query, planID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	var ingredients []model.RecipeIngredient
-	var recipeNames []string
+	var ingredients []model.MealPlanIngredient
 	for rows.Next() {
-		var ing model.RecipeIngredient
-		var recipeName string
-		err := rows.Scan(&ing.Name, &ing.Quantity, &ing.Unit, &ing.Category, &recipeName)
+		var ing model.MealPlanIngredient
+		err := rows.Scan(&ing.Name, &ing.Quantity, &ing.Unit, &ing.Category,
+			&ing.RecipeTitle, &ing.RecipeServings, &ing.EntryServings)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		ingredients = append(ingredients, ing)
-		recipeNames = append(recipeNames, recipeName)
 	}
 
-	return ingredients, recipeNames, rows.Err()
+	return ingredients, rows.Err()
 }
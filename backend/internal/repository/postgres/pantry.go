@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
 )
 
 // PantryRepository handles pantry item data access
@@ -21,59 +22,87 @@ func NewPantryRepository(db *sql.DB) *PantryRepository {
 	return &PantryRepository{db: db}
 }
 
-// List returns pantry items for a user with SQL-based pagination
-func (r *PantryRepository) List(ctx context.Context, userID uuid.UUID, category *string, limit, offset int) ([]*model.PantryItem, int, error) {
+// pantryKeyset orders pantry items by category, then name
+var pantryKeyset = pagination.Keyset{Name: "pantry", Keys: []pagination.Key{
+	{SQL: "category", Type: "text"},
+	{SQL: "name", Type: "text"},
+	{SQL: "id", Type: "uuid"},
+}}
+
+// List returns a page of a user's pantry items, the number of items matching
+// category and the cursor for the next page, empty on the last page
+func (r *PantryRepository) List(ctx context.Context, userID uuid.UUID, category *string, page pagination.Page) ([]*model.PantryItem, int, string, error) {
 	// Build WHERE clause
 	whereClause := `WHERE user_id = $1 AND deleted_at IS NULL`
 	args := []interface{}{userID}
-	argIndex := 2
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
 
 	if category != nil && *category != "" {
-		whereClause += fmt.Sprintf(` AND category = $%d`, argIndex)
-		args = append(args, *category)
-		argIndex++
+		whereClause += ` AND category = ` + arg(*category)
 	}
 
 	// Get total count first
 	countQuery := `SELECT COUNT(*) FROM pantry_items ` + whereClause
 	var total int
 	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
+	}
+
+	if page.Cursor != "" {
+		position, err := pantryKeyset.Decode(page.Cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		whereClause += ` AND ` + pantryKeyset.After(position, arg)
 	}
 
-	// Get paginated items
+	limit := page.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	// Fetch one extra row to know whether another page exists
 	query := fmt.Sprintf(`
 		SELECT id, user_id, name, category, quantity, unit,
-		       sync_version, created_at, updated_at, deleted_at
+		       sync_version, created_at, updated_at, deleted_at, %s
 		FROM pantry_items
 		%s
-		ORDER BY category ASC, name ASC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
-
-	args = append(args, limit, offset)
+		ORDER BY %s
+		LIMIT %s%s
+	`, pantryKeyset.Columns(), whereClause, pantryKeyset.OrderBy(), arg(limit+1), page.Skip(arg))
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 	defer rows.Close()
 
 	var items []*model.PantryItem
+	var keys [][]string
 	for rows.Next() {
 		item := &model.PantryItem{}
+		key := make([]string, 3)
 		err := rows.Scan(
 			&item.ID, &item.UserID, &item.Name, &item.Category,
 			&item.Quantity, &item.Unit,
 			&item.SyncVersion, &item.CreatedAt, &item.UpdatedAt, &item.DeletedAt,
+			&key[0], &key[1], &key[2],
 		)
 		if err != nil {
-			return nil, 0, err
+			return nil, 0, "", err
 		}
 		items = append(items, item)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, "", err
 	}
 
-	return items, total, rows.Err()
+	items, next := pagination.Next(pantryKeyset, items, keys, limit)
+	return items, total, next, nil
 }
 
 // ListAll returns all pantry items for a user (for recommendations)
@@ -6,12 +6,14 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/diet"
+	"github.com/dishflow/backend/internal/pkg/ingredient"
+	"github.com/dishflow/backend/internal/pkg/tag"
 )
 
 // unmarshalJSONB unmarshals JSONB data, logging a warning on corruption instead of failing silently
@@ -27,16 +29,23 @@ var (
 
 // RecipeRepository handles recipe database operations
 type RecipeRepository struct {
-	db *sql.DB
+	db   *sql.DB
+	tags *tag.Normalizer
 }
 
-// NewRecipeRepository creates a new recipe repository
-func NewRecipeRepository(db *sql.DB) *RecipeRepository {
-	return &RecipeRepository{db: db}
+// NewRecipeRepository creates a new recipe repository. Recipe tags are
+// folded to canonical form by tags on every save.
+func NewRecipeRepository(db *sql.DB, tags *tag.Normalizer) *RecipeRepository {
+	return &RecipeRepository{db: db, tags: tags}
 }
 
 // Create creates a new recipe with its ingredients and steps
 func (r *RecipeRepository) Create(ctx context.Context, recipe *model.Recipe) error {
+	return r.create(ctx, recipe, model.RevisionSourceCreate)
+}
+
+// create inserts the recipe and records its first revision
+func (r *RecipeRepository) create(ctx context.Context, recipe *model.Recipe, source model.RevisionSource) error {
 	// Timeout to prevent long transactions
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -65,6 +74,10 @@ func (r *RecipeRepository) Create(ctx context.Context, recipe *model.Recipe) err
 		}
 	}
 
+	// Check the AI's dietary labels against the ingredients
+	recipe.DietaryInfo = diet.Reconcile(recipe.DietaryInfo, recipe.Ingredients)
+	recipe.Tags = r.tags.Tags(recipe.Tags)
+
 	// Marshal dietary info to JSON
 	var dietaryInfoJSON []byte
 	if recipe.DietaryInfo != nil {
@@ -149,21 +162,27 @@ func (r *RecipeRepository) Create(ctx context.Context, recipe *model.Recipe) err
 		}
 	}
 
+	if _, err := r.insertRevision(ctx, tx, recipe, source, nil); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
 func (r *RecipeRepository) insertIngredient(ctx context.Context, tx *sql.Tx, ing *model.RecipeIngredient) error {
 	query := `
 		INSERT INTO recipe_ingredients (
-			id, recipe_id, name, quantity, unit, category, section, is_optional,
+			id, recipe_id, name, match_name, quantity, quantity_max, unit, category, section, is_optional,
 			notes, video_timestamp, sort_order, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 	_, err := tx.ExecContext(ctx, query,
 		ing.ID,
 		ing.RecipeID,
 		ing.Name,
+		ingredient.MatchKey(ing.Name),
 		ing.Quantity,
+		ing.QuantityMax,
 		ing.Unit,
 		ing.Category,
 		ing.Section,
@@ -198,15 +217,27 @@ func (r *RecipeRepository) insertStep(ctx context.Context, tx *sql.Tx, step *mod
 	return err
 }
 
+// queryer runs reads on either the pool or a transaction
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // GetByID retrieves a recipe by ID with ingredients and steps
 func (r *RecipeRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Recipe, error) {
+	return r.getByID(ctx, r.db, id)
+}
+
+// getByID loads a recipe through q, so a transaction sees its own changes
+func (r *RecipeRepository) getByID(ctx context.Context, q queryer, id uuid.UUID) (*model.Recipe, error) {
 	query := `
 		SELECT id, user_id, title, description, servings, prep_time, cook_time,
 			   difficulty, cuisine, thumbnail_url, source_type, source_url,
 			   source_recipe_id, source_metadata, tags, is_public, is_favorite,
 			   is_featured, featured_at,
-			   nutrition, dietary_info, sync_version, created_at, updated_at
-		FROM recipes
+			   nutrition, dietary_info, sync_version, created_at, updated_at,
+			   ` + cookStatsColumns + `
+		FROM recipes r
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
@@ -214,7 +245,7 @@ func (r *RecipeRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Re
 	var sourceMetadata, nutritionJSON, dietaryInfoJSON []byte
 	var tags TextArray
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := q.QueryRowContext(ctx, query, id).Scan(
 		&recipe.ID,
 		&recipe.UserID,
 		&recipe.Title,
@@ -239,6 +270,9 @@ func (r *RecipeRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Re
 		&recipe.SyncVersion,
 		&recipe.CreatedAt,
 		&recipe.UpdatedAt,
+		&recipe.TimesCooked,
+		&recipe.LastCookedOn,
+		&recipe.AverageRating,
 	)
 
 	if err != nil {
@@ -263,13 +297,13 @@ func (r *RecipeRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Re
 	}
 
 	// Load ingredients
-	recipe.Ingredients, err = r.getIngredients(ctx, id)
+	recipe.Ingredients, err = r.getIngredients(ctx, q, id)
 	if err != nil {
 		return nil, err
 	}
 
 	// Load steps
-	recipe.Steps, err = r.getSteps(ctx, id)
+	recipe.Steps, err = r.getSteps(ctx, q, id)
 	if err != nil {
 		return nil, err
 	}
@@ -344,13 +378,13 @@ func (r *RecipeRepository) GetBySourceURL(ctx context.Context, userID uuid.UUID,
 	}
 
 	// Load ingredients
-	recipe.Ingredients, err = r.getIngredients(ctx, recipe.ID)
+	recipe.Ingredients, err = r.getIngredients(ctx, r.db, recipe.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Load steps
-	recipe.Steps, err = r.getSteps(ctx, recipe.ID)
+	recipe.Steps, err = r.getSteps(ctx, r.db, recipe.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -427,16 +461,16 @@ func (r *RecipeRepository) GetBySourceRecipeID(ctx context.Context, userID, sour
 	return recipe, nil
 }
 
-func (r *RecipeRepository) getIngredients(ctx context.Context, recipeID uuid.UUID) ([]model.RecipeIngredient, error) {
+func (r *RecipeRepository) getIngredients(ctx context.Context, q queryer, recipeID uuid.UUID) ([]model.RecipeIngredient, error) {
 	query := `
-		SELECT id, recipe_id, name, quantity, unit, category, section, is_optional,
+		SELECT id, recipe_id, name, quantity, quantity_max, unit, category, section, is_optional,
 			   notes, video_timestamp, sort_order, created_at
 		FROM recipe_ingredients
 		WHERE recipe_id = $1
 		ORDER BY sort_order
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, recipeID)
+	rows, err := q.QueryContext(ctx, query, recipeID)
 	if err != nil {
 		return nil, err
 	}
@@ -452,6 +486,7 @@ func (r *RecipeRepository) getIngredients(ctx context.Context, recipeID uuid.UUI
 			&ing.RecipeID,
 			&ing.Name,
 			&ing.Quantity,
+			&ing.QuantityMax,
 			&ing.Unit,
 			&ing.Category,
 			&section,
@@ -475,7 +510,7 @@ func (r *RecipeRepository) getIngredients(ctx context.Context, recipeID uuid.UUI
 	return ingredients, rows.Err()
 }
 
-func (r *RecipeRepository) getSteps(ctx context.Context, recipeID uuid.UUID) ([]model.RecipeStep, error) {
+func (r *RecipeRepository) getSteps(ctx context.Context, q queryer, recipeID uuid.UUID) ([]model.RecipeStep, error) {
 	query := `
 		SELECT id, recipe_id, step_number, instruction, duration_seconds,
 			   technique, temperature, video_timestamp_start, video_timestamp_end, created_at
@@ -484,7 +519,7 @@ func (r *RecipeRepository) getSteps(ctx context.Context, recipeID uuid.UUID) ([]
 		ORDER BY step_number
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, recipeID)
+	rows, err := q.QueryContext(ctx, query, recipeID)
 	if err != nil {
 		return nil, err
 	}
@@ -514,184 +549,25 @@ func (r *RecipeRepository) getSteps(ctx context.Context, recipeID uuid.UUID) ([]
 	return steps, rows.Err()
 }
 
-// ListByUser retrieves all recipes for a user with ingredient/step counts
-func (r *RecipeRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.Recipe, int, error) {
-	// Get total count
-	countQuery := `SELECT COUNT(*) FROM recipes WHERE user_id = $1 AND deleted_at IS NULL`
-	var total int
-	err := r.db.QueryRowContext(ctx, countQuery, userID).Scan(&total)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Get recipes with ingredient and step counts (single query, no N+1)
-	query := `
-		SELECT r.id, r.user_id, r.title, r.description, r.servings, r.prep_time, r.cook_time,
-			   r.difficulty, r.cuisine, r.thumbnail_url, r.source_type, r.source_url,
-			   r.source_recipe_id, r.source_metadata, r.tags, r.is_public, r.is_favorite,
-			   r.is_featured, r.featured_at,
-			   r.nutrition, r.dietary_info, r.sync_version, r.created_at, r.updated_at,
-			   COALESCE((SELECT COUNT(*) FROM recipe_ingredients WHERE recipe_id = r.id), 0) AS ingredient_count,
-			   COALESCE((SELECT COUNT(*) FROM recipe_steps WHERE recipe_id = r.id), 0) AS step_count
-		FROM recipes r
-		WHERE r.user_id = $1 AND r.deleted_at IS NULL
-		ORDER BY r.created_at DESC
-		LIMIT $2 OFFSET $3
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer rows.Close()
-
-	var recipes []*model.Recipe
-	for rows.Next() {
-		recipe := &model.Recipe{}
-		var sourceMetadata, nutritionJSON, dietaryInfoJSON []byte
-		var tags TextArray
-
-		err := rows.Scan(
-			&recipe.ID,
-			&recipe.UserID,
-			&recipe.Title,
-			&recipe.Description,
-			&recipe.Servings,
-			&recipe.PrepTime,
-			&recipe.CookTime,
-			&recipe.Difficulty,
-			&recipe.Cuisine,
-			&recipe.ThumbnailURL,
-			&recipe.SourceType,
-			&recipe.SourceURL,
-			&recipe.SourceRecipeID,
-			&sourceMetadata,
-			&tags,
-			&recipe.IsPublic,
-			&recipe.IsFavorite,
-			&recipe.IsFeatured,
-			&recipe.FeaturedAt,
-			&nutritionJSON,
-			&dietaryInfoJSON,
-			&recipe.SyncVersion,
-			&recipe.CreatedAt,
-			&recipe.UpdatedAt,
-			&recipe.IngredientCount,
-			&recipe.StepCount,
-		)
-		if err != nil {
-			return nil, 0, err
-		}
-
-		recipe.Tags = []string(tags)
-		if sourceMetadata != nil {
-			unmarshalJSONB(sourceMetadata, &recipe.SourceMetadata, "source_metadata")
-		}
-		if nutritionJSON != nil {
-			recipe.Nutrition = &model.RecipeNutrition{}
-			unmarshalJSONB(nutritionJSON, recipe.Nutrition, "nutrition")
-		}
-		if dietaryInfoJSON != nil {
-			recipe.DietaryInfo = &model.DietaryInfo{}
-			unmarshalJSONB(dietaryInfoJSON, recipe.DietaryInfo, "dietary_info")
-		}
-
-		recipes = append(recipes, recipe)
-	}
-
-	return recipes, total, rows.Err()
+// Update updates a recipe
+func (r *RecipeRepository) Update(ctx context.Context, recipe *model.Recipe) error {
+	return r.update(ctx, recipe, model.RevisionSourceUpdate, nil)
 }
 
-// ListPublic retrieves all public/suggested recipes with ingredient/step counts
-func (r *RecipeRepository) ListPublic(ctx context.Context, limit, offset int) ([]*model.Recipe, int, error) {
-	// Get total count
-	countQuery := `SELECT COUNT(*) FROM recipes WHERE is_public = TRUE AND deleted_at IS NULL`
-	var total int
-	err := r.db.QueryRowContext(ctx, countQuery).Scan(&total)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Get public recipes in random order so users see different suggestions each time
-	query := `
-		SELECT r.id, r.user_id, r.title, r.description, r.servings, r.prep_time, r.cook_time,
-			   r.difficulty, r.cuisine, r.thumbnail_url, r.source_type, r.source_url,
-			   r.source_recipe_id, r.source_metadata, r.tags, r.is_public, r.is_favorite,
-			   r.is_featured, r.featured_at,
-			   r.nutrition, r.dietary_info, r.sync_version, r.created_at, r.updated_at,
-			   COALESCE((SELECT COUNT(*) FROM recipe_ingredients WHERE recipe_id = r.id), 0) AS ingredient_count,
-			   COALESCE((SELECT COUNT(*) FROM recipe_steps WHERE recipe_id = r.id), 0) AS step_count
-		FROM recipes r
-		WHERE r.is_public = TRUE AND r.deleted_at IS NULL
-		ORDER BY RANDOM()
-		LIMIT $1 OFFSET $2
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer rows.Close()
-
-	var recipes []*model.Recipe
-	for rows.Next() {
-		recipe := &model.Recipe{}
-		var sourceMetadata, nutritionJSON, dietaryInfoJSON []byte
-		var tags TextArray
-
-		err := rows.Scan(
-			&recipe.ID,
-			&recipe.UserID,
-			&recipe.Title,
-			&recipe.Description,
-			&recipe.Servings,
-			&recipe.PrepTime,
-			&recipe.CookTime,
-			&recipe.Difficulty,
-			&recipe.Cuisine,
-			&recipe.ThumbnailURL,
-			&recipe.SourceType,
-			&recipe.SourceURL,
-			&recipe.SourceRecipeID,
-			&sourceMetadata,
-			&tags,
-			&recipe.IsPublic,
-			&recipe.IsFavorite,
-			&recipe.IsFeatured,
-			&recipe.FeaturedAt,
-			&nutritionJSON,
-			&dietaryInfoJSON,
-			&recipe.SyncVersion,
-			&recipe.CreatedAt,
-			&recipe.UpdatedAt,
-			&recipe.IngredientCount,
-			&recipe.StepCount,
-		)
-		if err != nil {
-			return nil, 0, err
-		}
-
-		recipe.Tags = []string(tags)
-		if sourceMetadata != nil {
-			unmarshalJSONB(sourceMetadata, &recipe.SourceMetadata, "source_metadata")
-		}
-		if nutritionJSON != nil {
-			recipe.Nutrition = &model.RecipeNutrition{}
-			unmarshalJSONB(nutritionJSON, recipe.Nutrition, "nutrition")
-		}
-		if dietaryInfoJSON != nil {
-			recipe.DietaryInfo = &model.DietaryInfo{}
-			unmarshalJSONB(dietaryInfoJSON, recipe.DietaryInfo, "dietary_info")
-		}
+// Restore saves recipe content taken from an old revision. The result is
+// recorded as a new "restore" revision; history is never rewritten.
+func (r *RecipeRepository) Restore(ctx context.Context, recipe *model.Recipe, fromRevisionID uuid.UUID) error {
+	return r.update(ctx, recipe, model.RevisionSourceRestore, &fromRevisionID)
+}
 
-		recipes = append(recipes, recipe)
+// update overwrites the recipe, its ingredients and steps, and appends a revision
+func (r *RecipeRepository) update(ctx context.Context, recipe *model.Recipe, source model.RevisionSource, restoredFrom *uuid.UUID) error {
+	// Recipes saved before revisions existed get their current state
+	// recorded first, so the edit can still be diffed and reverted
+	if err := r.ensureBaselineRevision(ctx, recipe.ID); err != nil {
+		return err
 	}
 
-	return recipes, total, rows.Err()
-}
-
-// Update updates a recipe
-func (r *RecipeRepository) Update(ctx context.Context, recipe *model.Recipe) error {
 	// Timeout for updates
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -718,6 +594,9 @@ func (r *RecipeRepository) Update(ctx context.Context, recipe *model.Recipe) err
 		}
 	}
 
+	recipe.DietaryInfo = diet.Reconcile(recipe.DietaryInfo, recipe.Ingredients)
+	recipe.Tags = r.tags.Tags(recipe.Tags)
+
 	var dietaryInfoJSON []byte
 	if recipe.DietaryInfo != nil {
 		dietaryInfoJSON, err = json.Marshal(recipe.DietaryInfo)
@@ -818,6 +697,10 @@ func (r *RecipeRepository) Update(ctx context.Context, recipe *model.Recipe) err
 		}
 	}
 
+	if _, err := r.insertRevision(ctx, tx, recipe, source, restoredFrom); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -948,13 +831,13 @@ func (r *RecipeRepository) GetChangesSince(ctx context.Context, userID uuid.UUID
 		}
 
 		// Get ingredients and steps
-		ingredients, err := r.getIngredients(ctx, recipe.ID)
+		ingredients, err := r.getIngredients(ctx, r.db, recipe.ID)
 		if err != nil {
 			return nil, err
 		}
 		recipe.Ingredients = ingredients
 
-		steps, err := r.getSteps(ctx, recipe.ID)
+		steps, err := r.getSteps(ctx, r.db, recipe.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -972,7 +855,7 @@ func (r *RecipeRepository) Upsert(ctx context.Context, recipe *model.Recipe) err
 	existing, err := r.GetByID(ctx, recipe.ID)
 	if errors.Is(err, ErrRecipeNotFound) {
 		// Create new recipe
-		return r.Create(ctx, recipe)
+		return r.create(ctx, recipe, model.RevisionSourceSync)
 	}
 	if err != nil {
 		return err
@@ -981,13 +864,13 @@ func (r *RecipeRepository) Upsert(ctx context.Context, recipe *model.Recipe) err
 	// Update existing recipe
 	// Preserve ID and user_id
 	recipe.UserID = existing.UserID
-	return r.Update(ctx, recipe)
+	return r.update(ctx, recipe, model.RevisionSourceSync, nil)
 }
 
 // ListForRecommendations retrieves all recipes for a user with full ingredients loaded
 // This is optimized for the recommendation engine which needs ingredient data for matching
 // Uses single JOIN query to load ingredients
-func (r *RecipeRepository) ListForRecommendations(ctx context.Context, userID uuid.UUID) ([]*model.Recipe, error) {
+func (r *RecipeRepository) ListForRecommendations(ctx context.Context, viewerID uuid.UUID) ([]*model.Recipe, error) {
 	// CRITICAL: Single query with LEFT JOIN to avoid N+1 problem
 	// Before: 100 recipes = 1 recipe query + 100 ingredient queries = 101 queries (~5 seconds)
 	// After: 100 recipes = 1 query with JOIN (~300ms)
@@ -998,6 +881,7 @@ func (r *RecipeRepository) ListForRecommendations(ctx context.Context, userID uu
 			r.source_recipe_id, r.source_metadata, r.tags, r.is_public, r.is_favorite,
 			r.is_featured, r.featured_at,
 			r.nutrition, r.dietary_info, r.sync_version, r.created_at, r.updated_at,
+			` + cookStatsColumns + `,
 			i.id as ing_id, i.name as ing_name, i.quantity, i.unit, i.category,
 			i.is_optional, i.sort_order
 		FROM recipes r
@@ -1007,7 +891,7 @@ func (r *RecipeRepository) ListForRecommendations(ctx context.Context, userID uu
 		ORDER BY r.created_at DESC, i.sort_order ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := r.db.QueryContext(ctx, query, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -1036,6 +920,9 @@ func (r *RecipeRepository) ListForRecommendations(ctx context.Context, userID uu
 			featuredAt                    *time.Time
 			syncVersion                   int
 			createdAt, updatedAt          time.Time
+			timesCooked                   int
+			lastCookedOn                  *time.Time
+			averageRating                 *float64
 			// Ingredient fields (nullable since LEFT JOIN)
 			ingID      sql.NullString
 			ingName    sql.NullString
@@ -1052,6 +939,7 @@ func (r *RecipeRepository) ListForRecommendations(ctx context.Context, userID uu
 			&sourceRecipeID, &sourceMetadata, &tags, &isPublic, &isFavorite,
 			&isFeatured, &featuredAt,
 			&nutritionJSON, &dietaryInfoJSON, &syncVersion, &createdAt, &updatedAt,
+			&timesCooked, &lastCookedOn, &averageRating,
 			&ingID, &ingName, &quantity, &unit, &category, &isOptional, &sortOrder,
 		)
 		if err != nil {
@@ -1083,6 +971,12 @@ func (r *RecipeRepository) ListForRecommendations(ctx context.Context, userID uu
 				UpdatedAt:    updatedAt,
 				Ingredients:  []model.RecipeIngredient{},
 			}
+			// Public recipes don't carry their owner's cook stats
+			if userID == viewerID {
+				recipe.TimesCooked = timesCooked
+				recipe.LastCookedOn = lastCookedOn
+				recipe.AverageRating = averageRating
+			}
 			if sourceURL.Valid {
 				url := sourceURL.String
 				recipe.SourceURL = &url
@@ -1142,339 +1036,3 @@ func (r *RecipeRepository) ListForRecommendations(ctx context.Context, userID uu
 
 	return recipes, rows.Err()
 }
-
-// Search searches user's recipes by query string.
-// Matches against title, cuisine, description, and tags using case-insensitive matching.
-// Returns lightweight recipe summaries (no ingredients/steps) for fast suggestion display.
-//
-// Security: Query is parameterized to prevent SQL injection.
-// Performance: Uses ILIKE with indexed columns. Suitable for <10k recipes per user.
-// Fault tolerance: Returns empty slice (not nil) on no results. Timeouts prevent runaway queries.
-func (r *RecipeRepository) Search(ctx context.Context, userID uuid.UUID, query string, limit int) ([]*model.Recipe, error) {
-	// Timeout for search
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	// Sanitize limit
-	if limit <= 0 {
-		limit = 10
-	}
-	if limit > 50 {
-		limit = 50
-	}
-
-	// Empty query returns empty results
-	query = strings.TrimSpace(query)
-	if query == "" {
-		return []*model.Recipe{}, nil
-	}
-
-	// Build search pattern for ILIKE
-	searchPattern := "%" + query + "%"
-
-	// Query searches title, cuisine, description, and tags with ranking:
-	// - Exact title prefix matches rank highest (0)
-	// - Other title matches rank second (1)
-	// - Cuisine/description/tag matches rank third (2)
-	sqlQuery := `
-		SELECT r.id, r.user_id, r.title, r.description, r.servings, r.prep_time, r.cook_time,
-		       r.difficulty, r.cuisine, r.thumbnail_url, r.source_type, r.source_url,
-		       r.source_recipe_id, r.source_metadata, r.tags, r.is_public, r.is_favorite,
-		       r.is_featured, r.featured_at,
-		       r.nutrition, r.dietary_info, r.sync_version, r.created_at, r.updated_at,
-		       COALESCE((SELECT COUNT(*) FROM recipe_ingredients WHERE recipe_id = r.id), 0) AS ingredient_count,
-		       COALESCE((SELECT COUNT(*) FROM recipe_steps WHERE recipe_id = r.id), 0) AS step_count
-		FROM recipes r
-		WHERE r.user_id = $1 
-		  AND r.deleted_at IS NULL
-		  AND (
-		      r.title ILIKE $2
-		      OR r.cuisine ILIKE $2
-		      OR r.description ILIKE $2
-		      OR EXISTS (SELECT 1 FROM unnest(r.tags) AS tag WHERE tag ILIKE $2)
-		  )
-		ORDER BY 
-		    CASE 
-		        WHEN r.title ILIKE $3 || '%' THEN 0  -- Prefix match ranks highest
-		        WHEN r.title ILIKE $2 THEN 1         -- Any title match
-		        ELSE 2                                -- Other matches
-		    END,
-		    r.is_favorite DESC,  -- Favorites bubble up within same rank
-		    r.title
-		LIMIT $4
-	`
-
-	rows, err := r.db.QueryContext(ctx, sqlQuery, userID, searchPattern, query, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	// Pre-allocate with reasonable capacity
-	recipes := make([]*model.Recipe, 0, limit)
-
-	for rows.Next() {
-		recipe := &model.Recipe{}
-		var sourceMetadata, nutritionJSON, dietaryInfoJSON []byte
-		var tags TextArray
-
-		err := rows.Scan(
-			&recipe.ID,
-			&recipe.UserID,
-			&recipe.Title,
-			&recipe.Description,
-			&recipe.Servings,
-			&recipe.PrepTime,
-			&recipe.CookTime,
-			&recipe.Difficulty,
-			&recipe.Cuisine,
-			&recipe.ThumbnailURL,
-			&recipe.SourceType,
-			&recipe.SourceURL,
-			&recipe.SourceRecipeID,
-			&sourceMetadata,
-			&tags,
-			&recipe.IsPublic,
-			&recipe.IsFavorite,
-			&recipe.IsFeatured,
-			&recipe.FeaturedAt,
-			&nutritionJSON,
-			&dietaryInfoJSON,
-			&recipe.SyncVersion,
-			&recipe.CreatedAt,
-			&recipe.UpdatedAt,
-			&recipe.IngredientCount,
-			&recipe.StepCount,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		recipe.Tags = []string(tags)
-
-		// Unmarshal JSONB fields gracefully (log warning, don't fail)
-		if sourceMetadata != nil {
-			unmarshalJSONB(sourceMetadata, &recipe.SourceMetadata, "source_metadata")
-		}
-		if nutritionJSON != nil {
-			recipe.Nutrition = &model.RecipeNutrition{}
-			unmarshalJSONB(nutritionJSON, recipe.Nutrition, "nutrition")
-		}
-		if dietaryInfoJSON != nil {
-			recipe.DietaryInfo = &model.DietaryInfo{}
-			unmarshalJSONB(dietaryInfoJSON, recipe.DietaryInfo, "dietary_info")
-		}
-
-		recipes = append(recipes, recipe)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return recipes, nil
-}
-
-// SearchPublic searches public (suggested) recipes by title, cuisine, description, and tags.
-func (r *RecipeRepository) SearchPublic(ctx context.Context, query string, limit int) ([]*model.Recipe, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	if limit <= 0 {
-		limit = 10
-	}
-	if limit > 50 {
-		limit = 50
-	}
-
-	query = strings.TrimSpace(query)
-	if query == "" {
-		return []*model.Recipe{}, nil
-	}
-
-	searchPattern := "%" + query + "%"
-
-	sqlQuery := `
-		SELECT r.id, r.user_id, r.title, r.description, r.servings, r.prep_time, r.cook_time,
-		       r.difficulty, r.cuisine, r.thumbnail_url, r.source_type, r.source_url,
-		       r.source_recipe_id, r.source_metadata, r.tags, r.is_public, r.is_favorite,
-		       r.is_featured, r.featured_at,
-		       r.nutrition, r.dietary_info, r.sync_version, r.created_at, r.updated_at,
-		       COALESCE((SELECT COUNT(*) FROM recipe_ingredients WHERE recipe_id = r.id), 0) AS ingredient_count,
-		       COALESCE((SELECT COUNT(*) FROM recipe_steps WHERE recipe_id = r.id), 0) AS step_count
-		FROM recipes r
-		WHERE (r.is_public = TRUE OR r.is_featured = TRUE)
-		  AND r.deleted_at IS NULL
-		  AND (
-		      r.title ILIKE $1
-		      OR r.cuisine ILIKE $1
-		      OR r.description ILIKE $1
-		      OR EXISTS (SELECT 1 FROM unnest(r.tags) AS tag WHERE tag ILIKE $1)
-		  )
-		ORDER BY
-		    CASE
-		        WHEN r.title ILIKE $2 || '%' THEN 0
-		        WHEN r.title ILIKE $1 THEN 1
-		        ELSE 2
-		    END,
-		    r.is_featured DESC,
-		    r.title
-		LIMIT $3
-	`
-
-	rows, err := r.db.QueryContext(ctx, sqlQuery, searchPattern, query, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	recipes := make([]*model.Recipe, 0, limit)
-
-	for rows.Next() {
-		recipe := &model.Recipe{}
-		var sourceMetadata, nutritionJSON, dietaryInfoJSON []byte
-		var tags TextArray
-
-		err := rows.Scan(
-			&recipe.ID,
-			&recipe.UserID,
-			&recipe.Title,
-			&recipe.Description,
-			&recipe.Servings,
-			&recipe.PrepTime,
-			&recipe.CookTime,
-			&recipe.Difficulty,
-			&recipe.Cuisine,
-			&recipe.ThumbnailURL,
-			&recipe.SourceType,
-			&recipe.SourceURL,
-			&recipe.SourceRecipeID,
-			&sourceMetadata,
-			&tags,
-			&recipe.IsPublic,
-			&recipe.IsFavorite,
-			&recipe.IsFeatured,
-			&recipe.FeaturedAt,
-			&nutritionJSON,
-			&dietaryInfoJSON,
-			&recipe.SyncVersion,
-			&recipe.CreatedAt,
-			&recipe.UpdatedAt,
-			&recipe.IngredientCount,
-			&recipe.StepCount,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		recipe.Tags = []string(tags)
-
-		if sourceMetadata != nil {
-			unmarshalJSONB(sourceMetadata, &recipe.SourceMetadata, "source_metadata")
-		}
-		if nutritionJSON != nil {
-			recipe.Nutrition = &model.RecipeNutrition{}
-			unmarshalJSONB(nutritionJSON, recipe.Nutrition, "nutrition")
-		}
-		if dietaryInfoJSON != nil {
-			recipe.DietaryInfo = &model.DietaryInfo{}
-			unmarshalJSONB(dietaryInfoJSON, recipe.DietaryInfo, "dietary_info")
-		}
-
-		recipes = append(recipes, recipe)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return recipes, nil
-}
-
-// ListFeatured retrieves all featured recipes with ingredient/step counts
-func (r *RecipeRepository) ListFeatured(ctx context.Context, limit, offset int) ([]*model.Recipe, int, error) {
-	// Get total count
-	countQuery := `SELECT COUNT(*) FROM recipes WHERE is_featured = TRUE AND deleted_at IS NULL`
-	var total int
-	err := r.db.QueryRowContext(ctx, countQuery).Scan(&total)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Get featured recipes in random order
-	query := `
-		SELECT r.id, r.user_id, r.title, r.description, r.servings, r.prep_time, r.cook_time,
-			   r.difficulty, r.cuisine, r.thumbnail_url, r.source_type, r.source_url,
-			   r.source_recipe_id, r.source_metadata, r.tags, r.is_public, r.is_favorite,
-			   r.is_featured, r.featured_at,
-			   r.nutrition, r.dietary_info, r.sync_version, r.created_at, r.updated_at,
-			   COALESCE((SELECT COUNT(*) FROM recipe_ingredients WHERE recipe_id = r.id), 0) AS ingredient_count,
-			   COALESCE((SELECT COUNT(*) FROM recipe_steps WHERE recipe_id = r.id), 0) AS step_count
-		FROM recipes r
-		WHERE r.is_featured = TRUE AND r.deleted_at IS NULL
-		ORDER BY RANDOM()
-		LIMIT $1 OFFSET $2
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer rows.Close()
-
-	var recipes []*model.Recipe
-	for rows.Next() {
-		recipe := &model.Recipe{}
-		var sourceMetadata, nutritionJSON, dietaryInfoJSON []byte
-		var tags TextArray
-
-		err := rows.Scan(
-			&recipe.ID,
-			&recipe.UserID,
-			&recipe.Title,
-			&recipe.Description,
-			&recipe.Servings,
-			&recipe.PrepTime,
-			&recipe.CookTime,
-			&recipe.Difficulty,
-			&recipe.Cuisine,
-			&recipe.ThumbnailURL,
-			&recipe.SourceType,
-			&recipe.SourceURL,
-			&recipe.SourceRecipeID,
-			&sourceMetadata,
-			&tags,
-			&recipe.IsPublic,
-			&recipe.IsFavorite,
-			&recipe.IsFeatured,
-			&recipe.FeaturedAt,
-			&nutritionJSON,
-			&dietaryInfoJSON,
-			&recipe.SyncVersion,
-			&recipe.CreatedAt,
-			&recipe.UpdatedAt,
-			&recipe.IngredientCount,
-			&recipe.StepCount,
-		)
-		if err != nil {
-			return nil, 0, err
-		}
-
-		recipe.Tags = []string(tags)
-		if sourceMetadata != nil {
-			unmarshalJSONB(sourceMetadata, &recipe.SourceMetadata, "source_metadata")
-		}
-		if nutritionJSON != nil {
-			recipe.Nutrition = &model.RecipeNutrition{}
-			unmarshalJSONB(nutritionJSON, recipe.Nutrition, "nutrition")
-		}
-		if dietaryInfoJSON != nil {
-			recipe.DietaryInfo = &model.DietaryInfo{}
-			unmarshalJSONB(dietaryInfoJSON, recipe.DietaryInfo, "dietary_info")
-		}
-
-		recipes = append(recipes, recipe)
-	}
-
-	return recipes, total, rows.Err()
-}
@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
+)
+
+// Facet names tag the filter conditions each facet's counts leave out
+const (
+	facetCuisine    = "cuisine"
+	facetDifficulty = "difficulty"
+	facetSourceType = "sourceType"
+	facetTime       = "time"
+)
+
+// Shared SQL expressions for filtering and sorting recipes
+const (
+	totalTimeSQL = "NULLIF(COALESCE(r.prep_time, 0) + COALESCE(r.cook_time, 0), 0)"
+	caloriesSQL  = "NULLIF((r.nutrition->>'calories')::numeric, 0)"
+	// Cook log stats; the average is over rated cooks only
+	cookCountSQL  = "(SELECT COUNT(*) FROM cook_logs c WHERE c.recipe_id = r.id)"
+	lastCookedSQL = "(SELECT MAX(c.cooked_on) FROM cook_logs c WHERE c.recipe_id = r.id)"
+	avgRatingSQL  = "(SELECT AVG(c.rating)::float8 FROM cook_logs c WHERE c.recipe_id = r.id)"
+)
+
+// cookStatsColumns selects a recipe's cook log stats, scanned into
+// TimesCooked, LastCookedOn and AverageRating
+const cookStatsColumns = cookCountSQL + ", " + lastCookedSQL + ", " + avgRatingSQL
+
+// recipeConditions builds the WHERE clause for a filtered recipe list,
+// remembering which facet each condition belongs to
+type recipeConditions struct {
+	args  []any
+	conds []recipeCondition
+}
+
+type recipeCondition struct {
+	facet string
+	sql   string
+}
+
+// newRecipeConditions builds the conditions for a user's recipes matching f
+func newRecipeConditions(userID uuid.UUID, f model.RecipeFilter) *recipeConditions {
+	c := &recipeConditions{}
+	c.add("", "r.user_id = "+c.arg(userID))
+	c.add("", "r.deleted_at IS NULL")
+
+	if len(f.Cuisines) > 0 {
+		c.add(facetCuisine, "lower(r.cuisine) = ANY("+c.arg(lowered(f.Cuisines))+"::text[])")
+	}
+	if len(f.Difficulties) > 0 {
+		c.add(facetDifficulty, "lower(r.difficulty) = ANY("+c.arg(lowered(f.Difficulties))+"::text[])")
+	}
+	if len(f.SourceTypes) > 0 {
+		c.add(facetSourceType, "r.source_type = ANY("+c.arg(lowered(f.SourceTypes))+"::text[])")
+	}
+	if len(f.Tags) > 0 {
+		c.add("", "ARRAY(SELECT lower(t) FROM unnest(r.tags) t) @> "+c.arg(lowered(f.Tags))+"::text[]")
+	}
+	for _, diet := range f.Diets {
+		if flag, ok := model.DietFlags[strings.ToLower(diet)]; ok {
+			c.add("", dietaryFlagSQL(flag))
+		}
+	}
+	if len(f.ExcludeAllergens) > 0 {
+		allergens := lowered(f.ExcludeAllergens)
+		for _, a := range allergens {
+			if flag, ok := model.AllergenFreeFlags[a]; ok {
+				c.add("", dietaryFlagSQL(flag))
+			}
+		}
+		c.add("", `NOT EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(CASE WHEN jsonb_typeof(r.dietary_info->'allergens') = 'array'
+				THEN r.dietary_info->'allergens' ELSE '[]'::jsonb END) a
+			WHERE lower(a) = ANY(`+c.arg(allergens)+`::text[]))`)
+	}
+	if f.MaxTotalTime > 0 {
+		c.add(facetTime, totalTimeSQL+" <= "+c.arg(f.MaxTotalTime))
+	}
+	if f.MaxCalories > 0 {
+		c.add("", caloriesSQL+" <= "+c.arg(f.MaxCalories))
+	}
+	if f.FavoritesOnly {
+		c.add("", "r.is_favorite = TRUE")
+	}
+	return c
+}
+
+// arg adds a query argument and returns its placeholder
+func (c *recipeConditions) arg(v any) string {
+	c.args = append(c.args, v)
+	return "$" + strconv.Itoa(len(c.args))
+}
+
+func (c *recipeConditions) add(facet, sql string) {
+	c.conds = append(c.conds, recipeCondition{facet: facet, sql: sql})
+}
+
+// where joins the conditions, leaving out those of the except facet
+func (c *recipeConditions) where(except string) string {
+	parts := make([]string, 0, len(c.conds))
+	for _, cond := range c.conds {
+		if except == "" || cond.facet != except {
+			parts = append(parts, cond.sql)
+		}
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// dietaryFlagSQL requires a DietaryInfo flag; flag comes from a fixed map
+func dietaryFlagSQL(flag string) string {
+	return fmt.Sprintf("(r.dietary_info->>'%s')::boolean IS TRUE", flag)
+}
+
+// recipeKeyset returns the ordering for a sort. Recipes missing the sort
+// value come last, and every ordering ends in r.id so pages are stable.
+func recipeKeyset(sort string) pagination.Keyset {
+	newest := pagination.Key{SQL: "r.created_at", Type: "timestamptz", Desc: true}
+	id := pagination.Key{SQL: "r.id", Type: "uuid"}
+
+	switch sort {
+	case model.RecipeSortTitle:
+		return pagination.Keyset{Name: "recipes:" + sort, Keys: []pagination.Key{
+			{SQL: "lower(r.title)", Type: "text"}, id,
+		}}
+	case model.RecipeSortTime:
+		return pagination.Keyset{Name: "recipes:" + sort, Keys: []pagination.Key{
+			{SQL: "COALESCE(" + totalTimeSQL + ", 2147483647)", Type: "int"}, newest, id,
+		}}
+	case model.RecipeSortCalories:
+		return pagination.Keyset{Name: "recipes:" + sort, Keys: []pagination.Key{
+			{SQL: "COALESCE(" + caloriesSQL + ", 'Infinity')", Type: "numeric"}, newest, id,
+		}}
+	case model.RecipeSortMostCooked:
+		return pagination.Keyset{Name: "recipes:" + sort, Keys: []pagination.Key{
+			{SQL: cookCountSQL, Type: "bigint", Desc: true}, newest, id,
+		}}
+	case model.RecipeSortLastCooked:
+		return pagination.Keyset{Name: "recipes:" + sort, Keys: []pagination.Key{
+			{SQL: "COALESCE(" + lastCookedSQL + ", '-infinity')", Type: "date", Desc: true}, newest, id,
+		}}
+	default:
+		id.Desc = true
+		return pagination.Keyset{Name: "recipes:" + model.RecipeSortRecent, Keys: []pagination.Key{newest, id}}
+	}
+}
+
+func lowered(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToLower(strings.TrimSpace(v))
+	}
+	return out
+}
+
+// Facets counts the user's recipes per filter value. Alternatives
+// (cuisine, difficulty, source type, time) are counted without their own
+// filter; tags and diets, which must all hold, with every filter.
+func (r *RecipeRepository) Facets(ctx context.Context, userID uuid.UUID, filter model.RecipeFilter) (*model.RecipeFacets, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	c := newRecipeConditions(userID, filter)
+
+	diets := slices.Sorted(maps.Keys(model.DietFlags))
+	dietValues := make([]string, len(diets))
+	for i, diet := range diets {
+		dietValues[i] = fmt.Sprintf("('%s', '%s')", diet, model.DietFlags[diet])
+	}
+	buckets := make([]string, len(model.TotalTimeBuckets))
+	for i, b := range model.TotalTimeBuckets {
+		buckets[i] = fmt.Sprintf("(%d)", b)
+	}
+
+	// Every argument is used by the diet facet, which applies all filters
+	query := `
+		(SELECT 'cuisine', MIN(r.cuisine), COUNT(*) FROM recipes r
+		 WHERE ` + c.where(facetCuisine) + ` AND COALESCE(r.cuisine, '') <> ''
+		 GROUP BY lower(r.cuisine))
+		UNION ALL
+		(SELECT 'difficulty', MIN(r.difficulty), COUNT(*) FROM recipes r
+		 WHERE ` + c.where(facetDifficulty) + ` AND COALESCE(r.difficulty, '') <> ''
+		 GROUP BY lower(r.difficulty))
+		UNION ALL
+		(SELECT 'sourceType', r.source_type, COUNT(*) FROM recipes r
+		 WHERE ` + c.where(facetSourceType) + `
+		 GROUP BY r.source_type)
+		UNION ALL
+		(SELECT 'tag', MIN(t), COUNT(DISTINCT r.id) FROM recipes r, unnest(r.tags) t
+		 WHERE ` + c.where("") + ` AND t <> ''
+		 GROUP BY lower(t) ORDER BY 3 DESC, 2 LIMIT 20)
+		UNION ALL
+		(SELECT 'diet', d.value, COUNT(*) FROM recipes r
+		 CROSS JOIN (VALUES ` + strings.Join(dietValues, ", ") + `) d(value, flag)
+		 WHERE ` + c.where("") + ` AND (r.dietary_info->>d.flag)::boolean IS TRUE
+		 GROUP BY d.value)
+		UNION ALL
+		(SELECT 'time', b.minutes::text, COUNT(*) FROM recipes r
+		 CROSS JOIN (VALUES ` + strings.Join(buckets, ", ") + `) b(minutes)
+		 WHERE ` + c.where(facetTime) + ` AND ` + totalTimeSQL + ` <= b.minutes
+		 GROUP BY b.minutes)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, c.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facets := &model.RecipeFacets{
+		Cuisines:     []model.FacetCount{},
+		Difficulties: []model.FacetCount{},
+		SourceTypes:  []model.FacetCount{},
+		Tags:         []model.FacetCount{},
+		Diets:        []model.FacetCount{},
+		TotalTime:    []model.FacetCount{},
+	}
+	for rows.Next() {
+		var facet string
+		var fc model.FacetCount
+		if err := rows.Scan(&facet, &fc.Value, &fc.Count); err != nil {
+			return nil, err
+		}
+		switch facet {
+		case "cuisine":
+			facets.Cuisines = append(facets.Cuisines, fc)
+		case "difficulty":
+			facets.Difficulties = append(facets.Difficulties, fc)
+		case "sourceType":
+			facets.SourceTypes = append(facets.SourceTypes, fc)
+		case "tag":
+			facets.Tags = append(facets.Tags, fc)
+		case "diet":
+			facets.Diets = append(facets.Diets, fc)
+		case "time":
+			facets.TotalTime = append(facets.TotalTime, fc)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, list := range [][]model.FacetCount{facets.Cuisines, facets.Difficulties, facets.SourceTypes, facets.Tags, facets.Diets} {
+		slices.SortFunc(list, func(a, b model.FacetCount) int {
+			if a.Count != b.Count {
+				return b.Count - a.Count
+			}
+			return strings.Compare(a.Value, b.Value)
+		})
+	}
+	slices.SortFunc(facets.TotalTime, func(a, b model.FacetCount) int {
+		x, _ := strconv.Atoi(a.Value)
+		y, _ := strconv.Atoi(b.Value)
+		return x - y
+	})
+
+	return facets, nil
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
+)
+
+// recipeSummaryColumns are the columns of a recipe list row, with
+// ingredient and step counts instead of the ingredients and steps, and
+// cook log stats
+const recipeSummaryColumns = `
+	r.id, r.user_id, r.title, r.description, r.servings, r.prep_time, r.cook_time,
+	r.difficulty, r.cuisine, r.thumbnail_url, r.source_type, r.source_url,
+	r.source_recipe_id, r.source_metadata, r.tags, r.is_public, r.is_favorite,
+	r.is_featured, r.featured_at,
+	r.nutrition, r.dietary_info, r.sync_version, r.created_at, r.updated_at,
+	COALESCE((SELECT COUNT(*) FROM recipe_ingredients WHERE recipe_id = r.id), 0) AS ingredient_count,
+	COALESCE((SELECT COUNT(*) FROM recipe_steps WHERE recipe_id = r.id), 0) AS step_count,
+	` + cookStatsColumns
+
+// ListByUser retrieves a page of a user's recipes matching filter, in the
+// filter's sort order, with ingredient/step counts. It also returns the
+// number of matching recipes and the cursor for the next page, empty on
+// the last page.
+func (r *RecipeRepository) ListByUser(ctx context.Context, userID uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+	c := newRecipeConditions(userID, filter)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM recipes r WHERE ` + c.where("")
+	if err := r.db.QueryRowContext(ctx, countQuery, c.args...).Scan(&total); err != nil {
+		return nil, 0, "", err
+	}
+
+	recipes, next, err := r.listRecipes(ctx, `recipes r`, c.where(""), recipeKeyset(filter.Sort), page, c.args)
+	return recipes, total, next, err
+}
+
+// ListByCollection retrieves a page of the user's recipes in a collection
+// matching filter, in the collection's order unless filter sets a sort
+func (r *RecipeRepository) ListByCollection(ctx context.Context, collectionID, userID uuid.UUID, filter model.RecipeFilter, page pagination.Page) ([]*model.Recipe, int, string, error) {
+	const from = `collection_recipes cr JOIN recipes r ON r.id = cr.recipe_id`
+	c := newRecipeConditions(userID, filter)
+	c.add("", "cr.collection_id = "+c.arg(collectionID))
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+from+` WHERE `+c.where(""), c.args...).Scan(&total); err != nil {
+		return nil, 0, "", err
+	}
+
+	keyset := recipeKeyset(filter.Sort)
+	if filter.Sort == "" {
+		keyset = pagination.Keyset{Name: "collection", Keys: []pagination.Key{
+			{SQL: "cr.sort_order", Type: "int"},
+			{SQL: "cr.added_at", Type: "timestamptz"},
+			{SQL: "cr.recipe_id", Type: "uuid"},
+		}}
+	}
+	recipes, next, err := r.listRecipes(ctx, from, c.where(""), keyset, page, c.args)
+	return recipes, total, next, err
+}
+
+// ListPublic retrieves a page of public/suggested recipes with
+// ingredient/step counts, shuffled so users see different suggestions each
+// time they start from the first page
+func (r *RecipeRepository) ListPublic(ctx context.Context, page pagination.Page) ([]*model.Recipe, int, string, error) {
+	return r.listShuffled(ctx, "public", `r.is_public = TRUE AND r.deleted_at IS NULL`, page)
+}
+
+// ListFeatured retrieves a page of featured recipes with ingredient/step
+// counts, shuffled like ListPublic
+func (r *RecipeRepository) ListFeatured(ctx context.Context, page pagination.Page) ([]*model.Recipe, int, string, error) {
+	return r.listShuffled(ctx, "featured", `r.is_featured = TRUE AND r.deleted_at IS NULL`, page)
+}
+
+// listShuffled pages through recipes matching where in a random order. The
+// order is fixed by a seed picked for the first page and carried in the
+// cursor, so later pages neither repeat nor skip recipes. Pages asked for
+// by offset share one seed a day instead, so their offsets line up.
+func (r *RecipeRepository) listShuffled(ctx context.Context, name, where string, page pagination.Page) ([]*model.Recipe, int, string, error) {
+	seed := strconv.FormatUint(rand.Uint64(), 36)
+	if page.ByOffset() {
+		seed = time.Now().UTC().Format("20060102")
+	}
+	if page.Cursor != "" {
+		ordering, err := pagination.Ordering(page.Cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		var ok bool
+		if seed, ok = strings.CutPrefix(ordering, name+":"); !ok {
+			return nil, 0, "", pagination.ErrInvalidCursor
+		}
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM recipes r WHERE `+where).Scan(&total); err != nil {
+		return nil, 0, "", err
+	}
+
+	// The seed is always $1
+	keyset := pagination.Keyset{Name: name + ":" + seed, Keys: []pagination.Key{
+		{SQL: "md5(r.id::text || $1::text)", Type: "text"},
+		{SQL: "r.id", Type: "uuid"},
+	}}
+	recipes, next, err := r.listRecipes(ctx, `recipes r`, where, keyset, page, []any{seed})
+	for _, recipe := range recipes {
+		recipe.HideCookStats()
+	}
+	return recipes, total, next, err
+}
+
+// listRecipes retrieves a page of recipe summaries from the from clause
+// matching where, in keyset order. args holds where's arguments.
+func (r *RecipeRepository) listRecipes(ctx context.Context, from, where string, keyset pagination.Keyset, page pagination.Page, args []any) ([]*model.Recipe, string, error) {
+	args = append([]any(nil), args...)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if page.Cursor != "" {
+		position, err := keyset.Decode(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		where += ` AND ` + keyset.After(position, arg)
+	}
+
+	limit := page.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	// Fetch one extra row to know whether another page exists
+	query := `
+		SELECT ` + recipeSummaryColumns + `, ` + keyset.Columns() + `
+		FROM ` + from + `
+		WHERE ` + where + `
+		ORDER BY ` + keyset.OrderBy() + `
+		LIMIT ` + arg(limit+1) + page.Skip(arg)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var recipes []*model.Recipe
+	var keys [][]string
+	for rows.Next() {
+		key := make([]string, len(keyset.Keys))
+		dest := make([]any, len(key))
+		for i := range key {
+			dest[i] = &key[i]
+		}
+		recipe, err := scanRecipeSummary(rows, dest...)
+		if err != nil {
+			return nil, "", err
+		}
+
+		recipes = append(recipes, recipe)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	recipes, next := pagination.Next(keyset, recipes, keys, limit)
+	return recipes, next, nil
+}
+
+// scanRecipeSummary scans a row of recipeSummaryColumns followed by extra
+// columns into extra
+func scanRecipeSummary(row rowScanner, extra ...any) (*model.Recipe, error) {
+	recipe := &model.Recipe{}
+	var sourceMetadata, nutritionJSON, dietaryInfoJSON []byte
+	var tags TextArray
+
+	dest := []any{
+		&recipe.ID,
+		&recipe.UserID,
+		&recipe.Title,
+		&recipe.Description,
+		&recipe.Servings,
+		&recipe.PrepTime,
+		&recipe.CookTime,
+		&recipe.Difficulty,
+		&recipe.Cuisine,
+		&recipe.ThumbnailURL,
+		&recipe.SourceType,
+		&recipe.SourceURL,
+		&recipe.SourceRecipeID,
+		&sourceMetadata,
+		&tags,
+		&recipe.IsPublic,
+		&recipe.IsFavorite,
+		&recipe.IsFeatured,
+		&recipe.FeaturedAt,
+		&nutritionJSON,
+		&dietaryInfoJSON,
+		&recipe.SyncVersion,
+		&recipe.CreatedAt,
+		&recipe.UpdatedAt,
+		&recipe.IngredientCount,
+		&recipe.StepCount,
+		&recipe.TimesCooked,
+		&recipe.LastCookedOn,
+		&recipe.AverageRating,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	recipe.Tags = []string(tags)
+	if sourceMetadata != nil {
+		unmarshalJSONB(sourceMetadata, &recipe.SourceMetadata, "source_metadata")
+	}
+	if nutritionJSON != nil {
+		recipe.Nutrition = &model.RecipeNutrition{}
+		unmarshalJSONB(nutritionJSON, recipe.Nutrition, "nutrition")
+	}
+	if dietaryInfoJSON != nil {
+		recipe.DietaryInfo = &model.DietaryInfo{}
+		unmarshalJSONB(dietaryInfoJSON, recipe.DietaryInfo, "dietary_info")
+	}
+
+	return recipe, nil
+}
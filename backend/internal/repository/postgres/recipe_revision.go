@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+var (
+	ErrRevisionNotFound = errors.New("recipe revision not found")
+)
+
+// insertRevision appends a snapshot of recipe to its history
+func (r *RecipeRepository) insertRevision(ctx context.Context, tx *sql.Tx, recipe *model.Recipe, source model.RevisionSource, restoredFrom *uuid.UUID) (*model.RecipeRevision, error) {
+	snapshot, err := json.Marshal(recipe)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := &model.RecipeRevision{
+		ID:           uuid.New(),
+		RecipeID:     recipe.ID,
+		SyncVersion:  recipe.SyncVersion,
+		Source:       source,
+		RestoredFrom: restoredFrom,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO recipe_revisions (id, recipe_id, sync_version, source, restored_from, snapshot, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, rev.ID, rev.RecipeID, rev.SyncVersion, rev.Source, rev.RestoredFrom, snapshot, rev.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// ensureBaselineRevision snapshots a recipe that has no history yet
+func (r *RecipeRepository) ensureBaselineRevision(ctx context.Context, recipeID uuid.UUID) error {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM recipe_revisions WHERE recipe_id = $1)`, recipeID,
+	).Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+
+	current, err := r.GetByID(ctx, recipeID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := r.insertRevision(ctx, tx, current, model.RevisionSourceCreate, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ensureBaselineRevisionsTx snapshots, in tx, those of recipeIDs that have
+// no history yet. Callers lock the recipes and call it before changing them.
+func (r *RecipeRepository) ensureBaselineRevisionsTx(ctx context.Context, tx *sql.Tx, recipeIDs []uuid.UUID) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT ids.id FROM unnest($1::uuid[]) AS ids(id)
+		WHERE NOT EXISTS (SELECT 1 FROM recipe_revisions rv WHERE rv.recipe_id = ids.id)
+	`, recipeIDs)
+	if err != nil {
+		return err
+	}
+	missing, err := scanIDs(rows)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range missing {
+		current, err := r.getByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := r.insertRevision(ctx, tx, current, model.RevisionSourceCreate, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordRevisionsTx snapshots recipes changed outside update, in the
+// transaction that changed them, as update does for a single edit
+func (r *RecipeRepository) recordRevisionsTx(ctx context.Context, tx *sql.Tx, recipeIDs []uuid.UUID) error {
+	for _, id := range recipeIDs {
+		changed, err := r.getByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := r.insertRevision(ctx, tx, changed, model.RevisionSourceUpdate, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanIDs reads a single uuid column and closes rows
+func scanIDs(rows *sql.Rows) ([]uuid.UUID, error) {
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SaveConflictRevision keeps a client's version of a recipe that lost a sync
+// conflict, so the edit can be reviewed and restored instead of being lost
+func (r *RecipeRepository) SaveConflictRevision(ctx context.Context, clientRecipe *model.Recipe) (*model.RecipeRevision, error) {
+	if err := r.ensureBaselineRevision(ctx, clientRecipe.ID); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rev, err := r.insertRevision(ctx, tx, clientRecipe, model.RevisionSourceConflict, nil)
+	if err != nil {
+		return nil, err
+	}
+	return rev, tx.Commit()
+}
+
+// ListRevisions returns a recipe's history, newest first, without snapshots
+func (r *RecipeRepository) ListRevisions(ctx context.Context, recipeID uuid.UUID) ([]model.RecipeRevision, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, recipe_id, sync_version, source, restored_from, created_at,
+		       COALESCE(snapshot->>'title', ''),
+		       COALESCE(jsonb_array_length(NULLIF(snapshot->'ingredients', 'null'::jsonb)), 0),
+		       COALESCE(jsonb_array_length(NULLIF(snapshot->'steps', 'null'::jsonb)), 0)
+		FROM recipe_revisions
+		WHERE recipe_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []model.RecipeRevision{}
+	for rows.Next() {
+		var rev model.RecipeRevision
+		if err := rows.Scan(
+			&rev.ID,
+			&rev.RecipeID,
+			&rev.SyncVersion,
+			&rev.Source,
+			&rev.RestoredFrom,
+			&rev.CreatedAt,
+			&rev.Title,
+			&rev.IngredientCount,
+			&rev.StepCount,
+		); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// GetRevision returns one revision of a recipe including its snapshot
+func (r *RecipeRepository) GetRevision(ctx context.Context, recipeID, revisionID uuid.UUID) (*model.RecipeRevision, error) {
+	rev := &model.RecipeRevision{}
+	var snapshot []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, recipe_id, sync_version, source, restored_from, created_at, snapshot
+		FROM recipe_revisions
+		WHERE id = $1 AND recipe_id = $2
+	`, revisionID, recipeID).Scan(
+		&rev.ID,
+		&rev.RecipeID,
+		&rev.SyncVersion,
+		&rev.Source,
+		&rev.RestoredFrom,
+		&rev.CreatedAt,
+		&snapshot,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	rev.Snapshot = &model.Recipe{}
+	unmarshalJSONB(snapshot, rev.Snapshot, "recipe_revisions.snapshot")
+	rev.Title = rev.Snapshot.Title
+	rev.IngredientCount = len(rev.Snapshot.Ingredients)
+	rev.StepCount = len(rev.Snapshot.Steps)
+	return rev, nil
+}
@@ -0,0 +1,428 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/ingredient"
+)
+
+// searchLanguage maps an API language code to its text search
+// configuration and the recipe_search column stemmed with it
+type searchLanguage struct {
+	config string
+	column string
+}
+
+var searchLanguages = map[string]searchLanguage{
+	"en": {config: "english", column: "search_en"},
+	"fr": {config: "french", column: "search_fr"},
+	"es": {config: "spanish", column: "search_es"},
+}
+
+const (
+	// maxSearchTerms bounds the words turned into a tsquery
+	maxSearchTerms = 8
+	// fuzzyThreshold is the pg_trgm word similarity a typo needs to match
+	fuzzyThreshold = "0.5"
+
+	// Private-use characters mark highlights in ts_headline output, so they
+	// survive HTML escaping and can't be confused with recipe text
+	snippetStart = "\ue000"
+	snippetStop  = "\ue001"
+)
+
+var (
+	snippetOptions  = "StartSel=" + snippetStart + ", StopSel=" + snippetStop + ", MinWords=6, MaxWords=18, MaxFragments=2"
+	snippetReplacer = strings.NewReplacer(snippetStart, "<mark>", snippetStop, "</mark>")
+)
+
+// Search searches a user's recipes by title, tags, cuisine, ingredient
+// names and description. Words are stemmed for lang (en, fr, es), or for
+// all three when lang is empty, and the last word matches as a prefix so
+// partial input works. Near misses such as typos match by trigram
+// similarity. Results are ranked by relevance, favorites first on ties,
+// and carry a highlighted snippet when the description or ingredients
+// matched.
+func (r *RecipeRepository) Search(ctx context.Context, userID uuid.UUID, query, lang string, limit int) ([]*model.Recipe, error) {
+	return r.search(ctx, "r.user_id = $4", "r.is_favorite DESC", query, lang, limit, userID)
+}
+
+// SearchPublic searches public and featured recipes the same way as
+// Search, preferring featured recipes on ties.
+func (r *RecipeRepository) SearchPublic(ctx context.Context, query, lang string, limit int) ([]*model.Recipe, error) {
+	return r.search(ctx, "(r.is_public = TRUE OR r.is_featured = TRUE)", "r.is_featured DESC", query, lang, limit)
+}
+
+// search runs a ranked search over recipes matching scope. The tsquery,
+// raw query and limit are $1-$3; scope may use further args from $4.
+func (r *RecipeRepository) search(ctx context.Context, scope, tiebreak, query, lang string, limit int, args ...any) ([]*model.Recipe, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	query = strings.TrimSpace(query)
+	tsquery := searchTSQuery(query)
+	if tsquery == "" {
+		return []*model.Recipe{}, nil
+	}
+
+	langs := make([]searchLanguage, 0, len(searchLanguages))
+	if l, ok := searchLanguages[lang]; ok {
+		langs = append(langs, l)
+	} else {
+		for _, code := range model.SearchLanguages {
+			langs = append(langs, searchLanguages[code])
+		}
+	}
+
+	// Each language gets its own tsquery; a recipe is highlighted with the
+	// first language it matched in
+	var queries, matches, ranks []string
+	config, tsq := "CASE", "CASE"
+	for _, l := range langs {
+		match := fmt.Sprintf("s.%s @@ q.%s", l.column, l.column)
+		queries = append(queries, fmt.Sprintf("to_tsquery('%s', $1) AS %s", l.config, l.column))
+		matches = append(matches, match)
+		ranks = append(ranks, fmt.Sprintf("ts_rank_cd(s.%s, q.%s, 32)", l.column, l.column))
+		config += fmt.Sprintf(" WHEN %s THEN '%s'::regconfig", match, l.config)
+		tsq += fmt.Sprintf(" WHEN %s THEN q.%s", match, l.column)
+	}
+	config += fmt.Sprintf(" ELSE '%s'::regconfig END", langs[0].config)
+	tsq += fmt.Sprintf(" ELSE q.%s END", langs[0].column)
+
+	// Full-text rank is normalised to 0-1 and typo similarity adds up to
+	// half as much again, so exact matches outrank fuzzy ones
+	sqlQuery := `
+		WITH q AS (SELECT ` + strings.Join(queries, ", ") + `),
+		hits AS (
+			SELECT r.id,
+			       GREATEST(` + strings.Join(ranks, ", ") + `) + word_similarity($2, s.words) * 0.5 AS rank,
+			       ` + config + ` AS config,
+			       ` + tsq + ` AS tsq
+			FROM recipes r
+			JOIN recipe_search s ON s.recipe_id = r.id, q
+			WHERE ` + scope + `
+			  AND r.deleted_at IS NULL
+			  AND (` + strings.Join(matches, " OR ") + ` OR $2 <% s.words)
+			ORDER BY rank DESC, ` + tiebreak + `, r.title
+			LIMIT $3
+		)
+		SELECT r.id, r.user_id, r.title, r.description, r.servings, r.prep_time, r.cook_time,
+		       r.difficulty, r.cuisine, r.thumbnail_url, r.source_type, r.source_url,
+		       r.source_recipe_id, r.source_metadata, r.tags, r.is_public, r.is_favorite,
+		       r.is_featured, r.featured_at,
+		       r.nutrition, r.dietary_info, r.sync_version, r.created_at, r.updated_at,
+		       COALESCE((SELECT COUNT(*) FROM recipe_ingredients WHERE recipe_id = r.id), 0) AS ingredient_count,
+		       COALESCE((SELECT COUNT(*) FROM recipe_steps WHERE recipe_id = r.id), 0) AS step_count,
+		       ts_headline(h.config,
+		           concat_ws(' · ', r.description,
+		               (SELECT string_agg(name, ', ' ORDER BY sort_order) FROM recipe_ingredients WHERE recipe_id = r.id)),
+		           h.tsq, '` + snippetOptions + `') AS snippet
+		FROM hits h
+		JOIN recipes r ON r.id = h.id
+		ORDER BY h.rank DESC, ` + tiebreak + `, r.title
+	`
+
+	// The similarity threshold is per session, so scope it to a transaction
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SET LOCAL pg_trgm.word_similarity_threshold = "+fuzzyThreshold); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, sqlQuery, append([]any{tsquery, strings.ToLower(query), limit}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := make([]*model.Recipe, 0, limit)
+
+	for rows.Next() {
+		recipe := &model.Recipe{}
+		var sourceMetadata, nutritionJSON, dietaryInfoJSON []byte
+		var tags TextArray
+		var snippet string
+
+		err := rows.Scan(
+			&recipe.ID,
+			&recipe.UserID,
+			&recipe.Title,
+			&recipe.Description,
+			&recipe.Servings,
+			&recipe.PrepTime,
+			&recipe.CookTime,
+			&recipe.Difficulty,
+			&recipe.Cuisine,
+			&recipe.ThumbnailURL,
+			&recipe.SourceType,
+			&recipe.SourceURL,
+			&recipe.SourceRecipeID,
+			&sourceMetadata,
+			&tags,
+			&recipe.IsPublic,
+			&recipe.IsFavorite,
+			&recipe.IsFeatured,
+			&recipe.FeaturedAt,
+			&nutritionJSON,
+			&dietaryInfoJSON,
+			&recipe.SyncVersion,
+			&recipe.CreatedAt,
+			&recipe.UpdatedAt,
+			&recipe.IngredientCount,
+			&recipe.StepCount,
+			&snippet,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		recipe.Tags = []string(tags)
+		recipe.Snippet = highlightSnippet(snippet)
+
+		if sourceMetadata != nil {
+			unmarshalJSONB(sourceMetadata, &recipe.SourceMetadata, "source_metadata")
+		}
+		if nutritionJSON != nil {
+			recipe.Nutrition = &model.RecipeNutrition{}
+			unmarshalJSONB(nutritionJSON, recipe.Nutrition, "nutrition")
+		}
+		if dietaryInfoJSON != nil {
+			recipe.DietaryInfo = &model.DietaryInfo{}
+			unmarshalJSONB(dietaryInfoJSON, recipe.DietaryInfo, "dietary_info")
+		}
+
+		recipes = append(recipes, recipe)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return recipes, tx.Commit()
+}
+
+// searchTSQuery turns free text into to_tsquery input: words joined with
+// AND, the last one as a prefix. Everything but letters and digits is
+// dropped, so user input can't inject tsquery operators.
+func searchTSQuery(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+	words[len(words)-1] += ":*"
+	return strings.Join(words, " & ")
+}
+
+// highlightSnippet escapes ts_headline output for HTML and turns its
+// markers into <mark> tags. Snippets without a highlight only repeat the
+// description, so they are dropped.
+func highlightSnippet(s string) string {
+	if !strings.Contains(s, snippetStart) {
+		return ""
+	}
+	return snippetReplacer.Replace(html.EscapeString(s))
+}
+
+// SearchByIngredients finds recipes that use the searched ingredients,
+// ranked by coverage: the share of a recipe's required ingredients the
+// user has. Names are compared by ingredient.MatchKey, whole words at a
+// time, in either direction ("chicken" covers "chicken thighs" and
+// "chicken breast" covers "chicken"), and common substitutes count too.
+// Recipes using any excluded ingredient are left out.
+func (r *RecipeRepository) SearchByIngredients(ctx context.Context, userID uuid.UUID, search model.IngredientSearch) ([]*model.IngredientSearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	limit := search.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > model.MaxIngredientSearchLimit {
+		limit = model.MaxIngredientSearchLimit
+	}
+
+	// Each searched ingredient expands to its key and its substitutes'
+	// keys, all pointing back at the ingredient's index
+	var keys []string
+	var indexes []int
+	var substitutes []bool
+	for i, name := range search.Include {
+		key := ingredient.MatchKey(name)
+		if key == "" {
+			continue
+		}
+		keys, indexes, substitutes = append(keys, key), append(indexes, i), append(substitutes, false)
+		for _, sub := range ingredient.Substitutes(key) {
+			keys, indexes, substitutes = append(keys, ingredient.MatchKey(sub)), append(indexes, i), append(substitutes, true)
+		}
+	}
+	if len(keys) == 0 {
+		return []*model.IngredientSearchResult{}, nil
+	}
+
+	excluded := []string{}
+	for _, name := range search.Exclude {
+		if key := ingredient.MatchKey(name); key != "" {
+			excluded = append(excluded, `\m`+key+`\M`)
+		}
+	}
+
+	var scope string
+	switch search.Scope {
+	case model.IngredientSearchLibrary:
+		scope = "r.user_id = $1"
+	case model.IngredientSearchPublic:
+		scope = "(r.is_public = TRUE OR r.is_featured = TRUE) AND r.user_id <> $1"
+	default:
+		scope = "(r.user_id = $1 OR r.is_public = TRUE OR r.is_featured = TRUE)"
+	}
+
+	// Each recipe ingredient takes the first searched ingredient covering
+	// it, preferring direct matches over substitutes
+	query := `
+		WITH terms AS (
+			SELECT * FROM unnest($2::text[], $3::int[], $4::bool[]) AS t(key, idx, substitute)
+		),
+		candidates AS (
+			SELECT r.id
+			FROM recipes r
+			WHERE ` + scope + `
+			  AND r.deleted_at IS NULL
+			  AND NOT EXISTS (
+			      SELECT 1 FROM recipe_ingredients x
+			      WHERE x.recipe_id = r.id AND x.match_name ~ ANY($5::text[])
+			  )
+		),
+		covered AS (
+			SELECT ri.recipe_id, ri.name, ri.sort_order,
+			       COALESCE(ri.is_optional, FALSE) AS optional,
+			       m.idx, m.substitute
+			FROM candidates c
+			JOIN recipe_ingredients ri ON ri.recipe_id = c.id
+			LEFT JOIN LATERAL (
+				SELECT t.idx, t.substitute
+				FROM terms t
+				WHERE ri.match_name <> ''
+				  AND (ri.match_name ~ ('\m' || t.key || '\M') OR t.key ~ ('\m' || ri.match_name || '\M'))
+				ORDER BY t.substitute, t.idx
+				LIMIT 1
+			) m ON TRUE
+		),
+		scored AS (
+			SELECT recipe_id,
+			       CASE WHEN COUNT(*) FILTER (WHERE NOT optional) > 0
+			            THEN COUNT(idx) FILTER (WHERE NOT optional) * 100 / COUNT(*) FILTER (WHERE NOT optional)
+			            ELSE COUNT(idx) * 100 / COUNT(*)
+			       END AS coverage,
+			       COUNT(DISTINCT idx) AS terms_used,
+			       json_agg(json_build_object(
+			           'name', name, 'optional', optional, 'idx', idx, 'substitute', substitute
+			       ) ORDER BY sort_order) AS ingredients
+			FROM covered
+			GROUP BY recipe_id
+			HAVING COUNT(idx) > 0
+		)
+		SELECT r.id, r.user_id, r.title, r.description, r.servings, r.prep_time, r.cook_time,
+		       r.difficulty, r.cuisine, r.thumbnail_url, r.tags, r.is_public, r.is_favorite,
+		       r.is_featured, s.coverage, s.terms_used, s.ingredients
+		FROM scored s
+		JOIN recipes r ON r.id = s.recipe_id
+		ORDER BY s.coverage DESC, s.terms_used DESC, (r.user_id = $1) DESC, r.is_favorite DESC, r.title
+		LIMIT $6
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, keys, indexes, substitutes, excluded, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*model.IngredientSearchResult, 0, limit)
+	for rows.Next() {
+		recipe := &model.Recipe{}
+		result := &model.IngredientSearchResult{Recipe: recipe}
+		var tags TextArray
+		var ingredientsJSON []byte
+
+		err := rows.Scan(
+			&recipe.ID,
+			&recipe.UserID,
+			&recipe.Title,
+			&recipe.Description,
+			&recipe.Servings,
+			&recipe.PrepTime,
+			&recipe.CookTime,
+			&recipe.Difficulty,
+			&recipe.Cuisine,
+			&recipe.ThumbnailURL,
+			&tags,
+			&recipe.IsPublic,
+			&recipe.IsFavorite,
+			&recipe.IsFeatured,
+			&result.Coverage,
+			&result.TermsUsed,
+			&ingredientsJSON,
+		)
+		if err != nil {
+			return nil, err
+		}
+		recipe.Tags = []string(tags)
+		if recipe.UserID != userID {
+			recipe.IsFavorite = false // the owner's favorite, not this user's
+		}
+
+		var ingredients []struct {
+			Name       string `json:"name"`
+			Optional   bool   `json:"optional"`
+			Idx        *int   `json:"idx"`
+			Substitute bool   `json:"substitute"`
+		}
+		unmarshalJSONB(ingredientsJSON, &ingredients, "ingredients")
+		recipe.IngredientCount = len(ingredients)
+		for _, ing := range ingredients {
+			switch {
+			case ing.Idx != nil && *ing.Idx < len(search.Include):
+				result.Matched = append(result.Matched, model.IngredientSearchMatch{
+					Ingredient:   ing.Name,
+					Term:         strings.TrimSpace(search.Include[*ing.Idx]),
+					IsSubstitute: ing.Substitute,
+				})
+			case !ing.Optional:
+				result.Missing = append(result.Missing, ing.Name)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
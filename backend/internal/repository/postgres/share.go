@@ -0,0 +1,244 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+var (
+	ErrShareNotFound = errors.New("recipe share not found")
+	ErrShareExists   = errors.New("recipe already shared with this user")
+	ErrSelfShare     = errors.New("cannot share a recipe with yourself")
+)
+
+// ShareRepository handles recipe share data access
+type ShareRepository struct {
+	db *sql.DB
+}
+
+// NewShareRepository creates a new share repository
+func NewShareRepository(db *sql.DB) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+// shareColumns selects a share with its recipe and owner for display.
+// The query must alias recipe_shares as s, recipes as r and users as u.
+const shareColumns = `
+	s.id, s.recipe_id, s.owner_id, s.recipient_id, s.recipient_email, s.status,
+	s.created_at, s.accepted_at, s.declined_at, s.accepted_recipe_id,
+	r.title, r.thumbnail_url, u.name
+`
+
+func scanShare(row rowScanner) (*model.RecipeShare, error) {
+	s := &model.RecipeShare{}
+	err := row.Scan(
+		&s.ID, &s.RecipeID, &s.OwnerID, &s.RecipientID, &s.RecipientEmail, &s.Status,
+		&s.CreatedAt, &s.AcceptedAt, &s.DeclinedAt, &s.AcceptedRecipeID,
+		&s.RecipeTitle, &s.RecipeThumbnailURL, &s.OwnerName,
+	)
+	return s, err
+}
+
+// Create shares a recipe. An email that belongs to a registered user is
+// resolved to that user; otherwise the share waits for someone to sign up
+// with it. A declined share can be sent again; a pending or accepted one can't.
+// A recipient that resolves to the owner is rejected with ErrSelfShare.
+func (r *ShareRepository) Create(ctx context.Context, share *model.RecipeShare) error {
+	if share.RecipientID != nil {
+		var exists bool
+		err := r.db.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND deleted_at IS NULL)`, *share.RecipientID,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrUserNotFound
+		}
+	} else if share.RecipientEmail != nil {
+		var id uuid.UUID
+		err := r.db.QueryRowContext(ctx,
+			`SELECT id FROM users WHERE LOWER(email) = LOWER($1) AND deleted_at IS NULL`, *share.RecipientEmail,
+		).Scan(&id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err == nil {
+			share.RecipientID = &id
+		}
+	}
+	if share.RecipientID != nil && *share.RecipientID == share.OwnerID {
+		return ErrSelfShare
+	}
+
+	conflict := `ON CONFLICT (recipe_id, recipient_id)`
+	if share.RecipientID == nil {
+		conflict = `ON CONFLICT (recipe_id, LOWER(recipient_email)) WHERE recipient_id IS NULL`
+	}
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO recipe_shares (recipe_id, owner_id, recipient_id, recipient_email, status)
+		VALUES ($1, $2, $3, $4, 'pending')
+		`+conflict+` DO UPDATE SET
+			status = 'pending', owner_id = EXCLUDED.owner_id, created_at = NOW(), declined_at = NULL
+		WHERE recipe_shares.status = 'declined'
+		RETURNING id, status, created_at
+	`, share.RecipeID, share.OwnerID, share.RecipientID, share.RecipientEmail,
+	).Scan(&share.ID, &share.Status, &share.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrShareExists
+	}
+	return err
+}
+
+// GetByID returns a share by ID
+func (r *ShareRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.RecipeShare, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+shareColumns+`
+		FROM recipe_shares s
+		JOIN recipes r ON r.id = s.recipe_id
+		JOIN users u ON u.id = s.owner_id
+		WHERE s.id = $1
+	`, id)
+
+	share, err := scanShare(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// ListInbox returns shares sent to a user, including ones sent to their
+// email before they signed up, newest first
+func (r *ShareRepository) ListInbox(ctx context.Context, userID uuid.UUID, email string, status model.ShareStatus) ([]*model.RecipeShare, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+shareColumns+`
+		FROM recipe_shares s
+		JOIN recipes r ON r.id = s.recipe_id AND r.deleted_at IS NULL
+		JOIN users u ON u.id = s.owner_id
+		WHERE (s.recipient_id = $1
+		       OR (s.recipient_id IS NULL AND $2 <> '' AND LOWER(s.recipient_email) = LOWER($2)))
+		  AND s.status = $3
+		ORDER BY s.created_at DESC
+	`, userID, email, status)
+	if err != nil {
+		return nil, err
+	}
+	return scanShares(rows)
+}
+
+// ListSent returns the shares a user has sent, optionally for one recipe
+func (r *ShareRepository) ListSent(ctx context.Context, ownerID uuid.UUID, recipeID *uuid.UUID) ([]*model.RecipeShare, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+shareColumns+`
+		FROM recipe_shares s
+		JOIN recipes r ON r.id = s.recipe_id AND r.deleted_at IS NULL
+		JOIN users u ON u.id = s.owner_id
+		WHERE s.owner_id = $1 AND ($2::uuid IS NULL OR s.recipe_id = $2)
+		ORDER BY s.created_at DESC
+	`, ownerID, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	return scanShares(rows)
+}
+
+func scanShares(rows *sql.Rows) ([]*model.RecipeShare, error) {
+	defer rows.Close()
+
+	shares := []*model.RecipeShare{}
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, rows.Err()
+}
+
+// Accept claims a pending share for the recipient by marking it accepted.
+// Only one caller can claim a share, so the copy is made after this succeeds
+// and linked with SetAcceptedRecipe. Shares sent by email are claimed by the
+// recipient.
+func (r *ShareRepository) Accept(ctx context.Context, id, recipientID uuid.UUID) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE recipe_shares
+		SET status = 'accepted', recipient_id = $2, accepted_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING id
+	`, id, recipientID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrShareNotFound
+	}
+	return shareError(err)
+}
+
+// SetAcceptedRecipe records the copy the recipient received for an accepted share
+func (r *ShareRepository) SetAcceptedRecipe(ctx context.Context, id, recipeID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE recipe_shares SET accepted_recipe_id = $2
+		WHERE id = $1 AND status = 'accepted'
+	`, id, recipeID)
+	return shareRowAffected(result, err)
+}
+
+// Reopen returns a claimed share to pending when the recipient's copy
+// couldn't be made, so they can accept it again
+func (r *ShareRepository) Reopen(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE recipe_shares SET status = 'pending', accepted_at = NULL
+		WHERE id = $1 AND status = 'accepted' AND accepted_recipe_id IS NULL
+	`, id)
+	return shareRowAffected(result, err)
+}
+
+// Decline marks a pending share declined by the recipient
+func (r *ShareRepository) Decline(ctx context.Context, id, recipientID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE recipe_shares
+		SET status = 'declined', recipient_id = $2, declined_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id, recipientID)
+	return shareRowAffected(result, err)
+}
+
+// Delete revokes a share. Copies already accepted are kept by the recipient.
+func (r *ShareRepository) Delete(ctx context.Context, id, ownerID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM recipe_shares WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	return shareRowAffected(result, err)
+}
+
+func shareRowAffected(result sql.Result, err error) error {
+	if err != nil {
+		return shareError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrShareNotFound
+	}
+	return nil
+}
+
+func shareError(err error) error {
+	// Claiming an email share for a user who already has a share of the
+	// same recipe
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrShareExists
+	}
+	return err
+}
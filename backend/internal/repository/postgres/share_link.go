@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+var ErrShareLinkNotFound = errors.New("share link not found")
+
+// ShareLinkRepository handles public recipe share links
+type ShareLinkRepository struct {
+	db *sql.DB
+}
+
+// NewShareLinkRepository creates a new share link repository
+func NewShareLinkRepository(db *sql.DB) *ShareLinkRepository {
+	return &ShareLinkRepository{db: db}
+}
+
+const shareLinkColumns = `id, recipe_id, user_id, token, view_count, last_viewed_at, created_at, revoked_at`
+
+func scanShareLink(row rowScanner) (*model.RecipeShareLink, error) {
+	l := &model.RecipeShareLink{}
+	err := row.Scan(&l.ID, &l.RecipeID, &l.UserID, &l.Token, &l.ViewCount, &l.LastViewedAt, &l.CreatedAt, &l.RevokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrShareLinkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// GetActive returns the recipe's active link
+func (r *ShareLinkRepository) GetActive(ctx context.Context, recipeID uuid.UUID) (*model.RecipeShareLink, error) {
+	return scanShareLink(r.db.QueryRowContext(ctx, `
+		SELECT `+shareLinkColumns+`
+		FROM recipe_share_links
+		WHERE recipe_id = $1 AND revoked_at IS NULL
+	`, recipeID))
+}
+
+// Create returns the recipe's active link, creating one with a new token if
+// it has none
+func (r *ShareLinkRepository) Create(ctx context.Context, recipeID, userID uuid.UUID) (*model.RecipeShareLink, error) {
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// The no-op update makes RETURNING yield the existing link on conflict
+	return scanShareLink(r.db.QueryRowContext(ctx, `
+		INSERT INTO recipe_share_links (recipe_id, user_id, token)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (recipe_id) WHERE revoked_at IS NULL
+		DO UPDATE SET recipe_id = EXCLUDED.recipe_id
+		RETURNING `+shareLinkColumns,
+		recipeID, userID, token))
+}
+
+// Revoke disables the recipe's active link. Its token stops working at once;
+// creating a link again issues a new token.
+func (r *ShareLinkRepository) Revoke(ctx context.Context, recipeID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE recipe_share_links SET revoked_at = NOW()
+		WHERE recipe_id = $1 AND revoked_at IS NULL
+	`, recipeID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrShareLinkNotFound
+	}
+	return nil
+}
+
+// RecordView resolves an active token and counts the view. Links to deleted
+// recipes are treated as not found.
+func (r *ShareLinkRepository) RecordView(ctx context.Context, token string) (*model.RecipeShareLink, error) {
+	return scanShareLink(r.db.QueryRowContext(ctx, `
+		UPDATE recipe_share_links l
+		SET view_count = l.view_count + 1, last_viewed_at = NOW()
+		FROM recipes r
+		WHERE l.token = $1 AND l.revoked_at IS NULL
+		  AND r.id = l.recipe_id AND r.deleted_at IS NULL
+		RETURNING l.id, l.recipe_id, l.user_id, l.token, l.view_count, l.last_viewed_at, l.created_at, l.revoked_at
+	`, token))
+}
+
+// newShareToken returns 128 random bits, URL-safe encoded
+func newShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -3,12 +3,14 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/pagination"
 )
 
 // ShoppingRepository handles shopping list data access
@@ -23,43 +25,80 @@ func NewShoppingRepository(db *sql.DB) *ShoppingRepository {
 
 // ===== Shopping Lists =====
 
-// ListLists returns all shopping lists for a user
-func (r *ShoppingRepository) ListLists(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]*model.ShoppingList, error) {
+// shoppingListKeyset orders shopping lists newest first
+var shoppingListKeyset = pagination.Keyset{Name: "shopping-lists", Keys: []pagination.Key{
+	{SQL: "created_at", Type: "timestamptz", Desc: true},
+	{SQL: "id", Type: "uuid", Desc: true},
+}}
+
+// ListLists returns a page of a user's shopping lists, newest first, and the
+// cursor for the next page, empty on the last page. A page without a limit
+// holds every list.
+func (r *ShoppingRepository) ListLists(ctx context.Context, userID uuid.UUID, includeArchived bool, page pagination.Page) ([]*model.ShoppingList, string, error) {
 	query := `
 		SELECT id, user_id, name, description, icon, is_template, is_archived,
-		       sync_version, created_at, updated_at, deleted_at
+		       sync_version, created_at, updated_at, deleted_at, ` + shoppingListKeyset.Columns() + `
 		FROM shopping_lists
 		WHERE user_id = $1 AND deleted_at IS NULL
 	`
 	args := []interface{}{userID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
 
 	if !includeArchived {
 		query += ` AND is_archived = false`
 	}
 
-	query += ` ORDER BY created_at DESC`
+	if page.Cursor != "" {
+		position, err := shoppingListKeyset.Decode(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += ` AND ` + shoppingListKeyset.After(position, arg)
+	}
+
+	// Fetch one extra row to know whether another page exists. Without a
+	// limit every list is returned.
+	query += ` ORDER BY ` + shoppingListKeyset.OrderBy()
+	if page.Limit > 0 {
+		query += ` LIMIT ` + arg(page.Limit+1)
+	}
+	query += page.Skip(arg)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
 	var lists []*model.ShoppingList
+	var keys [][]string
 	for rows.Next() {
 		list := &model.ShoppingList{}
+		key := make([]string, 2)
 		err := rows.Scan(
 			&list.ID, &list.UserID, &list.Name, &list.Description, &list.Icon,
 			&list.IsTemplate, &list.IsArchived, &list.SyncVersion,
 			&list.CreatedAt, &list.UpdatedAt, &list.DeletedAt,
+			&key[0], &key[1],
 		)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		lists = append(lists, list)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
 	}
 
-	return lists, rows.Err()
+	if page.Limit <= 0 {
+		return lists, "", nil
+	}
+	lists, next := pagination.Next(shoppingListKeyset, lists, keys, page.Limit)
+	return lists, next, nil
 }
 
 // GetList returns a single shopping list by ID
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// TagRepository handles tags across a user's recipe library
+type TagRepository struct {
+	db      *sql.DB
+	recipes *RecipeRepository
+}
+
+// NewTagRepository creates a new tag repository. Recipes it retags get a
+// revision through recipes, as an edit would.
+func NewTagRepository(db *sql.DB, recipes *RecipeRepository) *TagRepository {
+	return &TagRepository{db: db, recipes: recipes}
+}
+
+// List retrieves every tag on the user's recipes, spelled as stored, with
+// the number of recipes carrying it, most used first
+func (r *TagRepository) List(ctx context.Context, userID uuid.UUID) ([]model.TagCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t, COUNT(DISTINCT r.id)
+		FROM recipes r, unnest(r.tags) t
+		WHERE r.user_id = $1 AND r.deleted_at IS NULL AND btrim(t) <> ''
+		GROUP BY t
+		ORDER BY 2 DESC, 1
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []model.TagCount{}
+	for rows.Next() {
+		var tc model.TagCount
+		if err := rows.Scan(&tc.Tag, &tc.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tc)
+	}
+	return tags, rows.Err()
+}
+
+// Replace rewrites tags across the user's recipes: each key of replacements
+// becomes its value, or is removed where the value is empty. Tags that end
+// up the same are kept once, in the position of the first. It returns the
+// number of recipes changed.
+func (r *TagRepository) Replace(ctx context.Context, userID uuid.UUID, replacements map[string]string) (int, error) {
+	if len(replacements) == 0 {
+		return 0, nil
+	}
+	from := make([]string, 0, len(replacements))
+	to := make([]string, 0, len(replacements))
+	for f, t := range replacements {
+		from = append(from, f)
+		to = append(to, t)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Lock the recipes to retag and snapshot any without history first, so
+	// the revisions written below have a baseline to be compared against
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id FROM recipes
+		WHERE user_id = $1 AND deleted_at IS NULL AND tags && $2::text[]
+		FOR UPDATE
+	`, userID, from)
+	if err != nil {
+		return 0, err
+	}
+	ids, err := scanIDs(rows)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	if err := r.recipes.ensureBaselineRevisionsTx(ctx, tx, ids); err != nil {
+		return 0, err
+	}
+
+	rows, err = tx.QueryContext(ctx, `
+		WITH m AS (
+			SELECT * FROM unnest($2::text[], $3::text[]) AS m(old, new)
+		), changed AS (
+			SELECT r.id, ARRAY(
+				SELECT x.tag FROM (
+					SELECT COALESCE(m.new, t.tag) AS tag, MIN(t.ord) AS ord
+					FROM unnest(r.tags) WITH ORDINALITY AS t(tag, ord)
+					LEFT JOIN m ON m.old = t.tag
+					GROUP BY 1
+				) x
+				WHERE btrim(x.tag) <> ''
+				ORDER BY x.ord
+			) AS tags
+			FROM recipes r
+			WHERE r.id = ANY($1::uuid[])
+		)
+		UPDATE recipes r
+		SET tags = c.tags, sync_version = r.sync_version + 1
+		FROM changed c
+		WHERE r.id = c.id
+		RETURNING r.id
+	`, ids, from, to)
+	if err != nil {
+		return 0, err
+	}
+	changed, err := scanIDs(rows)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.recipes.recordRevisionsTx(ctx, tx, changed); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(changed), nil
+}
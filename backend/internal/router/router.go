@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/redis/go-redis/v9"
@@ -14,9 +15,12 @@ import (
 	"github.com/dishflow/backend/internal/config"
 	"github.com/dishflow/backend/internal/handler"
 	"github.com/dishflow/backend/internal/middleware"
+	"github.com/dishflow/backend/internal/pkg/tag"
 	"github.com/dishflow/backend/internal/repository/postgres"
 	"github.com/dishflow/backend/internal/service/ai"
+	"github.com/dishflow/backend/internal/service/nutrition"
 	"github.com/dishflow/backend/internal/service/revenuecat"
+	"github.com/dishflow/backend/internal/service/scheduler"
 	"github.com/dishflow/backend/internal/service/sync"
 	"github.com/dishflow/backend/internal/service/thumbnail"
 	"github.com/dishflow/backend/internal/service/video"
@@ -57,8 +61,10 @@ func New(cfg *config.Config, logger *slog.Logger, db *sql.DB, redis *redis.Clien
 	adminHandler := handler.NewAdminHandler(db, cfg.AdminAPIKey, cfg.AdminEmails)
 
 	// Services
-	recipeRepo := postgres.NewRecipeRepository(db)
-	recipeHandler := handler.NewRecipeHandler(recipeRepo, cfg.AdminEmails, cfg.InspiratorEmails)
+	tagNormalizer := tag.NewNormalizer(cfg.TagSynonyms)
+	recipeRepo := postgres.NewRecipeRepository(db, tagNormalizer)
+	annotationRepo := postgres.NewAnnotationRepository(db)
+	recipeHandler := handler.NewRecipeHandler(recipeRepo, annotationRepo, cfg.AdminEmails, cfg.InspiratorEmails)
 
 	// Initialize AI service
 	geminiCtx := context.Background()
@@ -97,11 +103,19 @@ func New(cfg *config.Config, logger *slog.Logger, db *sql.DB, redis *redis.Clien
 	}
 	shoppingHandler := handler.NewShoppingHandler(shoppingRepo, recipeRepo, userRepo, shoppingAnalyzer)
 
+	collectionRepo := postgres.NewCollectionRepository(db)
+	collectionHandler := handler.NewCollectionHandler(collectionRepo, recipeRepo)
+
+	shareRepo := postgres.NewShareRepository(db)
+	shareHandler := handler.NewShareHandler(shareRepo, recipeRepo, userRepo, cfg.AdminEmails)
+	shareLinkRepo := postgres.NewShareLinkRepository(db)
+	shareLinkHandler := handler.NewShareLinkHandler(shareLinkRepo, recipeRepo, cfg.BaseURL)
+
 	mealPlanRepo := postgres.NewMealPlanRepository(db)
 	mealPlanHandler := handler.NewMealPlanHandler(mealPlanRepo, shoppingRepo, pantryRepo)
 
 	// Initialize sync service
-	syncService := sync.NewService(recipeRepo, pantryRepo, shoppingRepo)
+	syncService := sync.NewService(recipeRepo, pantryRepo, shoppingRepo, collectionRepo, annotationRepo)
 	syncHandler := handler.NewSyncHandler(syncService)
 
 	// Initialize recommendations handler
@@ -113,6 +127,22 @@ func New(cfg *config.Config, logger *slog.Logger, db *sql.DB, redis *redis.Clien
 		logger.Error("Failed to create thumbnail directory", "error", err)
 	}
 	thumbnailHandler := handler.NewThumbnailHandler(cfg.ThumbnailDir)
+	exportHandler := handler.NewExportHandler(recipeRepo, thumbDownloader, logger)
+	cookLogRepo := postgres.NewCookLogRepository(db)
+	cookLogHandler := handler.NewCookLogHandler(cookLogRepo, recipeRepo, thumbDownloader)
+	cookSessionHandler := handler.NewCookSessionHandler(postgres.NewCookSessionRepository(db), recipeRepo, cookLogRepo, pantryRepo)
+	annotationHandler := handler.NewAnnotationHandler(annotationRepo, recipeRepo)
+	duplicateHandler := handler.NewDuplicateHandler(postgres.NewDuplicateRepository(db, recipeRepo), recipeRepo)
+	tagHandler := handler.NewTagHandler(postgres.NewTagRepository(db, recipeRepo), tagNormalizer)
+
+	// Nutrition is computed from the USDA food data; the recommender's AI
+	// estimate only covers ingredients no food matches
+	var nutritionEstimator nutrition.Estimator
+	if recommender != nil {
+		nutritionEstimator = recommender
+	}
+	nutritionCalculator := nutrition.NewCalculator(postgres.NewFoodRepository(db), nutritionEstimator)
+	nutritionHandler := handler.NewNutritionHandler(recipeRepo, nutritionCalculator)
 
 	jobRepo := postgres.NewJobRepository(db)
 	downloader := video.NewDownloader(os.TempDir())
@@ -126,7 +156,16 @@ func New(cfg *config.Config, logger *slog.Logger, db *sql.DB, redis *redis.Clien
 	// Unified extraction handler (handles url, image, video extraction with async jobs)
 	// Also handles job listing, status, and cancellation
 	// Now includes enrichment and caching support
-	unifiedExtractionHandler := handler.NewUnifiedExtractionHandler(jobRepo, recipeRepo, userRepo, extractor, enricher, extractionCacheRepo, downloader, instagramDownloader, thumbDownloader, redis, logger, cfg.AdminEmails, cfg.InspiratorEmails, cfg.MaxConcurrentVideoJobs, cfg.MaxConcurrentLightJobs)
+	jobScheduler := scheduler.New(scheduler.Config{
+		Lanes: []scheduler.LaneConfig{
+			{Name: scheduler.LaneVideo, Slots: cfg.MaxConcurrentVideoJobs, ExpectedDuration: 45 * time.Second},
+			{Name: scheduler.LaneLight, Slots: cfg.MaxConcurrentLightJobs, ExpectedDuration: 15 * time.Second},
+		},
+		PerUserLimit:     cfg.MaxJobsPerUser,
+		BulkPerUserLimit: cfg.MaxBulkJobsPerUser,
+		AgingInterval:    cfg.JobQueueAging,
+	})
+	unifiedExtractionHandler := handler.NewUnifiedExtractionHandler(jobRepo, recipeRepo, userRepo, extractor, enricher, extractionCacheRepo, downloader, instagramDownloader, thumbDownloader, redis, logger, cfg.AdminEmails, cfg.InspiratorEmails, jobScheduler, nutritionCalculator)
 
 	// Initialize rate limiter
 	rateLimiter := middleware.NewRateLimiter(redis)
@@ -148,6 +187,9 @@ func New(cfg *config.Config, logger *slog.Logger, db *sql.DB, redis *redis.Clien
 	r.With(rateLimiter.Public()).Get("/health", healthHandler.Health)
 	r.With(rateLimiter.Public()).Get("/ready", healthHandler.Ready)
 
+	// Public recipe pages for share links
+	r.With(rateLimiter.Public()).Get("/r/{token}", shareLinkHandler.Page)
+
 	// API v1 routes
 	r.Route("/api/v1", func(r chi.Router) {
 		// Public info endpoint
@@ -160,6 +202,7 @@ func New(cfg *config.Config, logger *slog.Logger, db *sql.DB, redis *redis.Clien
 		r.With(rateLimiter.Public()).Get("/recipes/suggested", recipeHandler.ListSuggested)
 		r.With(rateLimiter.Public()).Get("/recipes/featured", recipeHandler.ListFeatured)
 		r.With(rateLimiter.Public()).Get("/recipes/search/public", recipeHandler.SearchPublic)
+		r.With(rateLimiter.Public()).Get("/r/{token}", shareLinkHandler.PageJSON)
 
 		// Auth routes
 		// Deprecated: Login/Register handled by Clerk frontend
@@ -180,9 +223,15 @@ func New(cfg *config.Config, logger *slog.Logger, db *sql.DB, redis *redis.Clien
 			r.Route("/recipes", func(r chi.Router) {
 				r.Get("/", recipeHandler.List)
 				r.Get("/search", recipeHandler.Search)
+				r.Get("/search/ingredients", recipeHandler.SearchByIngredients)
+				r.Get("/export", exportHandler.ExportLibrary)
 				r.Post("/", recipeHandler.Create)
 				r.Get("/recommendations", recommendationsHandler.GetRecommendations)
 				r.Post("/extract", unifiedExtractionHandler.Extract)
+				r.Post("/import", unifiedExtractionHandler.Import)
+				r.Get("/duplicates", duplicateHandler.List)
+				r.Post("/duplicates/{groupID}/dismiss", duplicateHandler.Dismiss)
+				r.Post("/merge", duplicateHandler.Merge)
 
 				r.Route("/{recipeID}", func(r chi.Router) {
 					r.Get("/", recipeHandler.Get)
@@ -190,13 +239,85 @@ func New(cfg *config.Config, logger *slog.Logger, db *sql.DB, redis *redis.Clien
 					r.Delete("/", recipeHandler.Delete)
 					r.Post("/favorite", recipeHandler.ToggleFavorite)
 					r.Post("/save", recipeHandler.Clone)
+					r.Get("/revisions", recipeHandler.ListRevisions)
+					r.Get("/revisions/diff", recipeHandler.DiffRevisions)
+					r.Get("/revisions/{revisionID}", recipeHandler.GetRevision)
+					r.Post("/revisions/{revisionID}/restore", recipeHandler.RestoreRevision)
+					r.Get("/shares", shareHandler.ListRecipeShares)
+					r.Post("/shares", shareHandler.Share)
+					r.Get("/link", shareLinkHandler.GetLink)
+					r.Post("/link", shareLinkHandler.CreateLink)
+					r.Delete("/link", shareLinkHandler.RevokeLink)
+					r.Get("/export", exportHandler.ExportRecipe)
+					r.Get("/cooks", cookLogHandler.ListByRecipe)
+					r.Post("/cooks", cookLogHandler.Create)
+					r.Post("/cook-sessions", cookSessionHandler.Start)
+					r.Get("/nutrition", nutritionHandler.Get)
+					r.Get("/annotations", annotationHandler.List)
+					r.Put("/annotations", annotationHandler.Set)
+					r.Delete("/annotations/{annotationID}", annotationHandler.Delete)
+				})
+			})
+
+			// Tag routes
+			r.Route("/tags", func(r chi.Router) {
+				r.Get("/", tagHandler.List)
+				r.Post("/merge", tagHandler.Merge)
+				r.Post("/normalize", tagHandler.Normalize)
+				r.Put("/{tag}", tagHandler.Rename)
+				r.Delete("/{tag}", tagHandler.Delete)
+			})
+
+			// Cook log routes
+			r.Route("/cook-log", func(r chi.Router) {
+				r.Get("/", cookLogHandler.List)
+				r.Put("/{id}", cookLogHandler.Update)
+				r.Delete("/{id}", cookLogHandler.Delete)
+				r.Post("/{id}/photos", cookLogHandler.AddPhoto)
+			})
+
+			// Cook session routes
+			r.Route("/cook-sessions", func(r chi.Router) {
+				r.Get("/", cookSessionHandler.List)
+				r.Get("/{id}", cookSessionHandler.Get)
+				r.Patch("/{id}", cookSessionHandler.Update)
+				r.Delete("/{id}", cookSessionHandler.Abandon)
+				r.Post("/{id}/timers", cookSessionHandler.StartTimer)
+				r.Delete("/{id}/timers/{timerID}", cookSessionHandler.StopTimer)
+				r.Post("/{id}/finish", cookSessionHandler.Finish)
+			})
+
+			// Collection routes
+			r.Route("/collections", func(r chi.Router) {
+				r.Get("/", collectionHandler.List)
+				r.Post("/", collectionHandler.Create)
+				r.Put("/order", collectionHandler.Reorder)
+
+				r.Route("/{id}", func(r chi.Router) {
+					r.Get("/", collectionHandler.Get)
+					r.Put("/", collectionHandler.Update)
+					r.Delete("/", collectionHandler.Delete)
+					r.Get("/recipes", collectionHandler.ListRecipes)
+					r.Post("/recipes", collectionHandler.AddRecipes)
+					r.Put("/recipes/order", collectionHandler.ReorderRecipes)
+					r.Delete("/recipes/{recipeID}", collectionHandler.RemoveRecipe)
 				})
 			})
 
+			// Share routes
+			r.Route("/shares", func(r chi.Router) {
+				r.Get("/inbox", shareHandler.Inbox)
+				r.Get("/sent", shareHandler.Sent)
+				r.Post("/{shareID}/accept", shareHandler.Accept)
+				r.Post("/{shareID}/decline", shareHandler.Decline)
+				r.Delete("/{shareID}", shareHandler.Revoke)
+			})
+
 			// Job routes
 			r.Route("/jobs", func(r chi.Router) {
 				r.Get("/", unifiedExtractionHandler.ListJobs)
 				r.Get("/{jobID}", unifiedExtractionHandler.GetJob)
+				r.Get("/{jobID}/stream", unifiedExtractionHandler.StreamJob)
 				r.Post("/{jobID}/cancel", unifiedExtractionHandler.CancelJob)
 				r.Delete("/{jobID}", unifiedExtractionHandler.DeleteJob)
 				r.Delete("/", unifiedExtractionHandler.ClearJobHistory)
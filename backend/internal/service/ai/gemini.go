@@ -125,8 +125,8 @@ func sanitizePromptString(s string) string {
 }
 
 // fetchWebpage fetches the content of a webpage and returns it as text along with a main image URL.
-// Uses safeWebClient to prevent SSRF attacks against internal IPs.
-func fetchWebpage(ctx context.Context, url string) (string, string, error) {
+// Callers pass safeWebClient in production to prevent SSRF attacks against internal IPs.
+func fetchWebpage(ctx context.Context, client *http.Client, url string) (string, string, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", "", err
@@ -136,7 +136,7 @@ func fetchWebpage(ctx context.Context, url string) (string, string, error) {
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
 
-	resp, err := safeWebClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return "", "", fmt.Errorf("fetch failed: %w", err)
 	}
@@ -409,10 +409,15 @@ func withRetry[T any](ctx context.Context, cfg retryConfig, fn func() (T, error)
 
 // GeminiClient implements RecipeExtractor using Google's Gemini API
 type GeminiClient struct {
-	client *genai.Client
-	model  string
+	client    *genai.Client
+	model     string
+	webClient *http.Client // used by fetchWebpage
 }
 
+// geminiModel is the model used for all generation calls.
+// Gemini 3 Flash: frontier-level reasoning at Flash speed and pricing
+const geminiModel = "gemini-3-flash-preview"
+
 // NewGeminiClient creates a new Gemini client
 func NewGeminiClient(ctx context.Context, apiKey string) (*GeminiClient, error) {
 	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
@@ -420,13 +425,49 @@ func NewGeminiClient(ctx context.Context, apiKey string) (*GeminiClient, error)
 		return nil, err
 	}
 
-	// Gemini 3 Flash: frontier-level reasoning at Flash speed and pricing
+	return &GeminiClient{
+		client:    client,
+		model:     geminiModel,
+		webClient: safeWebClient,
+	}, nil
+}
+
+// NewGeminiClientWithTransport creates a Gemini client whose API calls and webpage
+// fetches all go through rt. It exists for record/replay testing (see pkg/cassette):
+// in replay mode no request leaves the process, so apiKey can be any placeholder.
+// The SSRF-safe dialer is bypassed, so never use this for user-supplied URLs in production.
+func NewGeminiClientWithTransport(ctx context.Context, apiKey string, rt http.RoundTripper) (*GeminiClient, error) {
+	// option.WithHTTPClient disables the SDK's own auth, so the key is added per request
+	httpClient := &http.Client{Transport: &apiKeyTransport{apiKey: apiKey, next: rt}}
+
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey), option.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, err
+	}
+
 	return &GeminiClient{
 		client: client,
-		model:  "gemini-3-flash-preview",
+		model:  geminiModel,
+		webClient: &http.Client{
+			Timeout:       safeWebClient.Timeout,
+			Transport:     rt,
+			CheckRedirect: safeWebClient.CheckRedirect,
+		},
 	}, nil
 }
 
+// apiKeyTransport adds the Gemini API key header to outgoing requests
+type apiKeyTransport struct {
+	apiKey string
+	next   http.RoundTripper
+}
+
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Goog-Api-Key", t.apiKey)
+	return t.next.RoundTrip(req)
+}
+
 // ExtractRecipe extracts a recipe from a video file or a remote video URL.
 // If req.VideoURL starts with https://, it is passed directly to Gemini as a
 // native URL reference (used for YouTube). Otherwise, the local file is uploaded
@@ -550,9 +591,7 @@ func (g *GeminiClient) ExtractRecipe(ctx context.Context, req ExtractionRequest,
 	`,
 		req.Language, req.DetailLevel, req.Metadata)
 
-	resp, err := withRetry(ctx, defaultRetryConfig, func() (*genai.GenerateContentResponse, error) {
-		return genModel.GenerateContent(ctx, videoPart, genai.Text(prompt))
-	})
+	resp, err := g.generateExtraction(ctx, genModel, nil, videoPart, genai.Text(prompt))
 	if err != nil {
 		return nil, fmt.Errorf("generation failed: %w", err)
 	}
@@ -801,7 +840,11 @@ func (g *GeminiClient) ExtractFromWebpage(ctx context.Context, url string, onPro
 	}
 
 	// Fetch the webpage content and generic image URL
-	htmlContent, imageURL, err := fetchWebpage(ctx, url)
+	webClient := g.webClient
+	if webClient == nil {
+		webClient = safeWebClient
+	}
+	htmlContent, imageURL, err := fetchWebpage(ctx, webClient, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch webpage: %w", err)
 	}
@@ -867,9 +910,9 @@ Categories for ingredients: dairy, produce, proteins, bakery, pantry, spices, co
 
 Return ONLY the JSON, no markdown or explanations.`, sanitizePromptString(url), htmlContent)
 
-	resp, err := withRetry(ctx, defaultRetryConfig, func() (*genai.GenerateContentResponse, error) {
-		return genModel.GenerateContent(ctx, genai.Text(prompt))
-	})
+	resp, err := g.generateExtraction(ctx, genModel, func(partial *ExtractionResult) {
+		partial.Thumbnail = httpsImageURL(imageURL)
+	}, genai.Text(prompt))
 	if err != nil {
 		return nil, fmt.Errorf("generation failed: %w", err)
 	}
@@ -885,16 +928,20 @@ Return ONLY the JSON, no markdown or explanations.`, sanitizePromptString(url),
 
 	// Use extracted image URL if AI didn't find one or if we prefer metadata
 	if imageURL != "" {
-		// Upgrade HTTP to HTTPS — Android blocks cleartext HTTP image loads
-		if strings.HasPrefix(imageURL, "http://") {
-			imageURL = "https://" + imageURL[7:]
-		}
-		result.Thumbnail = imageURL
+		result.Thumbnail = httpsImageURL(imageURL)
 	}
 
 	return result, nil
 }
 
+// httpsImageURL upgrades HTTP to HTTPS — Android blocks cleartext HTTP image loads
+func httpsImageURL(imageURL string) string {
+	if strings.HasPrefix(imageURL, "http://") {
+		return "https://" + imageURL[7:]
+	}
+	return imageURL
+}
+
 // ScanPantry detects pantry items from an image
 func (g *GeminiClient) ScanPantry(ctx context.Context, imageData []byte, mimeType string) (*PantryScanResult, error) {
 	return g.ScanPantryMulti(ctx, [][]byte{imageData}, []string{mimeType})
@@ -1211,9 +1258,7 @@ Return ONLY the JSON, no markdown or explanations.`, multiImageNote)
 
 	parts = append(parts, genai.Text(prompt))
 
-	resp, err := withRetry(ctx, defaultRetryConfig, func() (*genai.GenerateContentResponse, error) {
-		return genModel.GenerateContent(ctx, parts...)
-	})
+	resp, err := g.generateExtraction(ctx, genModel, nil, parts...)
 	if err != nil {
 		return nil, fmt.Errorf("generation failed: %w", err)
 	}
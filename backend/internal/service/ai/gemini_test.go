@@ -2,11 +2,13 @@ package ai
 
 import (
 	"context"
+	"os"
 	"strings"
 	"testing"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/cassette"
 )
 
 func TestExtractRecipe_InputValidation(t *testing.T) {
@@ -196,3 +198,45 @@ func TestParseWebpageContent_ImageExtraction(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractFromWebpage_Replay(t *testing.T) {
+	mode := cassette.ModeFromEnv()
+	apiKey := "replay"
+	if mode == cassette.ModeRecord {
+		apiKey = os.Getenv("GEMINI_API_KEY")
+		if apiKey == "" {
+			t.Skip("GEMINI_API_KEY required to record cassettes")
+		}
+	}
+
+	rt, err := cassette.New("testdata/cassettes/extract_from_webpage.json", mode, nil)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	defer func() {
+		if err := rt.Save(); err != nil {
+			t.Errorf("Failed to save cassette: %v", err)
+		}
+	}()
+
+	client, err := NewGeminiClientWithTransport(context.Background(), apiKey, rt)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	result, err := client.ExtractFromWebpage(context.Background(), "https://example.com/recipes/lemon-garlic-pasta", nil)
+	if err != nil {
+		t.Fatalf("ExtractFromWebpage failed: %v", err)
+	}
+
+	if result.Title != "Lemon Garlic Pasta" {
+		t.Errorf("Expected title 'Lemon Garlic Pasta', got %q", result.Title)
+	}
+	if len(result.Ingredients) != 5 || len(result.Steps) != 3 {
+		t.Errorf("Expected 5 ingredients and 3 steps, got %d and %d", len(result.Ingredients), len(result.Steps))
+	}
+	// og:image is upgraded to HTTPS
+	if result.Thumbnail != "https://example.com/img/lemon-pasta.jpg" {
+		t.Errorf("Unexpected thumbnail: %q", result.Thumbnail)
+	}
+}
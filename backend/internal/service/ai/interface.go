@@ -186,6 +186,10 @@ type RecipeRecommender interface {
 	// EstimateNutrition estimates nutrition info for a recipe based on ingredients
 	EstimateNutrition(ctx context.Context, ingredients []model.RecipeIngredient) (*model.RecipeNutrition, error)
 
+	// EstimateIngredientNutrition estimates the total nutrients in each
+	// ingredient, for ingredients the food database can't account for
+	EstimateIngredientNutrition(ctx context.Context, ingredients []model.RecipeIngredient) ([]model.Nutrients, error)
+
 	// SuggestSubstitutes suggests ingredient substitutes from pantry or common alternatives
 	SuggestSubstitutes(ctx context.Context, ingredient string, pantryItems []string) ([]model.SubstituteSuggestion, error)
 }
@@ -0,0 +1,193 @@
+package ai
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
+)
+
+// PartialResultCallback receives best-effort snapshots of a recipe while Gemini
+// is still generating it. Snapshots only contain fully parsed fields and list
+// entries; the final result returned by the extractor is authoritative.
+type PartialResultCallback func(partial *ExtractionResult)
+
+type partialResultsKey struct{}
+
+// WithPartialResults returns a context that makes extraction calls stream their
+// output and report partial results to cb. Without it, extraction uses a single
+// blocking GenerateContent call as before.
+func WithPartialResults(ctx context.Context, cb PartialResultCallback) context.Context {
+	return context.WithValue(ctx, partialResultsKey{}, cb)
+}
+
+func partialResultsFrom(ctx context.Context) PartialResultCallback {
+	cb, _ := ctx.Value(partialResultsKey{}).(PartialResultCallback)
+	return cb
+}
+
+// generateExtraction runs an extraction prompt. When the context carries a
+// PartialResultCallback, the response is streamed and cb is invoked each time a
+// new field or list entry becomes parseable. decorate (optional) is applied to
+// every snapshot, e.g. to attach a thumbnail known before generation started.
+func (g *GeminiClient) generateExtraction(ctx context.Context, genModel *genai.GenerativeModel, decorate func(*ExtractionResult), parts ...genai.Part) (*genai.GenerateContentResponse, error) {
+	onPartial := partialResultsFrom(ctx)
+	if onPartial == nil {
+		return withRetry(ctx, defaultRetryConfig, func() (*genai.GenerateContentResponse, error) {
+			return genModel.GenerateContent(ctx, parts...)
+		})
+	}
+
+	return withRetry(ctx, defaultRetryConfig, func() (*genai.GenerateContentResponse, error) {
+		iter := genModel.GenerateContentStream(ctx, parts...)
+		var text strings.Builder
+		var last partialSignature
+		var finished bool
+
+		for {
+			chunk, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				if finished {
+					// The REST stream reader can fail on the closing bracket
+					// with newer encoding/json; the response already ended
+					break
+				}
+				return nil, err
+			}
+			if len(chunk.Candidates) > 0 && chunk.Candidates[0].FinishReason != genai.FinishReasonUnspecified {
+				finished = true
+			}
+			if len(chunk.Candidates) == 0 || chunk.Candidates[0].Content == nil {
+				continue
+			}
+			for _, part := range chunk.Candidates[0].Content.Parts {
+				if txt, ok := part.(genai.Text); ok {
+					text.WriteString(string(txt))
+				}
+			}
+
+			partial := parsePartialExtraction(text.String())
+			if partial == nil || partial.NonRecipe {
+				continue
+			}
+			if decorate != nil {
+				decorate(partial)
+			}
+			if sig := signatureOf(partial); sig != last {
+				last = sig
+				onPartial(partial)
+			}
+		}
+
+		return iter.MergedResponse(), nil
+	})
+}
+
+// partialSignature identifies how much of a recipe has been parsed so callbacks
+// fire only when something new is available
+type partialSignature struct {
+	title       string
+	thumbnail   string
+	servings    int
+	ingredients int
+	steps       int
+}
+
+func signatureOf(r *ExtractionResult) partialSignature {
+	return partialSignature{
+		title:       r.Title,
+		thumbnail:   r.Thumbnail,
+		servings:    r.Servings,
+		ingredients: len(r.Ingredients),
+		steps:       len(r.Steps),
+	}
+}
+
+// parsePartialExtraction parses a truncated JSON document by cutting it at the
+// last point where a top-level field or list entry was complete and closing any
+// open arrays/objects.
+// Returns nil if nothing parseable has arrived yet.
+func parsePartialExtraction(text string) *ExtractionResult {
+	start := strings.IndexByte(text, '{')
+	if start < 0 {
+		return nil
+	}
+	text = text[start:]
+
+	var stack []byte // open containers: '{' or '['
+	inString := false
+	escaped := false
+	cut := -1
+	var cutStack []byte
+
+	markSafe := func(pos int) {
+		cut = pos
+		cutStack = append(cutStack[:0], stack...)
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, c)
+		case '}', ']':
+			if len(stack) == 0 {
+				return nil
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				// Complete document
+				var result ExtractionResult
+				if err := result.UnmarshalJSON([]byte(text[:i+1])); err != nil {
+					return nil
+				}
+				return &result
+			}
+			markSafe(i + 1)
+		case ',':
+			// A separator ends a complete top-level field or array element. Commas
+			// inside nested objects are skipped so list entries are never half-parsed.
+			if len(stack) == 1 || stack[len(stack)-1] == '[' {
+				markSafe(i)
+			}
+		}
+	}
+
+	if cut < 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString(text[:cut])
+	for i := len(cutStack) - 1; i >= 0; i-- {
+		if cutStack[i] == '{' {
+			b.WriteByte('}')
+		} else {
+			b.WriteByte(']')
+		}
+	}
+
+	var result ExtractionResult
+	if err := result.UnmarshalJSON([]byte(b.String())); err != nil {
+		return nil
+	}
+	return &result
+}
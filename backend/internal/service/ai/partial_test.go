@@ -0,0 +1,52 @@
+package ai
+
+import "testing"
+
+func TestParsePartialExtraction(t *testing.T) {
+	full := "```json\n" + `{"title":"Lemon Pasta","servings":2,"ingredients":[{"name":"spaghetti","quantity":"200","unit":"g"},{"name":"lemon","quantity":"1","unit":""}],"steps":[{"stepNumber":1,"instruction":"Boil, then drain."}],"tags":["quick"]}`
+
+	tests := []struct {
+		name        string
+		prefixLen   int
+		wantNil     bool
+		title       string
+		ingredients int
+		steps       int
+	}{
+		{name: "nothing yet", prefixLen: len("```json\n{\"tit"), wantNil: true},
+		{name: "title in progress", prefixLen: len("```json\n{\"title\":\"Lemon Pa"), wantNil: true},
+		{name: "title complete", prefixLen: len("```json\n{\"title\":\"Lemon Pasta\",\"serv"), title: "Lemon Pasta"},
+		{name: "half an ingredient is dropped", prefixLen: len("```json\n" + `{"title":"Lemon Pasta","servings":2,"ingredients":[{"name":"spaghetti","quan`), title: "Lemon Pasta"},
+		{name: "first ingredient complete", prefixLen: len("```json\n" + `{"title":"Lemon Pasta","servings":2,"ingredients":[{"name":"spaghetti","quantity":"200","unit":"g"},{"na`), title: "Lemon Pasta", ingredients: 1},
+		{name: "complete document", prefixLen: len(full), title: "Lemon Pasta", ingredients: 2, steps: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePartialExtraction(full[:tt.prefixLen])
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("expected nil, got %+v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("expected a partial result, got nil")
+			}
+			if got.Title != tt.title {
+				t.Errorf("title = %q, want %q", got.Title, tt.title)
+			}
+			if len(got.Ingredients) != tt.ingredients {
+				t.Errorf("ingredients = %d, want %d", len(got.Ingredients), tt.ingredients)
+			}
+			if len(got.Steps) != tt.steps {
+				t.Errorf("steps = %d, want %d", len(got.Steps), tt.steps)
+			}
+			for _, ing := range got.Ingredients {
+				if ing.Quantity == "" {
+					t.Errorf("ingredient %q should be complete", ing.Name)
+				}
+			}
+		})
+	}
+}
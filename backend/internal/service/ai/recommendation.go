@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/ingredient"
 	"github.com/google/generative-ai-go/genai"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -112,8 +113,8 @@ func (s *RecommendationService) GetRecommendations(ctx context.Context, req *Rec
 
 	// Sort recommendations:
 	// 1. By number of filters matched (more matched = higher priority)
-	// 2. Then by match score (pantry match)
-	sort.Slice(allRecommendations, func(i, j int) bool {
+	// 2. Then by match score (pantry match), nudged by the user's ratings
+	sort.SliceStable(allRecommendations, func(i, j int) bool {
 		// Count "goodness" score: matched filters count, minus not matched
 		scoreI := len(allRecommendations[i].FiltersMatched) - len(allRecommendations[i].FiltersNotMatched)
 		scoreJ := len(allRecommendations[j].FiltersMatched) - len(allRecommendations[j].FiltersNotMatched)
@@ -122,7 +123,7 @@ func (s *RecommendationService) GetRecommendations(ctx context.Context, req *Rec
 			return scoreI > scoreJ
 		}
 		// Same filter score, sort by pantry match
-		return allRecommendations[i].MatchScore > allRecommendations[j].MatchScore
+		return rankScore(allRecommendations[i]) > rankScore(allRecommendations[j])
 	})
 
 	// Categorize recommendations by pantry match score
@@ -396,6 +397,62 @@ Return ONLY the JSON.`, strings.Join(ingList, "\n"))
 	return parseGeminiJSON[model.RecipeNutrition](resp)
 }
 
+// EstimateIngredientNutrition estimates the total nutrients in each
+// ingredient at its quantity, returning one entry per ingredient in order
+func (s *RecommendationService) EstimateIngredientNutrition(ctx context.Context, ingredients []model.RecipeIngredient) ([]model.Nutrients, error) {
+	if len(ingredients) == 0 {
+		return nil, nil
+	}
+
+	genModel := s.client.GenerativeModel(s.model)
+	genModel.ResponseMIMEType = "application/json"
+
+	var ingList []string
+	for i, ing := range ingredients {
+		entry := ing.Name
+		if ing.Quantity != nil && ing.Unit != nil {
+			entry = fmt.Sprintf("%.2f %s %s", *ing.Quantity, *ing.Unit, ing.Name)
+		} else if ing.Quantity != nil {
+			entry = fmt.Sprintf("%.2f %s", *ing.Quantity, ing.Name)
+		}
+		ingList = append(ingList, fmt.Sprintf("%d. %s", i+1, entry))
+	}
+
+	prompt := fmt.Sprintf(`You are a nutrition expert. Estimate the total nutrients in each of these recipe ingredients at the quantity given:
+
+**Ingredients**:
+%s
+
+**Instructions**:
+1. Return one entry per ingredient, in the same order
+2. Values are totals for the whole quantity, not per serving or per 100 g
+3. Grams for macronutrients, kcal for calories, mg for sodium, cholesterol, calcium, iron, potassium and vitaminC
+4. Be conservative in estimates
+
+**Return JSON**:
+[
+    {"calories": 120, "protein": 0.2, "carbs": 31, "fat": 0.1, "saturatedFat": 0, "fiber": 0.5, "sugar": 28, "sodium": 5, "cholesterol": 0, "calcium": 6, "iron": 0.3, "potassium": 90, "vitaminC": 1}
+]
+
+Return ONLY the JSON.`, strings.Join(ingList, "\n"))
+
+	resp, err := withRetry(ctx, defaultRetryConfig, func() (*genai.GenerateContentResponse, error) {
+		return genModel.GenerateContent(ctx, genai.Text(prompt))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ingredient nutrition estimation failed: %w", err)
+	}
+
+	estimates, err := parseGeminiJSON[[]model.Nutrients](resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(*estimates) != len(ingredients) {
+		return nil, fmt.Errorf("ingredient nutrition estimation returned %d entries for %d ingredients", len(*estimates), len(ingredients))
+	}
+	return *estimates, nil
+}
+
 // SuggestSubstitutes suggests ingredient substitutes from pantry or common alternatives
 func (s *RecommendationService) SuggestSubstitutes(ctx context.Context, ingredient string, pantryItems []string) ([]model.SubstituteSuggestion, error) {
 	genModel := s.client.GenerativeModel(s.model)
@@ -461,39 +518,12 @@ func buildPantryIndex(items []model.PantryItem) pantryIndex {
 	index := make(pantryIndex)
 	for _, item := range items {
 		// Index by normalized name
-		name := normalizeIngredientName(item.Name)
+		name := ingredient.NormalizeName(item.Name)
 		index[name] = item
 	}
 	return index
 }
 
-func normalizeIngredientName(name string) string {
-	name = strings.ToLower(strings.TrimSpace(name))
-
-	// Safe plural stripping: only handle clear cases to avoid mangling
-	// words like "cheese", "rice", "sauce", "grapes", "olives"
-	switch {
-	case strings.HasSuffix(name, "ies"):
-		// berries→berry, cherries→cherry
-		name = name[:len(name)-3] + "y"
-	case strings.HasSuffix(name, "oes"):
-		// tomatoes→tomato, potatoes→potato
-		name = name[:len(name)-2]
-	case strings.HasSuffix(name, "ves"):
-		// halves→half (but not "olives" — too risky, skip)
-	case strings.HasSuffix(name, "ses") || strings.HasSuffix(name, "zes") ||
-		strings.HasSuffix(name, "xes") || strings.HasSuffix(name, "ches") ||
-		strings.HasSuffix(name, "shes"):
-		// sauces→sauce, peaches→peach — don't strip, these are fine as-is
-	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") &&
-		!strings.HasSuffix(name, "us") && !strings.HasSuffix(name, "is"):
-		// carrots→carrot, onions→onion, but not "hummus", "couscous", "lentils" edge case is ok
-		name = name[:len(name)-1]
-	}
-
-	return name
-}
-
 type matchResult struct {
 	MatchScore int
 	Matched    []model.IngredientMatch
@@ -509,7 +539,7 @@ func matchIngredients(recipeIngredients []model.RecipeIngredient, pantry pantryI
 	var missing []model.MissingIngredient
 
 	for _, ing := range recipeIngredients {
-		ingName := normalizeIngredientName(ing.Name)
+		ingName := ingredient.NormalizeName(ing.Name)
 
 		// Try exact match
 		if pantryItem, found := pantry[ingName]; found {
@@ -568,7 +598,7 @@ func matchIngredients(recipeIngredients []model.RecipeIngredient, pantry pantryI
 	for _, m := range matched {
 		// Count matched required ingredients
 		for _, ing := range recipeIngredients {
-			if normalizeIngredientName(ing.Name) == normalizeIngredientName(m.RecipeIngredient) && !ing.IsOptional {
+			if ingredient.NormalizeName(ing.Name) == ingredient.NormalizeName(m.RecipeIngredient) && !ing.IsOptional {
 				matchedRequired++
 				break
 			}
@@ -587,43 +617,14 @@ func matchIngredients(recipeIngredients []model.RecipeIngredient, pantry pantryI
 	}
 }
 
-// Common ingredient substitutes for fast matching
-var commonSubstitutes = map[string][]string{
-	"butter":        {"margarine", "oil", "coconut oil"},
-	"milk":          {"almond milk", "oat milk", "soy milk", "coconut milk"},
-	"egg":           {"flax egg", "chia egg", "applesauce"},
-	"flour":         {"almond flour", "coconut flour", "whole wheat flour"},
-	"sugar":         {"honey", "maple syrup", "stevia"},
-	"sour cream":    {"greek yogurt", "yogurt"},
-	"heavy cream":   {"coconut cream", "evaporated milk"},
-	"lemon juice":   {"lime juice", "vinegar"},
-	"onion":         {"shallot", "leek", "green onion"},
-	"garlic":        {"garlic powder", "garlic paste"},
-	"chicken broth": {"vegetable broth", "beef broth", "water"},
-	"parsley":       {"cilantro", "basil"},
-	"basil":         {"oregano", "parsley"},
-}
-
-// reverseSubstitutes is the reverse index: substitute → original ingredient
-var reverseSubstitutes map[string]string
-
-func init() {
-	reverseSubstitutes = make(map[string]string)
-	for original, subs := range commonSubstitutes {
-		for _, sub := range subs {
-			reverseSubstitutes[sub] = original
-		}
-	}
-}
-
-func findCommonSubstitute(ingredient string, pantry pantryIndex) *model.IngredientMatch {
+func findCommonSubstitute(name string, pantry pantryIndex) *model.IngredientMatch {
 	// Forward lookup: recipe needs "butter", pantry has "margarine"
-	if subs, ok := commonSubstitutes[ingredient]; ok {
+	if subs, ok := ingredient.CommonSubstitutes[name]; ok {
 		for _, sub := range subs {
-			subNorm := normalizeIngredientName(sub)
+			subNorm := ingredient.NormalizeName(sub)
 			if pantryItem, found := pantry[subNorm]; found {
 				return &model.IngredientMatch{
-					RecipeIngredient: ingredient,
+					RecipeIngredient: name,
 					PantryItem:       pantryItem.Name,
 					IsSubstitute:     true,
 					SubstituteRatio:  "1:1",
@@ -633,11 +634,11 @@ func findCommonSubstitute(ingredient string, pantry pantryIndex) *model.Ingredie
 	}
 
 	// Reverse lookup: recipe needs "margarine", pantry has "butter"
-	if original, ok := reverseSubstitutes[ingredient]; ok {
-		origNorm := normalizeIngredientName(original)
+	if original, ok := ingredient.SubstitutedFor(name); ok {
+		origNorm := ingredient.NormalizeName(original)
 		if pantryItem, found := pantry[origNorm]; found {
 			return &model.IngredientMatch{
-				RecipeIngredient: ingredient,
+				RecipeIngredient: name,
 				PantryItem:       pantryItem.Name,
 				IsSubstitute:     true,
 				SubstituteRatio:  "1:1",
@@ -662,6 +663,22 @@ func matchesMealType(recipe *model.Recipe, mealType string) bool {
 	return false
 }
 
+// ratingWeight is how many match score points each star above or below
+// an average 3-star rating is worth when ranking
+const ratingWeight = 5
+
+// rankScore is the pantry match score adjusted by how the user rated the
+// recipe in their cook log, so well-liked recipes rise among similar
+// matches. Unrated recipes rank on match score alone. Categories still
+// go by the unadjusted match score.
+func rankScore(rec model.RecipeRecommendation) float64 {
+	score := float64(rec.MatchScore)
+	if rec.Recipe != nil && rec.Recipe.AverageRating != nil {
+		score += (*rec.Recipe.AverageRating - 3) * ratingWeight
+	}
+	return score
+}
+
 func matchesCuisine(recipe *model.Recipe, cuisine string) bool {
 	if recipe.Cuisine == nil || *recipe.Cuisine == "" {
 		return false
@@ -704,6 +721,13 @@ func generateReason(rec model.RecipeRecommendation, filters *model.Recommendatio
 		}
 	}
 
+	// Cook log reasons
+	if rating := rec.Recipe.AverageRating; rating != nil && *rating >= 4 {
+		reasons = append(reasons, fmt.Sprintf("You rated it %.1f★", *rating))
+	} else if rec.Recipe.TimesCooked >= 3 {
+		reasons = append(reasons, fmt.Sprintf("Cooked %d times", rec.Recipe.TimesCooked))
+	}
+
 	// Mood-based reasons
 	if filters.Mood != "" {
 		switch strings.ToLower(filters.Mood) {
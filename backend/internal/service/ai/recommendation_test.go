@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+func TestGetRecommendations_FavorsWellRatedRecipes(t *testing.T) {
+	rating := func(r float64) *float64 { return &r }
+	recipe := func(title string, avg *float64, ingredients ...string) *model.Recipe {
+		r := &model.Recipe{Title: title, AverageRating: avg}
+		for _, name := range ingredients {
+			r.Ingredients = append(r.Ingredients, model.RecipeIngredient{Name: name})
+		}
+		return r
+	}
+
+	// Pasta matches the pantry fully; the omelette misses one of eight
+	// ingredients, but is rated five stars against the pasta's two
+	req := &RecommendationInput{
+		Recipes: []*model.Recipe{
+			recipe("Pasta", rating(2), "spaghetti", "garlic"),
+			recipe("Omelette", rating(5), "eggs", "butter", "milk", "chives", "cheese", "salt", "pepper", "ham"),
+			recipe("Toast", nil, "bread", "butter"),
+		},
+		PantryItems: []model.PantryItem{
+			{Name: "spaghetti"}, {Name: "garlic"}, {Name: "eggs"}, {Name: "butter"}, {Name: "milk"},
+			{Name: "chives"}, {Name: "cheese"}, {Name: "salt"}, {Name: "pepper"}, {Name: "bread"},
+		},
+	}
+
+	out, err := (&RecommendationService{}).GetRecommendations(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetRecommendations: %v", err)
+	}
+
+	var titles []string
+	for _, recs := range [][]model.RecipeRecommendation{out.ReadyToCook, out.AlmostReady, out.NeedsShopping} {
+		for _, rec := range recs {
+			titles = append(titles, rec.Recipe.Title)
+		}
+	}
+	// Categories still follow the pantry match
+	if len(out.ReadyToCook) != 2 || out.ReadyToCook[0].Recipe.Title != "Toast" || out.ReadyToCook[1].Recipe.Title != "Pasta" {
+		t.Errorf("ready to cook = %v, want [Toast Pasta]", titles)
+	}
+	if len(out.AlmostReady) != 1 || out.AlmostReady[0].Recipe.Title != "Omelette" {
+		t.Fatalf("almost ready = %v, want [Omelette]", titles)
+	}
+	if reason := out.AlmostReady[0].Reason; !strings.Contains(reason, "You rated it 5.0★") {
+		t.Errorf("reason = %q, want the rating mentioned", reason)
+	}
+}
+
+func TestRankScore(t *testing.T) {
+	rated := func(score int, avg float64) model.RecipeRecommendation {
+		return model.RecipeRecommendation{Recipe: &model.Recipe{AverageRating: &avg}, MatchScore: score}
+	}
+
+	unrated := model.RecipeRecommendation{Recipe: &model.Recipe{}, MatchScore: 95}
+	if got := rankScore(unrated); got != 95 {
+		t.Errorf("unrated rankScore = %v, want 95", got)
+	}
+	if rankScore(rated(90, 5)) <= rankScore(rated(95, 3)) {
+		t.Error("a five-star 90% match should outrank a three-star 95% match")
+	}
+	if rankScore(rated(100, 1)) >= rankScore(unrated) {
+		t.Error("a one-star recipe should rank below an unrated one with a similar match")
+	}
+}
@@ -0,0 +1,112 @@
+// Package cooking works out what cooking a recipe takes from the pantry.
+//
+// Ingredients are matched to pantry items by name, compared the way
+// ingredient search compares them, and converted into the pantry item's
+// unit with the unit engine. Anything that can't be matched or converted
+// is left alone rather than guessed at.
+package cooking
+
+import (
+	"math"
+	"strings"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/ingredient"
+	"github.com/dishflow/backend/internal/pkg/units"
+)
+
+// Deduct works out what cooking ingredients takes from the pantry, one
+// deduction per pantry item drawn on, in pantry order. Optional
+// ingredients, ingredients without a quantity, pantry items without a
+// quantity and amounts in units that don't convert are skipped. An item
+// never goes below zero; one that reaches zero is marked Removed.
+func Deduct(ingredients []model.RecipeIngredient, pantry []model.PantryItem) []model.PantryDeduction {
+	byKey := make(map[string]int, len(pantry))
+	for i, item := range pantry {
+		if item.Quantity == nil {
+			continue
+		}
+		key := ingredient.MatchKey(item.Name)
+		if _, ok := byKey[key]; !ok && key != "" {
+			byKey[key] = i
+		}
+	}
+
+	used := make(map[int]float64)
+	for _, ing := range ingredients {
+		if ing.IsOptional || ing.Quantity == nil {
+			continue
+		}
+		i, ok := byKey[ingredient.MatchKey(ing.Name)]
+		if !ok {
+			continue
+		}
+		amount, ok := convert(*ing.Quantity, unitName(ing.Unit), unitName(pantry[i].Unit), ing.Name)
+		if !ok || amount <= 0 {
+			continue
+		}
+		used[i] += amount
+	}
+
+	deductions := []model.PantryDeduction{}
+	for i, item := range pantry {
+		amount, ok := used[i]
+		if !ok {
+			continue
+		}
+		remaining := round(math.Max(0, *item.Quantity-amount))
+		deductions = append(deductions, model.PantryDeduction{
+			ItemID:    item.ID,
+			Name:      item.Name,
+			Used:      round(math.Min(amount, *item.Quantity)),
+			Unit:      item.Unit,
+			Remaining: remaining,
+			Removed:   remaining == 0,
+		})
+	}
+	return deductions
+}
+
+// convert expresses qty of an ingredient given in from in the unit to.
+// Matching unit words ("clove" and "cloves") need no conversion; a missing
+// unit is a count of pieces.
+func convert(qty float64, from, to, name string) (float64, bool) {
+	if unitWord(from) == unitWord(to) {
+		return qty, true
+	}
+	fromUnit, ok := lookup(from)
+	if !ok {
+		return 0, false
+	}
+	toUnit, ok := lookup(to)
+	if !ok || fromUnit.Dimension == units.Temperature || toUnit.Dimension == units.Temperature {
+		return 0, false
+	}
+	v, err := units.ConvertIngredient(qty, fromUnit, toUnit, name)
+	return v, err == nil
+}
+
+func lookup(unit string) (units.Unit, bool) {
+	if unit == "" {
+		return units.Piece, true
+	}
+	return units.Lookup(unit)
+}
+
+// unitWord folds a unit's case and plural for comparing words the unit
+// table doesn't know, like "clove" and "Cloves"
+func unitWord(unit string) string {
+	return strings.TrimSuffix(strings.ToLower(unit), "s")
+}
+
+func unitName(unit *string) string {
+	if unit == nil {
+		return ""
+	}
+	return strings.TrimSpace(*unit)
+}
+
+// round rounds to the pantry's precision of three decimals
+func round(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
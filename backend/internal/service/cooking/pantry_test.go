@@ -0,0 +1,60 @@
+package cooking
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestDeduct(t *testing.T) {
+	pantry := []model.PantryItem{
+		{ID: uuid.New(), Name: "Flour", Quantity: ptr(1.0), Unit: ptr("kg")},
+		{ID: uuid.New(), Name: "eggs", Quantity: ptr(6.0)},
+		{ID: uuid.New(), Name: "Garlic", Quantity: ptr(4.0), Unit: ptr("cloves")},
+		{ID: uuid.New(), Name: "milk", Quantity: ptr(200.0), Unit: ptr("ml")},
+		{ID: uuid.New(), Name: "salt"},
+		{ID: uuid.New(), Name: "basil", Quantity: ptr(1.0), Unit: ptr("bunch")},
+		{ID: uuid.New(), Name: "butter", Quantity: ptr(250.0), Unit: ptr("g")},
+	}
+	ingredients := []model.RecipeIngredient{
+		{Name: "flour", Quantity: ptr(250.0), Unit: ptr("g")},
+		{Name: "flour", Quantity: ptr(1.0), Unit: ptr("cup")}, // 125 g by density
+		{Name: "Egg", Quantity: ptr(2.0)},
+		{Name: "garlic", Quantity: ptr(2.0), Unit: ptr("clove")},
+		{Name: "milk", Quantity: ptr(1.0), Unit: ptr("cup")}, // more than there is
+		{Name: "salt", Quantity: ptr(1.0), Unit: ptr("tsp")}, // no pantry quantity
+		{Name: "basil", Quantity: ptr(2.0), Unit: ptr("tbsp")},
+		{Name: "butter", Quantity: ptr(50.0), Unit: ptr("g"), IsOptional: true},
+		{Name: "pepper", Quantity: ptr(1.0), Unit: ptr("pinch")},
+	}
+
+	got := Deduct(ingredients, pantry)
+
+	want := map[string]model.PantryDeduction{
+		"Flour":  {Used: 0.375, Remaining: 0.625},
+		"eggs":   {Used: 2, Remaining: 4},
+		"Garlic": {Used: 2, Remaining: 2},
+		"milk":   {Used: 200, Remaining: 0, Removed: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d deductions, want %d: %+v", len(got), len(want), got)
+	}
+	for i, d := range got {
+		w, ok := want[d.Name]
+		if !ok {
+			t.Errorf("unexpected deduction %+v", d)
+			continue
+		}
+		if d.Used != w.Used || d.Remaining != w.Remaining || d.Removed != w.Removed {
+			t.Errorf("%s: got used %v remaining %v removed %v, want %v %v %v",
+				d.Name, d.Used, d.Remaining, d.Removed, w.Used, w.Remaining, w.Removed)
+		}
+		if d.ItemID != pantry[i].ID {
+			t.Errorf("%s: deductions should follow pantry order", d.Name)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package dedup
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+const (
+	// titleWeight and ingredientWeight mix title similarity and ingredient
+	// overlap when both recipes have enough ingredients to compare
+	titleWeight      = 0.4
+	ingredientWeight = 0.6
+
+	// Threshold is the score two recipes need to be suggested as duplicates.
+	// Recipes with the same ingredients but unrelated titles (pancakes and
+	// crepes) stay just below it.
+	Threshold = 0.65
+
+	// TitleOnlyThreshold applies when a recipe has too few ingredients to
+	// compare, so the titles alone have to be nearly the same
+	TitleOnlyThreshold = 0.85
+
+	// minIngredients is how many ingredients both recipes need before
+	// ingredient overlap counts
+	minIngredients = 3
+)
+
+// titleStopwords carry no meaning about which dish a recipe is
+var titleStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true, "with": true,
+	"my": true, "recipe": true, "easy": true, "quick": true, "simple": true,
+	"best": true, "homemade": true, "perfect": true, "ultimate": true,
+	"authentic": true, "classic": true, "how": true, "to": true, "make": true,
+}
+
+// candidate is a recipe prepared for comparison
+type candidate struct {
+	id          uuid.UUID
+	trigrams    map[string]bool
+	ingredients map[string]bool
+}
+
+func prepare(c model.DuplicateCandidate) candidate {
+	ingredients := make(map[string]bool, len(c.Ingredients))
+	for _, name := range c.Ingredients {
+		if name != "" {
+			ingredients[name] = true
+		}
+	}
+	return candidate{id: c.RecipeID, trigrams: titleTrigrams(c.Title), ingredients: ingredients}
+}
+
+// titleTrigrams splits a title into trigrams the way pg_trgm does, after
+// dropping stopwords and plurals, so "The Best Chocolate Chip Cookies" and
+// "chocolate chip cookie" compare equal
+func titleTrigrams(title string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	trigrams := make(map[string]bool)
+	for _, w := range words {
+		if titleStopwords[w] {
+			continue
+		}
+		padded := []rune("  " + singular(w) + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			trigrams[string(padded[i:i+3])] = true
+		}
+	}
+	return trigrams
+}
+
+// Similarity scores how likely two recipes are the same dish, from 0 to 1,
+// and reports whether the score reaches the threshold that applies to them
+func Similarity(a, b model.DuplicateCandidate) (float64, bool) {
+	return similarity(prepare(a), prepare(b))
+}
+
+func similarity(a, b candidate) (float64, bool) {
+	title := jaccard(a.trigrams, b.trigrams)
+	if len(a.ingredients) < minIngredients || len(b.ingredients) < minIngredients {
+		return title, title >= TitleOnlyThreshold
+	}
+
+	// Overlap against the shorter list, so a blog version listing a few
+	// extra garnishes still matches the video version
+	shared := 0
+	for name := range a.ingredients {
+		if b.ingredients[name] {
+			shared++
+		}
+	}
+	overlap := float64(shared) / float64(min(len(a.ingredients), len(b.ingredients)))
+
+	score := titleWeight*title + ingredientWeight*overlap
+	return score, score >= Threshold
+}
+
+// singular drops a plural "s" from a title word. Unlike ingredient names,
+// "-ies" is left as "-ie" so "cookies" meets "cookie"; the trigram
+// comparison absorbs what this misses, like "tomatoes" and "tomato".
+func singular(word string) string {
+	if len(word) > 3 && strings.HasSuffix(word, "s") &&
+		!strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is") {
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// Cluster groups recipes that look like the same dish. Any two recipes
+// scoring at least the threshold end up in the same group; a group's score
+// is the average of those pair scores. Groups are returned best first, with
+// their recipe IDs sorted so the same set always compares equal.
+func Cluster(candidates []model.DuplicateCandidate) []model.DuplicateGroup {
+	prepared := make([]candidate, len(candidates))
+	for i, c := range candidates {
+		prepared[i] = prepare(c)
+	}
+
+	parent := make([]int, len(prepared))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	type pair struct {
+		i     int
+		score float64
+	}
+	var pairs []pair
+	for i := range prepared {
+		for j := i + 1; j < len(prepared); j++ {
+			score, ok := similarity(prepared[i], prepared[j])
+			if !ok {
+				continue
+			}
+			parent[find(j)] = find(i)
+			pairs = append(pairs, pair{i: i, score: score})
+		}
+	}
+
+	members := make(map[int][]uuid.UUID)
+	for i, c := range prepared {
+		root := find(i)
+		members[root] = append(members[root], c.id)
+	}
+	totals := make(map[int]float64)
+	counts := make(map[int]int)
+	for _, p := range pairs {
+		root := find(p.i)
+		totals[root] += p.score
+		counts[root]++
+	}
+
+	var groups []model.DuplicateGroup
+	for root, ids := range members {
+		if len(ids) < 2 {
+			continue
+		}
+		slices.SortFunc(ids, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+		groups = append(groups, model.DuplicateGroup{
+			RecipeIDs: ids,
+			Score:     totals[root] / float64(counts[root]),
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Score != groups[j].Score {
+			return groups[i].Score > groups[j].Score
+		}
+		return groups[i].RecipeIDs[0].String() < groups[j].RecipeIDs[0].String()
+	})
+	return groups
+}
@@ -0,0 +1,104 @@
+package dedup
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+func recipe(title string, ingredients ...string) model.DuplicateCandidate {
+	return model.DuplicateCandidate{RecipeID: uuid.New(), Title: title, Ingredients: ingredients}
+}
+
+func TestSimilarity(t *testing.T) {
+	carbonara := []string{"spaghetti", "egg", "guanciale", "pecorino romano", "black pepper"}
+
+	tests := []struct {
+		name string
+		a, b model.DuplicateCandidate
+		want bool
+	}{
+		{
+			"same dish from video and blog",
+			recipe("Spaghetti Carbonara", carbonara...),
+			recipe("The Best Authentic Carbonara (Spaghetti)", append(carbonara, "salt")...),
+			true,
+		},
+		{
+			"same ingredients, different dish",
+			recipe("Fluffy Pancakes", "flour", "egg", "milk", "butter", "sugar"),
+			recipe("French Crepes", "flour", "egg", "milk", "butter", "sugar"),
+			false,
+		},
+		{
+			"same title, different ingredients",
+			recipe("Curry", "chicken", "coconut milk", "curry paste", "rice"),
+			recipe("Curry", "potato", "carrot", "roux", "onion"),
+			false,
+		},
+		{
+			"titles only, plural and stopwords ignored",
+			recipe("Chocolate Chip Cookies"),
+			recipe("Easy chocolate chip cookie recipe", "flour", "butter", "chocolate chip"),
+			true,
+		},
+		{
+			"titles only, different dish",
+			recipe("Chocolate Chip Cookies"),
+			recipe("Chocolate Cake"),
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, got := Similarity(tt.a, tt.b)
+			if got != tt.want {
+				t.Errorf("Similarity() = %.2f, duplicate %v, want %v", score, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCluster(t *testing.T) {
+	soup := []string{"tomato", "onion", "garlic", "basil", "cream"}
+	a := recipe("Tomato Soup", soup...)
+	b := recipe("Creamy Tomato Soup", soup...)
+	c := recipe("tomato soup", soup[:4]...)
+	other := recipe("Banana Bread", "banana", "flour", "egg", "butter")
+
+	groups := Cluster([]model.DuplicateCandidate{a, other, b, c})
+
+	if len(groups) != 1 {
+		t.Fatalf("got %d groups, want 1: %+v", len(groups), groups)
+	}
+	g := groups[0]
+	if len(g.RecipeIDs) != 3 {
+		t.Fatalf("group has %d recipes, want 3", len(g.RecipeIDs))
+	}
+	for i := 1; i < len(g.RecipeIDs); i++ {
+		if g.RecipeIDs[i-1].String() > g.RecipeIDs[i].String() {
+			t.Error("recipe IDs are not sorted")
+		}
+	}
+	for _, id := range g.RecipeIDs {
+		if id == other.RecipeID {
+			t.Error("banana bread grouped with tomato soup")
+		}
+	}
+	if g.Score < Threshold || g.Score > 1 {
+		t.Errorf("score = %.2f", g.Score)
+	}
+}
+
+func TestCluster_NoDuplicates(t *testing.T) {
+	groups := Cluster([]model.DuplicateCandidate{
+		recipe("Tomato Soup"),
+		recipe("Banana Bread"),
+	})
+	if len(groups) != 0 {
+		t.Errorf("got %d groups, want none", len(groups))
+	}
+}
@@ -0,0 +1,121 @@
+// Package dedup finds near-duplicate recipes in users' libraries, such as
+// the same dish saved from a video and from the creator's blog.
+//
+// A background worker re-analyzes each library whose recipes changed since
+// its last analysis and stores the duplicate groups it finds as suggestions.
+// Merging is left to the user.
+package dedup
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// Repository interface for duplicate analysis storage
+type Repository interface {
+	ListUsersToScan(ctx context.Context, limit int) ([]uuid.UUID, error)
+	ListCandidates(ctx context.Context, userID uuid.UUID) ([]model.DuplicateCandidate, error)
+	SaveGroups(ctx context.Context, userID uuid.UUID, groups []model.DuplicateGroup, scannedAt time.Time) error
+}
+
+// Config holds configuration for the dedup worker
+type Config struct {
+	Interval  time.Duration // How often to look for changed libraries
+	BatchSize int           // Libraries analyzed per run
+}
+
+// Service runs the duplicate analysis in the background
+type Service struct {
+	repo      Repository
+	logger    *slog.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+// NewService creates a new dedup service
+func NewService(repo Repository, logger *slog.Logger, cfg Config) *Service {
+	if cfg.Interval == 0 {
+		cfg.Interval = 15 * time.Minute
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = 100
+	}
+
+	return &Service{
+		repo:      repo,
+		logger:    logger,
+		interval:  cfg.Interval,
+		batchSize: cfg.BatchSize,
+	}
+}
+
+// Start begins the dedup worker in the background
+func (s *Service) Start(ctx context.Context) {
+	s.logger.Info("Starting dedup service",
+		"interval", s.interval,
+		"batch_size", s.batchSize,
+	)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.run(ctx)
+		case <-ctx.Done():
+			s.logger.Info("Dedup service stopping")
+			return
+		}
+	}
+}
+
+// run analyzes a batch of libraries that changed since their last analysis
+func (s *Service) run(ctx context.Context) {
+	userIDs, err := s.repo.ListUsersToScan(ctx, s.batchSize)
+	if err != nil {
+		s.logger.Error("Failed to list libraries to analyze", "error", err)
+		return
+	}
+
+	found := 0
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		groups, err := s.Analyze(ctx, userID)
+		if err != nil {
+			s.logger.Error("Failed to analyze library for duplicates", "user_id", userID, "error", err)
+			continue
+		}
+		found += groups
+	}
+
+	if len(userIDs) > 0 {
+		s.logger.Info("Analyzed libraries for duplicates", "libraries", len(userIDs), "groups", found)
+	}
+}
+
+// Analyze re-clusters one user's recipes and replaces their suggested
+// duplicate groups, returning how many groups were found
+func (s *Service) Analyze(ctx context.Context, userID uuid.UUID) (int, error) {
+	// Taken before loading, so edits made during the analysis are picked
+	// up by the next run
+	scannedAt := time.Now().UTC()
+
+	candidates, err := s.repo.ListCandidates(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	groups := Cluster(candidates)
+	if err := s.repo.SaveGroups(ctx, userID, groups, scannedAt); err != nil {
+		return 0, err
+	}
+	return len(groups), nil
+}
@@ -0,0 +1,121 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/units"
+)
+
+// Cooklang renders a recipe as a Cooklang (.cook) file with YAML front
+// matter. Ingredients are declared up front, one step per section, since
+// steps don't say which ingredients they use. image is the thumbnail URL or
+// file name, if any.
+func Cooklang(recipe *model.Recipe, image string) []byte {
+	var b strings.Builder
+
+	b.WriteString("---\n")
+	yamlField(&b, "title", recipe.Title)
+	if recipe.Description != nil {
+		yamlField(&b, "description", *recipe.Description)
+	}
+	if recipe.Servings != nil && *recipe.Servings > 0 {
+		fmt.Fprintf(&b, "servings: %d\n", *recipe.Servings)
+	}
+	if recipe.PrepTime != nil && *recipe.PrepTime > 0 {
+		yamlField(&b, "prep time", humanDuration(*recipe.PrepTime))
+	}
+	if recipe.CookTime != nil && *recipe.CookTime > 0 {
+		yamlField(&b, "cook time", humanDuration(*recipe.CookTime))
+	}
+	if recipe.Difficulty != nil {
+		yamlField(&b, "difficulty", *recipe.Difficulty)
+	}
+	if recipe.Cuisine != nil {
+		yamlField(&b, "cuisine", *recipe.Cuisine)
+	}
+	if len(recipe.Tags) > 0 {
+		quoted := make([]string, len(recipe.Tags))
+		for i, tag := range recipe.Tags {
+			quoted[i] = strconv.Quote(tag)
+		}
+		fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(quoted, ", "))
+	}
+	if recipe.SourceURL != nil {
+		yamlField(&b, "source", *recipe.SourceURL)
+	}
+	yamlField(&b, "image", image)
+	if lines := nutritionLines(recipe.Nutrition); len(lines) > 0 {
+		b.WriteString("nutrition:\n")
+		for _, line := range lines {
+			label, value, _ := strings.Cut(line, ": ")
+			fmt.Fprintf(&b, "  %s: %s\n", strings.ToLower(label), strconv.Quote(value))
+		}
+	}
+	b.WriteString("---\n")
+
+	groups := sections(recipe.Ingredients)
+	named := len(groups) > 0 && groups[len(groups)-1].Name != ""
+	for _, s := range groups {
+		if s.Name != "" {
+			fmt.Fprintf(&b, "\n== %s ==\n", cooklangText(s.Name))
+		}
+		refs := make([]string, len(s.Ingredients))
+		for i, ing := range s.Ingredients {
+			refs[i] = cooklangIngredient(ing)
+		}
+		fmt.Fprintf(&b, "\n%s\n", strings.Join(refs, ", "))
+	}
+
+	if named && len(recipe.Steps) > 0 {
+		b.WriteString("\n== Method ==\n")
+	}
+	for _, step := range recipe.Steps {
+		fmt.Fprintf(&b, "\n%s\n", cooklangText(strings.Join(strings.Fields(step.Instruction), " ")))
+	}
+
+	return []byte(b.String())
+}
+
+// cooklangIngredient renders "@flour{1 1/2%cups}(sifted)"
+func cooklangIngredient(ing model.RecipeIngredient) string {
+	var amount string
+	if ing.Quantity != nil {
+		amount = units.FormatFraction(*ing.Quantity)
+		if ing.QuantityMax != nil && *ing.QuantityMax > *ing.Quantity {
+			amount += "-" + units.FormatFraction(*ing.QuantityMax)
+		}
+		if ing.Unit != nil && *ing.Unit != "" {
+			amount += "%" + *ing.Unit
+		}
+	}
+
+	ref := "@" + cooklangText(ing.Name) + "{" + amount + "}"
+
+	var notes []string
+	if ing.Notes != nil && *ing.Notes != "" {
+		notes = append(notes, *ing.Notes)
+	}
+	if ing.IsOptional {
+		notes = append(notes, "optional")
+	}
+	if len(notes) > 0 {
+		ref += "(" + strings.NewReplacer("(", "", ")", "").Replace(strings.Join(notes, ", ")) + ")"
+	}
+	return ref
+}
+
+// cooklangText escapes characters that start Cooklang markup
+func cooklangText(s string) string {
+	return strings.NewReplacer("@", `\@`, "#", `\#`, "~", `\~`, "{", `\{`, "}", `\}`).Replace(s)
+}
+
+// yamlField writes a quoted YAML string field, skipping empty values
+func yamlField(b *strings.Builder, key, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "%s: %s\n", key, strconv.Quote(value))
+}
@@ -0,0 +1,257 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// Format is an export file format
+type Format string
+
+const (
+	FormatJSONLD   Format = "jsonld"
+	FormatCooklang Format = "cooklang"
+	FormatMarkdown Format = "markdown"
+	FormatPaprika  Format = "paprika"
+)
+
+// ParseFormat parses a format name, accepting common aliases
+func ParseFormat(s string) (Format, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "jsonld", "json-ld", "json", "schema.org":
+		return FormatJSONLD, true
+	case "cooklang", "cook":
+		return FormatCooklang, true
+	case "markdown", "md":
+		return FormatMarkdown, true
+	case "paprika", "paprikarecipes":
+		return FormatPaprika, true
+	}
+	return "", false
+}
+
+// Image is a recipe thumbnail to include in an export
+type Image struct {
+	Data []byte
+	Ext  string // e.g. ".jpg"
+}
+
+// File is a single exported recipe
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Single exports one recipe as a file. Formats that reference the thumbnail
+// use its URL; Paprika embeds image, when given.
+func Single(format Format, recipe *model.Recipe, image *Image) (*File, error) {
+	name := FileName(recipe.Title)
+	var imageURL string
+	if recipe.ThumbnailURL != nil {
+		imageURL = *recipe.ThumbnailURL
+	}
+
+	switch format {
+	case FormatJSONLD:
+		data, err := json.MarshalIndent(JSONLD(recipe, ""), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &File{Name: name + ".json", ContentType: "application/ld+json", Data: data}, nil
+	case FormatCooklang:
+		return &File{Name: name + ".cook", ContentType: "text/plain; charset=utf-8", Data: Cooklang(recipe, imageURL)}, nil
+	case FormatMarkdown:
+		return &File{Name: name + ".md", ContentType: "text/markdown; charset=utf-8", Data: Markdown(recipe, imageURL)}, nil
+	case FormatPaprika:
+		// Paprika only imports archives, so a single recipe is an archive of one
+		var buf bytes.Buffer
+		a := NewArchive(&buf, FormatPaprika)
+		if err := a.Add(recipe, image); err != nil {
+			return nil, err
+		}
+		if err := a.Close(); err != nil {
+			return nil, err
+		}
+		return &File{Name: name + ".paprikarecipes", ContentType: "application/zip", Data: buf.Bytes()}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// ArchiveName returns the file name for a library export
+func ArchiveName(format Format) string {
+	if format == FormatPaprika {
+		return "recipes.paprikarecipes"
+	}
+	return "recipes-" + string(format) + ".zip"
+}
+
+// Archive streams recipes into a zip. Paprika archives hold one gzipped
+// .paprikarecipe per recipe with the photo embedded; other formats hold one
+// file per recipe with its thumbnail saved alongside under the same name.
+type Archive struct {
+	zw     *zip.Writer
+	format Format
+	names  map[string]int
+}
+
+// NewArchive starts an archive written to w
+func NewArchive(w io.Writer, format Format) *Archive {
+	return &Archive{zw: zip.NewWriter(w), format: format, names: map[string]int{}}
+}
+
+// Add writes a recipe, and its thumbnail if given, to the archive
+func (a *Archive) Add(recipe *model.Recipe, image *Image) error {
+	name := a.uniqueName(FileName(recipe.Title))
+
+	var imageFile string
+	if image != nil && a.format != FormatPaprika {
+		imageFile = name + image.Ext
+		if err := a.write(imageFile, image.Data); err != nil {
+			return err
+		}
+	}
+
+	switch a.format {
+	case FormatJSONLD:
+		data, err := json.MarshalIndent(JSONLD(recipe, ""), "", "  ")
+		if err != nil {
+			return err
+		}
+		return a.write(name+".json", data)
+	case FormatCooklang:
+		return a.write(name+".cook", Cooklang(recipe, imageFile))
+	case FormatMarkdown:
+		return a.write(name+".md", Markdown(recipe, imageFile))
+	case FormatPaprika:
+		data, err := Paprika(recipe, image)
+		if err != nil {
+			return err
+		}
+		return a.write(name+".paprikarecipe", data)
+	}
+	return fmt.Errorf("unsupported export format %q", a.format)
+}
+
+// Close finishes the archive. It does not close the underlying writer.
+func (a *Archive) Close() error {
+	return a.zw.Close()
+}
+
+func (a *Archive) write(name string, data []byte) error {
+	f, err := a.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+// uniqueName suffixes repeated titles: "Pancakes", "Pancakes (2)"
+func (a *Archive) uniqueName(name string) string {
+	key := strings.ToLower(name)
+	a.names[key]++
+	if n := a.names[key]; n > 1 {
+		return fmt.Sprintf("%s (%d)", name, n)
+	}
+	return name
+}
+
+// FileName turns a recipe title into a safe file name without extension
+func FileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r), unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, title)
+	name = strings.Join(strings.Fields(name), " ")
+	name = strings.Trim(name, ". ")
+	if runes := []rune(name); len(runes) > 100 {
+		name = strings.TrimSpace(string(runes[:100]))
+	}
+	if name == "" {
+		return "Recipe"
+	}
+	return name
+}
+
+// section is a run of ingredients under one heading
+type section struct {
+	Name        string
+	Ingredients []model.RecipeIngredient
+}
+
+// sections groups ingredients by section in order of first appearance.
+// Ingredients without a section come first under an unnamed group. A recipe
+// with a single section (extractions file everything under "Main") gets no
+// heading at all.
+func sections(ings []model.RecipeIngredient) []section {
+	var out []section
+	index := map[string]int{}
+	for _, ing := range ings {
+		name := strings.TrimSpace(ing.Section)
+		i, ok := index[name]
+		if !ok {
+			i = len(out)
+			index[name] = i
+			out = append(out, section{Name: name})
+		}
+		out[i].Ingredients = append(out[i].Ingredients, ing)
+	}
+	if len(out) == 1 {
+		out[0].Name = ""
+	}
+	if i, ok := index[""]; ok && i > 0 {
+		unnamed := out[i]
+		copy(out[1:i+1], out[:i])
+		out[0] = unnamed
+	}
+	return out
+}
+
+// humanDuration renders minutes as "1 h 15 min"
+func humanDuration(minutes int) string {
+	h, m := minutes/60, minutes%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%d min", m)
+	case m == 0:
+		return fmt.Sprintf("%d h", h)
+	default:
+		return fmt.Sprintf("%d h %d min", h, m)
+	}
+}
+
+// nutritionLines lists a recipe's nutrition per serving, e.g. "Protein: 12 g"
+func nutritionLines(n *model.RecipeNutrition) []string {
+	if n == nil || n.Calories <= 0 {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("Calories: %d kcal", n.Calories)}
+	for _, v := range []struct {
+		label string
+		value int
+		unit  string
+	}{
+		{"Protein", n.Protein, "g"},
+		{"Carbohydrates", n.Carbs, "g"},
+		{"Fat", n.Fat, "g"},
+		{"Fiber", n.Fiber, "g"},
+		{"Sugar", n.Sugar, "g"},
+		{"Sodium", n.Sodium, "mg"},
+	} {
+		if v.value > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %d %s", v.label, v.value, v.unit))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,171 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func sampleRecipe() *model.Recipe {
+	return &model.Recipe{
+		ID:           uuid.New(),
+		Title:        "Pizza Margherita",
+		Servings:     ptr(2),
+		CookTime:     ptr(90),
+		ThumbnailURL: ptr("https://example.com/pizza.jpg"),
+		Tags:         []string{"italian"},
+		Ingredients: []model.RecipeIngredient{
+			{Name: "flour", Quantity: ptr(500.0), Unit: ptr("g"), Section: "Dough"},
+			{Name: "basil", IsOptional: true},
+			{Name: "tomatoes", Quantity: ptr(400.0), Unit: ptr("g"), Section: "Sauce"},
+			{Name: "water", Quantity: ptr(300.0), Unit: ptr("ml"), Section: "Dough"},
+		},
+		Steps: []model.RecipeStep{
+			{StepNumber: 1, Instruction: "Knead the dough for 10 minutes"},
+			{StepNumber: 2, Instruction: "Top with sauce @ 250C #hot"},
+		},
+		Nutrition: &model.RecipeNutrition{Calories: 800, Protein: 25},
+	}
+}
+
+func TestSections(t *testing.T) {
+	got := sections(sampleRecipe().Ingredients)
+
+	want := []struct {
+		name  string
+		count int
+	}{{"", 1}, {"Dough", 2}, {"Sauce", 1}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d sections, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		if got[i].Name != w.name || len(got[i].Ingredients) != w.count {
+			t.Errorf("section %d: expected %q with %d, got %q with %d", i, w.name, w.count, got[i].Name, len(got[i].Ingredients))
+		}
+	}
+
+	single := sections([]model.RecipeIngredient{{Name: "salt", Section: "Main"}, {Name: "pepper", Section: "Main"}})
+	if len(single) != 1 || single[0].Name != "" {
+		t.Errorf("expected a lone section to be unnamed, got %+v", single)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	md := string(Markdown(sampleRecipe(), "Pizza Margherita.jpg"))
+
+	for _, want := range []string{
+		"# Pizza Margherita\n",
+		"![Pizza Margherita](<Pizza Margherita.jpg>)",
+		"**Servings:** 2 · **Cook:** 1 h 30 min",
+		"- basil (optional)\n\n### Dough\n\n- 500 g flour\n- 300 ml water\n\n### Sauce\n",
+		"1. Knead the dough for 10 minutes\n2. Top with sauce",
+		"## Nutrition\n\nPer serving:\n\n- Calories: 800 kcal\n- Protein: 25 g\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+}
+
+func TestCooklang(t *testing.T) {
+	cook := string(Cooklang(sampleRecipe(), "https://example.com/pizza.jpg"))
+
+	for _, want := range []string{
+		"---\ntitle: \"Pizza Margherita\"\nservings: 2\ncook time: \"1 h 30 min\"\n",
+		`tags: ["italian"]`,
+		`image: "https://example.com/pizza.jpg"`,
+		"nutrition:\n  calories: \"800 kcal\"\n  protein: \"25 g\"\n---\n",
+		"\n@basil{}(optional)\n",
+		"\n== Dough ==\n\n@flour{500%g}, @water{300%ml}\n",
+		"\n== Method ==\n\nKnead the dough",
+		`Top with sauce \@ 250C \#hot`,
+	} {
+		if !strings.Contains(cook, want) {
+			t.Errorf("expected cooklang to contain %q, got:\n%s", want, cook)
+		}
+	}
+}
+
+func TestPaprika(t *testing.T) {
+	recipe := sampleRecipe()
+	data, err := Paprika(recipe, &Image{Data: []byte("jpeg"), Ext: ".jpg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected gzip data: %v", err)
+	}
+	var p paprikaRecipe
+	if err := json.NewDecoder(zr).Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Name != recipe.Title || p.UID != strings.ToUpper(recipe.ID.String()) {
+		t.Errorf("unexpected recipe: %+v", p)
+	}
+	if !strings.Contains(p.Ingredients, "basil (optional)\nDough:\n500 g flour\n300 ml water\nSauce:\n") {
+		t.Errorf("unexpected ingredients: %q", p.Ingredients)
+	}
+	if p.PhotoData != "anBlZw==" || p.Photo == "" || p.Hash == "" {
+		t.Errorf("expected embedded photo and hash, got %q %q %q", p.PhotoData, p.Photo, p.Hash)
+	}
+	if !strings.HasPrefix(p.NutritionalInfo, "Calories: 800 kcal") {
+		t.Errorf("unexpected nutrition: %q", p.NutritionalInfo)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	var buf bytes.Buffer
+	a := NewArchive(&buf, FormatMarkdown)
+	if err := a.Add(sampleRecipe(), &Image{Data: []byte("img"), Ext: ".png"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Add(sampleRecipe(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	want := "Pizza Margherita.png,Pizza Margherita.md,Pizza Margherita (2).md"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("expected files %s, got %s", want, got)
+	}
+
+	f, _ := zr.File[1].Open()
+	md, _ := io.ReadAll(f)
+	if !strings.Contains(string(md), "(<Pizza Margherita.png>)") {
+		t.Error("expected markdown to reference the image in the archive")
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := map[string]string{
+		"Mac & Cheese":       "Mac & Cheese",
+		"1/2 Batch: Cookies": "12 Batch Cookies",
+		"  ...  ":            "Recipe",
+		"Tab\tTitle?":        "TabTitle",
+	}
+	for in, want := range tests {
+		if got := FileName(in); got != want {
+			t.Errorf("FileName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package export renders recipes in formats other apps and sites understand.
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/units"
+)
+
+// JSONLDRecipe is a schema.org Recipe, as read by search engines and
+// recipe managers
+type JSONLDRecipe struct {
+	Context            string           `json:"@context"`
+	Type               string           `json:"@type"`
+	Name               string           `json:"name"`
+	Description        string           `json:"description,omitempty"`
+	Image              []string         `json:"image,omitempty"`
+	URL                string           `json:"url,omitempty"`
+	RecipeYield        string           `json:"recipeYield,omitempty"`
+	PrepTime           string           `json:"prepTime,omitempty"`
+	CookTime           string           `json:"cookTime,omitempty"`
+	TotalTime          string           `json:"totalTime,omitempty"`
+	RecipeCuisine      string           `json:"recipeCuisine,omitempty"`
+	Keywords           string           `json:"keywords,omitempty"`
+	RecipeIngredient   []string         `json:"recipeIngredient"`
+	RecipeInstructions []JSONLDStep     `json:"recipeInstructions"`
+	Nutrition          *JSONLDNutrition `json:"nutrition,omitempty"`
+	IsBasedOn          string           `json:"isBasedOn,omitempty"`
+	DatePublished      string           `json:"datePublished,omitempty"`
+	SuitableForDiet    []string         `json:"suitableForDiet,omitempty"`
+}
+
+// JSONLDStep is a schema.org HowToStep
+type JSONLDStep struct {
+	Type string `json:"@type"`
+	Text string `json:"text"`
+}
+
+// JSONLDNutrition is a schema.org NutritionInformation
+type JSONLDNutrition struct {
+	Type                string `json:"@type"`
+	Calories            string `json:"calories,omitempty"`
+	ProteinContent      string `json:"proteinContent,omitempty"`
+	CarbohydrateContent string `json:"carbohydrateContent,omitempty"`
+	FatContent          string `json:"fatContent,omitempty"`
+	FiberContent        string `json:"fiberContent,omitempty"`
+	SugarContent        string `json:"sugarContent,omitempty"`
+	SodiumContent       string `json:"sodiumContent,omitempty"`
+}
+
+// JSONLD converts a recipe to schema.org Recipe. url is the page the recipe
+// is published at, if any.
+func JSONLD(recipe *model.Recipe, url string) *JSONLDRecipe {
+	out := &JSONLDRecipe{
+		Context:            "https://schema.org",
+		Type:               "Recipe",
+		Name:               recipe.Title,
+		URL:                url,
+		PrepTime:           isoDuration(recipe.PrepTime),
+		CookTime:           isoDuration(recipe.CookTime),
+		Keywords:           strings.Join(recipe.Tags, ", "),
+		RecipeIngredient:   make([]string, 0, len(recipe.Ingredients)),
+		RecipeInstructions: make([]JSONLDStep, 0, len(recipe.Steps)),
+	}
+	if !recipe.CreatedAt.IsZero() {
+		out.DatePublished = recipe.CreatedAt.Format("2006-01-02")
+	}
+	if recipe.Description != nil {
+		out.Description = *recipe.Description
+	}
+	if recipe.ThumbnailURL != nil && *recipe.ThumbnailURL != "" {
+		out.Image = []string{*recipe.ThumbnailURL}
+	}
+	if recipe.Servings != nil && *recipe.Servings > 0 {
+		out.RecipeYield = fmt.Sprintf("%d servings", *recipe.Servings)
+	}
+	if total := recipe.TotalTime(); total > 0 {
+		out.TotalTime = isoDuration(&total)
+	}
+	if recipe.Cuisine != nil {
+		out.RecipeCuisine = *recipe.Cuisine
+	}
+	if recipe.SourceURL != nil {
+		out.IsBasedOn = *recipe.SourceURL
+	}
+
+	for _, ing := range recipe.Ingredients {
+		out.RecipeIngredient = append(out.RecipeIngredient, IngredientLine(ing))
+	}
+	for _, step := range recipe.Steps {
+		out.RecipeInstructions = append(out.RecipeInstructions, JSONLDStep{Type: "HowToStep", Text: step.Instruction})
+	}
+
+	if n := recipe.Nutrition; n != nil && n.Calories > 0 {
+		out.Nutrition = &JSONLDNutrition{
+			Type:                "NutritionInformation",
+			Calories:            fmt.Sprintf("%d calories", n.Calories),
+			ProteinContent:      nutrient(n.Protein, "g"),
+			CarbohydrateContent: nutrient(n.Carbs, "g"),
+			FatContent:          nutrient(n.Fat, "g"),
+			FiberContent:        nutrient(n.Fiber, "g"),
+			SugarContent:        nutrient(n.Sugar, "g"),
+			SodiumContent:       nutrient(n.Sodium, "mg"),
+		}
+	}
+
+	if d := recipe.DietaryInfo; d != nil {
+		for _, diet := range []struct {
+			ok   bool
+			name string
+		}{
+			{d.IsVegan, "VeganDiet"},
+			{d.IsVegetarian, "VegetarianDiet"},
+			{d.IsGlutenFree, "GlutenFreeDiet"},
+			{d.IsHalal, "HalalDiet"},
+			{d.IsKosher, "KosherDiet"},
+		} {
+			if diet.ok {
+				out.SuitableForDiet = append(out.SuitableForDiet, "https://schema.org/"+diet.name)
+			}
+		}
+	}
+
+	return out
+}
+
+// IngredientLine renders an ingredient as a single line,
+// e.g. "1 1/2 cups flour, sifted" or "2-3 cloves garlic (optional)"
+func IngredientLine(ing model.RecipeIngredient) string {
+	var parts []string
+	if ing.Quantity != nil {
+		qty := units.FormatFraction(*ing.Quantity)
+		if ing.QuantityMax != nil && *ing.QuantityMax > *ing.Quantity {
+			qty += "-" + units.FormatFraction(*ing.QuantityMax)
+		}
+		parts = append(parts, qty)
+	}
+	if ing.Unit != nil && *ing.Unit != "" {
+		parts = append(parts, *ing.Unit)
+	}
+	parts = append(parts, ing.Name)
+
+	line := strings.Join(parts, " ")
+	if ing.Notes != nil && *ing.Notes != "" {
+		line += ", " + *ing.Notes
+	}
+	if ing.IsOptional {
+		line += " (optional)"
+	}
+	return line
+}
+
+// isoDuration renders minutes as an ISO 8601 duration, e.g. PT1H30M
+func isoDuration(minutes *int) string {
+	if minutes == nil || *minutes <= 0 {
+		return ""
+	}
+	h, m := *minutes/60, *minutes%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("PT%dM", m)
+	case m == 0:
+		return fmt.Sprintf("PT%dH", h)
+	default:
+		return fmt.Sprintf("PT%dH%dM", h, m)
+	}
+}
+
+func nutrient(v int, unit string) string {
+	if v <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d %s", v, unit)
+}
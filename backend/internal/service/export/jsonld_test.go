@@ -0,0 +1,51 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestJSONLD(t *testing.T) {
+	recipe := &model.Recipe{
+		Title:    "Banana Bread",
+		Servings: ptr(8),
+		PrepTime: ptr(15),
+		CookTime: ptr(60),
+		Tags:     []string{"baking", "breakfast"},
+		Ingredients: []model.RecipeIngredient{
+			{Name: "flour", Quantity: ptr(1.5), Unit: ptr("cups"), Notes: ptr("sifted")},
+			{Name: "bananas", Quantity: ptr(2.0), QuantityMax: ptr(3.0)},
+			{Name: "walnuts", IsOptional: true},
+		},
+		Steps:       []model.RecipeStep{{StepNumber: 1, Instruction: "Mash the bananas"}},
+		Nutrition:   &model.RecipeNutrition{Calories: 250, Protein: 4},
+		DietaryInfo: &model.DietaryInfo{IsVegetarian: true},
+	}
+
+	out := JSONLD(recipe, "https://example.com/r/abc")
+
+	if out.PrepTime != "PT15M" || out.CookTime != "PT1H" || out.TotalTime != "PT1H15M" {
+		t.Errorf("unexpected durations: %s %s %s", out.PrepTime, out.CookTime, out.TotalTime)
+	}
+	if out.RecipeYield != "8 servings" || out.Keywords != "baking, breakfast" {
+		t.Errorf("unexpected yield/keywords: %q %q", out.RecipeYield, out.Keywords)
+	}
+	want := []string{"1 1/2 cups flour, sifted", "2-3 bananas", "walnuts (optional)"}
+	for i, line := range want {
+		if out.RecipeIngredient[i] != line {
+			t.Errorf("ingredient %d: expected %q, got %q", i, line, out.RecipeIngredient[i])
+		}
+	}
+	if len(out.RecipeInstructions) != 1 || out.RecipeInstructions[0].Type != "HowToStep" {
+		t.Errorf("unexpected instructions: %+v", out.RecipeInstructions)
+	}
+	if out.Nutrition == nil || out.Nutrition.Calories != "250 calories" || out.Nutrition.FatContent != "" {
+		t.Errorf("unexpected nutrition: %+v", out.Nutrition)
+	}
+	if len(out.SuitableForDiet) != 1 || out.SuitableForDiet[0] != "https://schema.org/VegetarianDiet" {
+		t.Errorf("unexpected diets: %v", out.SuitableForDiet)
+	}
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// Markdown renders a recipe as a Markdown document. image is the thumbnail
+// URL or file name to show, if any.
+func Markdown(recipe *model.Recipe, image string) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", recipe.Title)
+	if image != "" {
+		fmt.Fprintf(&b, "![%s](%s)\n\n", recipe.Title, markdownURL(image))
+	}
+	if recipe.Description != nil && *recipe.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", *recipe.Description)
+	}
+
+	var facts []string
+	if recipe.Servings != nil && *recipe.Servings > 0 {
+		facts = append(facts, fmt.Sprintf("**Servings:** %d", *recipe.Servings))
+	}
+	if recipe.PrepTime != nil && *recipe.PrepTime > 0 {
+		facts = append(facts, "**Prep:** "+humanDuration(*recipe.PrepTime))
+	}
+	if recipe.CookTime != nil && *recipe.CookTime > 0 {
+		facts = append(facts, "**Cook:** "+humanDuration(*recipe.CookTime))
+	}
+	if recipe.Difficulty != nil && *recipe.Difficulty != "" {
+		facts = append(facts, "**Difficulty:** "+*recipe.Difficulty)
+	}
+	if recipe.Cuisine != nil && *recipe.Cuisine != "" {
+		facts = append(facts, "**Cuisine:** "+*recipe.Cuisine)
+	}
+	if len(facts) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(facts, " · "))
+	}
+	if len(recipe.Tags) > 0 {
+		fmt.Fprintf(&b, "**Tags:** %s\n\n", strings.Join(recipe.Tags, ", "))
+	}
+
+	if len(recipe.Ingredients) > 0 {
+		b.WriteString("## Ingredients\n\n")
+		for _, s := range sections(recipe.Ingredients) {
+			if s.Name != "" {
+				fmt.Fprintf(&b, "### %s\n\n", s.Name)
+			}
+			for _, ing := range s.Ingredients {
+				fmt.Fprintf(&b, "- %s\n", IngredientLine(ing))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(recipe.Steps) > 0 {
+		b.WriteString("## Steps\n\n")
+		for i, step := range recipe.Steps {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, strings.Join(strings.Fields(step.Instruction), " "))
+		}
+		b.WriteString("\n")
+	}
+
+	if lines := nutritionLines(recipe.Nutrition); len(lines) > 0 {
+		b.WriteString("## Nutrition\n\nPer serving:\n\n")
+		for _, line := range lines {
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+
+	if recipe.SourceURL != nil && *recipe.SourceURL != "" {
+		fmt.Fprintf(&b, "Source: <%s>\n", *recipe.SourceURL)
+	}
+
+	return []byte(strings.TrimRight(b.String(), "\n") + "\n")
+}
+
+// markdownURL wraps file names with spaces so they stay one link target
+func markdownURL(s string) string {
+	if strings.ContainsAny(s, " ()") {
+		return "<" + s + ">"
+	}
+	return s
+}
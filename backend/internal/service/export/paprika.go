@@ -0,0 +1,126 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// paprikaRecipe is the JSON inside a .paprikarecipe file
+type paprikaRecipe struct {
+	UID             string   `json:"uid"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	Ingredients     string   `json:"ingredients"`
+	Directions      string   `json:"directions"`
+	Notes           string   `json:"notes"`
+	NutritionalInfo string   `json:"nutritional_info"`
+	Servings        string   `json:"servings"`
+	PrepTime        string   `json:"prep_time"`
+	CookTime        string   `json:"cook_time"`
+	TotalTime       string   `json:"total_time"`
+	Difficulty      string   `json:"difficulty"`
+	Source          string   `json:"source"`
+	SourceURL       string   `json:"source_url"`
+	ImageURL        string   `json:"image_url"`
+	Photo           string   `json:"photo"`
+	PhotoData       string   `json:"photo_data"`
+	PhotoHash       string   `json:"photo_hash"`
+	Categories      []string `json:"categories"`
+	Rating          int      `json:"rating"`
+	Created         string   `json:"created"`
+	Hash            string   `json:"hash"`
+}
+
+// Paprika renders a recipe as a gzipped .paprikarecipe file, with the
+// thumbnail embedded when given. Ingredient sections become header lines,
+// which Paprika shows in bold.
+func Paprika(recipe *model.Recipe, image *Image) ([]byte, error) {
+	p := paprikaRecipe{
+		UID:        strings.ToUpper(recipe.ID.String()),
+		Name:       recipe.Title,
+		Categories: recipe.Tags,
+		Created:    recipe.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+	}
+	if p.Categories == nil {
+		p.Categories = []string{}
+	}
+	if recipe.Description != nil {
+		p.Description = *recipe.Description
+	}
+	if recipe.Servings != nil && *recipe.Servings > 0 {
+		p.Servings = fmt.Sprintf("%d", *recipe.Servings)
+	}
+	if recipe.PrepTime != nil && *recipe.PrepTime > 0 {
+		p.PrepTime = humanDuration(*recipe.PrepTime)
+	}
+	if recipe.CookTime != nil && *recipe.CookTime > 0 {
+		p.CookTime = humanDuration(*recipe.CookTime)
+	}
+	if total := recipe.TotalTime(); total > 0 {
+		p.TotalTime = humanDuration(total)
+	}
+	if recipe.Difficulty != nil {
+		p.Difficulty = *recipe.Difficulty
+	}
+	if recipe.SourceURL != nil {
+		p.SourceURL = *recipe.SourceURL
+	}
+	if recipe.ThumbnailURL != nil {
+		p.ImageURL = *recipe.ThumbnailURL
+	}
+
+	var ingredients []string
+	for _, s := range sections(recipe.Ingredients) {
+		if s.Name != "" {
+			ingredients = append(ingredients, s.Name+":")
+		}
+		for _, ing := range s.Ingredients {
+			ingredients = append(ingredients, IngredientLine(ing))
+		}
+	}
+	p.Ingredients = strings.Join(ingredients, "\n")
+
+	steps := make([]string, len(recipe.Steps))
+	for i, step := range recipe.Steps {
+		steps[i] = strings.TrimSpace(step.Instruction)
+	}
+	p.Directions = strings.Join(steps, "\n\n")
+	p.NutritionalInfo = strings.Join(nutritionLines(recipe.Nutrition), "\n")
+
+	if image != nil && len(image.Data) > 0 {
+		sum := sha256.Sum256(image.Data)
+		p.Photo = strings.ToUpper(recipe.ID.String()) + image.Ext
+		p.PhotoData = base64.StdEncoding.EncodeToString(image.Data)
+		p.PhotoHash = strings.ToUpper(hex.EncodeToString(sum[:]))
+	}
+
+	// Paprika uses the hash to detect changed recipes on re-import
+	content, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	p.Hash = strings.ToUpper(hex.EncodeToString(sum[:]))
+	content, err = json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
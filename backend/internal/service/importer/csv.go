@@ -0,0 +1,155 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// csvAliases lists the header names accepted for each draft field
+var csvAliases = map[string][]string{
+	"title":       {"title", "name", "recipe", "recipe name"},
+	"description": {"description", "summary"},
+	"servings":    {"servings", "serves", "yield", "portions"},
+	"prep":        {"prep time", "prep", "preparation time"},
+	"cook":        {"cook time", "cook", "cooking time"},
+	"total":       {"total time", "time"},
+	"ingredients": {"ingredients"},
+	"steps":       {"directions", "instructions", "steps", "method"},
+	"url":         {"url", "source", "source url", "link"},
+	"image":       {"image", "image url", "photo", "photo url"},
+	"tags":        {"tags", "categories", "category", "keywords"},
+	"cuisine":     {"cuisine"},
+	"difficulty":  {"difficulty"},
+	"nutrition":   {"nutrition"},
+	"calories":    {"calories"},
+}
+
+// csvField maps a header name to its draft field, or "" when unknown
+func csvField(header string) string {
+	key := strings.ToLower(strings.Join(strings.Fields(strings.NewReplacer("_", " ", "-", " ").Replace(header)), " "))
+	for field, aliases := range csvAliases {
+		if slices.Contains(aliases, key) {
+			return field
+		}
+	}
+	return ""
+}
+
+// parseCSV reads a spreadsheet with one recipe per row and a header row.
+// Ingredients and directions are one per line within their cell, or
+// separated by "|" when the cell is a single line.
+func parseCSV(data []byte) ([]Entry, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel's UTF-8 BOM
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	if semicolonSeparated(data) {
+		r.Comma = ';'
+	}
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	fields := make([]string, len(header))
+	hasTitle := false
+	for i, h := range header {
+		fields[i] = csvField(h)
+		hasTitle = hasTitle || fields[i] == "title"
+	}
+	if !hasTitle {
+		return nil, errors.New("CSV needs a title or name column")
+	}
+
+	var entries []Entry
+	for row := 2; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(entries) > MaxEntries {
+			break // Parse reports the overflow
+		}
+		if d := csvDraft(fields, record); d != nil {
+			entries = append(entries, Entry{Name: fmt.Sprintf("Row %d", row), Draft: d})
+		}
+	}
+	return entries, nil
+}
+
+// csvDraft maps a row, returning nil for blank rows
+func csvDraft(fields, record []string) *Draft {
+	d := &Draft{}
+	blank := true
+	var total int
+	for i, value := range record {
+		if i >= len(fields) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		blank = false
+		switch fields[i] {
+		case "title":
+			d.Title = value
+		case "description":
+			d.Description = strings.TrimSpace(value)
+		case "servings":
+			d.Servings = parseServings(value)
+		case "prep":
+			d.PrepTime = parseMinutes(value)
+		case "cook":
+			d.CookTime = parseMinutes(value)
+		case "total":
+			total = parseMinutes(value)
+		case "ingredients":
+			d.Ingredients = ingredientLines(cellLines(value))
+		case "steps":
+			d.Steps = stepLines(cellLines(value))
+		case "url":
+			d.SourceURL = strings.TrimSpace(value)
+		case "image":
+			d.ImageURL = strings.TrimSpace(value)
+		case "tags":
+			d.Tags = splitList(value)
+		case "cuisine":
+			d.Cuisine = strings.TrimSpace(value)
+		case "difficulty":
+			d.Difficulty = value
+		case "nutrition":
+			d.Nutrition = parseNutritionText(value)
+		case "calories":
+			if d.Nutrition == nil {
+				d.Nutrition = parseNutritionText("calories " + value)
+			}
+		}
+	}
+	if blank {
+		return nil
+	}
+	if d.PrepTime == 0 && d.CookTime == 0 {
+		d.CookTime = total
+	}
+	return d
+}
+
+// cellLines turns a single-line "a | b | c" cell into one item per line
+func cellLines(value string) string {
+	if !strings.Contains(value, "\n") && strings.Contains(value, "|") {
+		return strings.ReplaceAll(value, "|", "\n")
+	}
+	return value
+}
+
+// semicolonSeparated detects the semicolon-delimited CSV that spreadsheets
+// write in locales using a decimal comma
+func semicolonSeparated(data []byte) bool {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	return bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(","))
+}
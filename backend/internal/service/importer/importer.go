@@ -0,0 +1,499 @@
+// Package importer reads recipe libraries exported from other recipe
+// managers (Paprika, Mealie, Tandoor, Whisk) or a CSV file and maps them to
+// recipes.
+//
+// Each format parser produces Drafts with ingredients as plain text lines;
+// ToRecipe runs them through the deterministic ingredient parser so imported
+// recipes get the same quantities and units as extracted ones. A broken entry
+// in an archive fails on its own without failing the whole import.
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/ingredient"
+)
+
+// Format is an import file format
+type Format string
+
+const (
+	FormatPaprika Format = "paprika"
+	FormatMealie  Format = "mealie"
+	FormatTandoor Format = "tandoor"
+	FormatWhisk   Format = "whisk"
+	FormatCSV     Format = "csv"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{FormatPaprika, FormatMealie, FormatTandoor, FormatWhisk, FormatCSV}
+
+// Limits guarding against oversized and malicious archives
+const (
+	MaxEntries   = 2000      // recipes per import
+	maxEntrySize = 20 << 20  // uncompressed bytes per archive entry
+	maxTotalSize = 512 << 20 // uncompressed bytes per import
+	maxImageSize = 5 << 20   // matches the thumbnail store's limit
+)
+
+var (
+	// ErrUnsupportedFormat is returned for an unknown format name
+	ErrUnsupportedFormat = errors.New("unsupported import format")
+	// ErrNoRecipes is returned when a file contains no recipes at all
+	ErrNoRecipes = errors.New("no recipes found in file")
+)
+
+// ParseFormat parses a format name, accepting common aliases
+func ParseFormat(s string) (Format, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "paprika", "paprikarecipes":
+		return FormatPaprika, true
+	case "mealie":
+		return FormatMealie, true
+	case "tandoor":
+		return FormatTandoor, true
+	case "whisk", "samsung-food", "schema.org", "jsonld", "json-ld":
+		return FormatWhisk, true
+	case "csv":
+		return FormatCSV, true
+	}
+	return "", false
+}
+
+// Draft is a recipe read from an import file, before ingredient parsing
+type Draft struct {
+	Title       string
+	Description string
+	Servings    int
+	PrepTime    int // minutes
+	CookTime    int // minutes
+	Cuisine     string
+	Difficulty  string
+	SourceURL   string
+	ImageURL    string
+	Image       *Image // photo embedded in the archive
+	Tags        []string
+	Ingredients []IngredientLine
+	Steps       []string
+	Nutrition   *model.RecipeNutrition
+}
+
+// IngredientLine is a raw ingredient line and the section it appeared under
+type IngredientLine struct {
+	Text    string
+	Section string
+}
+
+// Image is a photo embedded in an import archive
+type Image struct {
+	Data []byte
+	Ext  string // e.g. ".jpg"
+}
+
+// Entry is one recipe in an import file. Name identifies it in results
+// when the recipe couldn't be read.
+type Entry struct {
+	Name  string
+	Draft *Draft
+	Err   error
+}
+
+// Parse reads all recipes in a file. It fails only when the file as a whole
+// can't be read; problems with single recipes are reported on their Entry.
+func Parse(format Format, data []byte) ([]Entry, error) {
+	var entries []Entry
+	var err error
+	switch format {
+	case FormatPaprika:
+		entries, err = parsePaprika(data)
+	case FormatMealie:
+		entries, err = parseMealie(data)
+	case FormatTandoor:
+		entries, err = parseTandoor(data)
+	case FormatWhisk:
+		entries, err = parseSchemaOrg(data)
+	case FormatCSV:
+		entries, err = parseCSV(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrNoRecipes
+	}
+	if len(entries) > MaxEntries {
+		return nil, fmt.Errorf("file has %d recipes, the maximum per import is %d", len(entries), MaxEntries)
+	}
+
+	for i := range entries {
+		if entries[i].Err == nil && entries[i].Draft != nil {
+			entries[i].Err = entries[i].Draft.validate()
+			if entries[i].Name == "" {
+				entries[i].Name = entries[i].Draft.Title
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (d *Draft) validate() error {
+	d.Title = strings.TrimSpace(d.Title)
+	if d.Title == "" {
+		return errors.New("recipe has no title")
+	}
+	if len(d.Ingredients) == 0 && len(d.Steps) == 0 {
+		return errors.New("recipe has no ingredients or steps")
+	}
+	return nil
+}
+
+// ToRecipe maps a draft to a new recipe owned by userID, parsing its
+// ingredient lines
+func ToRecipe(d *Draft, userID uuid.UUID) *model.Recipe {
+	now := time.Now().UTC()
+	recipe := &model.Recipe{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Title:       truncate(d.Title, 255),
+		Description: optional(d.Description),
+		Servings:    positive(d.Servings),
+		PrepTime:    positive(d.PrepTime),
+		CookTime:    positive(d.CookTime),
+		Difficulty:  optional(normalizeDifficulty(d.Difficulty)),
+		Cuisine:     optional(truncate(d.Cuisine, 100)),
+		SourceType:  "import",
+		SourceURL:   optional(d.SourceURL),
+		Tags:        d.Tags,
+		Nutrition:   d.Nutrition,
+		SyncVersion: 1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if d.ImageURL != "" {
+		recipe.ThumbnailURL = &d.ImageURL
+	}
+
+	for _, l := range d.Ingredients {
+		line := ingredient.Parse(l.Text)
+		name := strings.TrimSpace(line.Name)
+		if name == "" {
+			continue
+		}
+		section := strings.TrimSpace(l.Section)
+		if section == "" {
+			section = "Main"
+		}
+		recipe.Ingredients = append(recipe.Ingredients, model.RecipeIngredient{
+			ID:          uuid.New(),
+			RecipeID:    recipe.ID,
+			Name:        truncate(name, 255),
+			Quantity:    line.Quantity,
+			QuantityMax: line.QuantityMax,
+			Unit:        optional(truncate(line.Unit, 50)),
+			Category:    model.NormalizeCategory(""),
+			Section:     section,
+			IsOptional:  line.IsOptional,
+			Notes:       optional(line.AllNotes()),
+			SortOrder:   len(recipe.Ingredients),
+		})
+	}
+
+	for _, s := range d.Steps {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		recipe.Steps = append(recipe.Steps, model.RecipeStep{
+			ID:          uuid.New(),
+			RecipeID:    recipe.ID,
+			StepNumber:  len(recipe.Steps) + 1,
+			Instruction: s,
+		})
+	}
+
+	return recipe
+}
+
+// readZip opens a zip archive
+func readZip(data []byte) (*zip.Reader, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid zip archive: %w", err)
+	}
+	return zr, nil
+}
+
+// unzipper reads archive entries within a budget of uncompressed bytes
+// shared by nested archives, so a small upload can't expand without bound
+type unzipper struct {
+	remaining int64
+}
+
+func newUnzipper() *unzipper {
+	return &unzipper{remaining: maxTotalSize}
+}
+
+// read reads a zip entry, refusing ones that expand past maxEntrySize
+func (u *unzipper) read(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	limit := min(int64(maxEntrySize), u.remaining)
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		if limit < maxEntrySize {
+			return nil, errTooLarge
+		}
+		return nil, fmt.Errorf("%s is too large", f.Name)
+	}
+	u.remaining -= int64(len(data))
+	return data, nil
+}
+
+// errTooLarge aborts an import whose archive expands past maxTotalSize
+var errTooLarge = errors.New("archive is too large once uncompressed")
+
+// isZip reports whether data starts with a zip signature
+func isZip(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04"))
+}
+
+// newImage wraps embedded photo data, dropping anything that isn't a
+// supported image or is too large to store as a thumbnail
+func newImage(data []byte) *Image {
+	if len(data) == 0 || len(data) > maxImageSize {
+		return nil
+	}
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return &Image{Data: data, Ext: ".jpg"}
+	case "image/png":
+		return &Image{Data: data, Ext: ".png"}
+	case "image/webp":
+		return &Image{Data: data, Ext: ".webp"}
+	case "image/gif":
+		return &Image{Data: data, Ext: ".gif"}
+	}
+	return nil
+}
+
+var (
+	isoDurationRe = regexp.MustCompile(`(?i)^P(?:(\d+)D)?T?(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:\d+(?:\.\d+)?S)?$`)
+	clockRe       = regexp.MustCompile(`^(\d+):(\d{2})$`)
+	durationRe    = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(days?|d|hours?|hrs?|h|heures?|horas?|minutes?|mins?|m|minutos?)?\b`)
+	firstIntRe    = regexp.MustCompile(`\d+`)
+)
+
+// parseMinutes reads a duration such as "PT1H30M", "1 hr 30 mins", "1:30",
+// "45 minutes" or "45" (minutes)
+func parseMinutes(s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	if m := isoDurationRe.FindStringSubmatch(s); m != nil && len(s) > 1 {
+		days, _ := strconv.Atoi(m[1])
+		hours, _ := strconv.ParseFloat(m[2], 64)
+		mins, _ := strconv.ParseFloat(m[3], 64)
+		return days*24*60 + int(hours*60+mins+0.5)
+	}
+	if m := clockRe.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		return h*60 + min
+	}
+
+	total := 0.0
+	for _, m := range durationRe.FindAllStringSubmatch(s, -1) {
+		v, err := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64)
+		if err != nil {
+			continue
+		}
+		switch unit := strings.ToLower(m[2]); {
+		case strings.HasPrefix(unit, "d"):
+			total += v * 24 * 60
+		case strings.HasPrefix(unit, "h"):
+			total += v * 60
+		default:
+			total += v
+		}
+	}
+	return int(total + 0.5)
+}
+
+// parseServings reads the first number in a yield such as "Serves 4-6"
+func parseServings(s string) int {
+	n, _ := strconv.Atoi(firstIntRe.FindString(s))
+	return n
+}
+
+// ingredientLines splits a block of ingredient text into lines, treating
+// lines like "For the sauce:" or "SAUCE" as section headers
+func ingredientLines(text string) []IngredientLine {
+	var lines []IngredientLine
+	section := ""
+	for _, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(raw), "-•*·▢☐ "))
+		if line == "" {
+			continue
+		}
+		if header, ok := sectionHeader(line); ok {
+			section = header
+			continue
+		}
+		lines = append(lines, IngredientLine{Text: line, Section: section})
+	}
+	return lines
+}
+
+// sectionHeader recognizes "Dough:", "For the sauce:" and "# Filling"
+func sectionHeader(line string) (string, bool) {
+	if strings.HasPrefix(line, "#") {
+		return strings.TrimSpace(strings.TrimLeft(line, "#")), true
+	}
+	if strings.HasSuffix(line, ":") && !strings.ContainsAny(line, "0123456789") && utf8.RuneCountInString(line) <= 60 {
+		header := strings.TrimSpace(strings.TrimSuffix(line, ":"))
+		lower := strings.ToLower(header)
+		for _, prefix := range []string{"for the ", "for "} {
+			if strings.HasPrefix(lower, prefix) {
+				header = header[len(prefix):]
+				break
+			}
+		}
+		return capitalize(header), header != ""
+	}
+	return "", false
+}
+
+// stepLines splits directions into steps on blank lines, or on single
+// newlines when the text has no blank lines. Leading "1." numbering is removed.
+func stepLines(text string) []string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return nil
+	}
+	sep := "\n"
+	if strings.Contains(text, "\n\n") {
+		sep = "\n\n"
+	}
+	var steps []string
+	for _, s := range strings.Split(text, sep) {
+		s = stepNumberRe.ReplaceAllString(strings.TrimSpace(s), "")
+		if s = strings.Join(strings.Fields(s), " "); s != "" {
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+var stepNumberRe = regexp.MustCompile(`^(?:(?i:step)\s*)?\d+[.):]\s*`)
+
+// splitList splits tags or categories on commas and semicolons
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+var nutrientRes = map[string]*regexp.Regexp{
+	"calories": regexp.MustCompile(`(?i)(?:calories|kcal|energy)\D{0,15}?(\d+)|(\d+)\s*(?:kcal|calories)`),
+	"protein":  regexp.MustCompile(`(?i)protein\D{0,15}?(\d+)`),
+	"carbs":    regexp.MustCompile(`(?i)carb\w*\D{0,15}?(\d+)`),
+	"fat":      regexp.MustCompile(`(?i)(?:^|[^a-z])fat\D{0,15}?(\d+)`),
+	"fiber":    regexp.MustCompile(`(?i)fib(?:er|re)\D{0,15}?(\d+)`),
+	"sugar":    regexp.MustCompile(`(?i)sugar\w*\D{0,15}?(\d+)`),
+	"sodium":   regexp.MustCompile(`(?i)sodium\D{0,15}?(\d+)`),
+}
+
+// parseNutritionText reads free-text nutrition such as
+// "Calories: 250\nProtein: 10 g". Values are per serving.
+func parseNutritionText(text string) *model.RecipeNutrition {
+	find := func(key string) int {
+		m := nutrientRes[key].FindStringSubmatch(text)
+		for _, g := range m[min(1, len(m)):] {
+			if n, err := strconv.Atoi(g); err == nil {
+				return n
+			}
+		}
+		return 0
+	}
+	n := &model.RecipeNutrition{
+		Calories: find("calories"),
+		Protein:  find("protein"),
+		Carbs:    find("carbs"),
+		Fat:      find("fat"),
+		Fiber:    find("fiber"),
+		Sugar:    find("sugar"),
+		Sodium:   find("sodium"),
+	}
+	if n.Calories == 0 {
+		return nil
+	}
+	return n
+}
+
+// normalizeDifficulty maps free-text difficulty to easy, medium or hard
+func normalizeDifficulty(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "easy", "simple", "beginner", "facile", "fácil":
+		return "easy"
+	case "medium", "moderate", "intermediate", "moyen", "media":
+		return "medium"
+	case "hard", "difficult", "advanced", "difficile", "difícil":
+		return "hard"
+	}
+	return ""
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return strings.ToUpper(string(r)) + s[size:]
+}
+
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:n]))
+}
+
+func optional(s string) *string {
+	if s = strings.TrimSpace(s); s == "" {
+		return nil
+	}
+	return &s
+}
+
+func positive(n int) *int {
+	if n <= 0 {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,390 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/service/export"
+)
+
+// zipOf builds a zip archive from name/content pairs
+func zipOf(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pngHeader is enough of a PNG for content sniffing
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+
+func TestParseMinutes(t *testing.T) {
+	tests := map[string]int{
+		"PT1H30M":       90,
+		"PT45M":         45,
+		"P0DT2H":        120,
+		"1 hr 30 mins":  90,
+		"1 hour":        60,
+		"45 minutes":    45,
+		"1:15":          75,
+		"20":            20,
+		"1.5 hours":     90,
+		"":              0,
+		"about 10 mins": 10,
+	}
+	for in, want := range tests {
+		if got := parseMinutes(in); got != want {
+			t.Errorf("parseMinutes(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestIngredientLines(t *testing.T) {
+	got := ingredientLines("2 cups flour\n\nFor the sauce:\n- 1 can tomatoes\n# Topping\n100 g mozzarella")
+	want := []IngredientLine{
+		{Text: "2 cups flour"},
+		{Text: "1 can tomatoes", Section: "Sauce"},
+		{Text: "100 g mozzarella", Section: "Topping"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d lines, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestStepLines(t *testing.T) {
+	got := stepLines("1. Mix the dough.\n2. Let it rise.")
+	if len(got) != 2 || got[0] != "Mix the dough." || got[1] != "Let it rise." {
+		t.Errorf("unexpected steps %q", got)
+	}
+
+	got = stepLines("Mix the dough\nuntil smooth.\n\nLet it rise.")
+	if len(got) != 2 || got[0] != "Mix the dough until smooth." {
+		t.Errorf("expected paragraphs as steps, got %q", got)
+	}
+}
+
+func TestParsePaprikaRoundTrip(t *testing.T) {
+	recipe := &model.Recipe{
+		Title:       "Pizza",
+		Servings:    ptr(2),
+		CookTime:    ptr(90),
+		SourceURL:   ptr("https://example.com/pizza"),
+		Tags:        []string{"italian"},
+		Nutrition:   &model.RecipeNutrition{Calories: 800, Protein: 25},
+		Ingredients: []model.RecipeIngredient{{Name: "flour", Quantity: ptr(500.0), Unit: ptr("g"), Section: "Dough"}, {Name: "tomatoes", Quantity: ptr(400.0), Unit: ptr("g"), Section: "Sauce"}},
+		Steps:       []model.RecipeStep{{StepNumber: 1, Instruction: "Knead"}, {StepNumber: 2, Instruction: "Bake"}},
+	}
+	var buf bytes.Buffer
+	a := export.NewArchive(&buf, export.FormatPaprika)
+	if err := a.Add(recipe, &export.Image{Data: pngHeader, Ext: ".png"}); err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+
+	entries, err := Parse(FormatPaprika, buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Err != nil {
+		t.Fatalf("expected one good entry, got %+v", entries)
+	}
+	d := entries[0].Draft
+	if d.Title != "Pizza" || d.Servings != 2 || d.SourceURL != "https://example.com/pizza" {
+		t.Errorf("unexpected draft %+v", d)
+	}
+	if d.PrepTime+d.CookTime != 90 {
+		t.Errorf("expected 90 minutes, got prep %d cook %d", d.PrepTime, d.CookTime)
+	}
+	if len(d.Ingredients) != 2 || d.Ingredients[0].Section != "Dough" || d.Ingredients[1].Section != "Sauce" {
+		t.Errorf("expected sectioned ingredients, got %+v", d.Ingredients)
+	}
+	if len(d.Steps) != 2 {
+		t.Errorf("expected 2 steps, got %q", d.Steps)
+	}
+	if d.Nutrition == nil || d.Nutrition.Calories != 800 || d.Nutrition.Protein != 25 {
+		t.Errorf("expected nutrition, got %+v", d.Nutrition)
+	}
+	if d.Image == nil || d.Image.Ext != ".png" {
+		t.Errorf("expected embedded photo, got %+v", d.Image)
+	}
+}
+
+func TestParsePaprikaBadEntry(t *testing.T) {
+	data := zipOf(t, map[string][]byte{"Broken.paprikarecipe": []byte("not gzip")})
+	entries, err := Parse(FormatPaprika, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Err == nil || entries[0].Name != "Broken" {
+		t.Errorf("expected a failed entry named Broken, got %+v", entries)
+	}
+}
+
+func TestParseMealie(t *testing.T) {
+	recipe := `{
+		"name": "Chili",
+		"recipeYield": "6 servings",
+		"prepTime": "15 minutes",
+		"performTime": "1 hour",
+		"orgURL": "https://example.com/chili",
+		"tags": [{"name": "Spicy"}],
+		"recipeCategory": [{"name": "Dinner"}],
+		"recipeIngredient": [
+			{"title": "Base", "originalText": "2 tbsp olive oil"},
+			{"quantity": 500, "unit": {"name": "g"}, "food": {"name": "beef"}, "note": "minced"},
+			{"title": "Garnish", "note": "sour cream", "display": ""}
+		],
+		"recipeInstructions": [{"text": "Brown the beef."}, {"text": "Simmer."}],
+		"nutrition": {"calories": "450", "proteinContent": "30"}
+	}`
+	data := zipOf(t, map[string][]byte{
+		"recipes/chili/chili.json":              []byte(recipe),
+		"recipes/chili/images/original.webp":    pngHeader,
+		"recipes/broken/broken.json":            []byte("{"),
+		"recipes/chili/images/min-original.png": pngHeader,
+	})
+
+	entries, err := Parse(FormatMealie, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d *Draft
+	failed := 0
+	for _, e := range entries {
+		if e.Err != nil {
+			failed++
+		} else {
+			d = e.Draft
+		}
+	}
+	if failed != 1 || d == nil {
+		t.Fatalf("expected one good and one failed entry, got %+v", entries)
+	}
+	if d.Title != "Chili" || d.Servings != 6 || d.PrepTime != 15 || d.CookTime != 60 {
+		t.Errorf("unexpected draft %+v", d)
+	}
+	want := []IngredientLine{
+		{Text: "2 tbsp olive oil", Section: "Base"},
+		{Text: "500 g beef, minced", Section: "Base"},
+		{Text: "sour cream", Section: "Garnish"},
+	}
+	if len(d.Ingredients) != len(want) {
+		t.Fatalf("expected %d ingredients, got %+v", len(want), d.Ingredients)
+	}
+	for i := range want {
+		if d.Ingredients[i] != want[i] {
+			t.Errorf("ingredient %d: expected %+v, got %+v", i, want[i], d.Ingredients[i])
+		}
+	}
+	if strings.Join(d.Tags, ",") != "Dinner,Spicy" {
+		t.Errorf("unexpected tags %q", d.Tags)
+	}
+	if len(d.Steps) != 2 || d.Nutrition == nil || d.Nutrition.Calories != 450 {
+		t.Errorf("unexpected steps %q or nutrition %+v", d.Steps, d.Nutrition)
+	}
+	if d.Image == nil {
+		t.Error("expected the original image")
+	}
+}
+
+func TestParseTandoor(t *testing.T) {
+	recipe := `{
+		"name": "Pancakes",
+		"servings": 4,
+		"working_time": 10,
+		"waiting_time": 20,
+		"keywords": [{"name": "breakfast"}],
+		"steps": [
+			{"name": "Batter", "instruction": "Whisk everything.", "ingredients": [
+				{"food": {"name": "flour"}, "unit": {"name": "g"}, "amount": "200.000", "note": "sifted"},
+				{"food": {"name": "salt"}, "unit": null, "amount": 0, "no_amount": true}
+			]},
+			{"name": "", "instruction": "Fry in butter.", "ingredients": [
+				{"is_header": true, "note": "To serve"},
+				{"food": {"name": "maple syrup"}, "amount": 2, "unit": {"name": "tbsp"}}
+			]}
+		]
+	}`
+	inner := zipOf(t, map[string][]byte{"recipe.json": []byte(recipe), "image.png": pngHeader})
+	data := zipOf(t, map[string][]byte{"1.zip": inner, "2.zip": []byte("junk")})
+
+	entries, err := Parse(FormatTandoor, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	var d *Draft
+	for _, e := range entries {
+		if e.Name == "1" {
+			d = e.Draft
+		} else if e.Err == nil {
+			t.Errorf("expected junk entry to fail")
+		}
+	}
+	if d == nil {
+		t.Fatal("missing good entry")
+	}
+	if d.Title != "Pancakes" || d.Servings != 4 || d.PrepTime != 10 || d.CookTime != 20 {
+		t.Errorf("unexpected draft %+v", d)
+	}
+	want := []IngredientLine{
+		{Text: "200 g flour, sifted", Section: "Batter"},
+		{Text: "salt", Section: "Batter"},
+		{Text: "2 tbsp maple syrup", Section: "To serve"},
+	}
+	for i := range want {
+		if i >= len(d.Ingredients) || d.Ingredients[i] != want[i] {
+			t.Fatalf("expected %+v, got %+v", want, d.Ingredients)
+		}
+	}
+	if len(d.Steps) != 2 || d.Image == nil {
+		t.Errorf("unexpected steps %q or image %+v", d.Steps, d.Image)
+	}
+}
+
+func TestParseSchemaOrg(t *testing.T) {
+	doc := `{"@context": "https://schema.org", "@graph": [
+		{"@type": "WebPage", "name": "ignored"},
+		{"@type": ["Recipe"], "name": "Soup", "recipeYield": ["4", "4 bowls"], "totalTime": "PT40M",
+		 "image": {"url": "https://example.com/soup.jpg"}, "keywords": "easy, vegan",
+		 "recipeIngredient": ["1 onion", "1 l stock"],
+		 "recipeInstructions": [{"@type": "HowToSection", "name": "Cook", "itemListElement": [
+			{"@type": "HowToStep", "text": "Chop the onion."}, {"@type": "HowToStep", "text": "Simmer."}]}],
+		 "nutrition": {"calories": "120 kcal", "fatContent": "3 g"}}
+	]}`
+	entries, err := Parse(FormatWhisk, []byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Err != nil {
+		t.Fatalf("expected one recipe, got %+v", entries)
+	}
+	d := entries[0].Draft
+	if d.Title != "Soup" || d.Servings != 4 || d.CookTime != 40 || d.ImageURL != "https://example.com/soup.jpg" {
+		t.Errorf("unexpected draft %+v", d)
+	}
+	if len(d.Ingredients) != 2 || len(d.Steps) != 2 || strings.Join(d.Tags, ",") != "easy,vegan" {
+		t.Errorf("unexpected draft %+v", d)
+	}
+	if d.Nutrition == nil || d.Nutrition.Calories != 120 || d.Nutrition.Fat != 3 {
+		t.Errorf("unexpected nutrition %+v", d.Nutrition)
+	}
+
+	if _, err := Parse(FormatWhisk, []byte(`{"@type": "WebPage"}`)); err == nil {
+		t.Error("expected an error for a document without recipes")
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	csv := "\xef\xbb\xbfName,Servings,Prep_Time,Ingredients,Directions,Tags,Unknown\n" +
+		"Toast,1,5 min,\"1 slice bread\n1 tbsp butter\",\"Toast the bread.\nButter it.\",\"breakfast, quick\",x\n" +
+		",,,,,,\n" +
+		"Salad,2,,lettuce | 1 tomato,Toss,,\n" +
+		"Nothing,,,,,,x\n"
+	entries, err := Parse(FormatCSV, []byte(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected blank rows skipped, got %+v", entries)
+	}
+	toast := entries[0].Draft
+	if toast.Title != "Toast" || toast.Servings != 1 || toast.PrepTime != 5 || len(toast.Ingredients) != 2 || len(toast.Steps) != 2 || len(toast.Tags) != 2 {
+		t.Errorf("unexpected draft %+v", toast)
+	}
+	if salad := entries[1].Draft; len(salad.Ingredients) != 2 || salad.Ingredients[1].Text != "1 tomato" {
+		t.Errorf("expected pipe separated ingredients, got %+v", salad.Ingredients)
+	}
+	if entries[2].Err == nil || entries[2].Name != "Row 5" {
+		t.Errorf("expected a recipe without content to fail, got %+v", entries[2])
+	}
+
+	if _, err := Parse(FormatCSV, []byte("foo,bar\n1,2\n")); err == nil {
+		t.Error("expected an error without a title column")
+	}
+
+	semi, err := Parse(FormatCSV, []byte("title;ingredients\nSoup;1,5 l water\n"))
+	if err != nil || len(semi) != 1 || semi[0].Draft.Ingredients[0].Text != "1,5 l water" {
+		t.Errorf("expected semicolon separated CSV, got %+v, %v", semi, err)
+	}
+}
+
+func TestToRecipe(t *testing.T) {
+	userID := uuid.New()
+	d := &Draft{
+		Title:      "  " + strings.Repeat("x", 300),
+		Servings:   4,
+		Difficulty: "Moderate",
+		Ingredients: []IngredientLine{
+			{Text: "2 cloves garlic, minced"},
+			{Text: "1/2 cup milk (optional)", Section: "Sauce"},
+			{Text: "   "},
+		},
+		Steps: []string{"Mix", " ", "Bake"},
+	}
+	r := ToRecipe(d, userID)
+
+	if r.UserID != userID || r.SourceType != "import" || len([]rune(r.Title)) != 255 {
+		t.Errorf("unexpected recipe %+v", r)
+	}
+	if r.Difficulty == nil || *r.Difficulty != "medium" {
+		t.Errorf("expected medium difficulty, got %v", r.Difficulty)
+	}
+	if len(r.Ingredients) != 2 {
+		t.Fatalf("expected 2 ingredients, got %+v", r.Ingredients)
+	}
+	garlic := r.Ingredients[0]
+	if garlic.Name != "garlic" || garlic.Quantity == nil || *garlic.Quantity != 2 || garlic.Section != "Main" || garlic.Notes == nil {
+		t.Errorf("unexpected garlic %+v", garlic)
+	}
+	if milk := r.Ingredients[1]; !milk.IsOptional || milk.Section != "Sauce" || milk.SortOrder != 1 {
+		t.Errorf("unexpected milk %+v", milk)
+	}
+	if len(r.Steps) != 2 || r.Steps[1].StepNumber != 2 {
+		t.Errorf("unexpected steps %+v", r.Steps)
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	if _, err := Parse(Format("nope"), nil); err != ErrUnsupportedFormat {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+	if _, err := Parse(FormatWhisk, []byte("[]")); err == nil {
+		t.Error("expected an error for an empty file")
+	}
+
+	var csv strings.Builder
+	csv.WriteString("title,steps\n")
+	for range MaxEntries + 5 {
+		csv.WriteString("x,y\n")
+	}
+	if _, err := Parse(FormatCSV, []byte(csv.String())); err == nil {
+		t.Error("expected an error past MaxEntries")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
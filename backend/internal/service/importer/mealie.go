@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// parseMealie reads a Mealie export: a zip with one directory per recipe
+// holding <slug>.json and images/original.*, or a single recipe's JSON
+func parseMealie(data []byte) ([]Entry, error) {
+	if !isZip(data) {
+		d, err := mealieDraft(data)
+		if err != nil {
+			return nil, err
+		}
+		return []Entry{{Draft: d}}, nil
+	}
+
+	zr, err := readZip(data)
+	if err != nil {
+		return nil, err
+	}
+
+	images := map[string]int{} // recipe directory -> index of its original image
+	for i, f := range zr.File {
+		dir, file := path.Split(f.Name)
+		if strings.HasSuffix(dir, "/images/") && strings.HasPrefix(file, "original.") {
+			images[strings.TrimSuffix(dir, "images/")] = i
+		}
+	}
+
+	u := newUnzipper()
+	var entries []Entry
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".json") || strings.Contains(f.Name, "/images/") {
+			continue
+		}
+		name := strings.TrimSuffix(path.Base(f.Name), path.Ext(f.Name))
+		raw, err := u.read(f)
+		if errors.Is(err, errTooLarge) {
+			return nil, err
+		}
+		if err != nil {
+			entries = append(entries, Entry{Name: name, Err: err})
+			continue
+		}
+		d, err := mealieDraft(raw)
+		if err != nil {
+			entries = append(entries, Entry{Name: name, Err: err})
+			continue
+		}
+		if i, ok := images[path.Dir(f.Name)+"/"]; ok {
+			photo, err := u.read(zr.File[i])
+			if errors.Is(err, errTooLarge) {
+				return nil, err
+			}
+			if err == nil {
+				d.Image = newImage(photo)
+			}
+		}
+		entries = append(entries, Entry{Name: name, Draft: d})
+	}
+	return entries, nil
+}
+
+func mealieDraft(data []byte) (*Draft, error) {
+	var r map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&r); err != nil {
+		return nil, fmt.Errorf("invalid recipe JSON: %w", err)
+	}
+	if r["recipeIngredient"] == nil && r["recipeInstructions"] == nil {
+		return nil, errors.New("not a Mealie recipe")
+	}
+
+	d := &Draft{
+		Title:       text(r["name"]),
+		Description: text(r["description"]),
+		Servings:    number(r["recipeServings"]),
+		PrepTime:    parseMinutes(text(r["prepTime"])),
+		CookTime:    parseMinutes(text(r["performTime"])),
+		SourceURL:   text(r["orgURL"]),
+		Nutrition:   schemaNutrition(r["nutrition"]),
+	}
+	if d.Servings == 0 {
+		d.Servings = number(r["recipeYield"])
+	}
+	if d.CookTime == 0 {
+		d.CookTime = parseMinutes(text(r["cookTime"]))
+	}
+	if d.PrepTime == 0 && d.CookTime == 0 {
+		d.CookTime = parseMinutes(text(r["totalTime"]))
+	}
+	d.Tags = append(texts(r["recipeCategory"]), texts(r["tags"])...)
+
+	items, _ := r["recipeIngredient"].([]any)
+	section := ""
+	for _, item := range items {
+		ing, ok := item.(map[string]any)
+		if !ok {
+			// Mealie before v1 stored plain lines
+			d.Ingredients = append(d.Ingredients, ingredientLines(text(item))...)
+			continue
+		}
+		if title := text(ing["title"]); title != "" {
+			section = title
+		}
+		if line := mealieIngredientLine(ing); line != "" {
+			d.Ingredients = append(d.Ingredients, IngredientLine{Text: line, Section: section})
+		}
+	}
+
+	d.Steps = schemaSteps(r["recipeInstructions"])
+	return d, nil
+}
+
+// mealieIngredientLine rebuilds an ingredient's text. The original line is
+// preferred so the ingredient parser sees what the user wrote.
+func mealieIngredientLine(ing map[string]any) string {
+	if s := text(ing["originalText"]); s != "" {
+		return s
+	}
+	if s := text(ing["display"]); s != "" {
+		return s
+	}
+
+	var parts []string
+	if q := quantity(ing["quantity"]); q != "" {
+		parts = append(parts, q)
+	}
+	parts = append(parts, text(ing["unit"]), text(ing["food"]))
+	line := strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+	if note := text(ing["note"]); note != "" {
+		if line == "" {
+			return note
+		}
+		line += ", " + note
+	}
+	return line
+}
+
+// quantity formats a numeric amount, returning "" for zero or missing ones
+func quantity(v any) string {
+	var f float64
+	switch v := v.(type) {
+	case json.Number:
+		f, _ = v.Float64()
+	case float64:
+		f = v
+	case string:
+		f, _ = strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+	if f <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// paprikaRecipe is the JSON inside a .paprikarecipe entry
+type paprikaRecipe struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	Ingredients     string   `json:"ingredients"`
+	Directions      string   `json:"directions"`
+	Servings        string   `json:"servings"`
+	PrepTime        string   `json:"prep_time"`
+	CookTime        string   `json:"cook_time"`
+	TotalTime       string   `json:"total_time"`
+	Difficulty      string   `json:"difficulty"`
+	Source          string   `json:"source"`
+	SourceURL       string   `json:"source_url"`
+	ImageURL        string   `json:"image_url"`
+	PhotoData       string   `json:"photo_data"`
+	Categories      []string `json:"categories"`
+	NutritionalInfo string   `json:"nutritional_info"`
+}
+
+// parsePaprika reads a .paprikarecipes archive (a zip of gzipped JSON
+// recipes) or a single gzipped .paprikarecipe
+func parsePaprika(data []byte) ([]Entry, error) {
+	if !isZip(data) {
+		d, err := paprikaDraft(data)
+		if err != nil {
+			return nil, err
+		}
+		return []Entry{{Draft: d}}, nil
+	}
+
+	zr, err := readZip(data)
+	if err != nil {
+		return nil, err
+	}
+	u := newUnzipper()
+	var entries []Entry
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".paprikarecipe") {
+			continue
+		}
+		name := strings.TrimSuffix(path.Base(f.Name), path.Ext(f.Name))
+		raw, err := u.read(f)
+		if errors.Is(err, errTooLarge) {
+			return nil, err
+		}
+		if err != nil {
+			entries = append(entries, Entry{Name: name, Err: err})
+			continue
+		}
+		d, err := paprikaDraft(raw)
+		entries = append(entries, Entry{Name: name, Draft: d, Err: err})
+	}
+	return entries, nil
+}
+
+func paprikaDraft(data []byte) (*Draft, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("not a Paprika recipe")
+	}
+	defer gz.Close()
+	raw, err := io.ReadAll(io.LimitReader(gz, maxEntrySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read recipe: %w", err)
+	}
+	if len(raw) > maxEntrySize {
+		return nil, errors.New("recipe is too large")
+	}
+
+	var p paprikaRecipe
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("invalid recipe JSON: %w", err)
+	}
+
+	d := &Draft{
+		Title:       p.Name,
+		Description: p.Description,
+		Servings:    parseServings(p.Servings),
+		PrepTime:    parseMinutes(p.PrepTime),
+		CookTime:    parseMinutes(p.CookTime),
+		Difficulty:  p.Difficulty,
+		SourceURL:   p.SourceURL,
+		ImageURL:    p.ImageURL,
+		Tags:        p.Categories,
+		Ingredients: ingredientLines(p.Ingredients),
+		Steps:       stepLines(p.Directions),
+		Nutrition:   parseNutritionText(p.NutritionalInfo),
+	}
+	if d.PrepTime == 0 && d.CookTime == 0 {
+		d.CookTime = parseMinutes(p.TotalTime)
+	}
+	if p.PhotoData != "" {
+		if photo, err := base64.StdEncoding.DecodeString(p.PhotoData); err == nil {
+			d.Image = newImage(photo)
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,242 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// parseSchemaOrg reads schema.org Recipe JSON, the format Whisk (Samsung
+// Food) exports and most recipe sites embed. The file may hold one recipe,
+// an array, an @graph, or be a zip of such files.
+func parseSchemaOrg(data []byte) ([]Entry, error) {
+	if !isZip(data) {
+		recipes, err := schemaRecipes(data)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]Entry, 0, len(recipes))
+		for _, r := range recipes {
+			entries = append(entries, Entry{Draft: schemaDraft(r)})
+		}
+		return entries, nil
+	}
+
+	zr, err := readZip(data)
+	if err != nil {
+		return nil, err
+	}
+	u := newUnzipper()
+	var entries []Entry
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".json") {
+			continue
+		}
+		raw, err := u.read(f)
+		if errors.Is(err, errTooLarge) {
+			return nil, err
+		}
+		if err != nil {
+			entries = append(entries, Entry{Name: f.Name, Err: err})
+			continue
+		}
+		recipes, err := schemaRecipes(raw)
+		if err != nil {
+			entries = append(entries, Entry{Name: f.Name, Err: err})
+			continue
+		}
+		for _, r := range recipes {
+			entries = append(entries, Entry{Name: f.Name, Draft: schemaDraft(r)})
+		}
+	}
+	return entries, nil
+}
+
+// schemaRecipes finds the Recipe objects in a JSON document
+func schemaRecipes(data []byte) ([]map[string]any, error) {
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var recipes []map[string]any
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]any:
+			if isRecipeType(v["@type"]) || (v["@type"] == nil && v["recipeIngredient"] != nil) {
+				recipes = append(recipes, v)
+				return
+			}
+			if graph, ok := v["@graph"]; ok {
+				walk(graph)
+			}
+		}
+	}
+	walk(doc)
+
+	if len(recipes) == 0 {
+		return nil, errors.New("no schema.org Recipe found")
+	}
+	return recipes, nil
+}
+
+func isRecipeType(t any) bool {
+	for _, s := range texts(t) {
+		if strings.EqualFold(s, "Recipe") {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaDraft maps a schema.org Recipe
+func schemaDraft(r map[string]any) *Draft {
+	d := &Draft{
+		Title:       text(r["name"]),
+		Description: text(r["description"]),
+		Servings:    number(r["recipeYield"]),
+		PrepTime:    parseMinutes(text(r["prepTime"])),
+		CookTime:    parseMinutes(text(r["cookTime"])),
+		Cuisine:     text(r["recipeCuisine"]),
+		SourceURL:   text(r["url"]),
+		ImageURL:    text(r["image"]),
+		Nutrition:   schemaNutrition(r["nutrition"]),
+	}
+	if d.PrepTime == 0 && d.CookTime == 0 {
+		d.CookTime = parseMinutes(text(r["totalTime"]))
+	}
+
+	for _, key := range []string{"keywords", "recipeCategory"} {
+		for _, t := range texts(r[key]) {
+			d.Tags = append(d.Tags, splitList(t)...)
+		}
+	}
+
+	for _, line := range texts(r["recipeIngredient"]) {
+		d.Ingredients = append(d.Ingredients, ingredientLines(line)...)
+	}
+	if len(d.Ingredients) == 0 {
+		// Pre-2017 markup
+		for _, line := range texts(r["ingredients"]) {
+			d.Ingredients = append(d.Ingredients, ingredientLines(line)...)
+		}
+	}
+
+	d.Steps = schemaSteps(r["recipeInstructions"])
+	return d
+}
+
+// schemaSteps flattens recipeInstructions, which may be text, a list of
+// strings, HowToSteps or HowToSections of steps
+func schemaSteps(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return stepLines(v)
+	case []any:
+		var steps []string
+		for _, item := range v {
+			steps = append(steps, schemaSteps(item)...)
+		}
+		return steps
+	case map[string]any:
+		if items, ok := v["itemListElement"]; ok {
+			return schemaSteps(items)
+		}
+		if s := text(v["text"]); s != "" {
+			return []string{s}
+		}
+		return schemaSteps(v["name"])
+	}
+	return nil
+}
+
+// schemaNutrition reads NutritionInformation, whose values are strings such
+// as "250 kcal" or "12 g"
+func schemaNutrition(v any) *model.RecipeNutrition {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	n := &model.RecipeNutrition{
+		Calories: number(m["calories"]),
+		Protein:  number(m["proteinContent"]),
+		Carbs:    number(m["carbohydrateContent"]),
+		Fat:      number(m["fatContent"]),
+		Fiber:    number(m["fiberContent"]),
+		Sugar:    number(m["sugarContent"]),
+		Sodium:   number(m["sodiumContent"]),
+	}
+	if n.Calories <= 0 {
+		return nil
+	}
+	return n
+}
+
+// text reads a loosely typed JSON value as a string: the first element of
+// an array, or an object's name, text or url
+func text(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		for _, item := range v {
+			if s := text(item); s != "" {
+				return s
+			}
+		}
+	case map[string]any:
+		for _, key := range []string{"name", "text", "url", "@value"} {
+			if s := text(v[key]); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// texts reads a loosely typed JSON value as a list of strings
+func texts(v any) []string {
+	if items, ok := v.([]any); ok {
+		var out []string
+		for _, item := range items {
+			if s := text(item); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	if s := text(v); s != "" {
+		return []string{s}
+	}
+	return nil
+}
+
+// number reads the leading whole number of a loosely typed JSON value,
+// e.g. 4, "4", "4 servings", "250 kcal"
+func number(v any) int {
+	switch v := v.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return int(f + 0.5)
+	case float64:
+		return int(v + 0.5)
+	}
+	return parseServings(text(v))
+}
@@ -0,0 +1,170 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// parseTandoor reads a Tandoor export: a zip of per-recipe zips, each
+// holding recipe.json and an image. A single recipe's zip is accepted too.
+func parseTandoor(data []byte) ([]Entry, error) {
+	zr, err := readZip(data)
+	if err != nil {
+		return nil, err
+	}
+	u := newUnzipper()
+
+	for _, f := range zr.File {
+		if path.Base(f.Name) == "recipe.json" {
+			d, err := tandoorRecipe(u, data)
+			if errors.Is(err, errTooLarge) {
+				return nil, err
+			}
+			return []Entry{{Draft: d, Err: err}}, nil
+		}
+	}
+
+	var entries []Entry
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".zip") {
+			continue
+		}
+		name := strings.TrimSuffix(path.Base(f.Name), path.Ext(f.Name))
+		raw, err := u.read(f)
+		if err == nil {
+			var d *Draft
+			if d, err = tandoorRecipe(u, raw); err == nil {
+				entries = append(entries, Entry{Name: name, Draft: d})
+				continue
+			}
+		}
+		if errors.Is(err, errTooLarge) {
+			return nil, err
+		}
+		entries = append(entries, Entry{Name: name, Err: err})
+	}
+	return entries, nil
+}
+
+// tandoorRecipe reads one recipe's zip
+func tandoorRecipe(u *unzipper, data []byte) (*Draft, error) {
+	zr, err := readZip(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var d *Draft
+	var photo []byte
+	for _, f := range zr.File {
+		switch name := path.Base(f.Name); {
+		case name == "recipe.json":
+			raw, err := u.read(f)
+			if err != nil {
+				return nil, err
+			}
+			if d, err = tandoorDraft(raw); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(name, "image."):
+			raw, err := u.read(f)
+			if err != nil {
+				return nil, err
+			}
+			photo = raw
+		}
+	}
+	if d == nil {
+		return nil, errors.New("recipe.json missing")
+	}
+	d.Image = newImage(photo)
+	return d, nil
+}
+
+func tandoorDraft(data []byte) (*Draft, error) {
+	var r map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&r); err != nil {
+		return nil, fmt.Errorf("invalid recipe JSON: %w", err)
+	}
+
+	d := &Draft{
+		Title:       text(r["name"]),
+		Description: text(r["description"]),
+		Servings:    number(r["servings"]),
+		PrepTime:    number(r["working_time"]),
+		CookTime:    number(r["waiting_time"]),
+		SourceURL:   text(r["source_url"]),
+		Tags:        texts(r["keywords"]),
+		Nutrition:   tandoorNutrition(r["nutrition"]),
+	}
+
+	// Tandoor attaches ingredients to steps; a named step becomes the
+	// section for its ingredients, and header rows start new sections
+	steps, _ := r["steps"].([]any)
+	for _, item := range steps {
+		step, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		section := text(step["name"])
+		ings, _ := step["ingredients"].([]any)
+		for _, item := range ings {
+			ing, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			if ing["is_header"] == true {
+				section = text(ing["note"])
+				continue
+			}
+			if line := tandoorIngredientLine(ing); line != "" {
+				d.Ingredients = append(d.Ingredients, IngredientLine{Text: line, Section: section})
+			}
+		}
+		d.Steps = append(d.Steps, stepLines(text(step["instruction"]))...)
+	}
+	return d, nil
+}
+
+func tandoorIngredientLine(ing map[string]any) string {
+	if s := text(ing["original_text"]); s != "" {
+		return s
+	}
+	var parts []string
+	if ing["no_amount"] != true {
+		if q := quantity(ing["amount"]); q != "" {
+			parts = append(parts, q, text(ing["unit"]))
+		}
+	}
+	parts = append(parts, text(ing["food"]))
+	line := strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+	if note := text(ing["note"]); note != "" && line != "" {
+		line += ", " + note
+	}
+	return line
+}
+
+// tandoorNutrition reads the nutrition block of older Tandoor exports
+func tandoorNutrition(v any) *model.RecipeNutrition {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	n := &model.RecipeNutrition{
+		Calories: number(m["calories"]),
+		Protein:  number(m["proteins"]),
+		Carbs:    number(m["carbohydrates"]),
+		Fat:      number(m["fats"]),
+	}
+	if n.Calories <= 0 {
+		return nil
+	}
+	return n
+}
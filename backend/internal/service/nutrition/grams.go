@@ -0,0 +1,108 @@
+package nutrition
+
+import (
+	"strings"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/ingredient"
+	"github.com/dishflow/backend/internal/pkg/units"
+)
+
+// Grams weighs qty of an ingredient given in unit. Weights convert
+// directly; volumes use the food's own household measures, then the unit
+// engine's density table; counts ("2", "3 cloves", "1 dozen") use the
+// food's counted portions. It reports false when the quantity can't be
+// weighed, like "1 bunch" of a food with no bunch portion.
+func Grams(qty float64, unit, name string, food *model.Food) (float64, bool) {
+	unit = strings.TrimSpace(unit)
+	u, known := units.Lookup(unit)
+	if unit == "" {
+		known = false
+	}
+
+	if known {
+		switch u.Dimension {
+		case units.Mass:
+			return qty * u.Base, true
+		case units.Volume:
+			if p, pu, ok := volumePortion(food); ok {
+				return qty * u.Base * p.GramWeight / (p.Amount * pu.Base), true
+			}
+			g, err := units.ConvertIngredient(qty, u, units.Gram, name)
+			return g, err == nil
+		case units.Count:
+			qty, unit = qty*u.Base, ""
+		default:
+			return 0, false
+		}
+	}
+
+	if p, ok := countPortion(food, unit); ok {
+		return qty * p.GramWeight / p.Amount, true
+	}
+	return 0, false
+}
+
+// volumePortion returns the food's largest volume measure, the one USDA
+// weighed most precisely, with its unit
+func volumePortion(food *model.Food) (model.FoodPortion, units.Unit, bool) {
+	var best model.FoodPortion
+	var bestUnit units.Unit
+	for _, p := range food.Portions {
+		if p.Amount <= 0 || p.Unit == "" {
+			continue
+		}
+		u, ok := units.Lookup(p.Unit)
+		if !ok || u.Dimension != units.Volume {
+			continue
+		}
+		if p.Amount*u.Base > best.Amount*bestUnit.Base {
+			best, bestUnit = p, u
+		}
+	}
+	return best, bestUnit, best.Amount > 0
+}
+
+// countPortion picks the counted portion matching word ("clove", "slice"),
+// or a medium or otherwise first one when the ingredient is a bare count
+func countPortion(food *model.Food, word string) (model.FoodPortion, bool) {
+	word = ingredient.NormalizeName(word)
+	var first, medium *model.FoodPortion
+	for i := range food.Portions {
+		p := &food.Portions[i]
+		if p.Amount <= 0 || p.Unit != "" {
+			continue
+		}
+		modifier := MatchName(p.Modifier)
+		if word != "" {
+			if containsWord(modifier, word) {
+				return *p, true
+			}
+			continue
+		}
+		if first == nil {
+			first = p
+		}
+		if medium == nil && containsWord(modifier, "medium") {
+			medium = p
+		}
+	}
+	switch {
+	case medium != nil:
+		return *medium, true
+	case first != nil:
+		return *first, true
+	}
+	return model.FoodPortion{}, false
+}
+
+// containsWord reports whether s has word, allowing a plural "s" the
+// ingredient normalizer leaves on words like "cloves" and "leaves"
+func containsWord(s, word string) bool {
+	for _, w := range strings.Fields(s) {
+		if w == word || w+"s" == word || w == word+"s" {
+			return true
+		}
+	}
+	return false
+}
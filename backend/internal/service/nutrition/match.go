@@ -0,0 +1,53 @@
+package nutrition
+
+import (
+	"strings"
+
+	"github.com/dishflow/backend/internal/pkg/ingredient"
+)
+
+// qualifiedHeads are USDA description heads naming a kind of food, with
+// which one coming next ("Oil, olive", "Spices, cinnamon, ground"). The
+// value is what stays of the head in the match name.
+var qualifiedHeads = map[string]string{
+	"oil":                "oil",
+	"vinegar":            "vinegar",
+	"cheese":             "cheese",
+	"beans":              "beans",
+	"peppers":            "peppers",
+	"sauce":              "sauce",
+	"spices":             "",
+	"nuts":               "",
+	"seeds":              "",
+	"soup":               "",
+	"fish":               "",
+	"crustaceans":        "",
+	"mollusks":           "",
+	"beverages":          "",
+	"alcoholic beverage": "",
+	"leavening agents":   "",
+	"salad dressing":     "",
+}
+
+// MatchName is the form ingredient and food names are compared in: the
+// ingredient match key with every word singular, so "Cherry Tomatoes" and
+// "cherry tomato" compare equal
+func MatchName(name string) string {
+	words := strings.Fields(ingredient.MatchKey(name))
+	for i, w := range words {
+		words[i] = ingredient.NormalizeName(w)
+	}
+	return strings.Join(words, " ")
+}
+
+// FoodMatchName turns a USDA food description into the common name a
+// recipe would use: "Onions, raw" is "onion", "Oil, olive, salad or
+// cooking" is "olive oil"
+func FoodMatchName(description string) string {
+	parts := strings.Split(description, ",")
+	name := strings.ToLower(strings.TrimSpace(parts[0]))
+	if kind, ok := qualifiedHeads[name]; ok && len(parts) > 1 {
+		name = strings.TrimSpace(parts[1]) + " " + kind
+	}
+	return MatchName(name)
+}
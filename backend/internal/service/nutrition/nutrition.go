@@ -0,0 +1,172 @@
+// Package nutrition computes recipe nutrition from the USDA FoodData Central
+// composition data imported by cmd/import-usda.
+//
+// Each ingredient is matched to a food, weighed with the unit engine and the
+// food's household measures, and its nutrients are added up. Only the
+// ingredients no food accounts for are left to the AI estimate, and the
+// report shows which ingredient each number came from.
+package nutrition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// MinCoverage is the share of ingredients that must come from the food
+// database before a computed report replaces a recipe's AI nutrition
+const MinCoverage = 0.6
+
+// FoodRepository finds foods in the composition data
+type FoodRepository interface {
+	// Match returns the food best matching a MatchName, with its portions,
+	// or model.ErrNotFound
+	Match(ctx context.Context, name string) (*model.Food, error)
+}
+
+// Estimator estimates the nutrients in ingredients the food database can't
+// account for, one entry per ingredient in order
+type Estimator interface {
+	EstimateIngredientNutrition(ctx context.Context, ingredients []model.RecipeIngredient) ([]model.Nutrients, error)
+}
+
+// Reasons an ingredient isn't computed from the food database
+const (
+	ReasonOptional   = "optional ingredient"
+	ReasonNoQuantity = "no quantity"
+	ReasonNoFood     = "no matching food"
+	ReasonNoWeight   = "can't weigh this unit"
+)
+
+// Calculator computes nutrition reports
+type Calculator struct {
+	foods     FoodRepository
+	estimator Estimator
+}
+
+// NewCalculator creates a new calculator. estimator may be nil, in which
+// case unmatched ingredients are left out of the totals.
+func NewCalculator(foods FoodRepository, estimator Estimator) *Calculator {
+	return &Calculator{foods: foods, estimator: estimator}
+}
+
+// Calculate computes a recipe's nutrition ingredient by ingredient. Recipes
+// without servings are reported as a single serving.
+func (c *Calculator) Calculate(ctx context.Context, recipe *model.Recipe) (*model.NutritionReport, error) {
+	report := &model.NutritionReport{
+		RecipeID:    recipe.ID,
+		Servings:    1,
+		Ingredients: make([]model.IngredientNutrition, len(recipe.Ingredients)),
+	}
+	if recipe.Servings != nil && *recipe.Servings > 0 {
+		report.Servings = *recipe.Servings
+	}
+
+	var unmatched []int
+	for i, ing := range recipe.Ingredients {
+		entry := &report.Ingredients[i]
+		*entry = model.IngredientNutrition{IngredientID: ing.ID, Name: ing.Name, Source: model.NutritionSourceNone}
+
+		if ing.IsOptional {
+			entry.Reason = ReasonOptional
+			continue
+		}
+		if ing.Quantity == nil || *ing.Quantity <= 0 {
+			entry.Reason = ReasonNoQuantity
+			continue
+		}
+
+		food, err := c.foods.Match(ctx, MatchName(ing.Name))
+		if err != nil && !errors.Is(err, model.ErrNotFound) {
+			return nil, fmt.Errorf("match %q: %w", ing.Name, err)
+		}
+		if food == nil {
+			entry.Reason = ReasonNoFood
+			unmatched = append(unmatched, i)
+			continue
+		}
+		entry.FoodID = &food.FDCID
+		entry.Food = food.Description
+
+		unit := ""
+		if ing.Unit != nil {
+			unit = *ing.Unit
+		}
+		grams, ok := Grams(quantity(ing), unit, ing.Name, food)
+		if !ok {
+			entry.Reason = ReasonNoWeight
+			unmatched = append(unmatched, i)
+			continue
+		}
+
+		grams = math.Round(grams*10) / 10
+		nutrients := food.Per100g.Scale(grams / 100)
+		entry.Grams = &grams
+		entry.Nutrients = &nutrients
+		entry.Source = model.NutritionSourceUSDA
+	}
+
+	c.estimate(ctx, recipe.Ingredients, report.Ingredients, unmatched)
+
+	counted, computed := 0, 0
+	for i := range report.Ingredients {
+		entry := &report.Ingredients[i]
+		if entry.Reason == ReasonOptional || entry.Reason == ReasonNoQuantity {
+			continue
+		}
+		counted++
+		if entry.Source == model.NutritionSourceUSDA {
+			computed++
+		}
+		if entry.Nutrients != nil {
+			report.Total = report.Total.Add(*entry.Nutrients)
+			rounded := entry.Nutrients.Round()
+			entry.Nutrients = &rounded
+		}
+	}
+	if counted > 0 {
+		report.Coverage = math.Round(float64(computed)/float64(counted)*100) / 100
+	}
+
+	report.PerServing = report.Total.Scale(1 / float64(report.Servings)).Round()
+	report.Total = report.Total.Round()
+	report.Tags = report.PerServing.Tags()
+	return report, nil
+}
+
+// estimate asks the AI for the ingredients at indexes. The AI is only a
+// fallback, so when it fails the ingredients stay out of the totals with
+// their reason rather than failing the report.
+func (c *Calculator) estimate(ctx context.Context, ingredients []model.RecipeIngredient, entries []model.IngredientNutrition, indexes []int) {
+	if c.estimator == nil || len(indexes) == 0 {
+		return
+	}
+
+	pending := make([]model.RecipeIngredient, len(indexes))
+	for i, idx := range indexes {
+		pending[i] = ingredients[idx]
+	}
+	estimates, err := c.estimator.EstimateIngredientNutrition(ctx, pending)
+	if err != nil || len(estimates) != len(indexes) {
+		return
+	}
+
+	for i, idx := range indexes {
+		nutrients := estimates[i]
+		entries[idx].Nutrients = &nutrients
+		entries[idx].Source = model.NutritionSourceAI
+		entries[idx].FallbackReason, entries[idx].Reason = entries[idx].Reason, ""
+	}
+}
+
+// quantity is the amount an ingredient is weighed at: the middle of a
+// range like "2-3", or the quantity itself
+func quantity(ing model.RecipeIngredient) float64 {
+	if ing.QuantityMax != nil && *ing.QuantityMax > *ing.Quantity {
+		return (*ing.Quantity + *ing.QuantityMax) / 2
+	}
+	return *ing.Quantity
+}
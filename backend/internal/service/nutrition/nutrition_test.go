@@ -0,0 +1,187 @@
+package nutrition
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var (
+	onion = &model.Food{
+		FDCID: 170000, Description: "Onions, raw",
+		Per100g: model.Nutrients{Calories: 40, Carbs: 9.3, Fiber: 1.7, Sugar: 4.2, Sodium: 4},
+		Portions: []model.FoodPortion{
+			{Amount: 1, Unit: "cup", Modifier: "chopped", GramWeight: 160},
+			{Amount: 1, Modifier: "large", GramWeight: 150},
+			{Amount: 1, Modifier: "medium (2-1/2\" dia)", GramWeight: 110},
+		},
+	}
+	garlic = &model.Food{
+		FDCID: 169230, Description: "Garlic, raw",
+		Per100g:  model.Nutrients{Calories: 149, Protein: 6.4, Carbs: 33},
+		Portions: []model.FoodPortion{{Amount: 1, Modifier: "clove", GramWeight: 3}},
+	}
+	butter = &model.Food{
+		FDCID: 173410, Description: "Butter, salted",
+		Per100g: model.Nutrients{Calories: 717, Fat: 81, SaturatedFat: 51, Sodium: 643},
+	}
+	flour = &model.Food{FDCID: 168894, Description: "Wheat flour, white, all-purpose", Per100g: model.Nutrients{Calories: 364}}
+)
+
+func TestFoodMatchName(t *testing.T) {
+	tests := map[string]string{
+		"Onions, raw":                               "onion",
+		"Oil, olive, salad or cooking":              "olive oil",
+		"Spices, pepper, black":                     "pepper",
+		"Cheese, parmesan, grated":                  "parmesan cheese",
+		"Beans, black, mature seeds, raw":           "black bean",
+		"Tomatoes, red, ripe, raw, year round":      "tomato",
+		"Soup, chicken broth, ready-to-serve":       "chicken broth",
+		"Wheat flour, white, all-purpose, bleached": "wheat flour",
+	}
+	for description, want := range tests {
+		if got := FoodMatchName(description); got != want {
+			t.Errorf("FoodMatchName(%q) = %q, want %q", description, got, want)
+		}
+	}
+
+	if got := MatchName("Cherry Tomatoes"); got != "cherry tomato" {
+		t.Errorf("MatchName = %q", got)
+	}
+}
+
+func TestGrams(t *testing.T) {
+	tests := []struct {
+		name string
+		qty  float64
+		unit string
+		food *model.Food
+		want float64
+		ok   bool
+	}{
+		{"weight", 2, "oz", butter, 56.7, true},
+		{"volume from the food's own measure", 0.5, "cup", onion, 80, true},
+		{"tablespoons from a cup measure", 2, "tbsp", onion, 20, true},
+		{"volume from the density table", 2, "tbsp", butter, 28.4, true},
+		{"bare count uses a medium", 2, "", onion, 220, true},
+		{"named portion", 3, "cloves", garlic, 9, true},
+		{"dozen", 1, "dozen", garlic, 36, true},
+		{"no such portion", 1, "bunch", onion, 0, false},
+		{"flour by density", 1, "cup", flour, 125.4, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Grams(tt.qty, tt.unit, tt.food.Description, tt.food)
+			if ok != tt.ok || math.Abs(got-tt.want) > 0.1 {
+				t.Errorf("Grams(%v %q) = %.2f, %v; want %.2f, %v", tt.qty, tt.unit, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+type foodRepo map[string]*model.Food
+
+func (r foodRepo) Match(ctx context.Context, name string) (*model.Food, error) {
+	if f, ok := r[name]; ok {
+		return f, nil
+	}
+	return nil, model.ErrNotFound
+}
+
+type estimator struct {
+	asked []string
+	err   error
+}
+
+func (e *estimator) EstimateIngredientNutrition(ctx context.Context, ingredients []model.RecipeIngredient) ([]model.Nutrients, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	estimates := make([]model.Nutrients, len(ingredients))
+	for i, ing := range ingredients {
+		e.asked = append(e.asked, ing.Name)
+		estimates[i] = model.Nutrients{Calories: 100}
+	}
+	return estimates, nil
+}
+
+func TestCalculate(t *testing.T) {
+	foods := foodRepo{"onion": onion, "garlic": garlic, "butter": butter}
+	recipe := &model.Recipe{
+		ID:       uuid.New(),
+		Servings: ptr(2),
+		Ingredients: []model.RecipeIngredient{
+			{Name: "Onions", Quantity: ptr(2.0)},
+			{Name: "garlic", Quantity: ptr(2.0), QuantityMax: ptr(4.0), Unit: ptr("cloves")},
+			{Name: "butter", Quantity: ptr(100.0), Unit: ptr("g")},
+			{Name: "saffron", Quantity: ptr(1.0), Unit: ptr("pinch")},
+			{Name: "onion", Quantity: ptr(1.0), Unit: ptr("bunch")},
+			{Name: "salt"},
+			{Name: "parsley", Quantity: ptr(1.0), IsOptional: true},
+		},
+	}
+
+	t.Run("database with AI fallback", func(t *testing.T) {
+		ai := &estimator{}
+		report, err := NewCalculator(foods, ai).Calculate(context.Background(), recipe)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 220 g onion (88 kcal) + 9 g garlic (13.4) + 100 g butter (717),
+		// plus 100 kcal each for the saffron and the bunch of onion
+		if report.Total.Calories != 1018 || report.PerServing.Calories != 509 {
+			t.Errorf("calories total %.1f, per serving %.1f", report.Total.Calories, report.PerServing.Calories)
+		}
+		if report.Coverage != 0.6 {
+			t.Errorf("coverage %.2f, want 0.6", report.Coverage)
+		}
+		if len(ai.asked) != 2 || ai.asked[0] != "saffron" || ai.asked[1] != "onion" {
+			t.Errorf("AI asked for %v", ai.asked)
+		}
+
+		sources := []string{
+			model.NutritionSourceUSDA, model.NutritionSourceUSDA, model.NutritionSourceUSDA,
+			model.NutritionSourceAI, model.NutritionSourceAI, model.NutritionSourceNone, model.NutritionSourceNone,
+		}
+		reasons := []string{"", "", "", "", "", ReasonNoQuantity, ReasonOptional}
+		fallbacks := []string{"", "", "", ReasonNoFood, ReasonNoWeight, "", ""}
+		for i, entry := range report.Ingredients {
+			if entry.Source != sources[i] || entry.Reason != reasons[i] || entry.FallbackReason != fallbacks[i] {
+				t.Errorf("%s: source %q reason %q fallback %q", entry.Name, entry.Source, entry.Reason, entry.FallbackReason)
+			}
+		}
+		if g := report.Ingredients[1].Grams; g == nil || *g != 9 {
+			t.Errorf("garlic range should weigh 3 cloves, got %v", g)
+		}
+		if report.Ingredients[0].FoodID == nil || *report.Ingredients[0].FoodID != onion.FDCID {
+			t.Error("onion should name its food")
+		}
+	})
+
+	t.Run("AI unavailable", func(t *testing.T) {
+		report, err := NewCalculator(foods, &estimator{err: errors.New("quota")}).Calculate(context.Background(), recipe)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Total.Calories != 818 || report.Ingredients[3].Source != model.NutritionSourceNone {
+			t.Errorf("unmatched ingredients should be left out, got %.1f kcal", report.Total.Calories)
+		}
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		report, _ := NewCalculator(foods, nil).Calculate(context.Background(), recipe)
+		summary := report.Summary()
+		if summary.Source != model.NutritionSourceUSDA || summary.Calories != 409 || summary.Confidence != 0.6 {
+			t.Errorf("unexpected summary %+v", summary)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+// Package revision compares and restores recipe revisions.
+//
+// Ingredients are matched across versions by ID and then by name, so a
+// reordered list doesn't read as a rewrite; steps are matched by position
+// because their order is their meaning.
+package revision
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+// Diff returns the content differences between two versions of a recipe
+func Diff(from, to *model.Recipe) model.RecipeDiff {
+	return model.RecipeDiff{
+		Fields:      diffFields(from, to),
+		Ingredients: diffIngredients(from.Ingredients, to.Ingredients),
+		Steps:       diffSteps(from.Steps, to.Steps),
+	}
+}
+
+// Restore returns current with its content replaced by the snapshot's.
+// Identity, ownership, visibility and sync state stay those of current.
+func Restore(current, snapshot *model.Recipe) *model.Recipe {
+	restored := *current
+	restored.Title = snapshot.Title
+	restored.Description = snapshot.Description
+	restored.Servings = snapshot.Servings
+	restored.PrepTime = snapshot.PrepTime
+	restored.CookTime = snapshot.CookTime
+	restored.Difficulty = snapshot.Difficulty
+	restored.Cuisine = snapshot.Cuisine
+	restored.ThumbnailURL = snapshot.ThumbnailURL
+	restored.Tags = snapshot.Tags
+	restored.Nutrition = snapshot.Nutrition
+	restored.DietaryInfo = snapshot.DietaryInfo
+
+	restored.Ingredients = make([]model.RecipeIngredient, len(snapshot.Ingredients))
+	copy(restored.Ingredients, snapshot.Ingredients)
+	restored.Steps = make([]model.RecipeStep, len(snapshot.Steps))
+	copy(restored.Steps, snapshot.Steps)
+	return &restored
+}
+
+func diffFields(from, to *model.Recipe) []model.FieldChange {
+	fields := []struct {
+		name     string
+		from, to interface{}
+	}{
+		{"title", from.Title, to.Title},
+		{"description", deref(from.Description), deref(to.Description)},
+		{"servings", deref(from.Servings), deref(to.Servings)},
+		{"prepTime", deref(from.PrepTime), deref(to.PrepTime)},
+		{"cookTime", deref(from.CookTime), deref(to.CookTime)},
+		{"difficulty", deref(from.Difficulty), deref(to.Difficulty)},
+		{"cuisine", deref(from.Cuisine), deref(to.Cuisine)},
+		{"thumbnailUrl", deref(from.ThumbnailURL), deref(to.ThumbnailURL)},
+		{"tags", nilIfEmpty(from.Tags), nilIfEmpty(to.Tags)},
+		{"nutrition", from.Nutrition, to.Nutrition},
+		{"dietaryInfo", from.DietaryInfo, to.DietaryInfo},
+	}
+
+	changes := []model.FieldChange{}
+	for _, f := range fields {
+		if !reflect.DeepEqual(f.from, f.to) {
+			changes = append(changes, model.FieldChange{Field: f.name, From: f.from, To: f.to})
+		}
+	}
+	return changes
+}
+
+func diffIngredients(from, to []model.RecipeIngredient) []model.IngredientChange {
+	changes := []model.IngredientChange{}
+	matched := make([]bool, len(to))
+
+	for i := range from {
+		old := &from[i]
+		j := matchIngredient(old, to, matched)
+		if j < 0 {
+			changes = append(changes, model.IngredientChange{Change: model.DiffRemoved, Name: old.Name, From: old})
+			continue
+		}
+		matched[j] = true
+		if !sameIngredient(old, &to[j]) {
+			changes = append(changes, model.IngredientChange{Change: model.DiffChanged, Name: to[j].Name, From: old, To: &to[j]})
+		}
+	}
+
+	for j := range to {
+		if !matched[j] {
+			changes = append(changes, model.IngredientChange{Change: model.DiffAdded, Name: to[j].Name, To: &to[j]})
+		}
+	}
+	return changes
+}
+
+// matchIngredient finds the unmatched ingredient in candidates with the same
+// ID, falling back to the same name
+func matchIngredient(ing *model.RecipeIngredient, candidates []model.RecipeIngredient, matched []bool) int {
+	if ing.ID != uuid.Nil {
+		for j := range candidates {
+			if !matched[j] && candidates[j].ID == ing.ID {
+				return j
+			}
+		}
+	}
+	name := strings.ToLower(strings.TrimSpace(ing.Name))
+	for j := range candidates {
+		if !matched[j] && strings.ToLower(strings.TrimSpace(candidates[j].Name)) == name {
+			return j
+		}
+	}
+	return -1
+}
+
+func sameIngredient(a, b *model.RecipeIngredient) bool {
+	return a.Name == b.Name &&
+		reflect.DeepEqual(a.Quantity, b.Quantity) &&
+		reflect.DeepEqual(a.QuantityMax, b.QuantityMax) &&
+		deref(a.Unit) == deref(b.Unit) &&
+		a.Category == b.Category &&
+		a.Section == b.Section &&
+		a.IsOptional == b.IsOptional &&
+		deref(a.Notes) == deref(b.Notes)
+}
+
+func diffSteps(from, to []model.RecipeStep) []model.StepChange {
+	changes := []model.StepChange{}
+	for i := 0; i < len(from) || i < len(to); i++ {
+		switch {
+		case i >= len(to):
+			changes = append(changes, model.StepChange{Change: model.DiffRemoved, StepNumber: i + 1, From: &from[i]})
+		case i >= len(from):
+			changes = append(changes, model.StepChange{Change: model.DiffAdded, StepNumber: i + 1, To: &to[i]})
+		case !sameStep(&from[i], &to[i]):
+			changes = append(changes, model.StepChange{Change: model.DiffChanged, StepNumber: i + 1, From: &from[i], To: &to[i]})
+		}
+	}
+	return changes
+}
+
+func sameStep(a, b *model.RecipeStep) bool {
+	return a.Instruction == b.Instruction &&
+		deref(a.DurationSeconds) == deref(b.DurationSeconds) &&
+		deref(a.Technique) == deref(b.Technique) &&
+		deref(a.Temperature) == deref(b.Temperature)
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package revision
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func baseRecipe() *model.Recipe {
+	return &model.Recipe{
+		ID:       uuid.New(),
+		UserID:   uuid.New(),
+		Title:    "Pancakes",
+		Servings: ptr(4),
+		Tags:     []string{"breakfast"},
+		Ingredients: []model.RecipeIngredient{
+			{ID: uuid.New(), Name: "Flour", Quantity: ptr(200.0), Unit: ptr("g")},
+			{ID: uuid.New(), Name: "Milk", Quantity: ptr(300.0), Unit: ptr("ml")},
+			{ID: uuid.New(), Name: "Egg", Quantity: ptr(2.0)},
+		},
+		Steps: []model.RecipeStep{
+			{StepNumber: 1, Instruction: "Whisk everything together"},
+			{StepNumber: 2, Instruction: "Fry in a hot pan"},
+		},
+	}
+}
+
+func clone(r *model.Recipe) *model.Recipe {
+	c := *r
+	c.Ingredients = append([]model.RecipeIngredient(nil), r.Ingredients...)
+	c.Steps = append([]model.RecipeStep(nil), r.Steps...)
+	return &c
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	r := baseRecipe()
+	d := Diff(r, clone(r))
+	if len(d.Fields) != 0 || len(d.Ingredients) != 0 || len(d.Steps) != 0 {
+		t.Errorf("expected empty diff, got %+v", d)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := baseRecipe()
+	to := clone(from)
+
+	to.Title = "Fluffy pancakes"
+	to.Servings = ptr(6)
+	// Reordered, one edited, one removed, one added; new IDs for the
+	// edited one, as if it came back from a client that lost them
+	to.Ingredients = []model.RecipeIngredient{
+		from.Ingredients[1],
+		{ID: uuid.New(), Name: "flour", Quantity: ptr(250.0), Unit: ptr("g")},
+		{ID: uuid.New(), Name: "Sugar", Quantity: ptr(1.0), Unit: ptr("tbsp")},
+	}
+	to.Steps = []model.RecipeStep{
+		from.Steps[0],
+		{StepNumber: 2, Instruction: "Rest for 10 minutes"},
+		{StepNumber: 3, Instruction: "Fry in a hot pan"},
+	}
+
+	d := Diff(from, to)
+
+	fields := map[string]model.FieldChange{}
+	for _, f := range d.Fields {
+		fields[f.Field] = f
+	}
+	if len(fields) != 2 || fields["title"].To != "Fluffy pancakes" || fields["servings"].From != 4 {
+		t.Errorf("unexpected field changes: %+v", d.Fields)
+	}
+
+	changes := map[string]model.DiffChange{}
+	for _, c := range d.Ingredients {
+		changes[c.Name] = c.Change
+	}
+	want := map[string]model.DiffChange{
+		"flour": model.DiffChanged,
+		"Egg":   model.DiffRemoved,
+		"Sugar": model.DiffAdded,
+	}
+	if len(changes) != len(want) {
+		t.Errorf("ingredient changes = %v; want %v", changes, want)
+	}
+	for name, c := range want {
+		if changes[name] != c {
+			t.Errorf("ingredient %q: got %q, want %q", name, changes[name], c)
+		}
+	}
+
+	if len(d.Steps) != 2 ||
+		d.Steps[0].StepNumber != 2 || d.Steps[0].Change != model.DiffChanged ||
+		d.Steps[1].StepNumber != 3 || d.Steps[1].Change != model.DiffAdded {
+		t.Errorf("unexpected step changes: %+v", d.Steps)
+	}
+}
+
+func TestDiff_EmptyTagsAreEqual(t *testing.T) {
+	from := baseRecipe()
+	from.Tags = nil
+	to := clone(from)
+	to.Tags = []string{}
+	if d := Diff(from, to); len(d.Fields) != 0 {
+		t.Errorf("expected no field changes, got %+v", d.Fields)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	snapshot := baseRecipe()
+	current := clone(snapshot)
+	current.Title = "Crepes"
+	current.Ingredients = current.Ingredients[:1]
+	current.IsFavorite = true
+	current.SyncVersion = 7
+
+	restored := Restore(current, snapshot)
+
+	if restored.Title != "Pancakes" || len(restored.Ingredients) != 3 || len(restored.Steps) != 2 {
+		t.Errorf("content not restored: %+v", restored)
+	}
+	if !restored.IsFavorite || restored.SyncVersion != 7 || restored.ID != current.ID {
+		t.Errorf("identity and state should be kept from current: %+v", restored)
+	}
+	if current.Title != "Crepes" {
+		t.Error("current recipe was modified")
+	}
+
+	restored.Ingredients[0].Name = "Spelt flour"
+	if snapshot.Ingredients[0].Name != "Flour" {
+		t.Error("restored ingredients share memory with the snapshot")
+	}
+}
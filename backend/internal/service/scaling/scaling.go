@@ -0,0 +1,198 @@
+// Package scaling scales recipe quantities to a different number of servings.
+//
+// Quantities are rounded to amounts a cook can actually measure (1/3 cup,
+// 1 1/2 tsp, 190 g), switched to a larger or smaller unit when that reads
+// better (9 tsp → 3 tbsp, 1500 g → 1.5 kg), and ingredients that don't scale
+// linearly (salt, spices, leavening) are flagged with a note.
+package scaling
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/dishflow/backend/internal/model"
+	"github.com/dishflow/backend/internal/pkg/units"
+)
+
+// ErrNoServings is returned when a recipe has no base servings to scale from
+var ErrNoServings = errors.New("recipe has no servings to scale from")
+
+// MaxServings caps the requested serving count
+const MaxServings = 100
+
+// Notes attached to ingredients that shouldn't be scaled blindly
+const (
+	NoteSeasoning = "Seasonings don't scale linearly — start with less and adjust to taste"
+	NoteLeavening = "Leavening doesn't scale linearly — large batches may need less"
+	NoteImprecise = "Imprecise measure — adjust to taste"
+)
+
+// WarningPanSize is returned when a scaled recipe mentions cookware in its steps
+const WarningPanSize = "Pan size, depth and cooking times may need adjusting for the new quantity"
+
+// Factor returns the multiplier that turns base servings into target servings.
+// It returns 1 when the base is unknown.
+func Factor(base *int, target int) float64 {
+	if base == nil || *base <= 0 || target <= 0 {
+		return 1
+	}
+	return float64(target) / float64(*base)
+}
+
+// Recipe returns a copy of recipe scaled to servings. The original is not modified.
+func Recipe(recipe *model.Recipe, servings int) (*model.Recipe, error) {
+	if recipe.Servings == nil || *recipe.Servings <= 0 {
+		return nil, ErrNoServings
+	}
+
+	factor := Factor(recipe.Servings, servings)
+	scaled := *recipe
+	scaled.Servings = &servings
+	scaled.Scaling = &model.RecipeScaling{
+		OriginalServings: *recipe.Servings,
+		Servings:         servings,
+		Factor:           math.Round(factor*1000) / 1000,
+	}
+
+	scaled.Ingredients = make([]model.RecipeIngredient, len(recipe.Ingredients))
+	for i, ing := range recipe.Ingredients {
+		scaled.Ingredients[i] = Ingredient(ing, factor)
+	}
+
+	if factor != 1 && mentionsCookware(recipe.Steps) {
+		scaled.Scaling.Warnings = append(scaled.Scaling.Warnings, WarningPanSize)
+	}
+
+	return &scaled, nil
+}
+
+// Ingredient returns a copy of ing with its quantity multiplied by factor,
+// rounded to a measurable amount and expressed in the most readable unit.
+// A factor of 1 returns ing unchanged.
+func Ingredient(ing model.RecipeIngredient, factor float64) model.RecipeIngredient {
+	if factor == 1 {
+		return ing
+	}
+	ing.ScalingNote = nonLinearNote(ing.Name, ing.Unit)
+	if ing.Quantity == nil {
+		return ing
+	}
+
+	unit := ""
+	if ing.Unit != nil {
+		unit = *ing.Unit
+	}
+
+	qty, newUnit, display := scaleQuantity(*ing.Quantity*factor, unit)
+	ing.Quantity = &qty
+	ing.DisplayQuantity = display
+	if newUnit != unit {
+		ing.Unit = &newUnit
+	}
+	if ing.QuantityMax != nil {
+		max := scaleRangeMax(*ing.QuantityMax*factor, unit, newUnit)
+		ing.QuantityMax = &max
+		ing.DisplayQuantity += "-" + units.Format(max, lookupOrZero(newUnit))
+	}
+	return ing
+}
+
+// scaleRangeMax expresses the upper end of a range in the unit scaleQuantity
+// picked for the lower end, so "2-3 tsp" doesn't become "2 tsp-1 tbsp"
+func scaleRangeMax(amount float64, unit, newUnit string) float64 {
+	from, to := lookupOrZero(unit), lookupOrZero(newUnit)
+	if converted, err := units.Convert(amount, from, to); err == nil && from != to {
+		amount = converted
+	}
+	return units.Round(amount, to)
+}
+
+func lookupOrZero(unit string) units.Unit {
+	u, _ := units.Lookup(unit)
+	return u
+}
+
+// scaleQuantity rounds an exact amount and picks its unit. unit is returned
+// unchanged unless a different unit in the same system reads better.
+func scaleQuantity(amount float64, unit string) (float64, string, string) {
+	u, ok := units.Lookup(unit)
+	if !ok || u.Dimension == units.Count || u.Dimension == units.Temperature {
+		q := units.RoundCount(amount)
+		return q, unit, units.FormatFraction(q)
+	}
+
+	q, normalized := units.Normalize(amount, u)
+	name := unit
+	if normalized != u {
+		name = normalized.Label(q)
+	}
+	return q, name, units.Format(q, normalized)
+}
+
+// Keywords for ingredients whose amount shouldn't simply be multiplied
+var (
+	leaveningKeywords = []string{"baking powder", "baking soda", "bicarbonate", "yeast", "cream of tartar"}
+	seasoningKeywords = []string{
+		"salt", "pepper", "chili", "chilli", "cayenne", "paprika", "cumin", "coriander",
+		"cinnamon", "nutmeg", "allspice", "cardamom", "turmeric", "curry", "garam masala",
+		"ground cloves", "ginger powder", "garlic powder", "onion powder", "oregano", "thyme",
+		"rosemary", "pepper flakes", "hot sauce", "extract", "saffron",
+		"spice", "seasoning", "harissa", "ras el hanout", "za'atar", "zaatar", "sumac",
+	}
+	linearPeppers    = []string{"bell pepper", "sweet pepper", "peppers"}
+	impreciseUnits   = map[string]bool{"pinch": true, "pinches": true, "dash": true, "dashes": true, "to taste": true, "splash": true, "drizzle": true}
+	cookwareKeywords = []string{"pan", "dish", "tin", "skillet", "tray", "sheet pan", "baking sheet", "mold", "mould", "ramekin", "dutch oven", "casserole"}
+)
+
+// nonLinearNote returns a note for ingredients that don't scale proportionally
+func nonLinearNote(name string, unit *string) string {
+	lower := strings.ToLower(name)
+	if containsAnyWord(lower, leaveningKeywords) {
+		return NoteLeavening
+	}
+	// Bell peppers scale like any vegetable; ground pepper doesn't
+	if containsAnyWord(lower, seasoningKeywords) && !containsAnyWord(lower, linearPeppers) {
+		return NoteSeasoning
+	}
+	if unit != nil && impreciseUnits[strings.ToLower(strings.TrimSpace(*unit))] {
+		return NoteImprecise
+	}
+	return ""
+}
+
+// mentionsCookware reports whether any step refers to a pan, dish or tin
+func mentionsCookware(steps []model.RecipeStep) bool {
+	for _, step := range steps {
+		if containsAnyWord(strings.ToLower(step.Instruction), cookwareKeywords) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAnyWord reports whether s contains any keyword as a whole word
+// (a trailing plural "s" is allowed), so "salt" doesn't match "unsalted"
+func containsAnyWord(s string, keywords []string) bool {
+	for _, k := range keywords {
+		for from := 0; ; {
+			i := strings.Index(s[from:], k)
+			if i < 0 {
+				break
+			}
+			start, end := from+i, from+i+len(k)
+			if end < len(s) && s[end] == 's' {
+				end++
+			}
+			if (start == 0 || !isLetter(s[start-1])) && (end == len(s) || !isLetter(s[end])) {
+				return true
+			}
+			from = start + 1
+		}
+	}
+	return false
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z'
+}
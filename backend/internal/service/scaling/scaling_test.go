@@ -0,0 +1,127 @@
+package scaling
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/dishflow/backend/internal/model"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestScaleQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		unit     string
+		wantQty  float64
+		wantUnit string
+		display  string
+	}{
+		{"eggs never become thirds", 2.0 / 6, "", 0.5, "", "1/2"},
+		{"eggs round to halves", 1.7, "", 1.5, "", "1 1/2"},
+		{"many eggs are whole", 7.4, "", 7, "", "7"},
+		{"tsp steps up to tbsp", 9, "tsp", 3, "tbsp", "3"},
+		{"tbsp steps up to cup", 8, "tbsp", 0.5, "cup", "1/2"},
+		{"uneven tbsp stay tbsp", 5, "tbsp", 5, "tbsp", "5"},
+		{"cups step down", 1.0 / 16, "cups", 1, "tbsp", "1"},
+		{"thirds of a cup", 0.34, "cup", 1.0 / 3, "cup", "1/3"},
+		{"original spelling kept", 3, "tablespoons", 3, "tablespoons", "3"},
+		{"plural cups", 24, "tbsp", 1.5, "cups", "1 1/2"},
+		{"grams round to tens", 187.5, "g", 190, "g", "190"},
+		{"grams step up to kg", 1500, "g", 1.5, "kg", "1.5"},
+		{"ml below a litre", 750, "ml", 750, "ml", "750"},
+		{"oz to lb", 32, "oz", 2, "lb", "2"},
+		{"tiny amounts survive", 0.01, "tsp", 0.125, "tsp", "1/8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qty, unit, display := scaleQuantity(tt.amount, tt.unit)
+			if math.Abs(qty-tt.wantQty) > 1e-9 || unit != tt.wantUnit || display != tt.display {
+				t.Errorf("scaleQuantity(%v, %q) = %v %q %q; want %v %q %q",
+					tt.amount, tt.unit, qty, unit, display, tt.wantQty, tt.wantUnit, tt.display)
+			}
+		})
+	}
+}
+
+func TestNonLinearNote(t *testing.T) {
+	tests := []struct {
+		name string
+		unit *string
+		want string
+	}{
+		{"kosher salt", nil, NoteSeasoning},
+		{"unsalted butter", nil, ""},
+		{"black pepper", nil, NoteSeasoning},
+		{"red bell pepper", nil, ""},
+		{"baking soda", nil, NoteLeavening},
+		{"vanilla extract", nil, NoteSeasoning},
+		{"mixed spices", nil, NoteSeasoning},
+		{"lemon juice", ptr("splash"), NoteImprecise},
+		{"flour", ptr("cup"), ""},
+	}
+
+	for _, tt := range tests {
+		if got := nonLinearNote(tt.name, tt.unit); got != tt.want {
+			t.Errorf("nonLinearNote(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRecipe(t *testing.T) {
+	recipe := &model.Recipe{
+		Title:    "Pancakes",
+		Servings: ptr(6),
+		Ingredients: []model.RecipeIngredient{
+			{Name: "eggs", Quantity: ptr(1.0)},
+			{Name: "milk", Quantity: ptr(1.5), Unit: ptr("cups")},
+			{Name: "baking powder", Quantity: ptr(2.0), Unit: ptr("tsp")},
+			{Name: "salt", Unit: ptr("to taste")},
+		},
+		Steps: []model.RecipeStep{{Instruction: "Heat a non-stick pan over medium heat."}},
+	}
+
+	scaled, err := Recipe(recipe, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *scaled.Servings != 2 || scaled.Scaling.OriginalServings != 6 || scaled.Scaling.Factor != 0.333 {
+		t.Errorf("unexpected scaling info: servings %d, %+v", *scaled.Servings, scaled.Scaling)
+	}
+	if len(scaled.Scaling.Warnings) != 1 {
+		t.Errorf("expected pan size warning, got %v", scaled.Scaling.Warnings)
+	}
+
+	eggs := scaled.Ingredients[0]
+	if *eggs.Quantity != 0.5 || eggs.DisplayQuantity != "1/2" {
+		t.Errorf("eggs = %v (%q), want 1/2", *eggs.Quantity, eggs.DisplayQuantity)
+	}
+	milk := scaled.Ingredients[1]
+	if *milk.Quantity != 0.5 || *milk.Unit != "cups" {
+		t.Errorf("milk = %v %s, want 0.5 cups", *milk.Quantity, *milk.Unit)
+	}
+	if scaled.Ingredients[2].ScalingNote != NoteLeavening {
+		t.Errorf("baking powder note = %q", scaled.Ingredients[2].ScalingNote)
+	}
+	if scaled.Ingredients[3].Quantity != nil || scaled.Ingredients[3].ScalingNote != NoteSeasoning {
+		t.Errorf("salt = %+v", scaled.Ingredients[3])
+	}
+
+	garlic := Ingredient(model.RecipeIngredient{Name: "garlic", Quantity: ptr(2.0), QuantityMax: ptr(3.0), Unit: ptr("tsp")}, 3)
+	if *garlic.Quantity != 2 || *garlic.Unit != "tbsp" || *garlic.QuantityMax != 3 || garlic.DisplayQuantity != "2-3" {
+		t.Errorf("garlic range = %v-%v %s (%q), want 2-3 tbsp", *garlic.Quantity, *garlic.QuantityMax, *garlic.Unit, garlic.DisplayQuantity)
+	}
+
+	// Original is untouched
+	if *recipe.Servings != 6 || *recipe.Ingredients[0].Quantity != 1 || recipe.Scaling != nil {
+		t.Error("Recipe modified its input")
+	}
+
+	if _, err := Recipe(&model.Recipe{}, 4); !errors.Is(err, ErrNoServings) {
+		t.Errorf("expected ErrNoServings, got %v", err)
+	}
+}
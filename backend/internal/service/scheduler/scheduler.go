@@ -0,0 +1,316 @@
+// Package scheduler decides which queued extraction jobs get a processing slot.
+//
+// Jobs are grouped into lanes (video, light) that each have a fixed number of
+// slots. Within a lane, waiting jobs are served by priority class (Pro, free,
+// bulk) and then in arrival order, skipping users that already have their
+// maximum number of jobs running. Waiting jobs are promoted one class every
+// AgingInterval so lower classes are never starved outright.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Priority is a scheduling class. Lower values are served first.
+type Priority int
+
+const (
+	// PriorityPro is used for paying users
+	PriorityPro Priority = iota
+	// PriorityFree is used for free-tier users
+	PriorityFree
+	// PriorityBulk is used for admin/inspirator bulk imports, which should only
+	// use capacity that interactive users aren't waiting for
+	PriorityBulk
+)
+
+// String returns the class name used in logs
+func (p Priority) String() string {
+	switch p {
+	case PriorityPro:
+		return "pro"
+	case PriorityFree:
+		return "free"
+	case PriorityBulk:
+		return "bulk"
+	default:
+		return "unknown"
+	}
+}
+
+// Lane names used by the extraction handler
+const (
+	LaneVideo = "video"
+	LaneLight = "light"
+)
+
+// ErrUnknownLane is returned when a request names a lane that wasn't configured
+var ErrUnknownLane = errors.New("scheduler: unknown lane")
+
+// LaneConfig configures a lane
+type LaneConfig struct {
+	Name  string
+	Slots int
+	// ExpectedDuration seeds the wait estimate until real jobs have finished
+	ExpectedDuration time.Duration
+}
+
+// Config configures a Scheduler
+type Config struct {
+	Lanes []LaneConfig
+	// PerUserLimit caps running jobs per user in a lane (0 = unlimited)
+	PerUserLimit int
+	// BulkPerUserLimit overrides PerUserLimit for PriorityBulk requests (0 = use PerUserLimit)
+	BulkPerUserLimit int
+	// AgingInterval is how long a job waits before being promoted one class (0 disables aging)
+	AgingInterval time.Duration
+}
+
+// Request describes a job asking for a slot
+type Request struct {
+	JobID    uuid.UUID
+	UserID   uuid.UUID
+	Lane     string
+	Priority Priority
+}
+
+// QueueInfo describes a waiting job's place in its lane
+type QueueInfo struct {
+	Position      int           // 1-based position among waiting jobs in the lane
+	EstimatedWait time.Duration // rough time until the job starts
+}
+
+// durationSmoothing weights the latest job in the moving average of job durations
+const durationSmoothing = 0.2
+
+type waiter struct {
+	req      Request
+	enqueued time.Time
+	ready    chan struct{} // closed when a slot is granted
+	granted  bool
+}
+
+type lane struct {
+	name    string
+	slots   int
+	running int
+	users   map[uuid.UUID]int // running jobs per user
+	waiting []*waiter
+	avg     time.Duration // moving average of job duration
+}
+
+// Scheduler hands out processing slots. It is safe for concurrent use.
+type Scheduler struct {
+	perUserLimit     int
+	bulkPerUserLimit int
+	agingInterval    time.Duration
+	now              func() time.Time
+
+	mu    sync.Mutex
+	lanes map[string]*lane
+	jobs  map[uuid.UUID]*waiter // waiting jobs by ID
+}
+
+// New creates a scheduler
+func New(cfg Config) *Scheduler {
+	s := &Scheduler{
+		perUserLimit:     cfg.PerUserLimit,
+		bulkPerUserLimit: cfg.BulkPerUserLimit,
+		agingInterval:    cfg.AgingInterval,
+		now:              time.Now,
+		lanes:            make(map[string]*lane, len(cfg.Lanes)),
+		jobs:             make(map[uuid.UUID]*waiter),
+	}
+	for _, lc := range cfg.Lanes {
+		slots := lc.Slots
+		if slots < 1 {
+			slots = 1
+		}
+		s.lanes[lc.Name] = &lane{
+			name:  lc.Name,
+			slots: slots,
+			users: make(map[uuid.UUID]int),
+			avg:   lc.ExpectedDuration,
+		}
+	}
+	return s
+}
+
+// Acquire blocks until req is granted a slot or ctx is done. The returned
+// release function must be called exactly once when the job finishes.
+func (s *Scheduler) Acquire(ctx context.Context, req Request) (release func(), err error) {
+	s.mu.Lock()
+	l, ok := s.lanes[req.Lane]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrUnknownLane
+	}
+
+	w := &waiter{req: req, enqueued: s.now(), ready: make(chan struct{})}
+	l.waiting = append(l.waiting, w)
+	s.jobs[req.JobID] = w
+	s.dispatch(l)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		s.mu.Lock()
+		if !w.granted {
+			s.remove(l, w)
+			s.mu.Unlock()
+			return nil, ctx.Err()
+		}
+		s.mu.Unlock()
+		// Granted concurrently with cancellation; hand the slot back
+		s.releaseFunc(l, w)()
+		return nil, ctx.Err()
+	}
+
+	return s.releaseFunc(l, w), nil
+}
+
+// Position reports where a waiting job is in its lane. ok is false if the job
+// isn't waiting on this scheduler (already running, finished, or queued on
+// another instance).
+func (s *Scheduler) Position(jobID uuid.UUID) (info QueueInfo, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.jobs[jobID]
+	if !ok {
+		return QueueInfo{}, false
+	}
+	l := s.lanes[w.req.Lane]
+
+	ordered := s.ordered(l)
+	for i, o := range ordered {
+		if o == w {
+			info.Position = i + 1
+			break
+		}
+	}
+
+	// Jobs ahead are started in waves of l.slots, each taking about one average
+	// job duration. The job's own user cap can only make this longer.
+	waves := (info.Position + l.running - 1) / l.slots
+	info.EstimatedWait = time.Duration(waves) * l.avg
+	return info, true
+}
+
+// Stats returns running and waiting counts for a lane
+func (s *Scheduler) Stats(laneName string) (running, waiting int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l, ok := s.lanes[laneName]; ok {
+		return l.running, len(l.waiting)
+	}
+	return 0, 0
+}
+
+func (s *Scheduler) releaseFunc(l *lane, w *waiter) func() {
+	started := s.now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			l.running--
+			if l.users[w.req.UserID]--; l.users[w.req.UserID] <= 0 {
+				delete(l.users, w.req.UserID)
+			}
+
+			took := s.now().Sub(started)
+			if l.avg == 0 {
+				l.avg = took
+			} else {
+				l.avg += time.Duration(durationSmoothing * float64(took-l.avg))
+			}
+
+			s.dispatch(l)
+		})
+	}
+}
+
+// dispatch grants free slots to the best eligible waiters. Caller holds s.mu.
+func (s *Scheduler) dispatch(l *lane) {
+	for l.running < l.slots {
+		next := s.next(l)
+		if next == nil {
+			return
+		}
+		s.remove(l, next)
+		next.granted = true
+		l.running++
+		l.users[next.req.UserID]++
+		close(next.ready)
+	}
+}
+
+// next returns the highest-priority waiter whose user is under their cap
+func (s *Scheduler) next(l *lane) *waiter {
+	now := s.now()
+	var best *waiter
+	var bestPriority Priority
+	for _, w := range l.waiting {
+		if limit := s.userLimit(w.req.Priority); limit > 0 && l.users[w.req.UserID] >= limit {
+			continue
+		}
+		p := s.effectivePriority(w, now)
+		if best == nil || p < bestPriority || (p == bestPriority && w.enqueued.Before(best.enqueued)) {
+			best, bestPriority = w, p
+		}
+	}
+	return best
+}
+
+// ordered returns waiters in the order they would be served if no user caps applied
+func (s *Scheduler) ordered(l *lane) []*waiter {
+	now := s.now()
+	ordered := make([]*waiter, len(l.waiting))
+	copy(ordered, l.waiting)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		pi, pj := s.effectivePriority(ordered[i], now), s.effectivePriority(ordered[j], now)
+		if pi != pj {
+			return pi < pj
+		}
+		return ordered[i].enqueued.Before(ordered[j].enqueued)
+	})
+	return ordered
+}
+
+func (s *Scheduler) effectivePriority(w *waiter, now time.Time) Priority {
+	p := w.req.Priority
+	if s.agingInterval > 0 {
+		p -= Priority(now.Sub(w.enqueued) / s.agingInterval)
+	}
+	if p < PriorityPro {
+		p = PriorityPro
+	}
+	return p
+}
+
+func (s *Scheduler) userLimit(p Priority) int {
+	if p == PriorityBulk && s.bulkPerUserLimit > 0 {
+		return s.bulkPerUserLimit
+	}
+	return s.perUserLimit
+}
+
+// remove drops w from the lane's queue. Caller holds s.mu.
+func (s *Scheduler) remove(l *lane, w *waiter) {
+	for i, o := range l.waiting {
+		if o == w {
+			l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+			break
+		}
+	}
+	delete(s.jobs, w.req.JobID)
+}
@@ -0,0 +1,213 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestScheduler(slots, perUser int) *Scheduler {
+	return New(Config{
+		Lanes:        []LaneConfig{{Name: LaneVideo, Slots: slots, ExpectedDuration: 30 * time.Second}},
+		PerUserLimit: perUser,
+	})
+}
+
+// acquireAsync starts an Acquire in the background and returns a channel that
+// receives the release func once granted
+func acquireAsync(t *testing.T, s *Scheduler, req Request) <-chan func() {
+	t.Helper()
+	ch := make(chan func(), 1)
+	go func() {
+		release, err := s.Acquire(context.Background(), req)
+		if err != nil {
+			t.Errorf("Acquire(%v) error: %v", req.JobID, err)
+			return
+		}
+		ch <- release
+	}()
+	// Wait until the request is either queued or granted
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, queued := s.Position(req.JobID); queued {
+			return ch
+		}
+		select {
+		case release := <-ch:
+			ch <- release
+			return ch
+		default:
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("request %v never reached the scheduler", req.JobID)
+	return ch
+}
+
+func receive(t *testing.T, ch <-chan func()) func() {
+	t.Helper()
+	select {
+	case release := <-ch:
+		return release
+	case <-time.After(time.Second):
+		t.Fatal("expected slot to be granted")
+		return nil
+	}
+}
+
+func assertWaiting(t *testing.T, ch <-chan func()) {
+	t.Helper()
+	select {
+	case <-ch:
+		t.Fatal("expected request to still be waiting")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestSchedulerPriorityOrder(t *testing.T) {
+	s := newTestScheduler(1, 0)
+	holder := uuid.New()
+	first := receive(t, acquireAsync(t, s, Request{JobID: uuid.New(), UserID: holder, Lane: LaneVideo, Priority: PriorityFree}))
+
+	bulk := acquireAsync(t, s, Request{JobID: uuid.New(), UserID: uuid.New(), Lane: LaneVideo, Priority: PriorityBulk})
+	free := acquireAsync(t, s, Request{JobID: uuid.New(), UserID: uuid.New(), Lane: LaneVideo, Priority: PriorityFree})
+	proID := uuid.New()
+	pro := acquireAsync(t, s, Request{JobID: proID, UserID: uuid.New(), Lane: LaneVideo, Priority: PriorityPro})
+
+	if info, ok := s.Position(proID); !ok || info.Position != 1 {
+		t.Fatalf("pro position = %+v, %v; want 1", info, ok)
+	}
+
+	first()
+	release := receive(t, pro)
+	assertWaiting(t, free)
+	release()
+	release = receive(t, free)
+	assertWaiting(t, bulk)
+	release()
+	receive(t, bulk)()
+}
+
+func TestSchedulerPerUserLimit(t *testing.T) {
+	s := newTestScheduler(3, 1)
+	heavy := uuid.New()
+
+	first := receive(t, acquireAsync(t, s, Request{JobID: uuid.New(), UserID: heavy, Lane: LaneVideo, Priority: PriorityFree}))
+	second := acquireAsync(t, s, Request{JobID: uuid.New(), UserID: heavy, Lane: LaneVideo, Priority: PriorityFree})
+	assertWaiting(t, second)
+
+	// Another user is not blocked by the heavy user's queued job
+	other := receive(t, acquireAsync(t, s, Request{JobID: uuid.New(), UserID: uuid.New(), Lane: LaneVideo, Priority: PriorityFree}))
+	other()
+
+	first()
+	receive(t, second)()
+}
+
+func TestSchedulerAging(t *testing.T) {
+	s := New(Config{
+		Lanes:         []LaneConfig{{Name: LaneVideo, Slots: 1}},
+		AgingInterval: time.Minute,
+	})
+	clock := &fakeClock{now: time.Now()}
+	s.now = clock.Now
+
+	holder := receive(t, acquireAsync(t, s, Request{JobID: uuid.New(), UserID: uuid.New(), Lane: LaneVideo, Priority: PriorityPro}))
+	bulkID := uuid.New()
+	bulk := acquireAsync(t, s, Request{JobID: bulkID, UserID: uuid.New(), Lane: LaneVideo, Priority: PriorityBulk})
+
+	// Two aging intervals later a new Pro job arrives; the bulk job has been
+	// promoted to Pro and arrived first
+	clock.Advance(2 * time.Minute)
+	pro := acquireAsync(t, s, Request{JobID: uuid.New(), UserID: uuid.New(), Lane: LaneVideo, Priority: PriorityPro})
+
+	if info, _ := s.Position(bulkID); info.Position != 1 {
+		t.Fatalf("aged bulk position = %d, want 1", info.Position)
+	}
+
+	holder()
+	release := receive(t, bulk)
+	assertWaiting(t, pro)
+	release()
+	receive(t, pro)()
+}
+
+func TestSchedulerCancelWhileWaiting(t *testing.T) {
+	s := newTestScheduler(1, 0)
+	release := receive(t, acquireAsync(t, s, Request{JobID: uuid.New(), UserID: uuid.New(), Lane: LaneVideo}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	jobID := uuid.New()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := s.Acquire(ctx, Request{JobID: jobID, UserID: uuid.New(), Lane: LaneVideo})
+		errCh <- err
+	}()
+	for {
+		if _, ok := s.Position(jobID); ok {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if _, waiting := s.Stats(LaneVideo); waiting != 0 {
+		t.Fatalf("waiting = %d after cancel, want 0", waiting)
+	}
+	release()
+	if running, _ := s.Stats(LaneVideo); running != 0 {
+		t.Fatalf("running = %d after release, want 0", running)
+	}
+}
+
+func TestSchedulerEstimatedWait(t *testing.T) {
+	s := newTestScheduler(2, 0)
+	for i := 0; i < 2; i++ {
+		receive(t, acquireAsync(t, s, Request{JobID: uuid.New(), UserID: uuid.New(), Lane: LaneVideo}))
+	}
+
+	var ids []uuid.UUID
+	for i := 0; i < 3; i++ {
+		id := uuid.New()
+		ids = append(ids, id)
+		acquireAsync(t, s, Request{JobID: id, UserID: uuid.New(), Lane: LaneVideo})
+	}
+
+	// 2 running + queue of 3 on 2 slots: positions 1-2 start after one job
+	// duration, position 3 after two
+	want := []time.Duration{30 * time.Second, 30 * time.Second, 60 * time.Second}
+	for i, id := range ids {
+		info, ok := s.Position(id)
+		if !ok || info.Position != i+1 || info.EstimatedWait != want[i] {
+			t.Errorf("job %d: %+v, %v; want position %d wait %v", i, info, ok, i+1, want[i])
+		}
+	}
+
+	if _, err := s.Acquire(context.Background(), Request{Lane: "missing"}); !errors.Is(err, ErrUnknownLane) {
+		t.Errorf("unknown lane err = %v", err)
+	}
+}
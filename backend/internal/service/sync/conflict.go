@@ -92,6 +92,50 @@ func (r *ConflictResolver) ResolveShoppingItem(clientItem, serverItem *model.Sho
 	return serverItem, conflict
 }
 
+// ResolveCollection resolves conflicts for collections (Last-Write-Wins)
+func (r *ConflictResolver) ResolveCollection(clientCollection, serverCollection *model.Collection) (winner *model.Collection, conflict *model.Conflict) {
+	// Last-Write-Wins based on updated_at timestamp
+	if clientCollection.UpdatedAt.After(serverCollection.UpdatedAt) {
+		conflict = &model.Conflict{
+			ResourceType: string(model.ResourceTypeCollection),
+			ResourceID:   clientCollection.ID,
+			Resolution:   string(model.ClientWins),
+			Reason:       "Client version is newer",
+		}
+		return clientCollection, conflict
+	}
+
+	conflict = &model.Conflict{
+		ResourceType: string(model.ResourceTypeCollection),
+		ResourceID:   serverCollection.ID,
+		Resolution:   string(model.ServerWins),
+		Reason:       "Server version is newer",
+	}
+	return serverCollection, conflict
+}
+
+// ResolveAnnotation resolves conflicts for recipe notes (Last-Write-Wins)
+func (r *ConflictResolver) ResolveAnnotation(clientNote, serverNote *model.RecipeAnnotation) (winner *model.RecipeAnnotation, conflict *model.Conflict) {
+	// Last-Write-Wins based on updated_at timestamp
+	if clientNote.UpdatedAt.After(serverNote.UpdatedAt) {
+		conflict = &model.Conflict{
+			ResourceType: string(model.ResourceTypeAnnotation),
+			ResourceID:   clientNote.ID,
+			Resolution:   string(model.ClientWins),
+			Reason:       "Client version is newer",
+		}
+		return clientNote, conflict
+	}
+
+	conflict = &model.Conflict{
+		ResourceType: string(model.ResourceTypeAnnotation),
+		ResourceID:   serverNote.ID,
+		Resolution:   string(model.ServerWins),
+		Reason:       "Server version is newer",
+	}
+	return serverNote, conflict
+}
+
 // ShouldResolve determines if a conflict needs resolution
 // Returns true if both items exist and have different sync versions
 func (r *ConflictResolver) ShouldResolve(clientVersion, serverVersion int, clientDeleted, serverDeleted *time.Time) bool {
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,10 +13,12 @@ import (
 
 // Service handles synchronization logic
 type Service struct {
-	recipeRepo   *postgres.RecipeRepository
-	pantryRepo   *postgres.PantryRepository
-	shoppingRepo *postgres.ShoppingRepository
-	resolver     *ConflictResolver
+	recipeRepo     *postgres.RecipeRepository
+	pantryRepo     *postgres.PantryRepository
+	shoppingRepo   *postgres.ShoppingRepository
+	collectionRepo *postgres.CollectionRepository
+	annotationRepo *postgres.AnnotationRepository
+	resolver       *ConflictResolver
 }
 
 // NewService creates a new sync service
@@ -23,12 +26,16 @@ func NewService(
 	recipeRepo *postgres.RecipeRepository,
 	pantryRepo *postgres.PantryRepository,
 	shoppingRepo *postgres.ShoppingRepository,
+	collectionRepo *postgres.CollectionRepository,
+	annotationRepo *postgres.AnnotationRepository,
 ) *Service {
 	return &Service{
-		recipeRepo:   recipeRepo,
-		pantryRepo:   pantryRepo,
-		shoppingRepo: shoppingRepo,
-		resolver:     NewConflictResolver(),
+		recipeRepo:     recipeRepo,
+		pantryRepo:     pantryRepo,
+		shoppingRepo:   shoppingRepo,
+		collectionRepo: collectionRepo,
+		annotationRepo: annotationRepo,
+		resolver:       NewConflictResolver(),
 	}
 }
 
@@ -59,6 +66,16 @@ func (s *Service) Sync(ctx context.Context, userID uuid.UUID, req *model.SyncReq
 		return nil, err
 	}
 
+	// Process collections after recipes so new recipes can be members
+	if err := s.syncCollections(ctx, userID, req.Collections, response); err != nil {
+		return nil, err
+	}
+
+	// Process notes after recipes so notes can be on new recipes
+	if err := s.syncAnnotations(ctx, userID, req.Annotations, response); err != nil {
+		return nil, err
+	}
+
 	// Get server changes since last sync
 	if err := s.getServerChanges(ctx, userID, req.LastSyncTimestamp, response); err != nil {
 		return nil, err
@@ -72,12 +89,12 @@ func (s *Service) syncRecipes(ctx context.Context, userID uuid.UUID, clientRecip
 	for _, clientRecipe := range clientRecipes {
 		// Get server version if it exists
 		serverRecipe, err := s.recipeRepo.GetByID(ctx, clientRecipe.ID)
-		if err != nil && err != model.ErrNotFound {
+		if err != nil && !errors.Is(err, postgres.ErrRecipeNotFound) {
 			return err
 		}
 
 		// New recipe from client
-		if err == model.ErrNotFound {
+		if errors.Is(err, postgres.ErrRecipeNotFound) {
 			// Ensure user ID matches
 			clientRecipe.UserID = userID
 			if err := s.recipeRepo.Upsert(ctx, &clientRecipe); err != nil {
@@ -89,6 +106,17 @@ func (s *Service) syncRecipes(ctx context.Context, userID uuid.UUID, clientRecip
 		// Check if conflict resolution needed
 		if s.resolver.ShouldResolve(clientRecipe.SyncVersion, serverRecipe.SyncVersion, clientRecipe.DeletedAt, serverRecipe.DeletedAt) {
 			winner, conflict := s.resolver.ResolveRecipe(&clientRecipe, serverRecipe)
+
+			// Keep the losing client edit in the recipe's history so it
+			// can be diffed and restored
+			if conflict.Resolution == string(model.ServerWins) && serverRecipe.UserID == userID && clientRecipe.DeletedAt == nil {
+				clientRecipe.UserID = userID
+				rev, err := s.recipeRepo.SaveConflictRevision(ctx, &clientRecipe)
+				if err != nil {
+					return err
+				}
+				conflict.RevisionID = &rev.ID
+			}
 			response.Conflicts = append(response.Conflicts, *conflict)
 
 			// If client wins, update server
@@ -245,6 +273,108 @@ func (s *Service) syncShoppingItems(ctx context.Context, userID uuid.UUID, clien
 	return nil
 }
 
+// syncCollections processes collection changes from client
+func (s *Service) syncCollections(ctx context.Context, userID uuid.UUID, clientCollections []model.Collection, response *model.SyncResponse) error {
+	for _, clientCollection := range clientCollections {
+		// Get server version if it exists
+		serverCollection, err := s.collectionRepo.Get(ctx, clientCollection.ID, userID)
+		if err != nil && !errors.Is(err, model.ErrNotFound) {
+			return err
+		}
+
+		// New collection from client
+		if errors.Is(err, model.ErrNotFound) {
+			if clientCollection.DeletedAt != nil {
+				continue
+			}
+			// ErrNotFound means the ID belongs to another user's collection; skip it
+			if err := s.collectionRepo.Upsert(ctx, userID, &clientCollection); err != nil && !errors.Is(err, model.ErrNotFound) {
+				return err
+			}
+			continue
+		}
+
+		// Check if conflict resolution needed
+		if s.resolver.ShouldResolve(clientCollection.SyncVersion, serverCollection.SyncVersion, clientCollection.DeletedAt, serverCollection.DeletedAt) {
+			winner, conflict := s.resolver.ResolveCollection(&clientCollection, serverCollection)
+			response.Conflicts = append(response.Conflicts, *conflict)
+
+			// If client wins, update server
+			if conflict.Resolution == string(model.ClientWins) {
+				if err := s.collectionRepo.Upsert(ctx, userID, winner); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// syncAnnotations processes recipe note changes from client
+func (s *Service) syncAnnotations(ctx context.Context, userID uuid.UUID, clientNotes []model.RecipeAnnotation, response *model.SyncResponse) error {
+	for _, clientNote := range clientNotes {
+		if err := s.anchorAnnotation(ctx, &clientNote); err != nil {
+			return err
+		}
+
+		// Get server version if it exists
+		serverNote, err := s.annotationRepo.Get(ctx, clientNote.ID, userID)
+		if err != nil && !errors.Is(err, model.ErrNotFound) {
+			return err
+		}
+
+		// New note from client
+		if errors.Is(err, model.ErrNotFound) {
+			if clientNote.DeletedAt != nil {
+				continue
+			}
+			// ErrNotFound means the ID is another user's note or the recipe
+			// isn't visible to this user; skip it
+			if err := s.annotationRepo.Upsert(ctx, userID, &clientNote); err != nil && !errors.Is(err, model.ErrNotFound) {
+				return err
+			}
+			continue
+		}
+
+		// Check if conflict resolution needed
+		if s.resolver.ShouldResolve(clientNote.SyncVersion, serverNote.SyncVersion, clientNote.DeletedAt, serverNote.DeletedAt) {
+			winner, conflict := s.resolver.ResolveAnnotation(&clientNote, serverNote)
+			response.Conflicts = append(response.Conflicts, *conflict)
+
+			// If client wins, update server
+			if conflict.Resolution == string(model.ClientWins) {
+				if err := s.annotationRepo.Upsert(ctx, userID, winner); err != nil && !errors.Is(err, model.ErrNotFound) {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// anchorAnnotation sets the anchor and position of a live note from its
+// ingredient or step, as clients only send the target ID
+func (s *Service) anchorAnnotation(ctx context.Context, note *model.RecipeAnnotation) error {
+	if note.DeletedAt != nil {
+		return nil
+	}
+
+	recipe, err := s.recipeRepo.GetByID(ctx, note.RecipeID)
+	if errors.Is(err, postgres.ErrRecipeNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if anchor, position, ok := recipe.AnnotationTarget(note.Target, note.TargetID); ok {
+		note.Anchor, note.Position = anchor, position
+	}
+	return nil
+}
+
 // getServerChanges retrieves all changes from server since last sync
 func (s *Service) getServerChanges(ctx context.Context, userID uuid.UUID, since time.Time, response *model.SyncResponse) error {
 	// Get recipe changes
@@ -279,5 +409,19 @@ func (s *Service) getServerChanges(ctx context.Context, userID uuid.UUID, since
 	response.ShoppingLists = lists
 	response.ShoppingItems = items
 
+	// Get collection changes
+	collections, err := s.collectionRepo.GetChangesSince(ctx, userID, since)
+	if err != nil {
+		return err
+	}
+	response.Collections = collections
+
+	// Get recipe note changes
+	annotations, err := s.annotationRepo.GetChangesSince(ctx, userID, since)
+	if err != nil {
+		return err
+	}
+	response.Annotations = annotations
+
 	return nil
 }
@@ -53,47 +53,18 @@ func (d *Downloader) EnsureDir() error {
 // Download fetches the image at url, saves it to disk, and returns the public serving URL.
 // If the download fails, it returns an empty string and the error.
 func (d *Downloader) Download(ctx context.Context, url string) (string, error) {
-	if url == "" {
-		return "", fmt.Errorf("empty URL")
-	}
-
-	// Upgrade http to https
-	if strings.HasPrefix(url, "http://") {
-		url = "https://" + url[7:]
-	}
-
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	body, ext, err := d.open(ctx, url)
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
-	}
-
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("fetch thumbnail: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
-	}
-
-	ct := resp.Header.Get("Content-Type")
-	if !strings.HasPrefix(ct, "image/") {
-		return "", fmt.Errorf("not an image: %s", ct)
-	}
-
-	// Early rejection if Content-Length is provided and too large
-	if resp.ContentLength > maxSize {
-		return "", fmt.Errorf("image too large (%d bytes)", resp.ContentLength)
+		return "", err
 	}
+	defer body.Close()
 
-	ext := extensionFromContentType(ct)
 	filename := uuid.New().String() + ext
 
-	limited := io.LimitReader(resp.Body, maxSize+1)
+	limited := io.LimitReader(body, maxSize+1)
 
 	outPath := filepath.Join(d.dir, filename)
 	f, err := os.Create(outPath)
@@ -121,6 +92,99 @@ func (d *Downloader) Download(ctx context.Context, url string) (string, error) {
 	return publicURL, nil
 }
 
+// Save stores image data under a new name and returns its public serving URL.
+// ext is the file extension including the dot, e.g. ".jpg".
+func (d *Downloader) Save(data []byte, ext string) (string, error) {
+	if len(data) > maxSize {
+		return "", fmt.Errorf("image too large (%d bytes)", len(data))
+	}
+	switch ext {
+	case ".jpg", ".png", ".webp", ".gif":
+	default:
+		return "", fmt.Errorf("unsupported image type %q", ext)
+	}
+
+	filename := uuid.New().String() + ext
+	if err := os.WriteFile(filepath.Join(d.dir, filename), data, 0o644); err != nil {
+		return "", fmt.Errorf("write file: %w", err)
+	}
+	return d.baseURL + "/api/v1/thumbnails/" + filename, nil
+}
+
+// Fetch returns the image at url and its file extension. Thumbnails this
+// server stores are read from disk instead of over HTTP.
+func (d *Downloader) Fetch(ctx context.Context, url string) ([]byte, string, error) {
+	if name, ok := strings.CutPrefix(url, d.baseURL+"/api/v1/thumbnails/"); ok {
+		if name == "" || strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+			return nil, "", fmt.Errorf("invalid thumbnail name")
+		}
+		data, err := os.ReadFile(filepath.Join(d.dir, name))
+		if err != nil {
+			return nil, "", fmt.Errorf("read thumbnail: %w", err)
+		}
+		return data, filepath.Ext(name), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	body, ext, err := d.open(ctx, url)
+	if err != nil {
+		return nil, "", err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("read thumbnail: %w", err)
+	}
+	if len(data) > maxSize {
+		return nil, "", fmt.Errorf("image too large (%d bytes)", len(data))
+	}
+	return data, ext, nil
+}
+
+// open requests a remote image and returns its body and file extension
+func (d *Downloader) open(ctx context.Context, url string) (io.ReadCloser, string, error) {
+	if url == "" {
+		return nil, "", fmt.Errorf("empty URL")
+	}
+
+	// Upgrade http to https
+	if strings.HasPrefix(url, "http://") {
+		url = "https://" + url[7:]
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch thumbnail: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	ct := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(ct, "image/") {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("not an image: %s", ct)
+	}
+
+	// Early rejection if Content-Length is provided and too large
+	if resp.ContentLength > maxSize {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("image too large (%d bytes)", resp.ContentLength)
+	}
+
+	return resp.Body, extensionFromContentType(ct), nil
+}
+
 func extensionFromContentType(ct string) string {
 	switch {
 	case strings.Contains(ct, "jpeg"), strings.Contains(ct, "jpg"):
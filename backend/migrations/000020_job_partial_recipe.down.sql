@@ -0,0 +1 @@
+ALTER TABLE video_jobs DROP COLUMN IF EXISTS partial_recipe;
@@ -0,0 +1,3 @@
+-- Partial recipe preview for running extraction jobs
+-- Written progressively while Gemini streams its response, cleared once the job finishes
+ALTER TABLE video_jobs ADD COLUMN IF NOT EXISTS partial_recipe JSONB;
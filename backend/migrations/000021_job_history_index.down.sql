@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_video_jobs_user_created;
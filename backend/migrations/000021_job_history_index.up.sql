@@ -0,0 +1,3 @@
+-- Keyset pagination index for job history
+-- Matches ListByUser's ORDER BY created_at DESC, id DESC over non-deleted jobs
+CREATE INDEX IF NOT EXISTS idx_video_jobs_user_created ON video_jobs(user_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
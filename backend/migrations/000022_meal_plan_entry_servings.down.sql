@@ -0,0 +1 @@
+ALTER TABLE meal_plan_entries DROP COLUMN IF EXISTS servings;
@@ -0,0 +1,3 @@
+-- Optional serving count per meal plan entry
+-- NULL means the recipe's own servings; used to scale generated shopping lists
+ALTER TABLE meal_plan_entries ADD COLUMN IF NOT EXISTS servings SMALLINT CHECK (servings > 0);
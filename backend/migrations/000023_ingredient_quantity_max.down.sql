@@ -0,0 +1,2 @@
+ALTER TABLE recipe_ingredients DROP CONSTRAINT IF EXISTS check_ingredient_quantity_range;
+ALTER TABLE recipe_ingredients DROP COLUMN IF EXISTS quantity_max;
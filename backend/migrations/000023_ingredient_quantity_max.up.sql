@@ -0,0 +1,5 @@
+-- Upper bound for ingredient quantity ranges ("2-3 cloves")
+-- quantity holds the lower bound; NULL means a single amount
+ALTER TABLE recipe_ingredients ADD COLUMN IF NOT EXISTS quantity_max DECIMAL(10, 3);
+ALTER TABLE recipe_ingredients ADD CONSTRAINT check_ingredient_quantity_range
+    CHECK (quantity_max IS NULL OR quantity_max >= quantity);
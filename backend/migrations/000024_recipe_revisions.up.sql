@@ -0,0 +1,13 @@
+-- Append-only recipe history
+-- One snapshot per saved sync_version, plus client edits that lost a sync conflict
+CREATE TABLE IF NOT EXISTS recipe_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    sync_version INTEGER NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('create', 'update', 'sync', 'conflict', 'restore')),
+    restored_from UUID REFERENCES recipe_revisions(id) ON DELETE SET NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_revisions_recipe ON recipe_revisions(recipe_id, created_at DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS collection_recipes;
+DROP TRIGGER IF EXISTS collections_updated_at ON collections;
+DROP TABLE IF EXISTS collections;
@@ -0,0 +1,31 @@
+-- User-defined recipe collections (cookbooks)
+-- A recipe can belong to any number of collections; order within a collection is manual
+CREATE TABLE IF NOT EXISTS collections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL CHECK (LENGTH(TRIM(name)) > 0),
+    description TEXT,
+    cover_image_url TEXT,
+    cover_recipe_id UUID REFERENCES recipes(id) ON DELETE SET NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    sync_version INTEGER DEFAULT 1,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_collections_user ON collections(user_id, sort_order) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_collections_sync ON collections(user_id, updated_at);
+
+CREATE TRIGGER collections_updated_at BEFORE UPDATE ON collections FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+CREATE TABLE IF NOT EXISTS collection_recipes (
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (collection_id, recipe_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_recipes_order ON collection_recipes(collection_id, sort_order);
+CREATE INDEX IF NOT EXISTS idx_collection_recipes_recipe ON collection_recipes(recipe_id);
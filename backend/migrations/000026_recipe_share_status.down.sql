@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_recipe_shares_owner;
+DROP INDEX IF EXISTS idx_recipe_shares_recipe_email;
+
+ALTER TABLE recipe_shares
+    DROP COLUMN IF EXISTS accepted_recipe_id,
+    DROP COLUMN IF EXISTS declined_at,
+    DROP COLUMN IF EXISTS status;
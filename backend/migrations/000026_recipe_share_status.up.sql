@@ -0,0 +1,15 @@
+-- Track the lifecycle of recipe shares
+-- Shares start pending, and the recipient accepts (receiving a copy) or declines
+ALTER TABLE recipe_shares
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'declined')),
+    ADD COLUMN IF NOT EXISTS declined_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS accepted_recipe_id UUID REFERENCES recipes(id) ON DELETE SET NULL;
+
+UPDATE recipe_shares SET status = 'accepted' WHERE accepted_at IS NOT NULL;
+
+-- One share per recipe and invited email until the invite is claimed
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_shares_recipe_email
+    ON recipe_shares(recipe_id, LOWER(recipient_email)) WHERE recipient_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_recipe_shares_owner ON recipe_shares(owner_id, created_at DESC);
@@ -0,0 +1,14 @@
+-- Public share links: an unguessable token that renders a recipe for people without the app
+-- A recipe has at most one active link; revoking it stops the token working immediately
+CREATE TABLE IF NOT EXISTS recipe_share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    view_count INTEGER NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_share_links_active ON recipe_share_links(recipe_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,3 @@
+-- Per-recipe outcome of bulk import jobs (job_type = 'import')
+-- Updated as the import progresses and kept once it finishes
+ALTER TABLE video_jobs ADD COLUMN IF NOT EXISTS import_result JSONB;
@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS recipe_ingredients_search_delete ON recipe_ingredients;
+DROP TRIGGER IF EXISTS recipe_ingredients_search_update ON recipe_ingredients;
+DROP TRIGGER IF EXISTS recipe_ingredients_search_insert ON recipe_ingredients;
+DROP TRIGGER IF EXISTS recipes_search ON recipes;
+DROP FUNCTION IF EXISTS recipe_ingredients_search_refresh();
+DROP FUNCTION IF EXISTS recipes_search_refresh();
+DROP FUNCTION IF EXISTS refresh_recipe_search(UUID);
+DROP FUNCTION IF EXISTS recipe_search_document(REGCONFIG, TEXT, TEXT[], TEXT, TEXT, TEXT);
+DROP TABLE IF EXISTS recipe_search;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_search ON recipes USING gin(to_tsvector('english', title || ' ' || COALESCE(description, '')));
+
+-- pg_trgm is left installed; other objects may depend on it
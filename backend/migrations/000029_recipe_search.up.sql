@@ -0,0 +1,103 @@
+-- Full-text and fuzzy recipe search
+-- Search documents live in their own table, maintained by triggers, so
+-- reindexing a recipe never bumps recipes.updated_at (which drives sync)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Superseded by recipe_search below
+DROP INDEX IF EXISTS idx_recipes_search;
+
+CREATE TABLE recipe_search (
+    recipe_id UUID PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    -- One weighted document per supported language: title (A),
+    -- tags and cuisine (B), ingredient names (C), description (D)
+    search_en TSVECTOR NOT NULL,
+    search_fr TSVECTOR NOT NULL,
+    search_es TSVECTOR NOT NULL,
+    -- Lowercased title, tags, cuisine and ingredient names for typo matching
+    words TEXT NOT NULL
+);
+
+CREATE INDEX idx_recipe_search_en ON recipe_search USING gin(search_en);
+CREATE INDEX idx_recipe_search_fr ON recipe_search USING gin(search_fr);
+CREATE INDEX idx_recipe_search_es ON recipe_search USING gin(search_es);
+CREATE INDEX idx_recipe_search_words ON recipe_search USING gin(words gin_trgm_ops);
+
+CREATE OR REPLACE FUNCTION recipe_search_document(
+    cfg REGCONFIG, title TEXT, tags TEXT[], cuisine TEXT, ingredients TEXT, description TEXT
+)
+RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector(cfg, COALESCE(title, '')), 'A')
+        || setweight(to_tsvector(cfg, concat_ws(' ', array_to_string(tags, ' '), cuisine)), 'B')
+        || setweight(to_tsvector(cfg, COALESCE(ingredients, '')), 'C')
+        || setweight(to_tsvector(cfg, COALESCE(description, '')), 'D');
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Rebuilds one recipe's search row from the recipe and its ingredients
+CREATE OR REPLACE FUNCTION refresh_recipe_search(rid UUID)
+RETURNS VOID AS $$
+DECLARE
+    r recipes%ROWTYPE;
+    ingredients TEXT;
+BEGIN
+    SELECT * INTO r FROM recipes WHERE id = rid;
+    IF NOT FOUND THEN
+        RETURN; -- deleted along with its ingredients
+    END IF;
+
+    SELECT string_agg(name, ' ' ORDER BY sort_order) INTO ingredients
+    FROM recipe_ingredients WHERE recipe_id = rid;
+
+    INSERT INTO recipe_search (recipe_id, search_en, search_fr, search_es, words)
+    VALUES (
+        rid,
+        recipe_search_document('english', r.title, r.tags, r.cuisine, ingredients, r.description),
+        recipe_search_document('french', r.title, r.tags, r.cuisine, ingredients, r.description),
+        recipe_search_document('spanish', r.title, r.tags, r.cuisine, ingredients, r.description),
+        lower(concat_ws(' ', r.title, array_to_string(r.tags, ' '), r.cuisine, ingredients))
+    )
+    ON CONFLICT (recipe_id) DO UPDATE SET
+        search_en = EXCLUDED.search_en,
+        search_fr = EXCLUDED.search_fr,
+        search_es = EXCLUDED.search_es,
+        words = EXCLUDED.words;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION recipes_search_refresh()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_recipe_search(NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER recipes_search
+    AFTER INSERT OR UPDATE OF title, description, cuisine, tags ON recipes
+    FOR EACH ROW EXECUTE FUNCTION recipes_search_refresh();
+
+-- Ingredient writes refresh each affected recipe once per statement
+CREATE OR REPLACE FUNCTION recipe_ingredients_search_refresh()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_recipe_search(recipe_id) FROM (SELECT DISTINCT recipe_id FROM changed) c;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER recipe_ingredients_search_insert
+    AFTER INSERT ON recipe_ingredients
+    REFERENCING NEW TABLE AS changed
+    FOR EACH STATEMENT EXECUTE FUNCTION recipe_ingredients_search_refresh();
+
+CREATE TRIGGER recipe_ingredients_search_update
+    AFTER UPDATE ON recipe_ingredients
+    REFERENCING NEW TABLE AS changed
+    FOR EACH STATEMENT EXECUTE FUNCTION recipe_ingredients_search_refresh();
+
+CREATE TRIGGER recipe_ingredients_search_delete
+    AFTER DELETE ON recipe_ingredients
+    REFERENCING OLD TABLE AS changed
+    FOR EACH STATEMENT EXECUTE FUNCTION recipe_ingredients_search_refresh();
+
+-- Index existing recipes
+SELECT refresh_recipe_search(id) FROM recipes;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_recipe_ingredients_match_name;
+ALTER TABLE recipe_ingredients DROP COLUMN IF EXISTS match_name;
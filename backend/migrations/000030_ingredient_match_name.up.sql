@@ -0,0 +1,24 @@
+-- Normalized ingredient names for "what can I make with..." search.
+-- New rows get ingredient.MatchKey from the application; this backfill
+-- mirrors it: punctuation collapsed to spaces, lowercased, and a clear
+-- English plural stripped from the end.
+ALTER TABLE recipe_ingredients ADD COLUMN IF NOT EXISTS match_name TEXT;
+
+UPDATE recipe_ingredients SET match_name = k.key
+FROM (
+    SELECT id, trim(regexp_replace(lower(name), '[^[:alnum:]]+', ' ', 'g')) AS key
+    FROM recipe_ingredients
+) k0
+CROSS JOIN LATERAL (
+    SELECT CASE
+        WHEN k0.key ~ 'ies$' THEN regexp_replace(k0.key, 'ies$', 'y')
+        WHEN k0.key ~ 'oes$' THEN regexp_replace(k0.key, 'es$', '')
+        WHEN k0.key ~ '(ves|ses|zes|xes|ches|shes)$' THEN k0.key
+        WHEN k0.key ~ 's$' AND k0.key !~ '(ss|us|is)$' THEN regexp_replace(k0.key, 's$', '')
+        ELSE k0.key
+    END AS key
+) k
+WHERE recipe_ingredients.id = k0.id;
+
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_match_name
+    ON recipe_ingredients USING gin(match_name gin_trgm_ops);
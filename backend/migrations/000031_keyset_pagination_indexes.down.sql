@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_shopping_lists_user_created;
+DROP INDEX IF EXISTS idx_pantry_items_user_category;
+DROP INDEX IF EXISTS idx_collection_recipes_order;
+CREATE INDEX IF NOT EXISTS idx_collection_recipes_order ON collection_recipes(collection_id, sort_order);
+DROP INDEX IF EXISTS idx_recipes_user_title;
+DROP INDEX IF EXISTS idx_recipes_user_created;
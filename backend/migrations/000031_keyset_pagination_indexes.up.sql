@@ -0,0 +1,18 @@
+-- Keyset pagination indexes for list endpoints, matching each list's
+-- default ORDER BY over non-deleted rows
+
+-- Recipe library, newest first (ListByUser sort=recent)
+CREATE INDEX IF NOT EXISTS idx_recipes_user_created ON recipes(user_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+-- Recipe library, A-Z (ListByUser sort=title)
+CREATE INDEX IF NOT EXISTS idx_recipes_user_title ON recipes(user_id, lower(title), id) WHERE deleted_at IS NULL;
+
+-- Collection recipes in collection order
+DROP INDEX IF EXISTS idx_collection_recipes_order;
+CREATE INDEX IF NOT EXISTS idx_collection_recipes_order ON collection_recipes(collection_id, sort_order, added_at, recipe_id);
+
+-- Pantry, grouped by category
+CREATE INDEX IF NOT EXISTS idx_pantry_items_user_category ON pantry_items(user_id, category, name, id) WHERE deleted_at IS NULL;
+
+-- Shopping lists, newest first
+CREATE INDEX IF NOT EXISTS idx_shopping_lists_user_created ON shopping_lists(user_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
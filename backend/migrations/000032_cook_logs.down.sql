@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS cook_logs_updated_at ON cook_logs;
+DROP TABLE IF EXISTS cook_logs;
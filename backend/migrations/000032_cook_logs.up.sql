@@ -0,0 +1,24 @@
+-- Cook log: each time a user cooked one of their recipes, with how it went
+-- Times cooked, last cooked and average rating are derived from it rather
+-- than stored on recipes, so logging a cook doesn't bump the recipe for sync
+CREATE TABLE IF NOT EXISTS cook_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    cooked_on DATE NOT NULL,
+    servings INTEGER CHECK (servings > 0),
+    rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    notes TEXT,
+    photos TEXT[] NOT NULL DEFAULT '{}',
+    modifications TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A recipe's cooks, for its history and stats
+CREATE INDEX IF NOT EXISTS idx_cook_logs_recipe ON cook_logs(recipe_id, cooked_on DESC);
+
+-- A user's cook history, most recent first
+CREATE INDEX IF NOT EXISTS idx_cook_logs_user ON cook_logs(user_id, cooked_on DESC, created_at DESC, id DESC);
+
+CREATE TRIGGER cook_logs_updated_at BEFORE UPDATE ON cook_logs FOR EACH ROW EXECUTE FUNCTION update_updated_at();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS recipe_annotations_updated_at ON recipe_annotations;
+DROP TABLE IF EXISTS recipe_annotations;
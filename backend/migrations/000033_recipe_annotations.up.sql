@@ -0,0 +1,24 @@
+-- Personal notes on a recipe's ingredients and steps, private to the user who wrote them
+-- Kept apart from the recipe so users can annotate recipes they don't own. Updates
+-- replace ingredient and step rows, so target_id has no foreign key; anchor and
+-- position (name or instruction, and order) re-attach notes whose target was replaced.
+CREATE TABLE IF NOT EXISTS recipe_annotations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    target VARCHAR(20) NOT NULL CHECK (target IN ('ingredient', 'step')),
+    target_id UUID NOT NULL,
+    anchor TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    note TEXT NOT NULL CHECK (LENGTH(TRIM(note)) > 0),
+    sync_version INTEGER DEFAULT 1,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+-- One live note per ingredient or step
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_annotations_target ON recipe_annotations(user_id, recipe_id, target, target_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_recipe_annotations_sync ON recipe_annotations(user_id, updated_at);
+
+CREATE TRIGGER recipe_annotations_updated_at BEFORE UPDATE ON recipe_annotations FOR EACH ROW EXECUTE FUNCTION update_updated_at();
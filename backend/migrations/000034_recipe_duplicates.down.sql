@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS recipe_duplicate_scans;
+DROP TABLE IF EXISTS recipe_duplicate_groups;
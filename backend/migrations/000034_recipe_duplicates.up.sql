@@ -0,0 +1,21 @@
+-- Near-duplicate recipes suggested by the background duplicate analysis.
+-- A user's open suggestions are replaced each time their library is
+-- analyzed; dismissed ones are kept so the same set of recipes isn't
+-- suggested again.
+CREATE TABLE IF NOT EXISTS recipe_duplicate_groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipe_ids UUID[] NOT NULL, -- sorted, so equal sets compare equal
+    score REAL NOT NULL,
+    dismissed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_duplicate_groups_recipes ON recipe_duplicate_groups(user_id, recipe_ids);
+
+-- When each library was last analyzed; libraries with recipes updated
+-- since are analyzed again
+CREATE TABLE IF NOT EXISTS recipe_duplicate_scans (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    scanned_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS food_portions;
+DROP TABLE IF EXISTS foods;
@@ -0,0 +1,43 @@
+-- Food composition data imported from USDA FoodData Central by
+-- cmd/import-usda. Recipe nutrition is computed from these rows; the AI
+-- estimate is only used for ingredients no food matches.
+CREATE TABLE IF NOT EXISTS foods (
+    fdc_id INTEGER PRIMARY KEY,
+    description TEXT NOT NULL,
+    data_type TEXT NOT NULL, -- foundation_food, sr_legacy_food, survey_fndds_food
+    match_name TEXT NOT NULL, -- ingredient.MatchKey of the food's common name
+
+    -- Per 100 g of the food as sold; grams unless noted
+    calories REAL NOT NULL DEFAULT 0, -- kcal
+    protein REAL NOT NULL DEFAULT 0,
+    carbs REAL NOT NULL DEFAULT 0,
+    fat REAL NOT NULL DEFAULT 0,
+    saturated_fat REAL NOT NULL DEFAULT 0,
+    fiber REAL NOT NULL DEFAULT 0,
+    sugar REAL NOT NULL DEFAULT 0,
+    sodium REAL NOT NULL DEFAULT 0, -- mg
+    cholesterol REAL NOT NULL DEFAULT 0, -- mg
+    calcium REAL NOT NULL DEFAULT 0, -- mg
+    iron REAL NOT NULL DEFAULT 0, -- mg
+    potassium REAL NOT NULL DEFAULT 0, -- mg
+    vitamin_c REAL NOT NULL DEFAULT 0, -- mg
+
+    imported_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Exact names are looked up first; fuzzy matches scan the table, which only
+-- holds a few thousand generic foods
+CREATE INDEX IF NOT EXISTS idx_foods_match_name ON foods(match_name);
+
+-- Household measures for a food ("1 large" egg = 50 g, "1 cup" rice = 185 g),
+-- used to weigh ingredients given in counts or by volume
+CREATE TABLE IF NOT EXISTS food_portions (
+    id SERIAL PRIMARY KEY,
+    fdc_id INTEGER NOT NULL REFERENCES foods(fdc_id) ON DELETE CASCADE,
+    amount REAL NOT NULL DEFAULT 1,
+    unit TEXT NOT NULL DEFAULT '', -- "cup", "tbsp", or empty for counted portions
+    modifier TEXT NOT NULL DEFAULT '', -- "large", "clove", "slice"
+    gram_weight REAL NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_food_portions_fdc_id ON food_portions(fdc_id);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS cook_sessions_updated_at ON cook_sessions;
+DROP TABLE IF EXISTS cook_sessions;
@@ -0,0 +1,24 @@
+-- Cook sessions: a recipe being cooked right now, at a chosen scale, with
+-- the step the cook is on and their running timers. Sessions live on the
+-- server so cooking can be picked up on another device. Finishing one logs
+-- a cook; abandoning one deletes it.
+CREATE TABLE IF NOT EXISTS cook_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    servings INTEGER CHECK (servings > 0),
+    current_step INTEGER NOT NULL DEFAULT 1,
+    -- Running timers, each {id, name, stepNumber, durationSeconds, startedAt, endsAt}
+    timers JSONB NOT NULL DEFAULT '[]',
+    cook_log_id UUID REFERENCES cook_logs(id) ON DELETE SET NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+-- One session in progress per recipe, which also serves listing a user's
+-- sessions in progress
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cook_sessions_active
+    ON cook_sessions(user_id, recipe_id) WHERE finished_at IS NULL;
+
+CREATE TRIGGER cook_sessions_updated_at BEFORE UPDATE ON cook_sessions FOR EACH ROW EXECUTE FUNCTION update_updated_at();